@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/bump"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+// bump command flags
+type bumpFlags struct {
+	apply        bool
+	author       string
+	outputFormat string
+	tags         []string
+	timeout      time.Duration
+	repoTimeout  time.Duration
+	jsonIndent   bool
+}
+
+var bmpFlags bumpFlags
+
+// newBumpCmd creates the 'bump' subcommand.
+func newBumpCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "bump <config-file> <package> <version>",
+		Short: "Open pull requests moving lagging repositories to a package version",
+		Long: strings.TrimSpace(`
+Find the configured repositories whose lock files pin a package below the
+given version and open a pull request (GitHub) or merge request (GitLab) in
+each one updating the lock files.
+
+Without --apply this is a dry run listing the repositories that would get a
+pull request. With --apply each repository is cloned into a temporary
+directory, the package is updated in the directory of every lock file
+locking it, and the change is pushed to the branch
+devdashboard/bump-<package>-<version>:
+
+  poetry  poetry update --lock <package>   (newest version the manifest allows)
+  uvlock  uv lock --upgrade-package <package>==<version>
+
+poetry or uv must be installed. A repository is skipped when a pull request
+from that branch is already open, and fails when the updater resolves a
+version below the target. The pull requests appear in the report's
+"Open update PRs" section of repositories with updatePRs enabled.
+
+Pushing uses the repository's configured token.
+
+Examples:
+  devdashboard bump repos.yaml django 4.2.11
+  devdashboard bump repos.yaml django 4.2.11 --tag team-payments --apply
+  devdashboard bump repos.yaml urllib3 2.2.2 --apply --format json
+`),
+		Args: cobra.ExactArgs(3),
+		RunE: runBump,
+	}
+
+	c.Flags().BoolVar(&bmpFlags.apply, "apply", false, "Push branches and open pull requests (default: dry run)")
+	c.Flags().StringVar(&bmpFlags.author, "author", bump.DefaultAuthor, "Commit author as \"Name <email>\"")
+	c.Flags().StringVarP(&bmpFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	c.Flags().StringSliceVar(&bmpFlags.tags, "tag", nil, "Only bump repositories carrying any of these tags (repeatable or comma-separated)")
+	c.Flags().DurationVar(&bmpFlags.timeout, "timeout", 30*time.Minute, "Timeout for analyzing and bumping all repositories")
+	c.Flags().DurationVar(&bmpFlags.repoTimeout, "repo-timeout", 0, "Timeout for analyzing each repository (0 = limited only by --timeout)")
+	c.Flags().BoolVar(&bmpFlags.jsonIndent, "json-indent", false, "Pretty-print JSON output")
+
+	return c
+}
+
+// runBump analyzes the configured repositories with dependency graphs and
+// bumps those locking the package below the version.
+func runBump(cmd *cobra.Command, args []string) error {
+	configFile, pkg, version := args[0], args[1], args[2]
+	format := strings.ToLower(bmpFlags.outputFormat)
+	if format != "console" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", bmpFlags.outputFormat)
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	repos, err := selectRepos(cfg, bmpFlags.tags, nil)
+	if err != nil {
+		return err
+	}
+	for i := range repos {
+		// Validate up front rather than after analyzing every repository
+		eco := dependencies.EcosystemForAnalyzer(repos[i].Config.Analyzer)
+		if _, err := versioning.ParseRange(eco, "<"+version); err != nil {
+			return exitcode.New(exitcode.ConfigError, err)
+		}
+	}
+
+	applyConfigTimeouts(cmd, cfg, &bmpFlags.timeout, &bmpFlags.repoTimeout)
+	ctx, cancel := context.WithTimeout(cmd.Context(), bmpFlags.timeout)
+	defer cancel()
+
+	generator := report.NewGenerator()
+	if cfg.Retry != nil {
+		generator.SetRetryPolicy(report.RetryPolicyFromConfig(cfg.Retry))
+	}
+	generator.SetRepositoryTimeout(bmpFlags.repoTimeout)
+	if cfg.Timeouts != nil {
+		generator.SetRequestTimeout(cfg.Timeouts.Request)
+	}
+	generator.SetBudget(cfg.Budget)
+	generator.SetMaxFileSize(cfg.MaxFileSize)
+	generator.SetIncludeGraph(true)
+	generator.SetHTTPTracer(httpTracer)
+	generator.SetFixtures(httpFixtures)
+	rpt, err := generator.Generate(ctx, repos)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil {
+			slog.Warn("Repository not searched", "repository", rr.Key(), "error", rr.Error)
+		}
+	}
+
+	targets, err := bump.Targets(rpt, repos, pkg, version)
+	if err != nil {
+		return exitcode.New(exitcode.ConfigError, err)
+	}
+	b := &bump.Bumper{Package: pkg, Version: version, DryRun: !bmpFlags.apply, Author: bmpFlags.author, HTTPTracer: httpTracer}
+	results := b.Run(ctx, targets)
+
+	if format == "json" {
+		err = renderBumpJSON(pkg, version, results, os.Stdout)
+	} else {
+		err = renderBump(pkg, version, results, os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Status == bump.StatusFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return exitcode.New(exitcode.Partial, fmt.Errorf("%d of %d bumps failed", failed, len(results)))
+	}
+	return nil
+}
+
+// renderBump writes the results as an aligned table
+func renderBump(pkg, version string, results []bump.Result, w ioWriter) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintf(w, "No repositories lock %s below %s\n", pkg, version)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPOSITORY\tFROM\tTO\tSTATUS\tPULL REQUEST")
+	for _, r := range results {
+		detail := r.Error
+		if r.PullRequest != nil {
+			detail = r.PullRequest.URL
+		}
+		if detail == "" {
+			detail = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Repository, r.From, r.To, r.Status, detail)
+	}
+	return tw.Flush()
+}
+
+// bumpOutput is the JSON shape of bump
+type bumpOutput struct {
+	Package string        `json:"package"`
+	Version string        `json:"version"`
+	DryRun  bool          `json:"dryRun"`
+	Results []bump.Result `json:"results"`
+}
+
+// renderBumpJSON writes the results as JSON
+func renderBumpJSON(pkg, version string, results []bump.Result, w ioWriter) error {
+	payload := bumpOutput{Package: pkg, Version: version, DryRun: !bmpFlags.apply, Results: results}
+	if payload.Results == nil {
+		payload.Results = []bump.Result{}
+	}
+
+	var data []byte
+	var err error
+	if bmpFlags.jsonIndent {
+		data, err = json.MarshalIndent(payload, "", "  ")
+	} else {
+		data, err = json.Marshal(payload)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, _ = w.Write(data)
+	_, _ = w.Write([]byte("\n"))
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/bump"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+func TestRenderBump(t *testing.T) {
+	results := []bump.Result{
+		{Repository: "github:o/api@main", From: "3.2.0", To: "4.2.11", Status: bump.StatusOpened,
+			PullRequest: &repository.UpdatePullRequest{Number: 9, URL: "https://github.com/o/api/pull/9"}},
+		{Repository: "github:o/web@main", From: "4.1.0", To: "4.2.11", Status: bump.StatusPlanned},
+		{Repository: "github:o/old@main", From: "2.2.0", To: "4.2.11", Status: bump.StatusFailed, Error: "no lock file updater"},
+	}
+	var buf bytes.Buffer
+	if err := renderBump("django", "4.2.11", results, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasSuffix(lines[1], "pull/9") || !strings.HasSuffix(lines[2], "-") || !strings.HasSuffix(lines[3], "no lock file updater") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	_ = renderBump("django", "4.2.11", nil, &buf)
+	expectContains(t, buf.String(), "No repositories lock django below 4.2.11", "empty result")
+
+	buf.Reset()
+	if err := renderBumpJSON("django", "4.2.11", results, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var out bumpOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(out.Results) != 3 || out.Results[0].PullRequest.Number != 9 || out.Results[2].Status != bump.StatusFailed {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
+
+// TestCLIBumpInvalidVersion ensures a bad target version fails before any analysis.
+func TestCLIBumpInvalidVersion(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+providers:
+  github:
+    default:
+      analyzer: poetry
+    repositories:
+      - owner: o
+        repository: r
+        packages: ["django"]
+`)
+	root := newRootCmd()
+	root.SetArgs([]string{"bump", cfgPath, "django", "not-a-version"})
+
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/spf13/cobra"
+)
+
+// cache command flags
+type cacheFlags struct {
+	dir          string
+	outputFormat string
+	jsonIndent   bool
+}
+
+var cchFlags cacheFlags
+
+// newCacheCmd creates the 'cache' subcommand.
+func newCacheCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect or clear the lock file content cache",
+		Long: strings.TrimSpace(`
+dependency-report keeps the listings and lock files it reads at each analyzed
+commit in a content cache (see --cache-dir), so later runs against unchanged
+commits do not fetch them again. Entries are keyed by provider, repository,
+commit SHA, path and blob SHA, and checked against a digest when read.
+
+Examples:
+  devdashboard cache stats
+  devdashboard cache stats --format json
+  devdashboard cache clear --cache-dir ./cache
+`),
+	}
+	c.PersistentFlags().StringVar(&cchFlags.dir, "cache-dir", "", "Content cache directory passed to dependency-report --cache-dir (default: in the user cache directory)")
+
+	stats := &cobra.Command{
+		Use:   "stats",
+		Short: "Show the content cache's location, entries and size",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheStats,
+	}
+	stats.Flags().StringVarP(&cchFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	stats.Flags().BoolVar(&cchFlags.jsonIndent, "json-indent", false, "Pretty-print JSON output")
+
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every content cache entry",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheClear,
+	}
+
+	c.AddCommand(stats, clearCmd)
+	return c
+}
+
+// openCacheCmdCache opens the cache the cache subcommands work on
+func openCacheCmdCache() (*repository.ContentCache, error) {
+	cache, err := openContentCache(cchFlags.dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if cache == nil {
+		return nil, exitcode.Errorf(exitcode.ConfigError, "no content cache directory: pass --cache-dir")
+	}
+	return cache, nil
+}
+
+// runCacheStats prints the cache's statistics.
+func runCacheStats(cmd *cobra.Command, _ []string) error {
+	format := strings.ToLower(cchFlags.outputFormat)
+	if format != "console" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", cchFlags.outputFormat)
+	}
+	cache, err := openCacheCmdCache()
+	if err != nil {
+		return err
+	}
+	stats, err := cache.Stats()
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		if cchFlags.jsonIndent {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(stats)
+	}
+	fmt.Fprintf(w, "Directory: %s\n", stats.Dir)
+	fmt.Fprintf(w, "Entries:   %d\n", stats.Entries)
+	fmt.Fprintf(w, "Size:      %.1f MiB\n", float64(stats.Size)/(1<<20))
+	return nil
+}
+
+// runCacheClear empties the cache.
+func runCacheClear(cmd *cobra.Command, _ []string) error {
+	cache, err := openCacheCmdCache()
+	if err != nil {
+		return err
+	}
+	stats, err := cache.Stats()
+	if err != nil {
+		return err
+	}
+	if err := cache.Clear(); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %d entries (%.1f MiB) from %s\n", stats.Entries, float64(stats.Size)/(1<<20), stats.Dir)
+	return nil
+}
+
+// openContentCache opens the content cache in dir, or the default one in
+// the user cache directory when dir is empty. maxSizeMiB bounds its size (0
+// = unlimited). It returns nil when dir is "none" or there is no user cache
+// directory.
+func openContentCache(dir string, maxSizeMiB int64) (*repository.ContentCache, error) {
+	switch dir {
+	case "none":
+		return nil, nil
+	case "":
+		var err error
+		if dir, err = repository.DefaultContentCacheDir(); err != nil {
+			slog.Debug("No user cache directory; content cache disabled", "error", err)
+			return nil, nil
+		}
+	}
+	maxSize := int64(-1)
+	if maxSizeMiB > 0 {
+		maxSize = maxSizeMiB << 20
+	}
+	cache, err := repository.NewContentCache(dir, maxSize)
+	if err != nil {
+		return nil, exitcode.New(exitcode.ConfigError, err)
+	}
+	return cache, nil
+}
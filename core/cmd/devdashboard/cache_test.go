@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+func TestCLICache(t *testing.T) {
+	defer func() { cchFlags = cacheFlags{} }()
+	dir := t.TempDir()
+	store := memory.NewStore()
+	store.AddRepository("org", "api", memory.Files{"poetry.lock": "lock"})
+	client := memory.NewClient(store)
+	ctx := context.Background()
+	sha, err := client.ResolveCommit(ctx, "org", "api", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := repository.NewContentCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Client("memory", client).GetFileContent(ctx, "org", "api", sha, "poetry.lock"); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newRootCmd()
+	root.SetArgs([]string{"cache", "stats", "--cache-dir", dir, "--format", "json"})
+	out, err := executeCommand(root)
+	if err != nil {
+		t.Fatalf("cache stats failed: %v", err)
+	}
+	var stats repository.ContentCacheStats
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if stats.Dir != dir || stats.Entries != 1 || stats.Size == 0 {
+		t.Errorf("stats = %+v, want one entry in %s", stats, dir)
+	}
+
+	root = newRootCmd()
+	root.SetArgs([]string{"cache", "clear", "--cache-dir", dir})
+	out, err = executeCommand(root)
+	if err != nil {
+		t.Fatalf("cache clear failed: %v", err)
+	}
+	expectContains(t, out, "Removed 1 entries", "clear output")
+
+	root = newRootCmd()
+	root.SetArgs([]string{"cache", "stats", "--cache-dir", dir})
+	out, err = executeCommand(root)
+	if err != nil {
+		t.Fatalf("cache stats failed: %v", err)
+	}
+	expectContains(t, out, "Entries:   0", "stats after clear")
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+// check command flags
+type checkFlags struct {
+	configFile    string
+	maxErrors     int
+	maxDrift      int
+	maxViolations int
+	failOnError   bool
+	outputFormat  string
+	tags          []string
+	packageGroups []string
+	timeout       time.Duration
+	repoTimeout   time.Duration
+}
+
+var chkFlags checkFlags
+
+// newCheckCmd creates the 'check' subcommand.
+func newCheckCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "check [config-file]",
+		Short: "Run the report headlessly and fail when thresholds are exceeded",
+		Long: strings.TrimSpace(`
+Run the dependency report without rendering it and compare the results with
+thresholds, for use as a CI gate. A concise key=value summary (or JSON with
+--format json) is printed to stdout.
+
+Thresholds (a negative value disables one):
+  --max-errors      repositories that failed to analyze (default unlimited;
+                    --fail-on-error sets it to 0)
+  --max-drift       tracked packages at more than one version (default unlimited)
+  --max-violations  error-severity policy violations (default 0)
+
+Drift and violations suppressed by the config's ignores (or ignoreFile) are
+counted as "ignored" and never exceed a threshold.
+
+Exit status is 0 when every threshold holds. Exceeding --max-errors exits
+with the repository failure class (2 when some repositories succeeded, see
+exit-codes); exceeding drift or violation thresholds exits 3.
+
+Examples:
+  devdashboard check --config repos.yaml --max-drift 1 --fail-on-error
+  devdashboard check repos.yaml --tag prod --format json
+`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: runCheck,
+	}
+
+	c.Flags().StringVarP(&chkFlags.configFile, "config", "c", "", "Configuration file (alternative to the positional argument)")
+	c.Flags().IntVar(&chkFlags.maxErrors, "max-errors", -1, "Fail when more repositories than this fail to analyze (negative = unlimited)")
+	c.Flags().IntVar(&chkFlags.maxDrift, "max-drift", -1, "Fail when more tracked packages than this have version drift (negative = unlimited)")
+	c.Flags().IntVar(&chkFlags.maxViolations, "max-violations", 0, "Fail when there are more error-severity policy violations than this (negative = unlimited)")
+	c.Flags().BoolVar(&chkFlags.failOnError, "fail-on-error", false, "Fail when any repository fails to analyze (same as --max-errors 0)")
+	c.Flags().StringVarP(&chkFlags.outputFormat, "format", "f", "text", "Summary format: text|json")
+	c.Flags().StringSliceVar(&chkFlags.tags, "tag", nil, "Only check repositories carrying any of these tags (repeatable or comma-separated)")
+	c.Flags().StringSliceVar(&chkFlags.packageGroups, "packages-group", nil, "Only check packages in these named packageGroups (repeatable or comma-separated)")
+	c.Flags().DurationVar(&chkFlags.timeout, "timeout", 5*time.Minute, "Timeout for generating the report")
+	c.Flags().DurationVar(&chkFlags.repoTimeout, "repo-timeout", 0, "Timeout for analyzing each repository (0 = limited only by --timeout)")
+
+	return c
+}
+
+// checkThresholds are the limits a check compares against; negative disables
+type checkThresholds struct {
+	MaxErrors     int `json:"maxErrors"`
+	MaxDrift      int `json:"maxDrift"`
+	MaxViolations int `json:"maxViolations"`
+}
+
+// checkResult is the summary printed by check
+type checkResult struct {
+	Passed        bool            `json:"passed"`
+	Repositories  int             `json:"repositories"`
+	Errors        int             `json:"errors"`
+	Drift         int             `json:"drift"`
+	DriftPackages []string        `json:"driftPackages"`
+	Violations    int             `json:"violations"` // Error severity
+	Warnings      int             `json:"warnings"`   // Warning-severity violations
+	Ignored       int             `json:"ignored"`    // Violations and versions suppressed by ignore rules
+	Thresholds    checkThresholds `json:"thresholds"`
+	Exceeded      []string        `json:"exceeded"` // "errors", "drift", "violations"
+}
+
+// evaluateCheck counts the report's failures, drift and violations and
+// compares them with th
+func evaluateCheck(rpt *report.Report, th checkThresholds) checkResult {
+	res := checkResult{Repositories: len(rpt.Repositories), Thresholds: th, DriftPackages: []string{}, Exceeded: []string{}}
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil {
+			res.Errors++
+		}
+		for pkg := range rr.Ignored {
+			if rr.Suppressed(pkg) {
+				res.Ignored++
+			}
+		}
+	}
+	m := rpt.Matrix()
+	for _, pkg := range rpt.Packages {
+		if m.HasDrift(pkg) {
+			res.DriftPackages = append(res.DriftPackages, pkg)
+		}
+	}
+	res.Drift = len(res.DriftPackages)
+	for _, v := range rpt.Violations() {
+		if v.Suppressed() {
+			res.Ignored++
+			continue
+		}
+		if v.Severity == report.SeverityError {
+			res.Violations++
+		} else {
+			res.Warnings++
+		}
+	}
+
+	exceeds := func(n, limit int) bool { return limit >= 0 && n > limit }
+	if exceeds(res.Errors, th.MaxErrors) {
+		res.Exceeded = append(res.Exceeded, "errors")
+	}
+	if exceeds(res.Drift, th.MaxDrift) {
+		res.Exceeded = append(res.Exceeded, "drift")
+	}
+	if exceeds(res.Violations, th.MaxViolations) {
+		res.Exceeded = append(res.Exceeded, "violations")
+	}
+	res.Passed = len(res.Exceeded) == 0
+	return res
+}
+
+// runCheck generates the report and gates on the thresholds.
+func runCheck(cmd *cobra.Command, args []string) error {
+	configFile := chkFlags.configFile
+	switch {
+	case len(args) == 1 && configFile != "" && args[0] != configFile:
+		return exitcode.New(exitcode.ConfigError, errors.New("config file given both as argument and --config"))
+	case len(args) == 1:
+		configFile = args[0]
+	case configFile == "":
+		return exitcode.New(exitcode.ConfigError, errors.New("no config file: pass it as an argument or with --config"))
+	}
+	format := strings.ToLower(chkFlags.outputFormat)
+	if format != "text" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", chkFlags.outputFormat)
+	}
+	th := checkThresholds{MaxErrors: chkFlags.maxErrors, MaxDrift: chkFlags.maxDrift, MaxViolations: chkFlags.maxViolations}
+	if chkFlags.failOnError {
+		th.MaxErrors = 0
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	repos, err := selectRepos(cfg, chkFlags.tags, chkFlags.packageGroups)
+	if err != nil {
+		return err
+	}
+	applyConfigTimeouts(cmd, cfg, &chkFlags.timeout, &chkFlags.repoTimeout)
+	generator, err := newConfiguredGenerator(cfg, chkFlags.repoTimeout)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), chkFlags.timeout)
+	defer cancel()
+	rpt, err := generator.Generate(ctx, repos)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	res := evaluateCheck(rpt, th)
+	if format == "json" {
+		data, err := json.Marshal(res)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	} else {
+		writeCheckSummary(cmd.OutOrStdout(), res)
+	}
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil {
+			slog.Warn("Repository failed", "repository", rr.Key(), "error", rr.Error)
+		}
+	}
+	for _, v := range rpt.Violations() {
+		slog.Warn("Policy violation", "repository", v.Repository, "policy", v.Policy, "severity", v.Severity, "message", v.Message)
+	}
+
+	switch {
+	case res.Passed:
+		return nil
+	case res.Exceeded[0] == "errors":
+		return fmt.Errorf("check failed (%s): %w", strings.Join(res.Exceeded, ", "), rpt.Err())
+	default:
+		return exitcode.Errorf(exitcode.PolicyViolation, "check failed (%s)", strings.Join(res.Exceeded, ", "))
+	}
+}
+
+// writeCheckSummary prints res as key=value lines
+func writeCheckSummary(w ioWriter, res checkResult) {
+	result := "pass"
+	if !res.Passed {
+		result = "fail"
+	}
+	_, _ = fmt.Fprintf(w, "result=%s\n", result)
+	_, _ = fmt.Fprintf(w, "repositories=%d\n", res.Repositories)
+	_, _ = fmt.Fprintf(w, "errors=%d\n", res.Errors)
+	_, _ = fmt.Fprintf(w, "drift=%d\n", res.Drift)
+	_, _ = fmt.Fprintf(w, "drift_packages=%s\n", strings.Join(res.DriftPackages, ","))
+	_, _ = fmt.Fprintf(w, "violations=%d\n", res.Violations)
+	_, _ = fmt.Fprintf(w, "warnings=%d\n", res.Warnings)
+	_, _ = fmt.Fprintf(w, "ignored=%d\n", res.Ignored)
+	_, _ = fmt.Fprintf(w, "exceeded=%s\n", strings.Join(res.Exceeded, ","))
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func checkTestReport() *report.Report {
+	return &report.Report{
+		Packages: []string{"django", "requests"},
+		Repositories: []report.RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Dependencies: map[string]string{"django": "4.2.0", "requests": "2.31.0"},
+				Violations: []report.Violation{{Policy: "p", Severity: report.SeverityWarning}}},
+			{Provider: "github", Owner: "o", Repository: "web", Dependencies: map[string]string{"django": "3.2.0", "requests": "2.31.0"},
+				Violations: []report.Violation{{Policy: "p", Severity: report.SeverityError}}},
+			{Provider: "github", Owner: "o", Repository: "down", Error: report.NewErrorDetail(errors.New("boom"))},
+		},
+	}
+}
+
+func TestEvaluateCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		th   checkThresholds
+		want []string
+	}{
+		{"unlimited", checkThresholds{MaxErrors: -1, MaxDrift: -1, MaxViolations: -1}, []string{}},
+		{"all exceeded", checkThresholds{MaxErrors: 0, MaxDrift: 0, MaxViolations: 0}, []string{"errors", "drift", "violations"}},
+		{"at the limits", checkThresholds{MaxErrors: 1, MaxDrift: 1, MaxViolations: 1}, []string{}},
+	}
+	for _, tt := range tests {
+		res := evaluateCheck(checkTestReport(), tt.th)
+		if !slices.Equal(res.Exceeded, tt.want) || res.Passed != (len(tt.want) == 0) {
+			t.Errorf("%s: exceeded = %v (passed %v), want %v", tt.name, res.Exceeded, res.Passed, tt.want)
+		}
+		if res.Errors != 1 || res.Drift != 1 || res.Violations != 1 || res.Warnings != 1 || !slices.Equal(res.DriftPackages, []string{"django"}) {
+			t.Errorf("%s: unexpected counts %+v", tt.name, res)
+		}
+	}
+}
+
+func TestEvaluateCheckIgnored(t *testing.T) {
+	rpt := checkTestReport()
+	web := &rpt.Repositories[1]
+	web.Ignored = map[string]report.Ignore{"django": {Reason: "pinned until Q3", Suppressed: true}}
+	web.Violations[0].Ignored = &report.Ignore{Reason: "pinned until Q3", Suppressed: true}
+	rpt.Repositories[0].Violations[0].Ignored = &report.Ignore{Reason: "known"} // Annotation only
+
+	res := evaluateCheck(rpt, checkThresholds{MaxErrors: -1, MaxDrift: 0, MaxViolations: 0})
+	if !res.Passed || res.Drift != 0 || res.Violations != 0 || res.Warnings != 1 || res.Ignored != 2 {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestWriteCheckSummary(t *testing.T) {
+	var buf bytes.Buffer
+	writeCheckSummary(&buf, evaluateCheck(checkTestReport(), checkThresholds{MaxErrors: -1, MaxDrift: 0, MaxViolations: 0}))
+	want := `result=fail
+repositories=3
+errors=1
+drift=1
+drift_packages=django
+violations=1
+warnings=1
+ignored=0
+exceeded=drift,violations
+`
+	if buf.String() != want {
+		t.Errorf("summary:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// TestCLICheckRequiresConfig ensures check without a config file is a config error.
+func TestCLICheckRequiresConfig(t *testing.T) {
+	root := newRootCmd()
+	root.SetArgs([]string{"check", "--max-drift", "1"})
+
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+}
+
+func TestCLICheckWritesToCommandOutput(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+providers:
+  github:
+    repositories:
+      - owner: o
+        repository: r
+        analyzer: invalidAnalyzerX
+        packages: ["django"]
+`)
+	root := newRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{"check", cfgPath, "--max-drift", "1", "--format", "json"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	var res checkResult
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		t.Fatalf("invalid JSON on the command output: %v\n%s", err, out.String())
+	}
+	if !res.Passed || res.Errors != 1 {
+		t.Errorf("result = %+v, want a pass with 1 failed repository", res)
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/spf13/cobra"
+)
+
+// doctor command flags
+type doctorFlags struct {
+	snapshot     string
+	offline      bool
+	outputFormat string
+	timeout      time.Duration
+}
+
+var docFlags doctorFlags
+
+// Check statuses reported by doctor
+const (
+	checkOK      = "ok"
+	checkWarn    = "warn" // Works, but degraded
+	checkFail    = "fail"
+	checkSkipped = "skipped"
+)
+
+// doctorCheck is the result of one doctor check
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Remedy  string `json:"remedy,omitempty"` // What to do about a warn or fail
+}
+
+// doctorOutput is the JSON shape of doctor
+type doctorOutput struct {
+	Healthy bool          `json:"healthy"` // No check failed
+	Checks  []doctorCheck `json:"checks"`
+}
+
+// newDoctorCmd creates the 'doctor' subcommand.
+func newDoctorCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "doctor <config-file>",
+		Short: "Diagnose the configuration, provider access and local state",
+		Long: strings.TrimSpace(`
+Run self-diagnostics and print what to do about each problem found:
+
+  config    the file loads and validates
+  reach     each provider endpoint (provider, baseURL and token) responds
+  token     its token is accepted, or repositories are readable without one
+  state     the dependency-report snapshot for the config can be written
+  cache     the devdashboard cache directory is writable and its files parse
+
+Provider checks make one API request per endpoint; --offline skips them.
+
+Exit status is 0 when no check fails (warnings allowed), 4 (config error)
+when the configuration is invalid, 5 (provider error) when a provider check
+fails, and 1 otherwise.
+
+Examples:
+  devdashboard doctor repos.yaml
+  devdashboard doctor repos.yaml --offline --format json
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: runDoctor,
+	}
+
+	c.Flags().StringVar(&docFlags.snapshot, "snapshot", "", "Snapshot file passed to dependency-report --snapshot (default: the config's in the cache directory)")
+	c.Flags().BoolVar(&docFlags.offline, "offline", false, "Skip the provider checks")
+	c.Flags().StringVarP(&docFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	c.Flags().DurationVar(&docFlags.timeout, "timeout", 30*time.Second, "Timeout for all provider checks")
+
+	return c
+}
+
+// runDoctor runs every check, prints them and fails when any did.
+func runDoctor(cmd *cobra.Command, args []string) error {
+	configFile := args[0]
+	format := strings.ToLower(docFlags.outputFormat)
+	if format != "console" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", docFlags.outputFormat)
+	}
+
+	cfg, check := checkConfig(configFile)
+	checks := []doctorCheck{check}
+	code := exitcode.OK
+	if check.Status == checkFail {
+		code = exitcode.ConfigError
+	}
+	switch {
+	case cfg == nil:
+		checks = append(checks, doctorCheck{Name: "reach", Status: checkSkipped, Message: "configuration invalid"})
+	case docFlags.offline:
+		checks = append(checks, doctorCheck{Name: "reach", Status: checkSkipped, Message: "--offline"})
+	default:
+		var requestTimeout time.Duration
+		if cfg.Timeouts != nil {
+			requestTimeout = cfg.Timeouts.Request
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), docFlags.timeout)
+		for _, ep := range providerEndpoints(cfg.GetAllRepos()) {
+			for _, c := range checkEndpoint(ctx, ep, requestTimeout) {
+				if c.Status == checkFail && code == exitcode.OK {
+					code = exitcode.ProviderError
+				}
+				checks = append(checks, c)
+			}
+		}
+		cancel()
+	}
+
+	cacheDir, cacheErr := os.UserCacheDir()
+	if cacheErr == nil {
+		cacheDir = filepath.Join(cacheDir, "devdashboard")
+	}
+	checks = append(checks, checkState(resolveSnapshotPath(docFlags.snapshot, configFile), docFlags.snapshot == "none"))
+	checks = append(checks, checkCache(cacheDir, cacheErr))
+
+	out := doctorOutput{Healthy: true, Checks: checks}
+	for _, c := range checks {
+		if c.Status == checkFail {
+			out.Healthy = false
+			if code == exitcode.OK {
+				code = exitcode.Failure
+			}
+		}
+	}
+	if format == "json" {
+		if err := writeJSON(os.Stdout, out); err != nil {
+			return err
+		}
+	} else if err := renderDoctor(out, os.Stdout); err != nil {
+		return err
+	}
+	if !out.Healthy {
+		return exitcode.New(code, errors.New("doctor found problems"))
+	}
+	return nil
+}
+
+// checkConfig loads and validates the configuration file
+func checkConfig(path string) (*config.Config, doctorCheck) {
+	check := doctorCheck{Name: "config", Status: checkOK}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		check.Status, check.Message = checkFail, err.Error()
+		check.Remedy = "Fix the error in " + path + "; docs/CLI_GUIDE.md describes every setting"
+		return nil, check
+	}
+	n := len(cfg.GetAllRepos())
+	check.Message = fmt.Sprintf("%s: %d repositories", path, n)
+	if n == 0 {
+		check.Status = checkWarn
+		check.Remedy = "Add repositories under providers.<provider>.repositories"
+	}
+	return cfg, check
+}
+
+// providerEndpoint is a set of repositories reached through the same
+// provider API with the same credentials and network settings
+type providerEndpoint struct {
+	Provider string
+	BaseURL  string
+	Repos    []config.RepoWithProvider
+}
+
+// name identifies the endpoint in check names, without its token
+func (e providerEndpoint) name() string {
+	host := e.BaseURL
+	if host == "" {
+		host = "default"
+	}
+	n := e.Provider + " " + host
+	if len(e.Repos) > 0 && e.Repos[0].Config.Token != "" {
+		n += " token " + tokenHint(e.Repos[0].Config.Token)
+	}
+	return n
+}
+
+// tokenHint identifies a token by its last characters
+func tokenHint(token string) string {
+	if len(token) <= 4 {
+		return "…"
+	}
+	return "…" + token[len(token)-4:]
+}
+
+// providerEndpoints groups repos by provider, baseURL, token and network
+// settings, in configuration order
+func providerEndpoints(repos []config.RepoWithProvider) []providerEndpoint {
+	type key struct {
+		provider, baseURL, token, proxy, caFile string
+		insecure                                bool
+	}
+	index := make(map[key]int)
+	var out []providerEndpoint
+	for _, r := range repos {
+		rc := r.Config
+		k := key{r.Provider, rc.BaseURL, rc.Token, rc.Proxy, rc.CAFile, rc.InsecureSkipVerify}
+		i, ok := index[k]
+		if !ok {
+			i = len(out)
+			index[k] = i
+			out = append(out, providerEndpoint{Provider: r.Provider, BaseURL: rc.BaseURL})
+		}
+		out[i].Repos = append(out[i].Repos, r)
+	}
+	return out
+}
+
+// checkEndpoint checks that ep's provider responds and accepts its token.
+// With a token, the token's account is looked up; without one, or when the
+// provider cannot look it up, the first repository is read instead.
+func checkEndpoint(ctx context.Context, ep providerEndpoint, requestTimeout time.Duration) []doctorCheck {
+	name := ep.name()
+	reach := doctorCheck{Name: "reach " + name, Status: checkOK}
+	token := doctorCheck{Name: "token " + name, Status: checkOK}
+	repo := ep.Repos[0]
+	rc := repo.Config
+	networkRemedy := "Check connectivity to the provider and the baseURL, proxy and caFile settings (HTTPS_PROXY is used when proxy is unset)"
+	tokenRemedy := fmt.Sprintf("Create a token with read access to the repositories and set it as providers.%s.default.token", repo.Provider)
+
+	client, err := newRepoClient(repo, requestTimeout)
+	if err != nil {
+		reach.Status, reach.Message = checkFail, err.Error()
+		reach.Remedy = fmt.Sprintf("Use a supported provider (%s) with valid network settings", strings.Join(repository.SupportedProviders(), ", "))
+		token.Status, token.Message = checkSkipped, "no client"
+		return []doctorCheck{reach, token}
+	}
+
+	discoverer, canDiscover := client.(repository.RepositoryDiscoverer)
+	if rc.Token != "" && canDiscover {
+		user, err := discoverer.CurrentUser(ctx)
+		if err == nil {
+			reach.Message = fmt.Sprintf("%d repositories", len(ep.Repos))
+			token.Message = "authenticated as " + user
+			return []doctorCheck{reach, token}
+		}
+		if status := repository.StatusCode(err); status == http.StatusUnauthorized {
+			reach.Message = "responded"
+			token.Status, token.Message, token.Remedy = checkFail, "token rejected (HTTP 401)", tokenRemedy
+			return []doctorCheck{reach, token}
+		}
+		// Other failures may be specific to the account lookup (e.g. token
+		// scopes); reading a repository tells more
+	}
+
+	_, err = client.GetRepositoryInfo(ctx, rc.Owner, rc.Repository)
+	status := repository.StatusCode(err)
+	switch {
+	case err == nil:
+		reach.Message = fmt.Sprintf("%d repositories", len(ep.Repos))
+		if rc.Token == "" {
+			token.Status, token.Message = checkWarn, "no token: public repositories only, with low rate limits"
+			token.Remedy = tokenRemedy
+		} else {
+			token.Message = fmt.Sprintf("reads %s/%s", rc.Owner, rc.Repository)
+		}
+	case repository.IsRateLimitError(err):
+		reach.Status, reach.Message = checkWarn, "rate limited: "+err.Error()
+		reach.Remedy = "Wait for the rate limit to reset, or configure a token for a higher limit"
+		token.Status, token.Message = checkSkipped, "rate limited"
+	case status == 0:
+		reach.Status, reach.Message, reach.Remedy = checkFail, err.Error(), networkRemedy
+		token.Status, token.Message = checkSkipped, "provider unreachable"
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound:
+		// Providers answer 404 for private repositories the caller cannot see
+		reach.Message = "responded"
+		token.Status = checkFail
+		token.Message = fmt.Sprintf("cannot read %s/%s (HTTP %d)", rc.Owner, rc.Repository, status)
+		token.Remedy = tokenRemedy + ", and check that " + rc.Owner + "/" + rc.Repository + " exists"
+	default:
+		reach.Status, reach.Message, reach.Remedy = checkWarn, err.Error(), "Retry later; the provider reported an error"
+		token.Status, token.Message = checkSkipped, "provider error"
+	}
+	return []doctorCheck{reach, token}
+}
+
+// checkState checks that the snapshot dependency-report saves at path can
+// be written, and that an existing one can be read
+func checkState(path string, disabled bool) doctorCheck {
+	check := doctorCheck{Name: "state", Status: checkOK}
+	if path == "" {
+		check.Status, check.Message = checkSkipped, "incremental runs disabled (no snapshot file)"
+		if !disabled {
+			check.Status = checkWarn
+			check.Remedy = "Set HOME or XDG_CACHE_HOME, or pass --snapshot to dependency-report"
+		}
+		return check
+	}
+	if err := probeWritable(filepath.Dir(path)); err != nil {
+		check.Status, check.Message = checkFail, err.Error()
+		check.Remedy = fmt.Sprintf("Make %s writable, or pass a writable --snapshot to dependency-report", filepath.Dir(path))
+		return check
+	}
+	snap, err := report.LoadSnapshot(path)
+	switch {
+	case err != nil:
+		check.Status, check.Message = checkWarn, err.Error()
+		check.Remedy = fmt.Sprintf("Delete %s; the next dependency-report run analyzes every repository and recreates it", path)
+	case snap != nil:
+		check.Message = fmt.Sprintf("%s: last run %s", path, snap.GeneratedAt.Local().Format("2006-01-02 15:04"))
+	default:
+		check.Message = path + ": no snapshot yet"
+	}
+	return check
+}
+
+// checkCache checks that the cache directory is writable and that the
+// snapshots in it parse; cacheErr is the error resolving it
+func checkCache(dir string, cacheErr error) doctorCheck {
+	check := doctorCheck{Name: "cache", Status: checkOK}
+	if cacheErr != nil {
+		check.Status, check.Message = checkWarn, cacheErr.Error()
+		check.Remedy = "Set HOME or XDG_CACHE_HOME so snapshots can be cached"
+		return check
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		check.Message = dir + ": not created yet"
+		return check
+	}
+	if err := probeWritable(dir); err != nil {
+		check.Status, check.Message = checkFail, err.Error()
+		check.Remedy = fmt.Sprintf("Make %s writable by this user", dir)
+		return check
+	}
+
+	var files int
+	var size int64
+	var corrupt []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			corrupt = append(corrupt, path)
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		if filepath.Ext(path) == ".json" {
+			if _, err := report.LoadSnapshot(path); err != nil {
+				corrupt = append(corrupt, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		check.Status, check.Message = checkFail, err.Error()
+		check.Remedy = fmt.Sprintf("Make %s readable by this user", dir)
+		return check
+	}
+	check.Message = fmt.Sprintf("%s: %d files, %.1f MiB", dir, files, float64(size)/(1<<20))
+	if len(corrupt) > 0 {
+		check.Status = checkWarn
+		check.Message += fmt.Sprintf(", %d unreadable", len(corrupt))
+		check.Remedy = "Delete " + strings.Join(corrupt, ", ") + "; they are recreated by the next run"
+	}
+	return check
+}
+
+// probeWritable creates dir if needed and writes and removes a file in it
+func probeWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create %s: %w", dir, err)
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return fmt.Errorf("%s not writable: %w", dir, err)
+	}
+	name := f.Name()
+	_ = f.Close()
+	return os.Remove(name)
+}
+
+// renderDoctor writes the checks as an aligned table, then the remedies
+func renderDoctor(out doctorOutput, w ioWriter) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAILS")
+	for _, c := range out.Checks {
+		details := c.Message
+		if details == "" {
+			details = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Name, c.Status, details)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var remedies []string
+	for _, c := range out.Checks {
+		if c.Remedy != "" {
+			remedies = append(remedies, fmt.Sprintf("  %s: %s", c.Name, c.Remedy))
+		}
+	}
+	if len(remedies) > 0 {
+		_, _ = fmt.Fprintf(w, "\nTo fix:\n%s\n", strings.Join(remedies, "\n"))
+	}
+	result := "healthy"
+	if !out.Healthy {
+		result = "unhealthy"
+	}
+	_, err := fmt.Fprintf(w, "\nDevDashboard %s\n", result)
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+)
+
+// TestCLIDoctor checks provider endpoints against a fake GitHub API: a
+// rejected token fails with a remedy, a missing token only warns.
+func TestCLIDoctor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch auth := r.Header.Get("Authorization"); {
+		case r.URL.Path == "/api/v3/user" && auth == "Bearer good-token-1234":
+			_, _ = w.Write([]byte(`{"login":"bot"}`))
+		case r.URL.Path == "/api/v3/repos/org/public" && auth == "":
+			_, _ = w.Write([]byte(`{"name":"public","default_branch":"main","owner":{"login":"org"}}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+		}
+	}))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+providers:
+  github:
+    default:
+      baseURL: %[1]s/
+      analyzer: poetry
+    repositories:
+      - owner: org
+        repository: api
+        token: good-token-1234
+      - owner: org
+        repository: web
+        token: bad-token-9999
+      - owner: org
+        repository: public
+`, srv.URL))
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	root := newRootCmd()
+	root.SetArgs([]string{"doctor", cfgPath, "--format", "json", "--snapshot", filepath.Join(t.TempDir(), "snap.json")})
+	out, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ProviderError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ProviderError, code, err)
+	}
+	var res doctorOutput
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	statuses := make(map[string]string)
+	for _, c := range res.Checks {
+		statuses[strings.Replace(c.Name, srv.URL+"/", "URL", 1)] = c.Status
+	}
+	want := map[string]string{
+		"config":                       checkOK,
+		"reach github URL token …1234": checkOK,
+		"token github URL token …1234": checkOK,
+		"reach github URL token …9999": checkOK,
+		"token github URL token …9999": checkFail,
+		"reach github URL":             checkOK,
+		"token github URL":             checkWarn,
+		"state":                        checkOK,
+		"cache":                        checkOK,
+	}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Errorf("%s: status %q, want %q (all: %v)", name, statuses[name], status, statuses)
+		}
+	}
+	if res.Healthy {
+		t.Error("expected unhealthy result")
+	}
+}
+
+// TestCLIDoctorInvalidConfig checks that provider checks are skipped when
+// the configuration does not load.
+func TestCLIDoctorInvalidConfig(t *testing.T) {
+	cfgPath := writeTempConfig(t, "providers: [")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	root := newRootCmd()
+	root.SetArgs([]string{"doctor", cfgPath, "--snapshot", "none"})
+	out, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	expectContains(t, out, "To fix:\n  config: Fix the error in", "remedy")
+	expectContains(t, out, "DevDashboard unhealthy", "summary")
+}
+
+func TestCheckCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "devdashboard")
+	if c := checkCache(dir, nil); c.Status != checkOK || !strings.Contains(c.Message, "not created yet") {
+		t.Errorf("missing dir: %+v", c)
+	}
+
+	bad := filepath.Join(dir, "snapshots", "bad.json")
+	if err := os.MkdirAll(filepath.Dir(bad), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := checkCache(dir, nil)
+	if c.Status != checkWarn || !strings.Contains(c.Remedy, bad) {
+		t.Errorf("corrupt snapshot: %+v", c)
+	}
+
+	if c := checkState(bad, false); c.Status != checkWarn || !strings.HasPrefix(c.Remedy, "Delete "+bad) {
+		t.Errorf("corrupt state: %+v", c)
+	}
+	if c := checkState("", true); c.Status != checkSkipped {
+		t.Errorf("disabled state: %+v", c)
+	}
+}
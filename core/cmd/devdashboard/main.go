@@ -5,18 +5,27 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/issues"
+	"github.com/greg-hellings/devdashboard/core/pkg/notify"
 	"github.com/greg-hellings/devdashboard/core/pkg/report"
 	consolefmt "github.com/greg-hellings/devdashboard/core/pkg/report/format"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
 	"github.com/spf13/cobra"
 )
 
@@ -25,8 +34,14 @@ var version = "dev"
 
 // Global (root-level) flag variables
 var (
-	flagVerbose bool
-	flagDebug   bool
+	flagVerbose        bool
+	flagDebug          bool
+	flagJSON           bool
+	flagTraceHTTP      bool
+	flagTraceHTTPFile  string
+	flagRecordFixtures string
+	flagReplayFixtures string
+	flagSet            []string
 )
 
 // dependency-report command flags
@@ -34,12 +49,33 @@ type depReportFlags struct {
 	outputFormat      string
 	outputFile        string
 	noColor           bool
+	quiet             bool
+	noSummary         bool
+	columns           []string
 	packageColWidth   int
 	repoColWidth      int
 	timeout           time.Duration
+	repoTimeout       time.Duration
 	failOnRepoError   bool
 	jsonIndent        bool
 	jsonIncludeErrors bool
+	packageGroups     []string
+	tags              []string
+	snapshot          string
+	force             bool
+	graph             bool
+	hashes            bool
+	includeDev        bool
+	archives          bool
+	cacheDir          string
+	cacheSize         int64
+	excludeDev        bool
+	noNotify          bool
+	noProgress        bool
+	noIssues          bool
+	resolveRefs       bool
+	preflight         bool
+	historySize       int
 }
 
 var depFlags depReportFlags
@@ -49,10 +85,22 @@ func main() {
 	root.SilenceUsage = true
 	root.SilenceErrors = true
 
-	if err := root.Execute(); err != nil {
+	err := root.Execute()
+	if traceErr := writeHTTPTrace(); err == nil {
+		err = traceErr
+	}
+	if telemetryErr := shutdownTelemetry(); err == nil {
+		err = telemetryErr
+	}
+	if err != nil {
 		// If Execute() returns an error, logging may or may not be initialized yet.
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		code := exitcode.FromError(err)
+		if flagJSON {
+			writeJSONError(os.Stderr, err, code)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(int(code))
 	}
 }
 
@@ -67,31 +115,148 @@ DevDashboard - Dependency reporting tool
 Current focus: Generate a cross-repository dependency version report using a
 configuration file that declares providers, repositories, analyzers, and the
 packages to track.`),
-		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		PersistentPreRunE: func(c *cobra.Command, _ []string) error {
 			initLogging()
-			return nil
+			initHTTPTrace()
+			if err := initHTTPFixtures(); err != nil {
+				return err
+			}
+			return applyJSONFlag(c)
 		},
 	}
 
 	// Global flags
 	cmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Enable verbose (info) logging")
 	cmd.PersistentFlags().BoolVar(&flagDebug, "debug", false, "Enable debug logging (overrides --verbose)")
+	cmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "Write JSON output (same as --format json) and JSON errors on stderr")
+	cmd.PersistentFlags().BoolVar(&flagTraceHTTP, "trace-http", false, "Log every provider API request (method, URL, status, duration, rate limit left) on stderr")
+	cmd.PersistentFlags().StringVar(&flagTraceHTTPFile, "trace-http-file", "", "Write provider API requests to this HAR-like JSON file (bodies and credentials omitted)")
+	cmd.PersistentFlags().StringVar(&flagRecordFixtures, "record-fixtures", "", "Save every provider API response to this directory for later --replay-fixtures runs (credentials omitted)")
+	cmd.PersistentFlags().StringVar(&flagReplayFixtures, "replay-fixtures", "", "Answer provider API requests from responses saved with --record-fixtures, without network access")
+	cmd.PersistentFlags().StringArrayVar(&flagSet, "set", nil, "Override a configuration field after loading, e.g. providers.github.default.ref=develop (repeatable; DEVDASHBOARD_* environment variables apply first)")
 	cmd.Version = version
 
 	// Add subcommands
 	cmd.AddCommand(newDependencyReportCmd())
 	cmd.AddCommand(newVersionCmd())
+	cmd.AddCommand(newExitCodesCmd())
+	cmd.AddCommand(newSchemaCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newWhoUsesCmd())
+	cmd.AddCommand(newPackageVersionsCmd())
+	cmd.AddCommand(newTrendCmd())
+	cmd.AddCommand(newQueryCmd())
+	cmd.AddCommand(newTUICmd())
+	cmd.AddCommand(newCheckCmd())
+	cmd.AddCommand(newBumpCmd())
+	cmd.AddCommand(newValidateConfigCmd())
+	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newCacheCmd())
 
 	return cmd
 }
 
+// applyJSONFlag makes the global --json flag select JSON output on commands
+// with a --format flag. Commands without one check flagJSON themselves.
+func applyJSONFlag(cmd *cobra.Command) error {
+	if !flagJSON {
+		return nil
+	}
+	f := cmd.Flags().Lookup("format")
+	if f == nil {
+		return nil
+	}
+	if f.Changed && !strings.EqualFold(f.Value.String(), "json") {
+		return exitcode.Errorf(exitcode.ConfigError, "--json conflicts with --format %s", f.Value.String())
+	}
+	return f.Value.Set("json")
+}
+
+// writeJSON writes v as one line of JSON
+func writeJSON(w ioWriter, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, _ = w.Write(append(data, '\n'))
+	return nil
+}
+
+// jsonError is the shape of errors written to stderr with --json
+type jsonError struct {
+	Error    string `json:"error"`
+	ExitCode int    `json:"exitCode"`
+	Code     string `json:"code"` // exitcode name, e.g. config-error
+}
+
+// writeJSONError writes a failed run's error to w as JSON
+func writeJSONError(w ioWriter, err error, code exitcode.Code) {
+	_ = writeJSON(w, jsonError{Error: err.Error(), ExitCode: int(code), Code: code.String()})
+}
+
 // newVersionCmd prints version info (simple helper).
 func newVersionCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "version",
 		Short: "Show version information",
-		Run: func(_ *cobra.Command, _ []string) {
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if flagJSON {
+				return writeJSON(os.Stdout, map[string]string{"version": version})
+			}
 			fmt.Printf("DevDashboard version: %s\n", version)
+			return nil
+		},
+	}
+}
+
+// exitCodeInfo is the JSON shape of one exit-codes entry
+type exitCodeInfo struct {
+	Code        int    `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// newExitCodesCmd lists the stable exit codes returned by all commands.
+func newExitCodesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "exit-codes",
+		Short: "List process exit codes and their meaning",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			w := cmd.OutOrStdout()
+			if flagJSON {
+				codes := make([]exitCodeInfo, 0, len(exitcode.All()))
+				for _, code := range exitcode.All() {
+					codes = append(codes, exitCodeInfo{Code: int(code), Name: code.String(), Description: code.Description()})
+				}
+				return writeJSON(w, codes)
+			}
+			for _, code := range exitcode.All() {
+				_, _ = fmt.Fprintf(w, "%d  %-16s %s\n", int(code), code.String(), code.Description())
+			}
+			return nil
+		},
+	}
+}
+
+// newSchemaCmd prints the JSON Schema of the --format json report.
+func newSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of JSON reports",
+		Long: strings.TrimSpace(`
+Print the JSON Schema (draft 2020-12) of the report written by
+dependency-report --format json, the serve API and the GUI's Export JSON.
+The document's schemaVersion changes when a field is removed or changes
+meaning; new fields keep the version.
+`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			schema, err := report.JSONSchema()
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(schema))
+			return err
 		},
 	}
 }
@@ -109,25 +274,63 @@ analyzer type, and the packages to track.
 Formats:
   console (default) - adaptive terminal table
   json              - machine-readable JSON
+  dot               - Graphviz dependency graph per repository (implies --graph)
+  html              - standalone HTML page (the email digest layout)
+
+Configured notifications report what changed since the run recorded in the
+snapshot (skip them with --no-notify). Configured issue trackers get a ticket
+per policy violation, closed once it is fixed (skip them with --no-issues).
+
+Each repository's ref (a branch, tag or commit SHA) is resolved to a commit
+before analysis; files are read at that commit and it is recorded as CommitSHA
+in JSON output, so results are reproducible. --resolve-refs prints the
+ref-to-commit mapping without analyzing.
 
 Examples:
   devdashboard dependency-report repos.yaml
   devdashboard dependency-report repos.yaml --format json --json-indent
   devdashboard dependency-report repos.yaml --format console --no-color
+  devdashboard dependency-report repos.yaml --packages-group crypto-critical
+  devdashboard dependency-report repos.yaml --tag team-payments
+  devdashboard dependency-report repos.yaml --format dot | dot -Tsvg > deps.svg
+  devdashboard dependency-report repos.yaml --format html -o report.html
+  devdashboard dependency-report repos.yaml --resolve-refs
 `),
 		Args: cobra.ExactArgs(1),
 		RunE: runDependencyReport,
 	}
 
-	c.Flags().StringVarP(&depFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	c.Flags().StringVarP(&depFlags.outputFormat, "format", "f", "console", "Output format: console|json|dot|html")
 	c.Flags().StringVarP(&depFlags.outputFile, "out", "o", "", "Write output to file instead of stdout")
 	c.Flags().BoolVar(&depFlags.noColor, "no-color", false, "Disable ANSI colors (console format)")
+	c.Flags().BoolVarP(&depFlags.quiet, "quiet", "q", false, "Porcelain output: one tab-separated \"repository package version\" line per cell (console format)")
+	c.Flags().BoolVar(&depFlags.noProgress, "no-progress", false, "Do not draw the progress bar on a terminal")
+	c.Flags().BoolVar(&depFlags.noSummary, "no-summary", false, "Omit the summary section (console format)")
+	c.Flags().StringSliceVar(&depFlags.columns, "columns", nil, "Package columns to show, in order (console format; repeatable or comma-separated)")
 	c.Flags().IntVar(&depFlags.packageColWidth, "package-col-width", 0, "Max width of package column (console format; 0=auto)")
 	c.Flags().IntVar(&depFlags.repoColWidth, "repo-col-width", 0, "Max width of repository/version columns (console format; 0=auto)")
-	c.Flags().DurationVar(&depFlags.timeout, "timeout", 5*time.Minute, "Timeout for generating the report")
+	c.Flags().DurationVar(&depFlags.timeout, "timeout", 5*time.Minute, "Timeout for generating the report (repositories still running are reported as timed out)")
+	c.Flags().DurationVar(&depFlags.repoTimeout, "repo-timeout", 0, "Timeout for analyzing each repository (0 = limited only by --timeout)")
 	c.Flags().BoolVar(&depFlags.failOnRepoError, "fail-on-error", false, "Exit with non-zero status if any repository failed to analyze")
 	c.Flags().BoolVar(&depFlags.jsonIndent, "json-indent", false, "Pretty-print JSON output")
 	c.Flags().BoolVar(&depFlags.jsonIncludeErrors, "json-include-errors", true, "Include repository errors section in JSON output")
+	c.Flags().StringSliceVar(&depFlags.packageGroups, "packages-group", nil, "Only report packages in these named packageGroups (repeatable or comma-separated)")
+	c.Flags().StringSliceVar(&depFlags.tags, "tag", nil, "Only report repositories carrying any of these tags (repeatable or comma-separated)")
+	c.Flags().StringVar(&depFlags.snapshot, "snapshot", "", "Commit snapshot used to skip unchanged repositories (default: per-config file in the user cache directory; \"none\" disables)")
+	c.Flags().IntVar(&depFlags.historySize, "history-size", report.DefaultSnapshotHistory, "Snapshots kept next to --snapshot for the trend command (0 disables the history)")
+	c.Flags().BoolVar(&depFlags.force, "force", false, "Re-analyze every repository even if its commit is unchanged since the last run")
+	c.Flags().BoolVar(&depFlags.graph, "graph", false, "Include each repository's package dependency graph (uv.lock, poetry.lock) in JSON output")
+	c.Flags().BoolVar(&depFlags.includeDev, "include-dev", true, "Report packages locked as development dependencies, marking those locked only for development")
+	c.Flags().BoolVar(&depFlags.excludeDev, "exclude-dev", false, "Leave development dependencies out of the report (same as --include-dev=false)")
+	c.MarkFlagsMutuallyExclusive("include-dev", "exclude-dev")
+	c.Flags().BoolVar(&depFlags.hashes, "hashes", false, "Include the artifact hashes lock files pin for tracked packages, flagging packages without any")
+	c.Flags().BoolVar(&depFlags.archives, "archive", false, "Download each repository's archive once instead of fetching files individually (GitHub, GitLab)")
+	c.Flags().StringVar(&depFlags.cacheDir, "cache-dir", "", "Content cache of lock files read at unchanged commits (default: in the user cache directory; \"none\" disables)")
+	c.Flags().Int64Var(&depFlags.cacheSize, "cache-size", repository.DefaultContentCacheSize>>20, "Max disk usage of the content cache in MiB; least recently used entries are evicted (0 = unlimited)")
+	c.Flags().BoolVar(&depFlags.noNotify, "no-notify", false, "Do not send the configured notifications for this run")
+	c.Flags().BoolVar(&depFlags.noIssues, "no-issues", false, "Do not open, update or close issue tracker tickets for this run")
+	c.Flags().BoolVar(&depFlags.preflight, "preflight", false, "Before the run, check that each repository can be read with its token and fail listing those that cannot")
+	c.Flags().BoolVar(&depFlags.resolveRefs, "resolve-refs", false, "Only print the commit each repository's ref resolves to (console or json format)")
 
 	return c
 }
@@ -152,8 +355,14 @@ func initLogging() {
 	slog.Debug("Logging initialized", "level", level.String())
 }
 
+// loadConfig loads the configuration file at path with the overrides of
+// DEVDASHBOARD_* environment variables and --set flags
+func loadConfig(path string) (*config.Config, error) {
+	return config.LoadFromFileWithOverrides(path, config.Overrides{Set: flagSet, Environ: os.Environ()})
+}
+
 // runDependencyReport executes the core logic for dependency-report.
-func runDependencyReport(_ *cobra.Command, args []string) error {
+func runDependencyReport(cmd *cobra.Command, args []string) error {
 	start := time.Now()
 	configFile := args[0]
 
@@ -161,35 +370,43 @@ func runDependencyReport(_ *cobra.Command, args []string) error {
 		"configFile", configFile,
 		"format", depFlags.outputFormat)
 
-	cfg, err := config.LoadFromFile(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	// Reject the output before the run notifies anyone or saves anything
+	format := strings.ToLower(depFlags.outputFormat)
+	switch format {
+	case "console", "json", "dot", "html":
+	default:
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", depFlags.outputFormat)
 	}
 
-	repos := cfg.GetAllRepos()
-	if len(repos) == 0 {
-		return errors.New("no repositories configured in the provided file")
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), depFlags.timeout)
-	defer cancel()
-
-	generator := report.NewGenerator()
-	rpt, err := generator.Generate(ctx, repos)
+	repos, err := selectRepos(cfg, depFlags.tags, depFlags.packageGroups)
 	if err != nil {
-		return fmt.Errorf("failed to generate report: %w", err)
+		return err
 	}
-
-	var outWriter ioWriteCloser = stdOutWriteCloser{w: os.Stdout}
-	if depFlags.outputFile != "" {
-		if err := os.MkdirAll(filepath.Dir(depFlags.outputFile), 0o750); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
-		}
-		f, err := os.Create(depFlags.outputFile)
+	applyConfigTimeouts(cmd, cfg, &depFlags.timeout, &depFlags.repoTimeout)
+	if depFlags.resolveRefs {
+		return runResolveRefs(cmd.Context(), cfg, repos)
+	}
+	if depFlags.preflight {
+		ctx, cancel := context.WithTimeout(cmd.Context(), depFlags.timeout)
+		err := runPreflight(ctx, cfg, repos, cmd.ErrOrStderr())
+		cancel()
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return err
 		}
-		outWriter = f
+	}
+	generator, err := newConfiguredGenerator(cfg, depFlags.repoTimeout)
+	if err != nil {
+		return err
+	}
+
+	outWriter, err := openOutput(depFlags.outputFile)
+	if err != nil {
+		return err
 	}
 	defer func() {
 		if cerr := outWriter.Close(); cerr != nil {
@@ -197,7 +414,80 @@ func runDependencyReport(_ *cobra.Command, args []string) error {
 		}
 	}()
 
-	switch strings.ToLower(depFlags.outputFormat) {
+	ctx, cancel := context.WithTimeout(context.Background(), depFlags.timeout)
+	defer cancel()
+
+	var notifiers []*notify.Notifier
+	if !depFlags.noNotify {
+		if notifiers, err = notify.FromConfig(cfg.Notifications); err != nil {
+			return err
+		}
+	}
+	var syncers []*issues.Syncer
+	if !depFlags.noIssues {
+		if syncers, err = issues.FromConfig(cfg); err != nil {
+			return err
+		}
+	}
+
+	snapshotPath := resolveSnapshotPath(depFlags.snapshot, configFile)
+	var prev *report.Snapshot
+	if snapshotPath != "" {
+		if prev, err = report.LoadSnapshot(snapshotPath); err != nil {
+			slog.Warn("Ignoring unreadable snapshot", "path", snapshotPath, "error", err)
+		}
+	}
+	policies, err := report.PoliciesFromConfig(cfg.Policies)
+	if err != nil {
+		return err
+	}
+	ignores, err := report.IgnoreRulesFromConfig(cfg.Ignores)
+	if err != nil {
+		return err
+	}
+	contentCache, err := openContentCache(depFlags.cacheDir, depFlags.cacheSize)
+	if err != nil {
+		slog.Warn("Content cache unavailable; fetching every file", "error", err)
+	}
+	opts := services.ReportOptions{
+		IncludeGraph:      depFlags.graph || format == "dot",
+		IncludeHashes:     depFlags.hashes,
+		ExcludeDev:        depFlags.excludeDev || !depFlags.includeDev,
+		UseArchives:       depFlags.archives,
+		ContentCache:      contentCache,
+		Policies:          policies,
+		IgnoreRules:       ignores,
+		HTTPTracer:        httpTracer,
+		Fixtures:          httpFixtures,
+		RepositoryTimeout: depFlags.repoTimeout,
+	}
+	if cfg.Timeouts != nil {
+		opts.RequestTimeout = cfg.Timeouts.Request
+	}
+	if !depFlags.force {
+		opts.Previous = prev
+	}
+	progressCh, handle, err := services.NewDependencyService(generator).RunReport(ctx, repos, opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	tty := !depFlags.noProgress && !depFlags.quiet && isTerminal()
+	newProgressDisplay(os.Stderr, len(repos), tty).Run(progressCh)
+	rpt, err := handle.Result()
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	// The previous run's snapshot is the baseline for "new" changes; the run
+	// timeout may have expired, so deliveries get their own
+	baseline := prev.Report()
+	notify.NotifyAll(context.Background(), notifiers, baseline, rpt)
+	for _, c := range report.HashCountChanges(baseline, rpt) {
+		slog.Warn("Artifact hash count changed without a version change",
+			"repository", c.Repository, "package", c.Package, "version", c.Version,
+			"before", c.Before, "after", c.After)
+	}
+
+	switch format {
 	case "console":
 		if err := renderConsole(rpt, outWriter); err != nil {
 			return fmt.Errorf("failed to render console output: %w", err)
@@ -206,8 +496,32 @@ func runDependencyReport(_ *cobra.Command, args []string) error {
 		if err := renderJSON(rpt, outWriter); err != nil {
 			return fmt.Errorf("failed to render JSON output: %w", err)
 		}
-	default:
-		return fmt.Errorf("unsupported format: %s", depFlags.outputFormat)
+	case "dot":
+		if err := renderDOT(rpt, outWriter); err != nil {
+			return fmt.Errorf("failed to render DOT output: %w", err)
+		}
+	case "html":
+		if err := consolefmt.RenderHTML(rpt, outWriter); err != nil {
+			return fmt.Errorf("failed to render HTML output: %w", err)
+		}
+	}
+
+	// Tickets and the snapshot only follow a run whose output was written.
+	// The run timeout may have expired, so the sync gets its own context,
+	// which Ctrl-C cancels.
+	syncCtx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	issues.SyncAll(syncCtx, syncers, rpt)
+	stop()
+	if snapshotPath != "" {
+		if err := report.SaveSnapshot(snapshotPath, rpt.Snapshot()); err != nil {
+			slog.Warn("Failed to save snapshot", "path", snapshotPath, "error", err)
+		}
+		if depFlags.historySize > 0 {
+			dir := report.HistoryDir(snapshotPath)
+			if err := report.ArchiveSnapshot(dir, rpt.Snapshot(), depFlags.historySize); err != nil {
+				slog.Warn("Failed to archive snapshot", "path", dir, "error", err)
+			}
+		}
 	}
 
 	duration := time.Since(start)
@@ -217,20 +531,119 @@ func runDependencyReport(_ *cobra.Command, args []string) error {
 		"duration", duration.String())
 
 	if depFlags.failOnRepoError && rpt.HasErrors() {
-		return errors.New("one or more repositories failed (fail-on-error enabled)")
+		return fmt.Errorf("%w (fail-on-error enabled)", rpt.Err())
 	}
 
-	return nil
+	return rpt.PolicyErr()
+}
+
+// selectRepos returns the configured repositories, narrowed to those carrying
+// any of tags and tracking packages of the named package groups. Selecting
+// nothing is a configuration error.
+func selectRepos(cfg *config.Config, tags, packageGroups []string) ([]config.RepoWithProvider, error) {
+	repos := cfg.GetAllRepos()
+	if len(repos) == 0 {
+		return nil, exitcode.New(exitcode.ConfigError, errors.New("no repositories configured in the provided file"))
+	}
+	if len(tags) > 0 {
+		available := config.RepoTags(repos)
+		repos = config.FilterTags(repos, tags)
+		if len(repos) == 0 {
+			inUse := "none defined"
+			if len(available) > 0 {
+				inUse = strings.Join(available, ", ")
+			}
+			return nil, exitcode.New(exitcode.ConfigError, fmt.Errorf("no repositories tagged %s (available: %s)", strings.Join(tags, ", "), inUse))
+		}
+		slog.Debug("Filtered by tags", "tags", tags, "repos", len(repos))
+	}
+	if len(packageGroups) > 0 {
+		packages, err := cfg.ResolvePackageGroups(packageGroups)
+		if err != nil {
+			return nil, err
+		}
+		repos = config.FilterPackages(repos, packages)
+		if len(repos) == 0 {
+			return nil, exitcode.New(exitcode.ConfigError, fmt.Errorf("no repositories track packages in group(s) %s", strings.Join(packageGroups, ", ")))
+		}
+		slog.Debug("Filtered by package groups", "groups", packageGroups, "packages", packages, "repos", len(repos))
+	}
+	return repos, nil
+}
+
+// applyConfigTimeouts replaces the run and per-repository timeouts with the
+// configuration's timeouts section, except where --timeout or --repo-timeout
+// was given explicitly
+func applyConfigTimeouts(cmd *cobra.Command, cfg *config.Config, timeout, repoTimeout *time.Duration) {
+	if cfg.Timeouts == nil {
+		return
+	}
+	if cfg.Timeouts.Run > 0 && !cmd.Flags().Changed("timeout") {
+		*timeout = cfg.Timeouts.Run
+	}
+	if cfg.Timeouts.Repository > 0 && !cmd.Flags().Changed("repo-timeout") {
+		*repoTimeout = cfg.Timeouts.Repository
+	}
+}
+
+// newConfiguredGenerator creates a report generator with the configuration's
+// retry policy, budgets, request timeout, file size limit, policies and
+// hooks, enabling telemetry export when configured
+func newConfiguredGenerator(cfg *config.Config, repoTimeout time.Duration) (*report.Generator, error) {
+	if err := initTelemetry(cfg); err != nil {
+		return nil, err
+	}
+	generator, err := report.NewGeneratorFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	generator.SetRepositoryTimeout(repoTimeout)
+	generator.SetHTTPTracer(httpTracer)
+	generator.SetFixtures(httpFixtures)
+	return generator, nil
+}
+
+// resolveSnapshotPath returns the snapshot file for a run: flag when set,
+// "" when it is "none", otherwise a file in the user cache directory keyed
+// by the absolute config path (so different configs never share results).
+func resolveSnapshotPath(flag, configFile string) string {
+	switch flag {
+	case "none":
+		return ""
+	case "":
+	default:
+		return flag
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		slog.Debug("No user cache directory; incremental analysis disabled", "error", err)
+		return ""
+	}
+	abs, err := filepath.Abs(configFile)
+	if err != nil {
+		abs = configFile
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(cacheDir, "devdashboard", "snapshots", hex.EncodeToString(sum[:8])+".json")
 }
 
 // renderConsole renders the report using the console formatter.
 func renderConsole(rpt *report.Report, w ioWriter) error {
-	if _, err := fmt.Fprintf(w, "Dependency Version Report (format=console)\n\n"); err != nil {
-		return fmt.Errorf("failed to write console header: %w", err)
+	formatter := consolefmt.NewConsoleFormatter()
+	formatter.Columns = depFlags.columns
+	if _, err := formatter.PackageColumns(rpt); err != nil {
+		return exitcode.New(exitcode.ConfigError, err)
+	}
+	if !depFlags.quiet {
+		if _, err := fmt.Fprintf(w, "Dependency Version Report (format=console)\n\n"); err != nil {
+			return fmt.Errorf("failed to write console header: %w", err)
+		}
 	}
 
-	formatter := consolefmt.NewConsoleFormatter()
-	formatter.EnableColors = !depFlags.noColor
+	// https://no-color.org: any non-empty NO_COLOR disables colors
+	formatter.EnableColors = !depFlags.noColor && os.Getenv("NO_COLOR") == ""
+	formatter.Quiet = depFlags.quiet
+	formatter.NoSummary = depFlags.noSummary
 	if depFlags.packageColWidth > 0 {
 		formatter.MaxPackageColWidth = depFlags.packageColWidth
 	}
@@ -240,69 +653,45 @@ func renderConsole(rpt *report.Report, w ioWriter) error {
 	return formatter.Render(rpt, w)
 }
 
-// jsonOutput is the structured JSON shape we emit (allows adding summary without
-// changing core report.Report struct).
-type jsonOutput struct {
-	Version      string                    `json:"cliVersion"`
-	GeneratedAt  time.Time                 `json:"generatedAt"`
-	Repositories []report.RepositoryReport `json:"repositories"`
-	Packages     []string                  `json:"packages"`
-	Summary      jsonSummary               `json:"summary"`
-	Errors       map[string]string         `json:"errors,omitempty"`
-}
-
-type jsonSummary struct {
-	RepositoryCount int `json:"repositoryCount"`
-	PackageCount    int `json:"packageCount"`
-	SuccessCount    int `json:"successCount"`
-	ErrorCount      int `json:"errorCount"`
+// renderJSON writes the report's canonical JSON document (see
+// report.JSONDocument).
+func renderJSON(rpt *report.Report, w ioWriter) error {
+	opts := report.JSONOptions{Version: version, OmitErrors: !depFlags.jsonIncludeErrors}
+	return rpt.WriteJSON(w, opts, depFlags.jsonIndent)
 }
 
-// renderJSON marshals the report to JSON with additional metadata.
-func renderJSON(rpt *report.Report, w ioWriter) error {
-	successCount := 0
+// renderDOT writes one Graphviz digraph per repository that has a dependency
+// graph; repositories without one (errors, no dependencies) are noted in
+// comments.
+func renderDOT(rpt *report.Report, w ioWriter) error {
 	for _, rr := range rpt.Repositories {
-		if rr.Error == nil {
-			successCount++
+		if rr.Graph == nil {
+			if _, err := fmt.Fprintf(w, "// %s: no dependency graph\n", rr.Key()); err != nil {
+				return err
+			}
+			continue
 		}
-	}
-	errCount := len(rpt.Repositories) - successCount
-
-	var errMap map[string]string
-	if depFlags.jsonIncludeErrors && rpt.HasErrors() {
-		errMap = make(map[string]string)
-		for repoID, err := range rpt.GetErrors() {
-			errMap[repoID] = err.Error()
+		if err := rr.Graph.WriteDOT(w, rr.Key()); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	payload := jsonOutput{
-		Version:      version,
-		GeneratedAt:  time.Now().UTC(),
-		Repositories: rpt.Repositories,
-		Packages:     rpt.Packages,
-		Summary: jsonSummary{
-			RepositoryCount: len(rpt.Repositories),
-			PackageCount:    len(rpt.Packages),
-			SuccessCount:    successCount,
-			ErrorCount:      errCount,
-		},
-		Errors: errMap,
+// openOutput returns a writer for path, creating its directory, or for stdout
+// when path is empty
+func openOutput(path string) (ioWriteCloser, error) {
+	if path == "" {
+		return stdOutWriteCloser{w: os.Stdout}, nil
 	}
-
-	var data []byte
-	var err error
-	if depFlags.jsonIndent {
-		data, err = json.MarshalIndent(payload, "", "  ")
-	} else {
-		data, err = json.Marshal(payload)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
+	f, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return nil, fmt.Errorf("failed to create output file: %w", err)
 	}
-	_, _ = w.Write(data)
-	_, _ = w.Write([]byte("\n"))
-	return nil
+	return f, nil
 }
 
 /* ---------- Minimal ioWriter / ioWriteCloser helpers (avoid extra imports) ---------- */
@@ -4,15 +4,38 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
 	"github.com/spf13/cobra"
 )
 
+// TestMain points the user cache directory (commit snapshots) at a temporary
+// directory so tests never read or write the real one.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "devdashboard-cli-test")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_CACHE_HOME", dir)
+	_ = os.Setenv("HOME", dir)
+	_ = os.Setenv("LocalAppData", dir)
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // TestCLIJSONOutputBasic verifies that invoking the CLI with --format json
 // produces valid JSON with the expected shape and content (including errors).
 func TestCLIJSONOutputBasic(t *testing.T) {
@@ -55,7 +78,8 @@ providers:
 			SuccessCount    int `json:"successCount"`
 			ErrorCount      int `json:"errorCount"`
 		} `json:"summary"`
-		Errors map[string]string `json:"errors"`
+		Errors          map[string]string `json:"errors"`
+		ErrorCategories map[string]string `json:"errorCategories"`
 	}
 
 	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
@@ -90,6 +114,9 @@ providers:
 	if !foundKey {
 		t.Errorf("expected errors map to contain key dummyowner/dummyrepo; keys: %v", keys(parsed.Errors))
 	}
+	if got := parsed.ErrorCategories["dummyowner/dummyrepo"]; got != "config" {
+		t.Errorf("expected errorCategories[dummyowner/dummyrepo]=config, got %q", got)
+	}
 
 	// Pretty-print (indent) check: expect leading newline+two-spaces after an opening brace somewhere
 	if !strings.Contains(output, "\n  \"repositories\"") {
@@ -128,6 +155,9 @@ providers:
 	if !strings.Contains(err.Error(), "one or more repositories failed") {
 		t.Errorf("expected fail-on-error message in error: %v", err)
 	}
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Errorf("expected exit code %d (config error) for invalid analyzer, got %d", exitcode.ConfigError, code)
+	}
 
 	// Output should still be valid JSON (partial validation).
 	var parsed map[string]interface{}
@@ -136,6 +166,423 @@ providers:
 	}
 }
 
+// TestCLIExitCodeConfigError ensures an unreadable config maps to exitcode.ConfigError.
+func TestCLIExitCodeConfigError(t *testing.T) {
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", filepath.Join(t.TempDir(), "missing.yaml")})
+
+	_, err := executeCommand(root)
+	if err == nil {
+		t.Fatal("expected error for missing config file")
+	}
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Errorf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+}
+
+// TestCLIUnknownPackageGroup ensures an unknown --packages-group is a config error naming the available groups.
+func TestCLIUnknownPackageGroup(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+packageGroups:
+  web: ["django"]
+providers:
+  github:
+    default:
+      analyzer: poetry
+    repositories:
+      - owner: o
+        repository: r
+        packages: ["django"]
+`)
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--packages-group", "crypto"})
+
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	expectContains(t, err.Error(), "available: web", "unknown group error")
+}
+
+// TestCLIUnknownTag ensures a --tag no repository carries is a config error naming the tags in use.
+func TestCLIUnknownTag(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+providers:
+  github:
+    default:
+      analyzer: poetry
+      tags: ["team-payments"]
+    repositories:
+      - owner: o
+        repository: r
+        packages: ["django"]
+        tags: ["deprecated"]
+`)
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--tag", "team-search"})
+
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	expectContains(t, err.Error(), "available: deprecated, team-payments", "unknown tag error")
+}
+
+// TestCLIInvalidPolicy ensures a policy with an unparsable range is a config error.
+func TestCLIInvalidPolicy(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+policies:
+  - name: django-lts
+    package: django
+    version: ">=four"
+providers:
+  github:
+    default:
+      analyzer: poetry
+    repositories:
+      - owner: o
+        repository: r
+        packages: ["django"]
+`)
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath})
+
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	expectContains(t, err.Error(), "policy django-lts", "policy error")
+}
+
+// TestCLINotifications posts a new failure once: the second run finds it in
+// the snapshot and has nothing new to report.
+func TestCLINotifications(t *testing.T) {
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		posts = append(posts, string(data))
+	}))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+notifications:
+  - name: chat
+    type: slack
+    url: %s
+providers:
+  github:
+    repositories:
+      - owner: o
+        repository: r
+        analyzer: invalidAnalyzerX
+        packages: ["django"]
+`, srv.URL))
+	snapshot := filepath.Join(t.TempDir(), "snap.json")
+
+	for range 2 {
+		root := newRootCmd()
+		root.SetArgs([]string{"dependency-report", cfgPath, "--format", "json", "--snapshot", snapshot})
+		if output, err := executeCommand(root); err != nil {
+			t.Fatalf("command returned error: %v\nOutput: %s", err, output)
+		}
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected one notification, got %d: %v", len(posts), posts)
+	}
+	expectContains(t, posts[0], "Newly failing repositories", "notification text")
+
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--snapshot", "none", "--no-notify"})
+	if _, err := executeCommand(root); err != nil {
+		t.Fatalf("--no-notify run returned error: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Errorf("--no-notify still posted: %v", posts)
+	}
+}
+
+// TestCLINotificationsUnknownFormat ensures an unusable --format fails before
+// anything is posted.
+func TestCLINotificationsUnknownFormat(t *testing.T) {
+	var posts int
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { posts++ }))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+notifications:
+  - name: chat
+    type: slack
+    url: %s
+providers:
+  github:
+    repositories:
+      - owner: o
+        repository: r
+        analyzer: invalidAnalyzerX
+        packages: ["django"]
+`, srv.URL))
+	snapshot := filepath.Join(t.TempDir(), "snap.json")
+
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--format", "typo", "--snapshot", snapshot})
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	if posts != 0 {
+		t.Errorf("unknown format still posted %d notifications", posts)
+	}
+	if _, err := os.Stat(snapshot); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unknown format still saved a snapshot (%v)", err)
+	}
+}
+
+// TestCLIIssueTrackers ensures unusable trackers are config errors unless
+// --no-issues skips them.
+func TestCLIIssueTrackers(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+issues:
+  - name: ops
+    type: jira
+    baseURL: https://example.atlassian.net
+    project: OPS
+    tokenEnv: DEVDASHBOARD_TEST_UNSET_TOKEN
+providers:
+  github:
+    repositories:
+      - owner: o
+        repository: r
+        analyzer: invalidAnalyzerX
+        packages: ["django"]
+`)
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--snapshot", "none"})
+	if _, err := executeCommand(root); exitcode.FromError(err) != exitcode.ConfigError {
+		t.Fatalf("expected a config error for the unset token, got %v", err)
+	}
+
+	root = newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--snapshot", "none", "--no-issues"})
+	if output, err := executeCommand(root); err != nil {
+		t.Fatalf("--no-issues run returned error: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestCLIExitCodesCommand ensures the exit-codes command documents every code.
+func TestCLIExitCodesCommand(t *testing.T) {
+	root := newRootCmd()
+	root.SetArgs([]string{"exit-codes"})
+
+	output, err := executeCommand(root)
+	if err != nil {
+		t.Fatalf("exit-codes failed: %v", err)
+	}
+	for _, code := range exitcode.All() {
+		expectContains(t, output, code.String(), "exit-codes output")
+	}
+}
+
+// TestCLISchemaCommand verifies the printed schema describes the JSON report.
+func TestCLISchemaCommand(t *testing.T) {
+	root := newRootCmd()
+	root.SetArgs([]string{"schema"})
+	output, err := executeCommand(root)
+	if err != nil {
+		t.Fatalf("schema failed: %v", err)
+	}
+	var schema struct {
+		ID         string                     `json:"$id"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(output), &schema); err != nil || schema.ID != report.JSONSchemaID {
+		t.Fatalf("schema = %s (err %v)", output, err)
+	}
+	for _, key := range []string{"schemaVersion", "repositories", "summary", "errorCategories"} {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("schema has no %q property", key)
+		}
+	}
+}
+
+// TestCLIGlobalJSONFlag verifies --json selects JSON output on every command
+// and conflicts with another --format.
+func TestCLIGlobalJSONFlag(t *testing.T) {
+	root := newRootCmd()
+	root.SetArgs([]string{"exit-codes", "--json"})
+	output, err := executeCommand(root)
+	if err != nil {
+		t.Fatalf("exit-codes --json failed: %v", err)
+	}
+	var codes []exitCodeInfo
+	if err := json.Unmarshal([]byte(output), &codes); err != nil || len(codes) != len(exitcode.All()) || codes[4].Name != "config-error" {
+		t.Fatalf("exit-codes --json = %s (err %v)", output, err)
+	}
+
+	root = newRootCmd()
+	root.SetArgs([]string{"--json", "version"})
+	output, err = executeCommand(root)
+	if err != nil {
+		t.Fatalf("version --json failed: %v", err)
+	}
+	expectContains(t, output, `{"version":"dev"}`, "version --json output")
+
+	cfgPath := writeTempConfig(t, `
+providers:
+  github:
+    repositories:
+      - owner: dummyowner
+        repository: dummyrepo
+        analyzer: invalidAnalyzerX
+        packages: [pkgA]
+`)
+	root = newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--json", "--snapshot", "none"})
+	output, err = executeCommand(root)
+	if err != nil {
+		t.Fatalf("dependency-report --json failed: %v", err)
+	}
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil || parsed["summary"] == nil {
+		t.Errorf("dependency-report --json is not the JSON report: %v\n%s", err, output)
+	}
+
+	root = newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--json", "--format", "html"})
+	_, err = executeCommand(root)
+	if err == nil || exitcode.FromError(err) != exitcode.ConfigError || !strings.Contains(err.Error(), "conflicts") {
+		t.Errorf("--json with --format html: err = %v, want a config error", err)
+	}
+
+	var buf bytes.Buffer
+	writeJSONError(&buf, exitcode.Errorf(exitcode.ConfigError, "bad config"), exitcode.ConfigError)
+	expectContains(t, buf.String(), `{"error":"bad config","exitCode":4,"code":"config-error"}`, "JSON error")
+}
+
+func TestCLIRecordReplayFixtures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+providers:
+  github:
+    repositories:
+      - owner: org
+        repository: api
+        analyzer: poetry
+        baseURL: %s/
+        token: secret-token
+`, srv.URL))
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	run := func(args ...string) string {
+		t.Helper()
+		root := newRootCmd()
+		root.SetArgs(append([]string{"dependency-report", cfgPath, "--snapshot", "none", "--no-progress", "--format", "json"}, args...))
+		output, _ := executeCommand(root)
+		var rpt struct {
+			Errors map[string]string `json:"errors"`
+		}
+		if err := json.Unmarshal([]byte(output), &rpt); err != nil || rpt.Errors["org/api"] == "" {
+			t.Fatalf("unexpected report (%v):\n%s", err, output)
+		}
+		return rpt.Errors["org/api"]
+	}
+
+	recorded := run("--record-fixtures", dir)
+	srv.Close()
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) == 0 {
+		t.Fatal("no fixtures recorded")
+	}
+	if replayed := run("--replay-fixtures", dir); replayed != recorded {
+		t.Errorf("replayed error = %q, want the recorded %q", replayed, recorded)
+	}
+
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--record-fixtures", dir, "--replay-fixtures", dir})
+	_, err := executeCommand(root)
+	if exitcode.FromError(err) != exitcode.ConfigError {
+		t.Errorf("both fixture flags: err = %v, want a config error", err)
+	}
+}
+
+func TestCLITraceHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+providers:
+  github:
+    repositories:
+      - owner: org
+        repository: api
+        analyzer: poetry
+        baseURL: %s/
+        token: secret-token
+`, srv.URL))
+	harPath := filepath.Join(t.TempDir(), "trace.har")
+
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--snapshot", "none", "--no-progress", "--trace-http-file", harPath})
+	_, _ = executeCommand(root)
+	if err := writeHTTPTrace(); err != nil {
+		t.Fatalf("writeHTTPTrace: %v", err)
+	}
+
+	data, err := os.ReadFile(harPath)
+	if err != nil {
+		t.Fatalf("trace file not written: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("trace file contains the token")
+	}
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request            struct{ URL string }
+				Response           struct{ Status int }
+				RateLimitRemaining string `json:"_rateLimitRemaining"`
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &har); err != nil || len(har.Log.Entries) == 0 {
+		t.Fatalf("trace file = %s (err %v), want recorded requests", data, err)
+	}
+	e := har.Log.Entries[0]
+	if !strings.HasPrefix(e.Request.URL, srv.URL) || e.Response.Status != http.StatusNotFound || e.RateLimitRemaining != "42" {
+		t.Errorf("first entry = %+v", e)
+	}
+}
+
+func TestCLIConfigTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+retry:
+  maxAttempts: 1
+timeouts:
+  request: 50ms
+providers:
+  github:
+    repositories:
+      - owner: org
+        repository: api
+        analyzer: poetry
+        baseURL: %s/
+`, srv.URL))
+
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--snapshot", "none", "--no-progress", "--format", "json"})
+	out, _ := executeCommand(root)
+	expectContains(t, out, "exceeded 50ms request timeout", "request timeout error")
+	expectContains(t, out, `"errorCategories":{"org/api":"timeout"}`, "error category")
+}
+
 // Helper: write temp config file
 func writeTempConfig(t *testing.T, content string) string {
 	t.Helper()
@@ -186,3 +633,68 @@ func keys(m map[string]string) []string {
 	}
 	return out
 }
+
+// TestResolveSnapshotPath verifies explicit, disabled and per-config default snapshot paths.
+func TestResolveSnapshotPath(t *testing.T) {
+	if got := resolveSnapshotPath("none", "a.yaml"); got != "" {
+		t.Errorf("none = %q", got)
+	}
+	if got := resolveSnapshotPath("/tmp/snap.json", "a.yaml"); got != "/tmp/snap.json" {
+		t.Errorf("explicit = %q", got)
+	}
+	a, b := resolveSnapshotPath("", "a.yaml"), resolveSnapshotPath("", "b.yaml")
+	if a == "" || a == b || !strings.Contains(a, filepath.Join("devdashboard", "snapshots")) {
+		t.Errorf("defaults = %q, %q", a, b)
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	rpt := &report.Report{Repositories: []report.RepositoryReport{
+		{Provider: "github", Owner: "o", Repository: "api", Graph: &dependencies.Graph{
+			Nodes: []dependencies.GraphNode{{Name: "requests", Version: "2.31.0"}, {Name: "urllib3", Version: "2.0.7"}},
+			Edges: []dependencies.GraphEdge{{From: "requests", To: "urllib3"}},
+		}},
+		{Provider: "github", Owner: "o", Repository: "legacy"},
+	}}
+	var buf bytes.Buffer
+	if err := renderDOT(rpt, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"requests" -> "urllib3";`) || !strings.Contains(out, "legacy") || strings.Count(out, "digraph") != 1 {
+		t.Errorf("unexpected DOT output:\n%s", out)
+	}
+}
+
+func TestRenderJSONRefComparisons(t *testing.T) {
+	rpt := &report.Report{
+		Packages: []string{"django"},
+		Repositories: []report.RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2.11"}},
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "release/1.x", Analyzer: "poetry", Dependencies: map[string]string{"django": "3.2.25"}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := renderJSON(rpt, &buf); err != nil {
+		t.Fatal(err)
+	}
+	expectContains(t, buf.String(), `"refComparisons":[{"provider":"github","owner":"o","repository":"api","refs":["main","release/1.x"],`, "ref comparison")
+	expectContains(t, buf.String(), `"versions":{"main":"4.2.11","release/1.x":"3.2.25"},"differs":true`, "differing versions")
+}
+
+func TestRenderJSONEcosystems(t *testing.T) {
+	rpt := &report.Report{
+		Packages:          []string{"django", "https://github.com/psf/black"},
+		PackageEcosystems: map[string]dependencies.Ecosystem{"django": "python", "https://github.com/psf/black": "pre-commit"},
+		Repositories: []report.RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry", Ecosystem: "python", Dependencies: map[string]string{"django": "4.2.11"}},
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "pre-commit", Ecosystem: "pre-commit", Dependencies: map[string]string{"https://github.com/psf/black": "24.2.0"}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := renderJSON(rpt, &buf); err != nil {
+		t.Fatal(err)
+	}
+	expectContains(t, buf.String(), `"Analyzer":"poetry","Ecosystem":"python"`, "repository ecosystem")
+	expectContains(t, buf.String(), `"ecosystems":[{"ecosystem":"pre-commit","packages":["https://github.com/psf/black"],"repositories":["github:o/api@main"]},{"ecosystem":"python","packages":["django"],"repositories":["github:o/api@main"]}]`, "ecosystem groups")
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+// package-versions command flags
+type packageVersionsFlags struct {
+	outputFormat string
+	tags         []string
+	timeout      time.Duration
+	repoTimeout  time.Duration
+	jsonIndent   bool
+}
+
+var pkgVerFlags packageVersionsFlags
+
+// newPackageVersionsCmd creates the 'package-versions' subcommand.
+func newPackageVersionsCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "package-versions <config-file> [package...]",
+		Short: "List each version of the tracked packages and the repositories using it",
+		Long: strings.TrimSpace(`
+Analyze the configured repositories and print, for each package, every version
+in use (newest first) and the repositories locking it, followed by the
+repositories not locking it at all.
+
+Without packages, the packages tracked by the configuration are listed. Named
+packages are looked up in every selected repository, whether or not its
+configuration tracks them.
+
+Examples:
+  devdashboard package-versions repos.yaml
+  devdashboard package-versions repos.yaml requests urllib3
+  devdashboard package-versions repos.yaml requests --json | jq '.packages[0].versions'
+`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: runPackageVersions,
+	}
+
+	c.Flags().StringVarP(&pkgVerFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	c.Flags().StringSliceVar(&pkgVerFlags.tags, "tag", nil, "Only analyze repositories carrying any of these tags (repeatable or comma-separated)")
+	c.Flags().DurationVar(&pkgVerFlags.timeout, "timeout", 5*time.Minute, "Timeout for analyzing all repositories")
+	c.Flags().DurationVar(&pkgVerFlags.repoTimeout, "repo-timeout", 0, "Timeout for analyzing each repository (0 = limited only by --timeout)")
+	c.Flags().BoolVar(&pkgVerFlags.jsonIndent, "json-indent", false, "Pretty-print JSON output")
+
+	return c
+}
+
+// runPackageVersions analyzes the configured repositories and lists the
+// versions of each package.
+func runPackageVersions(cmd *cobra.Command, args []string) error {
+	configFile, packages := args[0], args[1:]
+	format := strings.ToLower(pkgVerFlags.outputFormat)
+	if format != "console" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", pkgVerFlags.outputFormat)
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	repos, err := selectRepos(cfg, pkgVerFlags.tags, nil)
+	if err != nil {
+		return err
+	}
+	if len(packages) > 0 {
+		for i := range repos {
+			repos[i].Config.Packages = packages
+		}
+	}
+
+	applyConfigTimeouts(cmd, cfg, &pkgVerFlags.timeout, &pkgVerFlags.repoTimeout)
+	generator, err := newConfiguredGenerator(cfg, pkgVerFlags.repoTimeout)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), pkgVerFlags.timeout)
+	defer cancel()
+	rpt, err := generator.Generate(ctx, repos)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil {
+			slog.Warn("Repository not analyzed", "repository", rr.Key(), "error", rr.Error)
+		}
+	}
+
+	out := packageVersionsOf(rpt)
+	if format == "json" {
+		return renderPackageVersionsJSON(out, os.Stdout)
+	}
+	return renderPackageVersions(out, os.Stdout)
+}
+
+// packageVersionsOutput is the JSON shape of package-versions
+type packageVersionsOutput struct {
+	Packages []packageVersionsEntry `json:"packages"`
+	// Errors maps repositories that could not be analyzed to their error
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// packageVersionsEntry lists the versions of one package
+type packageVersionsEntry struct {
+	Package  string                `json:"package"`
+	Latest   string                `json:"latest,omitempty"` // PackageVersions.Max
+	Versions []packageVersionUsers `json:"versions"`         // Newest first
+	// NotLocked lists the analyzed repositories not locking the package
+	NotLocked []string `json:"notLocked,omitempty"`
+}
+
+// packageVersionUsers is one version and the repositories (Report.RepoLabel)
+// locking it
+type packageVersionUsers struct {
+	Version      string   `json:"version"`
+	Repositories []string `json:"repositories"`
+}
+
+// packageVersionsOf collects the output from rpt's GetPackageVersions,
+// leaving failed repositories to Errors
+func packageVersionsOf(rpt *report.Report) packageVersionsOutput {
+	out := packageVersionsOutput{Packages: []packageVersionsEntry{}}
+	failed := make(map[string]bool)
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		if rr.Error == nil {
+			continue
+		}
+		failed[rpt.RepoLabel(rr)] = true
+		if out.Errors == nil {
+			out.Errors = make(map[string]string)
+		}
+		out.Errors[rr.Key()] = rr.Error.Error()
+	}
+	analyzed := func(labels []string) []string {
+		return slices.DeleteFunc(slices.Clone(labels), func(l string) bool { return failed[l] })
+	}
+
+	for _, pv := range rpt.GetPackageVersions() {
+		entry := packageVersionsEntry{Package: pv.PackageName, Latest: pv.Max, Versions: []packageVersionUsers{}}
+		var versions []string
+		for version := range pv.Versions {
+			if version != "" {
+				versions = append(versions, version)
+			}
+		}
+		versioning.Sort(rpt.PackageEcosystem(pv.PackageName), versions)
+		slices.Reverse(versions)
+		for _, version := range versions {
+			if users := analyzed(pv.Versions[version]); len(users) > 0 {
+				entry.Versions = append(entry.Versions, packageVersionUsers{Version: version, Repositories: users})
+			}
+		}
+		entry.NotLocked = analyzed(pv.Versions[""])
+		if len(entry.NotLocked) == 0 {
+			entry.NotLocked = nil
+		}
+		out.Packages = append(out.Packages, entry)
+	}
+	return out
+}
+
+// renderPackageVersions writes out as a table per package
+func renderPackageVersions(out packageVersionsOutput, w ioWriter) error {
+	if len(out.Packages) == 0 {
+		_, err := fmt.Fprintln(w, "No packages tracked")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, entry := range out.Packages {
+		if i > 0 {
+			_, _ = fmt.Fprintln(tw)
+		}
+		_, _ = fmt.Fprintf(tw, "%s (%d versions)\n", entry.Package, len(entry.Versions))
+		for _, v := range entry.Versions {
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\n", v.Version, strings.Join(v.Repositories, ", "))
+		}
+		if len(entry.NotLocked) > 0 {
+			_, _ = fmt.Fprintf(tw, "  not locked\t%s\n", strings.Join(entry.NotLocked, ", "))
+		}
+	}
+	return tw.Flush()
+}
+
+// renderPackageVersionsJSON writes out as JSON
+func renderPackageVersionsJSON(out packageVersionsOutput, w ioWriter) error {
+	var data []byte
+	var err error
+	if pkgVerFlags.jsonIndent {
+		data, err = json.MarshalIndent(out, "", "  ")
+	} else {
+		data, err = json.Marshal(out)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, _ = w.Write(data)
+	_, _ = w.Write([]byte("\n"))
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestPackageVersionsOf(t *testing.T) {
+	repo := func(name, version string) report.RepositoryReport {
+		rr := report.RepositoryReport{Provider: "github", Owner: "o", Repository: name, Ref: "main", Analyzer: "poetry", Dependencies: map[string]string{}}
+		if version != "" {
+			rr.Dependencies["requests"] = version
+		}
+		return rr
+	}
+	rpt := &report.Report{Packages: []string{"requests"}, Repositories: []report.RepositoryReport{
+		repo("api", "2.25.0"),
+		repo("web", "2.31.0"),
+		repo("cli", "2.25.0"),
+		repo("docs", ""),
+		repo("down", ""),
+	}}
+	rpt.Repositories[4].Error = report.NewErrorDetail(errors.New("boom"))
+
+	out := packageVersionsOf(rpt)
+	if len(out.Packages) != 1 || out.Errors["github:o/down@main"] != "boom" {
+		t.Fatalf("output = %+v", out)
+	}
+	entry := out.Packages[0]
+	want := []packageVersionUsers{
+		{Version: "2.31.0", Repositories: []string{"o/web"}},
+		{Version: "2.25.0", Repositories: []string{"o/api", "o/cli"}},
+	}
+	if entry.Latest != "2.31.0" || !slices.EqualFunc(entry.Versions, want, func(a, b packageVersionUsers) bool {
+		return a.Version == b.Version && slices.Equal(a.Repositories, b.Repositories)
+	}) || !slices.Equal(entry.NotLocked, []string{"o/docs"}) {
+		t.Errorf("requests = %+v", entry)
+	}
+
+	var buf bytes.Buffer
+	if err := renderPackageVersions(out, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[0] != "requests (2 versions)" || !strings.HasSuffix(lines[2], "o/api, o/cli") || !strings.HasSuffix(lines[3], "o/docs") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := renderPackageVersionsJSON(out, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded packageVersionsOutput
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded.Packages) != 1 || len(decoded.Packages[0].Versions) != 2 {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// preflightConcurrency bounds the repository metadata requests --preflight
+// sends at once
+const preflightConcurrency = 8
+
+// inaccessibleRepo is a repository --preflight could not read
+type inaccessibleRepo struct {
+	Repository string // provider:owner/repo
+	Reason     string
+}
+
+// runPreflight implements dependency-report --preflight: before the run it
+// reads each configured repository's metadata with the token the repository
+// resolves to, failing with the list of repositories that cannot be read
+// rather than reporting each of them as an error once the run is over.
+// Repositories configured at several refs are checked once.
+func runPreflight(ctx context.Context, cfg *config.Config, repos []config.RepoWithProvider, w io.Writer) error {
+	var requestTimeout time.Duration
+	if cfg.Timeouts != nil {
+		requestTimeout = cfg.Timeouts.Request
+	}
+
+	seen := make(map[string]bool)
+	var unique []config.RepoWithProvider
+	for _, repo := range repos {
+		rc := repo.Config
+		key := strings.Join([]string{repo.Provider, rc.BaseURL, rc.Token, rc.Owner, rc.Repository}, "\x00")
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, repo)
+		}
+	}
+
+	reasons := make([]string, len(unique))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, preflightConcurrency)
+	for i, repo := range unique {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			reasons[i] = checkRepoAccess(ctx, repo, requestTimeout)
+		}()
+	}
+	wg.Wait()
+
+	var failed []inaccessibleRepo
+	for i, repo := range unique {
+		if reasons[i] != "" {
+			failed = append(failed, inaccessibleRepo{
+				Repository: fmt.Sprintf("%s:%s/%s", repo.Provider, repo.Config.Owner, repo.Config.Repository),
+				Reason:     reasons[i],
+			})
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(w, "Preflight: %d of %d repositories cannot be read:\n", len(failed), len(unique))
+	for _, f := range failed {
+		_, _ = fmt.Fprintf(w, "  %s: %s\n", f.Repository, f.Reason)
+	}
+	return exitcode.Errorf(exitcode.ProviderError, "preflight failed: %d of %d repositories cannot be read", len(failed), len(unique))
+}
+
+// checkRepoAccess reads repo's metadata, returning why it cannot be read or
+// "" when it can
+func checkRepoAccess(ctx context.Context, repo config.RepoWithProvider, requestTimeout time.Duration) string {
+	client, err := newRepoClient(repo, requestTimeout)
+	if err != nil {
+		return err.Error()
+	}
+	_, err = client.GetRepositoryInfo(ctx, repo.Config.Owner, repo.Config.Repository)
+	switch repository.StatusCode(err) {
+	case 0:
+		if err != nil {
+			return err.Error()
+		}
+		return ""
+	case http.StatusUnauthorized:
+		return "token rejected (401)"
+	case http.StatusForbidden:
+		return "token lacks access (403)"
+	case http.StatusNotFound:
+		return "not found, or not visible to the token (404)"
+	default:
+		return err.Error()
+	}
+}
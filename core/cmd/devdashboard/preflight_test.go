@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+)
+
+// TestCLIPreflight lists the repositories a fake GitHub API refuses and
+// fails before analyzing any of them.
+func TestCLIPreflight(t *testing.T) {
+	var metadata, contents atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/org/api":
+			metadata.Add(1)
+			_, _ = w.Write([]byte(`{"name":"api","full_name":"org/api"}`))
+		case "/api/v3/repos/org/secret":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case "/api/v3/repos/org/locked":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"Resource not accessible by personal access token"}`))
+		default:
+			contents.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+providers:
+  github:
+    default:
+      baseURL: %s/
+      analyzer: poetry
+      owner: org
+    repositories:
+      - repository: api
+        refs: [main, release]
+      - repository: secret
+      - repository: locked
+`, srv.URL))
+
+	var stderr bytes.Buffer
+	root := newRootCmd()
+	root.SetErr(&stderr)
+	root.SetArgs([]string{"dependency-report", cfgPath, "--preflight", "--snapshot", "none", "--cache-dir", "none"})
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ProviderError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ProviderError, code, err)
+	}
+	out := stderr.String()
+	expectContains(t, out, "2 of 3 repositories cannot be read", "preflight summary")
+	expectContains(t, out, "github:org/secret: not found, or not visible to the token (404)", "missing repository")
+	expectContains(t, out, "github:org/locked: token lacks access (403)", "forbidden repository")
+	if strings.Contains(out, "org/api:") || metadata.Load() != 1 {
+		t.Errorf("org/api should be checked once and pass, checked %d times:\n%s", metadata.Load(), out)
+	}
+	if contents.Load() != 0 {
+		t.Errorf("expected no analysis after a failed preflight, got %d requests", contents.Load())
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
+	"golang.org/x/term"
+)
+
+// progressBarWidth is the number of cells in the TTY progress bar
+const progressBarWidth = 24
+
+// spinnerFrames animate the TTY progress line while repositories run
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// progressDisplay renders the services.ReportProgress events of a report run:
+// a redrawn bar with completed/total count, ETA and a spinner naming a running
+// repository on a terminal, or one log line per finished repository
+// otherwise.
+type progressDisplay struct {
+	w       io.Writer
+	tty     bool
+	total   int
+	done    int
+	failed  int
+	anon    int      // Repositories started without a token
+	running []string // Repositories started and not finished, in start order
+	start   time.Time
+	frame   int
+	now     func() time.Time
+}
+
+// newProgressDisplay creates a display for total repositories drawing on w
+// when tty is set
+func newProgressDisplay(w io.Writer, total int, tty bool) *progressDisplay {
+	return &progressDisplay{w: w, tty: tty, total: total, start: time.Now(), now: time.Now}
+}
+
+// isTerminal reports whether both stdout and stderr are terminals, so a
+// progress line on stderr does not end up in redirected output
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Run consumes events until ch is closed, redrawing the TTY line on every
+// event and periodically in between, then clears it
+func (p *progressDisplay) Run(ch <-chan services.ReportProgress) {
+	ticker := time.NewTicker(150 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				p.finish()
+				return
+			}
+			p.update(ev)
+		case <-ticker.C:
+			p.frame++
+		}
+		p.draw()
+	}
+}
+
+// update records one event
+func (p *progressDisplay) update(ev services.ReportProgress) {
+	if ev.RepoID == "" {
+		return
+	}
+	switch ev.Phase {
+	case services.PhaseRunning:
+		p.running = append(p.running, ev.RepoID)
+		if ev.Anonymous {
+			p.anon++
+		}
+	case services.PhaseComplete, services.PhaseError:
+		for i, id := range p.running {
+			if id == ev.RepoID {
+				p.running = append(p.running[:i], p.running[i+1:]...)
+				break
+			}
+		}
+		p.done++
+		if ev.Phase == services.PhaseError {
+			p.failed++
+		}
+		if !p.tty {
+			attrs := []any{"progress", fmt.Sprintf("%d/%d", p.done, p.total), "repository", ev.RepoID}
+			if eta, ok := p.eta(); ok {
+				attrs = append(attrs, "eta", eta)
+			}
+			if ev.Anonymous {
+				attrs = append(attrs, "anonymous", true)
+			}
+			if ev.Error != nil {
+				attrs = append(attrs, "error", ev.Error)
+			}
+			slog.Info("Repository analyzed", attrs...)
+		}
+	}
+}
+
+// eta estimates the time left from the average time per finished repository
+func (p *progressDisplay) eta() (time.Duration, bool) {
+	if p.done == 0 || p.done >= p.total {
+		return 0, false
+	}
+	elapsed := p.now().Sub(p.start)
+	return (elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)).Round(time.Second), true
+}
+
+// line formats the TTY progress line
+func (p *progressDisplay) line() string {
+	filled := 0
+	if p.total > 0 {
+		filled = p.done * progressBarWidth / p.total
+	}
+	var b strings.Builder
+	b.WriteString("[" + strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled) + "]")
+	fmt.Fprintf(&b, " %d/%d", p.done, p.total)
+	if p.failed > 0 {
+		fmt.Fprintf(&b, " (%d failed)", p.failed)
+	}
+	if p.anon > 0 {
+		fmt.Fprintf(&b, " [%d anonymous]", p.anon)
+	}
+	if eta, ok := p.eta(); ok {
+		fmt.Fprintf(&b, " ETA %s", eta)
+	}
+	if len(p.running) > 0 {
+		fmt.Fprintf(&b, " %s %s", spinnerFrames[p.frame%len(spinnerFrames)], p.running[0])
+		if len(p.running) > 1 {
+			fmt.Fprintf(&b, " +%d", len(p.running)-1)
+		}
+	}
+	return b.String()
+}
+
+// draw redraws the TTY line in place
+func (p *progressDisplay) draw() {
+	if p.tty {
+		_, _ = fmt.Fprintf(p.w, "\r\x1b[K%s", p.line())
+	}
+}
+
+// finish clears the TTY line so report output starts on a clean line
+func (p *progressDisplay) finish() {
+	if p.tty {
+		_, _ = fmt.Fprint(p.w, "\r\x1b[K")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
+)
+
+func TestProgressDisplay(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressDisplay(&buf, 4, true)
+	start := time.Now()
+	p.start = start
+	p.now = func() time.Time { return start.Add(20 * time.Second) }
+
+	for _, ev := range []services.ReportProgress{
+		{RepoID: "github:org/a@main", Phase: services.PhaseQueued},
+		{RepoID: "github:org/a@main", Phase: services.PhaseRunning},
+		{RepoID: "github:org/b@main", Phase: services.PhaseRunning},
+		{RepoID: "github:org/c@main", Phase: services.PhaseRunning, Anonymous: true},
+		{RepoID: "github:org/a@main", Phase: services.PhaseComplete},
+		{RepoID: "github:org/b@main", Phase: services.PhaseError, Error: errors.New("not found")},
+	} {
+		p.update(ev)
+	}
+
+	// Two of four done in 20s: 20s left
+	want := "[############------------] 2/4 (1 failed) [1 anonymous] ETA 20s ⠋ github:org/c@main"
+	if got := p.line(); got != want {
+		t.Errorf("line() = %q, want %q", got, want)
+	}
+
+	ch := make(chan services.ReportProgress)
+	close(ch)
+	p.Run(ch)
+	if !strings.HasSuffix(buf.String(), "\r\x1b[K") {
+		t.Errorf("progress line not cleared at the end: %q", buf.String())
+	}
+}
+
+func TestProgressDisplayPlain(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressDisplay(&buf, 1, false)
+	ch := make(chan services.ReportProgress, 2)
+	ch <- services.ReportProgress{RepoID: "github:org/a@main", Phase: services.PhaseRunning}
+	ch <- services.ReportProgress{RepoID: "github:org/a@main", Phase: services.PhaseComplete}
+	close(ch)
+	p.Run(ch)
+	if buf.Len() != 0 {
+		t.Errorf("non-terminal display drew on the writer: %q", buf.String())
+	}
+	if p.done != 1 {
+		t.Errorf("done = %d, want 1", p.done)
+	}
+}
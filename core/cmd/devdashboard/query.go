@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/report/query"
+	"github.com/spf13/cobra"
+)
+
+// query command flags
+type queryFlags struct {
+	configFile   string
+	snapshot     string
+	outputFormat string
+	failOnMatch  bool
+	jsonIndent   bool
+}
+
+var qryFlags queryFlags
+
+// newQueryCmd creates the 'query' subcommand.
+func newQueryCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "query <expression>",
+		Short: "Query the last dependency report",
+		Long: strings.TrimSpace(`
+Evaluate a query over the snapshot the last dependency-report run saved for a
+config (or over --snapshot). Nothing is analyzed.
+
+  repos(expr)     repositories with a dependency matching expr
+  packages(expr)  package versions matching expr, with their repositories
+
+Expressions compare fields with == != < <= > >= =~ (regular expression) and
+"in" (version range), joined by &&, || and !, with parentheses. Package and
+version comparisons joined by && refer to the same dependency, and versions
+compare in the repository's ecosystem ordering.
+
+Fields: package, version, constraint, repo (owner/repo), key
+(provider:owner/repo@ref), provider, owner, ref, analyzer, ecosystem, commit,
+tag, policy (a violated policy), error ("" when analyzed). Tags are read from
+--config.
+
+Examples:
+  devdashboard query 'repos(package == "requests" && version < "2.31")' -c repos.yaml
+  devdashboard query 'packages(package == django)' -c repos.yaml
+  devdashboard query 'repos(version in ">=4,<5" && tag == prod)' -c repos.yaml --format keys
+  devdashboard query 'repos(policy == no-eol-django)' -c repos.yaml --fail-on-match
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: runQuery,
+	}
+
+	c.Flags().StringVarP(&qryFlags.configFile, "config", "c", "", "Configuration file whose dependency-report snapshot is queried")
+	c.Flags().StringVar(&qryFlags.snapshot, "snapshot", "", "Snapshot file passed to dependency-report --snapshot (instead of the config's)")
+	c.Flags().StringVarP(&qryFlags.outputFormat, "format", "f", "console", "Output format: console|json|keys")
+	c.Flags().BoolVar(&qryFlags.failOnMatch, "fail-on-match", false, "Exit with the policy-violation code when anything matches")
+	c.Flags().BoolVar(&qryFlags.jsonIndent, "json-indent", false, "Pretty-print JSON output")
+
+	return c
+}
+
+// runQuery loads the snapshot and evaluates the query over it.
+func runQuery(cmd *cobra.Command, args []string) error {
+	outFormat := strings.ToLower(qryFlags.outputFormat)
+	if outFormat != "console" && outFormat != "json" && outFormat != "keys" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", qryFlags.outputFormat)
+	}
+	q, err := query.Parse(args[0])
+	if err != nil {
+		return exitcode.New(exitcode.ConfigError, err)
+	}
+
+	var cfg *config.Config
+	if qryFlags.configFile != "" {
+		if cfg, err = loadConfig(qryFlags.configFile); err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+	var snapshotPath string
+	switch {
+	case qryFlags.snapshot != "" && qryFlags.snapshot != "none":
+		snapshotPath = qryFlags.snapshot
+	case qryFlags.configFile != "":
+		snapshotPath = resolveSnapshotPath("", qryFlags.configFile)
+	}
+	if snapshotPath == "" {
+		return exitcode.New(exitcode.ConfigError, errors.New("no snapshot: pass --config or --snapshot"))
+	}
+	snap, err := report.LoadSnapshot(snapshotPath)
+	if err != nil {
+		return err
+	}
+	if snap == nil {
+		return exitcode.Errorf(exitcode.ConfigError, "no snapshot at %s: run dependency-report first", snapshotPath)
+	}
+
+	rpt := snap.Report()
+	if cfg != nil {
+		applyConfigTags(rpt, cfg.GetAllRepos())
+	}
+	res := q.Eval(rpt)
+	switch outFormat {
+	case "json":
+		err = renderQueryJSON(res, os.Stdout)
+	case "keys":
+		err = renderQueryKeys(res, os.Stdout)
+	default:
+		err = renderQuery(res, os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if qryFlags.failOnMatch && res.Len() > 0 {
+		return exitcode.Errorf(exitcode.PolicyViolation, "query matched %d results", res.Len())
+	}
+	return nil
+}
+
+// applyConfigTags sets the tags of rpt's repositories from their
+// configuration, which snapshots do not record
+func applyConfigTags(rpt *report.Report, repos []config.RepoWithProvider) {
+	tags := make(map[string][]string, len(repos))
+	for _, r := range repos {
+		rr := report.RepositoryReport{Provider: r.Provider, Owner: r.Config.Owner, Repository: r.Config.Repository, Ref: r.Config.Ref}
+		tags[rr.Key()] = r.Config.Tags
+	}
+	for i := range rpt.Repositories {
+		rpt.Repositories[i].Tags = tags[rpt.Repositories[i].Key()]
+	}
+}
+
+// renderQuery writes the matches of res as an aligned table
+func renderQuery(res query.Result, w ioWriter) error {
+	if res.Len() == 0 {
+		_, err := fmt.Fprintln(w, "No matches")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if res.Function == "packages" {
+		_, _ = fmt.Fprintln(tw, "PACKAGE\tVERSION\tREPOSITORIES")
+		for _, p := range res.Packages {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Package, p.Version, strings.Join(p.Repositories, ", "))
+		}
+		return tw.Flush()
+	}
+	_, _ = fmt.Fprintln(tw, "REPOSITORY\tMATCHED")
+	for _, m := range res.Repositories {
+		matched := make([]string, 0, len(m.Dependencies))
+		for _, d := range m.Dependencies {
+			matched = append(matched, d.Package+"=="+d.Version)
+		}
+		deps := strings.Join(matched, ", ")
+		if deps == "" {
+			deps = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\n", m.Repository, deps)
+	}
+	return tw.Flush()
+}
+
+// renderQueryKeys writes one line per match: repository keys for repos(),
+// package==version for packages()
+func renderQueryKeys(res query.Result, w ioWriter) error {
+	for _, m := range res.Repositories {
+		if _, err := fmt.Fprintln(w, m.Repository); err != nil {
+			return err
+		}
+	}
+	for _, p := range res.Packages {
+		if _, err := fmt.Fprintf(w, "%s==%s\n", p.Package, p.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderQueryJSON writes res as JSON
+func renderQueryJSON(res query.Result, w ioWriter) error {
+	var data []byte
+	var err error
+	if qryFlags.jsonIndent {
+		data, err = json.MarshalIndent(res, "", "  ")
+	} else {
+		data, err = json.Marshal(res)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, _ = w.Write(data)
+	_, _ = w.Write([]byte("\n"))
+	return nil
+}
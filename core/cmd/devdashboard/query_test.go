@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/report/query"
+)
+
+func queryFixture() *report.Report {
+	return &report.Report{
+		Repositories: []report.RepositoryReport{
+			{Provider: "github", Owner: "acme", Repository: "api", Ref: "main", Analyzer: "poetry", Ecosystem: "python",
+				Dependencies: map[string]string{"requests": "2.28.1"}},
+			{Provider: "github", Owner: "acme", Repository: "web", Ref: "main", Analyzer: "poetry", Ecosystem: "python",
+				Dependencies: map[string]string{"requests": "2.31.0"}},
+		},
+	}
+}
+
+func evalQuery(t *testing.T, src string, rpt *report.Report) query.Result {
+	t.Helper()
+	q, err := query.Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q.Eval(rpt)
+}
+
+func TestRenderQuery(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderQuery(evalQuery(t, `repos(package == requests && version < 2.31)`, queryFixture()), &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "github:acme/api@main") || !strings.HasSuffix(lines[1], "requests==2.28.1") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := renderQueryKeys(evalQuery(t, `packages(package == requests)`, queryFixture()), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "requests==2.28.1\nrequests==2.31.0\n" {
+		t.Errorf("keys = %q", got)
+	}
+
+	buf.Reset()
+	_ = renderQuery(evalQuery(t, `repos(package == flask)`, queryFixture()), &buf)
+	expectContains(t, buf.String(), "No matches", "empty result")
+}
+
+func TestRenderQueryJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderQueryJSON(evalQuery(t, `packages(version >= 2.31)`, queryFixture()), &buf); err != nil {
+		t.Fatal(err)
+	}
+	var out query.Result
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if out.Function != "packages" || len(out.Packages) != 1 || out.Packages[0].Repositories[0] != "github:acme/web@main" {
+		t.Errorf("unexpected JSON %+v", out)
+	}
+}
+
+func TestApplyConfigTags(t *testing.T) {
+	rpt := queryFixture()
+	applyConfigTags(rpt, []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "acme", Repository: "web", Ref: "main", Tags: []string{"prod"}}},
+	})
+	if rpt.Repositories[0].Tags != nil || len(rpt.Repositories[1].Tags) != 1 {
+		t.Errorf("tags = %v, %v", rpt.Repositories[0].Tags, rpt.Repositories[1].Tags)
+	}
+}
+
+func TestRunQuery_FailOnMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snap.json")
+	s := &report.Snapshot{Version: report.SnapshotVersion, GeneratedAt: time.Now(), Repositories: map[string]report.SnapshotEntry{
+		"github:acme/api@main": {CommitSHA: "a1", Analyzer: "poetry", Dependencies: map[string]string{"requests": "2.28.1"}},
+		"github:acme/web@main": {CommitSHA: "b2", Analyzer: "poetry", Dependencies: map[string]string{"requests": "2.31.0"}},
+	}}
+	if err := report.SaveSnapshot(path, s); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newQueryCmd() // Binding the flags resets them
+	qryFlags = queryFlags{snapshot: path, outputFormat: "keys", failOnMatch: true}
+	defer func() { qryFlags = queryFlags{} }()
+
+	err := runQuery(cmd, []string{`repos(version < 2.31)`})
+	if code := exitcode.FromError(err); code != exitcode.PolicyViolation {
+		t.Errorf("matching query: code = %v (%v), want policy-violation", code, err)
+	}
+	if err := runQuery(cmd, []string{`repos(version > 3)`}); err != nil {
+		t.Errorf("query without matches: %v", err)
+	}
+	err = runQuery(cmd, []string{`repos(version <)`})
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Errorf("invalid query: code = %v (%v), want config-error", code, err)
+	}
+}
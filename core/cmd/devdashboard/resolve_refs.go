@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// refResolution is the commit one repository's ref resolves to
+type refResolution struct {
+	Repository string `json:"repository"` // provider:owner/repo@ref
+	Ref        string `json:"ref"`
+	Commit     string `json:"commit,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// runResolveRefs implements dependency-report --resolve-refs: it prints the
+// commit each repository's ref resolves to instead of a report
+func runResolveRefs(ctx context.Context, cfg *config.Config, repos []config.RepoWithProvider) error {
+	format := strings.ToLower(depFlags.outputFormat)
+	if format != "console" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "--resolve-refs supports the console and json formats, not %s", depFlags.outputFormat)
+	}
+	var requestTimeout time.Duration
+	if cfg.Timeouts != nil {
+		requestTimeout = cfg.Timeouts.Request
+	}
+	ctx, cancel := context.WithTimeout(ctx, depFlags.timeout)
+	defer cancel()
+
+	resolutions := make([]refResolution, 0, len(repos))
+	failed := 0
+	for _, repo := range repos {
+		res := resolveRepoRef(ctx, repo, requestTimeout)
+		if res.Error != "" {
+			failed++
+		}
+		resolutions = append(resolutions, res)
+	}
+
+	w, err := openOutput(depFlags.outputFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = w.Close() }()
+	if format == "json" {
+		err = writeJSON(w, resolutions)
+	} else {
+		err = renderRefResolutions(resolutions, w)
+	}
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return exitcode.Errorf(exitcode.ProviderError, "failed to resolve %d of %d refs", failed, len(repos))
+	}
+	return nil
+}
+
+// resolveRepoRef resolves repo's ref (the default branch when empty) with its
+// provider
+func resolveRepoRef(ctx context.Context, repo config.RepoWithProvider, requestTimeout time.Duration) refResolution {
+	rc := repo.Config
+	res := refResolution{
+		Repository: fmt.Sprintf("%s:%s/%s@%s", repo.Provider, rc.Owner, rc.Repository, rc.Ref),
+		Ref:        rc.Ref,
+	}
+	client, err := newRepoClient(repo, requestTimeout)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	resolver, ok := client.(repository.CommitResolver)
+	if !ok {
+		res.Error = "provider cannot resolve commits"
+		return res
+	}
+	if res.Commit, err = resolver.ResolveCommit(ctx, rc.Owner, rc.Repository, rc.Ref); err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// renderRefResolutions writes the resolutions as an aligned table
+func renderRefResolutions(resolutions []refResolution, w ioWriter) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPOSITORY\tCOMMIT")
+	for _, r := range resolutions {
+		commit := r.Commit
+		if r.Error != "" {
+			commit = "ERROR: " + r.Error
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\n", r.Repository, commit)
+	}
+	return tw.Flush()
+}
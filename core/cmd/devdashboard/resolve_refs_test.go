@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+)
+
+// TestCLIResolveRefs resolves a branch and a pinned commit against a fake
+// GitHub API; an unknown ref fails with a provider error.
+func TestCLIResolveRefs(t *testing.T) {
+	const sha = "3f1c9e2a7b4d5e6f708192a3b4c5d6e7f8091a2b"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/org/api/commits/main", "/api/v3/repos/org/api/commits/3f1c9e2":
+			_, _ = w.Write([]byte(sha))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+retry:
+  maxAttempts: 1
+providers:
+  github:
+    default:
+      baseURL: %s/
+      analyzer: poetry
+      owner: org
+    repositories:
+      - repository: api
+        refs: [main, 3f1c9e2]
+`, srv.URL))
+
+	root := newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--resolve-refs", "--format", "json"})
+	out, err := executeCommand(root)
+	if err != nil {
+		t.Fatalf("resolve-refs failed: %v\n%s", err, out)
+	}
+	var res []refResolution
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(res) != 2 || res[0].Commit != sha || res[1].Commit != sha || res[1].Repository != "github:org/api@3f1c9e2" {
+		t.Errorf("unexpected resolutions %+v", res)
+	}
+
+	cfgPath = writeTempConfig(t, fmt.Sprintf(`
+retry:
+  maxAttempts: 1
+providers:
+  github:
+    repositories:
+      - owner: org
+        repository: api
+        ref: gone
+        analyzer: poetry
+        baseURL: %s/
+`, srv.URL))
+	root = newRootCmd()
+	root.SetArgs([]string{"dependency-report", cfgPath, "--resolve-refs"})
+	out, err = executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ProviderError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ProviderError, code, err)
+	}
+	expectContains(t, out, "github:org/api@gone  ERROR:", "failed resolution")
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/grpcapi"
+	"github.com/greg-hellings/devdashboard/core/pkg/issues"
+	"github.com/greg-hellings/devdashboard/core/pkg/notify"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
+	"github.com/greg-hellings/devdashboard/core/pkg/webhook"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+// serve command flags
+type serveFlags struct {
+	listen        string
+	grpcListen    string
+	githubSecret  string
+	gitlabSecret  string
+	timeout       time.Duration
+	repoTimeout   time.Duration
+	fullRefresh   time.Duration
+	pushQueueSize int
+}
+
+var srvFlags serveFlags
+
+// newServeCmd creates the 'serve' subcommand.
+func newServeCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "serve <config-file>",
+		Short: "Serve the latest dependency report and refresh it from push webhooks",
+		Long: strings.TrimSpace(`
+Run a long-lived server that generates the dependency report once and keeps it
+current. GitHub and GitLab push webhooks re-analyze only the repository (and
+branch) that changed and merge the result into the latest report.
+
+Endpoints:
+  GET  /report           - latest report (same JSON as --format json)
+  GET  /healthz          - 200 while the server runs (liveness)
+  GET  /readyz           - 200 once a report has been generated, 503 before
+  POST /webhooks/github  - GitHub push events (X-Hub-Signature-256 verified)
+  POST /webhooks/gitlab  - GitLab push hooks (X-Gitlab-Token verified)
+
+With --grpc-listen, the devdashboard.v1.DependencyService gRPC API is served
+on that address too: RunReport (streaming progress, then the report),
+GetLatestReport (the report kept current here) and ListRepositories. The
+service definition is core/pkg/grpcapi/devdashboard.proto.
+
+Webhook secrets default to DEV_DASHBOARD_GITHUB_WEBHOOK_SECRET and
+DEV_DASHBOARD_GITLAB_WEBHOOK_SECRET.
+
+Configured notifications are sent after every refresh that finds new drift,
+new failures or newly added packages, and configured issue trackers are
+synced with the refreshed report's policy violations.
+
+Examples:
+  devdashboard serve repos.yaml --listen :8080
+  devdashboard serve repos.yaml --full-refresh 6h
+  devdashboard serve repos.yaml --grpc-listen :9090
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: runServe,
+	}
+
+	c.Flags().StringVar(&srvFlags.listen, "listen", ":8080", "Address to listen on")
+	c.Flags().StringVar(&srvFlags.grpcListen, "grpc-listen", "", "Also serve the gRPC API on this address (empty disables)")
+	c.Flags().StringVar(&srvFlags.githubSecret, "github-webhook-secret", os.Getenv("DEV_DASHBOARD_GITHUB_WEBHOOK_SECRET"), "Shared secret for GitHub webhook signatures (empty disables verification)")
+	c.Flags().StringVar(&srvFlags.gitlabSecret, "gitlab-webhook-secret", os.Getenv("DEV_DASHBOARD_GITLAB_WEBHOOK_SECRET"), "Secret token for GitLab webhooks (empty disables verification)")
+	c.Flags().DurationVar(&srvFlags.timeout, "timeout", 5*time.Minute, "Timeout for each report run (full or webhook-triggered)")
+	c.Flags().DurationVar(&srvFlags.repoTimeout, "repo-timeout", 0, "Timeout for analyzing each repository (0 = limited only by --timeout)")
+	c.Flags().DurationVar(&srvFlags.fullRefresh, "full-refresh", 0, "Also re-run the whole report at this interval (0 = only on startup)")
+	c.Flags().IntVar(&srvFlags.pushQueueSize, "push-queue", 64, "Webhook events buffered while a refresh runs; extra events are dropped")
+
+	return c
+}
+
+// runServe loads the configuration and serves until interrupted.
+func runServe(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	repos := cfg.GetAllRepos()
+	if len(repos) == 0 {
+		return exitcode.New(exitcode.ConfigError, errors.New("no repositories configured in the provided file"))
+	}
+	if srvFlags.githubSecret == "" || srvFlags.gitlabSecret == "" {
+		slog.Warn("Webhook secret not set; deliveries for that provider are not verified",
+			"github", srvFlags.githubSecret != "", "gitlab", srvFlags.gitlabSecret != "")
+	}
+
+	applyConfigTimeouts(cmd, cfg, &srvFlags.timeout, &srvFlags.repoTimeout)
+	generator, err := newConfiguredGenerator(cfg, srvFlags.repoTimeout)
+	if err != nil {
+		return err
+	}
+	notifiers, err := notify.FromConfig(cfg.Notifications)
+	if err != nil {
+		return err
+	}
+	syncers, err := issues.FromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := newReportServer(generator, repos, srvFlags.timeout, srvFlags.pushQueueSize)
+	srv.notifiers = notifiers
+	srv.syncers = syncers
+	go srv.run(ctx, srvFlags.fullRefresh)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/report", srv.serveReport)
+	mux.HandleFunc("/healthz", serveHealthz)
+	mux.HandleFunc("/readyz", srv.serveReadyz)
+	webhook.NewHandler(mux, webhook.Secrets{GitHub: srvFlags.githubSecret, GitLab: srvFlags.gitlabSecret}, srv.enqueue)
+
+	httpServer := &http.Server{Addr: srvFlags.listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 2)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+	slog.Info("Serving dependency report", "listen", srvFlags.listen, "repositories", len(repos))
+	if srvFlags.grpcListen != "" {
+		grpcServer, err := newGRPCServer(cfg, repos, srv)
+		if err != nil {
+			return err
+		}
+		lis, err := net.Listen("tcp", srvFlags.grpcListen)
+		if err != nil {
+			return fmt.Errorf("gRPC server failed: %w", err)
+		}
+		go func() { errCh <- grpcServer.Serve(lis) }()
+		defer grpcServer.Stop()
+		slog.Info("Serving gRPC API", "listen", srvFlags.grpcListen)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// newGRPCServer creates the gRPC API of serve: runs use their own generator,
+// so they never wait for (or disturb) the refresh loop, and GetLatestReport
+// returns srv's latest report.
+func newGRPCServer(cfg *config.Config, repos []config.RepoWithProvider, srv *reportServer) (*grpc.Server, error) {
+	generator, err := newConfiguredGenerator(cfg, srvFlags.repoTimeout)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := report.PoliciesFromConfig(cfg.Policies)
+	if err != nil {
+		return nil, err
+	}
+	ignores, err := report.IgnoreRulesFromConfig(cfg.Ignores)
+	if err != nil {
+		return nil, err
+	}
+	opts := grpcapi.Options{
+		Report: services.ReportOptions{
+			Policies:          policies,
+			IgnoreRules:       ignores,
+			HTTPTracer:        httpTracer,
+			Fixtures:          httpFixtures,
+			RepositoryTimeout: srvFlags.repoTimeout,
+		},
+		Timeout: srvFlags.timeout,
+		Latest:  srv.latest,
+	}
+	if cfg.Timeouts != nil {
+		opts.Report.RequestTimeout = cfg.Timeouts.Request
+	}
+	gs := grpc.NewServer()
+	grpcapi.NewServer(services.NewDependencyService(generator), repos, opts).Register(gs)
+	return gs, nil
+}
+
+// reportServer keeps the latest report and refreshes it one run at a time:
+// a full run on startup (and every fullRefresh) and a partial Regenerate for
+// each push webhook.
+type reportServer struct {
+	gen     *report.Generator
+	repos   []config.RepoWithProvider
+	timeout time.Duration
+	pushes  chan webhook.PushEvent
+
+	// notifiers are told what changed after each refresh
+	notifiers []*notify.Notifier
+	// syncers file and close tickets for policy violations after each refresh
+	syncers []*issues.Syncer
+
+	mu        sync.RWMutex
+	current   *report.Report
+	updatedAt time.Time
+	lastErr   error // Of the last refresh, nil when it succeeded
+}
+
+func newReportServer(gen *report.Generator, repos []config.RepoWithProvider, timeout time.Duration, queue int) *reportServer {
+	if queue < 1 {
+		queue = 1
+	}
+	return &reportServer{gen: gen, repos: repos, timeout: timeout, pushes: make(chan webhook.PushEvent, queue)}
+}
+
+// enqueue schedules a push for the refresh loop without blocking the webhook
+func (s *reportServer) enqueue(ev webhook.PushEvent) {
+	select {
+	case s.pushes <- ev:
+	default:
+		slog.Warn("Webhook queue full; dropping push", "repository", ev.FullName, "branch", ev.Branch)
+	}
+}
+
+// run performs the initial report and then serializes refreshes until ctx ends
+func (s *reportServer) run(ctx context.Context, fullRefresh time.Duration) {
+	s.refresh(ctx, nil)
+	var tick <-chan time.Time
+	if fullRefresh > 0 {
+		ticker := time.NewTicker(fullRefresh)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			s.refresh(ctx, nil)
+		case ev := <-s.pushes:
+			s.refresh(ctx, &ev)
+		}
+	}
+}
+
+// refresh re-runs the whole report (ev nil) or just the repositories ev matches
+func (s *reportServer) refresh(ctx context.Context, ev *webhook.PushEvent) {
+	only := func(config.RepoWithProvider) bool { return true }
+	if ev != nil {
+		matched := 0
+		for _, r := range s.repos {
+			if ev.Matches(r) {
+				matched++
+			}
+		}
+		if matched == 0 {
+			slog.Info("Push does not match a configured repository", "provider", ev.Provider, "repository", ev.FullName, "branch", ev.Branch)
+			return
+		}
+		only = ev.Matches
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	start := time.Now()
+
+	s.mu.RLock()
+	base := s.current
+	s.mu.RUnlock()
+	if base != nil {
+		// Repositories whose commit did not move are not fetched again
+		s.gen.SetPrevious(base.Snapshot())
+	}
+	if ev == nil {
+		base = nil
+	}
+	rpt, err := s.gen.Regenerate(runCtx, base, s.repos, only)
+	if err != nil {
+		slog.Error("Report refresh failed", "error", err)
+		s.mu.Lock()
+		s.lastErr = err
+		s.mu.Unlock()
+		return
+	}
+
+	s.mu.Lock()
+	prev := s.current
+	s.current = rpt
+	s.updatedAt = time.Now().UTC()
+	s.lastErr = nil
+	s.mu.Unlock()
+	notify.NotifyAll(ctx, s.notifiers, prev, rpt)
+	issues.SyncAll(ctx, s.syncers, rpt)
+	slog.Info("Report refreshed", "partial", ev != nil, "repositories", len(rpt.Repositories), "duration", time.Since(start).String())
+}
+
+// latest returns the latest report, nil before the first run
+func (s *reportServer) latest() *report.Report {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// serveReport writes the latest report as JSON, or 503 before the first run
+func (s *reportServer) serveReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.RLock()
+	rpt, updatedAt := s.current, s.updatedAt
+	s.mu.RUnlock()
+	if rpt == nil {
+		http.Error(w, "report not generated yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := renderJSON(rpt, &buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Last-Modified", updatedAt.Format(http.TimeFormat))
+	_, _ = w.Write(buf.Bytes())
+}
+
+// readiness is the JSON body of /readyz
+type readiness struct {
+	Ready     bool       `json:"ready"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"` // Of the report served
+	// LastError is the error of the last refresh; the previous report is
+	// still served, so it does not make the server unready
+	LastError string `json:"lastError,omitempty"`
+}
+
+// serveHealthz answers liveness probes: the server is up when it responds
+func serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// serveReadyz answers readiness probes: 200 once there is a report to serve,
+// 503 before the first run completes
+func (s *reportServer) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	out := readiness{Ready: s.current != nil}
+	if out.Ready {
+		updatedAt := s.updatedAt
+		out.UpdatedAt = &updatedAt
+	}
+	if s.lastErr != nil {
+		out.LastError = s.lastErr.Error()
+	}
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !out.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = writeJSON(w, out)
+}
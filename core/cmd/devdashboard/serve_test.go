@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/webhook"
+)
+
+// TestServeReport verifies the /report endpoint before and after a report exists.
+func TestServeReport(t *testing.T) {
+	repos := []config.RepoWithProvider{{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Ref: "main"}}}
+	srv := newReportServer(report.NewGenerator(), repos, time.Minute, 1)
+
+	rec := httptest.NewRecorder()
+	srv.serveReport(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("before first run: status %d", rec.Code)
+	}
+
+	srv.current = &report.Report{
+		Repositories: []report.RepositoryReport{{Provider: "github", Owner: "o", Repository: "r", Ref: "main", Dependencies: map[string]string{"django": "4.2"}}},
+		Packages:     []string{"django"},
+	}
+	rec = httptest.NewRecorder()
+	srv.serveReport(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	var parsed report.JSONDocument
+	if err := json.Unmarshal(rec.Body.Bytes(), &parsed); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status %d, decode error %v: %s", rec.Code, err, rec.Body.String())
+	}
+	if parsed.Summary.RepositoryCount != 1 || parsed.Packages[0] != "django" {
+		t.Errorf("unexpected payload: %+v", parsed)
+	}
+}
+
+// TestServeUnmatchedPush verifies pushes for unconfigured repositories leave the report alone.
+func TestServeUnmatchedPush(t *testing.T) {
+	repos := []config.RepoWithProvider{{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Ref: "main"}}}
+	srv := newReportServer(report.NewGenerator(), repos, time.Minute, 1)
+	base := &report.Report{}
+	srv.current = base
+
+	srv.refresh(context.Background(), &webhook.PushEvent{Provider: "github", FullName: "o/other", Branch: "main"})
+	if srv.current != base {
+		t.Error("unmatched push should not replace the report")
+	}
+
+	srv.enqueue(webhook.PushEvent{FullName: "a"})
+	srv.enqueue(webhook.PushEvent{FullName: "b"}) // queue of one: dropped, must not block
+	if len(srv.pushes) != 1 {
+		t.Errorf("queued pushes = %d, want 1", len(srv.pushes))
+	}
+}
+
+// TestServeProbes verifies /healthz always succeeds and /readyz waits for
+// the first report.
+func TestServeProbes(t *testing.T) {
+	srv := newReportServer(report.NewGenerator(), nil, time.Minute, 1)
+
+	rec := httptest.NewRecorder()
+	serveHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok\n" {
+		t.Errorf("healthz: status %d body %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.serveReadyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("readyz before first run: status %d", rec.Code)
+	}
+
+	srv.current = &report.Report{}
+	srv.updatedAt = time.Now().UTC()
+	srv.lastErr = context.DeadlineExceeded
+	rec = httptest.NewRecorder()
+	srv.serveReadyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body readiness
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("readyz: status %d, decode error %v: %s", rec.Code, err, rec.Body.String())
+	}
+	if !body.Ready || body.UpdatedAt == nil || body.LastError != context.DeadlineExceeded.Error() {
+		t.Errorf("unexpected readiness %+v", body)
+	}
+}
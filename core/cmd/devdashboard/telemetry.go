@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+)
+
+// telemetryShutdownTimeout bounds flushing spans and metrics on exit
+const telemetryShutdownTimeout = 10 * time.Second
+
+// telemetryShutdown flushes and stops the OpenTelemetry exporters; nil until
+// initTelemetry installed them
+var telemetryShutdown func(context.Context) error
+
+// initTelemetry installs OTLP exporters when the configuration has a
+// telemetry section or the environment names a collector. Later calls (serve
+// builds several generators) keep the first setup.
+func initTelemetry(cfg *config.Config) error {
+	if telemetryShutdown != nil || (cfg.Telemetry == nil && !telemetry.EnvConfigured()) {
+		return nil
+	}
+	tc := telemetry.Config{ServiceVersion: version}
+	if t := cfg.Telemetry; t != nil {
+		tc.Endpoint = t.Endpoint
+		tc.Headers = t.Headers
+		tc.ServiceName = t.ServiceName
+		tc.MetricsInterval = t.MetricsInterval
+	}
+	shutdown, err := telemetry.Setup(context.Background(), tc)
+	if err != nil {
+		return exitcode.New(exitcode.ConfigError, err)
+	}
+	telemetryShutdown = shutdown
+	slog.Debug("OpenTelemetry export enabled", "endpoint", tc.Endpoint)
+	return nil
+}
+
+// shutdownTelemetry flushes the spans and metrics recorded during the
+// command, if telemetry was enabled
+func shutdownTelemetry() error {
+	if telemetryShutdown == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+	defer cancel()
+	err := telemetryShutdown(ctx)
+	telemetryShutdown = nil
+	if err != nil {
+		return fmt.Errorf("failed to export telemetry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// httpTracer records provider API requests when --trace-http or
+// --trace-http-file is set; nil otherwise
+var httpTracer *repository.HTTPTracer
+
+// initHTTPTrace creates httpTracer from the global flags. --trace-http logs
+// each request on stderr regardless of the log level.
+func initHTTPTrace() {
+	httpTracer = nil
+	if !flagTraceHTTP && flagTraceHTTPFile == "" {
+		return
+	}
+	var logger *slog.Logger
+	if flagTraceHTTP {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+	httpTracer = repository.NewHTTPTracer(logger)
+}
+
+// httpFixtures records or replays provider API responses when
+// --record-fixtures or --replay-fixtures is set; nil otherwise
+var httpFixtures *repository.Fixtures
+
+// initHTTPFixtures creates httpFixtures from the global flags
+func initHTTPFixtures() error {
+	httpFixtures = nil
+	var err error
+	switch {
+	case flagRecordFixtures != "" && flagReplayFixtures != "":
+		return exitcode.Errorf(exitcode.ConfigError, "--record-fixtures and --replay-fixtures are mutually exclusive")
+	case flagRecordFixtures != "":
+		httpFixtures, err = repository.RecordFixtures(filepath.Clean(flagRecordFixtures))
+		slog.Info("Recording provider API responses", "dir", flagRecordFixtures)
+	case flagReplayFixtures != "":
+		httpFixtures, err = repository.ReplayFixtures(filepath.Clean(flagReplayFixtures))
+		slog.Info("Replaying recorded provider API responses", "dir", flagReplayFixtures)
+	}
+	if err != nil {
+		return exitcode.New(exitcode.ConfigError, err)
+	}
+	return nil
+}
+
+// writeHTTPTrace writes the requests recorded during the command to the
+// --trace-http-file file, if requested
+func writeHTTPTrace() error {
+	if httpTracer == nil || flagTraceHTTPFile == "" {
+		return nil
+	}
+	f, err := os.Create(filepath.Clean(flagTraceHTTPFile))
+	if err != nil {
+		return fmt.Errorf("failed to write HTTP trace: %w", err)
+	}
+	if err := httpTracer.WriteHAR(f, version); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write HTTP trace: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write HTTP trace: %w", err)
+	}
+	slog.Info("HTTP trace written", "file", flagTraceHTTPFile, "requests", len(httpTracer.Entries()))
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/report/format"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+// trend command flags
+type trendFlags struct {
+	configFile   string
+	snapshot     string
+	versionRange string
+	last         int
+	outputFormat string
+	jsonIndent   bool
+}
+
+var trdFlags trendFlags
+
+// newTrendCmd creates the 'trend' subcommand.
+func newTrendCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "trend <package>",
+		Short: "Show how a package's versions evolved across previous reports",
+		Long: strings.TrimSpace(`
+Show how the locked versions of a package evolved across the snapshots that
+dependency-report archives after each run (see --history-size). Nothing is
+analyzed: the history of the config's snapshot (or of --snapshot) is read.
+
+By default every version seen is listed with a sparkline of the repositories
+locking it, oldest run first, scaled to the most repositories locking the
+package in any run. With --version, a single sparkline shows the share of
+those repositories locking a version in the range: an adoption curve.
+
+Examples:
+  devdashboard trend django --config repos.yaml
+  devdashboard trend django --config repos.yaml --version ">=4.2,<4.3"
+  devdashboard trend requests --snapshot ./snap.json --last 30 --format json
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: runTrend,
+	}
+
+	c.Flags().StringVarP(&trdFlags.configFile, "config", "c", "", "Configuration file whose dependency-report snapshot history is read")
+	c.Flags().StringVar(&trdFlags.snapshot, "snapshot", "", "Snapshot file passed to dependency-report --snapshot (instead of --config)")
+	c.Flags().StringVar(&trdFlags.versionRange, "version", "", "Show the share of repositories locking a version in this range (e.g. \">=4.2,<4.3\")")
+	c.Flags().IntVar(&trdFlags.last, "last", 0, "Only use the newest N snapshots (0 = all)")
+	c.Flags().StringVarP(&trdFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	c.Flags().BoolVar(&trdFlags.jsonIndent, "json-indent", false, "Pretty-print JSON output")
+
+	return c
+}
+
+// runTrend loads the snapshot history and renders the package's trend.
+func runTrend(cmd *cobra.Command, args []string) error {
+	pkg := args[0]
+	outFormat := strings.ToLower(trdFlags.outputFormat)
+	if outFormat != "console" && outFormat != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", trdFlags.outputFormat)
+	}
+	var snapshotPath string
+	switch {
+	case trdFlags.snapshot != "" && trdFlags.snapshot != "none":
+		snapshotPath = trdFlags.snapshot
+	case trdFlags.configFile != "":
+		snapshotPath = resolveSnapshotPath("", trdFlags.configFile)
+	}
+	if snapshotPath == "" {
+		return exitcode.New(exitcode.ConfigError, errors.New("no snapshot history: pass --config or --snapshot"))
+	}
+
+	dir := report.HistoryDir(snapshotPath)
+	history, err := report.LoadSnapshotHistory(dir)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return exitcode.Errorf(exitcode.ConfigError, "no archived snapshots in %s: run dependency-report first", dir)
+	}
+	if trdFlags.last > 0 && len(history) > trdFlags.last {
+		history = history[len(history)-trdFlags.last:]
+	}
+
+	t := report.Trend(history, pkg)
+	var rng *versioning.Range
+	if trdFlags.versionRange != "" {
+		if rng, err = versioning.ParseRange(t.Ecosystem, trdFlags.versionRange); err != nil {
+			return exitcode.New(exitcode.ConfigError, err)
+		}
+	}
+	if outFormat == "json" {
+		return renderTrendJSON(t, rng, os.Stdout)
+	}
+	return renderTrend(t, rng, os.Stdout)
+}
+
+// renderTrend writes one sparkline per version of t, or the adoption curve
+// of rng when it is set
+func renderTrend(t report.PackageTrend, rng *versioning.Range, w ioWriter) error {
+	if len(t.Versions) == 0 {
+		_, err := fmt.Fprintf(w, "No repositories locked %s in %d snapshots\n", t.Package, len(t.Points))
+		return err
+	}
+	first, last := t.Points[0], t.Points[len(t.Points)-1]
+	_, _ = fmt.Fprintf(w, "%s: %d snapshots from %s to %s\n\n", t.Package, len(t.Points),
+		first.Time.Local().Format("2006-01-02 15:04"), last.Time.Local().Format("2006-01-02 15:04"))
+
+	if rng != nil {
+		shares := t.Adoption(rng)
+		_, err := fmt.Fprintf(w, "%s  %s  %s of %d repositories (was %s)\n", trdFlags.versionRange,
+			format.Sparkline(shares, 1), percent(shares[len(shares)-1]), last.Repositories, percent(shares[0]))
+		return err
+	}
+
+	ceiling := 0
+	for _, p := range t.Points {
+		ceiling = max(ceiling, p.Repositories)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "VERSION\tTREND\tNOW")
+	for i := len(t.Versions) - 1; i >= 0; i-- {
+		counts := t.Counts(t.Versions[i])
+		values := make([]float64, len(counts))
+		for j, n := range counts {
+			values[j] = float64(n)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\n", t.Versions[i], format.Sparkline(values, float64(ceiling)), counts[len(counts)-1])
+	}
+	return tw.Flush()
+}
+
+// percent formats a 0 to 1 share as a whole percentage
+func percent(share float64) string {
+	return fmt.Sprintf("%.0f%%", share*100)
+}
+
+// trendOutput is the JSON shape of trend
+type trendOutput struct {
+	report.PackageTrend
+	VersionRange string    `json:"versionRange,omitempty"`
+	Adoption     []float64 `json:"adoption,omitempty"` // Per point, with VersionRange
+}
+
+// renderTrendJSON writes t, with the adoption curve of rng when it is set
+func renderTrendJSON(t report.PackageTrend, rng *versioning.Range, w ioWriter) error {
+	payload := trendOutput{PackageTrend: t}
+	if rng != nil {
+		payload.VersionRange = trdFlags.versionRange
+		payload.Adoption = t.Adoption(rng)
+	}
+
+	var data []byte
+	var err error
+	if trdFlags.jsonIndent {
+		data, err = json.MarshalIndent(payload, "", "  ")
+	} else {
+		data, err = json.Marshal(payload)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, _ = w.Write(data)
+	_, _ = w.Write([]byte("\n"))
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+func trendFixture() report.PackageTrend {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	return report.PackageTrend{
+		Package:  "django",
+		Versions: []string{"3.2.25", "4.2.0"},
+		Points: []report.TrendPoint{
+			{Time: day(1), Repositories: 4, Versions: map[string]int{"3.2.25": 4}},
+			{Time: day(2), Repositories: 4, Versions: map[string]int{"3.2.25": 2, "4.2.0": 2}},
+			{Time: day(3), Repositories: 4, Versions: map[string]int{"4.2.0": 4}},
+		},
+	}
+}
+
+func TestRenderTrend(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderTrend(trendFixture(), nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "django: 3 snapshots") {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	// Newest version first, scaled to the 4 repositories locking django
+	if !strings.HasPrefix(lines[3], "4.2.0") || !strings.Contains(lines[3], "▁▅█") || !strings.HasSuffix(lines[3], "4") {
+		t.Errorf("unexpected 4.2.0 row %q", lines[3])
+	}
+	if !strings.Contains(lines[4], "█▅▁") || !strings.HasSuffix(lines[4], "0") {
+		t.Errorf("unexpected 3.2.25 row %q", lines[4])
+	}
+
+	buf.Reset()
+	_ = renderTrend(report.PackageTrend{Package: "flask", Points: make([]report.TrendPoint, 2)}, nil, &buf)
+	expectContains(t, buf.String(), "No repositories locked flask in 2 snapshots", "empty trend")
+}
+
+func TestRenderTrendAdoption(t *testing.T) {
+	trdFlags = trendFlags{versionRange: ">=4.2,<4.3"}
+	defer func() { trdFlags = trendFlags{} }()
+	rng, err := versioning.ParseRange("", trdFlags.versionRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := renderTrend(trendFixture(), rng, &buf); err != nil {
+		t.Fatal(err)
+	}
+	expectContains(t, buf.String(), ">=4.2,<4.3  ▁▅█  100% of 4 repositories (was 0%)", "adoption line")
+
+	buf.Reset()
+	if err := renderTrendJSON(trendFixture(), rng, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var out trendOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if out.Package != "django" || out.VersionRange != ">=4.2,<4.3" || len(out.Adoption) != 3 || out.Adoption[1] != 0.5 {
+		t.Errorf("unexpected JSON %+v", out)
+	}
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	consolefmt "github.com/greg-hellings/devdashboard/core/pkg/report/format"
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
+	"github.com/spf13/cobra"
+)
+
+// tui command flags
+type tuiFlags struct {
+	configFile    string
+	tags          []string
+	packageGroups []string
+	columns       []string
+	noColor       bool
+	timeout       time.Duration
+	repoTimeout   time.Duration
+}
+
+var tuiOpts tuiFlags
+
+// newTUICmd creates the 'tui' subcommand.
+func newTUICmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "tui [config-file]",
+		Short: "Run the report in a read-only terminal dashboard",
+		Long: strings.TrimSpace(`
+Run the dependency report in a full-screen terminal dashboard, for use over
+SSH where neither the GUI nor a browser is available. Nothing is changed:
+the dashboard only displays the report.
+
+Tabs:
+  Matrix    the dependency matrix and summary (same as --format console)
+  Errors    repositories that failed to analyze, as they fail
+  Progress  the run's progress events
+
+Keys:
+  tab, 1-3        switch tabs
+  arrows, hjkl    scroll (pgup/pgdn, home/end)
+  r               re-run the report (unchanged commits are reused)
+  q, ctrl+c       quit
+
+Examples:
+  devdashboard tui repos.yaml
+  devdashboard tui --config repos.yaml --tag prod --columns django,requests
+`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: runTUI,
+	}
+
+	c.Flags().StringVarP(&tuiOpts.configFile, "config", "c", "", "Configuration file (alternative to the positional argument)")
+	c.Flags().StringSliceVar(&tuiOpts.tags, "tag", nil, "Only report repositories carrying any of these tags (repeatable or comma-separated)")
+	c.Flags().StringSliceVar(&tuiOpts.packageGroups, "packages-group", nil, "Only report packages in these named packageGroups (repeatable or comma-separated)")
+	c.Flags().StringSliceVar(&tuiOpts.columns, "columns", nil, "Package columns to show, in order (repeatable or comma-separated)")
+	c.Flags().BoolVar(&tuiOpts.noColor, "no-color", false, "Disable colors in the matrix")
+	c.Flags().DurationVar(&tuiOpts.timeout, "timeout", 5*time.Minute, "Timeout for each report run")
+	c.Flags().DurationVar(&tuiOpts.repoTimeout, "repo-timeout", 0, "Timeout for analyzing each repository (0 = limited only by --timeout)")
+
+	return c
+}
+
+// runTUI loads the configuration and runs the dashboard until quit.
+func runTUI(cmd *cobra.Command, args []string) error {
+	configFile := tuiOpts.configFile
+	switch {
+	case len(args) == 1 && configFile != "" && args[0] != configFile:
+		return exitcode.New(exitcode.ConfigError, errors.New("config file given both as argument and --config"))
+	case len(args) == 1:
+		configFile = args[0]
+	case configFile == "":
+		return exitcode.New(exitcode.ConfigError, errors.New("no config file: pass it as an argument or with --config"))
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	repos, err := selectRepos(cfg, tuiOpts.tags, tuiOpts.packageGroups)
+	if err != nil {
+		return err
+	}
+	applyConfigTimeouts(cmd, cfg, &tuiOpts.timeout, &tuiOpts.repoTimeout)
+	generator, err := newConfiguredGenerator(cfg, tuiOpts.repoTimeout)
+	if err != nil {
+		return err
+	}
+	policies, err := report.PoliciesFromConfig(cfg.Policies)
+	if err != nil {
+		return err
+	}
+	ignores, err := report.IgnoreRulesFromConfig(cfg.Ignores)
+	if err != nil {
+		return err
+	}
+	opts := services.ReportOptions{
+		Policies:          policies,
+		IgnoreRules:       ignores,
+		HTTPTracer:        httpTracer,
+		Fixtures:          httpFixtures,
+		RepositoryTimeout: tuiOpts.repoTimeout,
+	}
+	if cfg.Timeouts != nil {
+		opts.RequestTimeout = cfg.Timeouts.Request
+	}
+
+	// Log lines would tear through the full-screen display
+	prevLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer slog.SetDefault(prevLogger)
+
+	svc := services.NewDependencyService(generator)
+	start := func(ctx context.Context, prev *report.Snapshot) (<-chan services.ReportProgress, *services.ResultHandle, error) {
+		runOpts := opts
+		runOpts.Previous = prev
+		return svc.RunReport(ctx, repos, runOpts)
+	}
+	m := newTUIModel(configFile, len(repos), start)
+	m.colors = !tuiOpts.noColor && os.Getenv("NO_COLOR") == ""
+	m.columns = tuiOpts.columns
+	m.timeout = tuiOpts.timeout
+
+	final, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(cmd.Context())).Run()
+	if fm, ok := final.(*tuiModel); ok && fm.cancel != nil {
+		fm.cancel()
+	}
+	if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return fmt.Errorf("terminal UI failed: %w", err)
+	}
+	return nil
+}
+
+// tuiTab is a tab of the dashboard
+type tuiTab int
+
+const (
+	tabMatrix tuiTab = iota
+	tabErrors
+	tabProgress
+)
+
+// tuiTabNames are the tab titles, by tuiTab
+var tuiTabNames = []string{"Matrix", "Errors", "Progress"}
+
+// tuiStartFunc starts a report run, reusing prev's unchanged commits
+type tuiStartFunc func(ctx context.Context, prev *report.Snapshot) (<-chan services.ReportProgress, *services.ResultHandle, error)
+
+// Messages of the dashboard's report runs
+type (
+	tuiProgressMsg struct {
+		run int
+		ev  services.ReportProgress
+	}
+	tuiProgressDoneMsg struct{ run int }
+	tuiReportMsg       struct {
+		run int
+		rpt *report.Report
+		err error
+	}
+	tuiTickMsg struct{}
+)
+
+// tuiModel is the bubbletea model of the tui command. Each run is numbered
+// so messages of a run replaced by "r" are dropped.
+type tuiModel struct {
+	configFile string
+	total      int
+	start      tuiStartFunc
+	colors     bool
+	columns    []string
+	timeout    time.Duration
+
+	run      int
+	running  bool
+	cancel   context.CancelFunc
+	progress *progressDisplay
+	ch       <-chan services.ReportProgress
+	handle   *services.ResultHandle
+	events   []string          // Progress tab lines
+	failures map[string]string // Repository key to error, as reported by the run
+
+	report     *report.Report
+	err        error // Run failure (not repository errors)
+	finishedAt time.Time
+
+	tab      tuiTab
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// newTUIModel creates a dashboard for total repositories, running reports
+// with start
+func newTUIModel(configFile string, total int, start tuiStartFunc) *tuiModel {
+	return &tuiModel{configFile: configFile, total: total, start: start, viewport: viewport.New(80, 20)}
+}
+
+// Init starts the first run
+func (m *tuiModel) Init() tea.Cmd {
+	return tea.Batch(m.startRun(), tuiTick())
+}
+
+// tuiTick animates the progress spinner
+func tuiTick() tea.Cmd {
+	return tea.Tick(150*time.Millisecond, func(time.Time) tea.Msg { return tuiTickMsg{} })
+}
+
+// startRun starts a report run, reusing the previous report's commits
+func (m *tuiModel) startRun() tea.Cmd {
+	var prev *report.Snapshot
+	if m.report != nil {
+		prev = m.report.Snapshot()
+	}
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if m.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+	}
+	ch, handle, err := m.start(ctx, prev)
+	if err != nil {
+		cancel()
+		m.err = err
+		m.refreshContent()
+		return nil
+	}
+	m.run++
+	m.running, m.cancel, m.ch, m.handle, m.err = true, cancel, ch, handle, nil
+	m.progress = newProgressDisplay(io.Discard, m.total, true)
+	m.events = nil
+	m.failures = make(map[string]string)
+	m.refreshContent()
+	return m.waitProgress()
+}
+
+// waitProgress reads the next progress event of the current run
+func (m *tuiModel) waitProgress() tea.Cmd {
+	run, ch := m.run, m.ch
+	return func() tea.Msg {
+		ev, ok := <-ch
+		if !ok {
+			return tuiProgressDoneMsg{run: run}
+		}
+		return tuiProgressMsg{run: run, ev: ev}
+	}
+}
+
+// waitResult waits for the current run's report
+func (m *tuiModel) waitResult() tea.Cmd {
+	run, handle := m.run, m.handle
+	return func() tea.Msg {
+		rpt, err := handle.Result()
+		return tuiReportMsg{run: run, rpt: rpt, err: err}
+	}
+}
+
+// Update handles keys, resizes and run messages
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(1, msg.Height-lipgloss.Height(m.header())-1)
+		m.refreshContent()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "tab":
+			m.setTab((m.tab + 1) % tuiTab(len(tuiTabNames)))
+			return m, nil
+		case "shift+tab":
+			m.setTab((m.tab + tuiTab(len(tuiTabNames)) - 1) % tuiTab(len(tuiTabNames)))
+			return m, nil
+		case "1", "2", "3":
+			m.setTab(tuiTab(msg.String()[0] - '1'))
+			return m, nil
+		case "r":
+			if m.running {
+				return m, nil
+			}
+			return m, m.startRun()
+		case "home", "g":
+			m.viewport.GotoTop()
+			return m, nil
+		case "end", "G":
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+	case tuiTickMsg:
+		if m.progress != nil {
+			m.progress.frame++
+		}
+		return m, tuiTick()
+	case tuiProgressMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		m.recordProgress(msg.ev)
+		return m, m.waitProgress()
+	case tuiProgressDoneMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		return m, m.waitResult()
+	case tuiReportMsg:
+		if msg.run != m.run {
+			return m, nil
+		}
+		m.running = false
+		m.cancel()
+		m.finishedAt = time.Now()
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.report = msg.rpt
+		}
+		m.refreshContent()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// recordProgress adds a progress event to the display, the Progress tab and,
+// for failures, the Errors tab
+func (m *tuiModel) recordProgress(ev services.ReportProgress) {
+	m.progress.update(ev)
+	if ev.RepoID == "" {
+		return
+	}
+	line := fmt.Sprintf("%s  %-8s %s", ev.Timestamp.Local().Format("15:04:05"), ev.Phase, ev.RepoID)
+	if ev.Error != nil {
+		line += ": " + ev.Error.Error()
+	}
+	m.events = append(m.events, line)
+	if ev.Phase == services.PhaseError && ev.Error != nil {
+		m.failures[ev.RepoID] = ev.Error.Error()
+	}
+	if m.tab != tabMatrix {
+		m.refreshContent()
+	}
+}
+
+// setTab switches tabs, scrolling the new one to the top
+func (m *tuiModel) setTab(t tuiTab) {
+	m.tab = t
+	m.refreshContent()
+	m.viewport.GotoTop()
+}
+
+// refreshContent renders the current tab into the viewport, keeping the
+// scroll position where possible
+func (m *tuiModel) refreshContent() {
+	switch m.tab {
+	case tabMatrix:
+		m.viewport.SetContent(m.matrixContent())
+	case tabErrors:
+		m.viewport.SetContent(m.errorsContent())
+	case tabProgress:
+		m.viewport.SetContent(m.progressContent())
+	}
+}
+
+// matrixContent is the console rendering of the latest report
+func (m *tuiModel) matrixContent() string {
+	if m.report == nil {
+		if m.err != nil {
+			return "Report failed: " + m.err.Error()
+		}
+		return "Waiting for the first report..."
+	}
+	formatter := consolefmt.NewConsoleFormatter()
+	formatter.Columns = m.columns
+	formatter.EnableColors = m.colors
+	var buf bytes.Buffer
+	if err := formatter.Render(m.report, &buf); err != nil {
+		return "Cannot render the report: " + err.Error()
+	}
+	return buf.String()
+}
+
+// errorsContent lists the repositories that failed: the latest report's, or
+// the current run's so far while it runs
+func (m *tuiModel) errorsContent() string {
+	failures := m.failures
+	if !m.running && m.report != nil {
+		failures = make(map[string]string)
+		for _, rr := range m.report.Repositories {
+			if rr.Error != nil {
+				failures[rr.Key()] = fmt.Sprintf("[%s] %s", rr.ErrorCategory(), rr.Error)
+			}
+		}
+	}
+	if len(failures) == 0 {
+		return "No repository errors."
+	}
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(failures)) {
+		fmt.Fprintf(&b, "%s\n  %s\n\n", key, failures[key])
+	}
+	return b.String()
+}
+
+// progressContent is the current run's progress log
+func (m *tuiModel) progressContent() string {
+	if len(m.events) == 0 {
+		return "No progress events yet."
+	}
+	return strings.Join(m.events, "\n")
+}
+
+// tuiActiveTab and tuiTabStyle draw the tab bar
+var (
+	tuiActiveTab = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	tuiTabStyle  = lipgloss.NewStyle().Padding(0, 1)
+	tuiDimStyle  = lipgloss.NewStyle().Faint(true)
+)
+
+// header is the title, tab bar and status line
+func (m *tuiModel) header() string {
+	tabs := make([]string, len(tuiTabNames))
+	for i, name := range tuiTabNames {
+		title := fmt.Sprintf("%d %s", i+1, name)
+		if tuiTab(i) == tabErrors {
+			if n := m.errorCount(); n > 0 {
+				title += fmt.Sprintf(" (%d)", n)
+			}
+		}
+		if tuiTab(i) == m.tab {
+			tabs[i] = tuiActiveTab.Render(title)
+		} else {
+			tabs[i] = tuiTabStyle.Render(title)
+		}
+	}
+
+	var status string
+	switch {
+	case m.running:
+		status = m.progress.line()
+	case m.err != nil:
+		status = "Report failed: " + m.err.Error()
+	case m.report != nil:
+		status = fmt.Sprintf("Report complete at %s (%d repositories, %d failed)",
+			m.finishedAt.Format("15:04:05"), len(m.report.Repositories), m.errorCount())
+	}
+	title := fmt.Sprintf("DevDashboard - %s - %d repositories", m.configFile, m.total)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Render(title),
+		lipgloss.JoinHorizontal(lipgloss.Top, tabs...),
+		status,
+	)
+}
+
+// errorCount is the number of failed repositories shown on the Errors tab
+func (m *tuiModel) errorCount() int {
+	if !m.running && m.report != nil {
+		n := 0
+		for _, rr := range m.report.Repositories {
+			if rr.Error != nil {
+				n++
+			}
+		}
+		return n
+	}
+	return len(m.failures)
+}
+
+// View draws the header, the current tab and the key help
+func (m *tuiModel) View() string {
+	help := tuiDimStyle.Render("tab/1-3 switch · arrows scroll · r re-run · q quit")
+	return lipgloss.JoinVertical(lipgloss.Left, m.header(), m.viewport.View(), help)
+}
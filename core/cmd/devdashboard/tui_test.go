@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
+)
+
+// newTestTUIModel returns a dashboard over repositories of an unsupported
+// provider, whose runs fail fast without network access
+func newTestTUIModel() *tuiModel {
+	repos := []config.RepoWithProvider{
+		{Provider: "bogus", Config: config.RepoConfig{Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}}},
+	}
+	svc := services.NewDependencyService(nil)
+	return newTUIModel("repos.yaml", len(repos), func(ctx context.Context, prev *report.Snapshot) (<-chan services.ReportProgress, *services.ResultHandle, error) {
+		return svc.RunReport(ctx, repos, services.ReportOptions{Previous: prev})
+	})
+}
+
+// finishRun executes cmd and the commands it leads to until the run's
+// report arrives
+func finishRun(t *testing.T, m *tuiModel, cmd tea.Cmd) {
+	t.Helper()
+	for i := 0; cmd != nil; i++ {
+		if i > 100 {
+			t.Fatal("run did not finish")
+		}
+		msg := cmd()
+		_, cmd = m.Update(msg)
+		if _, ok := msg.(tuiReportMsg); ok {
+			return
+		}
+	}
+}
+
+func TestTUIModel_Run(t *testing.T) {
+	m := newTestTUIModel()
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	cmd := m.startRun()
+	if !m.running || !strings.Contains(m.View(), "[") || !strings.Contains(m.viewport.View(), "Waiting for the first report") {
+		t.Fatalf("running view:\n%s", m.View())
+	}
+	finishRun(t, m, cmd)
+
+	if m.running || m.report == nil {
+		t.Fatalf("run should be complete: running=%v report=%v err=%v", m.running, m.report, m.err)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Report complete") || !strings.Contains(view, "1 failed") || !strings.Contains(view, "2 Errors (1)") {
+		t.Errorf("complete view:\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	if content := m.errorsContent(); m.tab != tabErrors || !strings.Contains(content, "bogus:o/api@main") {
+		t.Errorf("errors tab %v:\n%s", m.tab, content)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if content := m.progressContent(); m.tab != tabProgress || !strings.Contains(content, "queued") || !strings.Contains(content, "error") {
+		t.Errorf("progress tab %v:\n%s", m.tab, content)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.tab != tabErrors {
+		t.Errorf("shift+tab should go back to errors, got %v", m.tab)
+	}
+}
+
+func TestTUIModel_RerunDropsStaleMessages(t *testing.T) {
+	m := newTestTUIModel()
+	finishRun(t, m, m.startRun())
+	first := m.report
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil || !m.running || m.run != 2 {
+		t.Fatalf("r should start a second run: running=%v run=%d", m.running, m.run)
+	}
+	if _, again := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}); again != nil {
+		t.Error("r should be ignored while a run is in progress")
+	}
+	m.Update(tuiReportMsg{run: 1, err: context.Canceled})
+	if !m.running || m.err != nil {
+		t.Error("a message of the previous run should be dropped")
+	}
+	finishRun(t, m, cmd)
+	if m.report == nil || m.report == first {
+		t.Error("second run should replace the report")
+	}
+}
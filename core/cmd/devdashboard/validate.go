@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/spf13/cobra"
+)
+
+// validate-config command flags
+type validateConfigFlags struct {
+	offline      bool
+	outputFormat string
+	tags         []string
+	timeout      time.Duration
+}
+
+var valFlags validateConfigFlags
+
+// Ref check statuses reported by validate-config
+const (
+	refStatusOK      = "ok"
+	refStatusMissing = "missing"
+	refStatusError   = "error"   // The check itself failed (auth, network)
+	refStatusSkipped = "skipped" // Provider cannot list refs
+)
+
+// refCheck is the result of checking one repository's ref
+type refCheck struct {
+	Repository  string   `json:"repository"` // provider:owner/repo@ref
+	Ref         string   `json:"ref"`
+	Status      string   `json:"status"`
+	Message     string   `json:"message,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// validateConfigOutput is the JSON shape of validate-config
+type validateConfigOutput struct {
+	Valid        bool       `json:"valid"`
+	Repositories int        `json:"repositories"`
+	Sources      []string   `json:"sources"` // Files merged, in merge order
+	Refs         []refCheck `json:"refs"`    // Empty with --offline
+}
+
+// newValidateConfigCmd creates the 'validate-config' subcommand.
+func newValidateConfigCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "validate-config <config-file>",
+		Short: "Check a configuration file and that its repositories' refs exist",
+		Long: strings.TrimSpace(`
+Load a configuration file, reporting syntax and validation errors, then check
+with each provider that every configured ref is a branch, tag or commit of its
+repository. A missing ref is reported with similarly named refs, so typos are
+caught before a report silently fails on them.
+
+Repositories without a ref use their default branch and are always valid.
+--offline skips the provider checks.
+
+A file using extends or include lists the files it was merged from.
+` + config.MergeOrder + `
+
+Exit status is 0 when the file is valid and every ref exists, and 1 (config
+error) otherwise.
+
+Examples:
+  devdashboard validate-config repos.yaml
+  devdashboard validate-config repos.yaml --tag prod --format json
+  devdashboard validate-config repos.yaml --offline
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: runValidateConfig,
+	}
+
+	c.Flags().BoolVar(&valFlags.offline, "offline", false, "Only validate the file; do not contact providers")
+	c.Flags().StringVarP(&valFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	c.Flags().StringSliceVar(&valFlags.tags, "tag", nil, "Only check repositories carrying any of these tags (repeatable or comma-separated)")
+	c.Flags().DurationVar(&valFlags.timeout, "timeout", 2*time.Minute, "Timeout for checking all refs")
+
+	return c
+}
+
+// runValidateConfig loads the configuration and checks its refs.
+func runValidateConfig(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(valFlags.outputFormat)
+	if format != "console" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", valFlags.outputFormat)
+	}
+	cfg, err := loadConfig(args[0])
+	if err != nil {
+		return exitcode.New(exitcode.ConfigError, fmt.Errorf("invalid config: %w", err))
+	}
+	repos := cfg.GetAllRepos()
+	if len(valFlags.tags) > 0 {
+		repos = config.FilterTags(repos, valFlags.tags)
+	}
+
+	out := validateConfigOutput{Valid: true, Repositories: len(repos), Sources: cfg.Sources, Refs: []refCheck{}}
+	if !valFlags.offline {
+		var requestTimeout time.Duration
+		if cfg.Timeouts != nil {
+			requestTimeout = cfg.Timeouts.Request
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), valFlags.timeout)
+		defer cancel()
+		for _, repo := range repos {
+			check := checkRepoRef(ctx, repo, requestTimeout)
+			if check.Status == refStatusMissing || check.Status == refStatusError {
+				out.Valid = false
+			}
+			out.Refs = append(out.Refs, check)
+		}
+	}
+
+	if format == "json" {
+		if err := writeJSON(os.Stdout, out); err != nil {
+			return err
+		}
+	} else if err := renderRefChecks(out, os.Stdout); err != nil {
+		return err
+	}
+	if !out.Valid {
+		return exitcode.New(exitcode.ConfigError, errors.New("configuration has invalid refs"))
+	}
+	return nil
+}
+
+// checkRepoRef checks that repo's ref exists with its provider
+func checkRepoRef(ctx context.Context, repo config.RepoWithProvider, requestTimeout time.Duration) refCheck {
+	rc := repo.Config
+	check := refCheck{
+		Repository: fmt.Sprintf("%s:%s/%s@%s", repo.Provider, rc.Owner, rc.Repository, rc.Ref),
+		Ref:        rc.Ref,
+		Status:     refStatusOK,
+	}
+	client, err := newRepoClient(repo, requestTimeout)
+	if err != nil {
+		check.Status, check.Message = refStatusError, err.Error()
+		return check
+	}
+	var notFound *repository.RefNotFoundError
+	switch err := repository.CheckRef(ctx, client, rc.Owner, rc.Repository, rc.Ref); {
+	case err == nil:
+		if rc.Ref == "" {
+			check.Message = "default branch"
+		}
+	case errors.Is(err, repository.ErrRefCheckUnsupported):
+		check.Status, check.Message = refStatusSkipped, err.Error()
+	case errors.As(err, &notFound):
+		check.Status, check.Message, check.Suggestions = refStatusMissing, err.Error(), notFound.Suggestions
+	default:
+		check.Status, check.Message = refStatusError, err.Error()
+	}
+	return check
+}
+
+// newRepoClient creates a provider client for repo outside report generation
+func newRepoClient(repo config.RepoWithProvider, requestTimeout time.Duration) (repository.Client, error) {
+	rc := repo.Config
+	return repository.NewClient(repo.Provider, repository.Config{
+		Token:              rc.Token,
+		BaseURL:            rc.BaseURL,
+		Enterprise:         rc.Enterprise,
+		APIPrefix:          rc.APIPrefix,
+		Proxy:              rc.Proxy,
+		CAFile:             rc.CAFile,
+		InsecureSkipVerify: rc.InsecureSkipVerify,
+		Tracer:             httpTracer,
+		Fixtures:           httpFixtures,
+		RequestTimeout:     requestTimeout,
+	})
+}
+
+// renderRefChecks writes the ref checks as an aligned table and a summary
+func renderRefChecks(out validateConfigOutput, w ioWriter) error {
+	if len(out.Refs) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "REPOSITORY\tSTATUS\tDETAILS")
+		for _, c := range out.Refs {
+			details := c.Message
+			if details == "" {
+				details = "-"
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Repository, c.Status, details)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(out.Sources) > 1 {
+		_, _ = fmt.Fprintln(w, "Merged files (later files override earlier ones):")
+		for i, source := range out.Sources {
+			_, _ = fmt.Fprintf(w, "  %d. %s\n", i+1, source)
+		}
+	}
+	result := "valid"
+	if !out.Valid {
+		result = "invalid"
+	}
+	_, err := fmt.Fprintf(w, "Configuration %s (%d repositories)\n", result, out.Repositories)
+	return err
+}
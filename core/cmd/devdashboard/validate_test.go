@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+)
+
+// TestCLIValidateConfigRefs checks refs against a fake GitHub API: a typo is
+// reported with a suggestion and fails validation, --offline skips the check.
+func TestCLIValidateConfigRefs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/org/api/branches":
+			_, _ = w.Write([]byte(`[{"name":"main"},{"name":"release/1.x"}]`))
+		case "/api/v3/repos/org/api/tags":
+			_, _ = w.Write([]byte(`[{"name":"v1.0.0"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+	cfgPath := writeTempConfig(t, fmt.Sprintf(`
+providers:
+  github:
+    default:
+      baseURL: %s/
+      analyzer: poetry
+    repositories:
+      - owner: org
+        repository: api
+        ref: main
+      - owner: org
+        repository: api
+        ref: relase/1.x
+      - owner: org
+        repository: api
+        ref: v1.0.0
+`, srv.URL))
+
+	root := newRootCmd()
+	root.SetArgs([]string{"validate-config", cfgPath, "--format", "json"})
+	out, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	var res validateConfigOutput
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if res.Valid || res.Repositories != 3 || len(res.Refs) != 3 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	for i, want := range []string{refStatusOK, refStatusMissing, refStatusOK} {
+		if res.Refs[i].Status != want {
+			t.Errorf("ref %s: status %s, want %s (%s)", res.Refs[i].Ref, res.Refs[i].Status, want, res.Refs[i].Message)
+		}
+	}
+	if s := res.Refs[1].Suggestions; len(s) != 1 || s[0] != "release/1.x" {
+		t.Errorf("suggestions = %v, want [release/1.x]", s)
+	}
+
+	root = newRootCmd()
+	root.SetArgs([]string{"validate-config", cfgPath, "--offline"})
+	out, err = executeCommand(root)
+	if err != nil {
+		t.Fatalf("offline validation failed: %v", err)
+	}
+	expectContains(t, out, "Configuration valid (3 repositories)", "offline summary")
+}
+
+// TestCLIValidateConfigSources lists the files a configuration is merged from
+func TestCLIValidateConfigSources(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	team := filepath.Join(dir, "team.yaml")
+	if err := os.WriteFile(base, []byte("providers:\n  github:\n    default:\n      analyzer: poetry\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(team, []byte("extends: [base.yaml]\nproviders:\n  github:\n    repositories:\n      - owner: org\n        repository: api\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newRootCmd()
+	root.SetArgs([]string{"validate-config", team, "--offline"})
+	out, err := executeCommand(root)
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	expectContains(t, out, "1. "+base, "merge order")
+	expectContains(t, out, "2. "+team, "merge order")
+	expectContains(t, out, "Configuration valid (1 repositories)", "summary")
+}
+
+// TestCLIConfigOverrides applies DEVDASHBOARD_* variables, then --set flags
+func TestCLIConfigOverrides(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+providers:
+  github:
+    repositories:
+      - owner: org
+        repository: api
+`)
+	t.Setenv("DEVDASHBOARD_PROVIDERS_GITHUB_DEFAULT_ANALYZER", "poetry")
+
+	root := newRootCmd()
+	root.SetArgs([]string{"validate-config", cfgPath, "--offline",
+		"--set", "providers.github.repositories.1.owner=org",
+		"--set", "providers.github.repositories.1.repository=web"})
+	out, err := executeCommand(root)
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	expectContains(t, out, "Configuration valid (2 repositories)", "summary")
+
+	root = newRootCmd()
+	root.SetArgs([]string{"validate-config", cfgPath, "--offline", "--set", "providers.github.default.analyzr=poetry"})
+	if _, err := executeCommand(root); exitcode.FromError(err) != exitcode.ConfigError {
+		t.Errorf("expected a config error for an unknown field, got %v", err)
+	}
+}
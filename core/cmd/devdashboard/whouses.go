@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+// who-uses command flags
+type whoUsesFlags struct {
+	versionRange string
+	outputFormat string
+	tags         []string
+	timeout      time.Duration
+	repoTimeout  time.Duration
+	jsonIndent   bool
+}
+
+var whoFlags whoUsesFlags
+
+// newWhoUsesCmd creates the 'who-uses' subcommand.
+func newWhoUsesCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "who-uses <config-file> <package>",
+		Short: "List the repositories whose lock files contain a package",
+		Long: strings.TrimSpace(`
+Find every configured repository whose lock files contain a package, whether
+or not the package is tracked. For each one, list the locked version, the lock
+files, whether the project requires the package directly or transitively, and
+the packages that require it.
+
+Direct requirements come from the project's entry in uv.lock and from the
+pyproject.toml or Pipfile next to each lock file, which are always read for
+this command. "unknown" means neither source was available.
+
+Examples:
+  devdashboard who-uses repos.yaml urllib3
+  devdashboard who-uses repos.yaml urllib3 --version "<2"
+  devdashboard who-uses repos.yaml requests --format json | jq -r '.usages[].repository'
+`),
+		Args: cobra.ExactArgs(2),
+		RunE: runWhoUses,
+	}
+
+	c.Flags().StringVar(&whoFlags.versionRange, "version", "", "Only list repositories whose locked version is in this range (e.g. \">=2.0,<3\", \"~=1.4\", \"==2.*\")")
+	c.Flags().StringVarP(&whoFlags.outputFormat, "format", "f", "console", "Output format: console|json")
+	c.Flags().StringSliceVar(&whoFlags.tags, "tag", nil, "Only search repositories carrying any of these tags (repeatable or comma-separated)")
+	c.Flags().DurationVar(&whoFlags.timeout, "timeout", 5*time.Minute, "Timeout for analyzing all repositories")
+	c.Flags().DurationVar(&whoFlags.repoTimeout, "repo-timeout", 0, "Timeout for analyzing each repository (0 = limited only by --timeout)")
+	c.Flags().BoolVar(&whoFlags.jsonIndent, "json-indent", false, "Pretty-print JSON output")
+
+	return c
+}
+
+// runWhoUses analyzes the configured repositories with dependency graphs and
+// lists those containing the package.
+func runWhoUses(cmd *cobra.Command, args []string) error {
+	configFile, pkg := args[0], args[1]
+	format := strings.ToLower(whoFlags.outputFormat)
+	if format != "console" && format != "json" {
+		return exitcode.Errorf(exitcode.ConfigError, "unsupported format: %s", whoFlags.outputFormat)
+	}
+
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	repos := cfg.GetAllRepos()
+	if len(whoFlags.tags) > 0 {
+		repos = config.FilterTags(repos, whoFlags.tags)
+	}
+	if len(repos) == 0 {
+		return exitcode.New(exitcode.ConfigError, errors.New("no repositories to search"))
+	}
+	if whoFlags.versionRange != "" {
+		// Validate up front rather than after analyzing every repository
+		for i := range repos {
+			eco := dependencies.EcosystemForAnalyzer(repos[i].Config.Analyzer)
+			if _, err := versioning.ParseRange(eco, whoFlags.versionRange); err != nil {
+				return exitcode.New(exitcode.ConfigError, err)
+			}
+		}
+	}
+	for i := range repos {
+		repos[i].Config.Constraints = true
+	}
+
+	applyConfigTimeouts(cmd, cfg, &whoFlags.timeout, &whoFlags.repoTimeout)
+	ctx, cancel := context.WithTimeout(cmd.Context(), whoFlags.timeout)
+	defer cancel()
+
+	generator := report.NewGenerator()
+	if cfg.Retry != nil {
+		generator.SetRetryPolicy(report.RetryPolicyFromConfig(cfg.Retry))
+	}
+	generator.SetRepositoryTimeout(whoFlags.repoTimeout)
+	if cfg.Timeouts != nil {
+		generator.SetRequestTimeout(cfg.Timeouts.Request)
+	}
+	generator.SetBudget(cfg.Budget)
+	generator.SetMaxFileSize(cfg.MaxFileSize)
+	generator.SetIncludeGraph(true)
+	generator.SetHTTPTracer(httpTracer)
+	generator.SetFixtures(httpFixtures)
+	rpt, err := generator.Generate(ctx, repos)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil {
+			slog.Warn("Repository not searched", "repository", rr.Key(), "error", rr.Error)
+		}
+	}
+
+	usages, err := rpt.FindUsages(pkg, whoFlags.versionRange)
+	if err != nil {
+		return exitcode.New(exitcode.ConfigError, err)
+	}
+	if format == "json" {
+		return renderUsagesJSON(rpt, pkg, usages, os.Stdout)
+	}
+	return renderUsages(pkg, usages, os.Stdout)
+}
+
+// renderUsages writes usages as an aligned table
+func renderUsages(pkg string, usages []report.Usage, w ioWriter) error {
+	if len(usages) == 0 {
+		_, err := fmt.Fprintf(w, "No repositories use %s\n", pkg)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPOSITORY\tVERSION\tRELATION\tFILES\tREQUIRED BY")
+	for _, u := range usages {
+		requiredBy := strings.Join(u.RequiredBy, ", ")
+		if requiredBy == "" {
+			requiredBy = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", u.Repository, u.Version, u.Relation, strings.Join(u.Files, ", "), requiredBy)
+	}
+	return tw.Flush()
+}
+
+// whoUsesOutput is the JSON shape of who-uses
+type whoUsesOutput struct {
+	Package      string         `json:"package"`
+	VersionRange string         `json:"versionRange,omitempty"`
+	Usages       []report.Usage `json:"usages"`
+	// Errors maps repositories that could not be searched to their error
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// renderUsagesJSON writes usages (and repositories that failed) as JSON
+func renderUsagesJSON(rpt *report.Report, pkg string, usages []report.Usage, w ioWriter) error {
+	payload := whoUsesOutput{Package: pkg, VersionRange: whoFlags.versionRange, Usages: usages}
+	if payload.Usages == nil {
+		payload.Usages = []report.Usage{}
+	}
+	for _, rr := range rpt.Repositories {
+		if rr.Error == nil {
+			continue
+		}
+		if payload.Errors == nil {
+			payload.Errors = make(map[string]string)
+		}
+		payload.Errors[rr.Key()] = rr.Error.Error()
+	}
+
+	var data []byte
+	var err error
+	if whoFlags.jsonIndent {
+		data, err = json.MarshalIndent(payload, "", "  ")
+	} else {
+		data, err = json.Marshal(payload)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, _ = w.Write(data)
+	_, _ = w.Write([]byte("\n"))
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestRenderUsages(t *testing.T) {
+	usages := []report.Usage{
+		{Repository: "github:o/api@main", Package: "urllib3", Version: "2.0.7", Files: []string{"uv.lock"}, Relation: report.RelationTransitive, RequiredBy: []string{"requests"}},
+		{Repository: "github:o/legacy@main", Package: "urllib3", Version: "1.26.18", Files: []string{"Pipfile.lock"}, Relation: report.RelationDirect},
+	}
+	var buf bytes.Buffer
+	if err := renderUsages("urllib3", usages, &buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "transitive") || !strings.HasSuffix(lines[1], "requests") || !strings.HasSuffix(lines[2], "-") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	_ = renderUsages("left-pad", nil, &buf)
+	expectContains(t, buf.String(), "No repositories use left-pad", "empty result")
+
+	buf.Reset()
+	rpt := &report.Report{Repositories: []report.RepositoryReport{{Provider: "github", Owner: "o", Repository: "down", Ref: "main", Error: report.NewErrorDetail(errors.New("boom"))}}}
+	if err := renderUsagesJSON(rpt, "urllib3", usages, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var out whoUsesOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(out.Usages) != 2 || out.Usages[1].Relation != "direct" || out.Errors["github:o/down@main"] != "boom" {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
+
+// TestCLIWhoUsesInvalidRange ensures a bad --version fails before any analysis.
+func TestCLIWhoUsesInvalidRange(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+providers:
+  github:
+    default:
+      analyzer: poetry
+    repositories:
+      - owner: o
+        repository: r
+        packages: ["django"]
+`)
+	root := newRootCmd()
+	root.SetArgs([]string{"who-uses", cfgPath, "django", "--version", ">=not-a-version"})
+
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	expectContains(t, err.Error(), "invalid version range", "range error")
+}
+
+// TestCLIWhoUsesConfigOverrides ensures --set applies to the loaded config.
+func TestCLIWhoUsesConfigOverrides(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+providers:
+  github:
+    default:
+      analyzer: poetry
+    repositories:
+      - owner: o
+        repository: r
+        packages: ["django"]
+`)
+	root := newRootCmd()
+	root.SetArgs([]string{"who-uses", cfgPath, "django", "--set", "providers.github.default.analyzr=poetry"})
+
+	_, err := executeCommand(root)
+	if code := exitcode.FromError(err); code != exitcode.ConfigError {
+		t.Fatalf("expected exit code %d for an unknown --set field, got %d (%v)", exitcode.ConfigError, code, err)
+	}
+	expectContains(t, err.Error(), "analyzr", "override error")
+}
@@ -0,0 +1,156 @@
+// Package devdashboard is the stable entry point for Go programs embedding
+// dependency reporting. It loads a configuration, analyzes the configured
+// repositories and returns the same report the CLI renders, without
+// importing the CLI or GUI.
+//
+// The identifiers exported here follow semantic versioning: they are not
+// removed or changed incompatibly within a major version. The pkg/...
+// packages they are built on may change between minor versions; prefer this
+// package where it covers what you need.
+//
+//	cfg, err := devdashboard.LoadConfig("repos.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	rpt, err := devdashboard.RunReport(ctx, cfg, devdashboard.Options{Tags: []string{"team-payments"}})
+//	if err != nil {
+//		return err
+//	}
+//	for _, pv := range rpt.GetPackageVersions() {
+//		fmt.Println(pv.PackageName, pv.HasDrift())
+//	}
+package devdashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// Configuration types
+type (
+	// Config is a parsed and validated configuration file
+	Config = config.Config
+	// Repository is one configured repository with its provider
+	Repository = config.RepoWithProvider
+)
+
+// Report types
+type (
+	// Report is the result of analyzing the configured repositories
+	Report = report.Report
+	// RepositoryReport is the result for one repository
+	RepositoryReport = report.RepositoryReport
+	// PackageVersions lists the versions of one package across repositories
+	PackageVersions = report.PackageVersions
+	// Snapshot is a serializable report used for incremental runs
+	Snapshot = report.Snapshot
+)
+
+// Extension types
+type (
+	// Analyzer finds and parses the lock files of one dependency manager
+	Analyzer = dependencies.Analyzer
+	// Dependency is one locked package
+	Dependency = dependencies.Dependency
+	// Client accesses a git hosting provider
+	Client = repository.Client
+	// ClientConfig holds the credentials and endpoint passed to a provider
+	ClientConfig = repository.Config
+)
+
+// Options tunes a RunReport call. The zero value analyzes every configured
+// repository.
+type Options struct {
+	// Tags limits the run to repositories carrying any of these tags
+	Tags []string
+	// PackageGroups limits the run to repositories tracking packages of these
+	// configured groups
+	PackageGroups []string
+	// RepositoryTimeout bounds the analysis of each repository (0 = the
+	// configuration's timeouts.repository, if any, else limited only by ctx)
+	RepositoryTimeout time.Duration
+	// IncludeGraph populates each repository's full dependency graph
+	IncludeGraph bool
+	// Previous enables incremental analysis: repositories whose commit is
+	// unchanged since this snapshot reuse its results
+	Previous *Snapshot
+}
+
+// LoadConfig reads, defaults and validates a configuration file
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadFromFile(path)
+}
+
+// RunReport analyzes the repositories of cfg selected by opts with the
+// configuration's retry policy, budgets, timeouts, file size limit, policies
+// and hooks. The configured run timeout shortens ctx's deadline.
+// Failures of individual repositories are recorded in the report rather than
+// returned; see Report.Err.
+func RunReport(ctx context.Context, cfg *Config, opts Options) (*Report, error) {
+	repos := cfg.GetAllRepos()
+	if len(opts.Tags) > 0 {
+		repos = config.FilterTags(repos, opts.Tags)
+	}
+	if len(opts.PackageGroups) > 0 {
+		packages, err := cfg.ResolvePackageGroups(opts.PackageGroups)
+		if err != nil {
+			return nil, err
+		}
+		repos = config.FilterPackages(repos, packages)
+	}
+	if len(repos) == 0 {
+		if len(opts.Tags) > 0 || len(opts.PackageGroups) > 0 {
+			return nil, fmt.Errorf("no repositories match tags [%s] and package groups [%s]",
+				strings.Join(opts.Tags, ", "), strings.Join(opts.PackageGroups, ", "))
+		}
+		return nil, errors.New("no repositories configured")
+	}
+
+	generator, err := report.NewGeneratorFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if opts.RepositoryTimeout > 0 {
+		generator.SetRepositoryTimeout(opts.RepositoryTimeout)
+	}
+	generator.SetIncludeGraph(opts.IncludeGraph)
+	generator.SetPrevious(opts.Previous)
+	if cfg.Timeouts != nil && cfg.Timeouts.Run > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeouts.Run)
+		defer cancel()
+	}
+	return generator.Generate(ctx, repos)
+}
+
+// Analyzers returns the analyzer types repositories may use: the built-in
+// ones followed by those added with RegisterAnalyzer
+func Analyzers() []string {
+	return dependencies.SupportedAnalyzers()
+}
+
+// Providers returns the git hosting providers configurations may use: the
+// built-in ones followed by those added with RegisterProvider
+func Providers() []string {
+	return repository.SupportedProviders()
+}
+
+// RegisterAnalyzer adds an analyzer type (see dependencies.Register). Call
+// it from an init function; it panics if the name is empty or taken.
+func RegisterAnalyzer(name string, factory func() Analyzer) {
+	dependencies.Register(name, factory)
+}
+
+// RegisterProvider adds a git hosting provider (see repository.Register).
+// Call it from an init function; it panics if the name is empty or taken.
+func RegisterProvider(name string, factory func(ClientConfig) (Client, error)) {
+	repository.Register(name, factory)
+}
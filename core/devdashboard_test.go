@@ -0,0 +1,98 @@
+package devdashboard
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// embeddedClient is a provider registered through the facade; only the
+// repository metadata is consulted because embeddedAnalyzer reads no files
+type embeddedClient struct {
+	repository.Client
+}
+
+func (embeddedClient) GetRepositoryInfo(_ context.Context, owner, repo string) (*repository.Info, error) {
+	return &repository.Info{Name: repo, FullName: owner + "/" + repo, DefaultBranch: "main"}, nil
+}
+
+// embeddedAnalyzer reports a fixed lock file per repository
+type embeddedAnalyzer struct{}
+
+func (embeddedAnalyzer) Name() string { return "embedded" }
+
+func (embeddedAnalyzer) CandidateFiles(context.Context, string, string, string, dependencies.Config) ([]dependencies.DependencyFile, error) {
+	return []dependencies.DependencyFile{{Path: "deps.lock", Type: "deps.lock", Analyzer: "embedded"}}, nil
+}
+
+func (embeddedAnalyzer) AnalyzeDependencies(_ context.Context, _, repo, _ string, _ []dependencies.DependencyFile, _ dependencies.Config) (map[string][]Dependency, error) {
+	version := "1.0.0"
+	if repo == "new" {
+		version = "2.0.0"
+	}
+	return map[string][]Dependency{"deps.lock": {{Name: "libfoo", Version: version}}}, nil
+}
+
+func init() {
+	RegisterProvider("embeddedhost", func(ClientConfig) (Client, error) { return embeddedClient{}, nil })
+	RegisterAnalyzer("embedded", func() Analyzer { return embeddedAnalyzer{} })
+}
+
+func newEmbeddedConfig(t *testing.T) *Config {
+	t.Helper()
+	var cfg Config
+	cfg.Providers = map[string]config.ProviderConfig{
+		"embeddedhost": {
+			Default: config.RepoDefaults{Owner: "org", Ref: "main", Analyzer: "embedded", Packages: []string{"libfoo"}},
+			Repositories: []config.RepoConfig{
+				{Repository: "old", Tags: []string{"legacy"}},
+				{Repository: "new"},
+			},
+		},
+	}
+	if err := cfg.ApplyDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	return &cfg
+}
+
+func TestRunReport(t *testing.T) {
+	cfg := newEmbeddedConfig(t)
+
+	rpt, err := RunReport(context.Background(), cfg, Options{})
+	if err != nil {
+		t.Fatalf("RunReport: %v", err)
+	}
+	if err := rpt.Err(); err != nil {
+		t.Fatalf("report error: %v", err)
+	}
+	pvs := rpt.GetPackageVersions()
+	if len(pvs) != 1 || pvs[0].PackageName != "libfoo" || !pvs[0].HasDrift() {
+		t.Errorf("package versions = %+v, want drifting libfoo", pvs)
+	}
+
+	rpt, err = RunReport(context.Background(), cfg, Options{Tags: []string{"legacy"}})
+	if err != nil {
+		t.Fatalf("RunReport with tags: %v", err)
+	}
+	if len(rpt.Repositories) != 1 || rpt.Repositories[0].Repository != "old" {
+		t.Errorf("tagged run = %+v, want only org/old", rpt.Repositories)
+	}
+
+	if _, err := RunReport(context.Background(), cfg, Options{Tags: []string{"missing"}}); err == nil {
+		t.Error("expected an error when no repository matches")
+	}
+}
+
+func TestRegistries(t *testing.T) {
+	if !slices.Contains(Analyzers(), "embedded") || !slices.Contains(Analyzers(), "poetry") {
+		t.Errorf("Analyzers() = %v", Analyzers())
+	}
+	if !slices.Contains(Providers(), "embeddedhost") || !slices.Contains(Providers(), "github") {
+		t.Errorf("Providers() = %v", Providers())
+	}
+}
@@ -108,6 +108,10 @@ func (m *failingMockClient) ListFilesRecursive(ctx context.Context, owner, repo,
 	return m.ListFiles(ctx, owner, repo, ref, "")
 }
 
+func (m *failingMockClient) ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]repository.FileInfo, error) {
+	return m.ListFiles(ctx, owner, repo, ref, prefix)
+}
+
 func (m *failingMockClient) GetFileContent(ctx context.Context, owner, repo, ref, path string) (string, error) {
 	m.callCount++
 
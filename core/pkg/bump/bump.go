@@ -0,0 +1,406 @@
+// Package bump opens pull requests that move repositories locking an older
+// version of a package to a target version. Each repository is cloned into a
+// temporary directory, its lock files are updated by the package manager
+// (poetry, uv) and the change is pushed to a branch named after the package
+// and version, so later runs find the open pull request instead of opening
+// another one.
+package bump
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// BranchPrefix starts the name of every bump branch; the report recognizes
+// pull requests from these branches as update PRs
+const BranchPrefix = "devdashboard/bump-"
+
+// DefaultAuthor signs bump commits when Bumper.Author is empty
+const DefaultAuthor = "devdashboard <devdashboard@localhost>"
+
+// Status is the outcome of one repository's bump
+type Status string
+
+const (
+	// StatusPlanned means a dry run would open a pull request
+	StatusPlanned Status = "planned"
+	// StatusOpened means a pull request was opened
+	StatusOpened Status = "opened"
+	// StatusOpen means a pull request for this bump was already open
+	StatusOpen Status = "open"
+	// StatusUnchanged means the updater left the lock files as they were
+	StatusUnchanged Status = "unchanged"
+	// StatusFailed means the bump could not be completed (see Result.Error)
+	StatusFailed Status = "failed"
+)
+
+// Target is a repository locking the package below the target version
+type Target struct {
+	Repo    config.RepoWithProvider
+	Version string   // Currently locked version
+	Files   []string // Lock files locking the package
+}
+
+// Result records what Bump did for one repository
+type Result struct {
+	Repository  string                        `json:"repository"` // RepositoryReport.Key
+	From        string                        `json:"from"`
+	To          string                        `json:"to"` // Version the updater resolved (the target until then)
+	Status      Status                        `json:"status"`
+	PullRequest *repository.UpdatePullRequest `json:"pullRequest,omitempty"`
+	Error       string                        `json:"error,omitempty"`
+}
+
+// Targets returns the repositories of rpt that lock pkg below version, in
+// report order. rpt must include dependency graphs (see
+// Generator.SetIncludeGraph); repos supplies their configuration.
+func Targets(rpt *report.Report, repos []config.RepoWithProvider, pkg, version string) ([]Target, error) {
+	usages, err := rpt.FindUsages(pkg, "<"+version)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]config.RepoWithProvider, len(repos))
+	for _, r := range repos {
+		rr := report.RepositoryReport{Provider: r.Provider, Owner: r.Config.Owner, Repository: r.Config.Repository, Ref: r.Config.Ref}
+		byKey[rr.Key()] = r
+	}
+	targets := make([]Target, 0, len(usages))
+	for _, u := range usages {
+		r, ok := byKey[u.Repository]
+		if !ok {
+			continue
+		}
+		targets = append(targets, Target{Repo: r, Version: u.Version, Files: u.Files})
+	}
+	return targets, nil
+}
+
+// Branch returns the branch a bump of pkg to version is pushed to
+func Branch(eco dependencies.Ecosystem, pkg, version string) string {
+	return BranchPrefix + dependencies.NormalizeName(eco, pkg) + "-" + version
+}
+
+// UpdateCommand returns the command that updates pkg in the lock file of an
+// analyzer's type, run in the lock file's directory. poetry moves the package
+// to the newest version its manifest constraint allows; uv pins the version.
+func UpdateCommand(analyzer, pkg, version string) ([]string, error) {
+	switch dependencies.AnalyzerType(strings.ToLower(analyzer)) {
+	case dependencies.AnalyzerPoetry:
+		return []string{"poetry", "update", "--lock", pkg}, nil
+	case dependencies.AnalyzerUvLock:
+		return []string{"uv", "lock", "--upgrade-package", pkg + "==" + version}, nil
+	default:
+		return nil, fmt.Errorf("no lock file updater for analyzer %s (supported: poetry, uvlock)", analyzer)
+	}
+}
+
+// Bumper opens bump pull requests for one package and target version
+type Bumper struct {
+	Package string
+	Version string
+
+	// DryRun stops after checking for an open pull request and reports the
+	// remaining targets as planned
+	DryRun bool
+
+	// Author signs the commits as "Name <email>"; DefaultAuthor when empty
+	Author string
+
+	// HTTPTracer, when set, records the provider API requests (see
+	// repository.HTTPTracer)
+	HTTPTracer *repository.HTTPTracer
+
+	// NewClient creates repository clients; repository.NewClient when nil
+	NewClient func(provider string, cfg repository.Config) (repository.Client, error)
+
+	// Command returns the updater to run; UpdateCommand when nil
+	Command func(analyzer, pkg, version string) ([]string, error)
+}
+
+// Run bumps every target in turn
+func (b *Bumper) Run(ctx context.Context, targets []Target) []Result {
+	results := make([]Result, 0, len(targets))
+	for _, t := range targets {
+		res := b.Bump(ctx, t)
+		if res.Status == StatusFailed {
+			slog.Warn("Bump failed", "repository", res.Repository, "error", res.Error)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// Bump updates one repository's lock files on the bump branch and opens a
+// pull request, unless one is already open
+func (b *Bumper) Bump(ctx context.Context, t Target) Result {
+	repo := t.Repo
+	rr := report.RepositoryReport{Provider: repo.Provider, Owner: repo.Config.Owner, Repository: repo.Config.Repository, Ref: repo.Config.Ref}
+	res := Result{Repository: rr.Key(), From: t.Version, To: b.Version}
+	fail := func(err error) Result {
+		res.Status = StatusFailed
+		res.Error = err.Error()
+		return res
+	}
+
+	command := b.Command
+	if command == nil {
+		command = UpdateCommand
+	}
+	argv, err := command(repo.Config.Analyzer, b.Package, b.Version)
+	if err != nil {
+		return fail(err)
+	}
+	newClient := b.NewClient
+	if newClient == nil {
+		newClient = repository.NewClient
+	}
+	client, err := newClient(repo.Provider, repository.Config{
+		Token:              repo.Config.Token,
+		BaseURL:            repo.Config.BaseURL,
+		Enterprise:         repo.Config.Enterprise,
+		APIPrefix:          repo.Config.APIPrefix,
+		Proxy:              repo.Config.Proxy,
+		CAFile:             repo.Config.CAFile,
+		InsecureSkipVerify: repo.Config.InsecureSkipVerify,
+		Tracer:             b.HTTPTracer,
+	})
+	if err != nil {
+		return fail(fmt.Errorf("failed to create repository client: %w", err))
+	}
+	creator, ok := client.(repository.PullRequestCreator)
+	if !ok {
+		return fail(fmt.Errorf("provider %s cannot open pull requests", repo.Provider))
+	}
+
+	eco := dependencies.EcosystemForAnalyzer(repo.Config.Analyzer)
+	branch := Branch(eco, b.Package, b.Version)
+	if lister, ok := client.(repository.UpdatePullRequestLister); ok {
+		prs, err := lister.ListUpdatePullRequests(ctx, repo.Config.Owner, repo.Config.Repository)
+		if err != nil {
+			return fail(err)
+		}
+		for i := range prs {
+			if prs[i].Branch == branch {
+				res.Status = StatusOpen
+				res.PullRequest = &prs[i]
+				return res
+			}
+		}
+	}
+	if b.DryRun {
+		res.Status = StatusPlanned
+		return res
+	}
+
+	info, err := client.GetRepositoryInfo(ctx, repo.Config.Owner, repo.Config.Repository)
+	if err != nil {
+		return fail(err)
+	}
+	if info.CloneURL == "" {
+		return fail(errors.New("provider did not return a clone URL"))
+	}
+	base := repo.Config.Ref
+	if base == "" {
+		base = info.DefaultBranch
+	}
+
+	dir, err := os.MkdirTemp("", "devdashboard-bump-")
+	if err != nil {
+		return fail(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	env := gitEnv(repo.Provider, repo.Config)
+	clone := []string{"clone", "--depth", "1", "--single-branch"}
+	if base != "" {
+		clone = append(clone, "--branch", base)
+	}
+	if err := run(ctx, "", env, "git", append(clone, info.CloneURL, dir)...); err != nil {
+		return fail(err)
+	}
+	if err := run(ctx, dir, env, "git", "checkout", "-b", branch); err != nil {
+		return fail(err)
+	}
+	for _, lockDir := range lockDirs(t.Files) {
+		if err := run(ctx, filepath.Join(dir, lockDir), env, argv[0], argv[1:]...); err != nil {
+			return fail(err)
+		}
+	}
+
+	changed, err := output(ctx, dir, env, "git", "status", "--porcelain")
+	if err != nil {
+		return fail(err)
+	}
+	if strings.TrimSpace(changed) == "" {
+		res.Status = StatusUnchanged
+		return res
+	}
+	resolved, err := resolvedVersion(dir, repo.Config.Analyzer, b.Package, t.Files)
+	if err != nil {
+		return fail(err)
+	}
+	res.To = resolved
+	if versioning.Compare(eco, resolved, b.Version) < 0 {
+		return fail(fmt.Errorf("updater resolved %s %s, below %s (check the manifest constraint)", b.Package, resolved, b.Version))
+	}
+
+	title := fmt.Sprintf("Bump %s from %s to %s", b.Package, t.Version, resolved)
+	name, email, err := parseAuthor(b.Author)
+	if err != nil {
+		return fail(err)
+	}
+	if err := run(ctx, dir, env, "git", "add", "-A"); err != nil {
+		return fail(err)
+	}
+	if err := run(ctx, dir, env, "git", "-c", "user.name="+name, "-c", "user.email="+email, "commit", "-m", title); err != nil {
+		return fail(err)
+	}
+	if err := run(ctx, dir, env, "git", "push", "--force", "origin", "HEAD:refs/heads/"+branch); err != nil {
+		return fail(err)
+	}
+
+	pr, err := creator.CreatePullRequest(ctx, repo.Config.Owner, repo.Config.Repository, repository.NewPullRequest{
+		Title: title,
+		Body:  body(b.Package, t, resolved, argv),
+		Head:  branch,
+		Base:  base,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	slog.Info("Opened bump pull request", "repository", res.Repository, "url", pr.URL)
+	res.Status = StatusOpened
+	res.PullRequest = &pr
+	return res
+}
+
+// body describes the bump in the pull request
+func body(pkg string, t Target, resolved string, argv []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Updates %s from %s to %s in:\n\n", pkg, t.Version, resolved)
+	for _, f := range t.Files {
+		fmt.Fprintf(&b, "- `%s`\n", f)
+	}
+	fmt.Fprintf(&b, "\nGenerated by devdashboard with `%s`.\n", strings.Join(argv, " "))
+	return b.String()
+}
+
+// lockDirs returns the distinct directories of files, in order
+func lockDirs(files []string) []string {
+	var dirs []string
+	for _, f := range files {
+		if d := filepath.Dir(filepath.FromSlash(f)); !slices.Contains(dirs, d) {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
+
+// resolvedVersion returns the lowest version of pkg across the updated lock
+// files in the clone at dir
+func resolvedVersion(dir, analyzer, pkg string, files []string) (string, error) {
+	eco := dependencies.EcosystemForAnalyzer(analyzer)
+	name := dependencies.NormalizeName(eco, pkg)
+	var versions []string
+	for _, f := range files {
+		fh, err := os.Open(filepath.Join(dir, filepath.FromSlash(f)))
+		if err != nil {
+			return "", err
+		}
+		deps, err := dependencies.ParseLockFile(analyzer, fh)
+		_ = fh.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to parse updated %s: %w", f, err)
+		}
+		for _, d := range deps {
+			if dependencies.NormalizeName(eco, d.Name) == name {
+				versions = append(versions, d.Version)
+			}
+		}
+	}
+	lowest, _ := versioning.MinMax(eco, versions)
+	if lowest == "" {
+		return "", fmt.Errorf("%s is no longer locked after the update", pkg)
+	}
+	return lowest, nil
+}
+
+// parseAuthor splits "Name <email>" (DefaultAuthor when empty)
+func parseAuthor(author string) (name, email string, err error) {
+	if author == "" {
+		author = DefaultAuthor
+	}
+	addr, err := mail.ParseAddress(author)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid author %q: %w", author, err)
+	}
+	return addr.Name, addr.Address, nil
+}
+
+// gitEnv authenticates git over HTTPS with the repository's token through an
+// extra header, so the token never appears in command lines, URLs or git's
+// error messages, and applies its proxy and TLS settings
+func gitEnv(provider string, repo config.RepoConfig) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var settings [][2]string
+	if repo.Token != "" {
+		user := "x-access-token"
+		if strings.EqualFold(provider, string(repository.ProviderGitLab)) {
+			user = "oauth2"
+		}
+		cred := base64.StdEncoding.EncodeToString([]byte(user + ":" + repo.Token))
+		settings = append(settings, [2]string{"http.extraHeader", "Authorization: Basic " + cred})
+	}
+	if repo.Proxy != "" {
+		settings = append(settings, [2]string{"http.proxy", repo.Proxy})
+	}
+	if repo.CAFile != "" {
+		settings = append(settings, [2]string{"http.sslCAInfo", repo.CAFile})
+	}
+	if repo.InsecureSkipVerify {
+		settings = append(settings, [2]string{"http.sslVerify", "false"})
+	}
+	if len(settings) == 0 {
+		return env
+	}
+	env = append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(settings)))
+	for i, kv := range settings {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, kv[0]), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, kv[1]))
+	}
+	return env
+}
+
+// run executes a command in dir, including its output in the error
+func run(ctx context.Context, dir string, env []string, name string, args ...string) error {
+	_, err := output(ctx, dir, env, name, args...)
+	return err
+}
+
+// output executes a command in dir and returns its standard output
+func output(ctx context.Context, dir string, env []string, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 -- fixed commands; arguments come from configuration
+	cmd.Dir = dir
+	cmd.Env = env
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
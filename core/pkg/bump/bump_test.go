@@ -0,0 +1,217 @@
+package bump
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+const lockBefore = `
+[[package]]
+name = "django"
+version = "3.2.0"
+`
+
+// fakeClient serves repository info pointing at a local bare repository and
+// records opened pull requests
+type fakeClient struct {
+	repository.Client
+	cloneURL string
+	open     []repository.UpdatePullRequest
+	created  []repository.NewPullRequest
+}
+
+func (f *fakeClient) GetRepositoryInfo(context.Context, string, string) (*repository.Info, error) {
+	return &repository.Info{DefaultBranch: "main", CloneURL: f.cloneURL}, nil
+}
+
+func (f *fakeClient) ListUpdatePullRequests(context.Context, string, string) ([]repository.UpdatePullRequest, error) {
+	return f.open, nil
+}
+
+func (f *fakeClient) CreatePullRequest(_ context.Context, _, _ string, pr repository.NewPullRequest) (repository.UpdatePullRequest, error) {
+	f.created = append(f.created, pr)
+	return repository.UpdatePullRequest{Number: 1, Title: pr.Title, Branch: pr.Head, URL: "https://example.com/pull/1"}, nil
+}
+
+// writeLock is an updater command locking django at version
+func writeLock(version string) []string {
+	return []string{"sh", "-c", `printf '[[package]]\nname = "django"\nversion = "%s"\n' "$0" > poetry.lock`, version}
+}
+
+// gitCmd runs git in dir, failing the test on error
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+// remote creates a bare repository whose main branch holds app/poetry.lock
+func remote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	bare := filepath.Join(t.TempDir(), "remote.git")
+	gitCmd(t, "", "init", "--bare", "--initial-branch=main", bare)
+	work := t.TempDir()
+	gitCmd(t, work, "clone", bare, ".")
+	if err := os.MkdirAll(filepath.Join(work, "app"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "app", "poetry.lock"), []byte(lockBefore), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	gitCmd(t, work, "checkout", "-b", "main")
+	gitCmd(t, work, "add", "-A")
+	gitCmd(t, work, "commit", "-m", "initial")
+	gitCmd(t, work, "push", "origin", "main")
+	return bare
+}
+
+func target() Target {
+	return Target{
+		Repo: config.RepoWithProvider{Provider: "github", Config: config.RepoConfig{
+			Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry",
+		}},
+		Version: "3.2.0",
+		Files:   []string{"app/poetry.lock"},
+	}
+}
+
+func TestBump(t *testing.T) {
+	bare := remote(t)
+	client := &fakeClient{cloneURL: bare}
+	b := &Bumper{
+		Package:   "Django",
+		Version:   "4.2.11",
+		NewClient: func(string, repository.Config) (repository.Client, error) { return client, nil },
+		Command: func(_, _, _ string) ([]string, error) {
+			return writeLock("4.2.11"), nil
+		},
+	}
+
+	res := b.Bump(context.Background(), target())
+	if res.Status != StatusOpened || res.To != "4.2.11" || res.PullRequest == nil {
+		t.Fatalf("Bump = %+v, want an opened pull request", res)
+	}
+	if len(client.created) != 1 {
+		t.Fatalf("created %d pull requests, want 1", len(client.created))
+	}
+	pr := client.created[0]
+	if pr.Title != "Bump Django from 3.2.0 to 4.2.11" || pr.Head != "devdashboard/bump-django-4.2.11" || pr.Base != "main" {
+		t.Errorf("pull request = %+v", pr)
+	}
+	if !strings.Contains(pr.Body, "- `app/poetry.lock`") {
+		t.Errorf("body missing lock file:\n%s", pr.Body)
+	}
+	lock := gitCmd(t, bare, "show", "devdashboard/bump-django-4.2.11:app/poetry.lock")
+	if !strings.Contains(lock, `version = "4.2.11"`) {
+		t.Errorf("pushed lock file not updated:\n%s", lock)
+	}
+
+	// The open pull request is found instead of opening another
+	client.open = []repository.UpdatePullRequest{*res.PullRequest}
+	if res := b.Bump(context.Background(), target()); res.Status != StatusOpen || len(client.created) != 1 {
+		t.Errorf("repeated Bump = %+v, want the open pull request", res)
+	}
+}
+
+func TestBumpOutcomes(t *testing.T) {
+	bare := remote(t)
+	client := &fakeClient{cloneURL: bare}
+	newClient := func(string, repository.Config) (repository.Client, error) { return client, nil }
+	ctx := context.Background()
+
+	dry := &Bumper{Package: "django", Version: "4.2.11", DryRun: true, NewClient: newClient}
+	if res := dry.Bump(ctx, target()); res.Status != StatusPlanned {
+		t.Errorf("dry run = %+v, want planned", res)
+	}
+
+	noop := &Bumper{Package: "django", Version: "4.2.11", NewClient: newClient,
+		Command: func(_, _, _ string) ([]string, error) { return []string{"true"}, nil }}
+	if res := noop.Bump(ctx, target()); res.Status != StatusUnchanged {
+		t.Errorf("no-op updater = %+v, want unchanged", res)
+	}
+
+	short := &Bumper{Package: "django", Version: "4.2.11", NewClient: newClient,
+		Command: func(_, _, _ string) ([]string, error) {
+			return writeLock("4.1.0"), nil
+		}}
+	if res := short.Bump(ctx, target()); res.Status != StatusFailed || res.To != "4.1.0" || !strings.Contains(res.Error, "below 4.2.11") {
+		t.Errorf("updater resolving too low = %+v, want failed", res)
+	}
+
+	pipfile := target()
+	pipfile.Repo.Config.Analyzer = "pipfile"
+	if res := dry.Bump(ctx, pipfile); res.Status != StatusFailed || !strings.Contains(res.Error, "no lock file updater") {
+		t.Errorf("pipfile = %+v, want unsupported", res)
+	}
+	if len(client.created) != 0 {
+		t.Errorf("opened pull requests: %+v", client.created)
+	}
+}
+
+func TestTargets(t *testing.T) {
+	graph := func(version string) *dependencies.Graph {
+		return dependencies.BuildGraph(dependencies.EcosystemPython, map[string][]dependencies.Dependency{
+			"poetry.lock": {{Name: "django", Version: version}},
+		})
+	}
+	repos := []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "org", Repository: "old", Ref: "main", Analyzer: "poetry"}},
+		{Provider: "github", Config: config.RepoConfig{Owner: "org", Repository: "new", Ref: "main", Analyzer: "poetry"}},
+	}
+	rpt := &report.Report{Repositories: []report.RepositoryReport{
+		{Provider: "github", Owner: "org", Repository: "old", Ref: "main", Analyzer: "poetry", Graph: graph("3.2.0")},
+		{Provider: "github", Owner: "org", Repository: "new", Ref: "main", Analyzer: "poetry", Graph: graph("4.2.11")},
+	}}
+
+	targets, err := Targets(rpt, repos, "Django", "4.2.11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 1 || targets[0].Repo.Config.Repository != "old" || targets[0].Version != "3.2.0" || targets[0].Files[0] != "poetry.lock" {
+		t.Errorf("Targets = %+v, want only org/old", targets)
+	}
+	if _, err := Targets(rpt, repos, "django", "not a version"); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
+
+func TestGitEnv(t *testing.T) {
+	env := gitEnv("gitlab", config.RepoConfig{Token: "secret", Proxy: "http://proxy.example.com:3128", InsecureSkipVerify: true})
+	got := strings.Join(env[len(env)-7:], "\n")
+	for _, want := range []string{
+		"GIT_CONFIG_COUNT=3",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_KEY_1=http.proxy",
+		"GIT_CONFIG_VALUE_1=http://proxy.example.com:3128",
+		"GIT_CONFIG_KEY_2=http.sslVerify",
+		"GIT_CONFIG_VALUE_2=false",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("gitEnv missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "secret") {
+		t.Error("token should only appear base64-encoded in the extra header")
+	}
+
+	if env := gitEnv("github", config.RepoConfig{}); strings.Contains(strings.Join(env, "\n"), "GIT_CONFIG_COUNT") {
+		t.Error("expected no git configuration without token, proxy or TLS settings")
+	}
+}
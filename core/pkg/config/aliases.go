@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validatePackageAliases checks no name is empty and each former name maps to
+// a single current name that is not itself a former name. Names compare
+// case-insensitively.
+func validatePackageAliases(aliases map[string][]string) error {
+	current := make(map[string]bool, len(aliases))
+	for name := range aliases {
+		current[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	owner := make(map[string]string)
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("package alias with an empty name")
+		}
+		for _, alias := range aliases[name] {
+			key := strings.ToLower(strings.TrimSpace(alias))
+			switch {
+			case key == "":
+				return fmt.Errorf("package %s: empty alias", name)
+			case current[key]:
+				return fmt.Errorf("package %s: alias %q is itself an aliased package", name, alias)
+			case owner[key] != "" && owner[key] != name:
+				return fmt.Errorf("package %s: alias %q is already an alias of %s", name, alias, owner[key])
+			}
+			owner[key] = name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyDefaults_PackageAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases map[string][]string
+		wantErr string
+	}{
+		{"valid", map[string][]string{"acme-core": {"acme-utils", "acme-common"}, "django": {"Django-Legacy"}}, ""},
+		{"empty alias", map[string][]string{"acme-core": {" "}}, "empty alias"},
+		{"alias of two packages", map[string][]string{"acme-core": {"acme-utils"}, "acme-next": {"ACME-utils"}}, `already an alias of acme-core`},
+		{"chained", map[string][]string{"acme-core": {"acme-utils"}, "acme-utils": {"acme-old"}}, "itself an aliased package"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{PackageAliases: tt.aliases}
+			err := cfg.ApplyDefaults()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ApplyDefaults() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,16 +5,312 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
 )
 
 // Config represents the top-level configuration file structure
 type Config struct {
+	// Extends names base configuration files (shared defaults, provider
+	// settings) this file builds on, and Include files merged after it
+	// (e.g. per-team repository lists); both are relative to this file and
+	// merge as described by MergeOrder
+	Extends []string `yaml:"extends,omitempty"`
+	Include []string `yaml:"include,omitempty"`
+	// Sources lists the files LoadFromFile merged, in merge order
+	Sources []string `yaml:"-"`
+
 	Providers map[string]ProviderConfig `yaml:"providers"`
+	// Retry overrides the retry policy for transient provider API failures
+	Retry *RetryConfig `yaml:"retry,omitempty"`
+	// Budget caps the provider API requests a single report run may issue
+	Budget *BudgetConfig `yaml:"budget,omitempty"`
+	// Timeouts bound the whole run, each repository and each provider API
+	// request; command-line flags take precedence
+	Timeouts *TimeoutsConfig `yaml:"timeouts,omitempty"`
+	// MaxFileSize bounds the bytes read from any single dependency file
+	// (0 = repository.DefaultMaxFileSize, negative = unlimited)
+	MaxFileSize int64 `yaml:"maxFileSize,omitempty"`
+	// Hooks are external commands run after report generation to annotate
+	// or suppress results (see report.ExecHook)
+	Hooks []HookConfig `yaml:"hooks,omitempty"`
+	// PackageGroups names reusable package watchlists (e.g. "crypto-critical")
+	// selectable with --packages-group to narrow a report
+	PackageGroups map[string][]string `yaml:"packageGroups,omitempty"`
+	// PackageAliases maps a package's current name to the names it was
+	// formerly published under (e.g. acme-core: [acme-utils]); reports show
+	// repositories locking a former name in the current name's column
+	PackageAliases map[string][]string `yaml:"packageAliases,omitempty"`
+	// Policies are rules every repository's lock files are checked against
+	// (see report.Policy); error-severity violations fail the run
+	Policies []PolicyConfig `yaml:"policies,omitempty"`
+	// Ignores annotate or suppress accepted drift and policy violations
+	// (see IgnoreConfig)
+	Ignores []IgnoreConfig `yaml:"ignores,omitempty"`
+	// IgnoreFile is a YAML file of further ignores (see LoadIgnoreFile),
+	// relative to the configuration file
+	IgnoreFile string `yaml:"ignoreFile,omitempty"`
+	// Notifications post a summary of what changed since the previous run
+	// (new drift, new failures, newly added packages) to chat services or
+	// webhooks (see notify.Notifier)
+	Notifications []NotifierConfig `yaml:"notifications,omitempty"`
+	// Issues open, update and close tracker tickets for policy violations
+	// (see issues.Syncer)
+	Issues []IssueTrackerConfig `yaml:"issues,omitempty"`
+	// Plugins selects which registered analyzers (built-in or added with
+	// dependencies.Register) repositories may use
+	Plugins *PluginsConfig `yaml:"plugins,omitempty"`
+	// Telemetry exports OpenTelemetry traces and metrics of report runs to
+	// an OTLP collector (see telemetry.Setup)
+	Telemetry *TelemetryConfig `yaml:"telemetry,omitempty"`
+}
+
+// TelemetryConfig selects the OTLP/HTTP collector report runs export spans
+// and metrics to. An empty endpoint falls back to the standard
+// OTEL_EXPORTER_OTLP_* environment variables.
+type TelemetryConfig struct {
+	Endpoint        string            `yaml:"endpoint,omitempty"`        // Collector base URL, e.g. "http://otel-collector:4318"
+	Headers         map[string]string `yaml:"headers,omitempty"`         // Sent with every export (e.g. an API key)
+	ServiceName     string            `yaml:"serviceName,omitempty"`     // service.name resource attribute (default "devdashboard")
+	MetricsInterval time.Duration     `yaml:"metricsInterval,omitempty"` // Metric export period (default 30s)
+}
+
+// validate checks the endpoint is an http(s) URL and the interval is not
+// negative
+func (t *TelemetryConfig) validate() error {
+	if t == nil {
+		return nil
+	}
+	if err := validateHTTPURL("endpoint", t.Endpoint); err != nil {
+		return err
+	}
+	if t.MetricsInterval < 0 {
+		return fmt.Errorf("metricsInterval must not be negative, got %s", t.MetricsInterval)
+	}
+	return nil
+}
+
+// PluginsConfig restricts the analyzer types available to repositories.
+// Programs embedding devdashboard register proprietary analyzers with
+// dependencies.Register; listing them here enables them for a deployment.
+type PluginsConfig struct {
+	Analyzers []string           `yaml:"analyzers,omitempty"` // Enabled analyzer types (empty = every registered analyzer)
+	Exec      []ExecPluginConfig `yaml:"exec,omitempty"`      // External analyzer programs (see dependencies.ExecAnalyzer)
+}
+
+// ExecPluginConfig registers an external program speaking the exec analyzer
+// JSON protocol as an analyzer type
+type ExecPluginConfig struct {
+	Name      string        `yaml:"name"`                // Analyzer type repositories select, e.g. "cargo"
+	Command   []string      `yaml:"command"`             // Program and arguments; not run through a shell
+	Timeout   time.Duration `yaml:"timeout,omitempty"`   // Upper bound for one run (default 1m)
+	Ecosystem string        `yaml:"ecosystem,omitempty"` // python to compare names and versions by PyPI rules (default: exact names)
+}
+
+// Issue tracker types
+const (
+	TrackerGitHub = "github" // GitHub issues
+	TrackerJira   = "jira"   // Jira tickets
+)
+
+// IssueTrackerConfig files one ticket per repository, package and violated
+// policy in a GitHub repository or Jira project. Tokens are usually secrets,
+// so they can be read from an environment variable with TokenEnv.
+type IssueTrackerConfig struct {
+	Name        string   `yaml:"name"`                  // Identifies the tracker in logs
+	Type        string   `yaml:"type"`                  // github or jira
+	BaseURL     string   `yaml:"baseURL,omitempty"`     // GitHub Enterprise API URL; Jira site URL (required for jira)
+	Token       string   `yaml:"token,omitempty"`       // API token (github: defaults to the github provider's token)
+	TokenEnv    string   `yaml:"tokenEnv,omitempty"`    // Environment variable holding the token (used when Token is empty)
+	Labels      []string `yaml:"labels,omitempty"`      // Extra labels for created tickets
+	Tags        []string `yaml:"tags,omitempty"`        // Only repositories carrying any of these tags (empty = all)
+	MinSeverity string   `yaml:"minSeverity,omitempty"` // warning (default; every violation) or error
+
+	// Repository (github) receives every issue as owner/repo; by default
+	// each GitHub repository gets issues about its own violations
+	Repository string `yaml:"repository,omitempty"`
+
+	// Jira settings
+	Project         string `yaml:"project,omitempty"`         // Project key (required for jira)
+	User            string `yaml:"user,omitempty"`            // Account for basic auth (Jira Cloud); empty sends the token as a bearer token
+	IssueType       string `yaml:"issueType,omitempty"`       // Default "Bug"
+	CloseTransition string `yaml:"closeTransition,omitempty"` // Workflow transition closing a ticket (default "Done")
+}
+
+// Notifier types
+const (
+	NotifierSlack   = "slack"   // Slack incoming webhook
+	NotifierTeams   = "teams"   // Microsoft Teams incoming webhook
+	NotifierWebhook = "webhook" // Generic JSON POST
+	NotifierEmail   = "email"   // HTML digest sent over SMTP
+)
+
+// Email digest contents
+const (
+	DigestChanges = "changes" // What changed since the last digest; nothing is sent without changes
+	DigestReport  = "report"  // The changes followed by the full report, sent every time
+)
+
+// Notification events a notifier can subscribe to
+const (
+	EventDrift    = "drift"    // Packages that started drifting
+	EventErrors   = "errors"   // Repositories that started failing
+	EventPackages = "packages" // Tracked packages newly locked by a repository
+)
+
+// NotifierConfig routes change summaries to one channel. The URL is usually
+// a secret, so it can be read from an environment variable with URLEnv.
+type NotifierConfig struct {
+	Name     string   `yaml:"name"`               // Identifies the channel in logs
+	Type     string   `yaml:"type"`               // slack, teams, webhook or email
+	URL      string   `yaml:"url,omitempty"`      // Webhook URL
+	URLEnv   string   `yaml:"urlEnv,omitempty"`   // Environment variable holding the URL (used when URL is empty)
+	Template string   `yaml:"template,omitempty"` // Go text/template for the message (default: built-in summary; not used by email)
+	Events   []string `yaml:"events,omitempty"`   // drift, errors, packages (empty = all)
+	Tags     []string `yaml:"tags,omitempty"`     // Only changes in repositories carrying any of these tags (empty = all)
+
+	// Every batches deliveries in long-running servers: at most one per
+	// interval, covering everything that changed since the previous one.
+	// Zero delivers after every run.
+	Every time.Duration `yaml:"every,omitempty"`
+
+	// Email configures type email
+	Email *EmailConfig `yaml:"email,omitempty"`
+}
+
+// EmailConfig describes the SMTP server and recipients of an email digest.
+// The connection is upgraded with STARTTLS when the server offers it.
+type EmailConfig struct {
+	Host        string   `yaml:"host"`                  // SMTP server
+	Port        int      `yaml:"port,omitempty"`        // Default 587
+	Username    string   `yaml:"username,omitempty"`    // Enables PLAIN authentication
+	PasswordEnv string   `yaml:"passwordEnv,omitempty"` // Environment variable holding the password
+	From        string   `yaml:"from"`                  // Sender address
+	To          []string `yaml:"to"`                    // Recipient addresses
+	Subject     string   `yaml:"subject,omitempty"`     // Default "Dependency report digest"
+	Digest      string   `yaml:"digest,omitempty"`      // changes (default) or report
+}
+
+// PolicyConfig declares a version pinning, source or registry rule. A rule
+// with Version requires Package; rules with only ForbidSources or
+// AllowRegistries apply to every package unless Package narrows them.
+type PolicyConfig struct {
+	Name          string   `yaml:"name"`                    // Identifies the rule in output
+	Package       string   `yaml:"package,omitempty"`       // Package the rule applies to (empty = every package)
+	Version       string   `yaml:"version,omitempty"`       // Allowed locked versions, e.g. ">=4.2" or ">=4.2,<6"
+	ForbidSources []string `yaml:"forbidSources,omitempty"` // Sources not allowed, e.g. git, path, url
+	Tags          []string `yaml:"tags,omitempty"`          // Only repositories carrying any of these tags (empty = all)
+	Severity      string   `yaml:"severity,omitempty"`      // error (default; fails the run) or warning
+
+	// AllowRegistries lists the package index URLs packages may be resolved
+	// from, e.g. https://pypi.acme.example/simple; a package whose lock file
+	// records any other registry (or a path below none of these) violates
+	// the rule
+	AllowRegistries []string `yaml:"allowRegistries,omitempty"`
+}
+
+// enabled reports whether repositories may use the analyzer type
+func (p *PluginsConfig) enabled(analyzer string) bool {
+	if p == nil || len(p.Analyzers) == 0 {
+		return true
+	}
+	for _, a := range p.Analyzers {
+		if strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(analyzer)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate registers the exec plugins and checks every enabled analyzer
+// type is registered
+func (p *PluginsConfig) validate() error {
+	if p == nil {
+		return nil
+	}
+	for i, e := range p.Exec {
+		switch dependencies.Ecosystem(e.Ecosystem) {
+		case dependencies.EcosystemUnknown, dependencies.EcosystemPython:
+		default:
+			return fmt.Errorf("exec plugin at index %d: unknown ecosystem %q (expected python or empty)", i, e.Ecosystem)
+		}
+		err := dependencies.RegisterExec(&dependencies.ExecAnalyzer{
+			AnalyzerName:     e.Name,
+			Command:          e.Command,
+			Timeout:          e.Timeout,
+			PackageEcosystem: dependencies.Ecosystem(e.Ecosystem),
+		})
+		if err != nil {
+			return fmt.Errorf("exec plugin at index %d: %w", i, err)
+		}
+	}
+	supported := dependencies.SupportedAnalyzers()
+	for _, a := range p.Analyzers {
+		if !slices.Contains(supported, strings.ToLower(strings.TrimSpace(a))) {
+			return fmt.Errorf("unknown analyzer %q (registered: %s)", a, strings.Join(supported, ", "))
+		}
+	}
+	return nil
+}
+
+// HookConfig describes an exec-based report post-processing hook. The command
+// receives the report as JSON on stdin and may print annotations and
+// suppressions as JSON on stdout.
+type HookConfig struct {
+	Name    string        `yaml:"name,omitempty"`    // Label used in logs (defaults to the command)
+	Command []string      `yaml:"command"`           // Program and arguments; not run through a shell
+	Timeout time.Duration `yaml:"timeout,omitempty"` // Upper bound for one run (default 30s)
+}
+
+// RetryConfig controls retries of transient provider API failures (5xx, 429,
+// network timeouts). Zero-valued fields fall back to built-in defaults.
+type RetryConfig struct {
+	MaxAttempts          int           `yaml:"maxAttempts,omitempty"`          // Total attempts per request (1 disables retries)
+	InitialBackoff       time.Duration `yaml:"initialBackoff,omitempty"`       // Delay before the first retry (e.g. "500ms")
+	MaxBackoff           time.Duration `yaml:"maxBackoff,omitempty"`           // Upper bound for the exponential backoff
+	RetryableStatusCodes []int         `yaml:"retryableStatusCodes,omitempty"` // HTTP statuses treated as transient
+}
+
+// BudgetConfig limits provider API requests (retried attempts included) per
+// report run, so a misconfigured repository cannot exhaust an organization's
+// rate limit. Repositories that hit a budget fail with the "budget" error
+// category while the rest of the report completes. Zero means unlimited.
+type BudgetConfig struct {
+	PerRepository int            `yaml:"perRepository,omitempty"` // Requests per repository
+	Providers     map[string]int `yaml:"providers,omitempty"`     // Requests per provider across all repositories (e.g. github: 2000)
+}
+
+// TimeoutsConfig is the timeout hierarchy of a report run: the run's deadline
+// bounds every repository, whose deadline bounds every API request it sends.
+// Zero keeps the caller's default (the --timeout and --repo-timeout flags;
+// no per-request limit).
+type TimeoutsConfig struct {
+	Run        time.Duration `yaml:"run,omitempty"`        // Whole report run (e.g. "10m")
+	Repository time.Duration `yaml:"repository,omitempty"` // Analysis of one repository, all its requests included
+	Request    time.Duration `yaml:"request,omitempty"`    // One provider API attempt; retried like a network timeout
+}
+
+// validate checks no timeout is negative
+func (t *TimeoutsConfig) validate() error {
+	if t == nil {
+		return nil
+	}
+	for _, f := range []struct {
+		name  string
+		value time.Duration
+	}{{"run", t.Run}, {"repository", t.Repository}, {"request", t.Request}} {
+		if f.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %s", f.name, f.value)
+		}
+	}
+	return nil
 }
 
 // ProviderConfig contains configuration for a specific repository provider
@@ -25,28 +321,107 @@ type ProviderConfig struct {
 
 // RepoDefaults contains default values that can be inherited by repositories
 type RepoDefaults struct {
-	Token      string   `yaml:"token"`
-	Owner      string   `yaml:"owner"`
-	Repository string   `yaml:"repository"`
-	Ref        string   `yaml:"ref"`
-	Paths      []string `yaml:"paths"`
-	Packages   []string `yaml:"packages"`
-	Analyzer   string   `yaml:"analyzer"`
+	Token       string   `yaml:"token"`
+	BaseURL     string   `yaml:"baseURL,omitempty"`
+	Enterprise  bool     `yaml:"enterprise,omitempty"`
+	APIPrefix   string   `yaml:"apiPrefix,omitempty"`
+	Owner       string   `yaml:"owner"`
+	Repository  string   `yaml:"repository"`
+	Ref         string   `yaml:"ref"`
+	Paths       []string `yaml:"paths"`
+	Packages    []string `yaml:"packages"`
+	Analyzer    string   `yaml:"analyzer"`
+	UpdatePRs   bool     `yaml:"updatePRs"`
+	Constraints bool     `yaml:"constraints"`
+	Tags        []string `yaml:"tags,omitempty"`
+	// ExcludePaths are inherited by repositories that set none; see RepoConfig
+	ExcludePaths []string `yaml:"excludePaths,omitempty"`
+	// Network settings shared by the provider's repositories; see RepoConfig
+	Proxy              string `yaml:"proxy,omitempty"`
+	CAFile             string `yaml:"caFile,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty"`
 }
 
 // RepoConfig contains configuration for a single repository
 type RepoConfig struct {
-	Token      string   `yaml:"token"`
-	Owner      string   `yaml:"owner"`
-	Repository string   `yaml:"repository"`
-	Ref        string   `yaml:"ref"`
-	Paths      []string `yaml:"paths"`
-	Packages   []string `yaml:"packages"`
-	Analyzer   string   `yaml:"analyzer"`
+	Token string `yaml:"token"`
+	// Owner is the user, organization or (gitlab) group path owning the
+	// repository, e.g. group/subgroup/team (see ValidateNamespace)
+	Owner string `yaml:"owner"`
+	// Repository is the repository name, or its full path
+	// (group/subgroup/project) when Owner is not set
+	Repository string `yaml:"repository"`
+	Ref        string `yaml:"ref"`
+	// Refs analyzes the repository at each of several refs (e.g. main and
+	// release/1.x) to compare them; ApplyDefaults expands it into one
+	// repository per ref. Mutually exclusive with Ref.
+	Refs     []string `yaml:"refs,omitempty"`
+	Paths    []string `yaml:"paths"`
+	Packages []string `yaml:"packages"`
+	Analyzer string   `yaml:"analyzer"`
+	// BaseURL points the provider client at a self-hosted instance (GitHub
+	// Enterprise API URL, self-hosted GitLab); empty uses the public service
+	BaseURL string `yaml:"baseURL,omitempty"`
+	// Enterprise marks BaseURL as a GitHub Enterprise Server; a baseURL
+	// naming a host other than github.com is detected as one without it
+	Enterprise bool `yaml:"enterprise,omitempty"`
+	// APIPrefix is the REST API path below BaseURL on GitHub Enterprise
+	// Server; empty uses /api/v3
+	APIPrefix string `yaml:"apiPrefix,omitempty"`
+	// UpdatePRs enables querying open Dependabot/Renovate PRs for tracked packages
+	UpdatePRs bool `yaml:"updatePRs"`
+	// Constraints enables reading manifests (pyproject.toml, Pipfile) next to
+	// lock files to report declared constraints alongside resolved versions
+	Constraints bool `yaml:"constraints"`
+	// Tags label the repository for grouping and --tag filtering (e.g.
+	// "team-payments", "deprecated"); default tags are added to these
+	Tags []string `yaml:"tags,omitempty"`
+	// Proxy is the URL of an HTTP(S) proxy for provider API requests; empty
+	// uses the HTTPS_PROXY/NO_PROXY environment variables
+	Proxy string `yaml:"proxy,omitempty"`
+	// CAFile is a PEM bundle of certificate authorities trusted in addition
+	// to the system ones
+	CAFile string `yaml:"caFile,omitempty"`
+	// InsecureSkipVerify disables TLS certificate verification (test
+	// instances only)
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty"`
+	// ExcludePaths are glob patterns of dependency files to skip, e.g.
+	// "tests/fixtures" or "examples/*" for vendored or example lock files
+	// (see dependencies.Config.Excluded)
+	ExcludePaths []string `yaml:"excludePaths,omitempty"`
+	// Canonical marks the repository as a mirror of a project tracked under
+	// another provider: an ID shared by all of the project's mirrors (e.g.
+	// "acme/api"), or the remote URL of the repository it mirrors. Reports
+	// merge mirrors at the same ref into one entry.
+	Canonical string `yaml:"canonical,omitempty"`
 }
 
-// LoadFromFile reads a YAML configuration file and returns the parsed Config
+// validateHTTPURL checks that a configured URL field is empty or an absolute
+// http(s) URL
+func validateHTTPURL(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s %q must be an absolute http or https URL", field, value)
+	}
+	return nil
+}
+
+// LoadFromFile reads a YAML configuration file and returns the parsed Config.
+// Errors are classified as exitcode.ConfigError.
 func LoadFromFile(filename string) (*Config, error) {
+	cfg, err := loadFromFile(filename, Overrides{})
+	if err != nil {
+		return nil, exitcode.New(exitcode.ConfigError, err)
+	}
+	return cfg, nil
+}
+
+// loadFromFile implements LoadFromFileWithOverrides without error
+// classification
+func loadFromFile(filename string, overrides Overrides) (*Config, error) {
 	// Sanitize and validate filename to mitigate G304 (file inclusion via variable).
 	// Allow absolute paths (needed for temp test files) but still normalize and
 	// enforce extension and traversal protections.
@@ -63,14 +438,12 @@ func LoadFromFile(filename string) (*Config, error) {
 		return nil, fmt.Errorf("unsupported config file extension: %s", ext)
 	}
 
-	data, err := os.ReadFile(cleaned)
+	config, err := loadLayered(cleaned)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
-
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	if err := overrides.apply(config); err != nil {
+		return nil, err
 	}
 
 	// Apply defaults to repositories
@@ -78,11 +451,27 @@ func LoadFromFile(filename string) (*Config, error) {
 		return nil, fmt.Errorf("failed to apply defaults: %w", err)
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // ApplyDefaults applies default values to repositories that don't have them set
 func (c *Config) ApplyDefaults() error {
+	if err := c.Plugins.validate(); err != nil {
+		return fmt.Errorf("plugins: %w", err)
+	}
+	if err := c.Timeouts.validate(); err != nil {
+		return fmt.Errorf("timeouts: %w", err)
+	}
+	if err := c.Telemetry.validate(); err != nil {
+		return fmt.Errorf("telemetry: %w", err)
+	}
+	if err := validateIgnores(c.Ignores); err != nil {
+		return err
+	}
+	if err := validatePackageAliases(c.PackageAliases); err != nil {
+		return fmt.Errorf("packageAliases: %w", err)
+	}
+
 	for providerName, providerConfig := range c.Providers {
 		for i := range providerConfig.Repositories {
 			repo := &providerConfig.Repositories[i]
@@ -92,21 +481,53 @@ func (c *Config) ApplyDefaults() error {
 			if repo.Token == "" {
 				repo.Token = defaults.Token
 			}
+			if repo.BaseURL == "" {
+				repo.BaseURL = defaults.BaseURL
+			}
+			if !repo.Enterprise {
+				repo.Enterprise = defaults.Enterprise
+			}
+			if repo.APIPrefix == "" {
+				repo.APIPrefix = defaults.APIPrefix
+			}
+			splitProjectPath(repo)
 			if repo.Owner == "" {
 				repo.Owner = defaults.Owner
 			}
-			if repo.Ref == "" {
+			if repo.Ref != "" && len(repo.Refs) > 0 {
+				return fmt.Errorf("provider %s: repository at index %d sets both 'ref' and 'refs'", providerName, i)
+			}
+			if repo.Ref == "" && len(repo.Refs) == 0 {
 				repo.Ref = defaults.Ref
 			}
 			if len(repo.Paths) == 0 {
 				repo.Paths = defaults.Paths
 			}
+			if len(repo.ExcludePaths) == 0 {
+				repo.ExcludePaths = defaults.ExcludePaths
+			}
 			if len(repo.Packages) == 0 {
 				repo.Packages = defaults.Packages
 			}
 			if repo.Analyzer == "" {
 				repo.Analyzer = defaults.Analyzer
 			}
+			if !repo.UpdatePRs {
+				repo.UpdatePRs = defaults.UpdatePRs
+			}
+			if !repo.Constraints {
+				repo.Constraints = defaults.Constraints
+			}
+			repo.Tags = MergeTags(defaults.Tags, repo.Tags)
+			if repo.Proxy == "" {
+				repo.Proxy = defaults.Proxy
+			}
+			if repo.CAFile == "" {
+				repo.CAFile = defaults.CAFile
+			}
+			if !repo.InsecureSkipVerify {
+				repo.InsecureSkipVerify = defaults.InsecureSkipVerify
+			}
 
 			// Validate required fields
 			if repo.Owner == "" {
@@ -118,13 +539,176 @@ func (c *Config) ApplyDefaults() error {
 			if repo.Analyzer == "" {
 				return fmt.Errorf("provider %s: repository at index %d missing required field 'analyzer'", providerName, i)
 			}
+			if err := ValidateNamespace(providerName, repo.Owner, repo.Repository); err != nil {
+				return fmt.Errorf("provider %s: repository at index %d: %w", providerName, i, err)
+			}
+			if err := validateHTTPURL("baseURL", repo.BaseURL); err != nil {
+				return fmt.Errorf("provider %s: repository at index %d: %w", providerName, i, err)
+			}
+			if (repo.Enterprise || repo.APIPrefix != "") && (providerName != "github" || repo.BaseURL == "") {
+				return fmt.Errorf("provider %s: repository at index %d: enterprise and apiPrefix need the github provider and a baseURL", providerName, i)
+			}
+			if err := validateHTTPURL("proxy", repo.Proxy); err != nil {
+				return fmt.Errorf("provider %s: repository at index %d: %w", providerName, i, err)
+			}
+			if err := dependencies.ValidateExcludePaths(repo.ExcludePaths); err != nil {
+				return fmt.Errorf("provider %s: repository at index %d: excludePaths: %w", providerName, i, err)
+			}
+			if !c.Plugins.enabled(repo.Analyzer) {
+				return fmt.Errorf("provider %s: repository at index %d uses analyzer %q, which is not enabled in plugins.analyzers", providerName, i, repo.Analyzer)
+			}
+			for j, ref := range repo.Refs {
+				if ref == "" || slices.Contains(repo.Refs[:j], ref) {
+					return fmt.Errorf("provider %s: repository at index %d: refs must be distinct and not empty", providerName, i)
+				}
+			}
 		}
+		providerConfig.Repositories = expandRefs(providerConfig.Repositories)
 		c.Providers[providerName] = providerConfig
 	}
 
+	for i, hook := range c.Hooks {
+		if len(hook.Command) == 0 || hook.Command[0] == "" {
+			return fmt.Errorf("hook at index %d missing required field 'command'", i)
+		}
+	}
+
+	for i, policy := range c.Policies {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("policy at index %d: %w", i, err)
+		}
+	}
+
+	for i, n := range c.Notifications {
+		if err := n.validate(); err != nil {
+			return fmt.Errorf("notification at index %d: %w", i, err)
+		}
+	}
+
+	for i, t := range c.Issues {
+		if err := t.validate(); err != nil {
+			return fmt.Errorf("issue tracker at index %d: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
+// expandRefs replaces each repository listing several refs with one copy per
+// ref, keeping the configured order
+func expandRefs(repos []RepoConfig) []RepoConfig {
+	out := make([]RepoConfig, 0, len(repos))
+	for _, repo := range repos {
+		if len(repo.Refs) == 0 {
+			out = append(out, repo)
+			continue
+		}
+		for _, ref := range repo.Refs {
+			r := repo
+			r.Ref, r.Refs = ref, nil
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// validate checks a policy names a rule it can enforce
+func (p PolicyConfig) validate() error {
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("missing required field 'name'")
+	case p.Version == "" && len(p.ForbidSources) == 0 && len(p.AllowRegistries) == 0:
+		return fmt.Errorf("policy %s needs 'version', 'forbidSources' or 'allowRegistries'", p.Name)
+	case p.Version != "" && p.Package == "":
+		return fmt.Errorf("policy %s: 'version' requires 'package'", p.Name)
+	}
+	for _, registry := range p.AllowRegistries {
+		if err := validateHTTPURL("allowRegistries entry", registry); err != nil || registry == "" {
+			return fmt.Errorf("policy %s: allowRegistries entries must be absolute http or https URLs, got %q", p.Name, registry)
+		}
+	}
+	switch p.Severity {
+	case "", "error", "warning":
+		return nil
+	default:
+		return fmt.Errorf("policy %s: unknown severity %q (expected error or warning)", p.Name, p.Severity)
+	}
+}
+
+// validate checks a notifier has a known type, a destination and known events
+func (n NotifierConfig) validate() error {
+	if n.Name == "" {
+		return fmt.Errorf("missing required field 'name'")
+	}
+	switch n.Type {
+	case NotifierSlack, NotifierTeams, NotifierWebhook:
+		if n.URL == "" && n.URLEnv == "" {
+			return fmt.Errorf("notifier %s needs 'url' or 'urlEnv'", n.Name)
+		}
+	case NotifierEmail:
+		if err := n.Email.validate(); err != nil {
+			return fmt.Errorf("notifier %s: %w", n.Name, err)
+		}
+		if n.Template != "" {
+			return fmt.Errorf("notifier %s: 'template' is not supported for email", n.Name)
+		}
+	default:
+		return fmt.Errorf("notifier %s: unknown type %q (expected slack, teams, webhook or email)", n.Name, n.Type)
+	}
+	for _, ev := range n.Events {
+		switch ev {
+		case EventDrift, EventErrors, EventPackages:
+		default:
+			return fmt.Errorf("notifier %s: unknown event %q (expected drift, errors or packages)", n.Name, ev)
+		}
+	}
+	return nil
+}
+
+// validate checks an email notifier names a server, a sender and recipients
+func (e *EmailConfig) validate() error {
+	switch {
+	case e == nil || e.Host == "":
+		return fmt.Errorf("email needs 'host'")
+	case e.From == "" || len(e.To) == 0:
+		return fmt.Errorf("email needs 'from' and 'to'")
+	}
+	switch e.Digest {
+	case "", DigestChanges, DigestReport:
+		return nil
+	default:
+		return fmt.Errorf("unknown digest %q (expected changes or report)", e.Digest)
+	}
+}
+
+// validate checks a tracker has a known type and the settings it needs
+func (t IssueTrackerConfig) validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("missing required field 'name'")
+	}
+	switch t.Type {
+	case TrackerGitHub:
+		if t.Repository != "" && !strings.Contains(t.Repository, "/") {
+			return fmt.Errorf("tracker %s: repository must be owner/repo", t.Name)
+		}
+	case TrackerJira:
+		if t.BaseURL == "" || t.Project == "" {
+			return fmt.Errorf("tracker %s: jira needs 'baseURL' and 'project'", t.Name)
+		}
+		if t.Token == "" && t.TokenEnv == "" {
+			return fmt.Errorf("tracker %s needs 'token' or 'tokenEnv'", t.Name)
+		}
+	default:
+		return fmt.Errorf("tracker %s: unknown type %q (expected github or jira)", t.Name, t.Type)
+	}
+	switch t.MinSeverity {
+	case "", "error", "warning":
+		return nil
+	default:
+		return fmt.Errorf("tracker %s: unknown minSeverity %q (expected error or warning)", t.Name, t.MinSeverity)
+	}
+}
+
 // GetAllRepos returns a flat list of all repositories with their provider name
 func (c *Config) GetAllRepos() []RepoWithProvider {
 	var repos []RepoWithProvider
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadFromFile(t *testing.T) {
@@ -234,6 +236,38 @@ providers:
 				}
 			},
 		},
+		{
+			name: "retry policy section",
+			content: `
+retry:
+  maxAttempts: 5
+  initialBackoff: 250ms
+  maxBackoff: 30s
+  retryableStatusCodes: [429, 503]
+providers:
+  github:
+    repositories:
+      - owner: "owner"
+        repository: "repo"
+        analyzer: "poetry"
+`,
+			wantErr:     false,
+			description: "Should parse retry settings with duration strings",
+			validateFn: func(t *testing.T, cfg *Config) {
+				if cfg.Retry == nil {
+					t.Fatal("Expected retry section to be parsed")
+				}
+				if cfg.Retry.MaxAttempts != 5 {
+					t.Errorf("Expected maxAttempts 5, got %d", cfg.Retry.MaxAttempts)
+				}
+				if cfg.Retry.InitialBackoff != 250*time.Millisecond || cfg.Retry.MaxBackoff != 30*time.Second {
+					t.Errorf("Unexpected backoff values: %v / %v", cfg.Retry.InitialBackoff, cfg.Retry.MaxBackoff)
+				}
+				if len(cfg.Retry.RetryableStatusCodes) != 2 {
+					t.Errorf("Expected 2 retryable status codes, got %v", cfg.Retry.RetryableStatusCodes)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -332,15 +366,18 @@ func TestApplyDefaults(t *testing.T) {
 				Providers: map[string]ProviderConfig{
 					"github": {
 						Default: RepoDefaults{
-							Token:    "token",
-							Owner:    "owner",
-							Ref:      "main",
-							Paths:    []string{"src"},
-							Packages: []string{"pkg1"},
-							Analyzer: "poetry",
+							Token:       "token",
+							Owner:       "owner",
+							Ref:         "main",
+							Paths:       []string{"src"},
+							Packages:    []string{"pkg1"},
+							Analyzer:    "poetry",
+							UpdatePRs:   true,
+							Constraints: true,
+							Tags:        []string{"team-payments"},
 						},
 						Repositories: []RepoConfig{
-							{Repository: "repo1"},
+							{Repository: "repo1", Tags: []string{"deprecated"}},
 						},
 					},
 				},
@@ -348,6 +385,15 @@ func TestApplyDefaults(t *testing.T) {
 			wantErr: false,
 			check: func(t *testing.T, cfg *Config) {
 				repo := cfg.Providers["github"].Repositories[0]
+				if !repo.UpdatePRs {
+					t.Error("UpdatePRs not applied")
+				}
+				if !repo.Constraints {
+					t.Error("Constraints not applied")
+				}
+				if fmt.Sprint(repo.Tags) != "[team-payments deprecated]" {
+					t.Errorf("Tags not merged with defaults: %v", repo.Tags)
+				}
 				if repo.Token != "token" {
 					t.Error("Token not applied")
 				}
@@ -402,6 +448,56 @@ func TestApplyDefaults(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "expands refs into one repository per ref",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"github": {
+						Default: RepoDefaults{Owner: "owner", Ref: "main", Analyzer: "poetry"},
+						Repositories: []RepoConfig{
+							{Repository: "api", Refs: []string{"main", "release/1.x"}},
+							{Repository: "web"},
+						},
+					},
+				},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				repos := cfg.Providers["github"].Repositories
+				if len(repos) != 3 {
+					t.Fatalf("expected 3 repositories, got %d", len(repos))
+				}
+				if repos[0].Ref != "main" || repos[1].Ref != "release/1.x" || repos[1].Repository != "api" || repos[2].Ref != "main" {
+					t.Errorf("unexpected expansion: %+v", repos)
+				}
+				if repos[0].Refs != nil || repos[1].Owner != "owner" {
+					t.Errorf("expanded repository not normalized: %+v", repos[1])
+				}
+			},
+		},
+		{
+			name: "error on ref and refs",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"github": {
+						Default:      RepoDefaults{Owner: "owner", Analyzer: "poetry"},
+						Repositories: []RepoConfig{{Repository: "api", Ref: "main", Refs: []string{"dev"}}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error on duplicate refs",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"github": {
+						Default:      RepoDefaults{Owner: "owner", Analyzer: "poetry"},
+						Repositories: []RepoConfig{{Repository: "api", Refs: []string{"main", "main"}}},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "error on missing owner",
 			config: &Config{
@@ -435,6 +531,300 @@ func TestApplyDefaults(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "per-provider and per-repository baseURL",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"gitlab": {
+						Default: RepoDefaults{
+							Token:    "internal-token",
+							BaseURL:  "https://gitlab.example.com",
+							Owner:    "platform",
+							Analyzer: "poetry",
+						},
+						Repositories: []RepoConfig{
+							{Repository: "internal-api"},
+							{Repository: "public-lib", BaseURL: "https://gitlab.com", Token: "public-token"},
+						},
+					},
+				},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				repos := cfg.Providers["gitlab"].Repositories
+				if repos[0].BaseURL != "https://gitlab.example.com" || repos[0].Token != "internal-token" {
+					t.Errorf("defaults not applied: %+v", repos[0])
+				}
+				if repos[1].BaseURL != "https://gitlab.com" || repos[1].Token != "public-token" {
+					t.Errorf("repository overrides replaced by defaults: %+v", repos[1])
+				}
+			},
+		},
+		{
+			name: "error on relative baseURL",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"gitlab": {
+						Default: RepoDefaults{Owner: "owner", Analyzer: "poetry", BaseURL: "gitlab.example.com"},
+						Repositories: []RepoConfig{
+							{Repository: "repo1"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "applies provider network defaults",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"gitlab": {
+						Default: RepoDefaults{
+							Owner:              "owner",
+							Analyzer:           "poetry",
+							Proxy:              "http://proxy.example.com:3128",
+							CAFile:             "/etc/ssl/internal-ca.pem",
+							InsecureSkipVerify: true,
+						},
+						Repositories: []RepoConfig{
+							{Repository: "repo1"},
+							{Repository: "repo2", Proxy: "https://other-proxy.example.com"},
+						},
+					},
+				},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				repos := cfg.Providers["gitlab"].Repositories
+				if repos[0].Proxy != "http://proxy.example.com:3128" || repos[0].CAFile != "/etc/ssl/internal-ca.pem" || !repos[0].InsecureSkipVerify {
+					t.Errorf("network defaults not applied: %+v", repos[0])
+				}
+				if repos[1].Proxy != "https://other-proxy.example.com" {
+					t.Errorf("repository proxy replaced by default: %q", repos[1].Proxy)
+				}
+			},
+		},
+		{
+			name: "error on invalid proxy",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"github": {
+						Default: RepoDefaults{Owner: "owner", Analyzer: "poetry", Proxy: "proxy.example.com:3128"},
+						Repositories: []RepoConfig{
+							{Repository: "repo1"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inherits exclude paths",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"github": {
+						Default: RepoDefaults{Owner: "owner", Analyzer: "poetry", ExcludePaths: []string{"tests/fixtures"}},
+						Repositories: []RepoConfig{
+							{Repository: "repo1"},
+							{Repository: "repo2", ExcludePaths: []string{"examples/*"}},
+						},
+					},
+				},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				repos := cfg.Providers["github"].Repositories
+				if fmt.Sprint(repos[0].ExcludePaths) != "[tests/fixtures]" {
+					t.Errorf("default exclude paths not applied: %v", repos[0].ExcludePaths)
+				}
+				if fmt.Sprint(repos[1].ExcludePaths) != "[examples/*]" {
+					t.Errorf("repository exclude paths replaced by default: %v", repos[1].ExcludePaths)
+				}
+			},
+		},
+		{
+			name: "error on invalid exclude pattern",
+			config: &Config{
+				Providers: map[string]ProviderConfig{
+					"github": {
+						Default: RepoDefaults{Owner: "owner", Analyzer: "poetry"},
+						Repositories: []RepoConfig{
+							{Repository: "repo1", ExcludePaths: []string{"fixtures/[a-"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "valid timeouts",
+			config: &Config{Timeouts: &TimeoutsConfig{Run: 10 * time.Minute, Repository: 45 * time.Second, Request: 10 * time.Second}},
+		},
+		{
+			name:    "error on negative timeout",
+			config:  &Config{Timeouts: &TimeoutsConfig{Request: -time.Second}},
+			wantErr: true,
+		},
+		{
+			name:   "valid telemetry",
+			config: &Config{Telemetry: &TelemetryConfig{Endpoint: "http://otel-collector:4318", MetricsInterval: time.Minute}},
+		},
+		{
+			name:    "error on non-URL telemetry endpoint",
+			config:  &Config{Telemetry: &TelemetryConfig{Endpoint: "otel-collector:4318"}},
+			wantErr: true,
+		},
+		{
+			name:    "error on negative metrics interval",
+			config:  &Config{Telemetry: &TelemetryConfig{MetricsInterval: -time.Second}},
+			wantErr: true,
+		},
+		{
+			name: "error on hook without command",
+			config: &Config{
+				Hooks: []HookConfig{{Name: "cmdb"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid policies",
+			config: &Config{
+				Policies: []PolicyConfig{
+					{Name: "django-lts", Package: "django", Version: ">=4.2"},
+					{Name: "no-git", ForbidSources: []string{"git"}, Tags: []string{"prod"}, Severity: "warning"},
+					{Name: "registries", AllowRegistries: []string{"https://pypi.acme.example/simple"}},
+				},
+			},
+		},
+		{
+			name:    "error on policy without a rule",
+			config:  &Config{Policies: []PolicyConfig{{Name: "empty", Package: "django"}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on registry allowlist entry that is not a URL",
+			config:  &Config{Policies: []PolicyConfig{{Name: "registries", AllowRegistries: []string{"pypi.acme.example"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on version policy without package",
+			config:  &Config{Policies: []PolicyConfig{{Name: "all", Version: ">=1"}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on unknown policy severity",
+			config:  &Config{Policies: []PolicyConfig{{Name: "p", Package: "django", Version: ">=4.2", Severity: "fatal"}}},
+			wantErr: true,
+		},
+		{
+			name: "valid notifications",
+			config: &Config{
+				Notifications: []NotifierConfig{
+					{Name: "chat", Type: NotifierSlack, URLEnv: "SLACK_WEBHOOK"},
+					{Name: "ops", Type: NotifierWebhook, URL: "https://example.com/hook", Events: []string{EventErrors}, Tags: []string{"prod"}},
+				},
+			},
+		},
+		{
+			name:    "error on notifier without url",
+			config:  &Config{Notifications: []NotifierConfig{{Name: "chat", Type: NotifierTeams}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on unknown notifier type",
+			config:  &Config{Notifications: []NotifierConfig{{Name: "chat", Type: "irc", URL: "https://example.com"}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on unknown notification event",
+			config:  &Config{Notifications: []NotifierConfig{{Name: "chat", Type: NotifierSlack, URL: "https://example.com", Events: []string{"drifts"}}}},
+			wantErr: true,
+		},
+		{
+			name: "valid email notifier",
+			config: &Config{Notifications: []NotifierConfig{{Name: "digest", Type: NotifierEmail, Every: 24 * time.Hour,
+				Email: &EmailConfig{Host: "smtp.example.com", From: "dd@example.com", To: []string{"team@example.com"}, Digest: DigestReport}}}},
+		},
+		{
+			name:    "error on email notifier without recipients",
+			config:  &Config{Notifications: []NotifierConfig{{Name: "digest", Type: NotifierEmail, Email: &EmailConfig{Host: "smtp.example.com", From: "dd@example.com"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on email notifier without email section",
+			config:  &Config{Notifications: []NotifierConfig{{Name: "digest", Type: NotifierEmail}}},
+			wantErr: true,
+		},
+		{
+			name: "valid issue trackers",
+			config: &Config{Issues: []IssueTrackerConfig{
+				{Name: "gh", Type: TrackerGitHub, Repository: "org/deps", Labels: []string{"dependencies"}},
+				{Name: "jira", Type: TrackerJira, BaseURL: "https://example.atlassian.net", Project: "OPS", TokenEnv: "JIRA_TOKEN", MinSeverity: "error"},
+			}},
+		},
+		{
+			name:    "error on github tracker repository without owner",
+			config:  &Config{Issues: []IssueTrackerConfig{{Name: "gh", Type: TrackerGitHub, Repository: "deps"}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on jira tracker without project",
+			config:  &Config{Issues: []IssueTrackerConfig{{Name: "jira", Type: TrackerJira, BaseURL: "https://example.atlassian.net", Token: "t"}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on unknown tracker minSeverity",
+			config:  &Config{Issues: []IssueTrackerConfig{{Name: "gh", Type: TrackerGitHub, MinSeverity: "fatal"}}},
+			wantErr: true,
+		},
+		{
+			name: "plugins enable the repositories' analyzers",
+			config: &Config{
+				Plugins: &PluginsConfig{Analyzers: []string{"Poetry", "uvlock"}},
+				Providers: map[string]ProviderConfig{
+					"github": {Repositories: []RepoConfig{{Owner: "org", Repository: "repo1", Analyzer: "poetry"}}},
+				},
+			},
+		},
+		{
+			name: "error on analyzer not enabled in plugins",
+			config: &Config{
+				Plugins: &PluginsConfig{Analyzers: []string{"uvlock"}},
+				Providers: map[string]ProviderConfig{
+					"github": {Repositories: []RepoConfig{{Owner: "org", Repository: "repo1", Analyzer: "poetry"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error on unregistered plugin analyzer",
+			config:  &Config{Plugins: &PluginsConfig{Analyzers: []string{"cargo"}}},
+			wantErr: true,
+		},
+		{
+			name: "exec plugins register analyzers",
+			config: &Config{
+				Plugins: &PluginsConfig{
+					Analyzers: []string{"poetry", "cargo-lock"},
+					Exec:      []ExecPluginConfig{{Name: "cargo-lock", Command: []string{"cargo-lock-analyzer"}}},
+				},
+				Providers: map[string]ProviderConfig{
+					"github": {Repositories: []RepoConfig{{Owner: "org", Repository: "repo1", Analyzer: "cargo-lock"}}},
+				},
+			},
+		},
+		{
+			name:    "error on exec plugin shadowing a built-in analyzer",
+			config:  &Config{Plugins: &PluginsConfig{Exec: []ExecPluginConfig{{Name: "poetry", Command: []string{"poetry-analyzer"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on exec plugin without command",
+			config:  &Config{Plugins: &PluginsConfig{Exec: []ExecPluginConfig{{Name: "gomod"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "error on exec plugin with unknown ecosystem",
+			config:  &Config{Plugins: &PluginsConfig{Exec: []ExecPluginConfig{{Name: "gomod", Command: []string{"gomod-analyzer"}, Ecosystem: "go"}}}},
+			wantErr: true,
+		},
 		{
 			name: "error on missing analyzer",
 			config: &Config{
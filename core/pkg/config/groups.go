@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+)
+
+// PackageGroupNames returns the configured package group names, sorted
+func (c *Config) PackageGroupNames() []string {
+	names := make([]string, 0, len(c.PackageGroups))
+	for name := range c.PackageGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolvePackageGroups returns the union of the named groups' packages in
+// first-seen order. Unknown names are an exitcode.ConfigError listing the
+// groups that do exist.
+func (c *Config) ResolvePackageGroups(names []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var packages []string
+	for _, name := range names {
+		group, ok := c.PackageGroups[name]
+		if !ok {
+			available := "none defined"
+			if len(c.PackageGroups) > 0 {
+				available = strings.Join(c.PackageGroupNames(), ", ")
+			}
+			return nil, exitcode.New(exitcode.ConfigError,
+				fmt.Errorf("unknown package group %q (available: %s)", name, available))
+		}
+		for _, pkg := range group {
+			if !seen[pkg] {
+				seen[pkg] = true
+				packages = append(packages, pkg)
+			}
+		}
+	}
+	if len(names) > 0 && len(packages) == 0 {
+		return nil, exitcode.New(exitcode.ConfigError, errors.New("selected package groups are empty"))
+	}
+	return packages, nil
+}
+
+// FilterPackages narrows each repository's tracked packages to those in
+// packages, comparing ecosystem-normalized names. Repositories left with no
+// tracked packages are dropped.
+func FilterPackages(repos []RepoWithProvider, packages []string) []RepoWithProvider {
+	out := make([]RepoWithProvider, 0, len(repos))
+	for _, repo := range repos {
+		eco := dependencies.EcosystemForAnalyzer(repo.Config.Analyzer)
+		wanted := make(map[string]bool, len(packages))
+		for _, pkg := range packages {
+			wanted[dependencies.NormalizeName(eco, pkg)] = true
+		}
+		var kept []string
+		for _, pkg := range repo.Config.Packages {
+			if wanted[dependencies.NormalizeName(eco, pkg)] {
+				kept = append(kept, pkg)
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		repo.Config.Packages = kept
+		out = append(out, repo)
+	}
+	return out
+}
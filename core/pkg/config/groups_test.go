@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+)
+
+func TestResolvePackageGroups(t *testing.T) {
+	cfg := &Config{PackageGroups: map[string][]string{
+		"web":    {"django", "requests"},
+		"crypto": {"cryptography", "requests"},
+		"empty":  {},
+	}}
+
+	got, err := cfg.ResolvePackageGroups([]string{"web", "crypto"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(got) != "[django requests cryptography]" {
+		t.Errorf("expected de-duplicated union in order, got %v", got)
+	}
+
+	_, err = cfg.ResolvePackageGroups([]string{"nope"})
+	if !exitcode.Is(err, exitcode.ConfigError) {
+		t.Fatalf("expected config error for unknown group, got %v", err)
+	}
+	if want := `unknown package group "nope" (available: crypto, empty, web)`; err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+
+	if _, err := cfg.ResolvePackageGroups([]string{"empty"}); !exitcode.Is(err, exitcode.ConfigError) {
+		t.Errorf("expected config error for empty selection, got %v", err)
+	}
+}
+
+func TestFilterPackages(t *testing.T) {
+	repos := []RepoWithProvider{
+		{Provider: "github", Config: RepoConfig{Repository: "a", Analyzer: "poetry", Packages: []string{"Django", "celery"}}},
+		{Provider: "github", Config: RepoConfig{Repository: "b", Analyzer: "poetry", Packages: []string{"numpy"}}},
+	}
+
+	got := FilterPackages(repos, []string{"django"})
+
+	if len(got) != 1 || got[0].Config.Repository != "a" {
+		t.Fatalf("expected only repository a, got %+v", got)
+	}
+	if fmt.Sprint(got[0].Config.Packages) != "[Django]" {
+		t.Errorf("expected normalized match keeping config spelling, got %v", got[0].Config.Packages)
+	}
+	if len(repos[0].Config.Packages) != 2 {
+		t.Error("input repositories should not be modified")
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IgnoreDateLayout is the format of IgnoreConfig.Expires
+const IgnoreDateLayout = time.DateOnly
+
+// IgnoreConfig annotates or suppresses findings (version drift and policy
+// violations) a team has accepted, e.g. a repository intentionally pinning
+// django 3.2 until a migration. Suppressed findings are still reported, with
+// Reason, but no longer fail the run.
+type IgnoreConfig struct {
+	Repository string `yaml:"repository,omitempty"` // owner/repo or provider:owner/repo@ref (empty = every repository)
+	Package    string `yaml:"package,omitempty"`    // Package the rule applies to (empty = every package)
+	Version    string `yaml:"version,omitempty"`    // Only while the locked version is in this range, e.g. "<4"
+	Policy     string `yaml:"policy,omitempty"`     // Only violations of this policy; drift is then not ignored
+	Reason     string `yaml:"reason"`               // Shown next to the finding (required)
+	Expires    string `yaml:"expires,omitempty"`    // YYYY-MM-DD; the rule no longer applies from this day
+	Annotate   bool   `yaml:"annotate,omitempty"`   // Only show Reason; the finding still counts
+}
+
+// ignoreFile is the shape of a file referenced by Config.IgnoreFile
+type ignoreFile struct {
+	Ignores []IgnoreConfig `yaml:"ignores"`
+}
+
+// LoadIgnoreFile reads the ignores list of a YAML ignore file, e.g.
+//
+//	ignores:
+//	  - repository: myorg/legacy-app
+//	    package: django
+//	    version: "<4"
+//	    reason: intentionally pins django 3.2 until Q3
+//	    expires: 2026-10-01
+func LoadIgnoreFile(filename string) ([]IgnoreConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+	var f ignoreFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse ignore file %s: %w", filename, err)
+	}
+	return f.Ignores, nil
+}
+
+// loadIgnoreFile appends the rules of c.IgnoreFile, resolved relative to the
+// directory of the configuration file at configPath, to c.Ignores
+func (c *Config) loadIgnoreFile(configPath string) error {
+	if c.IgnoreFile == "" {
+		return nil
+	}
+	path := c.IgnoreFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(configPath), path)
+	}
+	rules, err := LoadIgnoreFile(path)
+	if err != nil {
+		return err
+	}
+	c.Ignores = append(c.Ignores, rules...)
+	return nil
+}
+
+// validateIgnores checks every rule has a reason, narrows what it ignores
+// and, when it expires, a valid date
+func validateIgnores(rules []IgnoreConfig) error {
+	for i, r := range rules {
+		if strings.TrimSpace(r.Reason) == "" {
+			return fmt.Errorf("ignore at index %d: reason is required", i)
+		}
+		if r.Repository == "" && r.Package == "" && r.Policy == "" {
+			return fmt.Errorf("ignore at index %d: set at least one of repository, package or policy", i)
+		}
+		if r.Expires != "" {
+			if _, err := time.Parse(IgnoreDateLayout, r.Expires); err != nil {
+				return fmt.Errorf("ignore at index %d: expires must be YYYY-MM-DD, got %q", i, r.Expires)
+			}
+		}
+	}
+	return nil
+}
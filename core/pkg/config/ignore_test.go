@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFromFile_IgnoreFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	ignores := `ignores:
+  - repository: myorg/legacy-app
+    package: django
+    version: "<4"
+    reason: intentionally pins django 3.2 until Q3
+    expires: 2030-10-01
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "ignores.yaml"), []byte(ignores), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfgFile := filepath.Join(tmpDir, "config.yaml")
+	content := `providers: {}
+ignores:
+  - policy: no-git
+    reason: vendored fork
+    annotate: true
+ignoreFile: ignores.yaml
+`
+	if err := os.WriteFile(cfgFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromFile(cfgFile)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if len(cfg.Ignores) != 2 {
+		t.Fatalf("expected inline and file ignores, got %+v", cfg.Ignores)
+	}
+	if got := cfg.Ignores[1]; got.Repository != "myorg/legacy-app" || got.Expires != "2030-10-01" || got.Version != "<4" {
+		t.Errorf("unexpected file rule %+v", got)
+	}
+}
+
+func TestLoadFromFile_InvalidIgnores(t *testing.T) {
+	tests := []struct {
+		name    string
+		ignores string
+		wantErr string
+	}{
+		{"no reason", "  - package: django\n", "reason is required"},
+		{"matches everything", "  - reason: why\n", "at least one of"},
+		{"bad expiry", "  - package: django\n    reason: why\n    expires: next year\n", "YYYY-MM-DD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(cfgFile, []byte("providers: {}\nignores:\n"+tt.ignores), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadFromFile(cfgFile)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFromFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFromFile_MissingIgnoreFile(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(cfgFile, []byte("providers: {}\nignoreFile: missing.yaml\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromFile(cfgFile); err == nil || !strings.Contains(err.Error(), "ignore file") {
+		t.Errorf("LoadFromFile() error = %v, want a missing ignore file error", err)
+	}
+}
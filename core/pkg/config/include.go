@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeOrder describes how the files of a configuration combine (see
+// Config.Extends and Config.Include), for validate-config to show
+const MergeOrder = `Files merge in order: the files a file extends, then the file itself,
+then the files it includes. Later files override the scalar settings and
+provider default fields they set; repositories and the hooks, policies,
+ignores, notifications and issues lists are concatenated; packageGroups and
+packageAliases merge by name. A file reached twice merges once, at its first
+position.`
+
+// configLoader reads a configuration file with the files it extends and
+// includes, merging each into one Config in MergeOrder
+type configLoader struct {
+	merged Config
+	stack  []string        // Files being loaded, outermost first
+	loaded map[string]bool // Absolute paths of merged files
+}
+
+// loadLayered reads the configuration at path and the files it extends and
+// includes, without applying defaults
+func loadLayered(path string) (*Config, error) {
+	l := &configLoader{loaded: make(map[string]bool)}
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+	return &l.merged, nil
+}
+
+// load merges the file at path, after the files it extends and before the
+// ones it includes
+func (l *configLoader) load(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config file %s: %w", path, err)
+	}
+	if i := slices.Index(l.stack, abs); i >= 0 {
+		return fmt.Errorf("config files form a cycle: %s", strings.Join(append(l.stack[i:], abs), " -> "))
+	}
+	if l.loaded[abs] {
+		return nil
+	}
+	l.stack = append(l.stack, abs)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	var layer Config
+	if err := yaml.Unmarshal(data, &layer); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := layer.loadIgnoreFile(path); err != nil {
+		return err
+	}
+
+	for _, base := range layer.Extends {
+		if err := l.load(relativeTo(path, base)); err != nil {
+			return fmt.Errorf("%s extends %s: %w", path, base, err)
+		}
+	}
+	l.loaded[abs] = true
+	l.merged.merge(&layer)
+	l.merged.Sources = append(l.merged.Sources, path)
+	for _, part := range layer.Include {
+		if err := l.load(relativeTo(path, part)); err != nil {
+			return fmt.Errorf("%s includes %s: %w", path, part, err)
+		}
+	}
+	return nil
+}
+
+// relativeTo resolves path against the directory of the configuration file
+// naming it
+func relativeTo(configPath, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(configPath), path)
+}
+
+// merge layers o over c as described by MergeOrder
+func (c *Config) merge(o *Config) {
+	for name, p := range o.Providers {
+		if c.Providers == nil {
+			c.Providers = make(map[string]ProviderConfig)
+		}
+		merged := c.Providers[name]
+		overlay(&merged.Default, &p.Default)
+		merged.Repositories = append(merged.Repositories, p.Repositories...)
+		c.Providers[name] = merged
+	}
+
+	if o.Retry != nil {
+		c.Retry = o.Retry
+	}
+	if o.Budget != nil {
+		c.Budget = o.Budget
+	}
+	if o.Timeouts != nil {
+		c.Timeouts = o.Timeouts
+	}
+	if o.MaxFileSize != 0 {
+		c.MaxFileSize = o.MaxFileSize
+	}
+	if o.IgnoreFile != "" {
+		c.IgnoreFile = o.IgnoreFile
+	}
+	if o.Plugins != nil {
+		c.Plugins = o.Plugins
+	}
+	if o.Telemetry != nil {
+		c.Telemetry = o.Telemetry
+	}
+
+	if len(o.PackageGroups) > 0 {
+		if c.PackageGroups == nil {
+			c.PackageGroups = make(map[string][]string)
+		}
+		maps.Copy(c.PackageGroups, o.PackageGroups)
+	}
+	if len(o.PackageAliases) > 0 {
+		if c.PackageAliases == nil {
+			c.PackageAliases = make(map[string][]string)
+		}
+		maps.Copy(c.PackageAliases, o.PackageAliases)
+	}
+
+	c.Hooks = append(c.Hooks, o.Hooks...)
+	c.Policies = append(c.Policies, o.Policies...)
+	c.Ignores = append(c.Ignores, o.Ignores...)
+	c.Notifications = append(c.Notifications, o.Notifications...)
+	c.Issues = append(c.Issues, o.Issues...)
+}
+
+// overlay copies the fields src sets (non-zero values) over dst
+func overlay(dst, src *RepoDefaults) {
+	d, s := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := range s.NumField() {
+		if f := s.Field(i); !f.IsZero() {
+			d.Field(i).Set(f)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeConfigFiles writes files (name -> content) under a temporary
+// directory and returns it
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadFromFile_ExtendsAndInclude(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"base.yaml": `providers:
+  github:
+    default:
+      token: base-token
+      analyzer: poetry
+      ref: main
+      packages: [django]
+maxFileSize: 1024
+packageGroups:
+  web: [django]
+policies:
+  - name: base-policy
+    package: django
+    version: ">=3"
+`,
+		"teams/payments.yaml": `extends: [../base.yaml]
+providers:
+  github:
+    default:
+      ref: develop
+    repositories:
+      - owner: acme
+        repository: payments
+include: [extra.yaml]
+maxFileSize: 2048
+packageGroups:
+  web: [django, flask]
+  crypto: [cryptography]
+`,
+		"teams/extra.yaml": `providers:
+  github:
+    repositories:
+      - owner: acme
+        repository: ledger
+        ref: stable
+`,
+	})
+	cfg, err := LoadFromFile(filepath.Join(dir, "teams", "payments.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	wantSources := []string{
+		filepath.Join(dir, "base.yaml"),
+		filepath.Join(dir, "teams", "payments.yaml"),
+		filepath.Join(dir, "teams", "extra.yaml"),
+	}
+	if !slices.Equal(cfg.Sources, wantSources) {
+		t.Errorf("Sources = %v, want %v", cfg.Sources, wantSources)
+	}
+	repos := cfg.Providers["github"].Repositories
+	if len(repos) != 2 || repos[0].Repository != "payments" || repos[1].Repository != "ledger" {
+		t.Fatalf("repositories = %+v, want payments then ledger", repos)
+	}
+	if repos[0].Token != "base-token" || repos[0].Analyzer != "poetry" || repos[0].Ref != "develop" {
+		t.Errorf("payments = %+v, want the base token and analyzer with the team's ref", repos[0])
+	}
+	if repos[1].Ref != "stable" || !slices.Equal(repos[1].Packages, []string{"django"}) {
+		t.Errorf("ledger = %+v, want its own ref and the base packages", repos[1])
+	}
+	if cfg.MaxFileSize != 2048 {
+		t.Errorf("MaxFileSize = %d, want the extending file's 2048", cfg.MaxFileSize)
+	}
+	if len(cfg.PackageGroups["web"]) != 2 || len(cfg.PackageGroups["crypto"]) != 1 {
+		t.Errorf("PackageGroups = %v, want web overridden and crypto added", cfg.PackageGroups)
+	}
+	if len(cfg.Policies) != 1 {
+		t.Errorf("Policies = %+v, want the base policy", cfg.Policies)
+	}
+}
+
+func TestLoadFromFile_ExtendsOnce(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"base.yaml": `providers:
+  github:
+    default:
+      analyzer: poetry
+    repositories:
+      - owner: acme
+        repository: shared
+`,
+		"a.yaml":    "extends: [base.yaml]\n",
+		"b.yaml":    "extends: [base.yaml]\n",
+		"team.yaml": "extends: [a.yaml, b.yaml]\n",
+	})
+	cfg, err := LoadFromFile(filepath.Join(dir, "team.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if repos := cfg.GetAllRepos(); len(repos) != 1 {
+		t.Errorf("expected base.yaml merged once, got %d repositories", len(repos))
+	}
+	if len(cfg.Sources) != 4 {
+		t.Errorf("Sources = %v, want 4 files", cfg.Sources)
+	}
+}
+
+func TestLoadFromFile_ExtendsCycle(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"a.yaml": "extends: [b.yaml]\nproviders: {}\n",
+		"b.yaml": "include: [a.yaml]\nproviders: {}\n",
+	})
+	_, err := LoadFromFile(filepath.Join(dir, "a.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected a cycle error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "a.yaml -> "+filepath.Join(dir, "b.yaml")) {
+		t.Errorf("error %q should name the cycle", err)
+	}
+
+	_, err = LoadFromFile(filepath.Join(writeConfigFiles(t, map[string]string{"c.yaml": "include: [missing.yaml]\n"}), "c.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "includes missing.yaml") {
+		t.Errorf("expected a missing include error, got %v", err)
+	}
+}
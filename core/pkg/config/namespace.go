@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// nestedNamespaceProviders lists the providers whose owners may be nested
+// namespaces (GitLab groups and subgroups, e.g. group/subgroup/team)
+var nestedNamespaceProviders = map[string]bool{"gitlab": true}
+
+// splitProjectPath splits a repository given as its full path
+// (group/subgroup/project) into owner and name when no owner is set, so the
+// namespace does not have to be configured separately
+func splitProjectPath(repo *RepoConfig) {
+	if repo.Owner != "" {
+		return
+	}
+	if i := strings.LastIndex(repo.Repository, "/"); i >= 0 {
+		repo.Owner, repo.Repository = repo.Repository[:i], repo.Repository[i+1:]
+	}
+}
+
+// ValidateNamespace checks a repository's owner and name for provider. The
+// name is a single path segment; the owner is one too, except on providers
+// with nested namespaces (GitLab), where it may be a group path such as
+// group/subgroup/team.
+func ValidateNamespace(provider, owner, repository string) error {
+	if strings.Contains(repository, "/") {
+		return fmt.Errorf("repository %q must be a name without '/'; set the namespace in owner, or leave owner empty to give the full path", repository)
+	}
+	segments := strings.Split(owner, "/")
+	if len(segments) > 1 && !nestedNamespaceProviders[provider] {
+		return fmt.Errorf("owner %q: %s does not support nested namespaces", owner, provider)
+	}
+	for _, s := range segments {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("owner %q has an empty path segment", owner)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyDefaults_Namespaces(t *testing.T) {
+	cfg := &Config{Providers: map[string]ProviderConfig{
+		"gitlab": {
+			Default: RepoDefaults{Owner: "acme", Analyzer: "poetry"},
+			Repositories: []RepoConfig{
+				{Owner: "platform/backend/payments", Repository: "api"},
+				{Repository: "platform/web/storefront"},
+				{Repository: "tools"},
+			},
+		},
+	}}
+	if err := cfg.ApplyDefaults(); err != nil {
+		t.Fatalf("ApplyDefaults() error = %v", err)
+	}
+	want := [][2]string{{"platform/backend/payments", "api"}, {"platform/web", "storefront"}, {"acme", "tools"}}
+	for i, repo := range cfg.Providers["gitlab"].Repositories {
+		if got := [2]string{repo.Owner, repo.Repository}; got != want[i] {
+			t.Errorf("repository %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	for _, tt := range []struct {
+		provider, owner, repository string
+		want                        string
+	}{
+		{"gitlab", "group/subgroup/team", "api", ""},
+		{"github", "acme", "api", ""},
+		{"github", "acme/platform", "api", "does not support nested namespaces"},
+		{"gitlab", "group//team", "api", "empty path segment"},
+		{"gitlab", "group", "team/api", "without '/'"},
+	} {
+		err := ValidateNamespace(tt.provider, tt.owner, tt.repository)
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("ValidateNamespace(%s, %s, %s) = %v, want %q", tt.provider, tt.owner, tt.repository, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables that override configuration
+// fields (see Overrides.Environ)
+const EnvPrefix = "DEVDASHBOARD_"
+
+// Overrides set configuration fields after a file is loaded and merged, before
+// defaults are applied, so pipelines can adjust a shared configuration
+// without templating it. A field is named by the path of YAML keys leading
+// to it; list items by their index, where the next index appends an item.
+// Values are YAML, e.g. "develop", "[django, flask]" or "30s".
+type Overrides struct {
+	// Set holds path=value assignments with dotted paths, e.g.
+	// providers.github.default.ref=develop (the --set flag)
+	Set []string
+	// Environ is searched for EnvPrefix variables whose upper-case,
+	// underscore-separated path names a field, e.g.
+	// DEVDASHBOARD_PROVIDERS_GITHUB_DEFAULT_REF. Variables naming no field
+	// are logged and skipped. Set assignments apply after these.
+	Environ []string
+}
+
+// LoadFromFileWithOverrides is LoadFromFile applying overrides to the loaded
+// configuration. Errors are classified as exitcode.ConfigError.
+func LoadFromFileWithOverrides(filename string, overrides Overrides) (*Config, error) {
+	cfg, err := loadFromFile(filename, overrides)
+	if err != nil {
+		return nil, exitcode.New(exitcode.ConfigError, err)
+	}
+	return cfg, nil
+}
+
+// apply sets the overridden fields of c
+func (o Overrides) apply(c *Config) error {
+	root := reflect.ValueOf(c).Elem()
+	for _, kv := range o.Environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		path, ok := envPath(root, strings.Split(strings.ToLower(strings.TrimPrefix(name, EnvPrefix)), "_"))
+		if !ok {
+			slog.Warn("Ignoring environment variable naming no configuration field", "name", name)
+			continue
+		}
+		if err := setField(root, path, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		slog.Debug("Configuration overridden from the environment", "name", name, "path", strings.Join(path, "."))
+	}
+	for _, kv := range o.Set {
+		path, value, ok := strings.Cut(kv, "=")
+		if !ok || path == "" {
+			return fmt.Errorf("--set %q: expected path=value", kv)
+		}
+		if err := setField(root, strings.Split(path, "."), value); err != nil {
+			return fmt.Errorf("--set %s: %w", path, err)
+		}
+		slog.Debug("Configuration overridden", "path", path)
+	}
+	return nil
+}
+
+// envPath resolves the lower-case words of an environment variable name to
+// the path of the field of v they name. A key may span several words
+// (max_file_size or maxfilesize for maxFileSize) and matches ignoring case,
+// dashes and underscores; map keys not in v yet are the words joined with
+// underscores.
+func envPath(v reflect.Value, words []string) ([]string, bool) {
+	if len(words) == 0 {
+		return nil, true
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return envPath(reflect.New(v.Type().Elem()).Elem(), words)
+		}
+		return envPath(v.Elem(), words)
+
+	case reflect.Struct:
+		t := v.Type()
+		for n := 1; n <= len(words); n++ {
+			key := strings.Join(words[:n], "")
+			for i := range t.NumField() {
+				name := yamlName(t.Field(i))
+				if name == "" || normalizeKey(name) != key {
+					continue
+				}
+				if rest, ok := envPath(v.Field(i), words[n:]); ok {
+					return append([]string{name}, rest...), true
+				}
+			}
+		}
+
+	case reflect.Map:
+		for n := 1; n <= len(words); n++ {
+			key := strings.Join(words[:n], "_")
+			for _, existing := range v.MapKeys() {
+				if normalizeKey(existing.String()) == strings.Join(words[:n], "") {
+					key = existing.String()
+					break
+				}
+			}
+			elem := v.MapIndex(reflect.ValueOf(key))
+			if !elem.IsValid() {
+				elem = reflect.New(v.Type().Elem()).Elem()
+			}
+			if rest, ok := envPath(elem, words[n:]); ok {
+				return append([]string{key}, rest...), true
+			}
+		}
+
+	case reflect.Slice:
+		i, err := strconv.Atoi(words[0])
+		if err != nil || i < 0 || i > v.Len() {
+			return nil, false
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if i < v.Len() {
+			elem = v.Index(i)
+		}
+		if rest, ok := envPath(elem, words[1:]); ok {
+			return append([]string{words[0]}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// setField decodes value into the field of v named by path
+func setField(v reflect.Value, path []string, value string) error {
+	if len(path) == 0 {
+		decoded := reflect.New(v.Type())
+		if err := yaml.Unmarshal([]byte(value), decoded.Interface()); err != nil {
+			return fmt.Errorf("invalid value %q: %w", value, err)
+		}
+		v.Set(decoded.Elem())
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setField(v.Elem(), path, value)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			if name := yamlName(t.Field(i)); name != "" && name == path[0] {
+				return setField(v.Field(i), path[1:], value)
+			}
+		}
+		return fmt.Errorf("unknown field %q", path[0])
+
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.ValueOf(path[0])
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if current := v.MapIndex(key); current.IsValid() {
+			elem.Set(current)
+		}
+		if err := setField(elem, path[1:], value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+
+	case reflect.Slice:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i > v.Len() {
+			return fmt.Errorf("invalid index %q (list has %d items)", path[0], v.Len())
+		}
+		if i == v.Len() {
+			v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+		}
+		return setField(v.Index(i), path[1:], value)
+
+	default:
+		return fmt.Errorf("%q is not a field of a %s value", path[0], v.Kind())
+	}
+}
+
+// yamlName returns the YAML key of a struct field, "" when it has none
+func yamlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// normalizeKey lower-cases a YAML key without dashes and underscores, as
+// environment variable words name it
+func normalizeKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+}
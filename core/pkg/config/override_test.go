@@ -0,0 +1,87 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const overrideConfig = `providers:
+  github:
+    default:
+      analyzer: poetry
+      ref: main
+    repositories:
+      - owner: acme
+        repository: api
+packageGroups:
+  crypto-critical: [cryptography]
+`
+
+func TestLoadFromFileWithOverrides(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{"config.yaml": overrideConfig})
+	cfg, err := LoadFromFileWithOverrides(filepath.Join(dir, "config.yaml"), Overrides{
+		Environ: []string{
+			"PATH=/usr/bin",
+			"DEVDASHBOARD_PROVIDERS_GITHUB_DEFAULT_REF=develop",
+			"DEVDASHBOARD_MAX_FILE_SIZE=1024",
+			"DEVDASHBOARD_TIMEOUTS_REPOSITORY=90s",
+			"DEVDASHBOARD_PACKAGE_GROUPS_CRYPTO_CRITICAL=[cryptography, pyopenssl]",
+			"DEVDASHBOARD_NOT_A_FIELD=1",
+		},
+		Set: []string{
+			"providers.github.repositories.0.packages=[django, flask]",
+			"providers.github.repositories.1.owner=acme",
+			"providers.github.repositories.1.repository=web",
+			"providers.gitlab.default.analyzer=uvlock",
+			"maxFileSize=2048",
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoadFromFileWithOverrides() error = %v", err)
+	}
+
+	repos := cfg.Providers["github"].Repositories
+	if len(repos) != 2 {
+		t.Fatalf("expected an appended repository, got %+v", repos)
+	}
+	if repos[0].Ref != "develop" || repos[1].Ref != "develop" || repos[1].Repository != "web" {
+		t.Errorf("repositories = %+v, want the overridden default ref", repos)
+	}
+	if !slices.Equal(repos[0].Packages, []string{"django", "flask"}) {
+		t.Errorf("packages = %v, want [django flask]", repos[0].Packages)
+	}
+	if cfg.MaxFileSize != 2048 {
+		t.Errorf("MaxFileSize = %d, want --set to win over the environment", cfg.MaxFileSize)
+	}
+	if cfg.Timeouts == nil || cfg.Timeouts.Repository != 90*time.Second {
+		t.Errorf("Timeouts = %+v, want a 90s repository timeout", cfg.Timeouts)
+	}
+	if got := cfg.PackageGroups["crypto-critical"]; len(got) != 2 {
+		t.Errorf("crypto-critical = %v, want the existing group overridden", got)
+	}
+	if cfg.Providers["gitlab"].Default.Analyzer != "uvlock" {
+		t.Errorf("expected a new provider, got %+v", cfg.Providers["gitlab"])
+	}
+}
+
+func TestLoadFromFileWithOverrides_Errors(t *testing.T) {
+	path := filepath.Join(writeConfigFiles(t, map[string]string{"config.yaml": overrideConfig}), "config.yaml")
+	for _, tt := range []struct {
+		set  string
+		want string
+	}{
+		{"maxFileSize", "expected path=value"},
+		{"providers.github.default.nope=1", `unknown field "nope"`},
+		{"providers.github.repositories.5.ref=x", "invalid index"},
+		{"maxFileSize=big", "invalid value"},
+		{"maxFileSize.x=1", "not a field"},
+	} {
+		_, err := LoadFromFileWithOverrides(path, Overrides{Set: []string{tt.set}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("--set %s: error = %v, want %q", tt.set, err, tt.want)
+		}
+	}
+}
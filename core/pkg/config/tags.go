@@ -0,0 +1,60 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// MergeTags returns the union of the tag lists in first-seen order. Tags are
+// trimmed and compared case-insensitively; empty tags are dropped. A nil
+// result means no tags.
+func MergeTags(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range lists {
+		for _, tag := range list {
+			tag = strings.TrimSpace(tag)
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// HasAnyTag reports whether tags contains any of wanted, ignoring case
+func HasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(strings.TrimSpace(tag), strings.TrimSpace(w)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RepoTags returns the distinct tags used by repos, sorted
+func RepoTags(repos []RepoWithProvider) []string {
+	var lists [][]string
+	for _, repo := range repos {
+		lists = append(lists, repo.Config.Tags)
+	}
+	tags := MergeTags(lists...)
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i]) < strings.ToLower(tags[j]) })
+	return tags
+}
+
+// FilterTags keeps the repositories carrying at least one of tags
+func FilterTags(repos []RepoWithProvider, tags []string) []RepoWithProvider {
+	out := make([]RepoWithProvider, 0, len(repos))
+	for _, repo := range repos {
+		if HasAnyTag(repo.Config.Tags, tags) {
+			out = append(out, repo)
+		}
+	}
+	return out
+}
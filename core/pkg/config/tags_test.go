@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"team-payments", " "}, []string{"Team-Payments", " deprecated "}, nil)
+	if fmt.Sprint(got) != "[team-payments deprecated]" {
+		t.Errorf("expected trimmed case-insensitive union, got %v", got)
+	}
+	if got := MergeTags(nil, []string{""}); got != nil {
+		t.Errorf("expected nil for no tags, got %v", got)
+	}
+}
+
+func TestFilterTags(t *testing.T) {
+	repos := []RepoWithProvider{
+		{Provider: "github", Config: RepoConfig{Repository: "a", Tags: []string{"team-payments"}}},
+		{Provider: "github", Config: RepoConfig{Repository: "b", Tags: []string{"deprecated", "Team-Search"}}},
+		{Provider: "gitlab", Config: RepoConfig{Repository: "c"}},
+	}
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"TEAM-PAYMENTS"}, "[a]"},
+		{[]string{"team-search", "team-payments"}, "[a b]"},
+		{[]string{"unknown"}, "[]"},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, repo := range FilterTags(repos, tt.tags) {
+			names = append(names, repo.Config.Repository)
+		}
+		if got := fmt.Sprint(names); got != tt.want {
+			t.Errorf("FilterTags(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+
+	if got := fmt.Sprint(RepoTags(repos)); got != "[deprecated team-payments Team-Search]" {
+		t.Errorf("RepoTags = %s", got)
+	}
+}
@@ -0,0 +1,367 @@
+// Package demo generates a synthetic dependency dataset: many repositories
+// served by the in-memory provider (see package memory) and a history of
+// report snapshots leading up to them. It lets new users and UI developers
+// exercise tables, filters, trends and exports without configuring
+// providers or tokens. The same Options always generate the same dataset.
+package demo
+
+import (
+	"crypto/sha1" // #nosec G505 -- fake commit IDs, not security
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+// DefaultRepositories is the number of repositories generated by default
+const DefaultRepositories = 60
+
+// DefaultWeeks is the number of weekly history snapshots generated by default
+const DefaultWeeks = 12
+
+// Options controls the size and randomness of a Dataset
+type Options struct {
+	// Repositories to generate (default DefaultRepositories)
+	Repositories int
+	// Weeks of history before the current state (default DefaultWeeks)
+	Weeks int
+	// Seed of the generator; datasets of equal options are identical
+	// (default 1)
+	Seed uint64
+}
+
+// catalog lists the packages demo repositories lock, each with its versions
+// oldest first
+var catalog = []struct {
+	name     string
+	versions []string
+}{
+	{"boto3", []string{"1.28.85", "1.34.162", "1.35.60"}},
+	{"celery", []string{"5.2.7", "5.3.6", "5.4.0"}},
+	{"cryptography", []string{"41.0.7", "42.0.8", "43.0.3"}},
+	{"django", []string{"3.2.25", "4.1.13", "4.2.16", "5.0.9", "5.1.3"}},
+	{"fastapi", []string{"0.100.1", "0.110.3", "0.115.4"}},
+	{"flask", []string{"2.2.5", "2.3.3", "3.0.3", "3.1.0"}},
+	{"gunicorn", []string{"21.2.0", "22.0.0", "23.0.0"}},
+	{"jinja2", []string{"3.1.2", "3.1.4"}},
+	{"numpy", []string{"1.24.4", "1.26.4", "2.1.3"}},
+	{"pydantic", []string{"1.10.18", "2.5.3", "2.9.2"}},
+	{"pyyaml", []string{"6.0", "6.0.1", "6.0.2"}},
+	{"redis", []string{"4.6.0", "5.0.8", "5.2.0"}},
+	{"requests", []string{"2.28.2", "2.31.0", "2.32.3"}},
+	{"sqlalchemy", []string{"1.4.54", "2.0.23", "2.0.36"}},
+	{"urllib3", []string{"1.26.18", "2.0.7", "2.2.3"}},
+}
+
+// packageGroups are the watchlists offered with the dataset
+var packageGroups = map[string][]string{
+	"web":   {"django", "fastapi", "flask", "gunicorn", "jinja2"},
+	"data":  {"numpy", "pydantic", "sqlalchemy"},
+	"infra": {"boto3", "celery", "cryptography", "redis", "requests", "urllib3"},
+}
+
+var (
+	owners = []string{"acme-payments", "acme-search", "acme-platform", "acme-data"}
+	words  = []string{
+		"billing", "checkout", "ledger", "invoices", "catalog", "indexer",
+		"ranking", "gateway", "auth", "notifications", "scheduler", "reports",
+		"ingest", "etl", "warehouse", "metrics", "profiles", "inventory",
+		"shipping", "support",
+	}
+	kinds = []string{"api", "worker", "web"}
+)
+
+// failures are the errors given to every failingEvery-th repository in turn
+var failures = []*repository.StatusError{
+	{StatusCode: http.StatusNotFound, Message: "repository not found"},
+	{StatusCode: http.StatusUnauthorized, Message: "bad credentials"},
+	{StatusCode: http.StatusTooManyRequests, Message: "API rate limit exceeded"},
+}
+
+const failingEvery = 17
+
+// lockedPackage is one package of a lock file and the weeks it was upgraded
+type lockedPackage struct {
+	pkg      int   // catalog index
+	version  int   // current version index
+	upgrades []int // weeks (1..Weeks) a version bump landed
+}
+
+// versionAt returns the version index locked in week w (Weeks is now)
+func (p lockedPackage) versionAt(w int) int {
+	v := p.version
+	for _, u := range p.upgrades {
+		if u > w {
+			v--
+		}
+	}
+	return max(v, 0)
+}
+
+// lockFile is one dependency file of a repository
+type lockFile struct {
+	path     string
+	packages []lockedPackage
+}
+
+// repo is one generated repository
+type repo struct {
+	owner, name string
+	analyzer    string
+	tags        []string
+	files       []lockFile
+	err         error
+}
+
+// Dataset is a generated set of repositories and their history
+type Dataset struct {
+	weeks int
+	repos []repo
+}
+
+// Generate creates the dataset described by opts
+func Generate(opts Options) *Dataset {
+	if opts.Repositories <= 0 {
+		opts.Repositories = DefaultRepositories
+	}
+	if opts.Weeks <= 0 {
+		opts.Weeks = DefaultWeeks
+	}
+	if opts.Seed == 0 {
+		opts.Seed = 1
+	}
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed)) // #nosec G404 -- reproducible sample data
+	d := &Dataset{weeks: opts.Weeks, repos: make([]repo, 0, opts.Repositories)}
+	for i := range opts.Repositories {
+		r := repo{
+			owner: owners[rng.IntN(len(owners))],
+			name:  words[i%len(words)] + "-" + kinds[(i/len(words))%len(kinds)],
+		}
+		if n := i / (len(words) * len(kinds)); n > 0 {
+			r.name += fmt.Sprintf("-%d", n+1)
+		}
+		r.tags = []string{"team-" + strings.TrimPrefix(r.owner, "acme-")}
+		if rng.IntN(2) == 0 {
+			r.tags = append(r.tags, "prod")
+		}
+		if rng.IntN(10) == 0 {
+			r.tags = append(r.tags, "deprecated")
+		}
+
+		switch n := rng.IntN(20); {
+		case n < 12:
+			r.analyzer = "poetry"
+		case n < 17:
+			r.analyzer = "uvlock"
+		default:
+			r.analyzer = "pipfile"
+		}
+		file := lockFile{path: lockFileName(r.analyzer), packages: randomPackages(rng, opts.Weeks)}
+		r.files = []lockFile{file}
+		// Some poetry repositories are monorepos whose services lag behind
+		// each other
+		if r.analyzer == "poetry" && rng.IntN(6) == 0 {
+			worker := lockFile{path: "services/worker/poetry.lock", packages: slices.Clone(file.packages)}
+			lag := rng.IntN(len(worker.packages))
+			worker.packages[lag].version = max(worker.packages[lag].version-1, 0)
+			r.files = []lockFile{{path: "services/api/poetry.lock", packages: file.packages}, worker}
+		}
+		if (i+1)%failingEvery == 0 {
+			r.err = failures[(i/failingEvery)%len(failures)]
+		}
+		d.repos = append(d.repos, r)
+	}
+	return d
+}
+
+// randomPackages picks the packages of a lock file, favoring recent versions
+func randomPackages(rng *rand.Rand, weeks int) []lockedPackage {
+	picked := rng.Perm(len(catalog))[:4+rng.IntN(6)]
+	slices.Sort(picked)
+	pkgs := make([]lockedPackage, 0, len(picked))
+	for _, idx := range picked {
+		n := len(catalog[idx].versions)
+		p := lockedPackage{pkg: idx, version: n - 1 - min(rng.IntN(n), rng.IntN(n))}
+		for range rng.IntN(3) {
+			p.upgrades = append(p.upgrades, 1+rng.IntN(weeks))
+		}
+		pkgs = append(pkgs, p)
+	}
+	return pkgs
+}
+
+// lockFileName returns the root lock file of an analyzer
+func lockFileName(analyzer string) string {
+	switch analyzer {
+	case "uvlock":
+		return "uv.lock"
+	case "pipfile":
+		return "Pipfile.lock"
+	default:
+		return "poetry.lock"
+	}
+}
+
+// render returns a lock file's contents in week w
+func (f lockFile) render(analyzer string, w int) string {
+	if analyzer == "pipfile" {
+		deps := make(map[string]map[string]string, len(f.packages))
+		for _, p := range f.packages {
+			c := catalog[p.pkg]
+			deps[c.name] = map[string]string{"version": "==" + c.versions[p.versionAt(w)]}
+		}
+		data, _ := json.MarshalIndent(map[string]any{
+			"_meta":   map[string]any{"pipfile-spec": 6},
+			"default": deps,
+			"develop": map[string]any{},
+		}, "", "    ")
+		return string(data) + "\n"
+	}
+	var b strings.Builder
+	if analyzer == "uvlock" {
+		b.WriteString("version = 1\nrequires-python = \">=3.11\"\n\n")
+	}
+	for _, p := range f.packages {
+		c := catalog[p.pkg]
+		fmt.Fprintf(&b, "[[package]]\nname = %q\nversion = %q\n\n", c.name, c.versions[p.versionAt(w)])
+	}
+	return b.String()
+}
+
+// filesAt returns a repository's files in week w
+func (r repo) filesAt(w int) memory.Files {
+	files := make(memory.Files, len(r.files))
+	for _, f := range r.files {
+		files[f.path] = f.render(r.analyzer, w)
+	}
+	return files
+}
+
+// trackedPackages returns the names of the packages a repository locks
+func (r repo) trackedPackages() []string {
+	var names []string
+	for _, f := range r.files {
+		for _, p := range f.packages {
+			names = append(names, catalog[p.pkg].name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// Repositories returns the report configuration of every repository, on
+// the memory provider's default branch
+func (d *Dataset) Repositories() []config.RepoWithProvider {
+	repos := make([]config.RepoWithProvider, 0, len(d.repos))
+	for _, r := range d.repos {
+		repos = append(repos, config.RepoWithProvider{Provider: memory.Provider, Config: config.RepoConfig{
+			Owner:      r.owner,
+			Repository: r.name,
+			Ref:        memory.DefaultBranch,
+			Analyzer:   r.analyzer,
+			Packages:   r.trackedPackages(),
+			Tags:       slices.Clone(r.tags),
+		}})
+	}
+	return repos
+}
+
+// Packages returns the names of every package the dataset locks, sorted
+func Packages() []string {
+	names := make([]string, 0, len(catalog))
+	for _, c := range catalog {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// PackageGroups returns named watchlists of the dataset's packages
+func PackageGroups() map[string][]string {
+	groups := make(map[string][]string, len(packageGroups))
+	for name, pkgs := range packageGroups {
+		groups[name] = slices.Clone(pkgs)
+	}
+	return groups
+}
+
+// Populate replaces the dataset's repositories in store with their current
+// state; failing repositories fail every request
+func (d *Dataset) Populate(store *memory.Store) {
+	for _, r := range d.repos {
+		store.AddRepository(r.owner, r.name, r.filesAt(d.weeks))
+		store.SetDescription(r.owner, r.name, fmt.Sprintf("Demo %s service of %s", r.name, r.owner))
+		if r.err != nil {
+			store.SetError(r.owner, r.name, r.err)
+		}
+	}
+}
+
+// History returns a snapshot per week before now, oldest first, as report
+// runs of the dataset's repositories would have archived them (see
+// report.ArchiveSnapshot)
+func (d *Dataset) History(now time.Time) []*report.Snapshot {
+	history := make([]*report.Snapshot, 0, d.weeks)
+	for w := range d.weeks {
+		s := &report.Snapshot{
+			Version:      report.SnapshotVersion,
+			GeneratedAt:  now.Add(-time.Duration(d.weeks-w) * 7 * 24 * time.Hour).UTC(),
+			Repositories: make(map[string]report.SnapshotEntry, len(d.repos)),
+		}
+		for _, r := range d.repos {
+			rr := report.RepositoryReport{Provider: memory.Provider, Owner: r.owner, Repository: r.name, Ref: memory.DefaultBranch}
+			if r.err != nil {
+				if s.Failed == nil {
+					s.Failed = make(map[string]*report.ErrorDetail)
+				}
+				s.Failed[rr.Key()] = report.NewErrorDetail(r.err)
+				continue
+			}
+			s.Repositories[rr.Key()] = r.snapshotEntry(w)
+		}
+		history = append(history, s)
+	}
+	return history
+}
+
+// snapshotEntry returns a repository's analysis results in week w
+func (r repo) snapshotEntry(w int) report.SnapshotEntry {
+	files := r.filesAt(w)
+	h := sha1.New() // #nosec G401 -- fake commit IDs, not security
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00", p, files[p])
+	}
+	entry := report.SnapshotEntry{
+		CommitSHA:    hex.EncodeToString(h.Sum(nil)),
+		Analyzer:     r.analyzer,
+		Dependencies: make(map[string]string),
+	}
+	// Files are ordered by path, so the first one found wins like in reports
+	for _, f := range r.files {
+		for _, p := range f.packages {
+			name, version := catalog[p.pkg].name, catalog[p.pkg].versions[p.versionAt(w)]
+			if _, ok := entry.Dependencies[name]; !ok {
+				entry.Dependencies[name] = version
+			}
+			if len(r.files) > 1 {
+				if entry.FileVersions == nil {
+					entry.FileVersions = make(map[string]map[string]string)
+				}
+				if entry.FileVersions[name] == nil {
+					entry.FileVersions[name] = make(map[string]string)
+				}
+				entry.FileVersions[name][f.path] = version
+			}
+		}
+	}
+	return entry
+}
@@ -0,0 +1,93 @@
+package demo
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+func TestGenerate_Deterministic(t *testing.T) {
+	a, b := Generate(Options{}), Generate(Options{})
+	if !reflect.DeepEqual(a.Repositories(), b.Repositories()) {
+		t.Error("equal options generated different repositories")
+	}
+	if got := len(a.Repositories()); got != DefaultRepositories {
+		t.Errorf("generated %d repositories, want %d", got, DefaultRepositories)
+	}
+	if reflect.DeepEqual(a.Repositories(), Generate(Options{Seed: 2}).Repositories()) {
+		t.Error("another seed generated the same repositories")
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range Generate(Options{Repositories: 150}).Repositories() {
+		key := r.Config.Owner + "/" + r.Config.Repository
+		if seen[key] {
+			t.Errorf("repository %s generated twice", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestDataset_ReportMatchesHistory(t *testing.T) {
+	d := Generate(Options{Repositories: 40, Weeks: 4})
+	t.Cleanup(memory.Default.Reset)
+	d.Populate(memory.Default)
+
+	rpt, err := report.NewGenerator().Generate(context.Background(), d.Repositories())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	history := d.History(now)
+	if len(history) != 4 {
+		t.Fatalf("History returned %d snapshots, want 4", len(history))
+	}
+	if got, want := history[0].GeneratedAt, now.Add(-4*7*24*time.Hour); !got.Equal(want) {
+		t.Errorf("oldest snapshot at %v, want %v", got, want)
+	}
+
+	// The populated state is one week past the latest snapshot
+	current := make(map[string]report.SnapshotEntry)
+	for _, r := range d.repos {
+		rr := report.RepositoryReport{Provider: memory.Provider, Owner: r.owner, Repository: r.name, Ref: memory.DefaultBranch}
+		current[rr.Key()] = r.snapshotEntry(d.weeks)
+	}
+	failed, multi := 0, 0
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil {
+			failed++
+			if _, ok := history[3].Failed[rr.Key()]; !ok {
+				t.Errorf("%s failed (%v) but is not failed in the history", rr.Key(), rr.Error)
+			}
+			continue
+		}
+		entry, ok := current[rr.Key()]
+		if !ok {
+			t.Errorf("%s missing from the current state", rr.Key())
+			continue
+		}
+		if !maps.Equal(rr.Dependencies, entry.Dependencies) {
+			t.Errorf("%s dependencies = %v, want %v", rr.Key(), rr.Dependencies, entry.Dependencies)
+		}
+		if len(rr.FileVersions) > 0 {
+			multi++
+		}
+	}
+	if failed == 0 || multi == 0 {
+		t.Errorf("dataset has %d failing and %d monorepo repositories, want some of each", failed, multi)
+	}
+}
+
+func TestLockedPackage_VersionAt(t *testing.T) {
+	p := lockedPackage{version: 2, upgrades: []int{3, 5}}
+	for w, want := range map[int]int{0: 0, 2: 0, 3: 1, 4: 1, 5: 2, 12: 2} {
+		if got := p.versionAt(w); got != want {
+			t.Errorf("versionAt(%d) = %d, want %d", w, got, want)
+		}
+	}
+}
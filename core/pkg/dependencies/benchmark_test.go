@@ -0,0 +1,132 @@
+package dependencies
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchmarkPackages is the size of the synthetic lock files benchmarks parse,
+// in the range of large monorepo lock files
+const benchmarkPackages = 10000
+
+// syntheticHash returns a distinct sha256 artifact hash
+func syntheticHash(i, artifact int) string {
+	return fmt.Sprintf("sha256:%064x", i*16+artifact)
+}
+
+// syntheticUvLock returns a uv.lock locking n packages, each with an sdist,
+// three wheels and two dependencies, plus the project's own entry
+func syntheticUvLock(n int) string {
+	var b strings.Builder
+	b.WriteString("version = 1\nrequires-python = \">=3.11\"\n\n")
+	b.WriteString("[[package]]\nname = \"app\"\nversion = \"0.1.0\"\nsource = { editable = \".\" }\ndependencies = [\n    { name = \"pkg-0\" },\n]\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, "[[package]]\nname = \"pkg-%d\"\nversion = \"1.%d.0\"\nsource = { registry = \"https://pypi.org/simple\" }\n", i, i%100)
+		fmt.Fprintf(&b, "dependencies = [\n    { name = \"pkg-%d\" },\n    { name = \"pkg-%d\", marker = \"python_full_version < '3.12'\" },\n]\n", (i+1)%n, (i+2)%n)
+		fmt.Fprintf(&b, "sdist = { url = \"https://files.pythonhosted.org/packages/pkg_%d-1.%d.0.tar.gz\", hash = \"%s\", size = 123456 }\n", i, i%100, syntheticHash(i, 0))
+		b.WriteString("wheels = [\n")
+		for w, tag := range []string{"py3-none-any", "cp312-cp312-manylinux_2_17_x86_64", "cp312-cp312-win_amd64"} {
+			fmt.Fprintf(&b, "    { url = \"https://files.pythonhosted.org/packages/pkg_%d-1.%d.0-%s.whl\", hash = \"%s\", size = 98765 },\n", i, i%100, tag, syntheticHash(i, w+1))
+		}
+		b.WriteString("]\n\n")
+	}
+	return b.String()
+}
+
+// syntheticPoetryLock returns a poetry.lock (format 2) locking n packages,
+// each with two files and two dependencies
+func syntheticPoetryLock(n int) string {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "[[package]]\nname = \"pkg-%d\"\nversion = \"1.%d.0\"\ndescription = \"Synthetic package %d\"\noptional = false\npython-versions = \">=3.8\"\n", i, i%100, i)
+		fmt.Fprintf(&b, "files = [\n    {file = \"pkg_%d-1.%d.0-py3-none-any.whl\", hash = \"%s\"},\n    {file = \"pkg_%d-1.%d.0.tar.gz\", hash = \"%s\"},\n]\n", i, i%100, syntheticHash(i, 0), i, i%100, syntheticHash(i, 1))
+		fmt.Fprintf(&b, "\n[package.dependencies]\npkg-%d = \">=1.0\"\npkg-%d = {version = \"*\", markers = \"python_version < \\\"3.12\\\"\"}\n\n", (i+1)%n, (i+2)%n)
+	}
+	b.WriteString("[metadata]\nlock-version = \"2.0\"\npython-versions = \"^3.11\"\ncontent-hash = \"abc123\"\n")
+	return b.String()
+}
+
+// syntheticPipfileLock returns a Pipfile.lock locking n packages, a tenth of
+// them for development, each with two hashes
+func syntheticPipfileLock(n int) string {
+	var b strings.Builder
+	b.WriteString(`{"_meta": {"hash": {"sha256": "abc123"}, "pipfile-spec": 6, "requires": {"python_version": "3.12"}, "sources": [{"name": "pypi", "url": "https://pypi.org/simple", "verify_ssl": true}]}`)
+	section := func(name string, from, to int) {
+		fmt.Fprintf(&b, ", %q: {", name)
+		for i := from; i < to; i++ {
+			if i > from {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, `"pkg-%d": {"hashes": [%q, %q], "index": "pypi", "markers": "python_version >= '3.8'", "version": "==1.%d.0"}`, i, syntheticHash(i, 0), syntheticHash(i, 1), i%100)
+		}
+		b.WriteString("}")
+	}
+	section("default", 0, n*9/10)
+	section("develop", n*9/10, n)
+	b.WriteString("}")
+	return b.String()
+}
+
+func TestSyntheticLockFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) ([]Dependency, error)
+		lock  string
+	}{
+		{"uv.lock", NewUvLockAnalyzer().parseUvLock, syntheticUvLock(100)},
+		{"poetry.lock", NewPoetryAnalyzer().parsePoetryLock, syntheticPoetryLock(100)},
+		{"Pipfile.lock", NewPipfileAnalyzer().parsePipfileLock, syntheticPipfileLock(100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps, err := tt.parse(tt.lock)
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			var pkg7 *Dependency
+			for i := range deps {
+				if deps[i].Name == "pkg-7" {
+					pkg7 = &deps[i]
+				}
+			}
+			if pkg7 == nil || pkg7.Version != "1.7.0" || len(pkg7.Hashes) < 2 {
+				t.Errorf("pkg-7 = %+v, want version 1.7.0 with its hashes (of %d packages)", pkg7, len(deps))
+			}
+		})
+	}
+}
+
+// TOML lock files are decoded in batches across CPUs (see
+// decodeLockTables); run with -cpu 1,4,8 to compare
+func BenchmarkParseUvLock(b *testing.B) {
+	benchmarkParse(b, NewUvLockAnalyzer().parseUvLock, syntheticUvLock(benchmarkPackages), 500*time.Microsecond)
+}
+
+func BenchmarkParsePoetryLock(b *testing.B) {
+	benchmarkParse(b, NewPoetryAnalyzer().parsePoetryLock, syntheticPoetryLock(benchmarkPackages), 500*time.Microsecond)
+}
+
+func BenchmarkParsePipfileLock(b *testing.B) {
+	benchmarkParse(b, NewPipfileAnalyzer().parsePipfileLock, syntheticPipfileLock(benchmarkPackages), 50*time.Microsecond)
+}
+
+// benchmarkParse reports the throughput of parsing lock and the time spent
+// per package, failing when that exceeds budget: several times what a
+// single core needs, so only a regression in kind (e.g. quadratic
+// parsing) trips it
+func benchmarkParse(b *testing.B, parse func(string) ([]Dependency, error), lock string, budget time.Duration) {
+	b.SetBytes(int64(len(lock)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := parse(lock); err != nil {
+			b.Fatal(err)
+		}
+	}
+	perPackage := b.Elapsed() / time.Duration(b.N*benchmarkPackages)
+	b.ReportMetric(float64(perPackage.Nanoseconds()), "ns/pkg")
+	if perPackage > budget {
+		b.Errorf("parsing took %v per package, over the %v budget", perPackage, budget)
+	}
+}
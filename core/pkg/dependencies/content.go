@@ -0,0 +1,37 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// fileStream streams a dependency file and remembers read failures, so a
+// download that breaks (or crosses the size limit) mid-stream is reported as
+// a fetch error rather than as unparseable content
+type fileStream struct {
+	rc  io.ReadCloser
+	err error
+}
+
+func openFileStream(ctx context.Context, config Config, owner, repo, ref, path string) (*fileStream, error) {
+	rc, err := repository.OpenFile(ctx, config.RepositoryClient, owner, repo, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileStream{rc: rc}, nil
+}
+
+func (f *fileStream) Read(p []byte) (int, error) {
+	n, err := f.rc.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		f.err = err
+	}
+	return n, err
+}
+
+func (f *fileStream) Close() {
+	_ = f.rc.Close()
+}
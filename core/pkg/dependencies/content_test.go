@@ -0,0 +1,48 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// streamingRepoClient serves content through repository.ContentStreamer,
+// failing with readErr after the content when set
+type streamingRepoClient struct {
+	mockRepoClient
+	readErr error
+}
+
+func (m *streamingRepoClient) OpenFileContent(_ context.Context, _, _, _, _ string) (io.ReadCloser, error) {
+	r := io.Reader(strings.NewReader(m.content))
+	if m.readErr != nil {
+		r = io.MultiReader(r, &failingReader{err: m.readErr})
+	}
+	return io.NopCloser(r), nil
+}
+
+type failingReader struct{ err error }
+
+func (f *failingReader) Read([]byte) (int, error) { return 0, f.err }
+
+func TestAnalyzeFile_Streaming(t *testing.T) {
+	lock := "version = 1\n[[package]]\nname = \"requests\"\nversion = \"2.31.0\"\n"
+	u := NewUvLockAnalyzer()
+
+	deps, err := u.analyzeFile(context.Background(), "o", "r", "", "uv.lock", Config{RepositoryClient: &streamingRepoClient{mockRepoClient: mockRepoClient{content: lock}}})
+	if err != nil || len(deps) != 1 || deps[0].Version != "2.31.0" {
+		t.Fatalf("deps = %+v, err = %v", deps, err)
+	}
+
+	// A download cut off by the size limit is a fetch error, not a parse error
+	tooLarge := &repository.FileTooLargeError{Path: "uv.lock", Limit: 10}
+	_, err = u.analyzeFile(context.Background(), "o", "r", "", "uv.lock", Config{RepositoryClient: &streamingRepoClient{mockRepoClient: mockRepoClient{content: lock[:20]}, readErr: tooLarge}})
+	var parseErr *ParseError
+	if !errors.Is(err, repository.ErrFileTooLarge) || errors.As(err, &parseErr) {
+		t.Errorf("err = %v, want size limit error that is not a ParseError", err)
+	}
+}
@@ -0,0 +1,210 @@
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ManifestAnalyzer implements the Analyzer interface for Python projects that
+// commit a manifest (pyproject.toml or Pipfile) but no lock file. It reports
+// each declared dependency with its Constraint and an empty Version, so such
+// repositories show what they require rather than what they resolve to.
+type ManifestAnalyzer struct {
+	name     AnalyzerType
+	fileName string
+	sections func(doc map[string]any) []manifestSection
+}
+
+// NewPyprojectAnalyzer creates an analyzer for the dependencies declared in
+// pyproject.toml files (PEP 621, PEP 735 groups, uv and Poetry tables)
+func NewPyprojectAnalyzer() *ManifestAnalyzer {
+	return &ManifestAnalyzer{name: AnalyzerPyproject, fileName: pyprojectManifest, sections: pyprojectSections}
+}
+
+// NewPipfileManifestAnalyzer creates an analyzer for the dependencies
+// declared in Pipfiles (not Pipfile.lock)
+func NewPipfileManifestAnalyzer() *ManifestAnalyzer {
+	return &ManifestAnalyzer{name: AnalyzerPipfileManifest, fileName: pipfileManifest, sections: pipfileSections}
+}
+
+// Name returns the name of this analyzer
+func (m *ManifestAnalyzer) Name() string {
+	return string(m.name)
+}
+
+// Ecosystem returns EcosystemPython
+func (m *ManifestAnalyzer) Ecosystem() Ecosystem {
+	return EcosystemPython
+}
+
+// CandidateFiles searches for manifests in the configured repository paths
+func (m *ManifestAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	searchPaths := config.RepositoryPaths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{""}
+	}
+
+	var candidates []DependencyFile
+	for _, searchPath := range searchPaths {
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		for _, file := range files {
+			if file.Type != "file" || path.Base(file.Path) != m.fileName {
+				continue
+			}
+			if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
+				continue
+			}
+			candidates = append(candidates, DependencyFile{
+				Path:     file.Path,
+				Type:     m.fileName,
+				Analyzer: m.Name(),
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// AnalyzeDependencies reads the declared dependencies of each manifest
+func (m *ManifestAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, ref string, files []DependencyFile, config Config) (map[string][]Dependency, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	result := make(map[string][]Dependency)
+	for _, file := range files {
+		deps, err := traceFile(ctx, m.Name(), file.Path, func(ctx context.Context) ([]Dependency, error) {
+			return m.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		})
+		if err != nil {
+			// Don't fail completely if one file fails, just skip it
+			slog.Debug("Failed to analyze manifest",
+				"file", file.Path,
+				"owner", owner,
+				"repo", repo,
+				"ref", ref,
+				"error", err)
+			config.reportFileError(file.Path, err)
+			continue
+		}
+		result[file.Path] = deps
+	}
+
+	return result, nil
+}
+
+// analyzeFile reads the declared dependencies of a single manifest
+func (m *ManifestAnalyzer) analyzeFile(ctx context.Context, owner, repo, ref, filePath string, config Config) ([]Dependency, error) {
+	body, err := openFileStream(ctx, config, owner, repo, ref, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
+	}
+	defer body.Close()
+
+	deps, err := m.decodeManifest(body)
+	if body.err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, body.err)
+	}
+	if err != nil {
+		return nil, &ParseError{Path: filePath, Err: err}
+	}
+	return deps, nil
+}
+
+// decodeManifest parses a manifest read from r
+func (m *ManifestAnalyzer) decodeManifest(r io.Reader) ([]Dependency, error) {
+	var doc map[string]any
+	if _, err := toml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", m.fileName, err)
+	}
+	return sectionDependencies(m.sections(doc)), nil
+}
+
+// sectionDependencies lists the dependencies declared in sections, keeping
+// the first declaration of each package. Versions are empty; Constraint holds
+// the declared requirement.
+func sectionDependencies(sections []manifestSection) []Dependency {
+	var deps []Dependency
+	seen := make(map[string]bool)
+	add := func(d Dependency) {
+		key := normalizeManifestName(d.Name)
+		if key == "python" || seen[key] {
+			return
+		}
+		seen[key] = true
+		d.Direct = true
+		deps = append(deps, d)
+	}
+	for _, s := range sections {
+		if s.table == nil {
+			for _, v := range s.specs {
+				spec, ok := v.(string)
+				if !ok {
+					continue // e.g. {include-group = "..."} entries
+				}
+				if name, constraint := parsePEP508(spec); name != "" {
+					add(Dependency{Name: name, Constraint: constraint, Type: s.kind, Source: pep508Source(spec)})
+				}
+			}
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.table)) {
+			d := Dependency{Name: name, Type: s.kind, Source: "pypi"}
+			switch val := s.table[name].(type) {
+			case string:
+				d.Constraint = val
+			case map[string]any:
+				d.Constraint, _ = val["version"].(string)
+				d.Source = tableSource(val)
+			}
+			add(d)
+		}
+	}
+	return deps
+}
+
+// pep508Source maps a PEP 508 requirement to a Dependency.Source: direct
+// references ("name @ url") are "git" or "url", anything else "pypi"
+func pep508Source(spec string) string {
+	if i := strings.Index(spec, ";"); i >= 0 {
+		spec = spec[:i]
+	}
+	_, ref, ok := strings.Cut(spec, "@")
+	switch {
+	case !ok:
+		return "pypi"
+	case strings.HasPrefix(strings.TrimSpace(ref), "git+"):
+		return "git"
+	default:
+		return "url"
+	}
+}
+
+// tableSource maps a Poetry/Pipfile dependency table to a Dependency.Source
+func tableSource(table map[string]any) string {
+	switch {
+	case table["git"] != nil:
+		return "git"
+	case table["path"] != nil:
+		return "path"
+	case table["url"] != nil || table["file"] != nil:
+		return "url"
+	default:
+		return "pypi"
+	}
+}
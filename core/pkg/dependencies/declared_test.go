@@ -0,0 +1,128 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+func TestManifestAnalyzers(t *testing.T) {
+	client := &mockRepoClient{
+		files: []repository.FileInfo{
+			{Path: "pyproject.toml", Type: "file"},
+			{Path: "svc/Pipfile", Type: "file"},
+			{Path: "svc/Pipfile.lock", Type: "file"},
+			{Path: "broken/pyproject.toml", Type: "file"},
+		},
+		byPath: map[string]string{
+			"pyproject.toml": `
+[project]
+dependencies = ["Requests>=2.31", "lib @ git+https://example.com/lib.git"]
+
+[project.optional-dependencies]
+test = ["pytest>=7", "requests<3"]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+localpkg = {path = "../localpkg"}
+`,
+			"svc/Pipfile": `
+[packages]
+django = {version = ">=4.2", extras = ["argon2"]}
+
+[dev-packages]
+black = "*"
+`,
+			"broken/pyproject.toml": "not = [valid",
+		},
+	}
+
+	tests := []struct {
+		analyzer *ManifestAnalyzer
+		files    []string
+		want     map[string]Dependency // file -> name -> expected
+	}{
+		{
+			analyzer: NewPyprojectAnalyzer(),
+			files:    []string{"pyproject.toml", "broken/pyproject.toml"},
+			want: map[string]Dependency{
+				"Requests": {Constraint: ">=2.31", Type: "runtime", Source: "pypi"},
+				"lib":      {Type: "runtime", Source: "git"},
+				"pytest":   {Constraint: ">=7", Type: "optional", Source: "pypi"},
+				"localpkg": {Type: "runtime", Source: "path"},
+			},
+		},
+		{
+			analyzer: NewPipfileManifestAnalyzer(),
+			files:    []string{"svc/Pipfile"},
+			want: map[string]Dependency{
+				"django": {Constraint: ">=4.2", Type: "runtime", Source: "pypi"},
+				"black":  {Constraint: "*", Type: "dev", Source: "pypi"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.analyzer.Name(), func(t *testing.T) {
+			var fileErrs []error
+			config := Config{RepositoryClient: client, OnFileError: func(_ string, err error) { fileErrs = append(fileErrs, err) }}
+			candidates, err := tt.analyzer.CandidateFiles(context.Background(), "o", "r", "main", config)
+			if err != nil {
+				t.Fatalf("CandidateFiles: %v", err)
+			}
+			var paths []string
+			for _, c := range candidates {
+				paths = append(paths, c.Path)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.files, ",") {
+				t.Fatalf("candidates = %v, want %v", paths, tt.files)
+			}
+
+			results, err := tt.analyzer.AnalyzeDependencies(context.Background(), "o", "r", "main", candidates, config)
+			if err != nil {
+				t.Fatalf("AnalyzeDependencies: %v", err)
+			}
+			deps := results[tt.files[0]]
+			if len(deps) != len(tt.want) {
+				t.Errorf("got %d dependencies, want %d: %+v", len(deps), len(tt.want), deps)
+			}
+			for _, d := range deps {
+				want, ok := tt.want[d.Name]
+				if !ok {
+					t.Errorf("unexpected dependency %+v", d)
+					continue
+				}
+				if d.Version != "" || !d.Direct || d.Constraint != want.Constraint || d.Type != want.Type || d.Source != want.Source {
+					t.Errorf("%s = %+v, want %+v", d.Name, d, want)
+				}
+			}
+
+			var parseErr *ParseError
+			if len(tt.files) > 1 && (len(fileErrs) != 1 || !errors.As(fileErrs[0], &parseErr)) {
+				t.Errorf("file errors = %v, want one parse error", fileErrs)
+			}
+		})
+	}
+}
+
+func TestManifestFallback(t *testing.T) {
+	for _, name := range []AnalyzerType{AnalyzerPoetry, AnalyzerUvLock, AnalyzerPipfile, AnalyzerPdm, AnalyzerHatch} {
+		a, err := NewAnalyzer(string(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fb, ok := a.(ManifestFallback)
+		if !ok {
+			t.Fatalf("%s does not implement ManifestFallback", name)
+		}
+		if _, ok := fb.ManifestAnalyzer().(*ManifestAnalyzer); !ok {
+			t.Errorf("%s falls back to %T", name, fb.ManifestAnalyzer())
+		}
+	}
+	deps, err := ParseLockFile("pipfile-manifest", strings.NewReader("[packages]\nrequests = \"==2.31.0\"\n"))
+	if err != nil || len(deps) != 1 || deps[0].Constraint != "==2.31.0" {
+		t.Errorf("ParseLockFile = %+v, %v", deps, err)
+	}
+}
@@ -6,6 +6,10 @@ package dependencies
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/repository"
 )
@@ -16,6 +20,32 @@ type Dependency struct {
 	Version string // Currently specified version (e.g., "1.2.3", "^2.0.0", ">=1.0.0")
 	Type    string // Type of dependency (e.g., "runtime", "dev", "optional")
 	Source  string // Source/registry (e.g., "pypi", "npm", "rubygems")
+
+	// Registry is the URL of the package index the package was resolved from
+	// (e.g. PyPIRegistry or a private mirror), when the lock file records it.
+	// Empty for git, path and url sources.
+	Registry string
+
+	// Hashes are the artifact digests (e.g. "sha256:…") the lock file pins
+	// for the package's wheels and source distribution, sorted. Empty when
+	// the lock file records none.
+	Hashes []string
+
+	// Constraint is the version requirement declared in the project manifest
+	// (e.g., "^2.31", ">=1.0,<2"). Empty unless Config.IncludeConstraints is set
+	// and the package is declared directly in a manifest next to the lock file,
+	// or the dependency comes from a ManifestAnalyzer (then Version is empty).
+	Constraint string
+
+	// Requires names the packages this one depends on, as recorded in lock
+	// files that keep dependency edges (uv.lock, poetry.lock)
+	Requires []string
+
+	// Direct is true when the project itself requires the package: it is
+	// declared in the manifest next to the lock file (needs
+	// Config.IncludeConstraints) or required by the project's own entry in
+	// uv.lock. False means transitive or unknown.
+	Direct bool
 }
 
 // DependencyFile represents a file that contains dependency information
@@ -32,9 +62,81 @@ type Config struct {
 	// Examples: []string{"src", "packages"} or []string{""} for root
 	RepositoryPaths []string
 
+	// ExcludePaths are glob patterns (path.Match syntax) of repository
+	// paths to skip, e.g. vendored or fixture lock files; see Excluded
+	ExcludePaths []string
+
 	// RepositoryClient is the repository client implementation used to
 	// fetch files from the repository
 	RepositoryClient repository.Client
+
+	// OnFileError, if set, is called for each dependency file that analyzers
+	// skip because it could not be fetched or parsed (e.g. *ParseError)
+	OnFileError func(path string, err error)
+
+	// IncludeConstraints makes analyzers also read the manifest next to each
+	// lock file (pyproject.toml, Pipfile) and populate Dependency.Constraint
+	IncludeConstraints bool
+}
+
+// Excluded reports whether the repository file p matches one of
+// ExcludePaths. A pattern containing a slash matches the path or any
+// directory above it, so "tests/fixtures" and "examples/*" exclude everything
+// beneath them; a pattern without one matches any single path element, e.g.
+// "fixtures" or "vendor*" at any depth.
+func (c Config) Excluded(p string) bool {
+	for _, pattern := range c.ExcludePaths {
+		pattern = strings.Trim(pattern, "/")
+		if pattern == "" {
+			continue
+		}
+		if !strings.Contains(pattern, "/") {
+			for _, elem := range strings.Split(p, "/") {
+				if ok, _ := path.Match(pattern, elem); ok {
+					return true
+				}
+			}
+			continue
+		}
+		for dir := strings.Trim(p, "/"); dir != "." && dir != ""; dir = path.Dir(dir) {
+			if ok, _ := path.Match(pattern, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsDependencyFile reports whether the built-in analyzers read the file at
+// p: a lock file, a manifest or a pre-commit configuration. Callers use it to
+// prefetch the files an analysis is likely to need (see
+// repository.NewArchiveClient).
+func IsDependencyFile(p string) bool {
+	base := path.Base(p)
+	switch {
+	case strings.HasSuffix(base, "poetry.lock"), strings.HasSuffix(base, "Pipfile.lock"), strings.HasSuffix(base, "uv.lock"):
+		return true
+	case base == "pdm.lock" || base == pyprojectManifest || base == pipfileManifest:
+		return true
+	}
+	return slices.Contains(preCommitConfigFiles, base) || hatchLockEnvironment(p) != ""
+}
+
+// ValidateExcludePaths returns an error for the first malformed pattern
+func ValidateExcludePaths(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// reportFileError forwards a skipped-file error to config.OnFileError if set
+func (c Config) reportFileError(path string, err error) {
+	if c.OnFileError != nil {
+		c.OnFileError(path, err)
+	}
 }
 
 // Analyzer defines the interface for analyzing dependency files
@@ -87,8 +189,28 @@ const (
 	AnalyzerPipfile AnalyzerType = "pipfile"
 	// AnalyzerUvLock represents Python uv.lock dependency analyzer
 	AnalyzerUvLock AnalyzerType = "uvlock"
+	// AnalyzerPdm represents Python pdm.lock dependency analyzer
+	AnalyzerPdm AnalyzerType = "pdm"
+	// AnalyzerHatch represents Python Hatch (hatch.lock and hatch-pip-compile
+	// requirements) dependency analyzer
+	AnalyzerHatch AnalyzerType = "hatch"
+	// AnalyzerPreCommit represents the pre-commit hook repository analyzer
+	AnalyzerPreCommit AnalyzerType = "pre-commit"
+	// AnalyzerPyproject represents the analyzer of dependencies declared in
+	// pyproject.toml, for projects without a lock file
+	AnalyzerPyproject AnalyzerType = "pyproject"
+	// AnalyzerPipfileManifest represents the analyzer of dependencies
+	// declared in a Pipfile, for projects without Pipfile.lock
+	AnalyzerPipfileManifest AnalyzerType = "pipfile-manifest"
 )
 
+// ManifestFallback is implemented by lock file analyzers whose projects may
+// commit only a manifest. When such an analyzer finds no lock file, reports
+// fall back to the returned analyzer and mark the repository constraint-only.
+type ManifestFallback interface {
+	ManifestAnalyzer() Analyzer
+}
+
 // Result contains the complete dependency analysis for a repository
 type Result struct {
 	// Repository information
@@ -0,0 +1,57 @@
+package dependencies
+
+import "testing"
+
+func TestConfigExcluded(t *testing.T) {
+	config := Config{ExcludePaths: []string{"fixtures", "vendor*", "examples/*", "/docs/poetry.lock", "[bad"}}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"poetry.lock", false},
+		{"tests/fixtures/poetry.lock", true},
+		{"fixtures/uv.lock", true},
+		{"third_party/vendored/Pipfile.lock", true},
+		{"examples/demo/sub/poetry.lock", true},
+		{"examples/poetry.lock", true},
+		{"src/examples/poetry.lock", false},
+		{"docs/poetry.lock", true},
+		{"docs/guide/poetry.lock", false},
+		{"services/api/poetry.lock", false},
+	}
+	for _, tt := range tests {
+		if got := config.Excluded(tt.path); got != tt.want {
+			t.Errorf("Excluded(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if (Config{}).Excluded("tests/fixtures/poetry.lock") {
+		t.Error("no patterns should exclude nothing")
+	}
+}
+
+func TestIsDependencyFile(t *testing.T) {
+	for p, want := range map[string]bool{
+		"poetry.lock":                        true,
+		"services/api/uv.lock":               true,
+		"Pipfile":                            true,
+		"Pipfile.lock":                       true,
+		"backend/pyproject.toml":             true,
+		".pre-commit-config.yaml":            true,
+		"requirements/requirements-docs.txt": true,
+		"src/app/main.py":                    false,
+		"README.md":                          false,
+	} {
+		if got := IsDependencyFile(p); got != want {
+			t.Errorf("IsDependencyFile(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestValidateExcludePaths(t *testing.T) {
+	if err := ValidateExcludePaths([]string{"fixtures", "examples/*"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateExcludePaths([]string{"fixtures", "[bad"}); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
@@ -0,0 +1,21 @@
+package dependencies
+
+import "fmt"
+
+// ParseError reports a dependency file whose content could not be parsed.
+// Callers can detect it with errors.As to distinguish malformed lock files
+// from provider or lookup failures.
+type ParseError struct {
+	Path string // Repository path of the file that failed to parse
+	Err  error  // Underlying decoder error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying decoder error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,249 @@
+package dependencies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+)
+
+// DefaultExecTimeout bounds one run of an ExecAnalyzer's command when its
+// Timeout is zero
+const DefaultExecTimeout = time.Minute
+
+// Exec plugin commands, sent as ExecRequest.Command
+const (
+	ExecCandidateFiles = "candidate-files"
+	ExecAnalyze        = "analyze"
+)
+
+// ExecAnalyzer is an Analyzer implemented by an external program, so
+// analyzers can be written in any language without rebuilding devdashboard.
+// The program is run once per command with an ExecRequest as JSON on stdin
+// and must print an ExecResponse as JSON on stdout; a non-zero exit status
+// fails the command. devdashboard does all repository access: the program
+// picks lock files from a listing and parses their content.
+type ExecAnalyzer struct {
+	AnalyzerName     string        // Analyzer type the program is registered as
+	Command          []string      // Program and arguments (not run through a shell)
+	Timeout          time.Duration // Per-run limit; DefaultExecTimeout when zero
+	PackageEcosystem Ecosystem     // Naming and versioning rules of the packages reported
+}
+
+// ExecRequest is the JSON document written to an ExecAnalyzer's stdin
+type ExecRequest struct {
+	Command    string `json:"command"` // ExecCandidateFiles or ExecAnalyze
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	Ref        string `json:"ref"`
+	// Paths are the configured search paths (candidate-files only)
+	Paths []string `json:"paths,omitempty"`
+	// Files lists every file under Paths for candidate-files, and the chosen
+	// candidates with their content for analyze
+	Files []ExecFile `json:"files"`
+}
+
+// ExecFile is a repository file in an ExecRequest or ExecResponse
+type ExecFile struct {
+	Path    string `json:"path"`
+	Type    string `json:"type,omitempty"`    // Lock file type, e.g. "Cargo.lock" (set by the program)
+	Size    int64  `json:"size,omitempty"`    // Bytes (candidate-files requests)
+	Content string `json:"content,omitempty"` // File content (analyze requests)
+}
+
+// ExecResponse is the JSON document an ExecAnalyzer's program prints
+type ExecResponse struct {
+	// Files are the lock files to analyze (candidate-files)
+	Files []ExecFile `json:"files,omitempty"`
+	// Dependencies maps each analyzed path to its locked packages (analyze)
+	Dependencies map[string][]ExecDependency `json:"dependencies,omitempty"`
+	// Errors maps paths that could not be parsed to a message (analyze)
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ExecDependency is one locked package in an ExecResponse
+type ExecDependency struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Type     string   `json:"type,omitempty"`     // runtime (default), dev or optional
+	Source   string   `json:"source,omitempty"`   // Registry or git, path, url
+	Registry string   `json:"registry,omitempty"` // Package index URL (see Dependency.Registry)
+	Hashes   []string `json:"hashes,omitempty"`   // Artifact digests (see Dependency.Hashes)
+	Requires []string `json:"requires,omitempty"`
+	Direct   bool     `json:"direct,omitempty"`
+}
+
+// Name returns the analyzer type the program is registered as
+func (e *ExecAnalyzer) Name() string {
+	return e.AnalyzerName
+}
+
+// Ecosystem returns the configured package ecosystem
+func (e *ExecAnalyzer) Ecosystem() Ecosystem {
+	return e.PackageEcosystem
+}
+
+// CandidateFiles lists the files under the configured paths and lets the
+// program pick the lock files among them
+func (e *ExecAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	searchPaths := config.RepositoryPaths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{""}
+	}
+	req := ExecRequest{Command: ExecCandidateFiles, Owner: owner, Repository: repo, Ref: ref, Paths: searchPaths, Files: []ExecFile{}}
+	for _, searchPath := range searchPaths {
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		for _, file := range files {
+			if file.Type != "file" {
+				continue
+			}
+			if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
+				continue
+			}
+			req.Files = append(req.Files, ExecFile{Path: file.Path, Size: file.Size})
+		}
+	}
+
+	res, err := e.run(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]DependencyFile, 0, len(res.Files))
+	for _, f := range res.Files {
+		candidates = append(candidates, DependencyFile{Path: f.Path, Type: f.Type, Analyzer: e.Name()})
+	}
+	return candidates, nil
+}
+
+// AnalyzeDependencies sends the content of the files to the program and
+// returns the packages it reports. Files that cannot be fetched or that the
+// program reports errors for are skipped.
+func (e *ExecAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, ref string, files []DependencyFile, config Config) (map[string][]Dependency, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	req := ExecRequest{Command: ExecAnalyze, Owner: owner, Repository: repo, Ref: ref, Files: []ExecFile{}}
+	for _, file := range files {
+		content, err := readFile(ctx, config, owner, repo, ref, file.Path)
+		if err != nil {
+			slog.Debug("Failed to fetch file for exec analyzer",
+				"analyzer", e.Name(),
+				"file", file.Path,
+				"error", err)
+			config.reportFileError(file.Path, err)
+			continue
+		}
+		req.Files = append(req.Files, ExecFile{Path: file.Path, Type: file.Type, Content: content})
+	}
+	result := make(map[string][]Dependency)
+	if len(req.Files) == 0 {
+		return result, nil
+	}
+
+	res, err := e.run(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for path, msg := range res.Errors {
+		config.reportFileError(path, &ParseError{Path: path, Err: fmt.Errorf("%s", msg)})
+	}
+	for path, deps := range res.Dependencies {
+		if _, failed := res.Errors[path]; failed {
+			continue
+		}
+		converted := make([]Dependency, 0, len(deps))
+		for _, d := range deps {
+			depType := d.Type
+			if depType == "" {
+				depType = "runtime"
+			}
+			converted = append(converted, Dependency{
+				Name:     d.Name,
+				Version:  d.Version,
+				Type:     depType,
+				Source:   d.Source,
+				Registry: d.Registry,
+				Hashes:   sortedHashes(d.Hashes),
+				Requires: d.Requires,
+				Direct:   d.Direct,
+			})
+		}
+		result[path] = converted
+	}
+	return result, nil
+}
+
+// readFile fetches a whole file within the configured size limit
+func readFile(ctx context.Context, config Config, owner, repo, ref, path string) (string, error) {
+	body, err := openFileStream(ctx, config, owner, repo, ref, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file content for %s: %w", path, err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file content for %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// run executes the program with req on stdin and decodes its response
+func (e *ExecAnalyzer) run(ctx context.Context, req ExecRequest) (_ *ExecResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "dependencies.exec",
+		attribute.String("devdashboard.analyzer", e.Name()),
+		attribute.String("devdashboard.command", req.Command),
+		attribute.Int("devdashboard.files", len(req.Files)))
+	defer func() { telemetry.End(span, err) }()
+
+	if len(e.Command) == 0 {
+		return nil, fmt.Errorf("exec analyzer %q has no command", e.Name())
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", req.Command, err)
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExecTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// #nosec G204 -- the command comes from the user's own configuration file
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("exec analyzer %s %s failed: %w: %s", e.Name(), req.Command, err, msg)
+		}
+		return nil, fmt.Errorf("exec analyzer %s %s failed: %w", e.Name(), req.Command, err)
+	}
+
+	var res ExecResponse
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return nil, fmt.Errorf("failed to decode exec analyzer %s %s output: %w", e.Name(), req.Command, err)
+	}
+	return &res, nil
+}
@@ -0,0 +1,150 @@
+package dependencies
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// TestExecPluginProcess is not a real test: it is the plugin program run by
+// the tests below, answering requests for "name==version" lines in
+// deps.txt files
+func TestExecPluginProcess(t *testing.T) {
+	if os.Getenv("DEVDASHBOARD_EXEC_PLUGIN") != "1" {
+		t.Skip("exec plugin helper process")
+	}
+	var req ExecRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		os.Exit(2)
+	}
+	var res ExecResponse
+	switch req.Command {
+	case ExecCandidateFiles:
+		for _, f := range req.Files {
+			if strings.HasSuffix(f.Path, "deps.txt") {
+				res.Files = append(res.Files, ExecFile{Path: f.Path, Type: "deps.txt"})
+			}
+		}
+	case ExecAnalyze:
+		res.Dependencies = map[string][]ExecDependency{}
+		res.Errors = map[string]string{}
+		for _, f := range req.Files {
+			for _, line := range strings.Fields(f.Content) {
+				name, version, ok := strings.Cut(line, "==")
+				if !ok {
+					res.Errors[f.Path] = "bad line " + line
+					break
+				}
+				res.Dependencies[f.Path] = append(res.Dependencies[f.Path], ExecDependency{Name: name, Version: version, Source: req.Owner})
+			}
+		}
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(res)
+	os.Exit(0)
+}
+
+func newTestExecAnalyzer(t *testing.T) *ExecAnalyzer {
+	t.Helper()
+	t.Setenv("DEVDASHBOARD_EXEC_PLUGIN", "1")
+	return &ExecAnalyzer{
+		AnalyzerName:     "deps",
+		Command:          []string{os.Args[0], "-test.run=^TestExecPluginProcess$"},
+		PackageEcosystem: EcosystemPython,
+	}
+}
+
+func TestExecAnalyzer(t *testing.T) {
+	a := newTestExecAnalyzer(t)
+	var skipped []string
+	config := Config{
+		RepositoryClient: &mockRepoClient{
+			files: []repository.FileInfo{
+				{Path: "app/deps.txt", Type: "file"},
+				{Path: "lib/deps.txt", Type: "file"},
+				{Path: "README.md", Type: "file"},
+				{Path: "docs", Type: "dir"},
+			},
+			byPath: map[string]string{
+				"app/deps.txt": "requests==2.31.0\ndjango==4.2.11\n",
+				"lib/deps.txt": "not-pinned\n",
+			},
+		},
+		OnFileError: func(path string, _ error) { skipped = append(skipped, path) },
+	}
+	ctx := context.Background()
+
+	files, err := a.CandidateFiles(ctx, "org", "api", "main", config)
+	if err != nil {
+		t.Fatalf("CandidateFiles: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != "app/deps.txt" || files[0].Type != "deps.txt" || files[0].Analyzer != "deps" {
+		t.Fatalf("CandidateFiles = %+v, want app/deps.txt and lib/deps.txt", files)
+	}
+
+	deps, err := a.AnalyzeDependencies(ctx, "org", "api", "main", files, config)
+	if err != nil {
+		t.Fatalf("AnalyzeDependencies: %v", err)
+	}
+	got := deps["app/deps.txt"]
+	if len(got) != 2 || got[1].Name != "django" || got[1].Version != "4.2.11" || got[1].Type != "runtime" || got[1].Source != "org" {
+		t.Errorf("app/deps.txt = %+v", got)
+	}
+	if _, ok := deps["lib/deps.txt"]; ok || len(skipped) != 1 || skipped[0] != "lib/deps.txt" {
+		t.Errorf("lib/deps.txt should be skipped: deps %+v, skipped %v", deps, skipped)
+	}
+}
+
+func TestExecAnalyzerFailure(t *testing.T) {
+	config := Config{RepositoryClient: &mockRepoClient{}}
+	tests := []struct {
+		name    string
+		command []string
+		want    string
+	}{
+		{name: "exit status", command: []string{"sh", "-c", "echo broken >&2; exit 1"}, want: "broken"},
+		{name: "invalid output", command: []string{"sh", "-c", "echo not json"}, want: "failed to decode"},
+		{name: "no command", want: "has no command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &ExecAnalyzer{AnalyzerName: "deps", Command: tt.command}
+			_, err := a.CandidateFiles(context.Background(), "org", "api", "main", config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CandidateFiles error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterExec(t *testing.T) {
+	a := &ExecAnalyzer{AnalyzerName: "Deps", Command: []string{"deps-analyzer"}, PackageEcosystem: EcosystemPython}
+	if err := RegisterExec(a); err != nil {
+		t.Fatalf("RegisterExec: %v", err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "deps")
+		registryMu.Unlock()
+	})
+	if got := EcosystemForAnalyzer("deps"); got != EcosystemPython {
+		t.Errorf("EcosystemForAnalyzer(deps) = %q, want python", got)
+	}
+
+	// Reloading a configuration replaces the program
+	b := &ExecAnalyzer{AnalyzerName: "deps", Command: []string{"deps-analyzer-v2"}}
+	if err := RegisterExec(b); err != nil {
+		t.Fatalf("RegisterExec replacing: %v", err)
+	}
+	if analyzer, _ := NewAnalyzer("deps"); analyzer != Analyzer(b) {
+		t.Errorf("NewAnalyzer(deps) = %+v, want the replacement", analyzer)
+	}
+
+	err := RegisterExec(&ExecAnalyzer{AnalyzerName: "uvlock", Command: []string{"x"}})
+	if err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("shadowing a built-in analyzer: err = %v", err)
+	}
+}
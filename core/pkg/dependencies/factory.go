@@ -2,6 +2,7 @@ package dependencies
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -18,22 +19,16 @@ func NewFactory() *Factory {
 //   - "poetry" - Creates a Poetry (Python) analyzer
 //   - "pipfile" - Creates a Pipfile (Python) analyzer
 //   - "uvlock" - Creates a uv.lock (Python) analyzer
+//   - "pdm" - Creates a pdm.lock (Python) analyzer
+//   - "hatch" - Creates a Hatch lock file (Python) analyzer
+//   - "pyproject" - Creates a pyproject.toml (Python, declared dependencies only) analyzer
+//   - "pipfile-manifest" - Creates a Pipfile (Python, declared dependencies only) analyzer
+//   - "pre-commit" - Creates a .pre-commit-config.yaml (hook repositories) analyzer
+//   - any name added with Register
 //
 // Returns an error if the analyzer type is not recognized
 func (f *Factory) CreateAnalyzer(analyzerType string) (Analyzer, error) {
-	// Normalize analyzer type to lowercase for comparison
-	normalized := strings.ToLower(strings.TrimSpace(analyzerType))
-
-	switch AnalyzerType(normalized) {
-	case AnalyzerPoetry:
-		return NewPoetryAnalyzer(), nil
-	case AnalyzerPipfile:
-		return NewPipfileAnalyzer(), nil
-	case AnalyzerUvLock:
-		return NewUvLockAnalyzer(), nil
-	default:
-		return nil, fmt.Errorf("unsupported analyzer type: %s (supported: poetry, pipfile, uvlock)", analyzerType)
-	}
+	return create(analyzerType)
 }
 
 // NewAnalyzer is a convenience function that creates a dependency analyzer
@@ -44,11 +39,34 @@ func NewAnalyzer(analyzerType string) (Analyzer, error) {
 	return factory.CreateAnalyzer(analyzerType)
 }
 
-// SupportedAnalyzers returns a list of all supported analyzer types
-func SupportedAnalyzers() []string {
-	return []string{
-		string(AnalyzerPoetry),
-		string(AnalyzerPipfile),
-		string(AnalyzerUvLock),
+// ParseLockFile parses a lock file (or, for the manifest analyzers, a
+// manifest) handled by analyzerType from r, for files read outside a
+// repository client (e.g. in a local clone)
+func ParseLockFile(analyzerType string, r io.Reader) ([]Dependency, error) {
+	switch AnalyzerType(strings.ToLower(strings.TrimSpace(analyzerType))) {
+	case AnalyzerPoetry:
+		return NewPoetryAnalyzer().decodePoetryLock(r)
+	case AnalyzerPipfile:
+		return NewPipfileAnalyzer().decodePipfileLock(r)
+	case AnalyzerUvLock:
+		return NewUvLockAnalyzer().decodeUvLock(r)
+	case AnalyzerPdm:
+		return NewPdmAnalyzer().decodePdmLock(r)
+	case AnalyzerHatch:
+		return NewHatchAnalyzer().decodeHatchLock(r)
+	case AnalyzerPyproject:
+		return NewPyprojectAnalyzer().decodeManifest(r)
+	case AnalyzerPipfileManifest:
+		return NewPipfileManifestAnalyzer().decodeManifest(r)
+	case AnalyzerPreCommit:
+		return NewPreCommitAnalyzer().decodePreCommitConfig(r)
+	default:
+		return nil, fmt.Errorf("unsupported analyzer type: %s (supported: poetry, pipfile, uvlock, pdm, hatch, pyproject, pipfile-manifest, pre-commit)", analyzerType)
 	}
 }
+
+// SupportedAnalyzers returns a list of all supported analyzer types: the
+// built-in ones followed by those added with Register
+func SupportedAnalyzers() []string {
+	return registeredAnalyzers()
+}
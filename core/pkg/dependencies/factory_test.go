@@ -1,6 +1,7 @@
 package dependencies
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -218,9 +219,14 @@ func TestSupportedAnalyzers(t *testing.T) {
 
 	// Check that expected analyzers are in the list
 	expectedAnalyzers := map[string]bool{
-		"poetry":  false,
-		"pipfile": false,
-		"uvlock":  false,
+		"poetry":           false,
+		"pipfile":          false,
+		"uvlock":           false,
+		"pdm":              false,
+		"hatch":            false,
+		"pyproject":        false,
+		"pipfile-manifest": false,
+		"pre-commit":       false,
 	}
 
 	for _, analyzer := range analyzers {
@@ -276,3 +282,23 @@ func TestUvLockAnalyzerName(t *testing.T) {
 		t.Errorf("Expected name 'uvlock', got '%s'", analyzer.Name())
 	}
 }
+
+// TestParseLockFile tests parsing a local lock file by analyzer type
+func TestParseLockFile(t *testing.T) {
+	lock := `
+[[package]]
+name = "django"
+version = "4.2.11"
+`
+	deps, err := ParseLockFile("Poetry", strings.NewReader(lock))
+	if err != nil {
+		t.Fatalf("ParseLockFile failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].Name != "django" || deps[0].Version != "4.2.11" {
+		t.Errorf("unexpected dependencies: %+v", deps)
+	}
+
+	if _, err := ParseLockFile("npm", strings.NewReader("")); err == nil {
+		t.Error("expected error for unsupported analyzer type")
+	}
+}
@@ -0,0 +1,150 @@
+package dependencies
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Graph is the package dependency graph of one repository, merged across its
+// lock files. Node names are normalized for the ecosystem, so edges match
+// regardless of how a lock file spells a package.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a locked package
+type GraphNode struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Type    string   `json:"type,omitempty"`   // Dependency.Type
+	Direct  bool     `json:"direct,omitempty"` // Dependency.Direct in any lock file
+	Files   []string `json:"files"`            // Lock files that lock the package
+}
+
+// GraphEdge records that From requires To
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// BuildGraph assembles a graph from analyzer results (lock file path to its
+// dependencies). Edges to packages missing from every lock file (e.g. an
+// optional extra that was not installed) are dropped. Lock files without
+// dependency edges (Pipfile.lock) contribute nodes only. Returns nil when
+// there are no dependencies at all.
+func BuildGraph(eco Ecosystem, results map[string][]Dependency) *Graph {
+	paths := make([]string, 0, len(results))
+	for path := range results {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	nodes := make(map[string]GraphNode)
+	requires := make(map[string][]string)
+	for _, path := range paths {
+		for _, dep := range results[path] {
+			name := NormalizeName(eco, dep.Name)
+			node, ok := nodes[name]
+			if !ok {
+				node = GraphNode{Name: name, Version: dep.Version, Type: dep.Type}
+			}
+			node.Direct = node.Direct || dep.Direct
+			if !slices.Contains(node.Files, path) {
+				node.Files = append(node.Files, path)
+			}
+			nodes[name] = node
+			for _, req := range dep.Requires {
+				requires[name] = append(requires[name], NormalizeName(eco, req))
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	g := &Graph{}
+	for _, node := range nodes {
+		g.Nodes = append(g.Nodes, node)
+	}
+	slices.SortFunc(g.Nodes, func(a, b GraphNode) int { return strings.Compare(a.Name, b.Name) })
+	for _, node := range g.Nodes {
+		targets := requires[node.Name]
+		slices.Sort(targets)
+		for _, to := range slices.Compact(targets) {
+			if _, ok := nodes[to]; ok && to != node.Name {
+				g.Edges = append(g.Edges, GraphEdge{From: node.Name, To: to})
+			}
+		}
+	}
+	return g
+}
+
+// Node returns the node named name (normalized), if present
+func (g *Graph) Node(name string) (GraphNode, bool) {
+	i, found := slices.BinarySearchFunc(g.Nodes, name, func(n GraphNode, target string) int {
+		return strings.Compare(n.Name, target)
+	})
+	if !found {
+		return GraphNode{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// Requires lists the packages name depends on directly
+func (g *Graph) Requires(name string) []string {
+	var out []string
+	for _, e := range g.Edges {
+		if e.From == name {
+			out = append(out, e.To)
+		}
+	}
+	return out
+}
+
+// Dependents answers "what depends on name?": the packages requiring it
+// directly, or with transitive set, every package from which it is reachable.
+// Results are sorted.
+func (g *Graph) Dependents(name string, transitive bool) []string {
+	reverse := make(map[string][]string)
+	for _, e := range g.Edges {
+		reverse[e.To] = append(reverse[e.To], e.From)
+	}
+
+	seen := map[string]bool{name: true}
+	queue := []string{name}
+	var out []string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, from := range reverse[current] {
+			if seen[from] {
+				continue
+			}
+			seen[from] = true
+			out = append(out, from)
+			if transitive {
+				queue = append(queue, from)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// WriteDOT writes the graph in Graphviz DOT format as a digraph called name
+func (g *Graph) WriteDOT(w io.Writer, name string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", n.Name, n.Name+"\n"+n.Version)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,114 @@
+package dependencies
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuildGraph(t *testing.T) {
+	results := map[string][]Dependency{
+		"uv.lock": {
+			{Name: "app", Version: "0.1.0", Requires: []string{"Requests", "missing-extra"}},
+			{Name: "requests", Version: "2.31.0", Requires: []string{"urllib3", "idna"}},
+			{Name: "urllib3", Version: "2.0.7"},
+			{Name: "idna", Version: "3.6"},
+		},
+		"tools/uv.lock": {
+			{Name: "httpx", Version: "0.27.0", Requires: []string{"idna"}},
+		},
+	}
+	g := BuildGraph(EcosystemPython, results)
+	if g == nil {
+		t.Fatal("expected a graph")
+	}
+	if len(g.Nodes) != 5 || len(g.Edges) != 4 {
+		t.Fatalf("nodes=%d edges=%d, want 5 and 4: %+v", len(g.Nodes), len(g.Edges), g.Edges)
+	}
+	if n, ok := g.Node("urllib3"); !ok || n.Version != "2.0.7" {
+		t.Errorf("Node(urllib3) = %+v, %v", n, ok)
+	}
+
+	tests := []struct {
+		name       string
+		transitive bool
+		want       []string
+	}{
+		{"urllib3", false, []string{"requests"}},
+		{"urllib3", true, []string{"app", "requests"}},
+		{"idna", true, []string{"app", "httpx", "requests"}},
+		{"app", true, nil},
+	}
+	for _, tt := range tests {
+		if got := g.Dependents(tt.name, tt.transitive); !slices.Equal(got, tt.want) {
+			t.Errorf("Dependents(%s, %v) = %v, want %v", tt.name, tt.transitive, got, tt.want)
+		}
+	}
+	if got := g.Requires("requests"); !slices.Equal(got, []string{"idna", "urllib3"}) {
+		t.Errorf("Requires(requests) = %v", got)
+	}
+
+	var dot strings.Builder
+	if err := g.WriteDOT(&dot, "org/app"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dot.String(), `digraph "org/app" {`) || !strings.Contains(dot.String(), `"requests" -> "urllib3";`) {
+		t.Errorf("unexpected DOT:\n%s", dot.String())
+	}
+}
+
+func TestBuildGraph_NoEdges(t *testing.T) {
+	g := BuildGraph(EcosystemPython, map[string][]Dependency{"Pipfile.lock": {{Name: "Django", Version: "4.2", Direct: true}}})
+	if g == nil || len(g.Edges) != 0 {
+		t.Fatalf("expected a graph of nodes only, got %+v", g)
+	}
+	if n, ok := g.Node("django"); !ok || !n.Direct || !slices.Equal(n.Files, []string{"Pipfile.lock"}) {
+		t.Errorf("Node(django) = %+v, %v", n, ok)
+	}
+	if g := BuildGraph(EcosystemPython, nil); g != nil {
+		t.Errorf("expected nil graph without dependencies, got %+v", g)
+	}
+}
+
+func TestLockFileEdges(t *testing.T) {
+	poetry := `[[package]]
+name = "requests"
+version = "2.31.0"
+
+[package.dependencies]
+urllib3 = ">=1.21.1,<3"
+idna = {version = ">=2.5,<4", markers = "python_version >= '3.7'"}
+`
+	deps, err := NewPoetryAnalyzer().parsePoetryLock(poetry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(deps[0].Requires, []string{"idna", "urllib3"}) {
+		t.Errorf("poetry Requires = %v", deps[0].Requires)
+	}
+
+	uv := `version = 1
+[[package]]
+name = "app"
+version = "0.1.0"
+source = { editable = "." }
+dependencies = [{ name = "requests" }]
+
+[package.dev-dependencies]
+dev = [{ name = "pytest" }, { name = "requests" }]
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+`
+	deps, err = NewUvLockAnalyzer().parseUvLock(uv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(deps[0].Requires, []string{"pytest", "requests"}) {
+		t.Errorf("uv Requires = %v", deps[0].Requires)
+	}
+	if deps[0].Direct || !deps[1].Direct {
+		t.Errorf("Direct = %v, %v; want false for the project, true for requests", deps[0].Direct, deps[1].Direct)
+	}
+}
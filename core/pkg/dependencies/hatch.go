@@ -0,0 +1,296 @@
+package dependencies
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+)
+
+// HatchAnalyzer implements the Analyzer interface for Python Hatch projects
+// It analyzes the requirements-format lock files Hatch environments are
+// locked to: hatch.lock, and the requirements.txt (default environment) and
+// requirements/requirements-<env>.txt exports written by hatch-pip-compile
+type HatchAnalyzer struct{}
+
+// NewHatchAnalyzer creates a new Hatch lock file dependency analyzer
+func NewHatchAnalyzer() *HatchAnalyzer {
+	return &HatchAnalyzer{}
+}
+
+// Name returns the name of this analyzer
+func (h *HatchAnalyzer) Name() string {
+	return string(AnalyzerHatch)
+}
+
+// Ecosystem returns EcosystemPython
+func (h *HatchAnalyzer) Ecosystem() Ecosystem {
+	return EcosystemPython
+}
+
+// ManifestAnalyzer returns the pyproject.toml analyzer used when no lock file is found
+func (h *HatchAnalyzer) ManifestAnalyzer() Analyzer {
+	return NewPyprojectAnalyzer()
+}
+
+// hatchLockEnvironment returns the Hatch environment locked by the file at
+// filePath, or "" when filePath is not a Hatch lock file
+func hatchLockEnvironment(filePath string) string {
+	base := path.Base(filePath)
+	switch {
+	case base == "hatch.lock" || base == "requirements.txt":
+		return "default"
+	case path.Base(path.Dir(filePath)) == "requirements" &&
+		strings.HasPrefix(base, "requirements-") && strings.HasSuffix(base, ".txt"):
+		return strings.TrimSuffix(strings.TrimPrefix(base, "requirements-"), ".txt")
+	default:
+		return ""
+	}
+}
+
+// CandidateFiles searches for Hatch lock files in the configured repository paths
+func (h *HatchAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	searchPaths := config.RepositoryPaths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{""}
+	}
+
+	var candidates []DependencyFile
+	for _, searchPath := range searchPaths {
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		for _, file := range files {
+			if file.Type != "file" || hatchLockEnvironment(file.Path) == "" {
+				continue
+			}
+			if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
+				continue
+			}
+			candidates = append(candidates, DependencyFile{
+				Path:     file.Path,
+				Type:     path.Base(file.Path),
+				Analyzer: h.Name(),
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// AnalyzeDependencies analyzes Hatch lock files and extracts dependency information
+func (h *HatchAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, ref string, files []DependencyFile, config Config) (map[string][]Dependency, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	result := make(map[string][]Dependency)
+	for _, file := range files {
+		deps, err := traceFile(ctx, h.Name(), file.Path, func(ctx context.Context) ([]Dependency, error) {
+			return h.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		})
+		if err != nil {
+			// Don't fail completely if one file fails, just skip it
+			slog.Debug("Failed to analyze Hatch lock file",
+				"file", file.Path,
+				"owner", owner,
+				"repo", repo,
+				"ref", ref,
+				"error", err)
+			config.reportFileError(file.Path, err)
+			continue
+		}
+		result[file.Path] = deps
+	}
+
+	return result, nil
+}
+
+// analyzeFile analyzes a single Hatch lock file
+func (h *HatchAnalyzer) analyzeFile(ctx context.Context, owner, repo, ref, filePath string, config Config) ([]Dependency, error) {
+	body, err := openFileStream(ctx, config, owner, repo, ref, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
+	}
+	defer body.Close()
+
+	depType := "dev"
+	if hatchLockEnvironment(filePath) == "default" {
+		depType = "runtime"
+	}
+	dependencies, err := decodeRequirementsLock(body, depType)
+	if body.err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, body.err)
+	}
+	if err != nil {
+		return nil, &ParseError{Path: filePath, Err: err}
+	}
+
+	if config.IncludeConstraints {
+		applyManifestConstraints(ctx, owner, repo, ref, filePath, pyprojectManifest, parsePyprojectConstraints, dependencies, config)
+	}
+
+	return dependencies, nil
+}
+
+// decodeHatchLock parses a Hatch lock file read from r, treating it as the
+// default (runtime) environment
+func (h *HatchAnalyzer) decodeHatchLock(r io.Reader) ([]Dependency, error) {
+	return decodeRequirementsLock(r, "runtime")
+}
+
+// decodeRequirementsLock parses a pip-compile style requirements file read
+// from r, giving every package depType. Pinned requirements ("name==1.0")
+// set Version; "# via" annotations set Requires on the requiring packages
+// and Direct on packages required by the project itself (a -r input file,
+// pyproject.toml or a hatch.envs.<env> environment). Options such as --hash
+// and --index-url are ignored.
+func decodeRequirementsLock(r io.Reader, depType string) ([]Dependency, error) {
+	var (
+		deps     []Dependency
+		index    = make(map[string]int) // normalized name -> position in deps
+		requires = make(map[string][]string)
+		current  = -1 // Package the "# via" annotations being read belong to
+		inVia    bool
+	)
+	addVia := func(via string) {
+		via = strings.TrimSpace(via)
+		if via == "" || current < 0 {
+			return
+		}
+		if strings.HasPrefix(via, "-r ") || strings.HasPrefix(via, "-c ") || strings.Contains(via, "pyproject.toml") || strings.HasPrefix(via, "hatch.envs.") {
+			deps[current].Direct = true
+			return
+		}
+		if name, _ := parsePEP508(via); name != "" {
+			key := normalizeManifestName(name)
+			requires[key] = append(requires[key], deps[current].Name)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var pending string // Requirement continued with a trailing backslash
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			comment = strings.TrimSpace(comment)
+			if via, ok := strings.CutPrefix(comment, "via"); ok && (via == "" || via[0] == ' ') {
+				inVia = true
+				addVia(via)
+			} else if inVia {
+				addVia(comment)
+			}
+			continue
+		}
+		inVia = false
+
+		if cont, ok := strings.CutSuffix(line, "\\"); ok {
+			pending += cont + " "
+			continue
+		}
+		line, pending = pending+line, ""
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		dep, ok := parseRequirementLine(line)
+		if !ok {
+			continue
+		}
+		dep.Type = depType
+		key := normalizeManifestName(dep.Name)
+		if i, seen := index[key]; seen {
+			current = i
+			continue
+		}
+		current = len(deps)
+		index[key] = current
+		deps = append(deps, dep)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read requirements: %w", err)
+	}
+
+	for i := range deps {
+		deps[i].Requires = requires[normalizeManifestName(deps[i].Name)]
+		slices.Sort(deps[i].Requires)
+		deps[i].Requires = slices.Compact(deps[i].Requires)
+	}
+	return deps, nil
+}
+
+// parseRequirementLine parses one requirement of a requirements file,
+// dropping per-requirement options such as --hash. Editable (-e) installs
+// are "path" or, for VCS URLs, "git" dependencies; other options are
+// skipped (ok is false).
+func parseRequirementLine(line string) (dep Dependency, ok bool) {
+	if editable, isEditable := strings.CutPrefix(line, "-e "); isEditable {
+		line = strings.TrimSpace(editable)
+		dep.Source = "path"
+		if strings.HasPrefix(line, "git+") {
+			dep.Source = "git"
+			_, fragment, _ := strings.Cut(line, "#egg=")
+			dep.Name = strings.TrimSpace(fragment)
+			dep.Version = gitRequirementRev(line)
+		} else if name, _, found := strings.Cut(line, " @ "); found {
+			dep.Name = strings.TrimSpace(name)
+		} else {
+			dep.Name = strings.Trim(path.Base(strings.TrimSuffix(line, "/")), ". ")
+		}
+		return dep, dep.Name != ""
+	}
+	if strings.HasPrefix(line, "-") {
+		return dep, false
+	}
+	if i := strings.Index(line, " --"); i >= 0 {
+		line = line[:i]
+	}
+
+	name, constraint := parsePEP508(line)
+	if name == "" {
+		return dep, false
+	}
+	dep.Name = name
+	dep.Source = pep508Source(line)
+	if version, pinned := strings.CutPrefix(constraint, "=="); pinned && !strings.ContainsAny(version, ",*") {
+		dep.Version = strings.TrimPrefix(version, "=")
+	} else {
+		dep.Constraint = constraint
+	}
+	if dep.Source == "git" {
+		dep.Version = gitRequirementRev(line)
+	}
+	return dep, true
+}
+
+// gitRequirementRev returns the revision pinned by a "git+<url>@<rev>"
+// requirement, or "" when the URL pins none
+func gitRequirementRev(line string) string {
+	i := strings.Index(line, "git+")
+	if i < 0 {
+		return ""
+	}
+	url, _, _ := strings.Cut(line[i:], "#")
+	url, _, _ = strings.Cut(url, ";")
+	url = strings.TrimSpace(url)
+	j := strings.LastIndex(url, "@")
+	if j < 0 || strings.Contains(url[j+1:], "/") {
+		return "" // No revision, or the user part of git+ssh://git@host/...
+	}
+	return url[j+1:]
+}
@@ -0,0 +1,123 @@
+package dependencies
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// testHatchLock is a hatch-pip-compile lock of the default environment
+const testHatchLock = `#
+# This file is autogenerated by hatch-pip-compile with Python 3.12
+#
+# - requests>=2.31
+# - mylib @ git+https://github.com/example/mylib.git@v1.2.0
+#
+
+--index-url https://pypi.org/simple
+
+certifi==2024.2.2 \
+    --hash=sha256:0569859f95fc761b18b45ef421b1290a0f65f147e92a1e5eb3e635f9a5e4e66f
+    # via requests
+charset-normalizer==3.3.2
+    # via requests
+idna==3.6
+    # via requests
+mylib @ git+https://github.com/example/mylib.git@v1.2.0
+    # via hatch.envs.default
+requests[socks]==2.31.0 ; python_version >= "3.8"
+    # via
+    #   hatch.envs.default
+    #   mylib
+urllib3==2.2.1
+    # via requests
+-e ./localpkg
+`
+
+func TestHatchAnalyzer_ParseLock(t *testing.T) {
+	deps, err := NewHatchAnalyzer().decodeHatchLock(strings.NewReader(testHatchLock))
+	if err != nil {
+		t.Fatalf("decodeHatchLock: %v", err)
+	}
+
+	want := map[string]Dependency{
+		"certifi":            {Version: "2024.2.2", Source: "pypi"},
+		"charset-normalizer": {Version: "3.3.2", Source: "pypi"},
+		"idna":               {Version: "3.6", Source: "pypi"},
+		"mylib":              {Version: "v1.2.0", Source: "git", Direct: true, Requires: []string{"requests"}},
+		"requests":           {Version: "2.31.0", Source: "pypi", Direct: true, Requires: []string{"certifi", "charset-normalizer", "idna", "urllib3"}},
+		"urllib3":            {Version: "2.2.1", Source: "pypi"},
+		"localpkg":           {Source: "path"},
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("got %d dependencies, want %d: %+v", len(deps), len(want), deps)
+	}
+	for _, d := range deps {
+		w, ok := want[d.Name]
+		if !ok {
+			t.Errorf("unexpected dependency %+v", d)
+			continue
+		}
+		if d.Version != w.Version || d.Type != "runtime" || d.Source != w.Source || d.Direct != w.Direct || !slices.Equal(d.Requires, w.Requires) {
+			t.Errorf("%s = %+v, want %+v", d.Name, d, w)
+		}
+	}
+}
+
+func TestHatchAnalyzer_CandidateFiles(t *testing.T) {
+	client := &mockRepoClient{
+		files: []repository.FileInfo{
+			{Path: "requirements.txt", Type: "file"},
+			{Path: "requirements/requirements-test.txt", Type: "file"},
+			{Path: "requirements/requirements-test.in", Type: "file"},
+			{Path: "svc/hatch.lock", Type: "file"},
+			{Path: "docs/requirements-docs.txt", Type: "file"},
+			{Path: "requirements", Type: "dir"},
+		},
+		byPath: map[string]string{
+			"requirements/requirements-test.txt": "pytest==8.0.0\n",
+		},
+	}
+	config := Config{RepositoryClient: client}
+
+	analyzer := NewHatchAnalyzer()
+	candidates, err := analyzer.CandidateFiles(context.Background(), "o", "r", "main", config)
+	if err != nil {
+		t.Fatalf("CandidateFiles: %v", err)
+	}
+	var paths []string
+	for _, c := range candidates {
+		if c.Analyzer != "hatch" {
+			t.Errorf("%s analyzer = %q", c.Path, c.Analyzer)
+		}
+		paths = append(paths, c.Path)
+	}
+	want := []string{"requirements.txt", "requirements/requirements-test.txt", "svc/hatch.lock"}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("candidates = %v, want %v", paths, want)
+	}
+
+	// Environments other than default lock development tooling
+	results, err := analyzer.AnalyzeDependencies(context.Background(), "o", "r", "main", candidates[1:2], config)
+	if err != nil {
+		t.Fatalf("AnalyzeDependencies: %v", err)
+	}
+	deps := results["requirements/requirements-test.txt"]
+	if len(deps) != 1 || deps[0].Name != "pytest" || deps[0].Version != "8.0.0" || deps[0].Type != "dev" {
+		t.Errorf("deps = %+v", deps)
+	}
+}
+
+func TestParseLockFile_PdmAndHatch(t *testing.T) {
+	deps, err := ParseLockFile("pdm", strings.NewReader(testPdmLock))
+	if err != nil || len(deps) != 5 {
+		t.Errorf("ParseLockFile(pdm) = %d deps, %v", len(deps), err)
+	}
+	deps, err = ParseLockFile("Hatch", strings.NewReader("django==4.2.7\n"))
+	if err != nil || len(deps) != 1 || deps[0].Version != "4.2.7" {
+		t.Errorf("ParseLockFile(hatch) = %+v, %v", deps, err)
+	}
+}
@@ -0,0 +1,98 @@
+package dependencies
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// lockBatchPackages is how many [[package]] tables decodeLockTables hands to
+// each decoder
+const lockBatchPackages = 256
+
+// lockPackageHeader starts each package of the TOML lock files
+const lockPackageHeader = "[[package]]"
+
+// lockBatch is a batch of packages decoded by decodeLockTables
+type lockBatch[T any] struct {
+	v   T
+	err error
+}
+
+// decodeLockTables decodes a TOML lock file whose bulk is a [[package]] array
+// (poetry.lock, uv.lock, pdm.lock). Decoding such files dominates analyzing
+// large repositories, so the file is split at its package headers as it
+// arrives and batches of packages are decoded concurrently, then combined in
+// file order by merge (which appends src's packages to dst's and keeps any
+// tables only src holds). A file with multi-line strings, where a header line
+// could be text, or with a batch that fails to decode is decoded whole, so
+// errors report positions in the file and are the same either way.
+func decodeLockTables[T any](r io.Reader, merge func(dst, src *T)) (T, error) {
+	var (
+		file      strings.Builder // Batches are substrings of it, not copies
+		start     int             // Offset of the current batch in file
+		results   []*lockBatch[T]
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, runtime.GOMAXPROCS(0))
+		packages  int
+		multiline bool
+	)
+	flush := func() {
+		doc := file.String()[start:]
+		start = file.Len()
+		if multiline {
+			return
+		}
+		res := new(lockBatch[T])
+		results = append(results, res)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			_, res.err = toml.Decode(doc, &res.v)
+		}()
+	}
+
+	br := bufio.NewReader(r)
+	var readErr error
+	for readErr == nil {
+		var line string
+		line, readErr = br.ReadString('\n')
+		if strings.TrimRight(line, "\r\n") == lockPackageHeader {
+			if packages == lockBatchPackages {
+				flush()
+				packages = 0
+			}
+			packages++
+		}
+		if strings.Contains(line, `"""`) || strings.Contains(line, "'''") {
+			multiline = true
+		}
+		file.WriteString(line)
+	}
+	flush()
+	wg.Wait()
+
+	var v T
+	if !errors.Is(readErr, io.EOF) {
+		return v, readErr
+	}
+	failed := multiline
+	for _, res := range results {
+		failed = failed || res.err != nil
+	}
+	if failed {
+		_, err := toml.Decode(file.String(), &v)
+		return v, err
+	}
+	v = results[0].v
+	for _, res := range results[1:] {
+		merge(&v, &res.v)
+	}
+	return v, nil
+}
@@ -0,0 +1,48 @@
+package dependencies
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestDecodeLockTables(t *testing.T) {
+	mergeUv := func(dst, src *uvLockFile) { dst.Packages = append(dst.Packages, src.Packages...) }
+	decodeWhole := func(doc string) (uvLockFile, error) {
+		var v uvLockFile
+		_, err := toml.Decode(doc, &v)
+		return v, err
+	}
+
+	// Several batches, merged in file order, match decoding the file whole
+	lock := syntheticUvLock(3*lockBatchPackages + 10)
+	got, err := decodeLockTables(strings.NewReader(lock), mergeUv)
+	if err != nil {
+		t.Fatalf("decodeLockTables failed: %v", err)
+	}
+	want, _ := decodeWhole(lock)
+	if len(got.Packages) != len(want.Packages) || got.Version != 1 || got.RequiresPython != ">=3.11" {
+		t.Fatalf("decoded %d packages (version %d, requires %q), want %d", len(got.Packages), got.Version, got.RequiresPython, len(want.Packages))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("batched decoding differs from decoding the file whole")
+	}
+
+	// A header line inside a multi-line string is not a package
+	multiline := lock + "[package.metadata]\nnote = \"\"\"\n[[package]]\n\"\"\"\n"
+	got, err = decodeLockTables(strings.NewReader(multiline), mergeUv)
+	if err != nil || len(got.Packages) != len(want.Packages) {
+		t.Errorf("multi-line string: %d packages, %v; want %d", len(got.Packages), err, len(want.Packages))
+	}
+
+	// Errors are reported as for the whole file
+	broken := lock + "[[package]]\nname = \n"
+	_, err = decodeLockTables(strings.NewReader(broken), mergeUv)
+	_, wantErr := decodeWhole(broken)
+	if err == nil || fmt.Sprint(err) != fmt.Sprint(wantErr) {
+		t.Errorf("error = %v, want %v", err, wantErr)
+	}
+}
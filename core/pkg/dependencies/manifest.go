@@ -0,0 +1,236 @@
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Manifest file names read alongside lock files when Config.IncludeConstraints is set.
+const (
+	pyprojectManifest = "pyproject.toml"
+	pipfileManifest   = "Pipfile"
+)
+
+// manifestParser extracts declared constraints keyed by normalized package name.
+type manifestParser func(content string) (map[string]string, error)
+
+// applyManifestConstraints reads the manifest sitting next to lockPath and
+// fills in Dependency.Constraint (and Direct) for declared packages. A missing or
+// unparsable manifest is logged and otherwise ignored: constraints are an
+// optional enrichment and must not fail lock file analysis.
+func applyManifestConstraints(ctx context.Context, owner, repo, ref, lockPath, manifestName string, parse manifestParser, deps []Dependency, config Config) {
+	manifestPath := path.Join(path.Dir(lockPath), manifestName)
+
+	content, err := config.RepositoryClient.GetFileContent(ctx, owner, repo, ref, manifestPath)
+	if err != nil {
+		slog.Debug("Manifest not available for constraints",
+			"file", manifestPath,
+			"error", err)
+		return
+	}
+
+	constraints, err := parse(content)
+	if err != nil {
+		slog.Debug("Failed to parse manifest for constraints",
+			"file", manifestPath,
+			"error", err)
+		return
+	}
+
+	for i := range deps {
+		if c, ok := constraints[normalizeManifestName(deps[i].Name)]; ok {
+			deps[i].Constraint = c
+			deps[i].Direct = true
+		}
+	}
+}
+
+// normalizeManifestName normalizes a Python package name so manifest and lock
+// file spellings compare equal
+func normalizeManifestName(name string) string {
+	return NormalizeName(EcosystemPython, name)
+}
+
+// pep508NameRe matches the distribution name (and optional extras) at the start
+// of a PEP 508 requirement string.
+var pep508NameRe = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?`)
+
+// parsePEP508 splits a requirement such as "requests[socks] (>=2.8, <3); python_version>'3'"
+// into its name and version constraint (">=2.8,<3"). URL requirements yield an
+// empty constraint.
+func parsePEP508(spec string) (name, constraint string) {
+	m := pep508NameRe.FindStringSubmatch(spec)
+	if m == nil {
+		return "", ""
+	}
+	rest := spec[len(m[0]):]
+	if i := strings.Index(rest, ";"); i >= 0 {
+		rest = rest[:i]
+	}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "@") {
+		return m[1], ""
+	}
+	rest = strings.TrimSuffix(strings.TrimPrefix(rest, "("), ")")
+	return m[1], strings.ReplaceAll(rest, " ", "")
+}
+
+// addPEP508Constraints records constraints from a list of PEP 508 strings,
+// keeping the first declaration of each package.
+func addPEP508Constraints(out map[string]string, specs []any) {
+	for _, s := range specs {
+		spec, ok := s.(string)
+		if !ok {
+			continue // e.g. {include-group = "..."} entries
+		}
+		if name, c := parsePEP508(spec); name != "" {
+			if _, seen := out[normalizeManifestName(name)]; !seen {
+				out[normalizeManifestName(name)] = c
+			}
+		}
+	}
+}
+
+// addTableConstraints records constraints from a Poetry/Pipfile style table
+// where each value is either a version string or a table with a "version" key.
+func addTableConstraints(out map[string]string, table map[string]any) {
+	for name, v := range table {
+		key := normalizeManifestName(name)
+		if key == "python" {
+			continue
+		}
+		if _, seen := out[key]; seen {
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			out[key] = val
+		case map[string]any:
+			if version, ok := val["version"].(string); ok {
+				out[key] = version
+			}
+		}
+	}
+}
+
+// tomlTable returns the nested table at the given key path, or nil.
+func tomlTable(doc map[string]any, keys ...string) map[string]any {
+	cur := doc
+	for _, k := range keys {
+		next, ok := cur[k].(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = next
+	}
+	return cur
+}
+
+// manifestSection is one dependency list or table of a manifest
+type manifestSection struct {
+	kind  string         // Dependency.Type of its entries: "runtime", "optional" or "dev"
+	specs []any          // PEP 508 requirement strings, or
+	table map[string]any // a Poetry/Pipfile style table of name -> version or table
+}
+
+// pyprojectSections returns the dependency sections of a pyproject.toml:
+// PEP 621 ([project] dependencies and optional-dependencies), PEP 735
+// dependency groups, uv and PDM dev-dependencies, Hatch environment
+// dependencies and Poetry dependency tables
+func pyprojectSections(doc map[string]any) []manifestSection {
+	var sections []manifestSection
+	if project := tomlTable(doc, "project"); project != nil {
+		if deps, ok := project["dependencies"].([]any); ok {
+			sections = append(sections, manifestSection{kind: "runtime", specs: deps})
+		}
+		for _, extra := range tomlTable(project, "optional-dependencies") {
+			if deps, ok := extra.([]any); ok {
+				sections = append(sections, manifestSection{kind: "optional", specs: deps})
+			}
+		}
+	}
+	for _, group := range tomlTable(doc, "dependency-groups") {
+		if deps, ok := group.([]any); ok {
+			sections = append(sections, manifestSection{kind: "dev", specs: deps})
+		}
+	}
+	if uv := tomlTable(doc, "tool", "uv"); uv != nil {
+		if deps, ok := uv["dev-dependencies"].([]any); ok {
+			sections = append(sections, manifestSection{kind: "dev", specs: deps})
+		}
+	}
+	for _, group := range tomlTable(doc, "tool", "pdm", "dev-dependencies") {
+		if deps, ok := group.([]any); ok {
+			sections = append(sections, manifestSection{kind: "dev", specs: deps})
+		}
+	}
+	for _, env := range tomlTable(doc, "tool", "hatch", "envs") {
+		if e, ok := env.(map[string]any); ok {
+			for _, key := range []string{"dependencies", "extra-dependencies"} {
+				if deps, ok := e[key].([]any); ok {
+					sections = append(sections, manifestSection{kind: "dev", specs: deps})
+				}
+			}
+		}
+	}
+
+	if poetry := tomlTable(doc, "tool", "poetry"); poetry != nil {
+		sections = append(sections,
+			manifestSection{kind: "runtime", table: tomlTable(poetry, "dependencies")},
+			manifestSection{kind: "dev", table: tomlTable(poetry, "dev-dependencies")})
+		for _, group := range tomlTable(poetry, "group") {
+			if g, ok := group.(map[string]any); ok {
+				sections = append(sections, manifestSection{kind: "dev", table: tomlTable(g, "dependencies")})
+			}
+		}
+	}
+	return sections
+}
+
+// pipfileSections returns a Pipfile's [packages] and [dev-packages] tables
+func pipfileSections(doc map[string]any) []manifestSection {
+	return []manifestSection{
+		{kind: "runtime", table: tomlTable(doc, "packages")},
+		{kind: "dev", table: tomlTable(doc, "dev-packages")},
+	}
+}
+
+// sectionConstraints records the constraints declared in sections, keeping
+// the first declaration of each package
+func sectionConstraints(sections []manifestSection) map[string]string {
+	out := make(map[string]string)
+	for _, s := range sections {
+		if s.table != nil {
+			addTableConstraints(out, s.table)
+		} else {
+			addPEP508Constraints(out, s.specs)
+		}
+	}
+	return out
+}
+
+// parsePyprojectConstraints extracts declared constraints from a pyproject.toml
+// (see pyprojectSections).
+func parsePyprojectConstraints(content string) (map[string]string, error) {
+	var doc map[string]any
+	if _, err := toml.Decode(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse pyproject.toml: %w", err)
+	}
+	return sectionConstraints(pyprojectSections(doc)), nil
+}
+
+// parsePipfileConstraints extracts declared constraints from a Pipfile's
+// [packages] and [dev-packages] tables.
+func parsePipfileConstraints(content string) (map[string]string, error) {
+	var doc map[string]any
+	if _, err := toml.Decode(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Pipfile: %w", err)
+	}
+	return sectionConstraints(pipfileSections(doc)), nil
+}
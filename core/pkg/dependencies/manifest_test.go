@@ -0,0 +1,205 @@
+package dependencies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+func TestParsePEP508(t *testing.T) {
+	tests := []struct {
+		spec           string
+		wantName       string
+		wantConstraint string
+	}{
+		{"requests>=2.31", "requests", ">=2.31"},
+		{"requests[socks] (>=2.8, <3)", "requests", ">=2.8,<3"},
+		{"Django>=4.2,<5; python_version >= '3.10'", "Django", ">=4.2,<5"},
+		{"black", "black", ""},
+		{"pkg @ https://example.com/pkg.whl", "pkg", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			name, constraint := parsePEP508(tt.spec)
+			if name != tt.wantName || constraint != tt.wantConstraint {
+				t.Errorf("parsePEP508(%q) = (%q, %q), want (%q, %q)",
+					tt.spec, name, constraint, tt.wantName, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func TestParsePyprojectConstraints(t *testing.T) {
+	content := `
+[project]
+dependencies = ["requests>=2.31", "Typing_Extensions~=4.0"]
+
+[project.optional-dependencies]
+test = ["pytest>=7"]
+
+[dependency-groups]
+lint = ["ruff==0.4.1", {include-group = "test"}]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+django = "^4.2"
+celery = {version = "^5.3", extras = ["redis"]}
+localpkg = {path = "../localpkg"}
+
+[tool.poetry.group.dev.dependencies]
+mypy = ">=1.8"
+`
+	got, err := parsePyprojectConstraints(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"requests":          ">=2.31",
+		"typing-extensions": "~=4.0",
+		"pytest":            ">=7",
+		"ruff":              "==0.4.1",
+		"django":            "^4.2",
+		"celery":            "^5.3",
+		"mypy":              ">=1.8",
+	}
+	for name, constraint := range want {
+		if got[name] != constraint {
+			t.Errorf("constraint for %s = %q, want %q", name, got[name], constraint)
+		}
+	}
+	if _, ok := got["python"]; ok {
+		t.Error("python interpreter requirement should not be reported as a constraint")
+	}
+	if _, ok := got["localpkg"]; ok {
+		t.Error("path dependency without version should have no constraint")
+	}
+
+	if _, err := parsePyprojectConstraints("not = [valid"); err == nil {
+		t.Error("expected error for invalid TOML")
+	}
+}
+
+func TestParsePipfileConstraints(t *testing.T) {
+	content := `
+[packages]
+requests = "*"
+django = {version = ">=4.2", extras = ["argon2"]}
+
+[dev-packages]
+pytest = "==7.4.0"
+`
+	got, err := parsePipfileConstraints(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"requests": "*", "django": ">=4.2", "pytest": "==7.4.0"}
+	if len(got) != len(want) {
+		t.Errorf("got %d constraints, want %d: %v", len(got), len(want), got)
+	}
+	for name, constraint := range want {
+		if got[name] != constraint {
+			t.Errorf("constraint for %s = %q, want %q", name, got[name], constraint)
+		}
+	}
+}
+
+func TestAnalyzers_IncludeConstraints(t *testing.T) {
+	pyproject := `
+[project]
+dependencies = ["Requests>=2.28"]
+`
+	tests := []struct {
+		name     string
+		analyzer Analyzer
+		lockPath string
+		lock     string
+		manifest map[string]string
+	}{
+		{
+			name:     "poetry",
+			analyzer: NewPoetryAnalyzer(),
+			lockPath: "app/poetry.lock",
+			lock: `
+[[package]]
+name = "requests"
+version = "2.31.0"
+`,
+			manifest: map[string]string{"app/pyproject.toml": pyproject},
+		},
+		{
+			name:     "uv",
+			analyzer: NewUvLockAnalyzer(),
+			lockPath: "uv.lock",
+			lock: `
+version = 1
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+`,
+			manifest: map[string]string{"pyproject.toml": pyproject},
+		},
+		{
+			name:     "pipfile",
+			analyzer: NewPipfileAnalyzer(),
+			lockPath: "Pipfile.lock",
+			lock:     `{"default": {"requests": {"version": "==2.31.0"}}, "develop": {}}`,
+			manifest: map[string]string{"Pipfile": "[packages]\nrequests = \">=2.28\"\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			byPath := map[string]string{tt.lockPath: tt.lock}
+			for p, c := range tt.manifest {
+				byPath[p] = c
+			}
+			files := []DependencyFile{{Path: tt.lockPath, Analyzer: tt.analyzer.Name()}}
+
+			for _, include := range []bool{false, true} {
+				config := Config{
+					RepositoryClient:   &mockRepoClient{byPath: byPath},
+					IncludeConstraints: include,
+				}
+				result, err := tt.analyzer.AnalyzeDependencies(context.Background(), "owner", "repo", "main", files, config)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				deps := result[tt.lockPath]
+				if len(deps) != 1 {
+					t.Fatalf("expected 1 dependency, got %d", len(deps))
+				}
+				if deps[0].Version != "2.31.0" {
+					t.Errorf("version = %q, want 2.31.0", deps[0].Version)
+				}
+				want := ""
+				if include {
+					want = ">=2.28"
+				}
+				if deps[0].Constraint != want {
+					t.Errorf("IncludeConstraints=%v: constraint = %q, want %q", include, deps[0].Constraint, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzers_IncludeConstraintsMissingManifest(t *testing.T) {
+	client := &mockRepoClient{
+		files:  []repository.FileInfo{{Path: "Pipfile.lock", Type: "file"}},
+		byPath: map[string]string{"Pipfile.lock": `{"default": {"requests": {"version": "==2.31.0"}}}`},
+	}
+	config := Config{RepositoryClient: client, IncludeConstraints: true}
+	files := []DependencyFile{{Path: "Pipfile.lock", Analyzer: string(AnalyzerPipfile)}}
+
+	result, err := NewPipfileAnalyzer().AnalyzeDependencies(context.Background(), "owner", "repo", "main", files, config)
+	if err != nil {
+		t.Fatalf("missing manifest should not fail analysis: %v", err)
+	}
+	if deps := result["Pipfile.lock"]; len(deps) != 1 || deps[0].Constraint != "" {
+		t.Errorf("expected one dependency without constraint, got %+v", deps)
+	}
+}
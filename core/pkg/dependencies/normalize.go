@@ -0,0 +1,63 @@
+package dependencies
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Ecosystem identifies a package naming/versioning universe shared by one or
+// more analyzers (e.g. poetry, pipfile and uvlock all resolve PyPI packages)
+type Ecosystem string
+
+const (
+	// EcosystemPython covers PyPI packages (PEP 503 name normalization)
+	EcosystemPython Ecosystem = "python"
+	// EcosystemPreCommit covers pre-commit hook repositories, named by URL
+	EcosystemPreCommit Ecosystem = "pre-commit"
+	// EcosystemUnknown is used for analyzers without specific naming rules
+	EcosystemUnknown Ecosystem = ""
+)
+
+// EcosystemForAnalyzer returns the ecosystem of the named analyzer
+// (case-insensitive), or EcosystemUnknown if it is not registered or does not
+// implement EcosystemAnalyzer
+func EcosystemForAnalyzer(analyzer string) Ecosystem {
+	r, ok := lookup(analyzer)
+	if !ok {
+		return EcosystemUnknown
+	}
+	return r.ecosystemOf()
+}
+
+// PyPIRegistry is the Dependency.Registry of packages resolved from the
+// public Python Package Index
+const PyPIRegistry = "https://pypi.org/simple"
+
+// pep503Separators matches runs of characters PEP 503 treats as equivalent
+var pep503Separators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizeName returns the canonical form of a package name for comparison
+// within an ecosystem. Python names follow PEP 503: lowercased with runs of
+// "-", "_" and "." collapsed to "-", so "PyYAML" matches "pyyaml" and
+// "Typing_Extensions" matches "typing-extensions". pre-commit hook
+// repositories compare by host and path, so "https://github.com/psf/black.git"
+// matches "github.com/psf/black". Unknown ecosystems only trim surrounding
+// whitespace.
+// The result is meant for matching; keep the original spelling for display.
+func NormalizeName(eco Ecosystem, name string) string {
+	name = strings.TrimSpace(name)
+	switch eco {
+	case EcosystemPython:
+		return pep503Separators.ReplaceAllString(strings.ToLower(name), "-")
+	case EcosystemPreCommit:
+		name = strings.ToLower(name)
+		if _, rest, ok := strings.Cut(name, "://"); ok {
+			name = rest
+		} else if user, rest, ok := strings.Cut(name, "@"); ok && !strings.Contains(user, "/") {
+			name = strings.Replace(rest, ":", "/", 1) // git@host:owner/repo
+		}
+		return strings.TrimSuffix(strings.TrimSuffix(name, "/"), ".git")
+	default:
+		return name
+	}
+}
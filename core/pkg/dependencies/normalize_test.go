@@ -0,0 +1,45 @@
+package dependencies
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		eco  Ecosystem
+		name string
+		want string
+	}{
+		{EcosystemPython, "PyYAML", "pyyaml"},
+		{EcosystemPython, "Typing_Extensions", "typing-extensions"},
+		{EcosystemPython, "zope.interface", "zope-interface"},
+		{EcosystemPython, "a-_.b", "a-b"},
+		{EcosystemPython, "  requests ", "requests"},
+		{EcosystemPreCommit, "https://github.com/psf/black", "github.com/psf/black"},
+		{EcosystemPreCommit, "https://GitHub.com/PyCQA/isort.git", "github.com/pycqa/isort"},
+		{EcosystemPreCommit, "git@github.com:pre-commit/mirrors-mypy.git", "github.com/pre-commit/mirrors-mypy"},
+		{EcosystemPreCommit, "github.com/psf/black/", "github.com/psf/black"},
+		{EcosystemUnknown, " Some_Name ", "Some_Name"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeName(tt.eco, tt.name); got != tt.want {
+			t.Errorf("NormalizeName(%q, %q) = %q, want %q", tt.eco, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEcosystemForAnalyzer(t *testing.T) {
+	for _, a := range SupportedAnalyzers() {
+		want := EcosystemPython
+		if a == string(AnalyzerPreCommit) {
+			want = EcosystemPreCommit
+		}
+		if got := EcosystemForAnalyzer(a); got != want {
+			t.Errorf("EcosystemForAnalyzer(%q) = %q, want %q", a, got, want)
+		}
+	}
+	if got := EcosystemForAnalyzer(" Poetry "); got != EcosystemPython {
+		t.Errorf("expected case-insensitive match, got %q", got)
+	}
+	if got := EcosystemForAnalyzer("cargo"); got != EcosystemUnknown {
+		t.Errorf("expected unknown ecosystem, got %q", got)
+	}
+}
@@ -0,0 +1,201 @@
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+)
+
+// PdmAnalyzer implements the Analyzer interface for Python PDM projects
+// It analyzes pdm.lock files to extract dependency information
+type PdmAnalyzer struct{}
+
+// NewPdmAnalyzer creates a new pdm.lock dependency analyzer
+func NewPdmAnalyzer() *PdmAnalyzer {
+	return &PdmAnalyzer{}
+}
+
+// Name returns the name of this analyzer
+func (p *PdmAnalyzer) Name() string {
+	return string(AnalyzerPdm)
+}
+
+// Ecosystem returns EcosystemPython
+func (p *PdmAnalyzer) Ecosystem() Ecosystem {
+	return EcosystemPython
+}
+
+// ManifestAnalyzer returns the pyproject.toml analyzer used when no lock file is found
+func (p *PdmAnalyzer) ManifestAnalyzer() Analyzer {
+	return NewPyprojectAnalyzer()
+}
+
+// CandidateFiles searches for pdm.lock files in the configured repository paths
+func (p *PdmAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	searchPaths := config.RepositoryPaths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{""}
+	}
+
+	var candidates []DependencyFile
+	for _, searchPath := range searchPaths {
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		for _, file := range files {
+			if file.Type != "file" || path.Base(file.Path) != "pdm.lock" {
+				continue
+			}
+			if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
+				continue
+			}
+			candidates = append(candidates, DependencyFile{
+				Path:     file.Path,
+				Type:     "pdm.lock",
+				Analyzer: p.Name(),
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// AnalyzeDependencies analyzes pdm.lock files and extracts dependency information
+func (p *PdmAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, ref string, files []DependencyFile, config Config) (map[string][]Dependency, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	result := make(map[string][]Dependency)
+	for _, file := range files {
+		deps, err := traceFile(ctx, p.Name(), file.Path, func(ctx context.Context) ([]Dependency, error) {
+			return p.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		})
+		if err != nil {
+			// Don't fail completely if one file fails, just skip it
+			slog.Debug("Failed to analyze pdm.lock file",
+				"file", file.Path,
+				"owner", owner,
+				"repo", repo,
+				"ref", ref,
+				"error", err)
+			config.reportFileError(file.Path, err)
+			continue
+		}
+		result[file.Path] = deps
+	}
+
+	return result, nil
+}
+
+// analyzeFile analyzes a single pdm.lock file
+func (p *PdmAnalyzer) analyzeFile(ctx context.Context, owner, repo, ref, filePath string, config Config) ([]Dependency, error) {
+	body, err := openFileStream(ctx, config, owner, repo, ref, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
+	}
+	defer body.Close()
+
+	dependencies, err := p.decodePdmLock(body)
+	if body.err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, body.err)
+	}
+	if err != nil {
+		return nil, &ParseError{Path: filePath, Err: err}
+	}
+
+	if config.IncludeConstraints {
+		applyManifestConstraints(ctx, owner, repo, ref, filePath, pyprojectManifest, parsePyprojectConstraints, dependencies, config)
+	}
+
+	return dependencies, nil
+}
+
+// pdmLockFile represents the structure of a pdm.lock file
+type pdmLockFile struct {
+	Packages []pdmPackage `toml:"package"`
+}
+
+// pdmPackage represents a single package entry in pdm.lock
+type pdmPackage struct {
+	Name         string       `toml:"name"`
+	Version      string       `toml:"version"`
+	Groups       []string     `toml:"groups"` // Absent before lock format 4.4
+	Dependencies []string     `toml:"dependencies"`
+	Git          string       `toml:"git"`
+	Path         string       `toml:"path"`
+	URL          string       `toml:"url"`
+	Files        []lockedFile `toml:"files"`
+}
+
+// decodePdmLock parses a pdm.lock file read from r. Packages locked for the
+// "default" group are runtime dependencies and those locked only for other
+// groups (dev, test, ...) dev dependencies. The extra entries PDM writes for
+// "name[extra]" requirements are merged into the package's own entry.
+func (p *PdmAnalyzer) decodePdmLock(r io.Reader) ([]Dependency, error) {
+	lockFile, err := decodeLockTables(r, func(dst, src *pdmLockFile) {
+		dst.Packages = append(dst.Packages, src.Packages...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pdm.lock: %w", err)
+	}
+
+	dependencies := make([]Dependency, 0, len(lockFile.Packages))
+	index := make(map[string]int) // normalized name -> position in dependencies
+	for _, pkg := range lockFile.Packages {
+		key := normalizeManifestName(pkg.Name)
+		depType := "runtime"
+		if len(pkg.Groups) > 0 && !slices.Contains(pkg.Groups, "default") {
+			depType = "dev"
+		}
+
+		source := "pypi"
+		switch {
+		case pkg.Git != "":
+			source = "git"
+		case pkg.Path != "":
+			source = "path"
+		case pkg.URL != "":
+			source = "url"
+		}
+
+		dep := Dependency{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Type:    depType,
+			Source:  source,
+			Hashes:  lockedFileHashes(pkg.Files),
+		}
+		var requires []string
+		for _, spec := range pkg.Dependencies {
+			if name, _ := parsePEP508(spec); name != "" && normalizeManifestName(name) != key {
+				requires = append(requires, name)
+			}
+		}
+
+		i, seen := index[key]
+		if !seen {
+			i = len(dependencies)
+			index[key] = i
+			dependencies = append(dependencies, dep)
+		} else if depType == "runtime" {
+			dependencies[i].Type = "runtime"
+		}
+		dependencies[i].Requires = append(dependencies[i].Requires, requires...)
+	}
+	for i := range dependencies {
+		slices.Sort(dependencies[i].Requires)
+		dependencies[i].Requires = slices.Compact(dependencies[i].Requires)
+	}
+
+	return dependencies, nil
+}
@@ -0,0 +1,152 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+const testPdmLock = `[metadata]
+groups = ["default", "dev"]
+strategy = ["cross_platform", "inherit_metadata"]
+lock_version = "4.4.1"
+content_hash = "sha256:abc123"
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+requires_python = ">=3.7"
+summary = "Python HTTP for Humans."
+groups = ["default"]
+dependencies = [
+    "certifi>=2017.4.17",
+    "charset-normalizer<4,>=2",
+    "urllib3<3,>=1.21.1",
+]
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+extras = ["socks"]
+groups = ["default"]
+dependencies = [
+    "PySocks!=1.5.7,>=1.5.6",
+    "requests==2.31.0",
+]
+
+[[package]]
+name = "certifi"
+version = "2024.2.2"
+groups = ["default", "dev"]
+
+[[package]]
+name = "pytest"
+version = "8.0.0"
+groups = ["dev"]
+dependencies = ["pluggy<2.0,>=1.3.0; python_version >= \"3.8\""]
+
+[[package]]
+name = "mylib"
+version = "0.1.0"
+groups = ["default"]
+git = "https://github.com/example/mylib.git"
+revision = "0123456789abcdef"
+
+[[package]]
+name = "localpkg"
+version = "1.0.0"
+groups = ["default"]
+path = "./localpkg"
+editable = true
+`
+
+func TestPdmAnalyzer_ParseLock(t *testing.T) {
+	deps, err := NewPdmAnalyzer().decodePdmLock(strings.NewReader(testPdmLock))
+	if err != nil {
+		t.Fatalf("decodePdmLock: %v", err)
+	}
+
+	want := map[string]Dependency{
+		"requests": {Version: "2.31.0", Type: "runtime", Source: "pypi", Requires: []string{"PySocks", "certifi", "charset-normalizer", "urllib3"}},
+		"certifi":  {Version: "2024.2.2", Type: "runtime", Source: "pypi"},
+		"pytest":   {Version: "8.0.0", Type: "dev", Source: "pypi", Requires: []string{"pluggy"}},
+		"mylib":    {Version: "0.1.0", Type: "runtime", Source: "git"},
+		"localpkg": {Version: "1.0.0", Type: "runtime", Source: "path"},
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("got %d dependencies, want %d: %+v", len(deps), len(want), deps)
+	}
+	for _, d := range deps {
+		w, ok := want[d.Name]
+		if !ok {
+			t.Errorf("unexpected dependency %+v", d)
+			continue
+		}
+		if d.Version != w.Version || d.Type != w.Type || d.Source != w.Source || !slices.Equal(d.Requires, w.Requires) {
+			t.Errorf("%s = %+v, want %+v", d.Name, d, w)
+		}
+	}
+}
+
+func TestPdmAnalyzer_AnalyzeDependencies(t *testing.T) {
+	client := &mockRepoClient{
+		files: []repository.FileInfo{
+			{Path: "pdm.lock", Type: "file"},
+			{Path: "svc/pdm.lock", Type: "file"},
+			{Path: "svc/pyproject.toml", Type: "file"},
+			{Path: "poetry.lock", Type: "file"},
+		},
+		byPath: map[string]string{
+			"pdm.lock":     testPdmLock,
+			"svc/pdm.lock": "not = [valid",
+			"pyproject.toml": `
+[project]
+dependencies = ["requests>=2.31"]
+
+[tool.pdm.dev-dependencies]
+test = ["pytest>=8"]
+`,
+		},
+	}
+	var fileErrs []error
+	config := Config{
+		RepositoryClient:   client,
+		IncludeConstraints: true,
+		OnFileError:        func(_ string, err error) { fileErrs = append(fileErrs, err) },
+	}
+
+	analyzer := NewPdmAnalyzer()
+	candidates, err := analyzer.CandidateFiles(context.Background(), "o", "r", "main", config)
+	if err != nil {
+		t.Fatalf("CandidateFiles: %v", err)
+	}
+	if len(candidates) != 2 || candidates[0].Path != "pdm.lock" || candidates[0].Type != "pdm.lock" || candidates[0].Analyzer != "pdm" {
+		t.Fatalf("candidates = %+v", candidates)
+	}
+
+	results, err := analyzer.AnalyzeDependencies(context.Background(), "o", "r", "main", candidates, config)
+	if err != nil {
+		t.Fatalf("AnalyzeDependencies: %v", err)
+	}
+	if _, ok := results["svc/pdm.lock"]; ok {
+		t.Error("unparsable lock file included in results")
+	}
+	var parseErr *ParseError
+	if len(fileErrs) != 1 || !errors.As(fileErrs[0], &parseErr) {
+		t.Errorf("file errors = %v, want one parse error", fileErrs)
+	}
+
+	constraints := make(map[string]string)
+	for _, d := range results["pdm.lock"] {
+		if d.Direct {
+			constraints[d.Name] = d.Constraint
+		}
+	}
+	if len(constraints) != 2 || constraints["requests"] != ">=2.31" || constraints["pytest"] != ">=8" {
+		t.Errorf("direct constraints = %v", constraints)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
 	"strings"
 )
 
@@ -22,6 +24,16 @@ func (p *PipfileAnalyzer) Name() string {
 	return string(AnalyzerPipfile)
 }
 
+// Ecosystem returns EcosystemPython
+func (p *PipfileAnalyzer) Ecosystem() Ecosystem {
+	return EcosystemPython
+}
+
+// ManifestAnalyzer returns the Pipfile analyzer used when no lock file is found
+func (p *PipfileAnalyzer) ManifestAnalyzer() Analyzer {
+	return NewPipfileManifestAnalyzer()
+}
+
 // CandidateFiles searches for Pipfile.lock files in the configured repository paths
 func (p *PipfileAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
 	if config.RepositoryClient == nil {
@@ -39,7 +51,7 @@ func (p *PipfileAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref s
 	// Search each configured path
 	for _, searchPath := range searchPaths {
 		// List all files recursively in this path
-		files, err := config.RepositoryClient.ListFilesRecursive(ctx, owner, repo, ref)
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list files: %w", err)
 		}
@@ -53,8 +65,8 @@ func (p *PipfileAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref s
 
 			// Check if this is a Pipfile.lock file
 			if strings.HasSuffix(file.Path, "Pipfile.lock") {
-				// If searchPath is specified, ensure file is within that path
-				if searchPath != "" && !strings.HasPrefix(file.Path, searchPath) {
+				// If searchPath is specified, ensure file is within that path; skip excluded paths
+				if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
 					continue
 				}
 
@@ -79,7 +91,9 @@ func (p *PipfileAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo,
 	result := make(map[string][]Dependency)
 
 	for _, file := range files {
-		deps, err := p.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		deps, err := traceFile(ctx, p.Name(), file.Path, func(ctx context.Context) ([]Dependency, error) {
+			return p.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		})
 		if err != nil {
 			// Don't fail completely if one file fails, just skip it
 			slog.Debug("Failed to analyze Pipfile.lock file",
@@ -88,6 +102,7 @@ func (p *PipfileAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo,
 				"repo", repo,
 				"ref", ref,
 				"error", err)
+			config.reportFileError(file.Path, err)
 			continue
 		}
 		result[file.Path] = deps
@@ -98,19 +113,27 @@ func (p *PipfileAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo,
 
 // analyzeFile analyzes a single Pipfile.lock file
 func (p *PipfileAnalyzer) analyzeFile(ctx context.Context, owner, repo, ref, filePath string, config Config) ([]Dependency, error) {
-	// Get the file content from the repository
-	content, err := config.RepositoryClient.GetFileContent(ctx, owner, repo, ref, filePath)
+	// Stream the file content from the repository
+	body, err := openFileStream(ctx, config, owner, repo, ref, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
 	}
+	defer body.Close()
 
-	// Parse the Pipfile.lock file
-	dependencies, err := p.parsePipfileLock(content)
+	// Parse the Pipfile.lock file as it downloads
+	dependencies, err := p.decodePipfileLock(body)
+	if body.err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, body.err)
+	}
 	if err != nil {
 		slog.Debug("Failed to parse Pipfile.lock content",
 			"file", filePath,
 			"error", err)
-		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+		return nil, &ParseError{Path: filePath, Err: err}
+	}
+
+	if config.IncludeConstraints {
+		applyManifestConstraints(ctx, owner, repo, ref, filePath, pipfileManifest, parsePipfileConstraints, dependencies, config)
 	}
 
 	return dependencies, nil
@@ -150,13 +173,61 @@ type pipfilePackageInfo struct {
 	Index   string   `json:"index"`
 	Markers string   `json:"markers"`
 	Extras  []string `json:"extras"`
+
+	// VCS, local and URL requirements carry one of these instead of an index
+	Git  string `json:"git"`
+	Path string `json:"path"`
+	File string `json:"file"`
+}
+
+// source maps a Pipfile.lock entry to a Dependency.Source
+func (p pipfilePackageInfo) source() string {
+	switch {
+	case p.Git != "":
+		return "git"
+	case p.Path != "":
+		return "path"
+	case p.File != "":
+		return "url"
+	default:
+		return "pypi"
+	}
+}
+
+// registry returns the URL of the source an index package was resolved from:
+// the one named by its index, or the first (default) source
+func (p pipfilePackageInfo) registry(sources []pipfileSource) string {
+	if p.source() != "pypi" {
+		return ""
+	}
+	for _, s := range sources {
+		if p.Index == "" || s.Name == p.Index {
+			return s.URL
+		}
+	}
+	return ""
+}
+
+// sortedHashes returns a sorted copy of hashes without duplicates, or nil
+func sortedHashes(hashes []string) []string {
+	if len(hashes) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(hashes)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
 }
 
 // parsePipfileLock parses the content of a Pipfile.lock file
 func (p *PipfileAnalyzer) parsePipfileLock(content string) ([]Dependency, error) {
+	return p.decodePipfileLock(strings.NewReader(content))
+}
+
+// decodePipfileLock parses a Pipfile.lock file read from r
+func (p *PipfileAnalyzer) decodePipfileLock(r io.Reader) ([]Dependency, error) {
 	var lockFile pipfileLockFile
 
-	if err := json.Unmarshal([]byte(content), &lockFile); err != nil {
+	if err := json.NewDecoder(r).Decode(&lockFile); err != nil {
 		return nil, fmt.Errorf("failed to parse Pipfile.lock: %w", err)
 	}
 
@@ -165,10 +236,12 @@ func (p *PipfileAnalyzer) parsePipfileLock(content string) ([]Dependency, error)
 	// Process default (runtime) dependencies
 	for name, pkg := range lockFile.Default {
 		dep := Dependency{
-			Name:    name,
-			Version: strings.TrimPrefix(pkg.Version, "=="),
-			Type:    "runtime",
-			Source:  "pypi",
+			Name:     name,
+			Version:  strings.TrimPrefix(pkg.Version, "=="),
+			Type:     "runtime",
+			Source:   pkg.source(),
+			Registry: pkg.registry(lockFile.Meta.Sources),
+			Hashes:   sortedHashes(pkg.Hashes),
 		}
 		dependencies = append(dependencies, dep)
 	}
@@ -176,10 +249,12 @@ func (p *PipfileAnalyzer) parsePipfileLock(content string) ([]Dependency, error)
 	// Process development dependencies
 	for name, pkg := range lockFile.Develop {
 		dep := Dependency{
-			Name:    name,
-			Version: strings.TrimPrefix(pkg.Version, "=="),
-			Type:    "dev",
-			Source:  "pypi",
+			Name:     name,
+			Version:  strings.TrimPrefix(pkg.Version, "=="),
+			Type:     "dev",
+			Source:   pkg.source(),
+			Registry: pkg.registry(lockFile.Meta.Sources),
+			Hashes:   sortedHashes(pkg.Hashes),
 		}
 		dependencies = append(dependencies, dep)
 	}
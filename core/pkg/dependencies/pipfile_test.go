@@ -418,6 +418,28 @@ func TestPipfileAnalyzer_ParsePipfileLock(t *testing.T) {
 				{Name: "package", Version: "1.0.0", Type: "runtime", Source: "pypi"},
 			},
 		},
+		{
+			name: "maps VCS and local sources",
+			content: `{
+				"_meta": {"pipfile-spec": 6, "requires": {}, "sources": []},
+				"default": {
+					"internal-lib": {
+						"git": "https://git.example.com/internal-lib.git",
+						"ref": "0c7d2a9"
+					},
+					"local-tool": {
+						"editable": true,
+						"path": "./tools/local-tool"
+					}
+				},
+				"develop": {}
+			}`,
+			wantNumDeps: 2,
+			checkDeps: []Dependency{
+				{Name: "internal-lib", Version: "", Type: "runtime", Source: "git"},
+				{Name: "local-tool", Version: "", Type: "runtime", Source: "path"},
+			},
+		},
 		{
 			name:        "handles invalid JSON",
 			content:     `invalid {{{ json`,
@@ -532,3 +554,31 @@ func TestPipfileAnalyzer_ParsePipfileLock(t *testing.T) {
 		})
 	}
 }
+
+func TestPipfileAnalyzer_ParsePipfileLock_Registry(t *testing.T) {
+	content := `{
+		"_meta": {"sources": [
+			{"name": "pypi", "url": "https://pypi.org/simple", "verify_ssl": true},
+			{"name": "internal", "url": "https://pypi.acme.example/simple", "verify_ssl": true}
+		]},
+		"default": {
+			"django": {"version": "==4.2.0", "hashes": ["sha256:bbb", "sha256:aaa"]},
+			"acme-core": {"version": "==2.0.0", "index": "internal"},
+			"mylib": {"git": "https://git.example.com/mylib.git", "ref": "abc"}
+		},
+		"develop": {}
+	}`
+	deps, err := NewPipfileAnalyzer().parsePipfileLock(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"django": "https://pypi.org/simple", "acme-core": "https://pypi.acme.example/simple", "mylib": ""}
+	for _, dep := range deps {
+		if dep.Registry != want[dep.Name] {
+			t.Errorf("%s Registry = %q, want %q", dep.Name, dep.Registry, want[dep.Name])
+		}
+		if dep.Name == "django" && (len(dep.Hashes) != 2 || dep.Hashes[0] != "sha256:aaa") {
+			t.Errorf("django Hashes = %v, want both digests sorted", dep.Hashes)
+		}
+	}
+}
@@ -3,10 +3,10 @@ package dependencies
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
 	"strings"
-
-	"github.com/BurntSushi/toml"
 )
 
 // PoetryAnalyzer implements the Analyzer interface for Python Poetry projects
@@ -23,6 +23,16 @@ func (p *PoetryAnalyzer) Name() string {
 	return string(AnalyzerPoetry)
 }
 
+// Ecosystem returns EcosystemPython
+func (p *PoetryAnalyzer) Ecosystem() Ecosystem {
+	return EcosystemPython
+}
+
+// ManifestAnalyzer returns the pyproject.toml analyzer used when no lock file is found
+func (p *PoetryAnalyzer) ManifestAnalyzer() Analyzer {
+	return NewPyprojectAnalyzer()
+}
+
 // CandidateFiles searches for poetry.lock files in the configured repository paths
 func (p *PoetryAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
 	if config.RepositoryClient == nil {
@@ -40,7 +50,7 @@ func (p *PoetryAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref st
 	// Search each configured path
 	for _, searchPath := range searchPaths {
 		// List all files recursively in this path
-		files, err := config.RepositoryClient.ListFilesRecursive(ctx, owner, repo, ref)
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list files: %w", err)
 		}
@@ -54,8 +64,8 @@ func (p *PoetryAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref st
 
 			// Check if this is a poetry.lock file
 			if strings.HasSuffix(file.Path, "poetry.lock") {
-				// If searchPath is specified, ensure file is within that path
-				if searchPath != "" && !strings.HasPrefix(file.Path, searchPath) {
+				// If searchPath is specified, ensure file is within that path; skip excluded paths
+				if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
 					continue
 				}
 
@@ -80,7 +90,9 @@ func (p *PoetryAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, r
 	result := make(map[string][]Dependency)
 
 	for _, file := range files {
-		deps, err := p.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		deps, err := traceFile(ctx, p.Name(), file.Path, func(ctx context.Context) ([]Dependency, error) {
+			return p.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		})
 		if err != nil {
 			// Don't fail completely if one file fails, just skip it
 			// Caller can check for incomplete results
@@ -90,6 +102,7 @@ func (p *PoetryAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, r
 				"repo", repo,
 				"ref", ref,
 				"error", err)
+			config.reportFileError(file.Path, err)
 			continue
 		}
 		result[file.Path] = deps
@@ -100,19 +113,27 @@ func (p *PoetryAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, r
 
 // analyzeFile analyzes a single poetry.lock file
 func (p *PoetryAnalyzer) analyzeFile(ctx context.Context, owner, repo, ref, filePath string, config Config) ([]Dependency, error) {
-	// Get the file content from the repository
-	content, err := config.RepositoryClient.GetFileContent(ctx, owner, repo, ref, filePath)
+	// Stream the file content from the repository
+	body, err := openFileStream(ctx, config, owner, repo, ref, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
 	}
+	defer body.Close()
 
-	// Parse the poetry.lock file
-	dependencies, err := p.parsePoetryLock(content)
+	// Parse the poetry.lock file as it downloads
+	dependencies, err := p.decodePoetryLock(body)
+	if body.err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, body.err)
+	}
 	if err != nil {
 		slog.Debug("Failed to parse poetry.lock content",
 			"file", filePath,
 			"error", err)
-		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+		return nil, &ParseError{Path: filePath, Err: err}
+	}
+
+	if config.IncludeConstraints {
+		applyManifestConstraints(ctx, owner, repo, ref, filePath, pyprojectManifest, parsePyprojectConstraints, dependencies, config)
 	}
 
 	return dependencies, nil
@@ -131,19 +152,67 @@ type poetryPackage struct {
 	Description string `toml:"description"`
 	Category    string `toml:"category"`
 	Optional    bool   `toml:"optional"`
+
+	// Dependencies maps required package names to version specs (a string
+	// or a table with markers/extras)
+	Dependencies map[string]any `toml:"dependencies"`
+
+	// Source is set for packages not installed from PyPI
+	Source poetrySource `toml:"source"`
+
+	// Files lists the package's artifacts (lock format 2.0; older lock
+	// files keep them under [metadata.files])
+	Files []lockedFile `toml:"files"`
+}
+
+// lockedFile is an artifact entry of a poetry.lock or pdm.lock package
+type lockedFile struct {
+	File string `toml:"file"`
+	URL  string `toml:"url"`
+	Hash string `toml:"hash"`
+}
+
+// lockedFileHashes returns the sorted digests of files
+func lockedFileHashes(files []lockedFile) []string {
+	var hashes []string
+	for _, f := range files {
+		if f.Hash != "" {
+			hashes = append(hashes, f.Hash)
+		}
+	}
+	slices.Sort(hashes)
+	return slices.Compact(hashes)
+}
+
+// poetrySource represents the [package.source] table of poetry.lock
+type poetrySource struct {
+	Type string `toml:"type"` // git, directory, file, url or legacy (another index)
+	URL  string `toml:"url"`  // Repository, file or index URL
 }
 
 // poetryMetadata represents the metadata section of poetry.lock
 type poetryMetadata struct {
 	PythonVersions string `toml:"python-versions"`
 	ContentHash    string `toml:"content-hash"`
+
+	// Files maps package names to their artifacts (lock format 1.x)
+	Files map[string][]lockedFile `toml:"files"`
 }
 
 // parsePoetryLock parses the content of a poetry.lock file
 func (p *PoetryAnalyzer) parsePoetryLock(content string) ([]Dependency, error) {
-	var lockFile poetryLockFile
+	return p.decodePoetryLock(strings.NewReader(content))
+}
 
-	if _, err := toml.Decode(content, &lockFile); err != nil {
+// decodePoetryLock parses a poetry.lock file read from r
+func (p *PoetryAnalyzer) decodePoetryLock(r io.Reader) ([]Dependency, error) {
+	lockFile, err := decodeLockTables(r, func(dst, src *poetryLockFile) {
+		dst.Package = append(dst.Package, src.Package...)
+		if src.Metadata.ContentHash != "" || src.Metadata.PythonVersions != "" || src.Metadata.Files != nil {
+			dst.Metadata = src.Metadata
+		}
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse poetry.lock: %w", err)
 	}
 
@@ -158,12 +227,32 @@ func (p *PoetryAnalyzer) parsePoetryLock(content string) ([]Dependency, error) {
 			depType = "optional"
 		}
 
+		source, registry := "pypi", PyPIRegistry
+		switch pkg.Source.Type {
+		case "":
+		case "legacy":
+			source, registry = pkg.Source.Type, pkg.Source.URL
+		case "directory", "file":
+			source, registry = "path", ""
+		default:
+			source, registry = pkg.Source.Type, ""
+		}
+
 		dep := Dependency{
-			Name:    pkg.Name,
-			Version: pkg.Version,
-			Type:    depType,
-			Source:  "pypi",
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Type:     depType,
+			Source:   source,
+			Registry: registry,
+			Hashes:   lockedFileHashes(pkg.Files),
+		}
+		if dep.Hashes == nil {
+			dep.Hashes = lockedFileHashes(lockFile.Metadata.Files[pkg.Name])
+		}
+		for name := range pkg.Dependencies {
+			dep.Requires = append(dep.Requires, name)
 		}
+		slices.Sort(dep.Requires)
 
 		dependencies = append(dependencies, dep)
 	}
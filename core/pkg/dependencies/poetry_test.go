@@ -3,6 +3,7 @@ package dependencies
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/repository"
@@ -21,6 +22,7 @@ func TestPoetryAnalyzer_CandidateFiles(t *testing.T) {
 		mockFiles   []repository.FileInfo
 		mockError   error
 		searchPaths []string
+		exclude     []string
 		want        []DependencyFile
 		wantErr     bool
 	}{
@@ -64,6 +66,20 @@ func TestPoetryAnalyzer_CandidateFiles(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "skips excluded paths",
+			mockFiles: []repository.FileInfo{
+				{Path: "poetry.lock", Type: "file"},
+				{Path: "tests/fixtures/poetry.lock", Type: "file"},
+				{Path: "examples/demo/poetry.lock", Type: "file"},
+			},
+			searchPaths: []string{""},
+			exclude:     []string{"fixtures", "examples/*"},
+			want: []DependencyFile{
+				{Path: "poetry.lock", Type: "poetry.lock", Analyzer: "poetry"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "ignores directories",
 			mockFiles: []repository.FileInfo{
@@ -100,6 +116,7 @@ func TestPoetryAnalyzer_CandidateFiles(t *testing.T) {
 			if tt.name != "returns error when client is nil" {
 				config = Config{
 					RepositoryPaths: tt.searchPaths,
+					ExcludePaths:    tt.exclude,
 					RepositoryClient: &mockRepoClient{
 						files: tt.mockFiles,
 						err:   tt.mockError,
@@ -290,6 +307,28 @@ content-hash = "abc123"
 	}
 }
 
+func TestPoetryAnalyzer_AnalyzeDependencies_ParseError(t *testing.T) {
+	analyzer := NewPoetryAnalyzer()
+	var err error
+	config := Config{
+		RepositoryClient: &mockRepoClient{content: "this is not valid TOML"},
+		OnFileError:      func(_ string, fileErr error) { err = fileErr },
+	}
+	files := []DependencyFile{{Path: "app/poetry.lock", Type: "poetry"}}
+
+	if _, aErr := analyzer.AnalyzeDependencies(context.Background(), "owner", "repo", "main", files, config); aErr != nil {
+		t.Fatalf("AnalyzeDependencies should skip unparsable files, got error: %v", aErr)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+	if parseErr.Path != "app/poetry.lock" {
+		t.Errorf("expected path app/poetry.lock, got %q", parseErr.Path)
+	}
+}
+
 func TestPoetryAnalyzer_ParsePoetryLock(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -449,3 +488,84 @@ content-hash = "xyz789"
 		})
 	}
 }
+
+func TestPoetryAnalyzer_ParsePoetryLock_Sources(t *testing.T) {
+	content := `[[package]]
+name = "django"
+version = "4.2.0"
+
+[[package]]
+name = "internal-lib"
+version = "1.0.0"
+
+[package.source]
+type = "git"
+url = "https://git.example.com/internal-lib.git"
+
+[[package]]
+name = "local-tool"
+version = "0.1.0"
+
+[package.source]
+type = "directory"
+url = "tools/local-tool"
+
+[[package]]
+name = "acme-core"
+version = "2.0.0"
+
+[package.source]
+type = "legacy"
+url = "https://pypi.acme.example/simple"
+reference = "acme"
+`
+	deps, err := NewPoetryAnalyzer().parsePoetryLock(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"django": "pypi", "internal-lib": "git", "local-tool": "path", "acme-core": "legacy"}
+	registries := map[string]string{"django": PyPIRegistry, "acme-core": "https://pypi.acme.example/simple"}
+	for _, dep := range deps {
+		if dep.Source != want[dep.Name] {
+			t.Errorf("%s Source = %q, want %q", dep.Name, dep.Source, want[dep.Name])
+		}
+		if dep.Registry != registries[dep.Name] {
+			t.Errorf("%s Registry = %q, want %q", dep.Name, dep.Registry, registries[dep.Name])
+		}
+	}
+}
+
+func TestPoetryAnalyzer_ParsePoetryLock_Hashes(t *testing.T) {
+	content := `
+[[package]]
+name = "django"
+version = "4.2.0"
+files = [
+    {file = "Django-4.2-py3-none-any.whl", hash = "sha256:bbb"},
+    {file = "Django-4.2.tar.gz", hash = "sha256:aaa"},
+]
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+
+[[package]]
+name = "six"
+version = "1.16.0"
+
+[metadata.files]
+six = [
+    {file = "six-1.16.0.tar.gz", hash = "sha256:ccc"},
+]
+`
+	deps, err := NewPoetryAnalyzer().parsePoetryLock(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"django": "[sha256:aaa sha256:bbb]", "requests": "[]", "six": "[sha256:ccc]"}
+	for _, dep := range deps {
+		if got := fmt.Sprint(dep.Hashes); got != want[dep.Name] {
+			t.Errorf("%s Hashes = %s, want %s", dep.Name, got, want[dep.Name])
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// preCommitConfigFiles are the file names pre-commit reads its configuration from
+var preCommitConfigFiles = []string{".pre-commit-config.yaml", ".pre-commit-config.yml"}
+
+// PreCommitAnalyzer implements the Analyzer interface for pre-commit hooks
+// It reports each hook repository of .pre-commit-config.yaml files as a
+// dependency named by its URL and versioned by its rev, so hook versions can
+// be kept in sync across repositories like any other package
+type PreCommitAnalyzer struct{}
+
+// NewPreCommitAnalyzer creates a new pre-commit hook analyzer
+func NewPreCommitAnalyzer() *PreCommitAnalyzer {
+	return &PreCommitAnalyzer{}
+}
+
+// Name returns the name of this analyzer
+func (p *PreCommitAnalyzer) Name() string {
+	return string(AnalyzerPreCommit)
+}
+
+// Ecosystem returns EcosystemPreCommit
+func (p *PreCommitAnalyzer) Ecosystem() Ecosystem {
+	return EcosystemPreCommit
+}
+
+// CandidateFiles searches for .pre-commit-config.yaml files in the configured repository paths
+func (p *PreCommitAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	searchPaths := config.RepositoryPaths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{""}
+	}
+
+	var candidates []DependencyFile
+	for _, searchPath := range searchPaths {
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+		for _, file := range files {
+			base := path.Base(file.Path)
+			if file.Type != "file" || !slices.Contains(preCommitConfigFiles, base) {
+				continue
+			}
+			if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
+				continue
+			}
+			candidates = append(candidates, DependencyFile{
+				Path:     file.Path,
+				Type:     base,
+				Analyzer: p.Name(),
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// AnalyzeDependencies reads the hook repositories of each pre-commit configuration
+func (p *PreCommitAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, ref string, files []DependencyFile, config Config) (map[string][]Dependency, error) {
+	if config.RepositoryClient == nil {
+		return nil, fmt.Errorf("repository client is required")
+	}
+
+	result := make(map[string][]Dependency)
+	for _, file := range files {
+		deps, err := traceFile(ctx, p.Name(), file.Path, func(ctx context.Context) ([]Dependency, error) {
+			return p.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		})
+		if err != nil {
+			// Don't fail completely if one file fails, just skip it
+			slog.Debug("Failed to analyze pre-commit configuration",
+				"file", file.Path,
+				"owner", owner,
+				"repo", repo,
+				"ref", ref,
+				"error", err)
+			config.reportFileError(file.Path, err)
+			continue
+		}
+		result[file.Path] = deps
+	}
+
+	return result, nil
+}
+
+// analyzeFile analyzes a single pre-commit configuration
+func (p *PreCommitAnalyzer) analyzeFile(ctx context.Context, owner, repo, ref, filePath string, config Config) ([]Dependency, error) {
+	body, err := openFileStream(ctx, config, owner, repo, ref, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
+	}
+	defer body.Close()
+
+	deps, err := p.decodePreCommitConfig(body)
+	if body.err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, body.err)
+	}
+	if err != nil {
+		return nil, &ParseError{Path: filePath, Err: err}
+	}
+	return deps, nil
+}
+
+// preCommitRepo is one entry of a pre-commit configuration's repos list
+type preCommitRepo struct {
+	Repo string `yaml:"repo"`
+	Rev  string `yaml:"rev"`
+}
+
+// decodePreCommitConfig parses a pre-commit configuration read from r. Each
+// remote hook repository becomes a dev dependency with Source "git" and its
+// rev as Version; the "local" and "meta" pseudo-repositories are skipped.
+// A rev frozen to a commit by "pre-commit autoupdate --freeze" is reported as
+// the tag in its "# frozen: <tag>" comment, so frozen and unfrozen
+// configurations compare.
+func (p *PreCommitAnalyzer) decodePreCommitConfig(r io.Reader) ([]Dependency, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse pre-commit configuration: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse pre-commit configuration: not a mapping")
+	}
+
+	var repos *yaml.Node
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "repos" {
+			repos = root.Content[i+1]
+		}
+	}
+	if repos == nil {
+		return nil, nil
+	}
+	if repos.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("failed to parse pre-commit configuration: repos is not a list")
+	}
+
+	var deps []Dependency
+	for _, node := range repos.Content {
+		var entry preCommitRepo
+		if err := node.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("failed to parse pre-commit configuration: %w", err)
+		}
+		if entry.Repo == "" || entry.Repo == "local" || entry.Repo == "meta" {
+			continue
+		}
+		dep := Dependency{
+			Name:    entry.Repo,
+			Version: entry.Rev,
+			Type:    "dev",
+			Source:  "git",
+			Direct:  true,
+		}
+		if tag := preCommitFrozenTag(node); tag != "" {
+			dep.Version = tag
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// preCommitFrozenTag returns the tag recorded in the "# frozen: <tag>"
+// comment of a repos entry's rev, or ""
+func preCommitFrozenTag(entry *yaml.Node) string {
+	for i := 0; i+1 < len(entry.Content); i += 2 {
+		if entry.Content[i].Value != "rev" {
+			continue
+		}
+		comment := strings.TrimSpace(strings.TrimPrefix(entry.Content[i+1].LineComment, "#"))
+		if tag, ok := strings.CutPrefix(comment, "frozen:"); ok {
+			return strings.TrimSpace(tag)
+		}
+	}
+	return ""
+}
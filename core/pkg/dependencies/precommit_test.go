@@ -0,0 +1,102 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+const testPreCommitConfig = `default_language_version:
+  python: python3.12
+repos:
+  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v4.5.0
+    hooks:
+      - id: trailing-whitespace
+      - id: end-of-file-fixer
+  - repo: https://github.com/psf/black
+    rev: 6fdf8a4af28071ed1d079c01122b34c5d587207a  # frozen: 24.2.0
+    hooks:
+      - id: black
+  - repo: local
+    hooks:
+      - id: pylint
+        name: pylint
+        entry: pylint
+        language: system
+  - repo: meta
+    hooks:
+      - id: check-hooks-apply
+`
+
+func TestPreCommitAnalyzer_ParseConfig(t *testing.T) {
+	deps, err := NewPreCommitAnalyzer().decodePreCommitConfig(strings.NewReader(testPreCommitConfig))
+	if err != nil {
+		t.Fatalf("decodePreCommitConfig: %v", err)
+	}
+	want := []Dependency{
+		{Name: "https://github.com/pre-commit/pre-commit-hooks", Version: "v4.5.0", Type: "dev", Source: "git", Direct: true},
+		{Name: "https://github.com/psf/black", Version: "24.2.0", Type: "dev", Source: "git", Direct: true},
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("got %+v, want %+v", deps, want)
+	}
+	for i := range want {
+		if deps[i].Name != want[i].Name || deps[i].Version != want[i].Version || deps[i].Type != want[i].Type ||
+			deps[i].Source != want[i].Source || deps[i].Direct != want[i].Direct {
+			t.Errorf("deps[%d] = %+v, want %+v", i, deps[i], want[i])
+		}
+	}
+
+	for _, content := range []string{"", "fail_fast: true\n"} {
+		if deps, err := NewPreCommitAnalyzer().decodePreCommitConfig(strings.NewReader(content)); err != nil || len(deps) != 0 {
+			t.Errorf("decodePreCommitConfig(%q) = %+v, %v", content, deps, err)
+		}
+	}
+	if _, err := NewPreCommitAnalyzer().decodePreCommitConfig(strings.NewReader("repos: 3\n")); err == nil {
+		t.Error("expected an error for a non-list repos")
+	}
+}
+
+func TestPreCommitAnalyzer_AnalyzeDependencies(t *testing.T) {
+	client := &mockRepoClient{
+		files: []repository.FileInfo{
+			{Path: ".pre-commit-config.yaml", Type: "file"},
+			{Path: "tools/.pre-commit-config.yml", Type: "file"},
+			{Path: "pre-commit-config.yaml", Type: "file"},
+		},
+		byPath: map[string]string{
+			".pre-commit-config.yaml":      testPreCommitConfig,
+			"tools/.pre-commit-config.yml": "repos: [unclosed",
+		},
+	}
+	var fileErrs []error
+	config := Config{RepositoryClient: client, OnFileError: func(_ string, err error) { fileErrs = append(fileErrs, err) }}
+
+	analyzer := NewPreCommitAnalyzer()
+	candidates, err := analyzer.CandidateFiles(context.Background(), "o", "r", "main", config)
+	if err != nil {
+		t.Fatalf("CandidateFiles: %v", err)
+	}
+	if len(candidates) != 2 || candidates[0].Analyzer != "pre-commit" || candidates[1].Type != ".pre-commit-config.yml" {
+		t.Fatalf("candidates = %+v", candidates)
+	}
+
+	results, err := analyzer.AnalyzeDependencies(context.Background(), "o", "r", "main", candidates, config)
+	if err != nil {
+		t.Fatalf("AnalyzeDependencies: %v", err)
+	}
+	if len(results) != 1 || len(results[".pre-commit-config.yaml"]) != 2 {
+		t.Errorf("results = %+v", results)
+	}
+	var parseErr *ParseError
+	if len(fileErrs) != 1 || !errors.As(fileErrs[0], &parseErr) {
+		t.Errorf("file errors = %v, want one parse error", fileErrs)
+	}
+	if got := EcosystemForAnalyzer("pre-commit"); got != EcosystemPreCommit {
+		t.Errorf("EcosystemForAnalyzer(pre-commit) = %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+package dependencies
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// EcosystemAnalyzer is implemented by registered analyzers whose packages
+// follow an ecosystem's naming and versioning rules (see
+// EcosystemForAnalyzer). Analyzers without it are EcosystemUnknown.
+type EcosystemAnalyzer interface {
+	Ecosystem() Ecosystem
+}
+
+// registration is one registered analyzer type
+type registration struct {
+	factory   func() Analyzer
+	builtin   bool // Shipped with devdashboard
+	exec      bool // Added by RegisterExec; may be replaced
+	once      sync.Once
+	ecosystem Ecosystem // Set by ecosystemOf
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*registration)
+)
+
+func init() {
+	registerBuiltin(AnalyzerPoetry, func() Analyzer { return NewPoetryAnalyzer() })
+	registerBuiltin(AnalyzerPipfile, func() Analyzer { return NewPipfileAnalyzer() })
+	registerBuiltin(AnalyzerUvLock, func() Analyzer { return NewUvLockAnalyzer() })
+	registerBuiltin(AnalyzerPdm, func() Analyzer { return NewPdmAnalyzer() })
+	registerBuiltin(AnalyzerHatch, func() Analyzer { return NewHatchAnalyzer() })
+	registerBuiltin(AnalyzerPyproject, func() Analyzer { return NewPyprojectAnalyzer() })
+	registerBuiltin(AnalyzerPipfileManifest, func() Analyzer { return NewPipfileManifestAnalyzer() })
+	registerBuiltin(AnalyzerPreCommit, func() Analyzer { return NewPreCommitAnalyzer() })
+}
+
+// registerBuiltin registers one of the analyzers shipped with devdashboard
+func registerBuiltin(t AnalyzerType, factory func() Analyzer) {
+	registry[string(t)] = &registration{factory: factory, builtin: true}
+}
+
+// Register makes an analyzer type available to the Factory (and so to the
+// "analyzer" field of the configuration) under name, which is matched
+// case-insensitively. Programs embedding devdashboard call it from an init
+// function to plug in their own ecosystems. Register panics if name is empty,
+// factory is nil or the name is already registered.
+func Register(name string, factory func() Analyzer) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		panic("dependencies: Register with empty analyzer name")
+	}
+	if factory == nil {
+		panic("dependencies: Register analyzer " + key + " with nil factory")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[key]; dup {
+		panic("dependencies: Register called twice for analyzer " + key)
+	}
+	registry[key] = &registration{factory: factory}
+}
+
+// RegisterExec registers an external analyzer program under its
+// AnalyzerName, replacing an exec analyzer previously registered under that
+// name (configurations are reloaded). It fails if the name is empty or taken
+// by a built-in analyzer or one added with Register.
+func RegisterExec(a *ExecAnalyzer) error {
+	key := strings.ToLower(strings.TrimSpace(a.AnalyzerName))
+	if key == "" {
+		return fmt.Errorf("exec analyzer missing name")
+	}
+	if len(a.Command) == 0 || a.Command[0] == "" {
+		return fmt.Errorf("exec analyzer %s missing command", key)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if r, ok := registry[key]; ok && !r.exec {
+		return fmt.Errorf("analyzer %s is already registered", key)
+	}
+	registry[key] = &registration{factory: func() Analyzer { return a }, exec: true}
+	return nil
+}
+
+// lookup returns the registration of an analyzer type (case-insensitive)
+func lookup(name string) (*registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[strings.ToLower(strings.TrimSpace(name))]
+	return r, ok
+}
+
+// create instantiates a registered analyzer type
+func create(name string) (Analyzer, error) {
+	r, ok := lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unsupported analyzer type: %s (supported: %s)", name, strings.Join(SupportedAnalyzers(), ", "))
+	}
+	return r.factory(), nil
+}
+
+// ecosystemOf returns the ecosystem of a registered analyzer, asking an
+// instance once
+func (r *registration) ecosystemOf() Ecosystem {
+	r.once.Do(func() {
+		if ea, ok := r.factory().(EcosystemAnalyzer); ok {
+			r.ecosystem = ea.Ecosystem()
+		}
+	})
+	return r.ecosystem
+}
+
+// registeredAnalyzers returns the built-in analyzer types in their usual
+// order followed by the registered ones, sorted
+func registeredAnalyzers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := []string{string(AnalyzerPoetry), string(AnalyzerPipfile), string(AnalyzerUvLock),
+		string(AnalyzerPdm), string(AnalyzerHatch), string(AnalyzerPyproject), string(AnalyzerPipfileManifest),
+		string(AnalyzerPreCommit)}
+	var extra []string
+	for name, r := range registry {
+		if !r.builtin {
+			extra = append(extra, name)
+		}
+	}
+	slices.Sort(extra)
+	return append(names, extra...)
+}
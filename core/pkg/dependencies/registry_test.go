@@ -0,0 +1,81 @@
+package dependencies
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// fakeAnalyzer is a stand-in for an analyzer registered by an embedding program
+type fakeAnalyzer struct{ ecosystem Ecosystem }
+
+func (f *fakeAnalyzer) Name() string { return "cargo" }
+
+func (f *fakeAnalyzer) Ecosystem() Ecosystem { return f.ecosystem }
+
+func (f *fakeAnalyzer) CandidateFiles(context.Context, string, string, string, Config) ([]DependencyFile, error) {
+	return nil, nil
+}
+
+func (f *fakeAnalyzer) AnalyzeDependencies(context.Context, string, string, string, []DependencyFile, Config) (map[string][]Dependency, error) {
+	return nil, nil
+}
+
+// registerForTest registers an analyzer and removes it when the test ends
+func registerForTest(t *testing.T, name string, factory func() Analyzer) {
+	t.Helper()
+	Register(name, factory)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, name)
+		registryMu.Unlock()
+	})
+}
+
+func TestRegister(t *testing.T) {
+	registerForTest(t, "cargo", func() Analyzer { return &fakeAnalyzer{ecosystem: "rust"} })
+	registerForTest(t, "apk", func() Analyzer { return &fakeAnalyzer{} })
+
+	analyzer, err := NewAnalyzer(" Cargo ")
+	if err != nil {
+		t.Fatalf("NewAnalyzer: %v", err)
+	}
+	if _, ok := analyzer.(*fakeAnalyzer); !ok {
+		t.Errorf("NewAnalyzer returned %T, want *fakeAnalyzer", analyzer)
+	}
+
+	want := []string{"poetry", "pipfile", "uvlock", "pdm", "hatch", "pyproject", "pipfile-manifest", "pre-commit", "apk", "cargo"}
+	if got := SupportedAnalyzers(); !slices.Equal(got, want) {
+		t.Errorf("SupportedAnalyzers() = %v, want %v", got, want)
+	}
+
+	if got := EcosystemForAnalyzer("cargo"); got != "rust" {
+		t.Errorf("EcosystemForAnalyzer(cargo) = %q, want rust", got)
+	}
+	if got := EcosystemForAnalyzer("apk"); got != EcosystemUnknown {
+		t.Errorf("EcosystemForAnalyzer(apk) = %q, want EcosystemUnknown", got)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	factory := func() Analyzer { return &fakeAnalyzer{} }
+	tests := []struct {
+		name     string
+		analyzer string
+		factory  func() Analyzer
+	}{
+		{name: "empty name", analyzer: " ", factory: factory},
+		{name: "nil factory", analyzer: "cargo", factory: nil},
+		{name: "built-in name", analyzer: "Poetry", factory: factory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected Register to panic")
+				}
+			}()
+			Register(tt.analyzer, tt.factory)
+		})
+	}
+}
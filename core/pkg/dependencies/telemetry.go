@@ -0,0 +1,28 @@
+package dependencies
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+)
+
+// traceFile runs analyze, the fetching and parsing of one file by analyzer,
+// in a span and records its duration (see telemetry.Setup)
+func traceFile(ctx context.Context, analyzer, path string, analyze func(context.Context) ([]Dependency, error)) ([]Dependency, error) {
+	ctx, span := telemetry.Start(ctx, "dependencies.analyzeFile",
+		attribute.String("devdashboard.analyzer", analyzer),
+		attribute.String("devdashboard.file", path))
+	start := time.Now()
+	deps, err := analyze(ctx)
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	span.SetAttributes(attribute.Int("devdashboard.dependencies", len(deps)))
+	telemetry.RecordFile(ctx, time.Since(start), analyzer, outcome)
+	telemetry.End(span, err)
+	return deps, err
+}
@@ -2,6 +2,8 @@ package dependencies
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/repository"
 )
@@ -11,6 +13,8 @@ type mockRepoClient struct {
 	files   []repository.FileInfo
 	content string
 	err     error
+	// byPath, if set, serves GetFileContent per path instead of content
+	byPath map[string]string
 }
 
 func (m *mockRepoClient) GetRepositoryInfo(_ context.Context, owner, repo string) (*repository.Info, error) {
@@ -41,9 +45,32 @@ func (m *mockRepoClient) ListFilesRecursive(_ context.Context, _, _, _ string) (
 	return m.files, nil
 }
 
-func (m *mockRepoClient) GetFileContent(_ context.Context, _, _, _, _ string) (string, error) {
+func (m *mockRepoClient) ListFilesUnder(_ context.Context, _, _, _, prefix string) ([]repository.FileInfo, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if prefix == "" {
+		return m.files, nil
+	}
+	var files []repository.FileInfo
+	for _, f := range m.files {
+		if strings.HasPrefix(f.Path, strings.TrimSuffix(prefix, "/")+"/") {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func (m *mockRepoClient) GetFileContent(_ context.Context, _, _, _, path string) (string, error) {
 	if m.err != nil {
 		return "", m.err
 	}
+	if m.byPath != nil {
+		content, ok := m.byPath[path]
+		if !ok {
+			return "", fmt.Errorf("file not found: %s", path)
+		}
+		return content, nil
+	}
 	return m.content, nil
 }
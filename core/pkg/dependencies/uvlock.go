@@ -3,10 +3,10 @@ package dependencies
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
 	"strings"
-
-	"github.com/BurntSushi/toml"
 )
 
 // UvLockAnalyzer implements the Analyzer interface for Python uv projects
@@ -23,6 +23,16 @@ func (u *UvLockAnalyzer) Name() string {
 	return string(AnalyzerUvLock)
 }
 
+// Ecosystem returns EcosystemPython
+func (u *UvLockAnalyzer) Ecosystem() Ecosystem {
+	return EcosystemPython
+}
+
+// ManifestAnalyzer returns the pyproject.toml analyzer used when no lock file is found
+func (u *UvLockAnalyzer) ManifestAnalyzer() Analyzer {
+	return NewPyprojectAnalyzer()
+}
+
 // CandidateFiles searches for uv.lock files in the configured repository paths
 func (u *UvLockAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref string, config Config) ([]DependencyFile, error) {
 	if config.RepositoryClient == nil {
@@ -40,7 +50,7 @@ func (u *UvLockAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref st
 	// Search each configured path
 	for _, searchPath := range searchPaths {
 		// List all files recursively in this path
-		files, err := config.RepositoryClient.ListFilesRecursive(ctx, owner, repo, ref)
+		files, err := config.RepositoryClient.ListFilesUnder(ctx, owner, repo, ref, searchPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list files: %w", err)
 		}
@@ -54,8 +64,8 @@ func (u *UvLockAnalyzer) CandidateFiles(ctx context.Context, owner, repo, ref st
 
 			// Check if this is a uv.lock file
 			if strings.HasSuffix(file.Path, "uv.lock") {
-				// If searchPath is specified, ensure file is within that path
-				if searchPath != "" && !strings.HasPrefix(file.Path, searchPath) {
+				// If searchPath is specified, ensure file is within that path; skip excluded paths
+				if (searchPath != "" && !strings.HasPrefix(file.Path, searchPath)) || config.Excluded(file.Path) {
 					continue
 				}
 
@@ -80,7 +90,9 @@ func (u *UvLockAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, r
 	result := make(map[string][]Dependency)
 
 	for _, file := range files {
-		deps, err := u.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		deps, err := traceFile(ctx, u.Name(), file.Path, func(ctx context.Context) ([]Dependency, error) {
+			return u.analyzeFile(ctx, owner, repo, ref, file.Path, config)
+		})
 		if err != nil {
 			// Don't fail completely if one file fails, just skip it
 			slog.Debug("Failed to analyze uv.lock file",
@@ -89,6 +101,7 @@ func (u *UvLockAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, r
 				"repo", repo,
 				"ref", ref,
 				"error", err)
+			config.reportFileError(file.Path, err)
 			continue
 		}
 		result[file.Path] = deps
@@ -99,19 +112,27 @@ func (u *UvLockAnalyzer) AnalyzeDependencies(ctx context.Context, owner, repo, r
 
 // analyzeFile analyzes a single uv.lock file
 func (u *UvLockAnalyzer) analyzeFile(ctx context.Context, owner, repo, ref, filePath string, config Config) ([]Dependency, error) {
-	// Get the file content from the repository
-	content, err := config.RepositoryClient.GetFileContent(ctx, owner, repo, ref, filePath)
+	// Stream the file content from the repository
+	body, err := openFileStream(ctx, config, owner, repo, ref, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
 	}
+	defer body.Close()
 
-	// Parse the uv.lock file
-	dependencies, err := u.parseUvLock(content)
+	// Parse the uv.lock file as it downloads
+	dependencies, err := u.decodeUvLock(body)
+	if body.err != nil {
+		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, body.err)
+	}
 	if err != nil {
 		slog.Debug("Failed to parse uv.lock content",
 			"file", filePath,
 			"error", err)
-		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+		return nil, &ParseError{Path: filePath, Err: err}
+	}
+
+	if config.IncludeConstraints {
+		applyManifestConstraints(ctx, owner, repo, ref, filePath, pyprojectManifest, parsePyprojectConstraints, dependencies, config)
 	}
 
 	return dependencies, nil
@@ -148,6 +169,7 @@ type uvSource struct {
 	Branch   string `toml:"branch"`
 	Path     string `toml:"path"`
 	Editable string `toml:"editable"`
+	Virtual  string `toml:"virtual"`
 }
 
 // uvDependency represents a dependency specification
@@ -172,11 +194,32 @@ type uvSdist struct {
 	Size int64  `toml:"size"`
 }
 
+// hashes returns the sorted digests of the package's sdist and wheels
+func (p uvPackage) hashes() []string {
+	var hashes []string
+	if p.Sdist.Hash != "" {
+		hashes = append(hashes, p.Sdist.Hash)
+	}
+	for _, w := range p.Wheels {
+		if w.Hash != "" {
+			hashes = append(hashes, w.Hash)
+		}
+	}
+	slices.Sort(hashes)
+	return slices.Compact(hashes)
+}
+
 // parseUvLock parses the content of a uv.lock file
 func (u *UvLockAnalyzer) parseUvLock(content string) ([]Dependency, error) {
-	var lockFile uvLockFile
+	return u.decodeUvLock(strings.NewReader(content))
+}
 
-	if _, err := toml.Decode(content, &lockFile); err != nil {
+// decodeUvLock parses a uv.lock file read from r
+func (u *UvLockAnalyzer) decodeUvLock(r io.Reader) ([]Dependency, error) {
+	lockFile, err := decodeLockTables(r, func(dst, src *uvLockFile) {
+		dst.Packages = append(dst.Packages, src.Packages...)
+	})
+	if err != nil {
 		slog.Debug("Failed to decode uv.lock content", "error", err)
 		return nil, fmt.Errorf("failed to parse uv.lock: %w", err)
 	}
@@ -217,14 +260,51 @@ func (u *UvLockAnalyzer) parseUvLock(content string) ([]Dependency, error) {
 		}
 
 		dep := Dependency{
-			Name:    pkg.Name,
-			Version: pkg.Version,
-			Type:    depType,
-			Source:  source,
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Type:     depType,
+			Source:   source,
+			Registry: pkg.Source.Registry,
+			Hashes:   pkg.hashes(),
+		}
+		for _, req := range pkg.Dependencies {
+			dep.Requires = append(dep.Requires, req.Name)
+		}
+		for _, group := range pkg.DevDependencies {
+			for _, req := range group {
+				dep.Requires = append(dep.Requires, req.Name)
+			}
 		}
+		slices.Sort(dep.Requires)
+		dep.Requires = slices.Compact(dep.Requires)
 
 		dependencies = append(dependencies, dep)
 	}
+	markUvDirect(lockFile.Packages, dependencies)
 
 	return dependencies, nil
 }
+
+// markUvDirect flags the packages required by the project's own entries,
+// which uv records with an editable or virtual source
+func markUvDirect(packages []uvPackage, deps []Dependency) {
+	direct := make(map[string]bool)
+	for _, pkg := range packages {
+		if pkg.Source.Editable == "" && pkg.Source.Virtual == "" {
+			continue
+		}
+		for _, req := range pkg.Dependencies {
+			direct[normalizeManifestName(req.Name)] = true
+		}
+		for _, group := range pkg.DevDependencies {
+			for _, req := range group {
+				direct[normalizeManifestName(req.Name)] = true
+			}
+		}
+	}
+	for i := range deps {
+		if direct[normalizeManifestName(deps[i].Name)] {
+			deps[i].Direct = true
+		}
+	}
+}
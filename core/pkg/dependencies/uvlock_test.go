@@ -814,6 +814,15 @@ wheels = [
 		}
 	}
 
+	for _, dep := range deps {
+		if dep.Name == "cfgv" && dep.Registry != "https://pypidev.ivrtechnology.com/" {
+			t.Errorf("cfgv Registry = %q, want the private registry URL", dep.Registry)
+		}
+		if dep.Name == "cfgv" && len(dep.Hashes) != 2 {
+			t.Errorf("cfgv Hashes = %v, want the sdist and wheel digests", dep.Hashes)
+		}
+	}
+
 	// Verify the tmp package is marked as dev (it has dev-dependencies)
 	var tmpPkg *Dependency
 	for i := range deps {
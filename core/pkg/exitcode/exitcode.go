@@ -0,0 +1,122 @@
+// Package exitcode defines the stable process exit codes returned by DevDashboard
+// commands, along with a typed error that carries a code from core packages up
+// to the CLI. CI wrappers can branch on these codes to distinguish failure classes.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is a process exit status.
+type Code int
+
+const (
+	// OK indicates the command completed successfully.
+	OK Code = 0
+	// Failure indicates an unclassified error (I/O, internal failure, usage).
+	Failure Code = 1
+	// Partial indicates some repositories failed while others produced results.
+	Partial Code = 2
+	// PolicyViolation indicates the report violated a configured policy.
+	PolicyViolation Code = 3
+	// ConfigError indicates an invalid or unreadable configuration.
+	ConfigError Code = 4
+	// ProviderError indicates provider API or authentication failures.
+	ProviderError Code = 5
+)
+
+// All returns every defined code in ascending order.
+func All() []Code {
+	return []Code{OK, Failure, Partial, PolicyViolation, ConfigError, ProviderError}
+}
+
+// String returns the short machine-friendly name of the code.
+func (c Code) String() string {
+	switch c {
+	case OK:
+		return "ok"
+	case Failure:
+		return "failure"
+	case Partial:
+		return "partial"
+	case PolicyViolation:
+		return "policy-violation"
+	case ConfigError:
+		return "config-error"
+	case ProviderError:
+		return "provider-error"
+	default:
+		return fmt.Sprintf("code-%d", int(c))
+	}
+}
+
+// Description returns a human-readable explanation of the code.
+func (c Code) Description() string {
+	switch c {
+	case OK:
+		return "Success"
+	case Failure:
+		return "General or internal error"
+	case Partial:
+		return "Some repositories failed to analyze (partial results)"
+	case PolicyViolation:
+		return "Report violates a configured policy"
+	case ConfigError:
+		return "Invalid or unreadable configuration"
+	case ProviderError:
+		return "Provider API or authentication failure"
+	default:
+		return "Unknown exit code"
+	}
+}
+
+// Error associates an exit code with an underlying error.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Code.Description()
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New wraps err with the given code. Returns nil if err is nil.
+func New(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf formats an error message and wraps it with the given code.
+func Errorf(code Code, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// FromError returns the exit code carried by err (the outermost *Error in its
+// chain). Nil maps to OK and unclassified errors map to Failure.
+func FromError(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return Failure
+}
+
+// Is reports whether err carries the given code.
+func Is(err error, code Code) bool {
+	return err != nil && FromError(err) == code
+}
@@ -0,0 +1,76 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCodeValuesAreStable(t *testing.T) {
+	tests := []struct {
+		code Code
+		want int
+		name string
+	}{
+		{OK, 0, "ok"},
+		{Failure, 1, "failure"},
+		{Partial, 2, "partial"},
+		{PolicyViolation, 3, "policy-violation"},
+		{ConfigError, 4, "config-error"},
+		{ProviderError, 5, "provider-error"},
+	}
+	for _, tt := range tests {
+		if int(tt.code) != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, int(tt.code), tt.want)
+		}
+		if tt.code.String() != tt.name {
+			t.Errorf("Code(%d).String() = %q, want %q", tt.want, tt.code.String(), tt.name)
+		}
+		if tt.code.Description() == "Unknown exit code" {
+			t.Errorf("Code(%d) missing description", tt.want)
+		}
+	}
+	if len(All()) != len(tests) {
+		t.Errorf("All() returned %d codes, want %d", len(All()), len(tests))
+	}
+}
+
+func TestFromError(t *testing.T) {
+	base := errors.New("boom")
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, OK},
+		{"plain error", base, Failure},
+		{"coded", New(ConfigError, base), ConfigError},
+		{"wrapped coded", fmt.Errorf("context: %w", New(ProviderError, base)), ProviderError},
+		{"outermost wins", New(Partial, New(ProviderError, base)), Partial},
+		{"errorf", Errorf(PolicyViolation, "%d violations", 3), PolicyViolation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromError(tt.err); got != tt.want {
+				t.Errorf("FromError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorPreservesMessageAndChain(t *testing.T) {
+	base := errors.New("bad token")
+	err := New(ProviderError, base)
+	if err.Error() != "bad token" {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Error("expected wrapped error to be reachable via errors.Is")
+	}
+	if !Is(err, ProviderError) || Is(err, ConfigError) {
+		t.Error("Is() returned wrong classification")
+	}
+	if New(ConfigError, nil) != nil {
+		t.Error("New with nil error should return nil")
+	}
+}
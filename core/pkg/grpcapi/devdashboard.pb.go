@@ -0,0 +1,982 @@
+// gRPC API of the DevDashboard dependency service.
+//
+// Regenerate the Go code after editing (from core/):
+//   make proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: devdashboard.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RunReportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Repository keys (provider:owner/repo@ref) or owner/repo identifiers to
+	// analyze; empty analyzes every configured repository.
+	Repositories []string `protobuf:"bytes,1,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	// Only analyze repositories carrying any of these tags.
+	Tags          []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunReportRequest) Reset() {
+	*x = RunReportRequest{}
+	mi := &file_devdashboard_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunReportRequest) ProtoMessage() {}
+
+func (x *RunReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunReportRequest.ProtoReflect.Descriptor instead.
+func (*RunReportRequest) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RunReportRequest) GetRepositories() []string {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+func (x *RunReportRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// RunReportResponse is one message of the RunReport stream: progress events,
+// then exactly one report.
+type RunReportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*RunReportResponse_Progress
+	//	*RunReportResponse_Report
+	Event         isRunReportResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunReportResponse) Reset() {
+	*x = RunReportResponse{}
+	mi := &file_devdashboard_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunReportResponse) ProtoMessage() {}
+
+func (x *RunReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunReportResponse.ProtoReflect.Descriptor instead.
+func (*RunReportResponse) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RunReportResponse) GetEvent() isRunReportResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *RunReportResponse) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Event.(*RunReportResponse_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *RunReportResponse) GetReport() *Report {
+	if x != nil {
+		if x, ok := x.Event.(*RunReportResponse_Report); ok {
+			return x.Report
+		}
+	}
+	return nil
+}
+
+type isRunReportResponse_Event interface {
+	isRunReportResponse_Event()
+}
+
+type RunReportResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type RunReportResponse_Report struct {
+	Report *Report `protobuf:"bytes,2,opt,name=report,proto3,oneof"`
+}
+
+func (*RunReportResponse_Progress) isRunReportResponse_Event() {}
+
+func (*RunReportResponse_Report) isRunReportResponse_Event() {}
+
+// Progress is a repository (or, with an empty repository, aggregate)
+// lifecycle event.
+type Progress struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Repository string                 `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// queued, running, complete, error, aggregate or retry.
+	Phase string `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// Failed attempt number of a retry.
+	Attempt       int32                  `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_devdashboard_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Progress) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *Progress) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *Progress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Progress) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Progress) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type Report struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	GeneratedAt *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	// Tracked packages, sorted.
+	Packages      []string            `protobuf:"bytes,2,rep,name=packages,proto3" json:"packages,omitempty"`
+	Repositories  []*RepositoryReport `protobuf:"bytes,3,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Report) Reset() {
+	*x = Report{}
+	mi := &file_devdashboard_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Report) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Report) ProtoMessage() {}
+
+func (x *Report) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Report.ProtoReflect.Descriptor instead.
+func (*Report) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Report) GetGeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return nil
+}
+
+func (x *Report) GetPackages() []string {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+func (x *Report) GetRepositories() []*RepositoryReport {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+type RepositoryReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// provider:owner/repo@ref
+	Key        string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Provider   string   `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Owner      string   `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Repository string   `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
+	Ref        string   `protobuf:"bytes,5,opt,name=ref,proto3" json:"ref,omitempty"`
+	Analyzer   string   `protobuf:"bytes,6,opt,name=analyzer,proto3" json:"analyzer,omitempty"`
+	Ecosystem  string   `protobuf:"bytes,7,opt,name=ecosystem,proto3" json:"ecosystem,omitempty"`
+	CommitSha  string   `protobuf:"bytes,8,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	Tags       []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Package name to locked version.
+	Dependencies map[string]string `protobuf:"bytes,10,rep,name=dependencies,proto3" json:"dependencies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Package name to declared constraint.
+	Constraints map[string]string `protobuf:"bytes,11,rep,name=constraints,proto3" json:"constraints,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Violations  []*Violation      `protobuf:"bytes,12,rep,name=violations,proto3" json:"violations,omitempty"`
+	// Analysis error; the other results are empty when set.
+	Error string `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	// Results reused from the previous run's snapshot.
+	Cached        bool `protobuf:"varint,14,opt,name=cached,proto3" json:"cached,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RepositoryReport) Reset() {
+	*x = RepositoryReport{}
+	mi := &file_devdashboard_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RepositoryReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepositoryReport) ProtoMessage() {}
+
+func (x *RepositoryReport) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepositoryReport.ProtoReflect.Descriptor instead.
+func (*RepositoryReport) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RepositoryReport) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetAnalyzer() string {
+	if x != nil {
+		return x.Analyzer
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetEcosystem() string {
+	if x != nil {
+		return x.Ecosystem
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *RepositoryReport) GetDependencies() map[string]string {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *RepositoryReport) GetConstraints() map[string]string {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *RepositoryReport) GetViolations() []*Violation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *RepositoryReport) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RepositoryReport) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+type Violation struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Policy string                 `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	// error or warning.
+	Severity string `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	Package  string `protobuf:"bytes,3,opt,name=package,proto3" json:"package,omitempty"`
+	Version  string `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Message  string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// Accepted by an ignore rule.
+	Suppressed    bool `protobuf:"varint,6,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_devdashboard_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Violation) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *Violation) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Violation) GetPackage() string {
+	if x != nil {
+		return x.Package
+	}
+	return ""
+}
+
+func (x *Violation) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Violation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Violation) GetSuppressed() bool {
+	if x != nil {
+		return x.Suppressed
+	}
+	return false
+}
+
+type GetLatestReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestReportRequest) Reset() {
+	*x = GetLatestReportRequest{}
+	mi := &file_devdashboard_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestReportRequest) ProtoMessage() {}
+
+func (x *GetLatestReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestReportRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestReportRequest) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{6}
+}
+
+type GetLatestReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *Report                `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestReportResponse) Reset() {
+	*x = GetLatestReportResponse{}
+	mi := &file_devdashboard_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestReportResponse) ProtoMessage() {}
+
+func (x *GetLatestReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestReportResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestReportResponse) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLatestReportResponse) GetReport() *Report {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type ListRepositoriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only list repositories carrying any of these tags.
+	Tags          []string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRepositoriesRequest) Reset() {
+	*x = ListRepositoriesRequest{}
+	mi := &file_devdashboard_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRepositoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRepositoriesRequest) ProtoMessage() {}
+
+func (x *ListRepositoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRepositoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListRepositoriesRequest) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListRepositoriesRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type ListRepositoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Repositories  []*Repository          `protobuf:"bytes,1,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRepositoriesResponse) Reset() {
+	*x = ListRepositoriesResponse{}
+	mi := &file_devdashboard_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRepositoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRepositoriesResponse) ProtoMessage() {}
+
+func (x *ListRepositoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRepositoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListRepositoriesResponse) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListRepositoriesResponse) GetRepositories() []*Repository {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+type Repository struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Key        string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Provider   string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Owner      string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Repository string                 `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
+	Ref        string                 `protobuf:"bytes,5,opt,name=ref,proto3" json:"ref,omitempty"`
+	Analyzer   string                 `protobuf:"bytes,6,opt,name=analyzer,proto3" json:"analyzer,omitempty"`
+	Tags       []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Tracked packages.
+	Packages      []string `protobuf:"bytes,8,rep,name=packages,proto3" json:"packages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Repository) Reset() {
+	*x = Repository{}
+	mi := &file_devdashboard_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Repository) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Repository) ProtoMessage() {}
+
+func (x *Repository) ProtoReflect() protoreflect.Message {
+	mi := &file_devdashboard_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Repository.ProtoReflect.Descriptor instead.
+func (*Repository) Descriptor() ([]byte, []int) {
+	return file_devdashboard_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Repository) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Repository) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Repository) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Repository) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *Repository) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *Repository) GetAnalyzer() string {
+	if x != nil {
+		return x.Analyzer
+	}
+	return ""
+}
+
+func (x *Repository) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Repository) GetPackages() []string {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+var File_devdashboard_proto protoreflect.FileDescriptor
+
+const file_devdashboard_proto_rawDesc = "" +
+	"\n" +
+	"\x12devdashboard.proto\x12\x0fdevdashboard.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"J\n" +
+	"\x10RunReportRequest\x12\"\n" +
+	"\frepositories\x18\x01 \x03(\tR\frepositories\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\"\x88\x01\n" +
+	"\x11RunReportResponse\x127\n" +
+	"\bprogress\x18\x01 \x01(\v2\x19.devdashboard.v1.ProgressH\x00R\bprogress\x121\n" +
+	"\x06report\x18\x02 \x01(\v2\x17.devdashboard.v1.ReportH\x00R\x06reportB\a\n" +
+	"\x05event\"\xa0\x01\n" +
+	"\bProgress\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x01 \x01(\tR\n" +
+	"repository\x12\x14\n" +
+	"\x05phase\x18\x02 \x01(\tR\x05phase\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x18\n" +
+	"\aattempt\x18\x04 \x01(\x05R\aattempt\x12.\n" +
+	"\x04time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\xaa\x01\n" +
+	"\x06Report\x12=\n" +
+	"\fgenerated_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vgeneratedAt\x12\x1a\n" +
+	"\bpackages\x18\x02 \x03(\tR\bpackages\x12E\n" +
+	"\frepositories\x18\x03 \x03(\v2!.devdashboard.v1.RepositoryReportR\frepositories\"\x8f\x05\n" +
+	"\x10RepositoryReport\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x04 \x01(\tR\n" +
+	"repository\x12\x10\n" +
+	"\x03ref\x18\x05 \x01(\tR\x03ref\x12\x1a\n" +
+	"\banalyzer\x18\x06 \x01(\tR\banalyzer\x12\x1c\n" +
+	"\tecosystem\x18\a \x01(\tR\tecosystem\x12\x1d\n" +
+	"\n" +
+	"commit_sha\x18\b \x01(\tR\tcommitSha\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x12W\n" +
+	"\fdependencies\x18\n" +
+	" \x03(\v23.devdashboard.v1.RepositoryReport.DependenciesEntryR\fdependencies\x12T\n" +
+	"\vconstraints\x18\v \x03(\v22.devdashboard.v1.RepositoryReport.ConstraintsEntryR\vconstraints\x12:\n" +
+	"\n" +
+	"violations\x18\f \x03(\v2\x1a.devdashboard.v1.ViolationR\n" +
+	"violations\x12\x14\n" +
+	"\x05error\x18\r \x01(\tR\x05error\x12\x16\n" +
+	"\x06cached\x18\x0e \x01(\bR\x06cached\x1a?\n" +
+	"\x11DependenciesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
+	"\x10ConstraintsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xad\x01\n" +
+	"\tViolation\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x18\n" +
+	"\apackage\x18\x03 \x01(\tR\apackage\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\x06 \x01(\bR\n" +
+	"suppressed\"\x18\n" +
+	"\x16GetLatestReportRequest\"J\n" +
+	"\x17GetLatestReportResponse\x12/\n" +
+	"\x06report\x18\x01 \x01(\v2\x17.devdashboard.v1.ReportR\x06report\"-\n" +
+	"\x17ListRepositoriesRequest\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\"[\n" +
+	"\x18ListRepositoriesResponse\x12?\n" +
+	"\frepositories\x18\x01 \x03(\v2\x1b.devdashboard.v1.RepositoryR\frepositories\"\xce\x01\n" +
+	"\n" +
+	"Repository\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x04 \x01(\tR\n" +
+	"repository\x12\x10\n" +
+	"\x03ref\x18\x05 \x01(\tR\x03ref\x12\x1a\n" +
+	"\banalyzer\x18\x06 \x01(\tR\banalyzer\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x1a\n" +
+	"\bpackages\x18\b \x03(\tR\bpackages2\xb8\x02\n" +
+	"\x11DependencyService\x12T\n" +
+	"\tRunReport\x12!.devdashboard.v1.RunReportRequest\x1a\".devdashboard.v1.RunReportResponse0\x01\x12d\n" +
+	"\x0fGetLatestReport\x12'.devdashboard.v1.GetLatestReportRequest\x1a(.devdashboard.v1.GetLatestReportResponse\x12g\n" +
+	"\x10ListRepositories\x12(.devdashboard.v1.ListRepositoriesRequest\x1a).devdashboard.v1.ListRepositoriesResponseB8Z6github.com/greg-hellings/devdashboard/core/pkg/grpcapib\x06proto3"
+
+var (
+	file_devdashboard_proto_rawDescOnce sync.Once
+	file_devdashboard_proto_rawDescData []byte
+)
+
+func file_devdashboard_proto_rawDescGZIP() []byte {
+	file_devdashboard_proto_rawDescOnce.Do(func() {
+		file_devdashboard_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_devdashboard_proto_rawDesc), len(file_devdashboard_proto_rawDesc)))
+	})
+	return file_devdashboard_proto_rawDescData
+}
+
+var file_devdashboard_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_devdashboard_proto_goTypes = []any{
+	(*RunReportRequest)(nil),         // 0: devdashboard.v1.RunReportRequest
+	(*RunReportResponse)(nil),        // 1: devdashboard.v1.RunReportResponse
+	(*Progress)(nil),                 // 2: devdashboard.v1.Progress
+	(*Report)(nil),                   // 3: devdashboard.v1.Report
+	(*RepositoryReport)(nil),         // 4: devdashboard.v1.RepositoryReport
+	(*Violation)(nil),                // 5: devdashboard.v1.Violation
+	(*GetLatestReportRequest)(nil),   // 6: devdashboard.v1.GetLatestReportRequest
+	(*GetLatestReportResponse)(nil),  // 7: devdashboard.v1.GetLatestReportResponse
+	(*ListRepositoriesRequest)(nil),  // 8: devdashboard.v1.ListRepositoriesRequest
+	(*ListRepositoriesResponse)(nil), // 9: devdashboard.v1.ListRepositoriesResponse
+	(*Repository)(nil),               // 10: devdashboard.v1.Repository
+	nil,                              // 11: devdashboard.v1.RepositoryReport.DependenciesEntry
+	nil,                              // 12: devdashboard.v1.RepositoryReport.ConstraintsEntry
+	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
+}
+var file_devdashboard_proto_depIdxs = []int32{
+	2,  // 0: devdashboard.v1.RunReportResponse.progress:type_name -> devdashboard.v1.Progress
+	3,  // 1: devdashboard.v1.RunReportResponse.report:type_name -> devdashboard.v1.Report
+	13, // 2: devdashboard.v1.Progress.time:type_name -> google.protobuf.Timestamp
+	13, // 3: devdashboard.v1.Report.generated_at:type_name -> google.protobuf.Timestamp
+	4,  // 4: devdashboard.v1.Report.repositories:type_name -> devdashboard.v1.RepositoryReport
+	11, // 5: devdashboard.v1.RepositoryReport.dependencies:type_name -> devdashboard.v1.RepositoryReport.DependenciesEntry
+	12, // 6: devdashboard.v1.RepositoryReport.constraints:type_name -> devdashboard.v1.RepositoryReport.ConstraintsEntry
+	5,  // 7: devdashboard.v1.RepositoryReport.violations:type_name -> devdashboard.v1.Violation
+	3,  // 8: devdashboard.v1.GetLatestReportResponse.report:type_name -> devdashboard.v1.Report
+	10, // 9: devdashboard.v1.ListRepositoriesResponse.repositories:type_name -> devdashboard.v1.Repository
+	0,  // 10: devdashboard.v1.DependencyService.RunReport:input_type -> devdashboard.v1.RunReportRequest
+	6,  // 11: devdashboard.v1.DependencyService.GetLatestReport:input_type -> devdashboard.v1.GetLatestReportRequest
+	8,  // 12: devdashboard.v1.DependencyService.ListRepositories:input_type -> devdashboard.v1.ListRepositoriesRequest
+	1,  // 13: devdashboard.v1.DependencyService.RunReport:output_type -> devdashboard.v1.RunReportResponse
+	7,  // 14: devdashboard.v1.DependencyService.GetLatestReport:output_type -> devdashboard.v1.GetLatestReportResponse
+	9,  // 15: devdashboard.v1.DependencyService.ListRepositories:output_type -> devdashboard.v1.ListRepositoriesResponse
+	13, // [13:16] is the sub-list for method output_type
+	10, // [10:13] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_devdashboard_proto_init() }
+func file_devdashboard_proto_init() {
+	if File_devdashboard_proto != nil {
+		return
+	}
+	file_devdashboard_proto_msgTypes[1].OneofWrappers = []any{
+		(*RunReportResponse_Progress)(nil),
+		(*RunReportResponse_Report)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_devdashboard_proto_rawDesc), len(file_devdashboard_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_devdashboard_proto_goTypes,
+		DependencyIndexes: file_devdashboard_proto_depIdxs,
+		MessageInfos:      file_devdashboard_proto_msgTypes,
+	}.Build()
+	File_devdashboard_proto = out.File
+	file_devdashboard_proto_goTypes = nil
+	file_devdashboard_proto_depIdxs = nil
+}
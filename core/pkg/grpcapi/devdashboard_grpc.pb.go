@@ -0,0 +1,220 @@
+// gRPC API of the DevDashboard dependency service.
+//
+// Regenerate the Go code after editing (from core/):
+//   make proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: devdashboard.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DependencyService_RunReport_FullMethodName        = "/devdashboard.v1.DependencyService/RunReport"
+	DependencyService_GetLatestReport_FullMethodName  = "/devdashboard.v1.DependencyService/GetLatestReport"
+	DependencyService_ListRepositories_FullMethodName = "/devdashboard.v1.DependencyService/ListRepositories"
+)
+
+// DependencyServiceClient is the client API for DependencyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DependencyService runs dependency reports over the configured repositories.
+type DependencyServiceClient interface {
+	// RunReport analyzes the configured repositories (or a selection),
+	// streaming progress events and finally the report.
+	RunReport(ctx context.Context, in *RunReportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunReportResponse], error)
+	// GetLatestReport returns the most recent report: the one serve keeps
+	// current, or the last RunReport result.
+	GetLatestReport(ctx context.Context, in *GetLatestReportRequest, opts ...grpc.CallOption) (*GetLatestReportResponse, error)
+	// ListRepositories lists the configured repositories.
+	ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error)
+}
+
+type dependencyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDependencyServiceClient(cc grpc.ClientConnInterface) DependencyServiceClient {
+	return &dependencyServiceClient{cc}
+}
+
+func (c *dependencyServiceClient) RunReport(ctx context.Context, in *RunReportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunReportResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DependencyService_ServiceDesc.Streams[0], DependencyService_RunReport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RunReportRequest, RunReportResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DependencyService_RunReportClient = grpc.ServerStreamingClient[RunReportResponse]
+
+func (c *dependencyServiceClient) GetLatestReport(ctx context.Context, in *GetLatestReportRequest, opts ...grpc.CallOption) (*GetLatestReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLatestReportResponse)
+	err := c.cc.Invoke(ctx, DependencyService_GetLatestReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dependencyServiceClient) ListRepositories(ctx context.Context, in *ListRepositoriesRequest, opts ...grpc.CallOption) (*ListRepositoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRepositoriesResponse)
+	err := c.cc.Invoke(ctx, DependencyService_ListRepositories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DependencyServiceServer is the server API for DependencyService service.
+// All implementations must embed UnimplementedDependencyServiceServer
+// for forward compatibility.
+//
+// DependencyService runs dependency reports over the configured repositories.
+type DependencyServiceServer interface {
+	// RunReport analyzes the configured repositories (or a selection),
+	// streaming progress events and finally the report.
+	RunReport(*RunReportRequest, grpc.ServerStreamingServer[RunReportResponse]) error
+	// GetLatestReport returns the most recent report: the one serve keeps
+	// current, or the last RunReport result.
+	GetLatestReport(context.Context, *GetLatestReportRequest) (*GetLatestReportResponse, error)
+	// ListRepositories lists the configured repositories.
+	ListRepositories(context.Context, *ListRepositoriesRequest) (*ListRepositoriesResponse, error)
+	mustEmbedUnimplementedDependencyServiceServer()
+}
+
+// UnimplementedDependencyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDependencyServiceServer struct{}
+
+func (UnimplementedDependencyServiceServer) RunReport(*RunReportRequest, grpc.ServerStreamingServer[RunReportResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method RunReport not implemented")
+}
+func (UnimplementedDependencyServiceServer) GetLatestReport(context.Context, *GetLatestReportRequest) (*GetLatestReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestReport not implemented")
+}
+func (UnimplementedDependencyServiceServer) ListRepositories(context.Context, *ListRepositoriesRequest) (*ListRepositoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRepositories not implemented")
+}
+func (UnimplementedDependencyServiceServer) mustEmbedUnimplementedDependencyServiceServer() {}
+func (UnimplementedDependencyServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeDependencyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DependencyServiceServer will
+// result in compilation errors.
+type UnsafeDependencyServiceServer interface {
+	mustEmbedUnimplementedDependencyServiceServer()
+}
+
+func RegisterDependencyServiceServer(s grpc.ServiceRegistrar, srv DependencyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDependencyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DependencyService_ServiceDesc, srv)
+}
+
+func _DependencyService_RunReport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunReportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DependencyServiceServer).RunReport(m, &grpc.GenericServerStream[RunReportRequest, RunReportResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DependencyService_RunReportServer = grpc.ServerStreamingServer[RunReportResponse]
+
+func _DependencyService_GetLatestReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DependencyServiceServer).GetLatestReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DependencyService_GetLatestReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DependencyServiceServer).GetLatestReport(ctx, req.(*GetLatestReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DependencyService_ListRepositories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRepositoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DependencyServiceServer).ListRepositories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DependencyService_ListRepositories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DependencyServiceServer).ListRepositories(ctx, req.(*ListRepositoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DependencyService_ServiceDesc is the grpc.ServiceDesc for DependencyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DependencyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "devdashboard.v1.DependencyService",
+	HandlerType: (*DependencyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLatestReport",
+			Handler:    _DependencyService_GetLatestReport_Handler,
+		},
+		{
+			MethodName: "ListRepositories",
+			Handler:    _DependencyService_ListRepositories_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunReport",
+			Handler:       _DependencyService_RunReport_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "devdashboard.proto",
+}
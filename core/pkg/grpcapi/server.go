@@ -0,0 +1,244 @@
+// Package grpcapi serves the dependency service over gRPC. The protocol is
+// defined in devdashboard.proto; devdashboard.pb.go and
+// devdashboard_grpc.pb.go are generated from it (make proto).
+package grpcapi
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
+)
+
+// Options configures a Server
+type Options struct {
+	// Report is passed to every RunReport run
+	Report services.ReportOptions
+
+	// Timeout bounds each RunReport run; zero leaves it limited only by the
+	// client's deadline
+	Timeout time.Duration
+
+	// Latest returns the report GetLatestReport serves (e.g. the one serve
+	// keeps current), nil before there is one. When unset, GetLatestReport
+	// serves the last successful RunReport result.
+	Latest func() *report.Report
+}
+
+// Server implements DependencyServiceServer on top of a
+// services.DependencyService
+type Server struct {
+	UnimplementedDependencyServiceServer
+
+	svc   services.DependencyService
+	repos []config.RepoWithProvider
+	opts  Options
+
+	// running holds a token while a RunReport run uses the service, whose
+	// generator analyzes one report at a time
+	running chan struct{}
+
+	mu   sync.RWMutex
+	last *report.Report
+}
+
+// NewServer creates a Server running reports over repos with svc
+func NewServer(svc services.DependencyService, repos []config.RepoWithProvider, opts Options) *Server {
+	return &Server{svc: svc, repos: repos, opts: opts, running: make(chan struct{}, 1)}
+}
+
+// Register registers s with a gRPC server
+func (s *Server) Register(gs *grpc.Server) {
+	RegisterDependencyServiceServer(gs, s)
+}
+
+// RunReport analyzes the selected repositories, streaming progress and then
+// the report. A run waits for the previous one to finish.
+func (s *Server) RunReport(req *RunReportRequest, stream grpc.ServerStreamingServer[RunReportResponse]) error {
+	repos, err := s.selectRepos(req.GetRepositories(), req.GetTags())
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	select {
+	case s.running <- struct{}{}:
+		defer func() { <-s.running }()
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	if s.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.Timeout)
+		defer cancel()
+	}
+	progressCh, handle, err := s.svc.RunReport(ctx, repos, s.opts.Report)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	var sendErr error
+	for p := range progressCh {
+		if sendErr == nil {
+			sendErr = stream.Send(&RunReportResponse{Event: &RunReportResponse_Progress{Progress: progressToProto(p)}})
+		}
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+	rpt, err := handle.Result()
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	s.mu.Lock()
+	s.last = rpt
+	s.mu.Unlock()
+	return stream.Send(&RunReportResponse{Event: &RunReportResponse_Report{Report: reportToProto(rpt)}})
+}
+
+// GetLatestReport returns the latest report, or NotFound before there is one
+func (s *Server) GetLatestReport(context.Context, *GetLatestReportRequest) (*GetLatestReportResponse, error) {
+	var rpt *report.Report
+	if s.opts.Latest != nil {
+		rpt = s.opts.Latest()
+	} else {
+		s.mu.RLock()
+		rpt = s.last
+		s.mu.RUnlock()
+	}
+	if rpt == nil {
+		return nil, status.Error(codes.NotFound, "report not generated yet")
+	}
+	return &GetLatestReportResponse{Report: reportToProto(rpt)}, nil
+}
+
+// ListRepositories lists the configured repositories, sorted by key
+func (s *Server) ListRepositories(_ context.Context, req *ListRepositoriesRequest) (*ListRepositoriesResponse, error) {
+	repos := s.repos
+	if len(req.GetTags()) > 0 {
+		repos = config.FilterTags(repos, req.GetTags())
+	}
+	resp := &ListRepositoriesResponse{Repositories: make([]*Repository, 0, len(repos))}
+	for _, r := range repos {
+		resp.Repositories = append(resp.Repositories, &Repository{
+			Key:        repoKey(r),
+			Provider:   r.Provider,
+			Owner:      r.Config.Owner,
+			Repository: r.Config.Repository,
+			Ref:        r.Config.Ref,
+			Analyzer:   r.Config.Analyzer,
+			Tags:       r.Config.Tags,
+			Packages:   r.Config.Packages,
+		})
+	}
+	slices.SortFunc(resp.Repositories, func(a, b *Repository) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return resp, nil
+}
+
+// selectRepos returns the configured repositories matching keys (full keys
+// or owner/repo identifiers; all when empty) and carrying any of tags
+func (s *Server) selectRepos(keys, tags []string) ([]config.RepoWithProvider, error) {
+	repos := s.repos
+	if len(keys) > 0 {
+		repos = nil
+		for _, key := range keys {
+			matched := false
+			for _, r := range s.repos {
+				if key == repoKey(r) || key == r.Config.Owner+"/"+r.Config.Repository {
+					repos = append(repos, r)
+					matched = true
+				}
+			}
+			if !matched {
+				return nil, status.Errorf(codes.InvalidArgument, "unknown repository %q", key)
+			}
+		}
+	}
+	if len(tags) > 0 {
+		repos = config.FilterTags(repos, tags)
+	}
+	if len(repos) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no repositories selected")
+	}
+	return repos, nil
+}
+
+// repoKey is the report key (provider:owner/repo@ref) of a configured
+// repository
+func repoKey(r config.RepoWithProvider) string {
+	rr := report.RepositoryReport{Provider: r.Provider, Owner: r.Config.Owner, Repository: r.Config.Repository, Ref: r.Config.Ref}
+	return rr.Key()
+}
+
+// progressToProto converts a service progress event
+func progressToProto(p services.ReportProgress) *Progress {
+	out := &Progress{
+		Repository: p.RepoID,
+		Phase:      string(p.Phase),
+		Attempt:    int32(p.Attempt),
+		Time:       timestamppb.New(p.Timestamp),
+	}
+	if p.Error != nil {
+		out.Error = p.Error.Error()
+	}
+	return out
+}
+
+// reportToProto converts a report
+func reportToProto(rpt *report.Report) *Report {
+	out := &Report{
+		Packages:     slices.Sorted(slices.Values(rpt.Packages)),
+		Repositories: make([]*RepositoryReport, 0, len(rpt.Repositories)),
+	}
+	if s := rpt.Snapshot(); s != nil {
+		out.GeneratedAt = timestamppb.New(s.GeneratedAt)
+	}
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		pr := &RepositoryReport{
+			Key:          rr.Key(),
+			Provider:     rr.Provider,
+			Owner:        rr.Owner,
+			Repository:   rr.Repository,
+			Ref:          rr.Ref,
+			Analyzer:     rr.Analyzer,
+			Ecosystem:    string(rr.Ecosystem),
+			CommitSha:    rr.CommitSHA,
+			Tags:         rr.Tags,
+			Dependencies: maps.Clone(rr.Dependencies),
+			Constraints:  maps.Clone(rr.Constraints),
+			Cached:       rr.Cached,
+		}
+		if rr.Error != nil {
+			pr.Error = rr.Error.Error()
+		}
+		for _, v := range rr.Violations {
+			pr.Violations = append(pr.Violations, &Violation{
+				Policy:     v.Policy,
+				Severity:   v.Severity,
+				Package:    v.Package,
+				Version:    v.Version,
+				Message:    v.Message,
+				Suppressed: v.Suppressed(),
+			})
+		}
+		out.Repositories = append(out.Repositories, pr)
+	}
+	return out
+}
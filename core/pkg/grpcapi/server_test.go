@@ -0,0 +1,142 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/services"
+)
+
+// testRepos use an unsupported provider, so every run fails fast without
+// network access and still yields a report
+var testRepos = []config.RepoWithProvider{
+	{Provider: "bogus", Config: config.RepoConfig{Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry", Tags: []string{"prod"}, Packages: []string{"django"}}},
+	{Provider: "bogus", Config: config.RepoConfig{Owner: "o", Repository: "web", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}}},
+}
+
+// dial serves s over an in-memory connection and returns a client for it
+func dial(t *testing.T, s *Server) DependencyServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	s.Register(gs)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewDependencyServiceClient(conn)
+}
+
+func TestRunReport_StreamsProgressThenReport(t *testing.T) {
+	client := dial(t, NewServer(services.NewDependencyService(nil), testRepos, Options{}))
+	ctx := context.Background()
+
+	if _, err := client.GetLatestReport(ctx, &GetLatestReportRequest{}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetLatestReport before a run = %v, want NotFound", err)
+	}
+
+	stream, err := client.RunReport(ctx, &RunReportRequest{Repositories: []string{"o/api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var phases []string
+	var rpt *Report
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := msg.GetProgress(); p != nil {
+			phases = append(phases, p.GetPhase())
+			continue
+		}
+		if rpt != nil {
+			t.Fatal("expected a single report message")
+		}
+		rpt = msg.GetReport()
+	}
+	if len(phases) == 0 || phases[0] != string(services.PhaseQueued) {
+		t.Errorf("progress phases = %v, want queued first", phases)
+	}
+	if rpt == nil || len(rpt.GetRepositories()) != 1 {
+		t.Fatalf("report = %v, want one repository", rpt)
+	}
+	if rr := rpt.GetRepositories()[0]; rr.GetKey() != "bogus:o/api@main" || rr.GetError() == "" {
+		t.Errorf("repository = %v, want o/api with its provider error", rr)
+	}
+
+	latest, err := client.GetLatestReport(ctx, &GetLatestReportRequest{})
+	if err != nil || latest.GetReport().GetRepositories()[0].GetKey() != "bogus:o/api@main" {
+		t.Errorf("GetLatestReport = %v, %v; want the run's report", latest, err)
+	}
+}
+
+func TestRunReport_InvalidSelection(t *testing.T) {
+	client := dial(t, NewServer(services.NewDependencyService(nil), testRepos, Options{}))
+	for _, req := range []*RunReportRequest{
+		{Repositories: []string{"o/missing"}},
+		{Tags: []string{"staging"}},
+	} {
+		stream, err := client.RunReport(context.Background(), req)
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("RunReport(%v) error = %v, want InvalidArgument", req, err)
+		}
+	}
+}
+
+func TestGetLatestReport_FromOptions(t *testing.T) {
+	served := &report.Report{
+		Packages:     []string{"requests", "django"},
+		Repositories: []report.RepositoryReport{{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Dependencies: map[string]string{"django": "4.2.0"}}},
+	}
+	client := dial(t, NewServer(services.NewDependencyService(nil), testRepos, Options{Latest: func() *report.Report { return served }}))
+	resp, err := client.GetLatestReport(context.Background(), &GetLatestReportRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpt := resp.GetReport()
+	if got := rpt.GetPackages(); len(got) != 2 || got[0] != "django" {
+		t.Errorf("packages = %v, want sorted", got)
+	}
+	if rr := rpt.GetRepositories()[0]; rr.GetDependencies()["django"] != "4.2.0" {
+		t.Errorf("repository = %v", rr)
+	}
+}
+
+func TestListRepositories(t *testing.T) {
+	client := dial(t, NewServer(services.NewDependencyService(nil), []config.RepoWithProvider{testRepos[1], testRepos[0]}, Options{}))
+	resp, err := client.ListRepositories(context.Background(), &ListRepositoriesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repos := resp.GetRepositories(); len(repos) != 2 || repos[0].GetKey() != "bogus:o/api@main" {
+		t.Errorf("repositories = %v, want sorted by key", repos)
+	}
+	resp, err = client.ListRepositories(context.Background(), &ListRepositoriesRequest{Tags: []string{"prod"}})
+	if err != nil || len(resp.GetRepositories()) != 1 || resp.GetRepositories()[0].GetPackages()[0] != "django" {
+		t.Errorf("tagged repositories = %v, %v", resp, err)
+	}
+}
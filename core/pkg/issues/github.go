@@ -0,0 +1,132 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"golang.org/x/oauth2"
+)
+
+// GitHubIssuesService abstracts the issue operations GitHubTracker uses;
+// *github.IssuesService implements it.
+type GitHubIssuesService interface {
+	ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	Create(ctx context.Context, owner, repo string, req *github.IssueRequest) (*github.Issue, *github.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, req *github.IssueRequest) (*github.Issue, *github.Response, error)
+}
+
+// GitHubTracker files GitHub issues, either in the violating repository or
+// in one configured repository
+type GitHubTracker struct {
+	api        GitHubIssuesService
+	repository string   // owner/repo receiving every issue; empty = the violating repository
+	labels     []string // Added to created issues besides ManagedLabel
+}
+
+// NewGitHubTracker creates a tracker authenticated with token (public
+// repositories only when empty). BaseURL selects a GitHub Enterprise server.
+func NewGitHubTracker(c config.IssueTrackerConfig, token string) (*GitHubTracker, error) {
+	client := github.NewClient(nil)
+	if token != "" {
+		client = github.NewClient(oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+	}
+	if c.BaseURL != "" {
+		var err error
+		if client, err = client.WithEnterpriseURLs(c.BaseURL, c.BaseURL); err != nil {
+			return nil, fmt.Errorf("failed to set GitHub Enterprise URL: %w", err)
+		}
+	}
+	return &GitHubTracker{api: client.Issues, repository: c.Repository, labels: c.Labels}, nil
+}
+
+// Project returns the configured repository, or the violating repository
+// when it is hosted on GitHub
+func (g *GitHubTracker) Project(rr *report.RepositoryReport) string {
+	if g.repository != "" {
+		return g.repository
+	}
+	if rr.Provider != "github" {
+		return ""
+	}
+	return rr.Owner + "/" + rr.Repository
+}
+
+// ListOpen returns the open issues labeled ManagedLabel that carry a marker
+func (g *GitHubTracker) ListOpen(ctx context.Context, project string) ([]Ticket, error) {
+	owner, repo, _ := strings.Cut(project, "/")
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		Labels:      []string{ManagedLabel},
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var out []Ticket
+	for {
+		list, resp, err := g.api.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues from GitHub: %w", err)
+		}
+		for _, issue := range list {
+			if issue.IsPullRequest() {
+				continue
+			}
+			fp, repository, ok := parseMarker(issue.GetBody())
+			if !ok {
+				continue
+			}
+			out = append(out, Ticket{
+				ID:          strconv.Itoa(issue.GetNumber()),
+				Project:     project,
+				Fingerprint: fp,
+				Repository:  repository,
+				Title:       issue.GetTitle(),
+				Body:        issue.GetBody(),
+				URL:         issue.GetHTMLURL(),
+			})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// Create opens an issue labeled ManagedLabel and the configured labels
+func (g *GitHubTracker) Create(ctx context.Context, t Ticket) (Ticket, error) {
+	owner, repo, _ := strings.Cut(t.Project, "/")
+	labels := append([]string{ManagedLabel}, g.labels...)
+	issue, _, err := g.api.Create(ctx, owner, repo, &github.IssueRequest{Title: &t.Title, Body: &t.Body, Labels: &labels})
+	if err != nil {
+		return t, fmt.Errorf("failed to create GitHub issue: %w", err)
+	}
+	t.ID = strconv.Itoa(issue.GetNumber())
+	t.URL = issue.GetHTMLURL()
+	return t, nil
+}
+
+// Update replaces the issue's title and body
+func (g *GitHubTracker) Update(ctx context.Context, t Ticket) error {
+	return g.edit(ctx, t, &github.IssueRequest{Title: &t.Title, Body: &t.Body})
+}
+
+// Close closes the issue as completed
+func (g *GitHubTracker) Close(ctx context.Context, t Ticket) error {
+	return g.edit(ctx, t, &github.IssueRequest{State: github.String("closed"), StateReason: github.String("completed")})
+}
+
+// edit applies req to the issue numbered t.ID
+func (g *GitHubTracker) edit(ctx context.Context, t Ticket, req *github.IssueRequest) error {
+	number, err := strconv.Atoi(t.ID)
+	if err != nil {
+		return fmt.Errorf("invalid GitHub issue number %q", t.ID)
+	}
+	owner, repo, _ := strings.Cut(t.Project, "/")
+	if _, _, err := g.api.Edit(ctx, owner, repo, number, req); err != nil {
+		return fmt.Errorf("failed to edit GitHub issue #%d: %w", number, err)
+	}
+	return nil
+}
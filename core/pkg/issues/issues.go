@@ -0,0 +1,274 @@
+// Package issues keeps issue tracker tickets (GitHub issues, Jira) in step
+// with policy violations: one ticket per repository, package and violated
+// policy, opened when the violation appears, updated when its details change
+// and closed once it is fixed. Tickets carry a fingerprint of that triple, so
+// repeated runs find and reuse them instead of filing duplicates.
+package issues
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// ManagedLabel marks the tickets Sync owns; others are never touched
+const ManagedLabel = "devdashboard"
+
+// Ticket is an open issue or Jira ticket filed by Sync
+type Ticket struct {
+	ID          string // Issue number (GitHub) or issue key (Jira)
+	Project     string // owner/repo (GitHub) or project key (Jira)
+	Fingerprint string // Identifies the repository, package and policy (see fingerprint)
+	Repository  string // RepositoryReport.Key the ticket is about
+	Title       string
+	Body        string
+	URL         string
+}
+
+// Tracker stores tickets in an issue tracker
+type Tracker interface {
+	// Project returns where tickets about rr are filed, or "" when this
+	// tracker does not cover the repository
+	Project(rr *report.RepositoryReport) string
+
+	// ListOpen returns the open tickets in project carrying ManagedLabel,
+	// with Fingerprint and Repository parsed from their body (see marker)
+	ListOpen(ctx context.Context, project string) ([]Ticket, error)
+
+	// Create files t and returns it with ID and URL set
+	Create(ctx context.Context, t Ticket) (Ticket, error)
+
+	// Update replaces the title and body of the ticket with t's
+	Update(ctx context.Context, t Ticket) error
+
+	// Close resolves the ticket
+	Close(ctx context.Context, t Ticket) error
+}
+
+// Result counts the changes one Sync made
+type Result struct {
+	Created int
+	Updated int
+	Closed  int
+}
+
+// Syncer files tickets for a report's violations in one tracker
+type Syncer struct {
+	Name        string
+	Tracker     Tracker
+	Tags        []string // Only repositories carrying any of these tags; empty means all
+	MinSeverity string   // report.SeverityError skips warnings
+}
+
+// FromConfig builds syncers from configuration entries. Tokens fall back to
+// the github provider's default token for GitHub trackers. Failures are
+// configuration errors.
+func FromConfig(cfg *config.Config) ([]*Syncer, error) {
+	syncers := make([]*Syncer, 0, len(cfg.Issues))
+	for _, c := range cfg.Issues {
+		token := c.Token
+		if token == "" && c.TokenEnv != "" {
+			if token = os.Getenv(c.TokenEnv); token == "" {
+				return nil, exitcode.Errorf(exitcode.ConfigError, "tracker %s: environment variable %s is not set", c.Name, c.TokenEnv)
+			}
+		}
+		var tracker Tracker
+		switch c.Type {
+		case config.TrackerGitHub:
+			if token == "" {
+				token = cfg.Providers["github"].Default.Token
+			}
+			gh, err := NewGitHubTracker(c, token)
+			if err != nil {
+				return nil, exitcode.Errorf(exitcode.ConfigError, "tracker %s: %w", c.Name, err)
+			}
+			tracker = gh
+		case config.TrackerJira:
+			tracker = NewJiraTracker(c, token)
+		default:
+			return nil, exitcode.Errorf(exitcode.ConfigError, "tracker %s: unknown type %q", c.Name, c.Type)
+		}
+		syncers = append(syncers, &Syncer{Name: c.Name, Tracker: tracker, Tags: c.Tags, MinSeverity: c.MinSeverity})
+	}
+	return syncers, nil
+}
+
+// group collects the violations one ticket is about
+type group struct {
+	ticket     Ticket
+	severity   string
+	violations []report.Violation
+}
+
+// Sync brings the tracker in line with rpt: it files a ticket for each new
+// (repository, package, policy) violation, updates tickets whose details
+// changed and closes tickets whose violation is gone. A ticket is only
+// closed when its repository was analyzed successfully in rpt, so failures
+// and narrowed runs never close tickets. Tracker errors for one project are
+// collected and the remaining projects are still synced.
+func (s *Syncer) Sync(ctx context.Context, rpt *report.Report) (Result, error) {
+	var res Result
+	analyzed := make(map[string]bool)
+	groups := make(map[string]map[string]*group) // project -> fingerprint -> group
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		if len(s.Tags) > 0 && !config.HasAnyTag(rr.Tags, s.Tags) {
+			continue
+		}
+		project := s.Tracker.Project(rr)
+		if project == "" || rr.Error != nil {
+			continue
+		}
+		analyzed[rr.Key()] = true
+		if groups[project] == nil {
+			groups[project] = make(map[string]*group)
+		}
+		eco := rr.GetEcosystem()
+		for _, v := range rr.Violations {
+			if v.Suppressed() || (s.MinSeverity == report.SeverityError && v.Severity != report.SeverityError) {
+				continue
+			}
+			fp := fingerprint(rr.Key(), dependencies.NormalizeName(eco, v.Package), v.Policy)
+			g, ok := groups[project][fp]
+			if !ok {
+				g = &group{ticket: Ticket{Project: project, Fingerprint: fp, Repository: rr.Key()}, severity: v.Severity}
+				groups[project][fp] = g
+			}
+			g.violations = append(g.violations, v)
+		}
+	}
+
+	projects := make([]string, 0, len(groups))
+	for project := range groups {
+		projects = append(projects, project)
+	}
+	slices.Sort(projects)
+
+	var errs []error
+	for _, project := range projects {
+		open, err := s.Tracker.ListOpen(ctx, project)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list tickets in %s: %w", project, err))
+			continue
+		}
+		existing := make(map[string]Ticket, len(open))
+		for _, t := range open {
+			if _, dup := existing[t.Fingerprint]; !dup {
+				existing[t.Fingerprint] = t
+			}
+		}
+
+		fps := make([]string, 0, len(groups[project]))
+		for fp := range groups[project] {
+			fps = append(fps, fp)
+		}
+		slices.Sort(fps)
+		for _, fp := range fps {
+			want := groups[project][fp].render()
+			if t, ok := existing[fp]; ok {
+				delete(existing, fp)
+				if t.Title == want.Title && t.Body == want.Body {
+					continue
+				}
+				want.ID, want.URL = t.ID, t.URL
+				if err := s.Tracker.Update(ctx, want); err != nil {
+					errs = append(errs, fmt.Errorf("update ticket %s: %w", t.ID, err))
+					continue
+				}
+				res.Updated++
+				continue
+			}
+			created, err := s.Tracker.Create(ctx, want)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("create ticket for %s: %w", want.Title, err))
+				continue
+			}
+			slog.Info("Opened ticket", "tracker", s.Name, "ticket", created.ID, "url", created.URL)
+			res.Created++
+		}
+
+		for _, t := range open {
+			if _, stale := existing[t.Fingerprint]; !stale || !analyzed[t.Repository] {
+				continue
+			}
+			delete(existing, t.Fingerprint)
+			if err := s.Tracker.Close(ctx, t); err != nil {
+				errs = append(errs, fmt.Errorf("close ticket %s: %w", t.ID, err))
+				continue
+			}
+			slog.Info("Closed ticket", "tracker", s.Name, "ticket", t.ID, "url", t.URL)
+			res.Closed++
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// render fills in the ticket's title and body from the group's violations
+func (g *group) render() Ticket {
+	t := g.ticket
+	v := g.violations[0]
+	_, owner, repo, ref := report.SplitKey(t.Repository)
+	name := owner + "/" + repo
+	if ref != "" {
+		name += "@" + ref
+	}
+	t.Title = fmt.Sprintf("Policy %s: %s in %s", v.Policy, v.Package, name)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Repository %s violates policy %s (%s).\n\n", t.Repository, v.Policy, g.severity)
+	for _, v := range g.violations {
+		fmt.Fprintf(&b, "- %s: %s\n", v.File, v.Message)
+	}
+	b.WriteString("\nThis ticket is managed by devdashboard: it is updated on each run and closed once the violation is fixed.\n\n")
+	b.WriteString(marker(t.Fingerprint, t.Repository))
+	t.Body = b.String()
+	return t
+}
+
+// fingerprint identifies a ticket by repository key, normalized package name
+// and policy
+func fingerprint(repository, pkg, policy string) string {
+	sum := sha256.Sum256([]byte(repository + "\n" + pkg + "\n" + policy))
+	return hex.EncodeToString(sum[:6])
+}
+
+var markerRe = regexp.MustCompile(`devdashboard-ticket: ([0-9a-f]{12}) (\S+)`)
+
+// marker is the last line of every ticket body, read back by parseMarker
+func marker(fp, repository string) string {
+	return fmt.Sprintf("devdashboard-ticket: %s %s", fp, repository)
+}
+
+// parseMarker extracts the fingerprint and repository key from a ticket
+// body; ok is false for tickets Sync did not write
+func parseMarker(body string) (fp, repository string, ok bool) {
+	m := markerRe.FindStringSubmatch(body)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// SyncAll runs every syncer against rpt. Failures are logged and never fail
+// the report run.
+func SyncAll(ctx context.Context, syncers []*Syncer, rpt *report.Report) {
+	for _, s := range syncers {
+		res, err := s.Sync(ctx, rpt)
+		if err != nil {
+			slog.Warn("Issue sync failed", "tracker", s.Name, "error", err)
+		}
+		slog.Info("Issue sync complete", "tracker", s.Name, "created", res.Created, "updated", res.Updated, "closed", res.Closed)
+	}
+}
@@ -0,0 +1,232 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// fakeTracker keeps tickets in memory, one project per repository
+type fakeTracker struct {
+	open    map[string]Ticket // ID -> ticket
+	next    int
+	created []string
+	updated []string
+	closed  []string
+}
+
+func newFakeTracker() *fakeTracker {
+	return &fakeTracker{open: make(map[string]Ticket)}
+}
+
+func (f *fakeTracker) Project(rr *report.RepositoryReport) string {
+	return rr.Owner + "/" + rr.Repository
+}
+
+func (f *fakeTracker) ListOpen(_ context.Context, project string) ([]Ticket, error) {
+	var out []Ticket
+	for _, t := range f.open {
+		if t.Project == project {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTracker) Create(_ context.Context, t Ticket) (Ticket, error) {
+	f.next++
+	t.ID = strconv.Itoa(f.next)
+	f.open[t.ID] = t
+	f.created = append(f.created, t.ID)
+	return t, nil
+}
+
+func (f *fakeTracker) Update(_ context.Context, t Ticket) error {
+	f.open[t.ID] = t
+	f.updated = append(f.updated, t.ID)
+	return nil
+}
+
+func (f *fakeTracker) Close(_ context.Context, t Ticket) error {
+	delete(f.open, t.ID)
+	f.closed = append(f.closed, t.ID)
+	return nil
+}
+
+// find returns the open ticket titled title
+func (f *fakeTracker) find(title string) (Ticket, bool) {
+	for _, t := range f.open {
+		if t.Title == title {
+			return t, true
+		}
+	}
+	return Ticket{}, false
+}
+
+func violating(violations ...report.Violation) *report.Report {
+	return &report.Report{Repositories: []report.RepositoryReport{
+		{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry", Violations: violations},
+		{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Analyzer: "poetry", Tags: []string{"frontend"}},
+	}}
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	tracker := newFakeTracker()
+	s := &Syncer{Name: "test", Tracker: tracker}
+
+	old := report.Violation{Policy: "django-lts", Severity: report.SeverityError, Package: "Django", File: "poetry.lock", Message: "django 3.2 does not satisfy >=4.2"}
+	warn := report.Violation{Policy: "pypi-only", Severity: report.SeverityWarning, Package: "internal", File: "poetry.lock", Message: "internal comes from git"}
+	res, err := s.Sync(ctx, violating(old, warn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (Result{Created: 2}) {
+		t.Fatalf("first sync = %+v, want 2 created", res)
+	}
+	ticket, ok := tracker.find("Policy django-lts: Django in org/api@main")
+	if !ok {
+		t.Fatalf("no django-lts ticket in %+v", tracker.open)
+	}
+	for _, want := range []string{"violates policy django-lts (error)", "- poetry.lock: django 3.2 does not satisfy >=4.2", "devdashboard-ticket: " + ticket.Fingerprint + " github:org/api@main"} {
+		if !strings.Contains(ticket.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, ticket.Body)
+		}
+	}
+
+	// Unchanged violations are deduplicated, even with a differently cased package name
+	renamed := old
+	renamed.Package = "django"
+	if res, err = s.Sync(ctx, violating(renamed, warn)); err != nil || res != (Result{Updated: 1}) {
+		t.Fatalf("repeated sync = %+v, %v; want only the renamed title updated", res, err)
+	}
+	if res, err = s.Sync(ctx, violating(renamed, warn)); err != nil || res != (Result{}) {
+		t.Fatalf("unchanged sync = %+v, %v; want no changes", res, err)
+	}
+
+	// A failed analysis never closes tickets
+	failed := violating()
+	failed.Repositories[0].Error = report.NewErrorDetail(errors.New("rate limited"))
+	if res, err = s.Sync(ctx, failed); err != nil || res != (Result{}) {
+		t.Fatalf("sync of failed repository = %+v, %v; want no changes", res, err)
+	}
+
+	// Fixed violations are closed
+	if res, err = s.Sync(ctx, violating(renamed)); err != nil || res != (Result{Closed: 1}) {
+		t.Fatalf("sync after fix = %+v, %v; want 1 closed", res, err)
+	}
+	if _, ok := tracker.find("Policy pypi-only: internal in org/api@main"); ok {
+		t.Error("fixed violation's ticket still open")
+	}
+
+	// Severity filter
+	tracker = newFakeTracker()
+	s = &Syncer{Name: "errors", Tracker: tracker, MinSeverity: report.SeverityError}
+	if res, err = s.Sync(ctx, violating(old, warn)); err != nil || res != (Result{Created: 1}) {
+		t.Fatalf("error-only sync = %+v, %v; want 1 created", res, err)
+	}
+
+	// Tag filter
+	s = &Syncer{Name: "frontend", Tracker: newFakeTracker(), Tags: []string{"frontend"}}
+	if res, err = s.Sync(ctx, violating(old, warn)); err != nil || res != (Result{}) {
+		t.Fatalf("frontend sync = %+v, %v; want no changes", res, err)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Setenv("JIRA_TOKEN", "secret")
+	cfg := &config.Config{Issues: []config.IssueTrackerConfig{
+		{Name: "gh", Type: config.TrackerGitHub},
+		{Name: "jira", Type: config.TrackerJira, BaseURL: "https://example.atlassian.net", Project: "OPS", TokenEnv: "JIRA_TOKEN"},
+	}}
+	syncers, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(syncers) != 2 {
+		t.Fatalf("got %d syncers, want 2", len(syncers))
+	}
+	if j := syncers[1].Tracker.(*JiraTracker); j.token != "secret" || j.issueType != DefaultJiraIssueType {
+		t.Errorf("jira tracker = %+v", j)
+	}
+
+	cfg.Issues[1].TokenEnv = "DEVDASHBOARD_UNSET_TOKEN"
+	if _, err := FromConfig(cfg); exitcode.FromError(err) != exitcode.ConfigError {
+		t.Errorf("missing token env: err = %v, want a config error", err)
+	}
+}
+
+// fakeIssues serves GitHubTracker from memory
+type fakeIssues struct {
+	issues []*github.Issue
+	edits  map[int]*github.IssueRequest
+}
+
+func (f *fakeIssues) ListByRepo(_ context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	if owner != "org" || repo != "api" || opts.State != "open" || opts.Labels[0] != ManagedLabel {
+		return nil, nil, errors.New("unexpected list")
+	}
+	return f.issues, &github.Response{}, nil
+}
+
+func (f *fakeIssues) Create(_ context.Context, _, _ string, req *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	issue := &github.Issue{Number: github.Int(len(f.issues) + 1), Title: req.Title, Body: req.Body, HTMLURL: github.String("https://github.com/org/api/issues/1")}
+	for _, l := range *req.Labels {
+		issue.Labels = append(issue.Labels, &github.Label{Name: github.String(l)})
+	}
+	f.issues = append(f.issues, issue)
+	return issue, nil, nil
+}
+
+func (f *fakeIssues) Edit(_ context.Context, _, _ string, number int, req *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	f.edits[number] = req
+	return nil, nil, nil
+}
+
+func TestGitHubTracker(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeIssues{edits: make(map[int]*github.IssueRequest)}
+	api.issues = []*github.Issue{
+		{Number: github.Int(7), Title: github.String("Unrelated"), Body: github.String("written by hand")},
+		{Number: github.Int(8), PullRequestLinks: &github.PullRequestLinks{}, Body: github.String(marker("0123456789ab", "github:org/api@main"))},
+	}
+	g := &GitHubTracker{api: api, labels: []string{"dependencies"}}
+
+	rr := violating().Repositories[0]
+	if p := g.Project(&rr); p != "org/api" {
+		t.Errorf("Project = %q, want org/api", p)
+	}
+	if p := g.Project(&report.RepositoryReport{Provider: "gitlab", Owner: "org", Repository: "api"}); p != "" {
+		t.Errorf("Project of a GitLab repository = %q, want none", p)
+	}
+
+	created, err := g.Create(ctx, Ticket{Project: "org/api", Title: "t", Body: marker("0123456789ab", "github:org/api@main")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "3" || len(api.issues[2].Labels) != 2 || api.issues[2].Labels[0].GetName() != ManagedLabel {
+		t.Errorf("created %+v with labels %v", created, api.issues[2].Labels)
+	}
+
+	open, err := g.ListOpen(ctx, "org/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].ID != "3" || open[0].Fingerprint != "0123456789ab" || open[0].Repository != "github:org/api@main" {
+		t.Errorf("ListOpen = %+v, want only the managed issue", open)
+	}
+
+	if err := g.Close(ctx, open[0]); err != nil {
+		t.Fatal(err)
+	}
+	if req := api.edits[3]; req == nil || req.GetState() != "closed" {
+		t.Errorf("close edit = %+v", req)
+	}
+}
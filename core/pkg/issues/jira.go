@@ -0,0 +1,206 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// DefaultJiraIssueType is the issue type of tickets without a configured one
+const DefaultJiraIssueType = "Bug"
+
+// DefaultCloseTransition is the workflow transition that closes a ticket
+const DefaultCloseTransition = "Done"
+
+// JiraTracker files Jira tickets in one project through the REST API (v2)
+type JiraTracker struct {
+	baseURL         string
+	project         string
+	user            string // Basic auth user; empty sends token as a bearer token
+	token           string
+	issueType       string
+	closeTransition string
+	labels          []string
+	client          *http.Client
+}
+
+// NewJiraTracker creates a tracker for c.Project on the Jira site at c.BaseURL
+func NewJiraTracker(c config.IssueTrackerConfig, token string) *JiraTracker {
+	j := &JiraTracker{
+		baseURL:         strings.TrimRight(c.BaseURL, "/"),
+		project:         c.Project,
+		user:            c.User,
+		token:           token,
+		issueType:       c.IssueType,
+		closeTransition: c.CloseTransition,
+		labels:          c.Labels,
+		client:          &http.Client{Timeout: 30 * time.Second},
+	}
+	if j.issueType == "" {
+		j.issueType = DefaultJiraIssueType
+	}
+	if j.closeTransition == "" {
+		j.closeTransition = DefaultCloseTransition
+	}
+	return j
+}
+
+// Project returns the configured project key for every repository
+func (j *JiraTracker) Project(*report.RepositoryReport) string {
+	return j.project
+}
+
+// jiraIssue is the subset of a Jira issue ListOpen reads
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+	} `json:"fields"`
+}
+
+// ListOpen returns the unresolved tickets labeled ManagedLabel that carry a
+// marker
+func (j *JiraTracker) ListOpen(ctx context.Context, project string) ([]Ticket, error) {
+	jql := fmt.Sprintf("project = %q AND labels = %q AND statusCategory != Done", project, ManagedLabel)
+	var out []Ticket
+	for start := 0; ; {
+		q := url.Values{
+			"jql":        {jql},
+			"fields":     {"summary,description"},
+			"startAt":    {fmt.Sprint(start)},
+			"maxResults": {"100"},
+		}
+		var page struct {
+			Total  int         `json:"total"`
+			Issues []jiraIssue `json:"issues"`
+		}
+		if err := j.do(ctx, http.MethodGet, "/rest/api/2/search?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			fp, repository, ok := parseMarker(issue.Fields.Description)
+			if !ok {
+				continue
+			}
+			out = append(out, Ticket{
+				ID:          issue.Key,
+				Project:     project,
+				Fingerprint: fp,
+				Repository:  repository,
+				Title:       issue.Fields.Summary,
+				Body:        issue.Fields.Description,
+				URL:         j.browseURL(issue.Key),
+			})
+		}
+		start += len(page.Issues)
+		if len(page.Issues) == 0 || start >= page.Total {
+			return out, nil
+		}
+	}
+}
+
+// Create files a ticket labeled ManagedLabel and the configured labels
+func (j *JiraTracker) Create(ctx context.Context, t Ticket) (Ticket, error) {
+	req := map[string]any{"fields": map[string]any{
+		"project":     map[string]string{"key": t.Project},
+		"summary":     t.Title,
+		"description": t.Body,
+		"issuetype":   map[string]string{"name": j.issueType},
+		"labels":      append([]string{ManagedLabel}, j.labels...),
+	}}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", req, &created); err != nil {
+		return t, err
+	}
+	t.ID = created.Key
+	t.URL = j.browseURL(created.Key)
+	return t, nil
+}
+
+// Update replaces the ticket's summary and description
+func (j *JiraTracker) Update(ctx context.Context, t Ticket) error {
+	req := map[string]any{"fields": map[string]any{
+		"summary":     t.Title,
+		"description": t.Body,
+	}}
+	return j.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(t.ID), req, nil)
+}
+
+// Close moves the ticket through the configured close transition
+func (j *JiraTracker) Close(ctx context.Context, t Ticket) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(t.ID) + "/transitions"
+	var list struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	if err := j.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return err
+	}
+	for _, tr := range list.Transitions {
+		if strings.EqualFold(tr.Name, j.closeTransition) {
+			return j.do(ctx, http.MethodPost, path, map[string]any{"transition": map[string]string{"id": tr.ID}}, nil)
+		}
+	}
+	return fmt.Errorf("ticket %s has no %q transition", t.ID, j.closeTransition)
+}
+
+// browseURL links to a ticket in the Jira UI
+func (j *JiraTracker) browseURL(key string) string {
+	return j.baseURL + "/browse/" + key
+}
+
+// do sends a JSON request to the Jira API and decodes the response into out
+// when it is non-nil
+func (j *JiraTracker) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode Jira request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, j.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.user != "" {
+		req.SetBasicAuth(j.user, j.token)
+	} else if j.token != "" {
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("jira %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode Jira response: %w", err)
+	}
+	return nil
+}
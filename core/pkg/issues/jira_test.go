@@ -0,0 +1,92 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+func TestJiraTracker(t *testing.T) {
+	var requests []string
+	var createdFields map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if user, pass, ok := r.BasicAuth(); !ok || user != "bot@example.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/search":
+			if jql := r.URL.Query().Get("jql"); !strings.Contains(jql, `project = "OPS"`) || !strings.Contains(jql, `labels = "devdashboard"`) {
+				t.Errorf("jql = %q", jql)
+			}
+			_, _ = w.Write([]byte(`{"total": 2, "issues": [
+				{"key": "OPS-1", "fields": {"summary": "Policy p: django in org/api@main", "description": "x\n\ndevdashboard-ticket: 0123456789ab github:org/api@main"}},
+				{"key": "OPS-2", "fields": {"summary": "Manual", "description": "filed by hand"}}]}`))
+		case "POST /rest/api/2/issue":
+			var req struct {
+				Fields map[string]any `json:"fields"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			createdFields = req.Fields
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"key": "OPS-3"}`))
+		case "GET /rest/api/2/issue/OPS-1/transitions":
+			_, _ = w.Write([]byte(`{"transitions": [{"id": "11", "name": "In Progress"}, {"id": "31", "name": "Done"}]}`))
+		case "POST /rest/api/2/issue/OPS-1/transitions":
+			var req struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.Transition.ID != "31" {
+				t.Errorf("transition = %q, want 31", req.Transition.ID)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	j := NewJiraTracker(config.IssueTrackerConfig{BaseURL: srv.URL, Project: "OPS", User: "bot@example.com", Labels: []string{"deps"}}, "secret")
+
+	open, err := j.ListOpen(ctx, "OPS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].ID != "OPS-1" || open[0].Fingerprint != "0123456789ab" || open[0].URL != srv.URL+"/browse/OPS-1" {
+		t.Errorf("ListOpen = %+v, want only OPS-1", open)
+	}
+
+	created, err := j.Create(ctx, Ticket{Project: "OPS", Title: "Policy p: flask in org/web@main", Body: "body"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "OPS-3" {
+		t.Errorf("created ID = %q, want OPS-3", created.ID)
+	}
+	if typ := createdFields["issuetype"].(map[string]any)["name"]; typ != DefaultJiraIssueType {
+		t.Errorf("issue type = %v, want %s", typ, DefaultJiraIssueType)
+	}
+	if labels := createdFields["labels"].([]any); len(labels) != 2 || labels[0] != ManagedLabel {
+		t.Errorf("labels = %v", labels)
+	}
+
+	if err := j.Close(ctx, open[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Update(ctx, open[0]); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("update against a failing server: err = %v", err)
+	}
+	if len(requests) != 5 {
+		t.Errorf("requests = %v", requests)
+	}
+}
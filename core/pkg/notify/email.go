@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/report/format"
+)
+
+// DefaultSMTPPort is used when an email notifier does not set a port
+const DefaultSMTPPort = 587
+
+// DefaultSubject is the subject of email digests without a configured one
+const DefaultSubject = "Dependency report digest"
+
+// emailTemplate lays out a digest: the changes, then optionally the report
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="font-family: sans-serif; color: #222;">
+<h2>{{.Subject}}</h2>
+{{- with .Summary}}
+{{- if .Empty}}
+<p>No new version drift, failures or packages.</p>
+{{- end}}
+{{- if .Drift}}
+<h3>New version drift</h3>
+<ul>{{range .Drift}}<li>{{.}}</li>{{end}}</ul>
+{{- end}}
+{{- if .Errors}}
+<h3>Newly failing repositories</h3>
+<ul>{{range .Errors}}<li><b>{{.Repository}}</b>: {{.Error}}</li>{{end}}</ul>
+{{- end}}
+{{- if .Packages}}
+<h3>Newly added packages</h3>
+<ul>{{range .Packages}}<li>{{.Package}} {{.Version}} in <b>{{.Repository}}</b></li>{{end}}</ul>
+{{- end}}
+{{- end}}
+{{- if .Report}}
+<hr>
+{{.Report}}
+{{- end}}
+</body></html>
+`))
+
+// sendEmail mails the HTML digest of prev→next. A changes digest is skipped
+// when nothing changed; a report digest is always sent.
+func (n *Notifier) sendEmail(prev, next *report.Report, now time.Time) error {
+	e := n.Email
+	s := n.Summarize(prev, next)
+	full := e.Digest == config.DigestReport
+	if s.Empty() && !full {
+		slog.Debug("Nothing to notify", "notifier", n.Name)
+		return nil
+	}
+
+	subject := e.Subject
+	if subject == "" {
+		subject = DefaultSubject
+	}
+	data := struct {
+		Subject string
+		Summary Summary
+		Report  template.HTML
+	}{Subject: subject, Summary: s}
+	if full && next != nil {
+		_, scoped := n.scope(nil, next)
+		var page bytes.Buffer
+		f := &format.HTMLFormatter{Title: "Current report", Fragment: true, Now: now}
+		if err := f.Render(scoped, &page); err != nil {
+			return err
+		}
+		// #nosec G203 -- produced by the html/template based formatter
+		data.Report = template.HTML(page.String())
+	}
+	var body bytes.Buffer
+	if err := emailTemplate.Execute(&body, data); err != nil {
+		return fmt.Errorf("render email: %w", err)
+	}
+	msg, err := buildMessage(e.From, e.To, subject, body.Bytes(), now)
+	if err != nil {
+		return err
+	}
+
+	port := e.Port
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+	var auth smtp.Auth
+	if e.Username != "" {
+		auth = smtp.PlainAuth("", e.Username, n.password, e.Host)
+	}
+	send := n.sendMail
+	if send == nil {
+		send = smtp.SendMail
+	}
+	if err := send(net.JoinHostPort(e.Host, strconv.Itoa(port)), auth, e.From, e.To, msg); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
+
+// buildMessage assembles an RFC 5322 message with a quoted-printable HTML body
+func buildMessage(from string, to []string, subject string, html []byte, now time.Time) ([]byte, error) {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&msg)
+	if _, err := qp.Write(html); err != nil {
+		return nil, fmt.Errorf("encode email: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("encode email: %w", err)
+	}
+	return msg.Bytes(), nil
+}
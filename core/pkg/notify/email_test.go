@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+// sentMail is one message captured instead of being sent
+type sentMail struct {
+	addr string
+	to   []string
+	body string
+}
+
+// captureMail swaps n's SMTP sender for one that records messages
+func captureMail(t *testing.T, n *Notifier) *[]sentMail {
+	t.Helper()
+	var sent []sentMail
+	n.sendMail = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		_, body, _ := bytes.Cut(msg, []byte("\r\n\r\n"))
+		decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, sentMail{addr: addr, to: to, body: string(decoded)})
+		return nil
+	}
+	return &sent
+}
+
+func emailNotifier(t *testing.T, digest string, every time.Duration) *Notifier {
+	t.Helper()
+	notifiers, err := FromConfig([]config.NotifierConfig{{
+		Name:  "digest",
+		Type:  config.NotifierEmail,
+		Every: every,
+		Email: &config.EmailConfig{Host: "smtp.example.com", From: "dd@example.com", To: []string{"team@example.com"}, Digest: digest},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notifiers[0]
+}
+
+func TestEmailDigest(t *testing.T) {
+	prev, next := reports()
+
+	changes := emailNotifier(t, config.DigestChanges, 0)
+	sent := captureMail(t, changes)
+	if err := changes.Notify(context.Background(), next, next); err != nil || len(*sent) != 0 {
+		t.Fatalf("unchanged changes digest: err=%v sent=%d", err, len(*sent))
+	}
+	if err := changes.Notify(context.Background(), prev, next); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 || (*sent)[0].addr != "smtp.example.com:587" || (*sent)[0].to[0] != "team@example.com" {
+		t.Fatalf("sent = %+v", *sent)
+	}
+	body := (*sent)[0].body
+	for _, want := range []string{"<h3>Newly failing repositories</h3>", "github:org/web@main</b>: rate limited", "requests 2.32.0 in"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("digest missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "Current report") {
+		t.Error("changes digest should not include the report")
+	}
+
+	full := emailNotifier(t, config.DigestReport, 0)
+	sent = captureMail(t, full)
+	if err := full.Notify(context.Background(), next, next); err != nil || len(*sent) != 1 {
+		t.Fatalf("report digest: err=%v sent=%d", err, len(*sent))
+	}
+	for _, want := range []string{"No new version drift", "<h2>Current report</h2>", "org/api"} {
+		if !strings.Contains((*sent)[0].body, want) {
+			t.Errorf("report digest missing %q:\n%s", want, (*sent)[0].body)
+		}
+	}
+}
+
+func TestNotifyEvery(t *testing.T) {
+	prev, next := reports()
+	n := emailNotifier(t, config.DigestChanges, time.Hour)
+	sent := captureMail(t, n)
+
+	if err := n.Notify(context.Background(), nil, prev); err != nil {
+		t.Fatal(err)
+	}
+	// Within the interval: batched, even though next has changes
+	if err := n.Notify(context.Background(), prev, next); err != nil || len(*sent) != 0 {
+		t.Fatalf("expected no delivery within the interval, err=%v sent=%d", err, len(*sent))
+	}
+	// Once due, the digest covers everything since the last delivery
+	n.sent = n.sent.Add(-2 * time.Hour)
+	if err := n.Notify(context.Background(), next, next); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 || !strings.Contains((*sent)[0].body, "rate limited") {
+		t.Errorf("expected the batched changes, got %+v", *sent)
+	}
+}
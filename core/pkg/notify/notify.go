@@ -0,0 +1,297 @@
+// Package notify posts a summary of what changed between two report runs
+// (new version drift, newly failing repositories, newly added packages) to
+// Slack, Microsoft Teams or a generic webhook, or emails it as an HTML digest.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/smtp"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// DefaultTimeout bounds a single notification delivery
+const DefaultTimeout = 15 * time.Second
+
+// DefaultTemplate renders a Summary as plain text with one line per change
+const DefaultTemplate = `Dependency report: {{.Repositories}} repositories analyzed
+{{- if .Drift}}
+New version drift: {{join .Drift ", "}}
+{{- end}}
+{{- if .Errors}}
+Newly failing repositories:
+{{- range .Errors}}
+- {{.Repository}}: {{.Error}}
+{{- end}}
+{{- end}}
+{{- if .Packages}}
+Newly added packages:
+{{- range .Packages}}
+- {{.Package}} {{.Version}} in {{.Repository}}
+{{- end}}
+{{- end}}
+`
+
+// Summary is what changed between two runs, as seen by one notifier. It is
+// the data passed to message templates.
+type Summary struct {
+	Notifier     string                   `json:"notifier"`
+	Repositories int                      `json:"repositories"` // Repositories in scope of the notifier
+	Drift        []string                 `json:"drift"`        // Packages that started drifting
+	Errors       []Failure                `json:"errors"`       // Repositories that started failing
+	Packages     []report.PackageAddition `json:"packages"`     // Tracked packages newly locked by a repository
+}
+
+// Failure is a newly failing repository
+type Failure struct {
+	Repository string `json:"repository"` // RepositoryReport.Key
+	Error      string `json:"error"`
+}
+
+// Empty reports whether there is nothing to notify about
+func (s Summary) Empty() bool {
+	return len(s.Drift) == 0 && len(s.Errors) == 0 && len(s.Packages) == 0
+}
+
+// Summarize compares next with prev. A nil prev (no previous run) reports
+// every drift and failure in next as new, but no added packages.
+func Summarize(prev, next *report.Report) Summary {
+	s := Summary{Drift: []string{}, Errors: []Failure{}, Packages: []report.PackageAddition{}}
+	if next == nil {
+		return s
+	}
+	s.Repositories = len(next.Repositories)
+	problems := report.NewProblems(prev, next)
+	s.Drift = append(s.Drift, problems.Drift...)
+	for _, key := range problems.Errors {
+		for _, rr := range next.Repositories {
+			if rr.Key() == key {
+				s.Errors = append(s.Errors, Failure{Repository: key, Error: rr.Error.Error()})
+				break
+			}
+		}
+	}
+	s.Packages = append(s.Packages, report.NewPackages(prev, next)...)
+	return s
+}
+
+// Notifier delivers summaries to one channel. A Notifier with Every set
+// keeps the report of its last delivery and must not be used concurrently.
+type Notifier struct {
+	Name     string
+	Type     string // config.NotifierSlack, NotifierTeams, NotifierWebhook or NotifierEmail
+	URL      string
+	Template *template.Template
+	Events   []string      // Empty means every event
+	Tags     []string      // Only repositories carrying any of these tags; empty means all
+	Every    time.Duration // Minimum interval between deliveries; zero delivers every run
+	Email    *config.EmailConfig
+	Client   *http.Client
+
+	password string // Resolved from Email.PasswordEnv
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+	sent     time.Time      // Last delivery (Every only)
+	baseline *report.Report // Report at the last delivery (Every only)
+}
+
+// FromConfig builds notifiers from configuration entries, resolving URLEnv
+// and parsing templates. Failures are configuration errors.
+func FromConfig(cfgs []config.NotifierConfig) ([]*Notifier, error) {
+	notifiers := make([]*Notifier, 0, len(cfgs))
+	for _, c := range cfgs {
+		n := &Notifier{
+			Name:     c.Name,
+			Type:     c.Type,
+			Events:   c.Events,
+			Tags:     c.Tags,
+			Every:    c.Every,
+			Email:    c.Email,
+			Client:   &http.Client{Timeout: DefaultTimeout},
+			sendMail: smtp.SendMail,
+		}
+		if c.Type == config.NotifierEmail {
+			if c.Email.PasswordEnv != "" {
+				if n.password = os.Getenv(c.Email.PasswordEnv); n.password == "" {
+					return nil, exitcode.Errorf(exitcode.ConfigError, "notifier %s: environment variable %s is not set", c.Name, c.Email.PasswordEnv)
+				}
+			}
+			notifiers = append(notifiers, n)
+			continue
+		}
+
+		n.URL = c.URL
+		if n.URL == "" {
+			if n.URL = os.Getenv(c.URLEnv); n.URL == "" {
+				return nil, exitcode.Errorf(exitcode.ConfigError, "notifier %s: environment variable %s is not set", c.Name, c.URLEnv)
+			}
+		}
+		text := c.Template
+		if text == "" {
+			text = DefaultTemplate
+		}
+		var err error
+		if n.Template, err = template.New(c.Name).Funcs(template.FuncMap{"join": strings.Join}).Parse(text); err != nil {
+			return nil, exitcode.Errorf(exitcode.ConfigError, "notifier %s: invalid template: %w", c.Name, err)
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers, nil
+}
+
+// Summarize narrows the comparison of prev and next to the notifier's
+// repositories and events. Repositories of prev that next did not analyze
+// (e.g. a run narrowed with --tag) are ignored.
+func (n *Notifier) Summarize(prev, next *report.Report) Summary {
+	prev, next = n.scope(prev, next)
+	s := Summarize(prev, next)
+	s.Notifier = n.Name
+	if !n.wants(config.EventDrift) {
+		s.Drift = []string{}
+	}
+	if !n.wants(config.EventErrors) {
+		s.Errors = []Failure{}
+	}
+	if !n.wants(config.EventPackages) {
+		s.Packages = []report.PackageAddition{}
+	}
+	return s
+}
+
+// scope narrows prev and next to the repositories of next the notifier covers
+func (n *Notifier) scope(prev, next *report.Report) (*report.Report, *report.Report) {
+	if next == nil {
+		return prev, nil
+	}
+	keys := make(map[string]bool)
+	for _, rr := range next.Repositories {
+		if len(n.Tags) == 0 || config.HasAnyTag(rr.Tags, n.Tags) {
+			keys[rr.Key()] = true
+		}
+	}
+	return subset(prev, keys), subset(next, keys)
+}
+
+// wants reports whether the notifier subscribes to event
+func (n *Notifier) wants(event string) bool {
+	return len(n.Events) == 0 || slices.Contains(n.Events, event)
+}
+
+// subset copies r keeping only the repositories whose key is in keys
+func subset(r *report.Report, keys map[string]bool) *report.Report {
+	if r == nil {
+		return nil
+	}
+	out := &report.Report{Packages: r.Packages}
+	for _, rr := range r.Repositories {
+		if keys[rr.Key()] {
+			out.Repositories = append(out.Repositories, rr)
+		}
+	}
+	return out
+}
+
+// Notify delivers the notifier's summary of prev→next. Chat and webhook
+// channels skip runs where nothing changed. With Every set, deliveries
+// within the interval of the last one are skipped, and the next delivery
+// compares with the report of the last one instead of prev.
+func (n *Notifier) Notify(ctx context.Context, prev, next *report.Report) error {
+	now := time.Now()
+	if n.Every > 0 && !n.sent.IsZero() {
+		if now.Sub(n.sent) < n.Every {
+			return nil
+		}
+		prev = n.baseline
+	}
+	var err error
+	if n.Type == config.NotifierEmail {
+		err = n.sendEmail(prev, next, now)
+	} else {
+		err = n.post(ctx, prev, next)
+	}
+	if err == nil && n.Every > 0 {
+		n.sent, n.baseline = now, next
+	}
+	return err
+}
+
+// post sends the rendered template to a chat or webhook URL
+func (n *Notifier) post(ctx context.Context, prev, next *report.Report) error {
+	s := n.Summarize(prev, next)
+	if s.Empty() {
+		slog.Debug("Nothing to notify", "notifier", n.Name)
+		return nil
+	}
+	var text strings.Builder
+	if err := n.Template.Execute(&text, s); err != nil {
+		return fmt.Errorf("render message: %w", err)
+	}
+	body, err := json.Marshal(n.payload(strings.TrimSpace(text.String()), s))
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("post notification: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// payload wraps the rendered text in the body the channel type expects
+func (n *Notifier) payload(text string, s Summary) any {
+	switch n.Type {
+	case config.NotifierSlack:
+		return map[string]string{"text": text}
+	case config.NotifierTeams:
+		// Teams renders Markdown, where single newlines do not break lines
+		return map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  "Dependency report changes",
+			"text":     strings.ReplaceAll(text, "\n", "\n\n"),
+		}
+	default:
+		return struct {
+			Text    string  `json:"text"`
+			Summary Summary `json:"summary"`
+		}{text, s}
+	}
+}
+
+// NotifyAll delivers to every notifier. Failures are logged and never fail
+// the report run.
+func NotifyAll(ctx context.Context, notifiers []*Notifier, prev, next *report.Report) {
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, prev, next); err != nil {
+			slog.Warn("Notification failed", "notifier", n.Name, "type", n.Type, "error", err)
+		}
+	}
+}
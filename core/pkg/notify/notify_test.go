@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func reports() (prev, next *report.Report) {
+	prev = &report.Report{
+		Packages: []string{"django", "requests"},
+		Repositories: []report.RepositoryReport{
+			{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry", Tags: []string{"prod"},
+				Dependencies: map[string]string{"django": "4.2", "requests": ""}},
+			{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"django": "4.2", "requests": "2.31.0"}},
+		},
+	}
+	next = &report.Report{
+		Packages: []string{"django", "requests"},
+		Repositories: []report.RepositoryReport{
+			{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry", Tags: []string{"prod"},
+				Dependencies: map[string]string{"django": "4.2", "requests": "2.32.0"}},
+			{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Analyzer: "poetry",
+				Error: report.NewErrorDetail(errors.New("rate limited"))},
+		},
+	}
+	return prev, next
+}
+
+// capture records the bodies posted to a test server
+func capture(t *testing.T, status int) (*httptest.Server, *[]string) {
+	t.Helper()
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func TestNotify(t *testing.T) {
+	prev, next := reports()
+	srv, bodies := capture(t, http.StatusOK)
+
+	notifiers, err := FromConfig([]config.NotifierConfig{
+		{Name: "chat", Type: config.NotifierSlack, URL: srv.URL},
+		{Name: "prod", Type: config.NotifierWebhook, URL: srv.URL, Tags: []string{"prod"}, Events: []string{config.EventPackages}},
+		{Name: "teams", Type: config.NotifierTeams, URL: srv.URL, Template: "{{len .Errors}} failing\n{{range .Errors}}{{.Repository}}{{end}}"},
+		{Name: "quiet", Type: config.NotifierSlack, URL: srv.URL, Tags: []string{"prod"}, Events: []string{config.EventErrors}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	NotifyAll(context.Background(), notifiers, prev, next)
+	if len(*bodies) != 3 {
+		t.Fatalf("got %d deliveries, want 3 (quiet has nothing to report): %v", len(*bodies), *bodies)
+	}
+
+	var slack map[string]string
+	if err := json.Unmarshal([]byte((*bodies)[0]), &slack); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"2 repositories analyzed", "Newly failing repositories:\n- github:org/web@main: rate limited", "- requests 2.32.0 in github:org/api@main"} {
+		if !strings.Contains(slack["text"], want) {
+			t.Errorf("slack text missing %q:\n%s", want, slack["text"])
+		}
+	}
+
+	var hook struct {
+		Summary Summary `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte((*bodies)[1]), &hook); err != nil {
+		t.Fatal(err)
+	}
+	if hook.Summary.Repositories != 1 || len(hook.Summary.Errors) != 0 || len(hook.Summary.Packages) != 1 {
+		t.Errorf("routed summary = %+v", hook.Summary)
+	}
+
+	var teams map[string]string
+	if err := json.Unmarshal([]byte((*bodies)[2]), &teams); err != nil {
+		t.Fatal(err)
+	}
+	if teams["@type"] != "MessageCard" || teams["text"] != "1 failing\n\ngithub:org/web@main" {
+		t.Errorf("teams payload = %v", teams)
+	}
+}
+
+func TestNotify_Failures(t *testing.T) {
+	prev, next := reports()
+	srv, _ := capture(t, http.StatusForbidden)
+	notifiers, err := FromConfig([]config.NotifierConfig{{Name: "chat", Type: config.NotifierSlack, URL: srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := notifiers[0].Notify(context.Background(), prev, next); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Notify error = %v, want 403", err)
+	}
+	if err := notifiers[0].Notify(context.Background(), next, next); err != nil {
+		t.Errorf("unchanged report should not be posted, got %v", err)
+	}
+
+	t.Setenv("DD_TEST_HOOK", "")
+	tests := []config.NotifierConfig{
+		{Name: "env", Type: config.NotifierSlack, URLEnv: "DD_TEST_HOOK"},
+		{Name: "tmpl", Type: config.NotifierSlack, URL: srv.URL, Template: "{{.Drift"},
+	}
+	for _, c := range tests {
+		if _, err := FromConfig([]config.NotifierConfig{c}); exitcode.FromError(err) != exitcode.ConfigError {
+			t.Errorf("FromConfig(%s) = %v, want a config error", c.Name, err)
+		}
+	}
+}
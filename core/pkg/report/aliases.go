@@ -0,0 +1,56 @@
+package report
+
+import (
+	"slices"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+// packageAliases maps a package's current name to its former names (see
+// Generator.SetPackageAliases)
+type packageAliases map[string][]string
+
+// SetPackageAliases makes repositories locking a former name of a package (a
+// value of aliases) report it under its current name (the key): a tracked
+// former name shares the current name's column and counts toward its drift,
+// and the name actually locked is kept in RepositoryReport.LockedNames.
+// Names compare by their ecosystem-normalized form.
+func (g *Generator) SetPackageAliases(aliases map[string][]string) {
+	g.aliases = aliases
+}
+
+// canonical returns the current name of pkg when it is a former name of an
+// aliased package, or pkg
+func (a packageAliases) canonical(eco dependencies.Ecosystem, pkg string) string {
+	name := dependencies.NormalizeName(eco, pkg)
+	for current, former := range a {
+		for _, alias := range former {
+			if dependencies.NormalizeName(eco, alias) == name {
+				return current
+			}
+		}
+	}
+	return pkg
+}
+
+// formerNames returns the sorted former names of pkg
+func (a packageAliases) formerNames(eco dependencies.Ecosystem, pkg string) []string {
+	name := dependencies.NormalizeName(eco, pkg)
+	var former []string
+	for current, aliases := range a {
+		if dependencies.NormalizeName(eco, current) == name {
+			former = append(former, aliases...)
+		}
+	}
+	slices.Sort(former)
+	return former
+}
+
+// LockedName returns the name pkg is locked as in the repository: its
+// LockedNames entry when found under a former name, otherwise pkg
+func (r *RepositoryReport) LockedName(pkg string) string {
+	if name, ok := r.LockedNames[pkg]; ok {
+		return name
+	}
+	return pkg
+}
@@ -0,0 +1,50 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+func TestGenerate_PackageAliases(t *testing.T) {
+	gen := stubGenerator()
+	gen.SetPackageAliases(map[string][]string{"web-core": {"Django"}})
+	repos := []config.RepoWithProvider{
+		{Provider: "fast", Config: config.RepoConfig{Owner: "o", Repository: "new", Analyzer: "poetry", Packages: []string{"web-core"}}},
+		{Provider: "fast", Config: config.RepoConfig{Owner: "o", Repository: "old", Analyzer: "poetry", Packages: []string{"django", "web_core"}}},
+	}
+
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if fmt.Sprint(rpt.Packages) != "[web-core]" {
+		t.Fatalf("expected the former name to share the current name's column, got %v", rpt.Packages)
+	}
+	for _, rr := range rpt.Repositories {
+		if got := rr.Dependencies["web-core"]; got != "4.2.0" {
+			t.Errorf("%s: web-core = %q, want the version locked as django", rr.Repository, got)
+		}
+		if got := rr.LockedName("web-core"); got != "django" {
+			t.Errorf("%s: LockedName(web-core) = %q, want django", rr.Repository, got)
+		}
+	}
+	rr := rpt.Repositories[0]
+	rr.CommitSHA = "abc123"
+	if got := newSnapshot([]RepositoryReport{rr}).Report().Repositories[0].LockedNames["web-core"]; got != "django" {
+		t.Errorf("snapshot LockedNames[web-core] = %q, want django", got)
+	}
+}
+
+func TestAnalysisFingerprint_Aliases(t *testing.T) {
+	repo := config.RepoWithProvider{Config: config.RepoConfig{Analyzer: "poetry", Packages: []string{"acme-core"}}}
+	plain := (&Generator{}).analysisFingerprint(repo)
+	if (&Generator{aliases: packageAliases{"other": {"x"}}}).analysisFingerprint(repo) != plain {
+		t.Error("aliases of untracked packages should not change the fingerprint")
+	}
+	if (&Generator{aliases: packageAliases{"acme-core": {"acme-utils"}}}).analysisFingerprint(repo) == plain {
+		t.Error("aliases of a tracked package should change the fingerprint")
+	}
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// SetBudget limits the provider API requests of each run (Generate or
+// Regenerate). Nil removes the limits.
+func (g *Generator) SetBudget(cfg *config.BudgetConfig) {
+	g.budget = cfg
+}
+
+type runBudgetsKey struct{}
+
+// withRunBudgets attaches fresh per-provider budgets for one run to ctx
+func (g *Generator) withRunBudgets(ctx context.Context, repos []config.RepoWithProvider) context.Context {
+	if g.budget == nil || len(g.budget.Providers) == 0 {
+		return ctx
+	}
+	budgets := make(map[string]*repository.Budget)
+	for _, r := range repos {
+		if _, ok := budgets[r.Provider]; !ok {
+			budgets[r.Provider] = repository.NewBudget(r.Provider, g.budget.Providers[r.Provider])
+		}
+	}
+	return context.WithValue(ctx, runBudgetsKey{}, budgets)
+}
+
+// repositoryBudgets returns the budgets charged by one repository's client:
+// its provider's run budget and its own per-repository budget
+func repositoryBudgets(ctx context.Context, repo config.RepoWithProvider, cfg *config.BudgetConfig) []*repository.Budget {
+	if cfg == nil {
+		return nil
+	}
+	var out []*repository.Budget
+	if run, ok := ctx.Value(runBudgetsKey{}).(map[string]*repository.Budget); ok && run[repo.Provider] != nil {
+		out = append(out, run[repo.Provider])
+	}
+	rr := RepositoryReport{Provider: repo.Provider, Owner: repo.Config.Owner, Repository: repo.Config.Repository, Ref: repo.Config.Ref}
+	if b := repository.NewBudget(rr.Key(), cfg.PerRepository); b != nil {
+		out = append(out, b)
+	}
+	return out
+}
+
+// logBudgetUsage reports how much of each provider budget the run consumed
+func logBudgetUsage(ctx context.Context) {
+	run, ok := ctx.Value(runBudgetsKey{}).(map[string]*repository.Budget)
+	if !ok {
+		return
+	}
+	providers := make([]string, 0, len(run))
+	for p := range run {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+	for _, p := range providers {
+		b := run[p]
+		if b == nil {
+			continue
+		}
+		level := slog.LevelInfo
+		if b.Used() >= b.Limit() {
+			level = slog.LevelWarn
+		}
+		slog.Log(ctx, level, "API request budget usage", "provider", p, "used", b.Used(), "limit", b.Limit())
+	}
+}
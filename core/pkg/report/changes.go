@@ -0,0 +1,101 @@
+package report
+
+import "sort"
+
+// Problems lists what got worse between two reports of the same repositories
+type Problems struct {
+	Drift  []string // Packages that now have version drift and did not before
+	Errors []string // Repository keys that now fail analysis and did not before
+}
+
+// Empty reports whether no new problems were found
+func (p Problems) Empty() bool {
+	return len(p.Drift) == 0 && len(p.Errors) == 0
+}
+
+// NewProblems compares next against prev and returns newly drifting packages
+// and newly failing repositories, each sorted. A nil prev treats every drift
+// and error in next as new; a nil next has no problems.
+func NewProblems(prev, next *Report) Problems {
+	var p Problems
+	if next == nil {
+		return p
+	}
+
+	drifted := make(map[string]bool)
+	failed := make(map[string]bool)
+	if prev != nil {
+		m := prev.Matrix()
+		for _, pkg := range prev.Packages {
+			if m.HasDrift(pkg) {
+				drifted[pkg] = true
+			}
+		}
+		for i := range prev.Repositories {
+			if prev.Repositories[i].Error != nil {
+				failed[prev.Repositories[i].Key()] = true
+			}
+		}
+	}
+
+	m := next.Matrix()
+	for _, pkg := range next.Packages {
+		if m.HasDrift(pkg) && !drifted[pkg] {
+			p.Drift = append(p.Drift, pkg)
+		}
+	}
+	for i := range next.Repositories {
+		rr := &next.Repositories[i]
+		if rr.Error != nil && !failed[rr.Key()] {
+			p.Errors = append(p.Errors, rr.Key())
+		}
+	}
+	sort.Strings(p.Drift)
+	sort.Strings(p.Errors)
+	return p
+}
+
+// PackageAddition is a tracked package locked by a repository that did not
+// lock it in the previous report
+type PackageAddition struct {
+	Repository string `json:"repository"` // RepositoryReport.Key
+	Package    string `json:"package"`
+	Version    string `json:"version"`
+}
+
+// NewPackages lists the tracked packages next finds in repositories that
+// prev analyzed successfully without finding them, sorted by repository and
+// package. Repositories absent or failing in prev are skipped, since every
+// package would look new; a nil prev therefore yields nothing.
+func NewPackages(prev, next *Report) []PackageAddition {
+	if prev == nil || next == nil {
+		return nil
+	}
+	before := make(map[string]map[string]string)
+	for i := range prev.Repositories {
+		if prev.Repositories[i].Error == nil {
+			before[prev.Repositories[i].Key()] = prev.Repositories[i].Dependencies
+		}
+	}
+
+	var added []PackageAddition
+	for i := range next.Repositories {
+		rr := &next.Repositories[i]
+		deps, ok := before[rr.Key()]
+		if !ok || rr.Error != nil {
+			continue
+		}
+		for pkg, version := range rr.Dependencies {
+			if version != "" && deps[pkg] == "" {
+				added = append(added, PackageAddition{Repository: rr.Key(), Package: pkg, Version: version})
+			}
+		}
+	}
+	sort.Slice(added, func(i, j int) bool {
+		if added[i].Repository != added[j].Repository {
+			return added[i].Repository < added[j].Repository
+		}
+		return added[i].Package < added[j].Package
+	})
+	return added
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewProblems(t *testing.T) {
+	prev := &Report{
+		Packages: []string{"requests", "django"},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"requests": "2.31.0", "django": "4.2"}},
+			{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"requests": "2.31.0", "django": "4.1"}},
+			{Provider: "github", Owner: "org", Repository: "cli", Ref: "main", Error: NewErrorDetail(errors.New("boom"))},
+		},
+	}
+	next := &Report{
+		Packages: []string{"requests", "django"},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"requests": "2.32.0", "django": "4.2"}},
+			{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"requests": "2.31.0", "django": "4.1"}},
+			{Provider: "github", Owner: "org", Repository: "cli", Ref: "main", Error: NewErrorDetail(errors.New("boom"))},
+			{Provider: "gitlab", Owner: "grp", Repository: "svc", Ref: "dev", Error: NewErrorDetail(errors.New("denied"))},
+		},
+	}
+
+	p := NewProblems(prev, next)
+	if fmt.Sprint(p.Drift) != "[requests]" {
+		t.Errorf("Drift = %v, want [requests]", p.Drift)
+	}
+	if fmt.Sprint(p.Errors) != "[gitlab:grp/svc@dev]" {
+		t.Errorf("Errors = %v, want [gitlab:grp/svc@dev]", p.Errors)
+	}
+	if p.Empty() {
+		t.Error("expected problems")
+	}
+
+	all := NewProblems(nil, next)
+	if fmt.Sprint(all.Drift) != "[django requests]" || len(all.Errors) != 2 {
+		t.Errorf("nil prev should report everything as new, got %+v", all)
+	}
+	if !NewProblems(next, next).Empty() || !NewProblems(prev, nil).Empty() {
+		t.Error("expected no problems for unchanged or nil reports")
+	}
+}
+
+func TestNewPackages(t *testing.T) {
+	prev := &Report{
+		Packages: []string{"requests", "django"},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Dependencies: map[string]string{"requests": "2.31.0", "django": ""}},
+			{Provider: "github", Owner: "org", Repository: "cli", Ref: "main", Error: NewErrorDetail(errors.New("boom"))},
+		},
+	}
+	next := &Report{
+		Packages: []string{"requests", "django"},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Dependencies: map[string]string{"requests": "2.32.0", "django": "4.2"}},
+			{Provider: "github", Owner: "org", Repository: "cli", Ref: "main", Dependencies: map[string]string{"requests": "2.31.0"}},
+			{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Dependencies: map[string]string{"django": "4.2"}},
+		},
+	}
+
+	got := NewPackages(prev, next)
+	want := []PackageAddition{{Repository: "github:org/api@main", Package: "django", Version: "4.2"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("NewPackages = %+v, want %+v", got, want)
+	}
+	if NewPackages(nil, next) != nil {
+		t.Error("nil prev should report no added packages")
+	}
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"slices"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+// EcosystemGroup is the part of a report belonging to one ecosystem: the
+// repositories whose analyzer resolves its packages and the packages they
+// track
+type EcosystemGroup struct {
+	Ecosystem    dependencies.Ecosystem `json:"ecosystem"`    // Empty for analyzers without an ecosystem
+	Packages     []string               `json:"packages"`     // Sorted
+	Repositories []string               `json:"repositories"` // RepositoryReport.Key values, in report order
+}
+
+// EcosystemGroups splits the report by ecosystem, ordered by ecosystem name
+// with analyzers without an ecosystem last
+func (r *Report) EcosystemGroups() []EcosystemGroup {
+	byEco := make(map[dependencies.Ecosystem]*EcosystemGroup)
+	group := func(eco dependencies.Ecosystem) *EcosystemGroup {
+		g, ok := byEco[eco]
+		if !ok {
+			g = &EcosystemGroup{Ecosystem: eco, Packages: []string{}, Repositories: []string{}}
+			byEco[eco] = g
+		}
+		return g
+	}
+	for i := range r.Repositories {
+		rr := &r.Repositories[i]
+		g := group(rr.GetEcosystem())
+		g.Repositories = append(g.Repositories, rr.Key())
+	}
+	for _, pkg := range r.Packages {
+		g := group(r.PackageEcosystem(pkg))
+		g.Packages = append(g.Packages, pkg)
+	}
+
+	out := make([]EcosystemGroup, 0, len(byEco))
+	for _, g := range byEco {
+		slices.Sort(g.Packages)
+		out = append(out, *g)
+	}
+	slices.SortFunc(out, func(a, b EcosystemGroup) int {
+		switch {
+		case a.Ecosystem == b.Ecosystem:
+			return 0
+		case a.Ecosystem == dependencies.EcosystemUnknown:
+			return 1
+		case b.Ecosystem == dependencies.EcosystemUnknown:
+			return -1
+		case a.Ecosystem < b.Ecosystem:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return out
+}
+
+// ForEcosystem returns a view of the report restricted to the repositories
+// and packages of eco, e.g. to render one section per ecosystem. Repository
+// reports are shared with r; suppressions are not carried over.
+func (r *Report) ForEcosystem(eco dependencies.Ecosystem) *Report {
+	view := &Report{PackageEcosystems: r.PackageEcosystems}
+	for _, rr := range r.Repositories {
+		if rr.GetEcosystem() == eco {
+			view.Repositories = append(view.Repositories, rr)
+		}
+	}
+	for _, pkg := range r.Packages {
+		if r.PackageEcosystem(pkg) == eco {
+			view.Packages = append(view.Packages, pkg)
+		}
+	}
+	return view
+}
+
+// EcosystemLabel returns the name shown for eco in section headings and
+// filters: the ecosystem itself, or "other" for analyzers without one
+func EcosystemLabel(eco dependencies.Ecosystem) string {
+	if eco == dependencies.EcosystemUnknown {
+		return "other"
+	}
+	return string(eco)
+}
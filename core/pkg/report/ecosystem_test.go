@@ -0,0 +1,60 @@
+package report
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+func TestEcosystemGroups(t *testing.T) {
+	rpt := &Report{
+		Packages: []string{"django", "https://github.com/psf/black", "serde"},
+		PackageEcosystems: map[string]dependencies.Ecosystem{
+			"django":                       dependencies.EcosystemPython,
+			"https://github.com/psf/black": dependencies.EcosystemPreCommit,
+			"serde":                        dependencies.EcosystemUnknown,
+		},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2.11"}},
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "pre-commit", Ecosystem: dependencies.EcosystemPreCommit},
+			{Provider: "github", Owner: "o", Repository: "cli", Ref: "main", Analyzer: "cargo"},
+			{Provider: "github", Owner: "o", Repository: "web", Ref: "main", Analyzer: "uvlock"},
+		},
+	}
+
+	groups := rpt.EcosystemGroups()
+	got := fmt.Sprint(groups)
+	want := "[{pre-commit [https://github.com/psf/black] [github:o/api@main]} " +
+		"{python [django] [github:o/api@main github:o/web@main]} " +
+		"{ [serde] [github:o/cli@main]}]"
+	if got != want {
+		t.Errorf("EcosystemGroups() =\n%s\nwant\n%s", got, want)
+	}
+
+	view := rpt.ForEcosystem(dependencies.EcosystemPython)
+	if len(view.Repositories) != 2 || view.Repositories[1].Repository != "web" || fmt.Sprint(view.Packages) != "[django]" {
+		t.Errorf("ForEcosystem(python) = %+v", view)
+	}
+	if EcosystemLabel(dependencies.EcosystemUnknown) != "other" || EcosystemLabel(dependencies.EcosystemPython) != "python" {
+		t.Error("unexpected ecosystem labels")
+	}
+}
+
+func TestPackageEcosystemFallback(t *testing.T) {
+	rpt := &Report{
+		Packages: []string{"django", "flask"},
+		Repositories: []RepositoryReport{
+			{Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2"}},
+		},
+	}
+	// Without PackageEcosystems, packages take their repositories' ecosystem
+	for _, pkg := range rpt.Packages {
+		if eco := rpt.PackageEcosystem(pkg); eco != dependencies.EcosystemPython {
+			t.Errorf("PackageEcosystem(%s) = %q, want python", pkg, eco)
+		}
+	}
+	if len(rpt.EcosystemGroups()) != 1 {
+		t.Errorf("expected a single group, got %+v", rpt.EcosystemGroups())
+	}
+}
@@ -0,0 +1,285 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrorCategory classifies why a repository analysis failed so callers can
+// handle (and display) auth problems, missing files and parse errors differently.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryNone indicates no error.
+	ErrorCategoryNone ErrorCategory = ""
+	// ErrorCategoryAuth indicates missing or insufficient credentials (HTTP 401/403).
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryNotFound indicates a missing repository, ref or dependency file.
+	ErrorCategoryNotFound ErrorCategory = "not-found"
+	// ErrorCategoryPrivate indicates a repository read without a token that
+	// the provider refused or hid (HTTP 401/403/404), most likely because it
+	// is private.
+	ErrorCategoryPrivate ErrorCategory = "private"
+	// ErrorCategoryParse indicates a dependency file that could not be parsed.
+	ErrorCategoryParse ErrorCategory = "parse"
+	// ErrorCategoryRateLimit indicates the provider rate-limited the request.
+	ErrorCategoryRateLimit ErrorCategory = "rate-limit"
+	// ErrorCategoryTimeout indicates the run or repository deadline, or a
+	// request's timeout (see repository.RequestTimeoutError), expired first.
+	ErrorCategoryTimeout ErrorCategory = "timeout"
+	// ErrorCategoryBudget indicates the run's API request budget ran out (see config.BudgetConfig).
+	ErrorCategoryBudget ErrorCategory = "budget"
+	// ErrorCategoryConfig indicates an invalid repository configuration (e.g. unknown analyzer).
+	ErrorCategoryConfig ErrorCategory = "config"
+	// ErrorCategoryUnknown covers all other failures.
+	ErrorCategoryUnknown ErrorCategory = "unknown"
+)
+
+// AuthError reports a provider authentication or authorization failure.
+type AuthError struct{ Err error }
+
+func (e *AuthError) Error() string { return e.Err.Error() }
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// NotFoundError reports a missing repository, ref or dependency file.
+type NotFoundError struct{ Err error }
+
+func (e *NotFoundError) Error() string { return e.Err.Error() }
+func (e *NotFoundError) Unwrap() error { return e.Err }
+
+// PrivateRepositoryError reports a repository read anonymously (no token
+// configured) that the provider refused or hid: private repositories look
+// missing to anonymous requests.
+type PrivateRepositoryError struct{ Err error }
+
+func (e *PrivateRepositoryError) Error() string { return e.Err.Error() }
+func (e *PrivateRepositoryError) Unwrap() error { return e.Err }
+
+// RateLimitError reports a provider rate-limit rejection.
+type RateLimitError struct{ Err error }
+
+func (e *RateLimitError) Error() string { return e.Err.Error() }
+func (e *RateLimitError) Unwrap() error { return e.Err }
+
+// TimeoutError reports a repository whose analysis did not finish before the
+// run or per-repository deadline.
+type TimeoutError struct{ Err error }
+
+func (e *TimeoutError) Error() string { return e.Err.Error() }
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// ErrorDetail is a repository's analysis failure in a form that survives
+// JSON and YAML (unlike an error interface), so reports and snapshots can be
+// persisted and compared faithfully. It is an error: the one it was built
+// from (see NewErrorDetail) is its Unwrap, and one decoded from JSON unwraps
+// to the typed error of its Category, so errors.Is/As and CategorizeError
+// behave the same on both.
+type ErrorDetail struct {
+	Category ErrorCategory `json:"category" yaml:"category"`
+	Message  string        `json:"message" yaml:"message"`
+	// Retryable is true when the failure is transient (rate limit, timeout,
+	// exhausted budget, provider server error) and a later run may succeed
+	Retryable bool `json:"retryable" yaml:"retryable"`
+	// Hint suggests how to fix the failure (see ErrorCategory.Hint), empty
+	// when its category has no remedy to suggest
+	Hint string `json:"hint,omitempty" yaml:"hint,omitempty"`
+
+	cause error
+}
+
+// NewErrorDetail describes err, or returns nil when err is nil. An
+// ErrorDetail in err's chain is returned as is.
+func NewErrorDetail(err error) *ErrorDetail {
+	if err == nil {
+		return nil
+	}
+	if detail, ok := err.(*ErrorDetail); ok {
+		return detail
+	}
+	category := CategorizeError(err)
+	return &ErrorDetail{
+		Category:  category,
+		Message:   err.Error(),
+		Retryable: isRetryable(category, err),
+		Hint:      category.Hint(),
+		cause:     err,
+	}
+}
+
+func (e *ErrorDetail) Error() string { return e.Message }
+
+// Unwrap returns the error e was built from, or for a decoded ErrorDetail
+// the typed error of its category
+func (e *ErrorDetail) Unwrap() error { return e.cause }
+
+// setImportedCause rebuilds e's cause from its persisted fields. Decoding
+// sets it once, so decoded reports can be read concurrently.
+func (e *ErrorDetail) setImportedCause() {
+	e.cause = importedError(e.Message, e.Category)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides the object form it
+// accepts the bare message written by JSON schema version 1 and snapshots
+// of earlier releases; any other object (the opaque error of unversioned
+// exports) leaves the message empty.
+func (e *ErrorDetail) UnmarshalJSON(data []byte) error {
+	var msg string
+	if err := json.Unmarshal(data, &msg); err == nil {
+		*e = ErrorDetail{Category: ErrorCategoryUnknown, Message: msg}
+		e.setImportedCause()
+		return nil
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return &json.UnmarshalTypeError{Value: string(data), Type: errorDetailType}
+	}
+	var fields errorDetailFields
+	_ = json.Unmarshal(data, &fields) // Unknown objects decode as empty
+	*e = ErrorDetail(fields)
+	e.setImportedCause()
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (e *ErrorDetail) UnmarshalYAML(node *yaml.Node) error {
+	var fields errorDetailFields
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	*e = ErrorDetail(fields)
+	e.setImportedCause()
+	return nil
+}
+
+// errorDetailFields is ErrorDetail without its JSON and YAML methods
+type errorDetailFields ErrorDetail
+
+var errorDetailType = reflect.TypeFor[ErrorDetail]()
+
+// Hint suggests how to fix a failure of category c, "" when there is no
+// general remedy
+func (c ErrorCategory) Hint() string {
+	switch c {
+	case ErrorCategoryPrivate:
+		return "The repository was read without a token. If it is private, configure a token with read access to it (token in the repository or provider default settings)."
+	case ErrorCategoryAuth:
+		return "Check that the configured token is valid, not expired, and grants read access to the repository."
+	}
+	return ""
+}
+
+// isRetryable reports whether a failure of category may go away on its own
+func isRetryable(category ErrorCategory, err error) bool {
+	switch category {
+	case ErrorCategoryRateLimit, ErrorCategoryTimeout, ErrorCategoryBudget:
+		return true
+	}
+	return repository.StatusCode(err) >= http.StatusInternalServerError
+}
+
+// CategorizeError returns the ErrorCategory of err based on the typed errors in its chain.
+func CategorizeError(err error) ErrorCategory {
+	if err == nil {
+		return ErrorCategoryNone
+	}
+	if detail, ok := err.(*ErrorDetail); ok {
+		if detail == nil {
+			return ErrorCategoryNone
+		}
+		if detail.Category != ErrorCategoryNone {
+			return detail.Category
+		}
+	}
+	var (
+		authErr     *AuthError
+		privateErr  *PrivateRepositoryError
+		notFoundErr *NotFoundError
+		rateErr     *RateLimitError
+		timeoutErr  *TimeoutError
+		reqTimeout  *repository.RequestTimeoutError
+		parseErr    *dependencies.ParseError
+	)
+	switch {
+	case errors.Is(err, repository.ErrBudgetExhausted):
+		return ErrorCategoryBudget
+	case errors.As(err, &timeoutErr), errors.As(err, &reqTimeout):
+		return ErrorCategoryTimeout
+	case errors.As(err, &rateErr):
+		return ErrorCategoryRateLimit
+	case errors.As(err, &privateErr):
+		return ErrorCategoryPrivate
+	case errors.As(err, &authErr):
+		return ErrorCategoryAuth
+	case errors.As(err, &notFoundErr):
+		return ErrorCategoryNotFound
+	case errors.As(err, &parseErr):
+		return ErrorCategoryParse
+	case exitcode.Is(err, exitcode.ConfigError):
+		return ErrorCategoryConfig
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+// ErrorCategory returns the category of the repository's analysis error.
+func (r *RepositoryReport) ErrorCategory() ErrorCategory {
+	return CategorizeError(r.Error)
+}
+
+// Err returns the repository's analysis error as an error, nil when the
+// analysis succeeded (unlike passing the nil *ErrorDetail on)
+func (r *RepositoryReport) Err() error {
+	if r.Error == nil {
+		return nil
+	}
+	return r.Error
+}
+
+// classifyError tags provider failures with exitcode.ProviderError and wraps
+// them in the matching typed error (RateLimitError, AuthError, NotFoundError).
+// Other errors are returned unchanged.
+func classifyError(err error) error {
+	if !repository.IsProviderError(err) {
+		return err
+	}
+	err = exitcode.New(exitcode.ProviderError, err)
+	switch status := repository.StatusCode(err); {
+	case repository.IsRateLimitError(err):
+		return &RateLimitError{Err: err}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return &AuthError{Err: err}
+	case status == http.StatusNotFound:
+		return &NotFoundError{Err: err}
+	}
+	return err
+}
+
+// classifyAnonymous turns the auth or not-found failure of a repository
+// read without a token into a PrivateRepositoryError when the repository
+// itself cannot be read either, since private repositories look missing to
+// anonymous requests. A file missing from a readable public repository keeps
+// its category, as do failures of repositories with a token.
+func classifyAnonymous(ctx context.Context, client repository.Client, repo config.RepoWithProvider, err error) error {
+	if repo.Config.Token != "" {
+		return err
+	}
+	if category := CategorizeError(err); category != ErrorCategoryAuth && category != ErrorCategoryNotFound {
+		return err
+	}
+	_, infoErr := client.GetRepositoryInfo(ctx, repo.Config.Owner, repo.Config.Repository)
+	switch repository.StatusCode(infoErr) {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return &PrivateRepositoryError{Err: fmt.Errorf("repository private or token missing: %w", err)}
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+	"gopkg.in/yaml.v3"
+)
+
+func githubError(status int) error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: status}}
+}
+
+func TestClassifyError_Categories(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category ErrorCategory
+		code     exitcode.Code
+	}{
+		{"unauthorized", githubError(401), ErrorCategoryAuth, exitcode.ProviderError},
+		{"forbidden", fmt.Errorf("list: %w", githubError(403)), ErrorCategoryAuth, exitcode.ProviderError},
+		{"not found", githubError(404), ErrorCategoryNotFound, exitcode.ProviderError},
+		{"rate limited", githubError(429), ErrorCategoryRateLimit, exitcode.ProviderError},
+		{"github rate limit", &github.RateLimitError{}, ErrorCategoryRateLimit, exitcode.ProviderError},
+		{"server error", githubError(500), ErrorCategoryUnknown, exitcode.ProviderError},
+		{"budget", &url.Error{Op: "Get", URL: "https://api.github.com", Err: &repository.BudgetExhaustedError{Scope: "github", Limit: 5}}, ErrorCategoryBudget, exitcode.ProviderError},
+		{"parse", &dependencies.ParseError{Path: "poetry.lock", Err: errors.New("bad toml")}, ErrorCategoryParse, exitcode.Failure},
+		{"plain", errors.New("boom"), ErrorCategoryUnknown, exitcode.Failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+			if got := CategorizeError(err); got != tt.category {
+				t.Errorf("category = %q, want %q", got, tt.category)
+			}
+			if got := exitcode.FromError(err); got != tt.code {
+				t.Errorf("exit code = %v, want %v", got, tt.code)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("classified error should wrap the original")
+			}
+		})
+	}
+}
+
+func TestCategorizeError_TypedAndConfig(t *testing.T) {
+	if got := CategorizeError(nil); got != ErrorCategoryNone {
+		t.Errorf("nil error category = %q", got)
+	}
+	if got := CategorizeError(&NotFoundError{Err: errors.New("no dependency files found")}); got != ErrorCategoryNotFound {
+		t.Errorf("NotFoundError category = %q", got)
+	}
+	cfgErr := exitcode.Errorf(exitcode.ConfigError, "failed to create analyzer")
+	rr := RepositoryReport{Error: NewErrorDetail(cfgErr)}
+	if got := rr.ErrorCategory(); got != ErrorCategoryConfig {
+		t.Errorf("config error category = %q", got)
+	}
+}
+
+func TestErrorDetail_RoundTrip(t *testing.T) {
+	detail := NewErrorDetail(classifyError(fmt.Errorf("failed to find dependency files: %w", githubError(429))))
+	if detail.Category != ErrorCategoryRateLimit || !detail.Retryable {
+		t.Fatalf("detail = %+v", detail)
+	}
+	if NewErrorDetail(detail) != detail || NewErrorDetail(nil) != nil {
+		t.Error("NewErrorDetail must keep an ErrorDetail and nil")
+	}
+
+	data, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON ErrorDetail
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	data, err = yaml.Marshal(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromYAML ErrorDetail
+	if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	for name, got := range map[string]*ErrorDetail{"JSON": &fromJSON, "YAML": &fromYAML} {
+		var rateErr *RateLimitError
+		if got.Message != detail.Message || !got.Retryable || CategorizeError(got) != ErrorCategoryRateLimit || !errors.As(got, &rateErr) {
+			t.Errorf("%s round trip = %+v, want %+v", name, got, detail)
+		}
+	}
+}
+
+func TestErrorDetail_DecodedConcurrentReads(t *testing.T) {
+	var detail ErrorDetail
+	if err := json.Unmarshal([]byte(`{"category":"timeout","message":"slow"}`), &detail); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var timeoutErr *TimeoutError
+			if !errors.As(&detail, &timeoutErr) {
+				t.Error("decoded timeout does not unwrap to a TimeoutError")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestErrorDetail_LegacyForms(t *testing.T) {
+	var fromMessage ErrorDetail
+	if err := json.Unmarshal([]byte(`"boom"`), &fromMessage); err != nil || fromMessage.Message != "boom" || fromMessage.Category != ErrorCategoryUnknown {
+		t.Errorf("bare message = %+v (err %v)", fromMessage, err)
+	}
+	var opaque ErrorDetail
+	if err := json.Unmarshal([]byte(`{}`), &opaque); err != nil || opaque.Message != "" {
+		t.Errorf("opaque object = %+v (err %v)", opaque, err)
+	}
+	if err := json.Unmarshal([]byte(`42`), &opaque); err == nil {
+		t.Error("a number decoded as an ErrorDetail")
+	}
+}
+
+func TestRepositoryReport_Err(t *testing.T) {
+	var rr RepositoryReport
+	if rr.Err() != nil || rr.ErrorCategory() != ErrorCategoryNone {
+		t.Errorf("successful repository Err() = %v, category %q", rr.Err(), rr.ErrorCategory())
+	}
+	rr.Error = NewErrorDetail(errors.New("boom"))
+	if rr.Err() == nil || rr.ErrorCategory() != ErrorCategoryUnknown || rr.Error.Retryable {
+		t.Errorf("failed repository Err() = %v, detail %+v", rr.Err(), rr.Error)
+	}
+}
+
+func TestGenerate_AnonymousPrivateRepository(t *testing.T) {
+	store := memory.NewStore()
+	store.AddRepository("acme", "public", memory.Files{"README.md": "no lock files"})
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) { return memory.NewClient(store), nil }
+	var events []RepositoryEvent
+	var mu sync.Mutex
+	gen.SetObserver(func(ev RepositoryEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+	})
+	repo := func(name, token string) config.RepoWithProvider {
+		return config.RepoWithProvider{Provider: memory.Provider, Config: config.RepoConfig{
+			Owner: "acme", Repository: name, Ref: "main", Analyzer: "poetry", Token: token,
+			Paths: []string{"poetry.lock"}, Packages: []string{"django"},
+		}}
+	}
+	rpt, err := gen.Generate(context.Background(), []config.RepoWithProvider{
+		repo("secret", ""), repo("hidden", "t0ken"), repo("public", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anonymous, withToken, public := rpt.Repositories[0], rpt.Repositories[1], rpt.Repositories[2]
+	if !anonymous.Anonymous || withToken.Anonymous || !public.Anonymous {
+		t.Errorf("Anonymous = %v, %v, %v; want true, false, true", anonymous.Anonymous, withToken.Anonymous, public.Anonymous)
+	}
+	if got := anonymous.ErrorCategory(); got != ErrorCategoryPrivate {
+		t.Errorf("anonymous missing repository category = %q, want %q", got, ErrorCategoryPrivate)
+	}
+	if !strings.Contains(anonymous.Error.Message, "repository private or token missing") || anonymous.Error.Hint == "" {
+		t.Errorf("private error = %+v, want the explanation and a hint", anonymous.Error)
+	}
+	if got := withToken.ErrorCategory(); got != ErrorCategoryNotFound {
+		t.Errorf("missing repository with a token category = %q, want %q", got, ErrorCategoryNotFound)
+	}
+	if got := public.ErrorCategory(); got != ErrorCategoryNotFound {
+		t.Errorf("file missing from a public repository category = %q, want %q", got, ErrorCategoryNotFound)
+	}
+
+	for _, ev := range events {
+		if want := ev.Key != withToken.Key(); ev.Anonymous != want {
+			t.Errorf("event %+v: Anonymous = %v, want %v", ev, ev.Anonymous, want)
+		}
+	}
+}
@@ -1,13 +1,17 @@
-// Package format provides console rendering utilities for dependency reports.
-// It adapts column widths to the terminal and supports color and truncation.
+// Package format provides rendering utilities for dependency reports: a
+// console table that adapts column widths to the terminal and supports color
+// and truncation, and a self-contained HTML page.
 package format
 
 import (
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/report"
@@ -27,6 +31,15 @@ type ConsoleFormatter struct {
 	MaxPackageColWidth int
 	// EnableColors toggles ANSI color output for status cells.
 	EnableColors bool
+	// Columns selects the package columns to show, in this order. Empty shows
+	// every tracked package sorted by name.
+	Columns []string
+	// NoSummary omits the "Summary" section.
+	NoSummary bool
+	// Quiet writes porcelain output instead of the table and sections: one
+	// tab-separated "repository package version" line per cell, with "-"
+	// for packages not locked and "ERROR" for failed repositories.
+	Quiet bool
 }
 
 // NewConsoleFormatter creates a formatter with sensible defaults.
@@ -44,6 +57,105 @@ func (f *ConsoleFormatter) Render(rpt *report.Report, writer io.Writer) error {
 		return fmt.Errorf("nil report")
 	}
 
+	pkgs, err := f.PackageColumns(rpt)
+	if err != nil {
+		return err
+	}
+	if f.Quiet {
+		return f.renderPorcelain(rpt, pkgs, writer)
+	}
+
+	// Reports spanning several ecosystems get one table per ecosystem, so
+	// each package column sits with the repositories that can lock it
+	groups := rpt.EcosystemGroups()
+	if len(groups) < 2 {
+		f.renderTable(rpt, pkgs, writer)
+	} else {
+		printed := false
+		for _, g := range groups {
+			view := rpt.ForEcosystem(g.Ecosystem)
+			groupPkgs := make([]string, 0, len(pkgs))
+			for _, pkg := range pkgs {
+				if slices.Contains(view.Packages, pkg) {
+					groupPkgs = append(groupPkgs, pkg)
+				}
+			}
+			if len(groupPkgs) == 0 && len(f.Columns) > 0 {
+				continue
+			}
+			if printed {
+				if _, err := fmt.Fprintln(writer); err != nil {
+					return fmt.Errorf("failed writing ecosystem spacer newline: %w", err)
+				}
+			}
+			printed = true
+			if _, err := fmt.Fprintf(writer, "%s:\n", report.EcosystemLabel(g.Ecosystem)); err != nil {
+				return fmt.Errorf("failed writing ecosystem header: %w", err)
+			}
+			f.renderTable(view, groupPkgs, writer)
+		}
+	}
+
+	if !f.NoSummary {
+		if err := f.renderSummary(rpt, writer); err != nil {
+			return err
+		}
+	}
+
+	if rpt.HasErrors() {
+		if _, err := fmt.Fprintln(writer); err != nil {
+			return fmt.Errorf("failed writing errors spacer newline: %w", err)
+		}
+		if _, err := fmt.Fprintf(writer, "Errors:\n"); err != nil {
+			return fmt.Errorf("failed writing errors header: %w", err)
+		}
+		for _, rr := range rpt.Repositories {
+			if rr.Error != nil {
+				name := rpt.RepoLabel(&rr)
+				category := rr.ErrorCategory()
+				label := f.color(fmt.Sprintf("%-12s", "["+string(category)+"]"), errorCategoryColor(category))
+				if _, err := fmt.Fprintf(writer, "  %-30s %s %v\n", name, label, rr.Error); err != nil {
+					return fmt.Errorf("failed writing error line for %s: %w", name, err)
+				}
+			}
+		}
+		hinted := make(map[report.ErrorCategory]bool)
+		for _, rr := range rpt.Repositories {
+			category := rr.ErrorCategory()
+			if category.Hint() == "" || hinted[category] {
+				continue
+			}
+			hinted[category] = true
+			if _, err := fmt.Fprintf(writer, "  Hint [%s]: %s\n", category, category.Hint()); err != nil {
+				return fmt.Errorf("failed writing error hint: %w", err)
+			}
+		}
+	}
+
+	if err := f.renderRefComparisons(rpt, writer); err != nil {
+		return err
+	}
+	if err := f.renderViolations(rpt, writer); err != nil {
+		return err
+	}
+	if err := f.renderUpdatePullRequests(rpt, writer, time.Now()); err != nil {
+		return err
+	}
+	if err := f.renderIgnored(rpt, writer); err != nil {
+		return err
+	}
+	if err := f.renderLockedNames(rpt, writer); err != nil {
+		return err
+	}
+	if err := f.renderUnhashed(rpt, writer); err != nil {
+		return err
+	}
+	return f.renderSuppressed(rpt, writer)
+}
+
+// renderTable writes the version table: one row per repository of rpt and
+// one column per package of pkgs
+func (f *ConsoleFormatter) renderTable(rpt *report.Report, pkgs []string, writer io.Writer) {
 	tw := table.NewWriter()
 	tw.SetOutputMirror(writer)
 	tw.SetStyle(table.StyleRounded)
@@ -52,8 +164,6 @@ func (f *ConsoleFormatter) Render(rpt *report.Report, writer io.Writer) error {
 	tw.Style().Options.DrawBorder = true
 
 	// Header row: Repository + each package
-	pkgs := append([]string(nil), rpt.Packages...)
-	sort.Strings(pkgs)
 	header := table.Row{"Repository"}
 	for _, pkg := range pkgs {
 		header = append(header, pkg)
@@ -66,19 +176,32 @@ func (f *ConsoleFormatter) Render(rpt *report.Report, writer io.Writer) error {
 		tw.SetColumnConfigs(colConfigs)
 	}
 
-	// Rows: each repository with versions per package
-	for _, repo := range rpt.Repositories {
-		row := table.Row{repo.GetRepoIdentifier()}
+	// Rows: each repository with versions per package; versions behind the
+	// newest one in use are highlighted and those suppressed by ignore rules
+	// dimmed
+	m := rpt.Matrix()
+	for i := range rpt.Repositories {
+		repo := &rpt.Repositories[i]
+		row := table.Row{m.Label(i)}
 		for _, pkg := range pkgs {
-			row = append(row, f.versionCell(&repo, pkg))
+			cell := f.versionCell(repo, pkg)
+			if repo.Suppressed(pkg) {
+				cell = f.color(cell, text.FgHiBlack)
+			} else if repo.Error == nil && m.IsOutdated(pkg, m.Version(i, pkg)) {
+				cell = f.color(cell, text.FgYellow)
+			}
+			row = append(row, cell)
 		}
 		tw.AppendRow(row)
 	}
 
-	// Render the table
 	tw.Render()
+}
 
-	// Summary / errors
+// renderSummary writes the "Summary" section: analyzed repositories, tracked
+// packages, repositories whose files lock a package at several versions and
+// packages with version drift
+func (f *ConsoleFormatter) renderSummary(rpt *report.Report, writer io.Writer) error {
 	successCount := 0
 	for _, rr := range rpt.Repositories {
 		if rr.Error == nil {
@@ -98,36 +221,352 @@ func (f *ConsoleFormatter) Render(rpt *report.Report, writer io.Writer) error {
 	if _, err := fmt.Fprintf(writer, "  Packages tracked: %d\n", len(rpt.Packages)); err != nil {
 		return fmt.Errorf("failed writing packages tracked line: %w", err)
 	}
-
-	if rpt.HasErrors() {
-		if _, err := fmt.Fprintln(writer); err != nil {
-			return fmt.Errorf("failed writing errors spacer newline: %w", err)
+	constraintOnly := 0
+	for _, rr := range rpt.Repositories {
+		if rr.Error == nil && rr.ConstraintOnly {
+			constraintOnly++
 		}
-		if _, err := fmt.Fprintf(writer, "Errors:\n"); err != nil {
-			return fmt.Errorf("failed writing errors header: %w", err)
+	}
+	if constraintOnly > 0 {
+		if _, err := fmt.Fprintf(writer, "  Constraint-only repositories (no lock file): %d\n", constraintOnly); err != nil {
+			return fmt.Errorf("failed writing constraint-only line: %w", err)
+		}
+	}
+	anonymous := 0
+	for _, rr := range rpt.Repositories {
+		if rr.Anonymous {
+			anonymous++
+		}
+	}
+	if anonymous > 0 {
+		if _, err := fmt.Fprintf(writer, "  Repositories accessed anonymously (no token): %d\n", anonymous); err != nil {
+			return fmt.Errorf("failed writing anonymous access line: %w", err)
+		}
+	}
+	mirrors, outOfSync := 0, 0
+	for _, rr := range rpt.Repositories {
+		for _, m := range rr.Mirrors {
+			mirrors++
+			if len(m.OutOfSync) > 0 {
+				outOfSync++
+			}
+		}
+	}
+	if mirrors > 0 {
+		if _, err := fmt.Fprintf(writer, "  Mirrors merged into other repositories: %d (%d out of sync)\n", mirrors, outOfSync); err != nil {
+			return fmt.Errorf("failed writing mirror line: %w", err)
+		}
+	}
+	inconsistent := 0
+	for _, rr := range rpt.Repositories {
+		if rr.Error == nil && len(rr.InconsistentPackages()) > 0 {
+			inconsistent++
+		}
+	}
+	if inconsistent > 0 {
+		if _, err := fmt.Fprintf(writer, "  Repositories with internal inconsistencies: %d\n", inconsistent); err != nil {
+			return fmt.Errorf("failed writing internal inconsistency line: %w", err)
 		}
 		for _, rr := range rpt.Repositories {
-			if rr.Error != nil {
-				name := rr.GetRepoIdentifier()
-				if _, err := fmt.Fprintf(writer, "  %-30s %v\n", name, rr.Error); err != nil {
-					return fmt.Errorf("failed writing error line for %s: %w", name, err)
+			pkgs := rr.InconsistentPackages()
+			if rr.Error != nil || len(pkgs) == 0 {
+				continue
+			}
+			if _, err := fmt.Fprintf(writer, "    %s: %s\n", rpt.RepoLabel(&rr), strings.Join(pkgs, ", ")); err != nil {
+				return fmt.Errorf("failed writing internal inconsistency line: %w", err)
+			}
+		}
+	}
+	drift := 0
+	m := rpt.Matrix()
+	for _, pkg := range rpt.Packages {
+		if m.HasDrift(pkg) {
+			drift++
+		}
+	}
+	if drift > 0 {
+		if _, err := fmt.Fprintf(writer, "  Packages with version drift: %d\n", drift); err != nil {
+			return fmt.Errorf("failed writing version drift line: %w", err)
+		}
+	}
+	return nil
+}
+
+// PackageColumns returns the packages shown as columns: f.Columns resolved
+// to the report's spelling, or every tracked package sorted
+func (f *ConsoleFormatter) PackageColumns(rpt *report.Report) ([]string, error) {
+	if len(f.Columns) == 0 {
+		pkgs := append([]string(nil), rpt.Packages...)
+		sort.Strings(pkgs)
+		return pkgs, nil
+	}
+	tracked := make(map[string]bool, len(rpt.Packages))
+	for _, pkg := range rpt.Packages {
+		tracked[pkg] = true
+	}
+	pkgs := make([]string, 0, len(f.Columns))
+	for _, col := range f.Columns {
+		pkg := rpt.ResolvePackage(strings.TrimSpace(col))
+		if !tracked[pkg] {
+			return nil, fmt.Errorf("unknown package column %q (tracked: %s)", col, strings.Join(rpt.Packages, ", "))
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// renderPorcelain writes one "repository<TAB>package<TAB>version" line per
+// repository and package for scripts
+func (f *ConsoleFormatter) renderPorcelain(rpt *report.Report, pkgs []string, writer io.Writer) error {
+	for _, repo := range rpt.Repositories {
+		name := rpt.RepoLabel(&repo)
+		for _, pkg := range pkgs {
+			ver := repo.Dependencies[pkg]
+			switch {
+			case repo.Error != nil:
+				ver = "ERROR"
+			case ver == "":
+				ver = "-"
+			}
+			if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\n", name, pkg, ver); err != nil {
+				return fmt.Errorf("failed writing porcelain line for %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderViolations writes the "Policy violations" section, one line per
+// locked package breaking a configured policy. Nothing is written when the
+// report has no violations.
+func (f *ConsoleFormatter) renderViolations(rpt *report.Report, writer io.Writer) error {
+	violations := rpt.Violations()
+	if len(violations) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(writer, "\nPolicy violations:\n"); err != nil {
+		return fmt.Errorf("failed writing policy violations header: %w", err)
+	}
+	for _, v := range violations {
+		color := text.FgRed
+		if v.Severity == report.SeverityWarning {
+			color = text.FgYellow
+		}
+		if v.Suppressed() {
+			color = text.FgHiBlack
+		}
+		label := f.color(fmt.Sprintf("%-10s", "["+v.Severity+"]"), color)
+		line := fmt.Sprintf("  %-30s %s %s: %s (%s)", v.Repository, label, v.Policy, v.Message, v.File)
+		if note := report.IgnoreNote(v.Ignored); note != "" {
+			line += " " + f.color("["+note+"]", text.FgHiBlack)
+		}
+		if _, err := fmt.Fprintln(writer, line); err != nil {
+			return fmt.Errorf("failed writing policy violation line for %s: %w", v.Repository, err)
+		}
+	}
+	return nil
+}
+
+// renderIgnored writes the "Ignored" section listing tracked package
+// versions matched by ignore rules with their reasons. Nothing is written
+// when no rule matched a version.
+func (f *ConsoleFormatter) renderIgnored(rpt *report.Report, writer io.Writer) error {
+	header := false
+	for _, rr := range rpt.Repositories {
+		for _, pkg := range slices.Sorted(maps.Keys(rr.Ignored)) {
+			if !header {
+				if _, err := fmt.Fprintf(writer, "\nIgnored:\n"); err != nil {
+					return fmt.Errorf("failed writing ignored header: %w", err)
+				}
+				header = true
+			}
+			ig := rr.Ignored[pkg]
+			line := fmt.Sprintf("  %-30s %-20s %s", rpt.RepoLabel(&rr), pkg+" "+rr.Dependencies[pkg], f.color("["+report.IgnoreNote(&ig)+"]", text.FgHiBlack))
+			if _, err := fmt.Fprintln(writer, line); err != nil {
+				return fmt.Errorf("failed writing ignored line for %s: %w", rr.Key(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderLockedNames writes the "Aliased packages" section listing tracked
+// packages found under a former name, with the name locked. Nothing is
+// written when no repository locks a former name.
+func (f *ConsoleFormatter) renderLockedNames(rpt *report.Report, writer io.Writer) error {
+	header := false
+	for _, rr := range rpt.Repositories {
+		for _, pkg := range slices.Sorted(maps.Keys(rr.LockedNames)) {
+			if !header {
+				if _, err := fmt.Fprintf(writer, "\nAliased packages:\n"); err != nil {
+					return fmt.Errorf("failed writing aliased packages header: %w", err)
 				}
+				header = true
+			}
+			if _, err := fmt.Fprintf(writer, "  %-30s %s locked as %s\n", rpt.RepoLabel(&rr), pkg, rr.LockedNames[pkg]); err != nil {
+				return fmt.Errorf("failed writing aliased package line for %s: %w", rr.Key(), err)
 			}
 		}
 	}
+	return nil
+}
 
+// renderUnhashed writes the "Without artifact hashes" section listing tracked
+// packages whose lock file pins no digest. Nothing is written when the
+// report does not include hashes or every package is hashed.
+func (f *ConsoleFormatter) renderUnhashed(rpt *report.Report, writer io.Writer) error {
+	header := false
+	for _, rr := range rpt.Repositories {
+		unhashed := rr.Unhashed()
+		if len(unhashed) == 0 {
+			continue
+		}
+		if !header {
+			if _, err := fmt.Fprintf(writer, "\nWithout artifact hashes:\n"); err != nil {
+				return fmt.Errorf("failed writing unhashed header: %w", err)
+			}
+			header = true
+		}
+		if _, err := fmt.Fprintf(writer, "  %-30s %s\n", rpt.RepoLabel(&rr), strings.Join(unhashed, ", ")); err != nil {
+			return fmt.Errorf("failed writing unhashed line for %s: %w", rr.Key(), err)
+		}
+	}
 	return nil
 }
 
-// versionCell returns the string (with optional color) for a repository/package cell.
+// renderSuppressed writes the "Suppressed by hooks" section listing
+// repositories and packages removed by report hooks, so suppressions stay
+// visible. Nothing is written when no hook suppressed anything.
+func (f *ConsoleFormatter) renderSuppressed(rpt *report.Report, writer io.Writer) error {
+	if len(rpt.Suppressed) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(writer, "\nSuppressed by hooks:\n"); err != nil {
+		return fmt.Errorf("failed writing suppressed header: %w", err)
+	}
+	for _, s := range rpt.Suppressed {
+		target := s.Package
+		if target == "" {
+			target = "(repository)"
+		}
+		line := fmt.Sprintf("  %-30s %-20s %s", s.Repository, target, s.Reason)
+		if s.Hook != "" {
+			line += " " + f.color("["+s.Hook+"]", text.FgHiBlack)
+		}
+		if _, err := fmt.Fprintln(writer, strings.TrimRight(line, " ")); err != nil {
+			return fmt.Errorf("failed writing suppressed line for %s: %w", s.Repository, err)
+		}
+	}
+	return nil
+}
+
+// renderRefComparisons writes the "Ref comparison" section listing, for each
+// repository analyzed at several refs, the packages whose versions differ
+// between them. Nothing is written when no repository has several refs.
+func (f *ConsoleFormatter) renderRefComparisons(rpt *report.Report, writer io.Writer) error {
+	comparisons := rpt.RefComparisons()
+	if len(comparisons) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(writer, "\nRef comparison:\n"); err != nil {
+		return fmt.Errorf("failed writing ref comparison header: %w", err)
+	}
+	for _, c := range comparisons {
+		diffs := c.Differences()
+		name := fmt.Sprintf("%s/%s", c.Owner, c.Repository)
+		line := fmt.Sprintf("  %s (%s): %d of %d packages differ", name, strings.Join(c.Refs, " vs "), len(diffs), len(c.Packages))
+		if _, err := fmt.Fprintln(writer, line); err != nil {
+			return fmt.Errorf("failed writing ref comparison for %s: %w", name, err)
+		}
+		for _, p := range diffs {
+			versions := make([]string, 0, len(c.Refs))
+			for _, ref := range c.Refs {
+				v := p.Versions[ref]
+				if v == "" {
+					v = "-"
+				}
+				versions = append(versions, ref+"="+v)
+			}
+			line := fmt.Sprintf("    %-24s %s", p.Package, f.color(strings.Join(versions, "  "), text.FgYellow))
+			if _, err := fmt.Fprintln(writer, line); err != nil {
+				return fmt.Errorf("failed writing ref comparison line for %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderUpdatePullRequests writes the "Open update PRs" section listing open
+// Dependabot/Renovate PRs per repository and tracked package. Nothing is
+// written when no repository carries update PR annotations.
+func (f *ConsoleFormatter) renderUpdatePullRequests(rpt *report.Report, writer io.Writer, now time.Time) error {
+	header := false
+	for _, rr := range rpt.Repositories {
+		if len(rr.UpdatePullRequests) == 0 {
+			continue
+		}
+		if !header {
+			if _, err := fmt.Fprintf(writer, "\nOpen update PRs:\n"); err != nil {
+				return fmt.Errorf("failed writing update PRs header: %w", err)
+			}
+			header = true
+		}
+		pkgs := make([]string, 0, len(rr.UpdatePullRequests))
+		for pkg := range rr.UpdatePullRequests {
+			pkgs = append(pkgs, pkg)
+		}
+		sort.Strings(pkgs)
+		name := rpt.RepoLabel(&rr)
+		for _, pkg := range pkgs {
+			pr := rr.UpdatePullRequests[pkg]
+			days := pr.OpenDays(now)
+			line := fmt.Sprintf("update PR open since %d days", days)
+			if days == 1 {
+				line = "update PR open since 1 day"
+			}
+			if days >= 30 {
+				line = f.color(line, text.FgYellow)
+			}
+			if _, err := fmt.Fprintf(writer, "  %-30s %-20s #%d %s\n", name, pkg, pr.Number, line); err != nil {
+				return fmt.Errorf("failed writing update PR line for %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// errorCategoryColor maps an error category to the color used in the errors section.
+func errorCategoryColor(category report.ErrorCategory) text.Color {
+	switch category {
+	case report.ErrorCategoryAuth, report.ErrorCategoryPrivate:
+		return text.FgMagenta
+	case report.ErrorCategoryRateLimit, report.ErrorCategoryTimeout, report.ErrorCategoryBudget:
+		return text.FgYellow
+	case report.ErrorCategoryNotFound:
+		return text.FgCyan
+	case report.ErrorCategoryParse, report.ErrorCategoryConfig:
+		return text.FgBlue
+	default:
+		return text.FgRed
+	}
+}
+
+// versionCell returns the string (with optional color) for a repository/package cell;
+// constraint-only repositories show the declared constraint in parentheses.
 func (f *ConsoleFormatter) versionCell(repo *report.RepositoryReport, pkg string) string {
 	if repo.Error != nil {
 		return f.color("ERROR", text.FgRed)
 	}
 	ver, ok := repo.Dependencies[pkg]
+	if constraint := repo.Constraints[pkg]; ver == "" && repo.ConstraintOnly && constraint != "" {
+		return f.color("("+constraint+")", text.FgCyan)
+	}
 	if !ok || ver == "" {
 		return f.color("—", text.FgHiBlack)
 	}
+	if repo.DevOnly[pkg] {
+		return ver + f.color(" [dev]", text.FgHiBlack)
+	}
 	return ver
 }
 
@@ -175,7 +614,7 @@ func (f *ConsoleFormatter) buildColumnConfig(rpt *report.Report, w io.Writer, pk
 			Number:      1,
 			WidthMax:    repoIDColWidth,
 			WidthMin:    minInt(10, repoIDColWidth),
-			Transformer: truncTransformer(repoIDColWidth),
+			Transformer: truncMiddleTransformer(repoIDColWidth),
 		},
 	}
 
@@ -198,7 +637,7 @@ func dynamicRepoIDWidth(rpt *report.Report, termWidth, packageCount int) int {
 	}
 	maxLen := 0
 	for _, r := range rpt.Repositories {
-		id := r.GetRepoIdentifier()
+		id := rpt.RepoLabel(&r)
 		l := utf8.RuneCountInString(id)
 		if l > maxLen {
 			maxLen = l
@@ -247,6 +686,30 @@ func truncTransformer(maxLen int) text.Transformer {
 	}
 }
 
+// truncMiddleTransformer returns a text.Transformer that ellipsizes overly
+// wide cells in the middle, keeping both the owner and the repository name
+// of identifiers recognizable.
+func truncMiddleTransformer(maxLen int) text.Transformer {
+	return func(val interface{}) string {
+		return truncateMiddle(fmt.Sprint(val), maxLen)
+	}
+}
+
+// truncateMiddle shortens a string to (max) runes by replacing its middle
+// with an ellipsis
+func truncateMiddle(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	if maxLen <= 1 {
+		return truncateRunes(s, maxLen)
+	}
+	tail := (maxLen - 1) / 2
+	head := maxLen - 1 - tail
+	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+}
+
 // truncateRunes truncates a string to (max) runes with ellipsis.
 func truncateRunes(s string, maxLen int) string {
 	if maxLen <= 0 {
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
 	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
 )
 
 // helper to build a sample report
@@ -27,7 +30,7 @@ func sampleReport() *report.Report {
 				Analyzer:     "poetry",
 				Dependencies: map[string]string{"pkgA": "1.2.4"},
 				// Repo has an error -> all cells should render as ERROR regardless of dependency map.
-				Error: assertError("dependency scan failed"),
+				Error: report.NewErrorDetail(assertError("dependency scan failed")),
 			},
 		},
 		Packages: []string{"pkgA", "pkgB"},
@@ -39,6 +42,39 @@ type assertError string
 
 func (e assertError) Error() string { return string(e) }
 
+func TestConsoleFormatterAnonymousAccess(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[1].Anonymous = true
+	rpt.Repositories[1].Error = report.NewErrorDetail(&report.PrivateRepositoryError{Err: assertError("repository private or token missing: 404 Not Found")})
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "Repositories accessed anonymously (no token): 1", "anonymous access summary missing")
+	expectContains(t, out, "[private]", "private error category label missing")
+	expectContains(t, out, "Hint [private]: "+report.ErrorCategoryPrivate.Hint(), "private repository hint missing")
+}
+
+func TestConsoleFormatterMirrors(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].Mirrors = []report.Mirror{
+		{Provider: "gitlab", Owner: "mirrors", Repository: "repo1", Ref: "main"},
+		{Provider: "gitlab", Owner: "backup", Repository: "repo1", Ref: "main", OutOfSync: []string{"pkgA"}},
+	}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	expectContains(t, buf.String(), "Mirrors merged into other repositories: 2 (1 out of sync)", "mirror summary missing")
+}
+
 func TestConsoleFormatterBasicRender(t *testing.T) {
 	rpt := sampleReport()
 
@@ -67,6 +103,7 @@ func TestConsoleFormatterBasicRender(t *testing.T) {
 	expectContains(t, out, "Errors:", "errors section header missing")
 	expectContains(t, out, "org2/repo2", "errored repository identifier missing")
 	expectContains(t, out, "dependency scan failed", "error message missing")
+	expectContains(t, out, "[unknown]", "error category label missing")
 
 	// Ensure no ANSI escapes when colors disabled
 	if strings.Contains(out, "\x1b[") {
@@ -135,3 +172,341 @@ func stripANSI(s string) string {
 	}
 	return b.String()
 }
+
+func TestConsoleFormatterUpdatePullRequests(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rpt := &report.Report{
+		Repositories: []report.RepositoryReport{
+			{
+				Owner:        "org1",
+				Repository:   "repo1",
+				Dependencies: map[string]string{"pkgA": "1.0.0"},
+				UpdatePullRequests: map[string]repository.UpdatePullRequest{
+					"pkgA": {Number: 42, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+				},
+			},
+		},
+		Packages: []string{"pkgA"},
+	}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.renderUpdatePullRequests(rpt, &buf, now); err != nil {
+		t.Fatalf("renderUpdatePullRequests returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "Open update PRs:", "update PR header missing")
+	expectContains(t, out, "#42 update PR open since 10 days", "update PR annotation missing")
+
+	// No annotations -> no section
+	buf.Reset()
+	if err := f.renderUpdatePullRequests(sampleReport(), &buf, now); err != nil {
+		t.Fatalf("renderUpdatePullRequests returned error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without annotations, got %q", buf.String())
+	}
+}
+
+func TestConsoleFormatterSuppressed(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Suppressed = []report.Suppression{
+		{Repository: "github:org1/repo9@main", Reason: "archived", Hook: "cmdb"},
+		{Repository: "github:org1/repo1@main", Package: "pkgA", Reason: "vendored fork"},
+	}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "Suppressed by hooks:", "suppressed header missing")
+	expectContains(t, out, "(repository)", "repository suppression missing")
+	expectContains(t, out, "archived [cmdb]", "suppression hook label missing")
+	expectContains(t, out, "vendored fork", "package suppression missing")
+
+	buf.Reset()
+	if err := f.Render(sampleReport(), &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "Suppressed by hooks:") {
+		t.Error("expected no suppressed section without suppressions")
+	}
+}
+
+func TestConsoleFormatterVersionDrift(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[1].Error = nil
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	expectContains(t, buf.String(), "Packages with version drift: 1", "drift summary missing")
+
+	buf.Reset()
+	if err := f.Render(sampleReport(), &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "version drift") {
+		t.Error("single successful version should not report drift")
+	}
+}
+
+func TestConsoleFormatterRefComparison(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[1] = report.RepositoryReport{
+		Provider:     "github",
+		Owner:        "org1",
+		Repository:   "repo1",
+		Ref:          "release/1.x",
+		Analyzer:     "poetry",
+		Dependencies: map[string]string{"pkgA": "1.0.0", "pkgB": "4.5.6"},
+	}
+	rpt.Repositories[0].Ref = "main"
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "org1/repo1@release/1.x", "rows should name the ref")
+	expectContains(t, out, "org1/repo1 (main vs release/1.x): 1 of 2 packages differ", "comparison header missing")
+	expectContains(t, out, "main=1.2.3  release/1.x=1.0.0", "differing versions missing")
+	if strings.Contains(out, "main=4.5.6") {
+		t.Errorf("identical package listed as a difference:\n%s", out)
+	}
+}
+
+func TestConsoleFormatterConstraintOnly(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[1].Error = nil
+	rpt.Repositories[1].ConstraintOnly = true
+	rpt.Repositories[1].Dependencies = map[string]string{"pkgA": ""}
+	rpt.Repositories[1].Constraints = map[string]string{"pkgA": ">=1.2"}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	expectContains(t, buf.String(), "(>=1.2)", "declared constraint missing")
+	expectContains(t, buf.String(), "Constraint-only repositories (no lock file): 1", "constraint-only summary missing")
+}
+
+func TestConsoleFormatterInternalInconsistency(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].FileVersions = map[string]map[string]string{
+		"pkgA": {"a/poetry.lock": "1.2.3", "b/poetry.lock": "1.0.0"},
+	}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "Repositories with internal inconsistencies: 1", "inconsistency summary missing")
+	expectContains(t, out, "    org1/repo1: pkgA\n", "inconsistent package line missing")
+
+	buf.Reset()
+	if err := f.Render(sampleReport(), &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "internal inconsistencies") {
+		t.Error("expected no inconsistency line when files agree")
+	}
+}
+
+func TestConsoleFormatterIgnored(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].Ignored = map[string]report.Ignore{"pkgA": {Reason: "pinned until Q3", Suppressed: true}}
+	rpt.Repositories[0].Violations = []report.Violation{
+		{Policy: "lts", Severity: report.SeverityError, Repository: "github:org1/repo1@main", File: "poetry.lock", Package: "pkgA", Message: "too old",
+			Ignored: &report.Ignore{Reason: "pinned until Q3", Suppressed: true}},
+	}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "lts: too old (poetry.lock) [ignored: pinned until Q3]", "ignored violation note missing")
+	expectContains(t, out, "Ignored:", "ignored header missing")
+	expectContains(t, out, "pkgA 1.2.3", "ignored version missing")
+}
+
+func TestConsoleFormatterLockedNames(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].LockedNames = map[string]string{"pkgA": "pkgA-legacy"}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	expectContains(t, buf.String(), "Aliased packages:", "aliased packages header missing")
+	expectContains(t, buf.String(), "pkgA locked as pkgA-legacy", "locked name missing")
+}
+
+func TestConsoleFormatterDevOnly(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].DevOnly = map[string]bool{"pkgB": true}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	expectContains(t, buf.String(), "4.5.6 [dev]", "dev-only marker missing")
+	if strings.Contains(buf.String(), "1.2.3 [dev]") {
+		t.Error("runtime package marked as dev only")
+	}
+}
+
+func TestConsoleFormatterUnhashed(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].Hashes = map[string][]string{"pkgA": {}, "pkgB": {"sha256:abc"}}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	expectContains(t, buf.String(), "Without artifact hashes:", "unhashed header missing")
+	if strings.Contains(buf.String(), "pkgA, pkgB") {
+		t.Error("hashed package listed as unhashed")
+	}
+}
+
+func TestConsoleFormatterViolations(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].Violations = []report.Violation{
+		{Policy: "django-lts", Severity: report.SeverityError, Repository: "github:org1/repo1@main", File: "poetry.lock", Package: "django", Message: "django 3.2.25 does not satisfy >=4.2"},
+	}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "Policy violations:", "violations header missing")
+	expectContains(t, out, "[error]    django-lts: django 3.2.25 does not satisfy >=4.2 (poetry.lock)", "violation line missing")
+
+	buf.Reset()
+	if err := f.Render(sampleReport(), &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "Policy violations:") {
+		t.Error("expected no violations section without violations")
+	}
+}
+
+func TestConsoleFormatterColumnsAndSummary(t *testing.T) {
+	var buf bytes.Buffer
+	f := &ConsoleFormatter{Columns: []string{"pkgB"}, NoSummary: true}
+	if err := f.Render(sampleReport(), &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "PKGB", "selected column missing")
+	if strings.Contains(out, "PKGA") || strings.Contains(out, "1.2.3") {
+		t.Errorf("unselected column pkgA rendered:\n%s", out)
+	}
+	if strings.Contains(out, "Summary:") {
+		t.Errorf("summary rendered with NoSummary:\n%s", out)
+	}
+	expectContains(t, out, "Errors:", "errors section should remain")
+
+	f.Columns = []string{"pkgC"}
+	if err := f.Render(sampleReport(), &buf); err == nil || !strings.Contains(err.Error(), "unknown package column") {
+		t.Errorf("unknown column: err = %v", err)
+	}
+}
+
+func TestConsoleFormatterQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	f := &ConsoleFormatter{Quiet: true, EnableColors: true, Columns: []string{"pkgB", "pkgA"}}
+	if err := f.Render(sampleReport(), &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	want := "org1/repo1\tpkgB\t4.5.6\n" +
+		"org1/repo1\tpkgA\t1.2.3\n" +
+		"org2/repo2\tpkgB\tERROR\n" +
+		"org2/repo2\tpkgA\tERROR\n"
+	if got := buf.String(); got != want {
+		t.Errorf("porcelain output =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"org/repo", 20, "org/repo"},
+		{"platform-team/payments-reconciliation-service", 21, "platform-t…on-service"},
+		{"abcdef", 1, "…"},
+	}
+	for _, tt := range tests {
+		if got := truncateMiddle(tt.in, tt.maxLen); got != tt.want {
+			t.Errorf("truncateMiddle(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestConsoleFormatterEcosystemSections(t *testing.T) {
+	rpt := &report.Report{
+		Packages:          []string{"django", "https://github.com/psf/black"},
+		PackageEcosystems: map[string]dependencies.Ecosystem{"django": "python", "https://github.com/psf/black": "pre-commit"},
+		Repositories: []report.RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Analyzer: "poetry", Ecosystem: "python", Dependencies: map[string]string{"django": "4.2.11"}},
+			{Provider: "github", Owner: "o", Repository: "hooks", Analyzer: "pre-commit", Ecosystem: "pre-commit", Dependencies: map[string]string{"https://github.com/psf/black": "24.2.0"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	f := NewConsoleFormatter()
+	f.EnableColors = false
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	preCommit, python := strings.Index(out, "pre-commit:\n"), strings.Index(out, "python:\n")
+	if preCommit < 0 || python < preCommit {
+		t.Fatalf("expected a pre-commit section followed by a python section:\n%s", out)
+	}
+	// Each section only lists its own repositories and packages
+	if strings.Contains(out[:python], "o/api") || strings.Contains(out[python:], "o/hooks") {
+		t.Errorf("repositories rendered in the wrong section:\n%s", out)
+	}
+	if strings.Contains(out[python:strings.Index(out, "Summary:")], "black") {
+		t.Errorf("pre-commit package rendered in the python section:\n%s", out)
+	}
+
+	// Single-ecosystem reports keep one table without headings
+	buf.Reset()
+	if err := f.Render(sampleReport(), &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "python:") {
+		t.Errorf("unexpected ecosystem heading:\n%s", buf.String())
+	}
+}
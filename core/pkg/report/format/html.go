@@ -0,0 +1,179 @@
+package format
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// HTMLFormatter renders a dependency Report as a self-contained HTML page
+// (inline styles only, so it survives email clients). Same pivoted layout as
+// the console table, followed by the summary, errors and policy violations.
+type HTMLFormatter struct {
+	// Title is the page heading; "Dependency Report" when empty
+	Title string
+	// Fragment omits the <html>/<body> wrapper so the output can be embedded
+	// in another document (e.g. an email digest)
+	Fragment bool
+	// Now stamps the page; time.Now when zero
+	Now time.Time
+}
+
+// NewHTMLFormatter creates an HTML formatter with default settings.
+func NewHTMLFormatter() *HTMLFormatter {
+	return &HTMLFormatter{}
+}
+
+// htmlCell is one version cell of the table
+type htmlCell struct {
+	Text  string
+	Style template.CSS // Fixed declarations from htmlVersionCell, never report data
+}
+
+// htmlRow is one repository of the table
+type htmlRow struct {
+	Repository string
+	Cells      []htmlCell
+}
+
+// htmlError is one entry of the errors section
+type htmlError struct {
+	Repository string
+	Category   report.ErrorCategory
+	Message    string
+}
+
+// htmlPage is the data passed to htmlTemplate
+type htmlPage struct {
+	Title        string
+	Fragment     bool
+	Generated    string
+	Packages     []string
+	Rows         []htmlRow
+	Successful   int
+	Repositories int
+	Drift        []string
+	Errors       []htmlError
+	Violations   []report.Violation
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`
+{{- if not .Fragment}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: sans-serif; color: #222;">
+{{end -}}
+<h2>{{.Title}}</h2>
+<p style="color: #666;">Generated {{.Generated}}</p>
+<table style="border-collapse: collapse;">
+<tr><th style="text-align: left; border-bottom: 2px solid #999; padding: 4px 8px;">Repository</th>
+{{- range .Packages}}<th style="text-align: left; border-bottom: 2px solid #999; padding: 4px 8px;">{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr><td style="border-bottom: 1px solid #ddd; padding: 4px 8px;">{{.Repository}}</td>
+{{- range .Cells}}<td style="border-bottom: 1px solid #ddd; padding: 4px 8px;{{.Style}}">{{.Text}}</td>{{end}}</tr>
+{{- end}}
+</table>
+<h3>Summary</h3>
+<ul>
+<li>Repositories analyzed: {{.Successful}}/{{.Repositories}} successful</li>
+<li>Packages tracked: {{len .Packages}}</li>
+{{- if .Drift}}
+<li>Packages with version drift: {{len .Drift}} ({{range $i, $p := .Drift}}{{if $i}}, {{end}}{{$p}}{{end}})</li>
+{{- end}}
+</ul>
+{{- if .Errors}}
+<h3>Errors</h3>
+<ul>
+{{- range .Errors}}
+<li><b>{{.Repository}}</b> [{{.Category}}] {{.Message}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Violations}}
+<h3>Policy violations</h3>
+<ul>
+{{- range .Violations}}
+<li{{if .Suppressed}} style="color: #999;"{{end}}><b>{{.Repository}}</b> [{{.Severity}}] {{.Policy}}: {{.Message}} ({{.File}}){{with .Ignored}} [{{if .Suppressed}}ignored{{else}}note{{end}}: {{.Reason}}]{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if not .Fragment}}
+</body></html>
+{{- end}}
+`))
+
+// Render writes the report as HTML to writer.
+func (f *HTMLFormatter) Render(rpt *report.Report, writer io.Writer) error {
+	if rpt == nil {
+		return fmt.Errorf("nil report")
+	}
+	now := f.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	page := htmlPage{
+		Title:        f.Title,
+		Fragment:     f.Fragment,
+		Generated:    now.UTC().Format("2006-01-02 15:04 UTC"),
+		Packages:     append([]string(nil), rpt.Packages...),
+		Repositories: len(rpt.Repositories),
+		Violations:   rpt.Violations(),
+	}
+	if page.Title == "" {
+		page.Title = "Dependency Report"
+	}
+	sort.Strings(page.Packages)
+
+	m := rpt.Matrix()
+	for i := range rpt.Repositories {
+		repo := &rpt.Repositories[i]
+		row := htmlRow{Repository: m.Label(i)}
+		for _, pkg := range page.Packages {
+			row.Cells = append(row.Cells, htmlVersionCell(m, repo, pkg))
+		}
+		page.Rows = append(page.Rows, row)
+		if repo.Error != nil {
+			page.Errors = append(page.Errors, htmlError{Repository: row.Repository, Category: repo.ErrorCategory(), Message: repo.Error.Error()})
+		} else {
+			page.Successful++
+		}
+	}
+	for _, pkg := range rpt.Packages {
+		if m.HasDrift(pkg) {
+			page.Drift = append(page.Drift, pkg)
+		}
+	}
+	sort.Strings(page.Drift)
+
+	if err := htmlTemplate.Execute(writer, page); err != nil {
+		return fmt.Errorf("failed writing HTML report: %w", err)
+	}
+	return nil
+}
+
+// htmlVersionCell mirrors versionCell: errors in red, missing packages grey,
+// versions behind the newest in use highlighted
+func htmlVersionCell(m *report.VersionMatrix, repo *report.RepositoryReport, pkg string) htmlCell {
+	if repo.Error != nil {
+		return htmlCell{Text: "ERROR", Style: " color: #c00;"}
+	}
+	ver := repo.Dependencies[pkg]
+	if constraint := repo.Constraints[pkg]; ver == "" && repo.ConstraintOnly && constraint != "" {
+		return htmlCell{Text: "(" + constraint + ")", Style: " color: #06c;"}
+	}
+	if ver == "" {
+		return htmlCell{Text: "—", Style: " color: #999;"}
+	}
+	if m.IsOutdated(pkg, ver) {
+		return htmlCell{Text: ver, Style: " background: #fff3cd;"}
+	}
+	return htmlCell{Text: ver}
+}
+
+// RenderHTML renders the provided Report to the writer as a standalone HTML page.
+func RenderHTML(rpt *report.Report, w io.Writer) error {
+	return NewHTMLFormatter().Render(rpt, w)
+}
@@ -0,0 +1,53 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestHTMLFormatterRender(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[1].Error = report.NewErrorDetail(assertError("bad <token>"))
+
+	var buf bytes.Buffer
+	if err := NewHTMLFormatter().Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	out := buf.String()
+	expectContains(t, out, "<!DOCTYPE html>", "page wrapper missing")
+	expectContains(t, out, "<th style=\"text-align: left; border-bottom: 2px solid #999; padding: 4px 8px;\">pkgA</th>", "package header missing")
+	expectContains(t, out, ">1.2.3</td>", "version cell missing")
+	expectContains(t, out, "color: #c00;\">ERROR</td>", "error cell missing")
+	expectContains(t, out, "Repositories analyzed: 1/2 successful", "summary missing")
+	expectContains(t, out, "bad &lt;token&gt;", "error message should be escaped")
+
+	buf.Reset()
+	f := &HTMLFormatter{Title: "Digest", Fragment: true}
+	if err := f.Render(rpt, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<html>") || !strings.Contains(buf.String(), "<h2>Digest</h2>") {
+		t.Errorf("unexpected fragment:\n%s", buf.String())
+	}
+	if err := f.Render((*report.Report)(nil), &buf); err == nil {
+		t.Error("expected an error for a nil report")
+	}
+}
+
+func TestHTMLFormatterIgnoredViolation(t *testing.T) {
+	rpt := sampleReport()
+	rpt.Repositories[0].Violations = []report.Violation{
+		{Policy: "lts", Severity: report.SeverityError, Repository: "github:org1/repo1@main", File: "poetry.lock", Message: "too old",
+			Ignored: &report.Ignore{Reason: "pinned until Q3", Suppressed: true}},
+	}
+
+	var buf bytes.Buffer
+	if err := NewHTMLFormatter().Render(rpt, &buf); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	expectContains(t, buf.String(), `<li style="color: #999;"><b>github:org1/repo1@main</b>`, "suppressed violation should be greyed out")
+	expectContains(t, buf.String(), "(poetry.lock) [ignored: pinned until Q3]</li>", "ignore reason missing")
+}
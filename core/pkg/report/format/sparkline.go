@@ -0,0 +1,26 @@
+package format
+
+import "strings"
+
+// sparkBlocks are the bar heights of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a row of block characters scaled between 0 and
+// ceiling (the largest value when ceiling <= 0), so a series of zeros stays
+// flat at the lowest bar. Values outside the range are clamped.
+func Sparkline(values []float64, ceiling float64) string {
+	if ceiling <= 0 {
+		for _, v := range values {
+			ceiling = max(ceiling, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if ceiling > 0 && v > 0 {
+			i = min(int(v/ceiling*float64(len(sparkBlocks)-1)+0.5), len(sparkBlocks)-1)
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package format
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		ceiling float64
+		want    string
+	}{
+		{"scaled to max", []float64{0, 1, 2, 4}, 0, "▁▃▅█"},
+		{"fixed ceiling", []float64{0, 0.5, 1}, 1, "▁▅█"},
+		{"clamped", []float64{-1, 2}, 1, "▁█"},
+		{"all zero", []float64{0, 0}, 0, "▁▁"},
+		{"empty", nil, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.ceiling); got != tt.want {
+				t.Errorf("Sparkline(%v, %v) = %q, want %q", tt.values, tt.ceiling, got, tt.want)
+			}
+		})
+	}
+}
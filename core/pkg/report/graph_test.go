@@ -0,0 +1,60 @@
+package report
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// graphClient serves a poetry.lock whose packages record dependency edges
+type graphClient struct {
+	stubClient
+}
+
+func (c *graphClient) GetFileContent(context.Context, string, string, string, string) (string, error) {
+	return `[[package]]
+name = "requests"
+version = "2.31.0"
+
+[package.dependencies]
+urllib3 = ">=1.21.1,<3"
+
+[[package]]
+name = "urllib3"
+version = "2.0.7"
+`, nil
+}
+
+func TestGenerate_IncludeGraph(t *testing.T) {
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) {
+		return &graphClient{}, nil
+	}
+	repos := []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Analyzer: "poetry", Packages: []string{"urllib3"}}},
+	}
+
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpt.Repositories[0].Graph != nil {
+		t.Fatal("graph included without SetIncludeGraph")
+	}
+
+	gen.SetIncludeGraph(true)
+	rpt, err = gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := rpt.Repositories[0].Graph
+	if g == nil {
+		t.Fatal("expected a graph")
+	}
+	if got := g.Dependents("urllib3", true); !slices.Equal(got, []string{"requests"}) {
+		t.Errorf("Dependents(urllib3) = %v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"slices"
+	"sort"
+)
+
+// SetIncludeHashes makes each RepositoryReport carry the artifact digests
+// its lock files pin for the tracked packages (see RepositoryReport.Hashes),
+// for supply-chain audits
+func (g *Generator) SetIncludeHashes(include bool) {
+	g.hashes = include
+}
+
+// Unhashed returns the sorted tracked packages the repository locks without
+// any artifact digest. Empty unless the report includes hashes.
+func (r *RepositoryReport) Unhashed() []string {
+	var pkgs []string
+	for pkg, hashes := range r.Hashes {
+		if len(hashes) == 0 && r.Dependencies[pkg] != "" {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	slices.Sort(pkgs)
+	return pkgs
+}
+
+// HashCountChange is a tracked package whose number of pinned artifact
+// digests changed while its version did not, e.g. a wheel added to or
+// removed from a release after it was locked
+type HashCountChange struct {
+	Repository string `json:"repository"` // RepositoryReport.Key
+	Package    string `json:"package"`
+	Version    string `json:"version"`
+	Before     int    `json:"before"`
+	After      int    `json:"after"`
+}
+
+// HashCountChanges lists the tracked packages whose digest count differs
+// between prev and next at the same version, sorted by repository and
+// package. Only packages both reports recorded hashes for are compared; a
+// nil prev therefore yields nothing.
+func HashCountChanges(prev, next *Report) []HashCountChange {
+	if prev == nil || next == nil {
+		return nil
+	}
+	before := make(map[string]*RepositoryReport)
+	for i := range prev.Repositories {
+		if prev.Repositories[i].Error == nil {
+			before[prev.Repositories[i].Key()] = &prev.Repositories[i]
+		}
+	}
+
+	var changes []HashCountChange
+	for i := range next.Repositories {
+		rr := &next.Repositories[i]
+		old, ok := before[rr.Key()]
+		if !ok || rr.Error != nil {
+			continue
+		}
+		for pkg, hashes := range rr.Hashes {
+			oldHashes, recorded := old.Hashes[pkg]
+			version := rr.Dependencies[pkg]
+			if !recorded || version == "" || old.Dependencies[pkg] != version || len(oldHashes) == len(hashes) {
+				continue
+			}
+			changes = append(changes, HashCountChange{
+				Repository: rr.Key(),
+				Package:    pkg,
+				Version:    version,
+				Before:     len(oldHashes),
+				After:      len(hashes),
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Repository != changes[j].Repository {
+			return changes[i].Repository < changes[j].Repository
+		}
+		return changes[i].Package < changes[j].Package
+	})
+	return changes
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+func TestGenerate_IncludeHashes(t *testing.T) {
+	repos := []config.RepoWithProvider{
+		{Provider: "fast", Config: config.RepoConfig{Owner: "o", Repository: "fast", Analyzer: "poetry", Packages: []string{"django", "flask"}}},
+	}
+
+	rpt, err := stubGenerator().Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if rpt.Repositories[0].Hashes != nil {
+		t.Errorf("expected no hashes unless included, got %v", rpt.Repositories[0].Hashes)
+	}
+
+	gen := stubGenerator()
+	gen.SetIncludeHashes(true)
+	if rpt, err = gen.Generate(context.Background(), repos); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	rr := rpt.Repositories[0]
+	if hashes, ok := rr.Hashes["django"]; !ok || len(hashes) != 0 {
+		t.Errorf("expected an empty hash list for django, got %v (recorded %v)", hashes, ok)
+	}
+	if got := fmt.Sprint(rr.Unhashed()); got != "[django]" {
+		t.Errorf("Unhashed() = %s, want [django] (flask is not locked)", got)
+	}
+}
+
+func TestHashCountChanges(t *testing.T) {
+	repo := func(version string, hashes map[string][]string) RepositoryReport {
+		return RepositoryReport{Provider: "github", Owner: "o", Repository: "r", Ref: "main",
+			Dependencies: map[string]string{"django": version, "flask": "3.0.0"}, Hashes: hashes}
+	}
+	prev := &Report{Repositories: []RepositoryReport{repo("4.2.0", map[string][]string{"django": {"sha256:a", "sha256:b"}})}}
+
+	tests := []struct {
+		name string
+		next RepositoryReport
+		want string
+	}{
+		{"unchanged", repo("4.2.0", map[string][]string{"django": {"sha256:a", "sha256:c"}}), "[]"},
+		{"count changed", repo("4.2.0", map[string][]string{"django": {"sha256:a"}}), "[{github:o/r@main django 4.2.0 2 1}]"},
+		{"version changed", repo("5.0.0", map[string][]string{"django": {"sha256:a"}}), "[]"},
+		{"not recorded before", repo("4.2.0", map[string][]string{"django": {"sha256:a", "sha256:b"}, "flask": {}}), "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HashCountChanges(prev, &Report{Repositories: []RepositoryReport{tt.next}})
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("HashCountChanges() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+	if HashCountChanges(nil, prev) != nil {
+		t.Error("expected no changes without a previous report")
+	}
+}
+
+func TestAnalysisFingerprint_Hashes(t *testing.T) {
+	repo := config.RepoWithProvider{Config: config.RepoConfig{Analyzer: "poetry", Packages: []string{"django"}}}
+	if (&Generator{hashes: true}).analysisFingerprint(repo) == (&Generator{}).analysisFingerprint(repo) {
+		t.Error("including hashes should change the fingerprint")
+	}
+}
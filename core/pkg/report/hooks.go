@@ -0,0 +1,278 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+// DefaultHookTimeout bounds a single hook run when no timeout is configured
+const DefaultHookTimeout = 30 * time.Second
+
+// Hook post-processes a generated report. Hooks run in registration order once
+// every repository has been analyzed, and each sees the annotations and
+// suppressions applied by the hooks before it. Hooks still run when the run's
+// context has expired, so a truncated report keeps its suppressions; each run
+// is bounded by its own timeout instead. A failing hook is logged and skipped;
+// it never fails the report.
+type Hook interface {
+	// Name identifies the hook in logs
+	Name() string
+
+	// Run inspects the report and returns changes to merge back into it.
+	// A nil result means no changes. Hooks must not modify rpt directly.
+	Run(ctx context.Context, rpt *Report) (*HookResult, error)
+}
+
+// HookResult carries the changes a hook contributes to a report
+type HookResult struct {
+	// Annotations maps a repository key (see RepositoryReport.Key) to extra
+	// fields merged into that repository's Annotations. Later hooks overwrite
+	// fields set by earlier ones.
+	Annotations map[string]map[string]any `json:"annotations,omitempty"`
+
+	// Suppressions removes repositories, or individual packages from a
+	// repository, from the report
+	Suppressions []Suppression `json:"suppressions,omitempty"`
+}
+
+// Suppression hides a whole repository (Package empty) or a single tracked
+// package of a repository from the report
+type Suppression struct {
+	Repository string `json:"repository"`        // Repository key (provider:owner/repo@ref)
+	Package    string `json:"package,omitempty"` // Tracked package; empty suppresses the repository
+	Reason     string `json:"reason,omitempty"`  // Free-form explanation shown in output
+	Hook       string `json:"hook,omitempty"`    // Hook that requested the suppression (set by the generator)
+}
+
+// AddHook registers a post-processing hook run at the end of Generate
+func (g *Generator) AddHook(h Hook) {
+	g.hooks = append(g.hooks, h)
+}
+
+// HooksFromConfig builds exec hooks from configuration entries
+func HooksFromConfig(cfgs []config.HookConfig) []Hook {
+	hooks := make([]Hook, 0, len(cfgs))
+	for _, c := range cfgs {
+		hooks = append(hooks, &ExecHook{
+			HookName: c.Name,
+			Command:  c.Command,
+			Timeout:  c.Timeout,
+		})
+	}
+	return hooks
+}
+
+// runHooks applies every registered hook to rpt in order. The hooks outlive
+// ctx's deadline or cancellation, keeping only its values.
+func (g *Generator) runHooks(ctx context.Context, rpt *Report) {
+	if len(g.hooks) == 0 {
+		return
+	}
+	if ctx.Err() != nil {
+		slog.Debug("Running report hooks after the run context ended", "error", ctx.Err())
+	}
+	base := context.WithoutCancel(ctx)
+	for _, h := range g.hooks {
+		hookCtx, cancel := context.WithTimeout(base, hookTimeout(h))
+		res, err := h.Run(hookCtx, rpt)
+		cancel()
+		if err != nil {
+			slog.Warn("Report hook failed", "hook", h.Name(), "error", err)
+			continue
+		}
+		rpt.applyHookResult(h.Name(), res)
+	}
+}
+
+// hookTimeout is the limit of one run of h: an ExecHook's configured Timeout,
+// otherwise DefaultHookTimeout
+func hookTimeout(h Hook) time.Duration {
+	if eh, ok := h.(*ExecHook); ok && eh.Timeout > 0 {
+		return eh.Timeout
+	}
+	return DefaultHookTimeout
+}
+
+// applyHookResult merges a hook's annotations and suppressions into the report.
+// Entries naming unknown repositories or packages are logged and ignored.
+func (r *Report) applyHookResult(hook string, res *HookResult) {
+	if res == nil {
+		return
+	}
+
+	index := make(map[string]int, len(r.Repositories))
+	for i := range r.Repositories {
+		index[r.Repositories[i].Key()] = i
+	}
+
+	for key, fields := range res.Annotations {
+		i, ok := index[key]
+		if !ok {
+			slog.Warn("Hook annotated unknown repository", "hook", hook, "repository", key)
+			continue
+		}
+		rr := &r.Repositories[i]
+		if rr.Annotations == nil {
+			rr.Annotations = make(map[string]any, len(fields))
+		}
+		for k, v := range fields {
+			rr.Annotations[k] = v
+		}
+	}
+
+	dropped := make(map[int]bool)
+	for _, s := range res.Suppressions {
+		i, ok := index[s.Repository]
+		if !ok {
+			slog.Warn("Hook suppressed unknown repository", "hook", hook, "repository", s.Repository)
+			continue
+		}
+		s.Hook = hook
+		if s.Package == "" {
+			dropped[i] = true
+		} else {
+			rr := &r.Repositories[i]
+			delete(rr.Dependencies, s.Package)
+			delete(rr.Constraints, s.Package)
+			delete(rr.FileVersions, s.Package)
+			delete(rr.LockedNames, s.Package)
+			delete(rr.DevOnly, s.Package)
+			delete(rr.Provenance, s.Package)
+			delete(rr.Registries, s.Package)
+			delete(rr.Hashes, s.Package)
+			delete(rr.UpdatePullRequests, s.Package)
+			rr.Violations = withoutPackage(rr.Violations, rr.GetEcosystem(), s.Package)
+		}
+		r.Suppressed = append(r.Suppressed, s)
+	}
+
+	if len(dropped) > 0 {
+		kept := r.Repositories[:0]
+		for i, rr := range r.Repositories {
+			if !dropped[i] {
+				kept = append(kept, rr)
+			}
+		}
+		r.Repositories = kept
+	}
+}
+
+// ExecHook runs an external command as a Hook. The report is written to the
+// command's stdin as JSON (see hookInput) and its stdout, if not empty, is
+// decoded as a HookResult. A non-zero exit status fails the hook.
+type ExecHook struct {
+	HookName string        // Label for logs; defaults to the command
+	Command  []string      // Program and arguments (not run through a shell)
+	Timeout  time.Duration // Per-run limit; DefaultHookTimeout when zero
+}
+
+// Name returns the configured hook name, falling back to the command
+func (h *ExecHook) Name() string {
+	if h.HookName != "" {
+		return h.HookName
+	}
+	return strings.Join(h.Command, " ")
+}
+
+// Run executes the command with the report on stdin
+func (h *ExecHook) Run(ctx context.Context, rpt *Report) (*HookResult, error) {
+	if len(h.Command) == 0 {
+		return nil, fmt.Errorf("hook %q has no command", h.Name())
+	}
+	input, err := json.Marshal(newHookInput(rpt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report for hook: %w", err)
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// #nosec G204 -- the command comes from the user's own configuration file
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("hook command failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("hook command failed: %w", err)
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil, nil
+	}
+	var res HookResult
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return nil, fmt.Errorf("failed to decode hook output: %w", err)
+	}
+	return &res, nil
+}
+
+// hookInput is the JSON document written to an ExecHook's stdin
+type hookInput struct {
+	Packages     []string         `json:"packages"`
+	Repositories []hookRepository `json:"repositories"`
+	Suppressed   []Suppression    `json:"suppressed,omitempty"`
+}
+
+// hookRepository is one repository in hookInput; errors are flattened to text
+type hookRepository struct {
+	Key           string            `json:"key"`
+	Provider      string            `json:"provider"`
+	Owner         string            `json:"owner"`
+	Repository    string            `json:"repository"`
+	Ref           string            `json:"ref"`
+	CommitSHA     string            `json:"commitSha,omitempty"`
+	Analyzer      string            `json:"analyzer"`
+	Tags          []string          `json:"tags,omitempty"`
+	Dependencies  map[string]string `json:"dependencies"`
+	Constraints   map[string]string `json:"constraints,omitempty"`
+	Annotations   map[string]any    `json:"annotations,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	ErrorCategory ErrorCategory     `json:"errorCategory,omitempty"`
+}
+
+func newHookInput(rpt *Report) hookInput {
+	in := hookInput{
+		Packages:     rpt.Packages,
+		Repositories: make([]hookRepository, 0, len(rpt.Repositories)),
+		Suppressed:   rpt.Suppressed,
+	}
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		hr := hookRepository{
+			Key:          rr.Key(),
+			Provider:     rr.Provider,
+			Owner:        rr.Owner,
+			Repository:   rr.Repository,
+			Ref:          rr.Ref,
+			CommitSHA:    rr.CommitSHA,
+			Analyzer:     rr.Analyzer,
+			Tags:         rr.Tags,
+			Dependencies: rr.Dependencies,
+			Constraints:  rr.Constraints,
+			Annotations:  rr.Annotations,
+		}
+		if rr.Error != nil {
+			hr.Error = rr.Error.Error()
+			hr.ErrorCategory = rr.ErrorCategory()
+		}
+		in.Repositories = append(in.Repositories, hr)
+	}
+	return in
+}
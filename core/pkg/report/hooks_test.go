@@ -0,0 +1,222 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+// funcHook adapts a function to the Hook interface
+type funcHook struct {
+	name string
+	run  func(ctx context.Context, rpt *Report) (*HookResult, error)
+}
+
+func (h funcHook) Name() string { return h.name }
+
+func (h funcHook) Run(ctx context.Context, rpt *Report) (*HookResult, error) {
+	return h.run(ctx, rpt)
+}
+
+func hookTestReport() *Report {
+	return &Report{
+		Packages: []string{"django", "requests"},
+		Repositories: []RepositoryReport{
+			{
+				Provider: "github", Owner: "o", Repository: "api", Ref: "main",
+				Dependencies: map[string]string{"django": "4.2.0", "requests": "2.31.0"},
+				Constraints:  map[string]string{"django": "^4.2"},
+			},
+			{
+				Provider: "github", Owner: "o", Repository: "legacy", Ref: "main",
+				Dependencies: map[string]string{},
+				Error:        NewErrorDetail(errors.New("no dependency files found")),
+			},
+		},
+	}
+}
+
+func TestApplyHookResult(t *testing.T) {
+	rpt := hookTestReport()
+	rpt.applyHookResult("cmdb", &HookResult{
+		Annotations: map[string]map[string]any{
+			"github:o/api@main":   {"team": "payments", "tier": float64(1)},
+			"github:o/nope@main":  {"team": "ignored"},
+			"github:o/api@branch": {"team": "wrong ref"},
+		},
+		Suppressions: []Suppression{
+			{Repository: "github:o/legacy@main", Reason: "archived"},
+			{Repository: "github:o/api@main", Package: "django", Reason: "vendored"},
+			{Repository: "github:o/missing@main"},
+		},
+	})
+
+	if len(rpt.Repositories) != 1 || rpt.Repositories[0].Repository != "api" {
+		t.Fatalf("expected only api to remain, got %+v", rpt.Repositories)
+	}
+	api := rpt.Repositories[0]
+	if api.Annotations["team"] != "payments" || api.Annotations["tier"] != float64(1) {
+		t.Errorf("annotations not merged: %v", api.Annotations)
+	}
+	if _, ok := api.Dependencies["django"]; ok {
+		t.Error("suppressed package should be removed from dependencies")
+	}
+	if _, ok := api.Constraints["django"]; ok {
+		t.Error("suppressed package should be removed from constraints")
+	}
+	if api.Dependencies["requests"] != "2.31.0" {
+		t.Error("unsuppressed package should be kept")
+	}
+	if len(rpt.Suppressed) != 2 {
+		t.Fatalf("expected 2 recorded suppressions, got %d", len(rpt.Suppressed))
+	}
+	for _, s := range rpt.Suppressed {
+		if s.Hook != "cmdb" {
+			t.Errorf("suppression should record hook name, got %q", s.Hook)
+		}
+	}
+	if rpt.Err() != nil {
+		t.Errorf("suppressed failing repository should not count as failure: %v", rpt.Err())
+	}
+}
+
+func TestGenerate_RunsHooksInOrder(t *testing.T) {
+	gen := stubGenerator()
+	repos := stubRepos()[:1]
+
+	var seen []string
+	gen.AddHook(funcHook{name: "first", run: func(_ context.Context, rpt *Report) (*HookResult, error) {
+		seen = append(seen, "first")
+		key := rpt.Repositories[0].Key()
+		return &HookResult{Annotations: map[string]map[string]any{key: {"owner": "team-a"}}}, nil
+	}})
+	gen.AddHook(funcHook{name: "broken", run: func(context.Context, *Report) (*HookResult, error) {
+		seen = append(seen, "broken")
+		return nil, errors.New("lookup failed")
+	}})
+	gen.AddHook(funcHook{name: "last", run: func(_ context.Context, rpt *Report) (*HookResult, error) {
+		seen = append(seen, "last")
+		if rpt.Repositories[0].Annotations["owner"] != "team-a" {
+			t.Error("later hook should see earlier annotations")
+		}
+		return nil, nil
+	}})
+
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(seen, ",") != "first,broken,last" {
+		t.Errorf("hooks ran as %v", seen)
+	}
+	if rpt.Repositories[0].Annotations["owner"] != "team-a" {
+		t.Errorf("expected annotation in final report, got %v", rpt.Repositories[0].Annotations)
+	}
+}
+
+func TestGenerate_HooksRunAfterDeadline(t *testing.T) {
+	gen := stubGenerator()
+	gen.AddHook(funcHook{name: "cmdb", run: func(ctx context.Context, _ *Report) (*HookResult, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &HookResult{Suppressions: []Suppression{
+			{Repository: "fast:o/fast@", Package: "django", Reason: "vendored"},
+			{Repository: "slow:o/slow@", Reason: "archived"},
+		}}, nil
+	}})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	rpt, err := gen.Generate(ctx, stubRepos())
+	if err != nil {
+		t.Fatalf("expected partial report on deadline, got error: %v", err)
+	}
+	if len(rpt.Repositories) != 1 || rpt.Repositories[0].Repository != "fast" {
+		t.Fatalf("expected suppressed slow repository to be dropped, got %+v", rpt.Repositories)
+	}
+	if _, ok := rpt.Repositories[0].Dependencies["django"]; ok {
+		t.Error("suppressed package should be removed from the truncated report")
+	}
+	if len(rpt.Suppressed) != 2 {
+		t.Errorf("expected 2 recorded suppressions, got %+v", rpt.Suppressed)
+	}
+}
+
+func TestExecHook(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+		check   func(*testing.T, *HookResult)
+	}{
+		{
+			name: "reads report and returns annotations",
+			// Echo back the first repository key found on stdin
+			script: `key=$(grep -o '"key":"[^"]*"' | head -n1 | cut -d'"' -f4); ` +
+				`printf '{"annotations":{"%s":{"seen":true}}}' "$key"`,
+			check: func(t *testing.T, res *HookResult) {
+				if res == nil || res.Annotations["github:o/api@main"]["seen"] != true {
+					t.Errorf("unexpected result: %+v", res)
+				}
+			},
+		},
+		{
+			name:   "empty output means no changes",
+			script: `cat >/dev/null`,
+			check: func(t *testing.T, res *HookResult) {
+				if res != nil {
+					t.Errorf("expected nil result, got %+v", res)
+				}
+			},
+		},
+		{
+			name:    "non-zero exit includes stderr",
+			script:  `echo "cmdb unreachable" >&2; exit 3`,
+			wantErr: "cmdb unreachable",
+		},
+		{
+			name:    "invalid output",
+			script:  `echo not-json`,
+			wantErr: "failed to decode hook output",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := &ExecHook{Command: []string{"sh", "-c", tt.script}}
+			res, err := hook.Run(context.Background(), hookTestReport())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, res)
+		})
+	}
+}
+
+func TestHooksFromConfig(t *testing.T) {
+	hooks := HooksFromConfig([]config.HookConfig{
+		{Name: "cmdb", Command: []string{"cmdb-lookup", "--json"}},
+		{Command: []string{"./annotate.sh"}},
+	})
+	if len(hooks) != 2 {
+		t.Fatalf("expected 2 hooks, got %d", len(hooks))
+	}
+	if hooks[0].Name() != "cmdb" || hooks[1].Name() != "./annotate.sh" {
+		t.Errorf("unexpected hook names %q, %q", hooks[0].Name(), hooks[1].Name())
+	}
+}
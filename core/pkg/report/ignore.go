@@ -0,0 +1,181 @@
+package report
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// IgnoreRule annotates or suppresses the findings it matches: a tracked
+// package's version (so it does not count as drift) and policy violations.
+// Suppressed findings stay in the report with Reason but no longer fail
+// the run; annotated ones only carry Reason.
+type IgnoreRule struct {
+	Repository string    // RepositoryReport.Key, owner/repo or owner/repo@ref; empty matches every repository
+	Package    string    // Empty matches every package
+	Version    string    // Range in versioning.ParseRange syntax; empty matches every version
+	Policy     string    // Only violations of this policy (drift is then not matched)
+	Reason     string    // Why the finding is accepted
+	Expires    time.Time // The rule no longer applies from this time; zero never expires
+	Annotate   bool      // Only annotate; the finding still counts
+}
+
+// Ignore records the IgnoreRule matching a finding
+type Ignore struct {
+	Reason     string `json:"reason"`
+	Suppressed bool   `json:"suppressed"` // False when the rule only annotates
+}
+
+// IgnoreRulesFromConfig builds ignore rules from configuration entries,
+// rejecting version ranges and expiry dates that do not parse
+func IgnoreRulesFromConfig(cfgs []config.IgnoreConfig) ([]IgnoreRule, error) {
+	rules := make([]IgnoreRule, 0, len(cfgs))
+	for i, c := range cfgs {
+		if c.Version != "" {
+			if _, err := versioning.ParseRange(dependencies.EcosystemPython, c.Version); err != nil {
+				return nil, exitcode.Errorf(exitcode.ConfigError, "ignore at index %d: %w", i, err)
+			}
+		}
+		rule := IgnoreRule{
+			Repository: c.Repository,
+			Package:    c.Package,
+			Version:    c.Version,
+			Policy:     c.Policy,
+			Reason:     c.Reason,
+			Annotate:   c.Annotate,
+		}
+		if c.Expires != "" {
+			expires, err := time.ParseInLocation(config.IgnoreDateLayout, c.Expires, time.Local)
+			if err != nil {
+				return nil, exitcode.Errorf(exitcode.ConfigError, "ignore at index %d: invalid expires %q: %w", i, c.Expires, err)
+			}
+			rule.Expires = expires
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// SetIgnoreRules registers the rules applied to every report the generator
+// produces (see RepositoryReport.Ignored and Violation.Ignored)
+func (g *Generator) SetIgnoreRules(rules []IgnoreRule) {
+	g.ignores = rules
+}
+
+// IgnoreRules returns the rules set by SetIgnoreRules
+func (g *Generator) IgnoreRules() []IgnoreRule {
+	return g.ignores
+}
+
+// matchesRepository reports whether the rule covers rr
+func (r IgnoreRule) matchesRepository(rr *RepositoryReport) bool {
+	switch r.Repository {
+	case "", rr.Key(), rr.GetRepoIdentifier(), rr.GetRepoIdentifier() + "@" + rr.Ref:
+		return true
+	default:
+		return false
+	}
+}
+
+// matchesPackage reports whether the rule covers name locked at version,
+// comparing ecosystem-normalized names
+func (r IgnoreRule) matchesPackage(eco dependencies.Ecosystem, name, version string) bool {
+	if r.Package != "" && dependencies.NormalizeName(eco, r.Package) != dependencies.NormalizeName(eco, name) {
+		return false
+	}
+	if r.Version == "" {
+		return true
+	}
+	rng, err := versioning.ParseRange(eco, r.Version)
+	return err == nil && rng.Contains(version)
+}
+
+// ignore returns what the rule records on the findings it matches
+func (r IgnoreRule) ignore() Ignore {
+	return Ignore{Reason: r.Reason, Suppressed: !r.Annotate}
+}
+
+// applyIgnores records the first matching rule on each tracked package and
+// policy violation of rpt, replacing earlier matches. Expired rules are
+// skipped with a warning.
+func (g *Generator) applyIgnores(rpt *Report) {
+	if len(g.ignores) == 0 {
+		return
+	}
+	now := time.Now()
+	active := make([]IgnoreRule, 0, len(g.ignores))
+	for _, rule := range g.ignores {
+		if !rule.Expires.IsZero() && !now.Before(rule.Expires) {
+			slog.Warn("Ignore rule expired; findings are reported again",
+				"repository", rule.Repository,
+				"package", rule.Package,
+				"policy", rule.Policy,
+				"expires", rule.Expires.Format(config.IgnoreDateLayout))
+			continue
+		}
+		active = append(active, rule)
+	}
+
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		rr.Ignored = nil
+		eco := rr.GetEcosystem()
+		for pkg, version := range rr.Dependencies {
+			for _, rule := range active {
+				if rule.Policy != "" || !rule.matchesRepository(rr) || !rule.matchesPackage(eco, pkg, version) {
+					continue
+				}
+				if rr.Ignored == nil {
+					rr.Ignored = make(map[string]Ignore)
+				}
+				rr.Ignored[pkg] = rule.ignore()
+				break
+			}
+		}
+		for j := range rr.Violations {
+			v := &rr.Violations[j]
+			v.Ignored = nil
+			for _, rule := range active {
+				if rule.Policy != "" && rule.Policy != v.Policy {
+					continue
+				}
+				if !rule.matchesRepository(rr) || !rule.matchesPackage(eco, v.Package, v.Version) {
+					continue
+				}
+				ignore := rule.ignore()
+				v.Ignored = &ignore
+				break
+			}
+		}
+	}
+}
+
+// Suppressed reports whether an ignore rule suppresses the version of pkg,
+// so it does not count as drift or make other repositories look outdated
+func (r *RepositoryReport) Suppressed(pkg string) bool {
+	return r.Ignored[pkg].Suppressed
+}
+
+// Suppressed reports whether an ignore rule suppresses the violation, so it
+// does not fail the run
+func (v Violation) Suppressed() bool {
+	return v.Ignored != nil && v.Ignored.Suppressed
+}
+
+// IgnoreNote returns the text appended to an ignored finding in output, or
+// "" when ig is nil
+func IgnoreNote(ig *Ignore) string {
+	switch {
+	case ig == nil:
+		return ""
+	case ig.Suppressed:
+		return fmt.Sprintf("ignored: %s", ig.Reason)
+	default:
+		return fmt.Sprintf("note: %s", ig.Reason)
+	}
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+func ignoreTestReport() *Report {
+	return &Report{
+		Packages: []string{"django"},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2.0"}},
+			{Provider: "github", Owner: "o", Repository: "legacy", Ref: "main", Analyzer: "poetry", Dependencies: map[string]string{"django": "3.2.25"},
+				Violations: []Violation{{Policy: "django-lts", Severity: SeverityError, Repository: "github:o/legacy@main", Package: "Django", Version: "3.2.25"}}},
+		},
+	}
+}
+
+func TestIgnoreRulesFromConfig(t *testing.T) {
+	rules, err := IgnoreRulesFromConfig([]config.IgnoreConfig{
+		{Package: "django", Version: "<4", Reason: "pinned", Expires: "2030-07-01", Annotate: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := rules[0]; r.Expires.Format(time.DateOnly) != "2030-07-01" || !r.Annotate || r.Version != "<4" {
+		t.Errorf("unexpected rule %+v", r)
+	}
+	if _, err := IgnoreRulesFromConfig([]config.IgnoreConfig{{Package: "django", Version: ">=>", Reason: "x"}}); err == nil {
+		t.Error("expected an error for an invalid version range")
+	}
+}
+
+func TestApplyIgnores_SuppressesDriftAndViolations(t *testing.T) {
+	rpt := ignoreTestReport()
+	if rpt.PolicyErr() == nil || !rpt.GetPackageVersions()[0].HasDrift() {
+		t.Fatal("fixture should have drift and a failing violation")
+	}
+
+	g := NewGenerator()
+	g.SetIgnoreRules([]IgnoreRule{{Repository: "o/legacy", Package: "django", Version: "<4", Reason: "pinned until Q3"}})
+	g.applyIgnores(rpt)
+
+	legacy := rpt.Repositories[1]
+	if !legacy.Suppressed("django") || legacy.Ignored["django"].Reason != "pinned until Q3" {
+		t.Errorf("Ignored = %v, want django suppressed", legacy.Ignored)
+	}
+	if !legacy.Violations[0].Suppressed() {
+		t.Error("violation of the pinned package should be suppressed")
+	}
+	if rpt.Repositories[0].Ignored != nil {
+		t.Errorf("rule should not match o/api: %v", rpt.Repositories[0].Ignored)
+	}
+	if err := rpt.PolicyErr(); err != nil {
+		t.Errorf("PolicyErr() = %v, want nil", err)
+	}
+	if pv := rpt.GetPackageVersions()[0]; pv.HasDrift() || len(pv.Versions["3.2.25"]) != 1 {
+		t.Errorf("suppressed version should stay listed but not drift: %+v", pv)
+	}
+	if rpt.IsOutdated("django", "4.2.0") {
+		t.Error("suppressed version should not affect IsOutdated")
+	}
+}
+
+func TestApplyIgnores_Matching(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       IgnoreRule
+		drift      bool // Legacy's django version is ignored
+		violation  bool
+		suppressed bool
+	}{
+		{"repository key", IgnoreRule{Repository: "github:o/legacy@main", Reason: "r"}, true, true, true},
+		{"repository with ref", IgnoreRule{Repository: "o/legacy@main", Package: "DJANGO", Reason: "r"}, true, true, true},
+		{"other ref", IgnoreRule{Repository: "o/legacy@dev", Reason: "r"}, false, false, false},
+		{"version out of range", IgnoreRule{Package: "django", Version: ">=4", Reason: "r"}, false, false, false},
+		{"policy only", IgnoreRule{Policy: "django-lts", Reason: "r"}, false, true, true},
+		{"other policy", IgnoreRule{Policy: "no-git", Reason: "r"}, false, false, false},
+		{"annotate", IgnoreRule{Repository: "o/legacy", Reason: "r", Annotate: true}, true, true, false},
+		{"expired", IgnoreRule{Repository: "o/legacy", Reason: "r", Expires: time.Now().Add(-time.Hour)}, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpt := ignoreTestReport()
+			g := NewGenerator()
+			g.SetIgnoreRules([]IgnoreRule{tt.rule})
+			g.applyIgnores(rpt)
+			legacy := rpt.Repositories[1]
+			if _, ok := legacy.Ignored["django"]; ok != tt.drift {
+				t.Errorf("django ignored = %v, want %v", ok, tt.drift)
+			}
+			if got := legacy.Violations[0].Ignored != nil; got != tt.violation {
+				t.Errorf("violation ignored = %v, want %v", got, tt.violation)
+			}
+			if got := legacy.Violations[0].Suppressed(); got != tt.suppressed {
+				t.Errorf("violation suppressed = %v, want %v", got, tt.suppressed)
+			}
+		})
+	}
+}
+
+func TestIgnoreNote(t *testing.T) {
+	if got := IgnoreNote(nil); got != "" {
+		t.Errorf("IgnoreNote(nil) = %q", got)
+	}
+	if got := IgnoreNote(&Ignore{Reason: "pinned", Suppressed: true}); got != "ignored: pinned" {
+		t.Errorf("suppressed note = %q", got)
+	}
+	if got := IgnoreNote(&Ignore{Reason: "known"}); got != "note: known" {
+		t.Errorf("annotation note = %q", got)
+	}
+}
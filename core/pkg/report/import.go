@@ -0,0 +1,133 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// exportedReport is the part of a JSONDocument ReadJSON restores
+type exportedReport struct {
+	ExportInfo
+	Repositories    *[]RepositoryReport      `json:"repositories"`
+	Packages        []string                 `json:"packages"`
+	Errors          map[string]string        `json:"errors"`
+	ErrorCategories map[string]ErrorCategory `json:"errorCategories"`
+	Suppressed      []Suppression            `json:"suppressed"`
+}
+
+// missingErrorDetails replaces the message of failures exported before
+// repositories carried their error message, without the errors map
+const missingErrorDetails = "analysis failed (the export has no error details)"
+
+// ReadJSON reads a JSONDocument, e.g. to browse a report on another machine
+// without a live run. Exports older than the current schema are accepted;
+// newer schema versions are not. Failed repositories get their exported
+// ErrorDetail, completed from the errors maps of exports whose repositories
+// carry less; the commit fingerprints needed to reuse results are not
+// exported, so the report cannot seed a snapshot.
+func ReadJSON(r io.Reader) (*Report, ExportInfo, error) {
+	var doc exportedReport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, ExportInfo{}, fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+	if doc.SchemaVersion > JSONSchemaVersion {
+		return nil, ExportInfo{}, fmt.Errorf("JSON report schema version %d is newer than the supported %d", doc.SchemaVersion, JSONSchemaVersion)
+	}
+	if doc.Repositories == nil {
+		return nil, ExportInfo{}, errors.New("not a JSON report: no repositories")
+	}
+
+	rpt := &Report{Repositories: *doc.Repositories, Packages: doc.Packages, Suppressed: doc.Suppressed}
+	for i := range rpt.Repositories {
+		if rr := &rpt.Repositories[i]; rr.Owner == "" || rr.Repository == "" {
+			return nil, ExportInfo{}, fmt.Errorf("JSON report repository %d has no owner or repository", i+1)
+		}
+	}
+
+	// Labels depend on the whole report, so errors are matched afterwards
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		msg, ok := doc.Errors[rr.Key()]
+		category := doc.ErrorCategories[rr.Key()]
+		if !ok {
+			label := rpt.RepoLabel(rr)
+			msg, ok = doc.Errors[label]
+			category = doc.ErrorCategories[label]
+		}
+		if !ok && rr.Error == nil {
+			continue
+		}
+		if rr.Error == nil {
+			rr.Error = &ErrorDetail{}
+		}
+		if rr.Error.Message == "" {
+			rr.Error.Message = msg
+		}
+		if rr.Error.Message == "" {
+			rr.Error.Message = missingErrorDetails
+		}
+		// Schema version 1 and earlier exported the category separately
+		if category != ErrorCategoryNone && (rr.Error.Category == ErrorCategoryNone || rr.Error.Category == ErrorCategoryUnknown) {
+			rr.Error.Category = category
+			rr.Error.Retryable = isRetryable(category, nil)
+		}
+		if rr.Error.Category == ErrorCategoryNone {
+			rr.Error.Category = ErrorCategoryUnknown
+		}
+		rr.Error.setImportedCause()
+	}
+
+	if len(rpt.Packages) == 0 {
+		packages := make(map[string]bool)
+		for _, rr := range rpt.Repositories {
+			for pkg := range rr.Dependencies {
+				packages[pkg] = true
+			}
+		}
+		rpt.Packages = slices.Sorted(maps.Keys(packages))
+	}
+	return rpt, doc.ExportInfo, nil
+}
+
+// importedError rebuilds a persisted failure as the typed error
+// CategorizeError maps to category (see ErrorDetail.Unwrap)
+func importedError(msg string, category ErrorCategory) error {
+	err := errors.New(msg)
+	switch category {
+	case ErrorCategoryAuth:
+		return &AuthError{Err: err}
+	case ErrorCategoryNotFound:
+		return &NotFoundError{Err: err}
+	case ErrorCategoryPrivate:
+		return &PrivateRepositoryError{Err: err}
+	case ErrorCategoryRateLimit:
+		return &RateLimitError{Err: err}
+	case ErrorCategoryTimeout:
+		return &TimeoutError{Err: err}
+	case ErrorCategoryParse:
+		return &importedCause{msg: msg, cause: &dependencies.ParseError{Err: err}}
+	case ErrorCategoryBudget:
+		return &importedCause{msg: msg, cause: repository.ErrBudgetExhausted}
+	case ErrorCategoryConfig:
+		return exitcode.New(exitcode.ConfigError, err)
+	}
+	return err
+}
+
+// importedCause keeps an exported message while matching the sentinel or
+// type of its category
+type importedCause struct {
+	msg   string
+	cause error
+}
+
+func (e *importedCause) Error() string { return e.msg }
+func (e *importedCause) Unwrap() error { return e.cause }
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+func TestReadJSON_RoundTrip(t *testing.T) {
+	store := memory.NewStore()
+	store.AddRepository("acme", "api", memory.Files{
+		"services/a/poetry.lock": "[[package]]\nname = \"django\"\nversion = \"4.2.0\"\n",
+		"services/b/poetry.lock": "[[package]]\nname = \"django\"\nversion = \"5.0.1\"\n",
+	})
+	store.SetError("acme", "gone", &repository.StatusError{StatusCode: http.StatusNotFound, Message: "no such repository"})
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) { return memory.NewClient(store), nil }
+	var repos []config.RepoWithProvider
+	for _, name := range []string{"api", "gone"} {
+		repos = append(repos, config.RepoWithProvider{Provider: memory.Provider, Config: config.RepoConfig{
+			Owner: "acme", Repository: name, Ref: "main", Analyzer: "poetry", Packages: []string{"django"}, Tags: []string{"team-a"},
+		}})
+	}
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The CLI's shape, errors keyed by RepoLabel
+	errs, categories := map[string]string{}, map[string]ErrorCategory{}
+	for label, err := range rpt.GetErrors() {
+		errs[label] = err.Error()
+		categories[label] = CategorizeError(err)
+	}
+	data, err := json.Marshal(map[string]any{
+		"cliVersion":      "1.2.3",
+		"generatedAt":     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		"repositories":    rpt.Repositories,
+		"packages":        rpt.Packages,
+		"errors":          errs,
+		"errorCategories": categories,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, info, err := ReadJSON(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "1.2.3" || info.GeneratedAt.Year() != 2026 {
+		t.Errorf("export info = %+v", info)
+	}
+	if len(got.Repositories) != 2 || len(got.Packages) != 1 {
+		t.Fatalf("imported %d repositories and packages %v", len(got.Repositories), got.Packages)
+	}
+	api, gone := got.Repositories[0], got.Repositories[1]
+	if api.Error != nil || api.Dependencies["django"] != "4.2.0" || !maps.Equal(api.FileVersions["django"], rpt.Repositories[0].FileVersions["django"]) || api.Tags[0] != "team-a" || api.CommitSHA == "" {
+		t.Errorf("imported repository = %+v, want the exported %+v", api, rpt.Repositories[0])
+	}
+	if gone.Error == nil || gone.Error.Error() != rpt.Repositories[1].Error.Error() || gone.ErrorCategory() != ErrorCategoryPrivate {
+		t.Errorf("imported failure = %v (%s), want %v (private)", gone.Error, gone.ErrorCategory(), rpt.Repositories[1].Error)
+	}
+}
+
+func TestReadJSON_WithoutErrorDetails(t *testing.T) {
+	doc := `{"repositories": [
+		{"Provider": "github", "Owner": "o", "Repository": "r", "Ref": "main", "Dependencies": {"requests": "2.31.0"}, "Error": null},
+		{"Provider": "github", "Owner": "o", "Repository": "broken", "Ref": "main", "Error": {}}
+	]}`
+	rpt, _, err := ReadJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rpt.Repositories[0].Error != nil {
+		t.Errorf("successful repository imported with error %v", rpt.Repositories[0].Error)
+	}
+	if err := rpt.Repositories[1].Error; err == nil || err.Error() != missingErrorDetails {
+		t.Errorf("failure without details = %v, want %q", err, missingErrorDetails)
+	}
+	if len(rpt.Packages) != 1 || rpt.Packages[0] != "requests" {
+		t.Errorf("packages = %v, want those of the repositories", rpt.Packages)
+	}
+}
+
+func TestReadJSON_Invalid(t *testing.T) {
+	for name, doc := range map[string]string{
+		"not JSON":        "dependency report",
+		"no repositories": `{"version": 1, "repositories": null}`,
+		"no owner":        `{"repositories": [{"Repository": "r"}]}`,
+	} {
+		if _, _, err := ReadJSON(strings.NewReader(doc)); err == nil {
+			t.Errorf("%s: ReadJSON accepted %s", name, doc)
+		}
+	}
+}
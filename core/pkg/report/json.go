@@ -0,0 +1,128 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JSONSchemaVersion is the version of the JSON report document (see
+// JSONDocument). It is bumped when a field is removed or changes meaning;
+// added fields keep the version. Version 2 made RepositoryReport.Error an
+// ErrorDetail object instead of a message.
+const JSONSchemaVersion = 2
+
+// ExportInfo describes the run that exported a JSON report
+type ExportInfo struct {
+	// SchemaVersion is JSONSchemaVersion when written; 0 in exports older
+	// than the versioned schema
+	SchemaVersion int       `json:"schemaVersion"`
+	Version       string    `json:"cliVersion"` // Of the exporting CLI or GUI
+	GeneratedAt   time.Time `json:"generatedAt"`
+}
+
+// JSONDocument is the canonical JSON form of a report, written by the CLI's
+// --format json, the serve API and the GUI's Export JSON, and read back by
+// ReadJSON. Its JSON Schema is printed by JSONSchema.
+type JSONDocument struct {
+	ExportInfo
+	Repositories []RepositoryReport `json:"repositories"`
+	Packages     []string           `json:"packages"`
+	// Ecosystems groups the repositories and packages by ecosystem
+	Ecosystems []EcosystemGroup `json:"ecosystems"`
+	Summary    JSONSummary      `json:"summary"`
+	// Errors maps the RepoLabel of each failed repository to its error
+	Errors map[string]string `json:"errors,omitempty"`
+	// ErrorCategories has the same keys as Errors
+	ErrorCategories map[string]ErrorCategory `json:"errorCategories,omitempty"`
+	// Suppressed lists repositories and packages removed by report hooks
+	Suppressed []Suppression `json:"suppressed,omitempty"`
+	// RefComparisons compares repositories analyzed at several refs
+	RefComparisons []RefComparison `json:"refComparisons,omitempty"`
+}
+
+// JSONSummary counts a JSONDocument's repositories and findings
+type JSONSummary struct {
+	RepositoryCount int `json:"repositoryCount"`
+	PackageCount    int `json:"packageCount"`
+	SuccessCount    int `json:"successCount"`
+	ErrorCount      int `json:"errorCount"`
+	// ViolationCount counts policy violations of any severity
+	ViolationCount int `json:"violationCount"`
+	// InconsistentCount counts repositories whose dependency files lock a
+	// tracked package at different versions (see RepositoryReport.FileVersions)
+	InconsistentCount int `json:"inconsistentCount"`
+}
+
+// JSONOptions controls NewJSONDocument
+type JSONOptions struct {
+	// Version names the exporting program's version
+	Version string
+	// GeneratedAt stamps the document (default now)
+	GeneratedAt time.Time
+	// OmitErrors leaves out the Errors and ErrorCategories maps; each
+	// repository still carries its error message
+	OmitErrors bool
+}
+
+// NewJSONDocument builds the canonical JSON form of the report
+func (r *Report) NewJSONDocument(opts JSONOptions) JSONDocument {
+	if opts.GeneratedAt.IsZero() {
+		opts.GeneratedAt = time.Now()
+	}
+	doc := JSONDocument{
+		ExportInfo: ExportInfo{
+			SchemaVersion: JSONSchemaVersion,
+			Version:       opts.Version,
+			GeneratedAt:   opts.GeneratedAt.UTC(),
+		},
+		Repositories: r.Repositories,
+		Packages:     r.Packages,
+		Ecosystems:   r.EcosystemGroups(),
+		Summary: JSONSummary{
+			RepositoryCount: len(r.Repositories),
+			PackageCount:    len(r.Packages),
+			ViolationCount:  len(r.Violations()),
+		},
+		Suppressed:     r.Suppressed,
+		RefComparisons: r.RefComparisons(),
+	}
+	for i := range r.Repositories {
+		rr := &r.Repositories[i]
+		if rr.Error == nil {
+			doc.Summary.SuccessCount++
+		} else if !opts.OmitErrors {
+			if doc.Errors == nil {
+				doc.Errors = make(map[string]string)
+				doc.ErrorCategories = make(map[string]ErrorCategory)
+			}
+			label := r.RepoLabel(rr)
+			doc.Errors[label] = rr.Error.Error()
+			doc.ErrorCategories[label] = rr.ErrorCategory()
+		}
+		if len(rr.InconsistentPackages()) > 0 {
+			doc.Summary.InconsistentCount++
+		}
+	}
+	doc.Summary.ErrorCount = doc.Summary.RepositoryCount - doc.Summary.SuccessCount
+	return doc
+}
+
+// WriteJSON writes the report's JSONDocument to w, followed by a newline;
+// indent pretty-prints it
+func (r *Report) WriteJSON(w io.Writer, opts JSONOptions, indent bool) error {
+	doc := r.NewJSONDocument(opts)
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		data, err = json.Marshal(doc)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
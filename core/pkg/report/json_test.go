@@ -0,0 +1,129 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func jsonTestReport() *Report {
+	return &Report{
+		Packages: []string{"django"},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry", CommitSHA: "abc123", Dependencies: map[string]string{"django": "4.2.11"}},
+			{Provider: "github", Owner: "o", Repository: "gone", Ref: "main", Analyzer: "poetry", Error: NewErrorDetail(&NotFoundError{Err: errors.New("repository not found")})},
+		},
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	rpt := jsonTestReport()
+	var buf bytes.Buffer
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if err := rpt.WriteJSON(&buf, JSONOptions{Version: "1.2.3", GeneratedAt: at}, false); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		SchemaVersion int
+		GeneratedAt   time.Time
+		Repositories  []map[string]any
+		Summary       JSONSummary
+		Errors        map[string]string
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.SchemaVersion != JSONSchemaVersion || !doc.GeneratedAt.Equal(at) {
+		t.Errorf("schemaVersion %d, generatedAt %v", doc.SchemaVersion, doc.GeneratedAt)
+	}
+	if doc.Repositories[0]["Error"] != nil || doc.Repositories[0]["CommitSHA"] != "abc123" {
+		t.Errorf("successful repository = %v", doc.Repositories[0])
+	}
+	if detail, _ := doc.Repositories[1]["Error"].(map[string]any); detail["message"] != "repository not found" || detail["category"] != "not-found" || detail["retryable"] != false {
+		t.Errorf("failed repository error = %v, want its ErrorDetail", doc.Repositories[1]["Error"])
+	}
+	if doc.Summary.SuccessCount != 1 || doc.Summary.ErrorCount != 1 || doc.Errors["o/gone"] != "repository not found" {
+		t.Errorf("summary %+v, errors %v", doc.Summary, doc.Errors)
+	}
+
+	buf.Reset()
+	if err := rpt.WriteJSON(&buf, JSONOptions{OmitErrors: true}, true); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"errors"`)) || !bytes.Contains(buf.Bytes(), []byte(`"message": "repository not found"`)) {
+		t.Errorf("OmitErrors output:\n%s", buf.String())
+	}
+
+	got, info, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.SchemaVersion != JSONSchemaVersion || got.Repositories[0].CommitSHA != "abc123" {
+		t.Errorf("read back %+v (%+v)", got.Repositories[0], info)
+	}
+	if err := got.Repositories[1].Error; err == nil || err.Error() != "repository not found" || got.Repositories[1].ErrorCategory() != ErrorCategoryNotFound {
+		t.Errorf("read back error %v, want the written one", err)
+	}
+}
+
+func TestReadJSON_NewerSchema(t *testing.T) {
+	if _, _, err := ReadJSON(bytes.NewReader([]byte(`{"schemaVersion": 99, "repositories": []}`))); err == nil {
+		t.Error("ReadJSON accepted a newer schema version")
+	}
+}
+
+// TestJSONSchema verifies every key WriteJSON writes is described by the
+// schema, and every required key is written.
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	type object struct {
+		Properties map[string]json.RawMessage
+		Required   []string
+	}
+	var schema struct {
+		object
+		Defs map[string]object `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := jsonTestReport().WriteJSON(&buf, JSONOptions{}, false); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Top          map[string]json.RawMessage
+		Repositories []map[string]json.RawMessage `json:"repositories"`
+	}
+	_ = json.Unmarshal(buf.Bytes(), &doc.Top)
+	_ = json.Unmarshal(buf.Bytes(), &doc)
+
+	check := func(name string, obj object, keys map[string]json.RawMessage) {
+		for key := range keys {
+			if _, ok := obj.Properties[key]; !ok {
+				t.Errorf("%s key %q is not in the schema", name, key)
+			}
+		}
+		for _, key := range obj.Required {
+			if _, ok := keys[key]; !ok {
+				t.Errorf("%s is missing required key %q", name, key)
+			}
+		}
+	}
+	check("document", schema.object, doc.Top)
+	repo, ok := schema.Defs["RepositoryReport"]
+	if !ok {
+		t.Fatalf("schema has no RepositoryReport definition: %v", slices.Sorted(maps.Keys(schema.Defs)))
+	}
+	for _, rr := range doc.Repositories {
+		check("repository", repo, rr)
+	}
+}
@@ -0,0 +1,224 @@
+package report
+
+import (
+	"fmt"
+	"unique"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// intern returns the canonical copy of s, so the versions and paths repeated
+// across the repositories of a large report share one string
+func intern(s string) string {
+	return unique.Make(s).Value()
+}
+
+// VersionMatrix is a column-oriented index of a report's tracked versions,
+// built in one pass over the report for aggregating large ones: each tracked
+// package is a column of small indexes into its distinct versions rather
+// than a map entry per repository, and the per-package figures (ecosystem,
+// lowest and highest version) and repository labels that per-cell lookups
+// would otherwise recompute from every repository are computed once.
+// Displays and exports read cells through it and expand a package's
+// PackageVersions only when asked.
+//
+// A matrix reflects the report when built; rebuild it after changing the
+// report's repositories.
+type VersionMatrix struct {
+	rpt     *Report
+	labels  []string       // RepoLabel of each repository
+	index   map[string]int // Tracked package -> column
+	columns []versionColumn
+}
+
+// versionColumn holds one tracked package's versions
+type versionColumn struct {
+	eco      dependencies.Ecosystem
+	versions []string // Distinct versions; versions[0] is "" (not locked)
+	cells    []uint32 // Per repository, an index into versions
+
+	// Distinct versions of successfully analyzed repositories not
+	// suppressed by an ignore rule, lowest first, and their bounds (see
+	// PackageVersions)
+	sorted   []string
+	min, max string
+}
+
+// Matrix builds the VersionMatrix of the report's tracked packages
+func (r *Report) Matrix() *VersionMatrix {
+	m := &VersionMatrix{
+		rpt:     r,
+		labels:  make([]string, len(r.Repositories)),
+		index:   make(map[string]int, len(r.Packages)),
+		columns: make([]versionColumn, len(r.Packages)),
+	}
+
+	// Repositories tracked at several refs are labeled with their ref (see
+	// RepoLabel)
+	refs := make(map[string]string)
+	multiRef := make(map[string]bool)
+	for i := range r.Repositories {
+		rr := &r.Repositories[i]
+		id := rr.GetRepoIdentifier()
+		if ref, seen := refs[id]; seen && ref != rr.Ref {
+			multiRef[id] = true
+		}
+		refs[id] = rr.Ref
+	}
+	for i := range r.Repositories {
+		rr := &r.Repositories[i]
+		m.labels[i] = rr.GetRepoIdentifier()
+		if multiRef[m.labels[i]] {
+			m.labels[i] = fmt.Sprintf("%s/%s@%s", rr.Owner, rr.Repository, rr.Ref)
+		}
+	}
+
+	for c, pkg := range r.Packages {
+		m.index[pkg] = c
+		col := &m.columns[c]
+		col.versions = []string{""}
+		col.cells = make([]uint32, len(r.Repositories))
+		positions := map[string]uint32{"": 0}
+		eco, found := r.PackageEcosystems[pkg]
+		for i := range r.Repositories {
+			rr := &r.Repositories[i]
+			version, ok := rr.Dependencies[pkg]
+			if ok && !found {
+				eco, found = rr.GetEcosystem(), true
+			}
+			pos, seen := positions[version]
+			if !seen {
+				pos = uint32(len(col.versions))
+				positions[version] = pos
+				col.versions = append(col.versions, version)
+			}
+			col.cells[i] = pos
+		}
+		if !found && len(r.Repositories) > 0 {
+			eco = r.Repositories[0].GetEcosystem()
+		}
+		col.eco = eco
+
+		counted := make([]bool, len(col.versions))
+		for i, pos := range col.cells {
+			rr := &r.Repositories[i]
+			if pos == 0 || counted[pos] || rr.Error != nil || rr.Suppressed(pkg) {
+				continue
+			}
+			counted[pos] = true
+			col.sorted = append(col.sorted, col.versions[pos])
+		}
+		versioning.Sort(eco, col.sorted)
+		col.min, col.max = versioning.MinMax(eco, col.sorted)
+		// Equivalent spellings ("1.0", "1.0.0") share one bound so HasDrift stays false
+		if col.min != "" && versioning.Compare(eco, col.min, col.max) == 0 {
+			col.max = col.min
+		}
+	}
+	return m
+}
+
+// Label returns the RepoLabel of repository i of the report
+func (m *VersionMatrix) Label(i int) string {
+	return m.labels[i]
+}
+
+// column returns the column of pkg, or nil when it is not tracked
+func (m *VersionMatrix) column(pkg string) *versionColumn {
+	c, ok := m.index[pkg]
+	if !ok {
+		return nil
+	}
+	return &m.columns[c]
+}
+
+// Version returns the version of pkg locked by repository i, "" when not
+// locked. Packages the report does not track are looked up in the
+// repository's Dependencies.
+func (m *VersionMatrix) Version(i int, pkg string) string {
+	col := m.column(pkg)
+	if col == nil {
+		return m.rpt.Repositories[i].Dependencies[pkg]
+	}
+	return col.versions[col.cells[i]]
+}
+
+// Ecosystem returns the report's PackageEcosystem of pkg
+func (m *VersionMatrix) Ecosystem(pkg string) dependencies.Ecosystem {
+	if col := m.column(pkg); col != nil {
+		return col.eco
+	}
+	return m.rpt.PackageEcosystem(pkg)
+}
+
+// Latest returns the highest version of pkg found across successfully
+// analyzed repositories, leaving out versions suppressed by ignore rules;
+// "" when there is none
+func (m *VersionMatrix) Latest(pkg string) string {
+	if col := m.column(pkg); col != nil {
+		return col.max
+	}
+	return ""
+}
+
+// IsOutdated is Report.IsOutdated without rescanning the report
+func (m *VersionMatrix) IsOutdated(pkg, version string) bool {
+	col := m.column(pkg)
+	if col == nil || version == "" || col.max == "" {
+		return false
+	}
+	return versioning.Compare(col.eco, version, col.max) < 0
+}
+
+// Behind returns the number of distinct versions of pkg newer than version
+// found across successfully analyzed repositories (see
+// PackageVersions.Sorted): 0 for the newest, -1 when version is not among
+// them. Equivalent spellings count once.
+func (m *VersionMatrix) Behind(pkg, version string) int {
+	col := m.column(pkg)
+	if col == nil || version == "" {
+		return -1
+	}
+	found, newer := false, 0
+	for j, v := range col.sorted {
+		switch c := versioning.Compare(col.eco, v, version); {
+		case c == 0:
+			found = true
+		case c > 0 && (j == 0 || versioning.Compare(col.eco, v, col.sorted[j-1]) != 0):
+			newer++
+		}
+	}
+	if !found {
+		return -1
+	}
+	return newer
+}
+
+// HasDrift is PackageVersions(pkg).HasDrift without expanding the package's
+// versions
+func (m *VersionMatrix) HasDrift(pkg string) bool {
+	col := m.column(pkg)
+	return col != nil && col.min != "" && col.min != col.max
+}
+
+// PackageVersions expands the PackageVersions of a tracked package (see
+// Report.GetPackageVersions). Versions of a package the report does not
+// track list every repository as not locking it.
+func (m *VersionMatrix) PackageVersions(pkg string) PackageVersions {
+	pv := PackageVersions{PackageName: pkg, Versions: make(map[string][]string)}
+	col := m.column(pkg)
+	if col == nil {
+		if len(m.labels) > 0 {
+			pv.Versions[""] = append([]string(nil), m.labels...)
+		}
+		return pv
+	}
+	for i, pos := range col.cells {
+		version := col.versions[pos]
+		pv.Versions[version] = append(pv.Versions[version], m.labels[i])
+	}
+	pv.Sorted = append([]string(nil), col.sorted...)
+	pv.Min, pv.Max = col.min, col.max
+	return pv
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestMatrix_MatchesReport(t *testing.T) {
+	rpt := &Report{
+		Packages: []string{"django", "requests", "absent"},
+		Repositories: []RepositoryReport{
+			{Owner: "org", Repository: "api", Ref: "main", Ecosystem: "pypi", Dependencies: map[string]string{"django": "4.2.0", "requests": "2.31.0"}},
+			{Owner: "org", Repository: "api", Ref: "v1", Ecosystem: "pypi", Dependencies: map[string]string{"django": "3.2.0"}},
+			{Owner: "org", Repository: "web", Ref: "main", Ecosystem: "pypi", Dependencies: map[string]string{"django": "4.2", "requests": "2.31.0"}},
+			{Owner: "org", Repository: "broken", Ref: "main", Ecosystem: "pypi", Dependencies: map[string]string{"django": "5.0.0"}, Error: NewErrorDetail(errors.New("boom"))},
+		},
+	}
+	m := rpt.Matrix()
+
+	for i := range rpt.Repositories {
+		if got, want := m.Label(i), rpt.RepoLabel(&rpt.Repositories[i]); got != want {
+			t.Errorf("Label(%d) = %q, want %q", i, got, want)
+		}
+	}
+	if got := m.Label(0); got != "org/api@main" {
+		t.Errorf("Label(0) = %q, want the ref of a repository tracked at several", got)
+	}
+
+	for _, pkg := range append(rpt.Packages, "untracked") {
+		if got, want := m.Ecosystem(pkg), rpt.PackageEcosystem(pkg); got != want {
+			t.Errorf("Ecosystem(%q) = %q, want %q", pkg, got, want)
+		}
+		for i := range rpt.Repositories {
+			if got, want := m.Version(i, pkg), rpt.Repositories[i].Dependencies[pkg]; got != want {
+				t.Errorf("Version(%d, %q) = %q, want %q", i, pkg, got, want)
+			}
+		}
+		for _, version := range []string{"", "3.2.0", "4.2", "4.2.0", "5.0.0", "2.30.0", "2.31.0"} {
+			if got, want := m.IsOutdated(pkg, version), rpt.IsOutdated(pkg, version); got != want {
+				t.Errorf("IsOutdated(%q, %q) = %v, want %v", pkg, version, got, want)
+			}
+		}
+	}
+
+	if got := m.Latest("django"); got != "4.2.0" {
+		t.Errorf("Latest(django) = %q, want 4.2.0 (failed repositories left out)", got)
+	}
+	if !m.HasDrift("django") || m.HasDrift("requests") || m.HasDrift("absent") || m.HasDrift("untracked") {
+		t.Error("HasDrift should hold only for django")
+	}
+
+	for version, want := range map[string]int{"3.2.0": 1, "4.2": 0, "4.2.0": 0, "5.0.0": -1, "": -1} {
+		if got := m.Behind("django", version); got != want {
+			t.Errorf("Behind(django, %q) = %d, want %d", version, got, want)
+		}
+	}
+
+	pv := m.PackageVersions("requests")
+	want := map[string][]string{"2.31.0": {"org/api@main", "org/web"}, "": {"org/api@v1", "org/broken"}}
+	if !reflect.DeepEqual(pv.Versions, want) {
+		t.Errorf("PackageVersions(requests).Versions = %v, want %v", pv.Versions, want)
+	}
+	if pv.HasDrift() {
+		t.Error("requests should not drift")
+	}
+	if pv := m.PackageVersions("untracked"); len(pv.Versions[""]) != len(rpt.Repositories) {
+		t.Errorf("PackageVersions(untracked) = %v, want every repository unlocked", pv.Versions)
+	}
+}
+
+func TestIntern(t *testing.T) {
+	a := intern(fmt.Sprintf("%d.%d.%d", 1, 2, 3))
+	b := intern(fmt.Sprintf("%d.%d.%d", 1, 2, 3))
+	if a != "1.2.3" || unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Errorf("intern(%q) and intern(%q) should share one copy", a, b)
+	}
+}
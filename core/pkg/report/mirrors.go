@@ -0,0 +1,161 @@
+package report
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+// Mirror is a repository merged into another report entry because both
+// mirror the same project (see config.RepoConfig.Canonical)
+type Mirror struct {
+	Provider   string
+	Owner      string
+	Repository string
+	Ref        string
+
+	// CommitSHA is the commit the mirror was analyzed at
+	CommitSHA string `json:",omitempty"`
+
+	// Error describes the mirror's analysis failure, nil when it succeeded
+	Error *ErrorDetail `json:",omitempty"`
+
+	// OutOfSync lists the tracked packages the mirror locks at another
+	// version than the entry it was merged into, sorted
+	OutOfSync []string `json:",omitempty"`
+}
+
+// Key returns the mirror's RepositoryReport.Key
+func (m Mirror) Key() string {
+	rr := RepositoryReport{Provider: m.Provider, Owner: m.Owner, Repository: m.Repository, Ref: m.Ref}
+	return rr.Key()
+}
+
+// defaultHosts maps providers to the host of their public service
+var defaultHosts = map[string]string{
+	"github": "github.com",
+	"gitlab": "gitlab.com",
+}
+
+// canonicalIdentity normalizes a configured canonical ID or remote URL, so
+// https://github.com/acme/api.git, git@github.com:acme/api and
+// github.com/acme/api name the same project
+func canonicalIdentity(canonical string) string {
+	s := strings.TrimSpace(canonical)
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host != "" {
+		s = u.Hostname() + u.Path
+	} else if at := strings.Index(s, "@"); at >= 0 {
+		// scp-like git@host:owner/repo
+		if colon := strings.Index(s[at:], ":"); colon > 0 {
+			s = s[at+1:at+colon] + "/" + s[at+colon+1:]
+		}
+	}
+	s = strings.TrimSuffix(strings.Trim(s, "/"), ".git")
+	return strings.ToLower(s)
+}
+
+// remoteIdentity is the canonicalIdentity of a configured repository's own
+// remote URL: host/owner/repo, the host taken from BaseURL when set
+func remoteIdentity(r config.RepoWithProvider) string {
+	host := defaultHosts[r.Provider]
+	if host == "" {
+		host = r.Provider
+	}
+	if u, err := url.Parse(r.Config.BaseURL); err == nil && u.Host != "" {
+		host = strings.TrimPrefix(u.Hostname(), "api.")
+	}
+	return canonicalIdentity(host + "/" + r.Config.Owner + "/" + r.Config.Repository)
+}
+
+// mirrorGroups maps the key of each configured repository that mirrors a
+// project also configured elsewhere to the project's identity at its ref.
+// A repository's identity is its Canonical ID or URL, or its own remote;
+// repositories sharing one are grouped when at least one sets Canonical.
+func mirrorGroups(repos []config.RepoWithProvider) map[string]string {
+	identities := make(map[string]string, len(repos))
+	members := make(map[string]int)
+	declared := make(map[string]bool)
+	for _, r := range repos {
+		identity := remoteIdentity(r)
+		if r.Config.Canonical != "" {
+			identity = canonicalIdentity(r.Config.Canonical)
+		}
+		identity += "@" + r.Config.Ref
+		identities[repoKey(r)] = identity
+		members[identity]++
+		if r.Config.Canonical != "" {
+			declared[identity] = true
+		}
+	}
+	groups := make(map[string]string)
+	for key, identity := range identities {
+		if members[identity] > 1 && declared[identity] {
+			groups[key] = identity
+		}
+	}
+	return groups
+}
+
+// mergeMirrors folds the mirrors of each project into one entry so drift
+// counts the project once. The first successfully analyzed mirror in report
+// order is kept (the first mirror when all failed); the others are recorded
+// in its Mirrors. Repositories outside groups are returned unchanged.
+func mergeMirrors(reports []RepositoryReport, groups map[string]string) []RepositoryReport {
+	if len(groups) == 0 {
+		return reports
+	}
+	primary := make(map[string]int)
+	for i, rr := range reports {
+		identity, ok := groups[rr.Key()]
+		if !ok {
+			continue
+		}
+		if p, seen := primary[identity]; !seen || (reports[p].Error != nil && rr.Error == nil) {
+			primary[identity] = i
+		}
+	}
+
+	merged := make([]RepositoryReport, 0, len(reports))
+	index := make(map[string]int)
+	for i, rr := range reports {
+		identity, ok := groups[rr.Key()]
+		if !ok {
+			merged = append(merged, rr)
+			continue
+		}
+		if _, placed := index[identity]; !placed {
+			index[identity] = len(merged)
+			merged = append(merged, reports[primary[identity]])
+		}
+		if i == primary[identity] {
+			continue
+		}
+		entry := &merged[index[identity]]
+		entry.Mirrors = append(slices.Clone(entry.Mirrors), newMirror(entry, &rr))
+	}
+	return merged
+}
+
+// newMirror records rr as a mirror of primary
+func newMirror(primary, rr *RepositoryReport) Mirror {
+	m := Mirror{
+		Provider:   rr.Provider,
+		Owner:      rr.Owner,
+		Repository: rr.Repository,
+		Ref:        rr.Ref,
+		CommitSHA:  rr.CommitSHA,
+		Error:      rr.Error,
+	}
+	if primary.Error != nil || rr.Error != nil {
+		return m
+	}
+	for pkg, version := range primary.Dependencies {
+		if other, ok := rr.Dependencies[pkg]; ok && other != version {
+			m.OutOfSync = append(m.OutOfSync, pkg)
+		}
+	}
+	slices.Sort(m.OutOfSync)
+	return m
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+func TestCanonicalIdentity(t *testing.T) {
+	for _, in := range []string{
+		"https://github.com/Acme/api.git",
+		"git@github.com:acme/api.git",
+		"ssh://git@github.com:22/acme/api",
+		"github.com/acme/api/",
+	} {
+		if got := canonicalIdentity(in); got != "github.com/acme/api" {
+			t.Errorf("canonicalIdentity(%q) = %q, want github.com/acme/api", in, got)
+		}
+	}
+	gitlab := config.RepoWithProvider{Provider: "gitlab", Config: config.RepoConfig{BaseURL: "https://git.example.com/", Owner: "mirrors/acme", Repository: "api"}}
+	if got := remoteIdentity(gitlab); got != "git.example.com/mirrors/acme/api" {
+		t.Errorf("remoteIdentity() = %q", got)
+	}
+}
+
+func TestGenerate_MergesMirrors(t *testing.T) {
+	lock := func(version string) memory.Files {
+		return memory.Files{"poetry.lock": "[[package]]\nname = \"django\"\nversion = \"" + version + "\"\n"}
+	}
+	store := memory.NewStore()
+	store.AddRepository("acme", "api", lock("4.2.0"))
+	store.AddRepository("mirror", "api", lock("4.1.0"))
+	store.AddRepository("acme", "web", lock("5.0.1"))
+	store.AddRepository("mirror", "web", lock("5.0.1"))
+	store.AddRepository("acme", "cli", lock("5.0.1"))
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) { return memory.NewClient(store), nil }
+	repo := func(owner, name, canonical string) config.RepoWithProvider {
+		return config.RepoWithProvider{Provider: memory.Provider, Config: config.RepoConfig{
+			Owner: owner, Repository: name, Ref: "main", Analyzer: "poetry", Canonical: canonical,
+			Paths: []string{"poetry.lock"}, Packages: []string{"django"},
+		}}
+	}
+	repos := []config.RepoWithProvider{
+		repo("missing", "web", "acme-web"),
+		repo("acme", "api", ""),
+		repo("acme", "web", "acme-web"),
+		repo("mirror", "api", "https://memory/acme/api.git"),
+		repo("mirror", "web", "acme-web"),
+		repo("acme", "cli", ""),
+	}
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, rr := range rpt.Repositories {
+		keys = append(keys, rr.Key())
+	}
+	want := []string{"memory:acme/web@main", "memory:acme/api@main", "memory:acme/cli@main"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("repositories = %v, want %v", keys, want)
+	}
+	web, api := rpt.Repositories[0], rpt.Repositories[1]
+	if len(web.Mirrors) != 2 || web.Mirrors[0].Key() != "memory:missing/web@main" || web.Mirrors[0].Error == nil || web.Mirrors[1].OutOfSync != nil {
+		t.Errorf("web mirrors = %+v, want the failed and the in-sync mirror", web.Mirrors)
+	}
+	if len(api.Mirrors) != 1 || !slices.Equal(api.Mirrors[0].OutOfSync, []string{"django"}) {
+		t.Errorf("api mirrors = %+v, want mirror/api out of sync on django", api.Mirrors)
+	}
+	if got := rpt.GetPackageVersions()[0].Versions["5.0.1"]; len(got) != 2 {
+		t.Errorf("django 5.0.1 used by %v, want each project counted once", got)
+	}
+
+	store.SetFile("mirror", "api", "main", "poetry.lock", "[[package]]\nname = \"django\"\nversion = \"4.2.0\"\n")
+	again, err := gen.Regenerate(context.Background(), rpt, repos, func(r config.RepoWithProvider) bool {
+		return r.Config.Owner == "mirror" && r.Config.Repository == "api"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = keys[:0]
+	for _, rr := range again.Repositories {
+		keys = append(keys, rr.Key())
+	}
+	if !slices.Equal(keys, want) || len(again.Repositories[0].Mirrors) != 2 {
+		t.Fatalf("regenerated repositories = %v, want %v with the other mirrors kept merged", keys, want)
+	}
+	if m := again.Repositories[1].Mirrors; len(m) != 1 || m[0].OutOfSync != nil {
+		t.Errorf("regenerated api mirrors = %+v, want mirror/api back in sync", m)
+	}
+}
@@ -0,0 +1,204 @@
+package report
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// Policy severities
+const (
+	// SeverityError violations fail the run (exitcode.PolicyViolation)
+	SeverityError = "error"
+	// SeverityWarning violations are reported only
+	SeverityWarning = "warning"
+)
+
+// Policy is a rule checked against every locked package of the repositories
+// in scope, tracked or not: the package's locked version must satisfy
+// Version, no package (or only Package) may come from one of ForbidSources,
+// and, when AllowRegistries is set, packages whose lock file records a
+// registry must come from one of those.
+type Policy struct {
+	Name            string
+	Package         string   // Empty matches every package
+	Version         string   // Range in versioning.ParseRange syntax
+	ForbidSources   []string // Dependency.Source values, e.g. "git"
+	AllowRegistries []string // Dependency.Registry URLs (or URL prefixes)
+	Tags            []string // Repositories carrying any of these tags; empty means all
+	Severity        string   // SeverityError or SeverityWarning
+}
+
+// Violation is a locked package breaking a Policy
+type Violation struct {
+	Policy     string  `json:"policy"`
+	Severity   string  `json:"severity"`
+	Repository string  `json:"repository"` // RepositoryReport.Key
+	File       string  `json:"file"`       // Lock file
+	Package    string  `json:"package"`
+	Version    string  `json:"version,omitempty"`
+	Source     string  `json:"source,omitempty"`
+	Registry   string  `json:"registry,omitempty"`
+	Message    string  `json:"message"`
+	Ignored    *Ignore `json:"ignored,omitempty"` // Matching ignore rule (see Generator.SetIgnoreRules)
+}
+
+// PoliciesFromConfig builds policies from configuration entries, rejecting
+// version ranges that do not parse
+func PoliciesFromConfig(cfgs []config.PolicyConfig) ([]Policy, error) {
+	policies := make([]Policy, 0, len(cfgs))
+	for _, c := range cfgs {
+		if c.Version != "" {
+			if _, err := versioning.ParseRange(dependencies.EcosystemPython, c.Version); err != nil {
+				return nil, exitcode.Errorf(exitcode.ConfigError, "policy %s: %w", c.Name, err)
+			}
+		}
+		severity := c.Severity
+		if severity == "" {
+			severity = SeverityError
+		}
+		policies = append(policies, Policy{
+			Name:            c.Name,
+			Package:         c.Package,
+			Version:         c.Version,
+			ForbidSources:   c.ForbidSources,
+			AllowRegistries: c.AllowRegistries,
+			Tags:            c.Tags,
+			Severity:        severity,
+		})
+	}
+	return policies, nil
+}
+
+// SetPolicies registers the policies checked for each analyzed repository
+// (see RepositoryReport.Violations)
+func (g *Generator) SetPolicies(policies []Policy) {
+	g.policies = policies
+}
+
+// Policies returns the policies set by SetPolicies
+func (g *Generator) Policies() []Policy {
+	return g.policies
+}
+
+// appliesTo reports whether the policy covers repo
+func (p Policy) appliesTo(repo config.RepoWithProvider) bool {
+	return len(p.Tags) == 0 || config.HasAnyTag(repo.Config.Tags, p.Tags)
+}
+
+// checkPolicies evaluates the generator's policies against a repository's
+// analyzer results (lock file path to dependencies)
+func (g *Generator) checkPolicies(repo config.RepoWithProvider, key string, results map[string][]dependencies.Dependency) []Violation {
+	if len(g.policies) == 0 {
+		return nil
+	}
+	eco := dependencies.EcosystemForAnalyzer(repo.Config.Analyzer)
+	paths := make([]string, 0, len(results))
+	for path := range results {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	var violations []Violation
+	for _, p := range g.policies {
+		if !p.appliesTo(repo) {
+			continue
+		}
+		var rng *versioning.Range
+		if p.Version != "" {
+			var err error
+			if rng, err = versioning.ParseRange(eco, p.Version); err != nil {
+				slog.Warn("Skipping policy version check", "policy", p.Name, "repository", key, "error", err)
+			}
+		}
+		for _, path := range paths {
+			for _, dep := range results[path] {
+				if p.Package != "" && dependencies.NormalizeName(eco, p.Package) != dependencies.NormalizeName(eco, dep.Name) {
+					continue
+				}
+				v := Violation{Policy: p.Name, Severity: p.Severity, Repository: key, File: path, Package: dep.Name, Version: dep.Version}
+				if rng != nil && !rng.Contains(dep.Version) {
+					v.Message = fmt.Sprintf("%s %s does not satisfy %s", dep.Name, displayVersion(dep.Version), p.Version)
+					violations = append(violations, v)
+				}
+				if slices.Contains(p.ForbidSources, dep.Source) {
+					v.Source = dep.Source
+					v.Message = fmt.Sprintf("%s comes from a forbidden source (%s)", dep.Name, dep.Source)
+					violations = append(violations, v)
+				}
+				if len(p.AllowRegistries) > 0 && dep.Registry != "" && !registryAllowed(dep.Registry, p.AllowRegistries) {
+					v.Source, v.Registry = "", dep.Registry
+					v.Message = fmt.Sprintf("%s is resolved from a registry not allowed (%s)", dep.Name, dep.Registry)
+					violations = append(violations, v)
+				}
+			}
+		}
+	}
+	slices.SortStableFunc(violations, func(a, b Violation) int {
+		if c := strings.Compare(a.Package, b.Package); c != 0 {
+			return c
+		}
+		return strings.Compare(a.File, b.File)
+	})
+	return violations
+}
+
+// registryAllowed reports whether registry is one of allowed, or below one of
+// them, ignoring case and trailing slashes
+func registryAllowed(registry string, allowed []string) bool {
+	registry = strings.TrimSuffix(strings.ToLower(registry), "/")
+	for _, a := range allowed {
+		a = strings.TrimSuffix(strings.ToLower(a), "/")
+		if registry == a || strings.HasPrefix(registry, a+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// displayVersion names an empty version (e.g. a git requirement) in messages
+func displayVersion(v string) string {
+	if v == "" {
+		return "(unversioned)"
+	}
+	return v
+}
+
+// withoutPackage drops the violations concerning pkg (compared by normalized
+// name), used when a hook suppresses the package
+func withoutPackage(violations []Violation, eco dependencies.Ecosystem, pkg string) []Violation {
+	name := dependencies.NormalizeName(eco, pkg)
+	return slices.DeleteFunc(violations, func(v Violation) bool {
+		return dependencies.NormalizeName(eco, v.Package) == name
+	})
+}
+
+// Violations returns every repository's policy violations in report order
+func (r *Report) Violations() []Violation {
+	var out []Violation
+	for _, rr := range r.Repositories {
+		out = append(out, rr.Violations...)
+	}
+	return out
+}
+
+// PolicyErr returns an exitcode.PolicyViolation error when any violation not
+// suppressed by an ignore rule has error severity, and nil otherwise
+func (r *Report) PolicyErr() error {
+	failed := 0
+	for _, v := range r.Violations() {
+		if v.Severity == SeverityError && !v.Suppressed() {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return exitcode.Errorf(exitcode.PolicyViolation, "%d policy violation(s)", failed)
+}
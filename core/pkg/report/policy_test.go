@@ -0,0 +1,111 @@
+package report
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+func TestPoliciesFromConfig(t *testing.T) {
+	policies, err := PoliciesFromConfig([]config.PolicyConfig{
+		{Name: "django-lts", Package: "django", Version: ">=4.2"},
+		{Name: "no-git", ForbidSources: []string{"git"}, Severity: SeverityWarning},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policies[0].Severity != SeverityError || policies[1].Severity != SeverityWarning {
+		t.Errorf("severities = %q, %q", policies[0].Severity, policies[1].Severity)
+	}
+
+	_, err = PoliciesFromConfig([]config.PolicyConfig{{Name: "bad", Package: "django", Version: ">=four"}})
+	if exitcode.FromError(err) != exitcode.ConfigError {
+		t.Errorf("expected a config error, got %v", err)
+	}
+}
+
+func TestCheckPolicies(t *testing.T) {
+	gen := NewGenerator()
+	gen.SetPolicies([]Policy{
+		{Name: "django-lts", Package: "Django", Version: ">=4.2", Severity: SeverityError},
+		{Name: "no-git-in-prod", ForbidSources: []string{"git"}, Tags: []string{"prod"}, Severity: SeverityWarning},
+	})
+	results := map[string][]dependencies.Dependency{
+		"poetry.lock": {
+			{Name: "django", Version: "3.2.25", Source: "pypi"},
+			{Name: "internal-lib", Version: "1.0.0", Source: "git"},
+		},
+		"svc/poetry.lock": {{Name: "django", Version: "4.2.11", Source: "pypi"}},
+	}
+	prod := config.RepoWithProvider{Provider: "github", Config: config.RepoConfig{Analyzer: "poetry", Tags: []string{"prod"}}}
+
+	got := gen.checkPolicies(prod, "github:o/api@main", results)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", got)
+	}
+	if v := got[0]; v.Policy != "django-lts" || v.File != "poetry.lock" || v.Message != "django 3.2.25 does not satisfy >=4.2" {
+		t.Errorf("unexpected version violation: %+v", v)
+	}
+	if v := got[1]; v.Policy != "no-git-in-prod" || v.Source != "git" || v.Severity != SeverityWarning {
+		t.Errorf("unexpected source violation: %+v", v)
+	}
+
+	dev := prod
+	dev.Config.Tags = []string{"dev"}
+	if got := gen.checkPolicies(dev, "github:o/api@main", results); len(got) != 1 {
+		t.Errorf("tag-scoped policy applied to an untagged repository: %+v", got)
+	}
+}
+
+func TestCheckPolicies_AllowRegistries(t *testing.T) {
+	gen := NewGenerator()
+	gen.SetPolicies([]Policy{{Name: "registries", AllowRegistries: []string{"https://pypi.acme.example/simple/"}, Severity: SeverityError}})
+	results := map[string][]dependencies.Dependency{
+		"uv.lock": {
+			{Name: "acme-core", Version: "2.0.0", Source: "pypi", Registry: "https://PyPI.acme.example/simple"},
+			{Name: "django", Version: "4.2.11", Source: "pypi", Registry: dependencies.PyPIRegistry},
+			{Name: "internal-lib", Version: "1.0.0", Source: "git"},
+		},
+	}
+	repo := config.RepoWithProvider{Provider: "github", Config: config.RepoConfig{Analyzer: "uvlock"}}
+
+	got := gen.checkPolicies(repo, "github:o/api@main", results)
+	if len(got) != 1 {
+		t.Fatalf("expected only the PyPI package to be flagged, got %+v", got)
+	}
+	if v := got[0]; v.Package != "django" || v.Registry != dependencies.PyPIRegistry || v.Source != "" {
+		t.Errorf("unexpected registry violation: %+v", v)
+	}
+}
+
+func TestGenerate_Policies(t *testing.T) {
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) {
+		return &graphClient{}, nil
+	}
+	repos := []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Analyzer: "poetry", Packages: []string{"requests"}}},
+	}
+
+	gen.SetPolicies([]Policy{{Name: "urllib3-2.1", Package: "urllib3", Version: ">=2.1", Severity: SeverityError}})
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := rpt.Violations(); len(v) != 1 || v[0].Package != "urllib3" || v[0].Repository != "github:o/r@" {
+		t.Fatalf("unexpected violations: %+v", v)
+	}
+	if code := exitcode.FromError(rpt.PolicyErr()); code != exitcode.PolicyViolation {
+		t.Errorf("PolicyErr code = %d, want %d", code, exitcode.PolicyViolation)
+	}
+
+	// A hook suppressing the package drops its violations
+	rpt.applyHookResult("h", &HookResult{Suppressions: []Suppression{{Repository: "github:o/r@", Package: "URLLib3"}}})
+	if err := rpt.PolicyErr(); err != nil {
+		t.Errorf("expected no violations after suppression, got %v", err)
+	}
+}
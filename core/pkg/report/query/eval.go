@@ -0,0 +1,245 @@
+package query
+
+import (
+	"cmp"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// Dependency is a locked package version
+type Dependency struct {
+	Package string `json:"package"`
+	Version string `json:"version"`
+}
+
+// RepositoryMatch is a repository matched by repos()
+type RepositoryMatch struct {
+	Repository string `json:"repository"` // report.RepositoryReport.Key
+	// Dependencies are those matching the expression, sorted by package;
+	// empty when only repository fields were tested
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+}
+
+// PackageMatch is a package version matched by packages()
+type PackageMatch struct {
+	Package      string   `json:"package"`
+	Version      string   `json:"version"`
+	Repositories []string `json:"repositories"` // Sorted keys
+}
+
+// Result is the outcome of a query; the field of its function is set
+type Result struct {
+	Query        string            `json:"query"`
+	Function     string            `json:"function"`
+	Repositories []RepositoryMatch `json:"repositories,omitempty"`
+	Packages     []PackageMatch    `json:"packages,omitempty"`
+}
+
+// Len returns the number of matches
+func (r Result) Len() int {
+	return len(r.Repositories) + len(r.Packages)
+}
+
+// Eval runs q over rpt's repositories
+func (q *Query) Eval(rpt *report.Report) Result {
+	res := Result{Query: q.src, Function: q.Function}
+	byVersion := make(map[Dependency][]string)
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		var matched []Dependency
+		found := false
+		for _, b := range bindings(rr) {
+			if q.expr != nil && !q.expr.match(b) {
+				continue
+			}
+			found = true
+			if b.pkg != "" {
+				matched = append(matched, Dependency{Package: b.pkg, Version: b.version})
+			}
+		}
+		if !found {
+			continue
+		}
+		switch q.Function {
+		case "repos":
+			res.Repositories = append(res.Repositories, RepositoryMatch{Repository: rr.Key(), Dependencies: matched})
+		case "packages":
+			for _, d := range matched {
+				byVersion[d] = append(byVersion[d], rr.Key())
+			}
+		}
+	}
+	slices.SortFunc(res.Repositories, func(a, b RepositoryMatch) int { return cmp.Compare(a.Repository, b.Repository) })
+	for d, repos := range byVersion {
+		slices.Sort(repos)
+		res.Packages = append(res.Packages, PackageMatch{Package: d.Package, Version: d.Version, Repositories: slices.Compact(repos)})
+	}
+	slices.SortFunc(res.Packages, func(a, b PackageMatch) int {
+		return cmp.Or(cmp.Compare(a.Package, b.Package), versioning.Compare("", a.Version, b.Version))
+	})
+	return res
+}
+
+// binding is one dependency of a repository an expression is tested against
+type binding struct {
+	rr      *report.RepositoryReport
+	pkg     string
+	version string
+}
+
+// bindings returns a binding per locked package of rr, sorted by package,
+// or a single empty one when it locks none
+func bindings(rr *report.RepositoryReport) []*binding {
+	var out []*binding
+	for _, pkg := range slices.Sorted(maps.Keys(rr.Dependencies)) {
+		if v := rr.Dependencies[pkg]; v != "" {
+			out = append(out, &binding{rr: rr, pkg: pkg, version: v})
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, &binding{rr: rr})
+	}
+	return out
+}
+
+type expr interface {
+	match(b *binding) bool
+}
+
+type andExpr struct{ left, right expr }
+
+func (e andExpr) match(b *binding) bool { return e.left.match(b) && e.right.match(b) }
+
+type orExpr struct{ left, right expr }
+
+func (e orExpr) match(b *binding) bool { return e.left.match(b) || e.right.match(b) }
+
+type notExpr struct{ e expr }
+
+func (e notExpr) match(b *binding) bool { return !e.e.match(b) }
+
+// comparison tests a field against a value
+type comparison struct {
+	field, op, value string
+	re               *regexp.Regexp
+	ranges           map[dependencies.Ecosystem]*versioning.Range // "in", by ecosystem
+}
+
+func (c *comparison) match(b *binding) bool {
+	rr := b.rr
+	eco := rr.GetEcosystem()
+	switch c.field {
+	case "package":
+		if b.pkg == "" {
+			return c.op == "!="
+		}
+		if c.op == "==" || c.op == "!=" {
+			return (dependencies.NormalizeName(eco, b.pkg) == dependencies.NormalizeName(eco, c.value)) == (c.op == "==")
+		}
+		return c.compareString(b.pkg)
+	case "version":
+		return c.compareVersion(eco, b.version)
+	case "constraint":
+		return c.compareString(rr.Constraints[b.pkg])
+	case "repo":
+		return c.compareString(rr.GetRepoIdentifier())
+	case "key":
+		return c.compareString(rr.Key())
+	case "provider":
+		return c.compareString(rr.Provider)
+	case "owner":
+		return c.compareString(rr.Owner)
+	case "ref":
+		return c.compareString(rr.Ref)
+	case "analyzer":
+		return c.compareString(rr.Analyzer)
+	case "ecosystem":
+		return c.compareString(string(eco))
+	case "commit":
+		return c.compareString(rr.CommitSHA)
+	case "error":
+		msg := ""
+		if rr.Error != nil {
+			msg = rr.Error.Error()
+		}
+		return c.compareString(msg)
+	case "tag":
+		return c.compareAny(rr.Tags)
+	case "policy":
+		var policies []string
+		for _, v := range rr.Violations {
+			if !v.Suppressed() {
+				policies = append(policies, v.Policy)
+			}
+		}
+		return c.compareAny(policies)
+	}
+	return false
+}
+
+// compareString applies the operator to a string field
+func (c *comparison) compareString(s string) bool {
+	switch c.op {
+	case "==":
+		return s == c.value
+	case "!=":
+		return s != c.value
+	case "=~":
+		return c.re.MatchString(s)
+	}
+	return orderHolds(c.op, strings.Compare(s, c.value))
+}
+
+// compareAny applies the operator to a list field: != holds when no element
+// equals the value, the other operators when any element satisfies them
+func (c *comparison) compareAny(values []string) bool {
+	if c.op == "!=" {
+		return !slices.Contains(values, c.value)
+	}
+	return slices.ContainsFunc(values, c.compareString)
+}
+
+// compareVersion applies the operator to a version in eco's ordering; an
+// unversioned binding only satisfies !=
+func (c *comparison) compareVersion(eco dependencies.Ecosystem, v string) bool {
+	if v == "" {
+		return c.op == "!="
+	}
+	switch c.op {
+	case "=~":
+		return c.re.MatchString(v)
+	case "in":
+		rng, ok := c.ranges[eco]
+		if !ok {
+			rng, _ = versioning.ParseRange(eco, c.value)
+			c.ranges[eco] = rng
+		}
+		return rng != nil && rng.Contains(v)
+	}
+	return orderHolds(c.op, versioning.Compare(eco, v, c.value))
+}
+
+// orderHolds reports whether a comparison result satisfies op
+func orderHolds(op string, n int) bool {
+	switch op {
+	case "==":
+		return n == 0
+	case "!=":
+		return n != 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+// Package query evaluates a small query language over reports, e.g.
+//
+//	repos(package == "requests" && version < "2.31")
+//	packages(ecosystem == python && tag == prod)
+//
+// A query is a function applied to a boolean expression. The expression is
+// tested against each dependency a repository locks, so package and version
+// comparisons joined by && refer to the same dependency; repository fields
+// (repo, tag, error, ...) are the same for all of them. Repositories locking
+// no tracked package are tested once with an empty package and version.
+//
+// Functions:
+//
+//	repos(expr)     repositories with a dependency matching expr
+//	packages(expr)  package versions matching expr, with their repositories
+//
+// Operators, loosest first: ||, &&, ! and parentheses, then comparisons
+// field OP value with OP one of == != < <= > >= =~ (regular expression) and
+// "in" (version range, e.g. version in ">=2,<3"). Values are double-quoted
+// strings or bare words such as 2.31 or prod.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// Functions lists the query functions
+var Functions = []string{"repos", "packages"}
+
+// Fields lists the fields comparisons may test
+var Fields = []string{
+	"package", "version", "constraint",
+	"repo", "key", "provider", "owner", "ref", "analyzer", "ecosystem", "commit",
+	"tag", "policy", "error",
+}
+
+// Query is a parsed query
+type Query struct {
+	Function string
+	expr     expr // Nil matches everything
+	src      string
+}
+
+// String returns the query's source
+func (q *Query) String() string { return q.src }
+
+// Error is a syntax error at a byte offset of the query
+type Error struct {
+	Offset int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("query: %s at offset %d", e.Msg, e.Offset)
+}
+
+// Parse parses a query
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	fn := p.next()
+	if fn.kind != tokWord || !slices.Contains(Functions, fn.text) {
+		return nil, &Error{fn.pos, fmt.Sprintf("expected a function (%s)", strings.Join(Functions, ", "))}
+	}
+	if err := p.expect(tokLParen, "'('"); err != nil {
+		return nil, err
+	}
+	q := &Query{Function: fn.text, src: src}
+	if p.peek().kind != tokRParen {
+		if q.expr, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(tokRParen, "')'"); err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &Error{t.pos, fmt.Sprintf("unexpected %q", t.text)}
+	}
+	return q, nil
+}
+
+// ----- Lexer -----
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokWord
+	tokString
+	tokOp // Comparison operator or "in"
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// isWordRune reports whether r may appear in a bare word: names, versions,
+// wildcards and repository keys
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-*+/:@", r)
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			toks = append(toks, token{tokAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			toks = append(toks, token{tokOr, "||", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="),
+			strings.HasPrefix(src[i:], "<="), strings.HasPrefix(src[i:], ">="), strings.HasPrefix(src[i:], "=~"):
+			toks = append(toks, token{tokOp, src[i : i+2], i})
+			i += 2
+		case c == '<' || c == '>':
+			toks = append(toks, token{tokOp, src[i : i+1], i})
+			i++
+		case c == '!':
+			toks = append(toks, token{tokNot, "!", i})
+			i++
+		case c == '"':
+			s, n, err := lexString(src[i:])
+			if err != nil {
+				return nil, &Error{i, err.Error()}
+			}
+			toks = append(toks, token{tokString, s, i})
+			i += n
+		default:
+			start := i
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if !isWordRune(r) {
+					break
+				}
+				i += size
+			}
+			if i == start {
+				return nil, &Error{i, fmt.Sprintf("unexpected character %q", src[i:i+1])}
+			}
+			word := src[start:i]
+			kind := tokWord
+			if word == "in" {
+				kind = tokOp
+			}
+			toks = append(toks, token{kind, word, start})
+		}
+	}
+	return append(toks, token{tokEOF, "end of query", len(src)}), nil
+}
+
+// lexString reads a double-quoted string with \" and \\ escapes, returning
+// its value and length in src
+func lexString(src string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if i+1 < len(src) {
+				i++
+				b.WriteByte(src[i])
+			}
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(src[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// ----- Parser -----
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return &Error{t.pos, fmt.Sprintf("expected %s, got %q", what, t.text)}
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	switch t := p.peek(); t.kind {
+	case tokNot:
+		p.next()
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	case tokLParen:
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(tokRParen, "')'")
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	field := p.next()
+	if field.kind != tokWord || !slices.Contains(Fields, field.text) {
+		return nil, &Error{field.pos, fmt.Sprintf("expected a field (%s), got %q", strings.Join(Fields, ", "), field.text)}
+	}
+	op := p.next()
+	if op.kind != tokOp {
+		return nil, &Error{op.pos, fmt.Sprintf("expected a comparison operator after %s, got %q", field.text, op.text)}
+	}
+	value := p.next()
+	if value.kind != tokString && value.kind != tokWord {
+		return nil, &Error{value.pos, fmt.Sprintf("expected a value, got %q", value.text)}
+	}
+
+	c := &comparison{field: field.text, op: op.text, value: value.text}
+	switch {
+	case op.text == "=~":
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, &Error{value.pos, fmt.Sprintf("invalid regular expression: %v", err)}
+		}
+		c.re = re
+	case op.text == "in" && field.text != "version":
+		return nil, &Error{op.pos, "\"in\" applies to version only"}
+	case op.text == "in":
+		// Validate now; the range is parsed again per ecosystem when matching
+		if _, err := versioning.ParseRange(dependencies.Ecosystem(""), value.text); err != nil {
+			return nil, &Error{value.pos, err.Error()}
+		}
+		c.ranges = make(map[dependencies.Ecosystem]*versioning.Range)
+	}
+	return c, nil
+}
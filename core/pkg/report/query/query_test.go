@@ -0,0 +1,133 @@
+package query
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// testReport has two Python repositories and an unanalyzable one
+func testReport() *report.Report {
+	return &report.Report{
+		Packages: []string{"requests", "django"},
+		Repositories: []report.RepositoryReport{
+			{
+				Provider: "github", Owner: "acme", Repository: "api", Ref: "main",
+				Analyzer: "poetry", Ecosystem: "python", Tags: []string{"prod"},
+				Dependencies: map[string]string{"requests": "2.28.1", "Django": "4.2.0"},
+				Violations:   []report.Violation{{Policy: "no-old-django", Package: "Django", Version: "4.2.0"}},
+			},
+			{
+				Provider: "github", Owner: "acme", Repository: "web", Ref: "main",
+				Analyzer: "poetry", Ecosystem: "python", Tags: []string{"dev"},
+				Dependencies: map[string]string{"requests": "2.31.0", "Django": "5.0.1"},
+			},
+			{
+				Provider: "gitlab", Owner: "acme", Repository: "broken", Ref: "main",
+				Analyzer: "poetry", Ecosystem: "python",
+				Error: report.NewErrorDetail(errors.New("clone failed")),
+			},
+		},
+	}
+}
+
+// repoKeys runs src over testReport and returns the matched repository keys
+func repoKeys(t *testing.T, src string) []string {
+	t.Helper()
+	q, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", src, err)
+	}
+	var keys []string
+	for _, m := range q.Eval(testReport()).Repositories {
+		keys = append(keys, m.Repository)
+	}
+	return keys
+}
+
+func TestEval_Repos(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`repos(package == "requests" && version < "2.31")`, []string{"github:acme/api@main"}},
+		{`repos(package == django && version >= 5)`, []string{"github:acme/web@main"}},
+		// && binds to one dependency: no repository locks requests at 5.0.1
+		{`repos(package == requests && version == 5.0.1)`, nil},
+		{`repos(version in ">=2.30,<3")`, []string{"github:acme/web@main"}},
+		{`repos(tag == prod)`, []string{"github:acme/api@main"}},
+		{`repos(tag != prod && error == "")`, []string{"github:acme/web@main"}},
+		{`repos(policy == no-old-django)`, []string{"github:acme/api@main"}},
+		{`repos(error =~ "clone")`, []string{"gitlab:acme/broken@main"}},
+		{`repos(!(provider == github) || repo == acme/web)`, []string{"github:acme/web@main", "gitlab:acme/broken@main"}},
+		{`repos()`, []string{"github:acme/api@main", "github:acme/web@main", "gitlab:acme/broken@main"}},
+	}
+	for _, tt := range tests {
+		if got := repoKeys(t, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestEval_ReposMatchedDependencies(t *testing.T) {
+	q, err := Parse(`repos(package == requests)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := q.Eval(testReport())
+	if res.Len() != 2 {
+		t.Fatalf("matches = %+v, want 2", res.Repositories)
+	}
+	want := []Dependency{{Package: "requests", Version: "2.28.1"}}
+	if got := res.Repositories[0].Dependencies; !slices.Equal(got, want) {
+		t.Errorf("dependencies = %v, want %v", got, want)
+	}
+}
+
+func TestEval_Packages(t *testing.T) {
+	q, err := Parse(`packages(package =~ "^req")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := q.Eval(testReport())
+	var got []string
+	for _, p := range res.Packages {
+		got = append(got, p.Package+"@"+p.Version)
+	}
+	if want := []string{"requests@2.28.1", "requests@2.31.0"}; !slices.Equal(got, want) {
+		t.Errorf("packages = %v, want %v", got, want)
+	}
+	if len(res.Repositories) != 0 {
+		t.Errorf("repositories = %v, want none for packages()", res.Repositories)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		query  string
+		offset int
+	}{
+		{`files(package == x)`, 0},
+		{`repos(package == x`, 18},
+		{`repos(name == x)`, 6},
+		{`repos(package x)`, 14},
+		{`repos(package == "x)`, 17},
+		{`repos(package =~ "(")`, 17},
+		{`repos(tag in ">=1")`, 10},
+		{`repos(package == x) extra`, 20},
+		{`repos(package == x && )`, 22},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		var qerr *Error
+		if !errors.As(err, &qerr) {
+			t.Errorf("Parse(%q) error = %v, want *Error", tt.query, err)
+			continue
+		}
+		if qerr.Offset != tt.offset {
+			t.Errorf("Parse(%q) offset = %d (%v), want %d", tt.query, qerr.Offset, qerr, tt.offset)
+		}
+	}
+}
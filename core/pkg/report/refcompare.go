@@ -0,0 +1,112 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// RefComparison compares the tracked packages of one repository analyzed at
+// several refs in the same report (e.g. main and release/1.x)
+type RefComparison struct {
+	Provider   string   `json:"provider"`
+	Owner      string   `json:"owner"`
+	Repository string   `json:"repository"`
+	Refs       []string `json:"refs"` // Successfully analyzed refs, in report order
+
+	// Packages lists every tracked package locked at any of the refs, sorted
+	// by name
+	Packages []RefPackage `json:"packages"`
+}
+
+// RefPackage is one package's versions across the refs of a RefComparison
+type RefPackage struct {
+	Package  string            `json:"package"`
+	Versions map[string]string `json:"versions"` // ref -> version; "" when not locked at that ref
+	// Differs is true when the refs lock different versions or only some of
+	// them lock the package. Equivalent spellings ("1.0", "1.0.0") do not differ.
+	Differs bool `json:"differs"`
+}
+
+// Differences returns the packages whose versions differ between the refs
+func (c RefComparison) Differences() []RefPackage {
+	var out []RefPackage
+	for _, p := range c.Packages {
+		if p.Differs {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// RefComparisons returns a comparison for each repository the report
+// analyzed successfully at more than one ref, in order of first appearance.
+// Failed refs are left out.
+func (r *Report) RefComparisons() []RefComparison {
+	type repoID struct{ provider, owner, repo string }
+	var order []repoID
+	byRepo := make(map[repoID][]*RepositoryReport)
+	for i := range r.Repositories {
+		rr := &r.Repositories[i]
+		if rr.Error != nil {
+			continue
+		}
+		id := repoID{rr.Provider, rr.Owner, rr.Repository}
+		if _, seen := byRepo[id]; !seen {
+			order = append(order, id)
+		}
+		byRepo[id] = append(byRepo[id], rr)
+	}
+
+	var out []RefComparison
+	for _, id := range order {
+		refs := byRepo[id]
+		if len(refs) < 2 {
+			continue
+		}
+		c := RefComparison{Provider: id.provider, Owner: id.owner, Repository: id.repo}
+		for _, rr := range refs {
+			c.Refs = append(c.Refs, rr.Ref)
+		}
+		for _, pkg := range r.Packages {
+			p := RefPackage{Package: pkg, Versions: make(map[string]string, len(refs))}
+			locked := false
+			for _, rr := range refs {
+				v := rr.Dependencies[pkg]
+				p.Versions[rr.Ref] = v
+				if v != "" {
+					locked = true
+				}
+			}
+			if !locked {
+				continue
+			}
+			eco := refs[0].GetEcosystem()
+			first := p.Versions[refs[0].Ref]
+			for _, rr := range refs[1:] {
+				v := p.Versions[rr.Ref]
+				if (v == "") != (first == "") || (v != "" && versioning.Compare(eco, v, first) != 0) {
+					p.Differs = true
+					break
+				}
+			}
+			c.Packages = append(c.Packages, p)
+		}
+		sort.SliceStable(c.Packages, func(i, j int) bool { return c.Packages[i].Package < c.Packages[j].Package })
+		out = append(out, c)
+	}
+	return out
+}
+
+// RepoLabel returns the name to show for rr: owner/repo, followed by @ref
+// when the report also includes the repository at another ref
+func (r *Report) RepoLabel(rr *RepositoryReport) string {
+	for i := range r.Repositories {
+		other := &r.Repositories[i]
+		if other.Owner == rr.Owner && other.Repository == rr.Repository && other.Ref != rr.Ref {
+			return fmt.Sprintf("%s/%s@%s", rr.Owner, rr.Repository, rr.Ref)
+		}
+	}
+	return rr.GetRepoIdentifier()
+}
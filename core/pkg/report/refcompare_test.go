@@ -0,0 +1,51 @@
+package report
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRefComparisons(t *testing.T) {
+	rpt := &Report{
+		Packages: []string{"django", "requests", "urllib3", "flask"},
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"django": "4.2.11", "requests": "2.31", "urllib3": "2.0.0"}},
+			{Provider: "github", Owner: "o", Repository: "web", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"django": "4.2.11"}},
+			{Provider: "github", Owner: "o", Repository: "api", Ref: "release/1.x", Analyzer: "poetry",
+				Dependencies: map[string]string{"django": "3.2.25", "requests": "2.31.0"}},
+			{Provider: "github", Owner: "o", Repository: "web", Ref: "stable", Error: NewErrorDetail(errors.New("boom"))},
+		},
+	}
+
+	comparisons := rpt.RefComparisons()
+	if len(comparisons) != 1 {
+		t.Fatalf("expected only o/api to be compared (o/web has one successful ref), got %+v", comparisons)
+	}
+	c := comparisons[0]
+	if c.Repository != "api" || len(c.Refs) != 2 || c.Refs[0] != "main" || c.Refs[1] != "release/1.x" {
+		t.Errorf("unexpected comparison: %+v", c)
+	}
+	if len(c.Packages) != 3 {
+		t.Fatalf("expected the three locked packages, got %+v", c.Packages)
+	}
+	diffs := c.Differences()
+	if len(diffs) != 2 || diffs[0].Package != "django" || diffs[1].Package != "urllib3" {
+		t.Errorf("expected django and urllib3 to differ (equivalent requests spellings do not), got %+v", diffs)
+	}
+	if diffs[1].Versions["release/1.x"] != "" || diffs[0].Versions["release/1.x"] != "3.2.25" {
+		t.Errorf("unexpected versions: %+v", diffs)
+	}
+
+	if got := rpt.RepoLabel(&rpt.Repositories[0]); got != "o/api@main" {
+		t.Errorf("RepoLabel = %q, want ref suffix for a repository at several refs", got)
+	}
+	if errs := rpt.GetErrors(); errs["o/web@stable"] == nil {
+		t.Errorf("GetErrors keys = %v", errs)
+	}
+	single := &Report{Repositories: rpt.Repositories[:1]}
+	if got := single.RepoLabel(&single.Repositories[0]); got != "o/api" {
+		t.Errorf("RepoLabel = %q, want owner/repo when unambiguous", got)
+	}
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+)
+
+// Regenerate re-analyzes only the repositories selected by only and merges
+// the fresh results into a copy of base, so a change to one repository (e.g.
+// a push webhook) does not re-run the whole report. repos is the full
+// configured list: it fixes package spellings and repository order exactly as
+// Generate would. Hooks run on the re-analyzed repositories only; base's
+// annotations and suppressions for the others are kept.
+//
+// A nil base behaves like Generate. Cancellation returns ctx.Err() and
+// leaves base untouched.
+func (g *Generator) Regenerate(ctx context.Context, base *Report, repos []config.RepoWithProvider, only func(config.RepoWithProvider) bool) (_ *Report, err error) {
+	if base == nil {
+		return g.Generate(ctx, repos)
+	}
+	ctx, span := telemetry.Start(ctx, "report.Regenerate")
+	start := time.Now()
+	defer func() {
+		telemetry.RecordReport(ctx, time.Since(start))
+		telemetry.End(span, err)
+	}()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	repos, packages, ecosystems := canonicalizePackages(repos, g.aliases)
+	ctx = g.withRunBudgets(ctx, repos)
+
+	// Mirrors are merged after analysis, so selecting one re-analyzes all of
+	// its project's
+	groups := mirrorGroups(repos)
+	reselect := make(map[string]bool)
+	for _, r := range repos {
+		if identity, ok := groups[repoKey(r)]; ok && only(r) {
+			reselect[identity] = true
+		}
+	}
+	var selected []config.RepoWithProvider
+	for _, r := range repos {
+		if only(r) || reselect[groups[repoKey(r)]] {
+			selected = append(selected, r)
+		}
+	}
+	slog.Info("Regenerating dependency report", "repoCount", len(selected))
+
+	var wg sync.WaitGroup
+	fresh := &Report{Repositories: make([]RepositoryReport, len(selected)), Packages: packages, PackageEcosystems: ecosystems}
+	for i, repo := range selected {
+		wg.Add(1)
+		go func(index int, r config.RepoWithProvider) {
+			defer wg.Done()
+			fresh.Repositories[index] = g.analyzeRepositoryWithTimeout(ctx, r)
+		}(i, repo)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+	fresh.snapshot = newSnapshot(fresh.Repositories)
+	g.runHooks(ctx, fresh)
+	logBudgetUsage(ctx)
+
+	// Fresh results (or their absence, when a hook suppressed them) replace
+	// base's entries for the re-analyzed repositories
+	redone := make(map[string]bool, len(selected))
+	for _, r := range selected {
+		redone[repoKey(r)] = true
+	}
+	byKey := make(map[string]RepositoryReport, len(base.Repositories)+len(fresh.Repositories))
+	for _, rr := range base.Repositories {
+		if !redone[rr.Key()] {
+			byKey[rr.Key()] = rr
+			// A merged entry stays at the position of its first mirror
+			for _, m := range rr.Mirrors {
+				byKey[m.Key()] = rr
+			}
+		}
+	}
+	for _, rr := range fresh.Repositories {
+		byKey[rr.Key()] = rr
+	}
+
+	merged := &Report{Packages: packages, PackageEcosystems: ecosystems}
+	placed := make(map[string]bool, len(byKey))
+	for _, r := range repos {
+		if rr, ok := byKey[repoKey(r)]; ok && !placed[rr.Key()] {
+			placed[rr.Key()] = true
+			merged.Repositories = append(merged.Repositories, rr)
+		}
+	}
+	for _, s := range base.Suppressed {
+		if !redone[s.Repository] {
+			merged.Suppressed = append(merged.Suppressed, s)
+		}
+	}
+	merged.Suppressed = append(merged.Suppressed, fresh.Suppressed...)
+	merged.Repositories = mergeMirrors(merged.Repositories, groups)
+	g.applyIgnores(merged)
+
+	merged.snapshot = newSnapshot(nil)
+	if base.snapshot != nil {
+		for key, entry := range base.snapshot.Repositories {
+			if !redone[key] {
+				merged.snapshot.Repositories[key] = entry
+			}
+		}
+		for key, detail := range base.snapshot.Failed {
+			if !redone[key] {
+				if merged.snapshot.Failed == nil {
+					merged.snapshot.Failed = make(map[string]*ErrorDetail)
+				}
+				merged.snapshot.Failed[key] = detail
+			}
+		}
+	}
+	for key, entry := range fresh.snapshot.Repositories {
+		merged.snapshot.Repositories[key] = entry
+	}
+	for key, detail := range fresh.snapshot.Failed {
+		if merged.snapshot.Failed == nil {
+			merged.snapshot.Failed = make(map[string]*ErrorDetail)
+		}
+		merged.snapshot.Failed[key] = detail
+	}
+	return merged, nil
+}
+
+// repoKey is RepositoryReport.Key for a configured repository
+func repoKey(r config.RepoWithProvider) string {
+	rr := RepositoryReport{Provider: r.Provider, Owner: r.Config.Owner, Repository: r.Config.Repository, Ref: r.Config.Ref}
+	return rr.Key()
+}
@@ -5,15 +5,27 @@
 package report
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/config"
 	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
 	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
 )
 
 // Report contains the results of analyzing dependencies across multiple repositories
@@ -23,6 +35,18 @@ type Report struct {
 
 	// Packages is the list of packages being tracked across repositories
 	Packages []string
+
+	// PackageEcosystems maps each tracked package to the ecosystem of the
+	// repositories tracking it (the first one, should repositories of several
+	// ecosystems track the same name). Reports not built by a Generator may
+	// leave it nil; see PackageEcosystem.
+	PackageEcosystems map[string]dependencies.Ecosystem
+
+	// Suppressed records repositories and packages removed by report hooks
+	Suppressed []Suppression
+
+	// snapshot holds the pre-hook results by commit (see Snapshot)
+	snapshot *Snapshot
 }
 
 // RepositoryReport contains dependency information for a single repository
@@ -33,33 +57,341 @@ type RepositoryReport struct {
 	Ref        string
 	Analyzer   string
 
-	// Dependencies maps package name to version (empty string if not found)
+	// Ecosystem is the naming and versioning universe of Analyzer's packages
+	// (e.g. python); empty for analyzers without one
+	Ecosystem dependencies.Ecosystem `json:",omitempty"`
+
+	// Tags are the repository's configured tags (see config.RepoConfig.Tags)
+	Tags []string
+
+	// Dependencies maps package name to version (empty string if not found).
+	// A package found in several dependency files has the version of the
+	// first file by path; FileVersions lists them all.
 	Dependencies map[string]string
 
-	// Error contains any error encountered during analysis
-	Error error
+	// FileVersions maps each tracked package found in more than one
+	// dependency file (e.g. a monorepo with a lock file per service) to the
+	// version locked by each file, by path
+	FileVersions map[string]map[string]string `json:",omitempty"`
+
+	// Error describes the failure encountered during analysis, nil when the
+	// analysis succeeded (see Err)
+	Error *ErrorDetail
+
+	// UpdatePullRequests maps a tracked package name to the oldest open
+	// dependency-update PR/MR (Dependabot, Renovate) targeting it. Only
+	// populated when the repository has UpdatePRs enabled.
+	UpdatePullRequests map[string]repository.UpdatePullRequest
+
+	// Constraints maps a tracked package name to the version constraint
+	// declared in the project manifest. Only populated when the repository
+	// has Constraints enabled and the package is declared directly.
+	Constraints map[string]string
+
+	// Annotations holds extra fields contributed by report hooks (e.g. owning
+	// team from a CMDB lookup)
+	Annotations map[string]any
+
+	// CommitSHA is the commit Ref resolved to when analyzed, and the commit
+	// its files were read at (empty when the provider could not resolve it;
+	// files were then read at Ref)
+	CommitSHA string
+
+	// ConstraintOnly is true when the repository has no lock file and was
+	// analyzed from its manifests: Dependencies then map tracked packages to
+	// "" and Constraints holds what the manifests declare
+	ConstraintOnly bool `json:",omitempty"`
+
+	// Anonymous is true when the repository was read without a token, so
+	// private repositories fail as ErrorCategoryPrivate and requests count
+	// against the provider's unauthenticated rate limit
+	Anonymous bool `json:",omitempty"`
+
+	// Cached is true when the results were reused from the previous snapshot
+	// because CommitSHA and the analysis settings did not change
+	Cached bool
+
+	// Mirrors lists the repositories merged into this entry because they
+	// mirror the same project (see config.RepoConfig.Canonical)
+	Mirrors []Mirror `json:",omitempty"`
+
+	// Graph is the package dependency graph of all locked packages (not
+	// only tracked ones). Only populated when the generator includes graphs
+	// and the lock files record dependency edges.
+	Graph *dependencies.Graph `json:",omitempty"`
+
+	// Violations lists locked packages breaking a configured policy (see
+	// Generator.SetPolicies)
+	Violations []Violation `json:",omitempty"`
+
+	// Ignored maps tracked packages matched by an ignore rule to its reason
+	// (see Generator.SetIgnoreRules); suppressed versions are left out of
+	// drift
+	Ignored map[string]Ignore `json:",omitempty"`
+
+	// DevOnly marks the tracked packages locked only as development
+	// dependencies (see Generator.SetExcludeDev)
+	DevOnly map[string]bool `json:",omitempty"`
+
+	// Provenance maps each tracked package found to the dependency file and
+	// entry its Dependencies version was read from
+	Provenance map[string]Provenance `json:",omitempty"`
+
+	// Registries maps each tracked package to the URL of the package index
+	// it was resolved from, when the lock file records one (see
+	// dependencies.Dependency.Registry)
+	Registries map[string]string `json:",omitempty"`
+
+	// Hashes maps each tracked package to the artifact digests its lock file
+	// pins, empty when it pins none. Only populated when the generator
+	// includes hashes (see Generator.SetIncludeHashes).
+	Hashes map[string][]string `json:",omitempty"`
+
+	// LockedNames maps each tracked package found under a former name (see
+	// Generator.SetPackageAliases) to the name its lock file uses
+	LockedNames map[string]string `json:",omitempty"`
+
+	// fingerprint summarizes the analysis settings (see analysisFingerprint)
+	fingerprint string
 }
 
 // PackageVersions contains all versions of a package across repositories
 type PackageVersions struct {
 	PackageName string
-	Versions    map[string][]string // version -> list of repo identifiers
+	Versions    map[string][]string // version -> list of repositories (see Report.RepoLabel)
+
+	// Sorted lists the distinct versions found in successfully analyzed
+	// repositories, lowest first, ordered by the package's ecosystem rules
+	// (PEP 440, SemVer)
+	Sorted []string
+
+	// Min and Max are the lowest and highest valid versions found (empty when
+	// no repository has a parsable version)
+	Min string
+	Max string
+}
+
+// HasDrift reports whether repositories use more than one version of the
+// package. Equivalent spellings (e.g. "1.0" and "1.0.0") are not drift.
+func (pv PackageVersions) HasDrift() bool {
+	return pv.Min != "" && pv.Min != pv.Max
+}
+
+// Provenance records where a tracked package's reported version was read
+type Provenance struct {
+	File   string `json:"file"`             // Dependency file path
+	Type   string `json:"type,omitempty"`   // Dependency type (runtime, dev or optional)
+	Source string `json:"source,omitempty"` // Where it is installed from (pypi, git, path, ...)
 }
 
 // Generator generates dependency reports for multiple repositories
 type Generator struct {
-	depFactory *dependencies.Factory
+	depFactory  *dependencies.Factory
+	retryPolicy *repository.RetryPolicy
+	repoTimeout time.Duration
+	reqTimeout  time.Duration
+	concurrency int
+	hooks       []Hook
+	ignores     []IgnoreRule
+	previous    *Snapshot
+	budget      *config.BudgetConfig
+	maxFileSize int64
+	graphs      bool
+	hashes      bool
+	excludeDev  bool
+	archives    bool
+	cache       *repository.ContentCache
+	policies    []Policy
+	observer    func(RepositoryEvent)
+	tracer      *repository.HTTPTracer
+	fixtures    *repository.Fixtures
+	aliases     packageAliases
+
+	// newClient creates repository clients; replaceable in tests
+	newClient func(provider string, cfg repository.Config) (repository.Client, error)
 }
 
-// NewGenerator creates a new report generator
+// NewGenerator creates a new report generator using the default retry policy
 func NewGenerator() *Generator {
+	retry := repository.DefaultRetryPolicy()
 	return &Generator{
-		depFactory: dependencies.NewFactory(),
+		depFactory:  dependencies.NewFactory(),
+		retryPolicy: &retry,
+		newClient: func(provider string, cfg repository.Config) (repository.Client, error) {
+			return repository.NewFactory(cfg).CreateClient(provider)
+		},
+	}
+}
+
+// SetRepositoryTimeout bounds the time spent analyzing each repository.
+// Zero (the default) leaves repositories limited only by the run's context.
+func (g *Generator) SetRepositoryTimeout(d time.Duration) {
+	g.repoTimeout = d
+}
+
+// RepositoryTimeout returns the limit set by SetRepositoryTimeout
+func (g *Generator) RepositoryTimeout() time.Duration {
+	return g.repoTimeout
+}
+
+// SetRequestTimeout bounds each provider API request attempt (see
+// repository.Config.RequestTimeout). Zero (the default) leaves requests
+// limited only by the repository's deadline.
+func (g *Generator) SetRequestTimeout(d time.Duration) {
+	g.reqTimeout = d
+}
+
+// RequestTimeout returns the limit set by SetRequestTimeout
+func (g *Generator) RequestTimeout() time.Duration {
+	return g.reqTimeout
+}
+
+// SetConcurrency limits how many repositories are analyzed at once. Zero
+// (the default) analyzes every repository in parallel.
+func (g *Generator) SetConcurrency(n int) {
+	g.concurrency = n
+}
+
+// SetMaxFileSize bounds the bytes read from a single dependency file (see
+// repository.Config.MaxFileSize); larger files are skipped with an error.
+func (g *Generator) SetMaxFileSize(n int64) {
+	g.maxFileSize = n
+}
+
+// SetIncludeGraph makes each RepositoryReport carry the dependency graph of
+// its lock files (see RepositoryReport.Graph)
+func (g *Generator) SetIncludeGraph(include bool) {
+	g.graphs = include
+}
+
+// SetUseArchives makes repositories whose provider can download archives
+// (repository.ArchiveDownloader) be read from one archive of the analyzed
+// commit instead of a request per listed directory and fetched file. A
+// failed download falls back to per-file requests.
+func (g *Generator) SetUseArchives(use bool) {
+	g.archives = use
+}
+
+// SetContentCache makes repositories read listings and dependency files at
+// their resolved commit through cache, so unchanged commits are not fetched
+// again on later runs. Nil (the default) fetches everything.
+func (g *Generator) SetContentCache(cache *repository.ContentCache) {
+	g.cache = cache
+}
+
+// SetExcludeDev leaves packages locked as development dependencies
+// (dependencies.Dependency.Type "dev") out of the report, as if not locked.
+// By default they are reported, and those locked only for development are
+// listed in RepositoryReport.DevOnly.
+func (g *Generator) SetExcludeDev(exclude bool) {
+	g.excludeDev = exclude
+}
+
+// RepositoryEvent reports a repository's analysis starting or finishing
+type RepositoryEvent struct {
+	Key       string            // RepositoryReport.Key of the repository
+	Done      bool              // False when analysis starts, true when it finishes
+	Anonymous bool              // The repository is read without a token (see RepositoryReport.Anonymous)
+	Report    *RepositoryReport // The repository's result (Done only; before hooks run)
+}
+
+// SetObserver registers fn to be called as each repository's analysis starts
+// and finishes, e.g. to display progress. fn is called from the goroutines
+// analyzing repositories, concurrently, and must not block for long.
+func (g *Generator) SetObserver(fn func(RepositoryEvent)) {
+	g.observer = fn
+}
+
+// notify passes ev to the observer, if any
+func (g *Generator) notify(ev RepositoryEvent) {
+	if g.observer != nil {
+		g.observer(ev)
 	}
 }
 
-// Generate creates a dependency report for the given repository configurations
-func (g *Generator) Generate(ctx context.Context, repos []config.RepoWithProvider) (*Report, error) {
+// SetRetryPolicy overrides the retry policy applied to repository clients.
+// A nil policy restores each provider library's default behavior.
+func (g *Generator) SetRetryPolicy(policy *repository.RetryPolicy) {
+	g.retryPolicy = policy
+}
+
+// SetHTTPTracer records every provider API request of subsequent runs with
+// tracer (see repository.HTTPTracer); nil disables tracing
+func (g *Generator) SetHTTPTracer(tracer *repository.HTTPTracer) {
+	g.tracer = tracer
+}
+
+// SetFixtures records the provider API responses of subsequent runs, or
+// replays recorded ones offline (see repository.Fixtures); nil disables both
+func (g *Generator) SetFixtures(fixtures *repository.Fixtures) {
+	g.fixtures = fixtures
+}
+
+// RetryPolicyFromConfig builds a repository retry policy from configuration,
+// starting from repository.DefaultRetryPolicy and overriding non-zero fields.
+func RetryPolicyFromConfig(cfg *config.RetryConfig) *repository.RetryPolicy {
+	policy := repository.DefaultRetryPolicy()
+	if cfg == nil {
+		return &policy
+	}
+	if cfg.MaxAttempts > 0 {
+		policy.MaxAttempts = cfg.MaxAttempts
+	}
+	if cfg.InitialBackoff > 0 {
+		policy.InitialBackoff = cfg.InitialBackoff
+	}
+	if cfg.MaxBackoff > 0 {
+		policy.MaxBackoff = cfg.MaxBackoff
+	}
+	if len(cfg.RetryableStatusCodes) > 0 {
+		policy.RetryableStatusCodes = cfg.RetryableStatusCodes
+	}
+	return &policy
+}
+
+// NewGeneratorFromConfig creates a generator with the configuration's retry
+// policy, budgets, timeouts, file size limit, policies and hooks. The run
+// timeout is the caller's to apply to the context passed to Generate.
+func NewGeneratorFromConfig(cfg *config.Config) (*Generator, error) {
+	policies, err := PoliciesFromConfig(cfg.Policies)
+	if err != nil {
+		return nil, err
+	}
+	ignores, err := IgnoreRulesFromConfig(cfg.Ignores)
+	if err != nil {
+		return nil, err
+	}
+	g := NewGenerator()
+	if cfg.Retry != nil {
+		g.SetRetryPolicy(RetryPolicyFromConfig(cfg.Retry))
+	}
+	g.SetBudget(cfg.Budget)
+	if cfg.Timeouts != nil {
+		g.SetRepositoryTimeout(cfg.Timeouts.Repository)
+		g.SetRequestTimeout(cfg.Timeouts.Request)
+	}
+	g.SetMaxFileSize(cfg.MaxFileSize)
+	g.SetPolicies(policies)
+	g.SetIgnoreRules(ignores)
+	g.SetPackageAliases(cfg.PackageAliases)
+	for _, hook := range HooksFromConfig(cfg.Hooks) {
+		g.AddHook(hook)
+	}
+	return g, nil
+}
+
+// Generate creates a dependency report for the given repository configurations.
+//
+// If ctx's deadline expires mid-run, repositories that completed are still
+// returned and the remaining ones carry a *TimeoutError. Cancellation (as
+// opposed to a deadline) aborts the run and returns ctx.Err().
+func (g *Generator) Generate(ctx context.Context, repos []config.RepoWithProvider) (_ *Report, err error) {
+	ctx, span := telemetry.Start(ctx, "report.Generate", attribute.Int("devdashboard.repositories", len(repos)))
+	start := time.Now()
+	defer func() {
+		telemetry.RecordReport(ctx, time.Since(start))
+		telemetry.End(span, err)
+	}()
 	slog.Info("Starting dependency report generation", "repoCount", len(repos))
 
 	// Check if context is already canceled
@@ -67,45 +399,193 @@ func (g *Generator) Generate(ctx context.Context, repos []config.RepoWithProvide
 		return nil, ctx.Err()
 	}
 
-	// Collect all unique packages to track
-	packageSet := make(map[string]bool)
-	for _, repo := range repos {
-		for _, pkg := range repo.Config.Packages {
-			packageSet[pkg] = true
-		}
-	}
+	// Collect all unique packages to track, merging spellings that normalize
+	// to the same name (e.g. "PyYAML" and "pyyaml") under the first one seen
+	// and former names of aliased packages under the current one
+	repos, packages, ecosystems := canonicalizePackages(repos, g.aliases)
+	ctx = g.withRunBudgets(ctx, repos)
 
-	packages := make([]string, 0, len(packageSet))
-	for pkg := range packageSet {
-		packages = append(packages, pkg)
-	}
-	sort.Strings(packages)
-
-	// Analyze repositories in parallel
+	// Analyze repositories in parallel, at most g.concurrency at a time. A
+	// repository's timeout starts once it gets a slot.
 	var wg sync.WaitGroup
 	repoReports := make([]RepositoryReport, len(repos))
+	var slots chan struct{}
+	if g.concurrency > 0 {
+		slots = make(chan struct{}, g.concurrency)
+	}
 
 	for i, repo := range repos {
 		wg.Add(1)
 		go func(index int, r config.RepoWithProvider) {
 			defer wg.Done()
-			repoReports[index] = g.analyzeRepository(ctx, r)
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					// Analysis fails fast and is reported as timed out
+				}
+			}
+			key, anonymous := repoKey(r), r.Config.Token == ""
+			g.notify(RepositoryEvent{Key: key, Anonymous: anonymous})
+			repoReports[index] = g.analyzeRepositoryWithTimeout(ctx, r)
+			g.notify(RepositoryEvent{Key: key, Done: true, Anonymous: anonymous, Report: &repoReports[index]})
 		}(i, repo)
 	}
 
 	wg.Wait()
 
-	// Check if context was canceled during analysis
+	// Check if context was canceled during analysis; a deadline keeps partial results
+	if err := ctx.Err(); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		timedOut := 0
+		for _, rr := range repoReports {
+			if rr.ErrorCategory() == ErrorCategoryTimeout {
+				timedOut++
+			}
+		}
+		slog.Warn("Report deadline exceeded; returning partial results",
+			"completed", len(repoReports)-timedOut,
+			"timedOut", timedOut)
+	}
+
+	rpt := &Report{
+		Repositories:      mergeMirrors(repoReports, mirrorGroups(repos)),
+		Packages:          packages,
+		PackageEcosystems: ecosystems,
+		snapshot:          newSnapshot(repoReports),
+	}
+	g.runHooks(ctx, rpt)
+	g.applyIgnores(rpt)
+
+	cached := 0
+	for _, rr := range repoReports {
+		if rr.Cached {
+			cached++
+		}
+	}
+	logBudgetUsage(ctx)
+	slog.Info("Dependency report generation complete", "repoCount", len(repos), "cached", cached)
+
+	return rpt, nil
+}
+
+// canonicalizePackages returns the sorted set of tracked package names, the
+// ecosystem of each, and a copy of repos whose Packages use those names.
+// Former names of aliased packages are replaced by the current name, then
+// names are deduplicated by their ecosystem-normalized form; the first
+// configured spelling is kept for display, so every repository reports the
+// package under the same column.
+func canonicalizePackages(repos []config.RepoWithProvider, aliases packageAliases) ([]config.RepoWithProvider, []string, map[string]dependencies.Ecosystem) {
+	display := make(map[string]string)
+	packages := make([]string, 0)
+	ecosystems := make(map[string]dependencies.Ecosystem)
+	out := make([]config.RepoWithProvider, len(repos))
+	for i, repo := range repos {
+		eco := dependencies.EcosystemForAnalyzer(repo.Config.Analyzer)
+		pkgs := make([]string, 0, len(repo.Config.Packages))
+		for _, pkg := range repo.Config.Packages {
+			pkg = aliases.canonical(eco, pkg)
+			key := string(eco) + ":" + dependencies.NormalizeName(eco, pkg)
+			name, ok := display[key]
+			if !ok {
+				name = pkg
+				display[key] = pkg
+				packages = append(packages, pkg)
+			}
+			if _, ok := ecosystems[name]; !ok {
+				ecosystems[name] = eco
+			}
+			if !slices.Contains(pkgs, name) {
+				pkgs = append(pkgs, name)
+			}
+		}
+		repo.Config.Packages = pkgs
+		out[i] = repo
+	}
+	sort.Strings(packages)
+	return out, packages, ecosystems
+}
+
+// analyzeRepositoryWithTimeout runs analyzeRepository under the per-repository
+// timeout (if any) and marks the result as timed out when the run or repository
+// deadline expired before analysis finished.
+func (g *Generator) analyzeRepositoryWithTimeout(ctx context.Context, repo config.RepoWithProvider) (rr RepositoryReport) {
+	ctx, span := telemetry.Start(ctx, "report.analyzeRepository",
+		attribute.String("devdashboard.repository", repoKey(repo)),
+		attribute.String("devdashboard.provider", repo.Provider),
+		attribute.String("devdashboard.analyzer", repo.Config.Analyzer))
+	start := time.Now()
+	defer func() { endRepositorySpan(ctx, span, start, &rr) }()
+
+	repoCtx := ctx
+	if g.repoTimeout > 0 {
+		var cancel context.CancelFunc
+		repoCtx, cancel = context.WithTimeout(ctx, g.repoTimeout)
+		defer cancel()
+	}
+
+	rr = g.analyzeRepository(repoCtx, repo)
+	// An analysis that completed just before the deadline keeps its results;
+	// only failures the deadline caused become timeouts
+	if rr.Error == nil || !errors.Is(repoCtx.Err(), context.DeadlineExceeded) || !errors.Is(rr.Error, context.DeadlineExceeded) {
+		return rr
+	}
+
+	var cause error
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		cause = fmt.Errorf("repository %s: report deadline exceeded before analysis completed: %w", rr.Key(), context.DeadlineExceeded)
+	} else {
+		cause = fmt.Errorf("repository %s exceeded %s analysis timeout: %w", rr.Key(), g.repoTimeout, context.DeadlineExceeded)
 	}
+	slog.Debug("Repository analysis timed out",
+		"owner", repo.Config.Owner,
+		"repo", repo.Config.Repository,
+		"error", cause)
+	rr.Error = NewErrorDetail(&TimeoutError{Err: cause})
+	return rr
+}
 
-	slog.Info("Dependency report generation complete", "repoCount", len(repos))
+// endRepositorySpan records rr's outcome on its analysis span and metric
+func endRepositorySpan(ctx context.Context, span trace.Span, start time.Time, rr *RepositoryReport) {
+	outcome := "ok"
+	switch {
+	case rr.Error != nil:
+		outcome = string(rr.ErrorCategory())
+	case rr.Cached:
+		outcome = "cached"
+	}
+	span.SetAttributes(
+		attribute.String("devdashboard.outcome", outcome),
+		attribute.String("devdashboard.commit", rr.CommitSHA),
+		attribute.Int("devdashboard.dependencies", len(rr.Dependencies)),
+	)
+	telemetry.RecordRepository(ctx, time.Since(start), rr.Provider, rr.Analyzer, outcome)
+	telemetry.End(span, rr.Err())
+}
 
-	return &Report{
-		Repositories: repoReports,
-		Packages:     packages,
-	}, nil
+// withRetryLogging returns a context whose retry observer logs each retry and
+// forwards it to the observer already present in ctx, if any.
+func withRetryLogging(ctx context.Context, repo config.RepoWithProvider) context.Context {
+	parent := repository.RetryObserverFromContext(ctx)
+	scope := fmt.Sprintf("%s:%s/%s@%s", repo.Provider, repo.Config.Owner, repo.Config.Repository, repo.Config.Ref)
+	return repository.WithRetryObserver(ctx, func(ev repository.RetryEvent) {
+		ev.Scope = scope
+		slog.Warn("Retrying provider request",
+			"repo", scope,
+			"method", ev.Method,
+			"url", ev.URL,
+			"attempt", ev.Attempt,
+			"maxAttempts", ev.MaxAttempts,
+			"status", ev.StatusCode,
+			"error", ev.Err,
+			"delay", ev.Delay)
+		if parent != nil {
+			parent(ev)
+		}
+	})
 }
 
 // analyzeRepository analyzes a single repository and extracts dependency versions
@@ -116,42 +596,97 @@ func (g *Generator) analyzeRepository(ctx context.Context, repo config.RepoWithP
 		Repository:   repo.Config.Repository,
 		Ref:          repo.Config.Ref,
 		Analyzer:     repo.Config.Analyzer,
+		Ecosystem:    dependencies.EcosystemForAnalyzer(repo.Config.Analyzer),
+		Tags:         repo.Config.Tags,
 		Dependencies: make(map[string]string),
+		Anonymous:    repo.Config.Token == "",
 	}
 
 	slog.Debug("Analyzing repository",
 		"provider", repo.Provider,
 		"owner", repo.Config.Owner,
 		"repo", repo.Config.Repository,
-		"analyzer", repo.Config.Analyzer)
+		"analyzer", repo.Config.Analyzer,
+		"anonymous", report.Anonymous)
+
+	// Log retries for this repository and forward them (tagged with the
+	// repository ID) to any observer registered by the caller.
+	ctx = withRetryLogging(ctx, repo)
 
 	// Create repository client
-	repoFactory := repository.NewFactory(repository.Config{
-		Token: repo.Config.Token,
+	repoClient, err := g.newClient(repo.Provider, repository.Config{
+		Token:              repo.Config.Token,
+		BaseURL:            repo.Config.BaseURL,
+		Enterprise:         repo.Config.Enterprise,
+		APIPrefix:          repo.Config.APIPrefix,
+		Retry:              g.retryPolicy,
+		Budgets:            repositoryBudgets(ctx, repo, g.budget),
+		MaxFileSize:        g.maxFileSize,
+		Proxy:              repo.Config.Proxy,
+		CAFile:             repo.Config.CAFile,
+		InsecureSkipVerify: repo.Config.InsecureSkipVerify,
+		Tracer:             g.tracer,
+		Fixtures:           g.fixtures,
+		RequestTimeout:     g.reqTimeout,
 	})
-	repoClient, err := repoFactory.CreateClient(repo.Provider)
 	if err != nil {
-		report.Error = fmt.Errorf("failed to create repository client: %w", err)
+		report.Error = NewErrorDetail(exitcode.Errorf(exitcode.ConfigError, "failed to create repository client: %w", err))
 		slog.Debug("Failed to create repository client",
 			"provider", repo.Provider,
 			"error", err)
 		return report
 	}
 
+	// Skip fetching and parsing when the ref still points at the commit
+	// recorded by the previous run
+	if g.resolveCommit(ctx, repoClient, repo, &report) {
+		if repo.Config.UpdatePRs {
+			collectUpdatePullRequests(ctx, repoClient, repo, &report)
+		}
+		return report
+	}
+	// Read files at the resolved commit, so the results match CommitSHA even
+	// when the branch moves during the run
+	ref := cmp.Or(report.CommitSHA, repo.Config.Ref)
+
 	// Create dependency analyzer
 	analyzer, err := g.depFactory.CreateAnalyzer(repo.Config.Analyzer)
 	if err != nil {
-		report.Error = fmt.Errorf("failed to create analyzer: %w", err)
+		report.Error = NewErrorDetail(exitcode.Errorf(exitcode.ConfigError, "failed to create analyzer: %w", err))
 		slog.Debug("Failed to create analyzer",
 			"analyzer", repo.Config.Analyzer,
 			"error", err)
 		return report
 	}
 
-	// Configure dependency analyzer
+	// Read listings and dependency files from one archive download if enabled
+	depClient := repoClient
+	if g.archives {
+		archived, err := repository.NewArchiveClient(ctx, repoClient, repo.Config.Owner, repo.Config.Repository, ref, dependencies.IsDependencyFile, g.maxFileSize)
+		if err != nil {
+			slog.Debug("Archive download unavailable; fetching files individually",
+				"owner", repo.Config.Owner,
+				"repo", repo.Config.Repository,
+				"error", err)
+		} else {
+			depClient = archived
+		}
+	}
+	if g.cache != nil && depClient == repoClient {
+		depClient = g.cache.Client(repo.Provider+" "+repo.Config.BaseURL, repoClient)
+	}
+
+	// Configure dependency analyzer, remembering files it had to skip so a
+	// repository where every file failed reports why
+	var fileErrs []error
 	depConfig := dependencies.Config{
 		RepositoryPaths:  repo.Config.Paths,
-		RepositoryClient: repoClient,
+		ExcludePaths:     repo.Config.ExcludePaths,
+		RepositoryClient: depClient,
+		OnFileError: func(_ string, err error) {
+			fileErrs = append(fileErrs, err)
+		},
+		IncludeConstraints: repo.Config.Constraints,
 	}
 
 	// Find dependency files
@@ -178,9 +713,9 @@ func (g *Generator) analyzeRepository(ctx context.Context, repo config.RepoWithP
 			"repo", repo.Config.Repository)
 
 		var err error
-		candidates, err = analyzer.CandidateFiles(ctx, repo.Config.Owner, repo.Config.Repository, repo.Config.Ref, depConfig)
+		candidates, err = analyzer.CandidateFiles(ctx, repo.Config.Owner, repo.Config.Repository, ref, depConfig)
 		if err != nil {
-			report.Error = fmt.Errorf("failed to find dependency files: %w", err)
+			report.Error = NewErrorDetail(classifyAnonymous(ctx, repoClient, repo, classifyError(fmt.Errorf("failed to find dependency files: %w", err))))
 			slog.Debug("Failed to find dependency files",
 				"owner", repo.Config.Owner,
 				"repo", repo.Config.Repository,
@@ -188,8 +723,21 @@ func (g *Generator) analyzeRepository(ctx context.Context, repo config.RepoWithP
 			return report
 		}
 
+		// Projects committing only a manifest are reported constraint-only
+		if fb, ok := analyzer.(dependencies.ManifestFallback); ok && len(candidates) == 0 {
+			manifest := fb.ManifestAnalyzer()
+			found, err := manifest.CandidateFiles(ctx, repo.Config.Owner, repo.Config.Repository, ref, depConfig)
+			if err == nil && len(found) > 0 {
+				slog.Debug("No lock files found; reading manifests",
+					"owner", repo.Config.Owner,
+					"repo", repo.Config.Repository,
+					"analyzer", manifest.Name())
+				analyzer, candidates = manifest, found
+			}
+		}
+
 		if len(candidates) == 0 {
-			report.Error = fmt.Errorf("no dependency files found")
+			report.Error = NewErrorDetail(&NotFoundError{Err: fmt.Errorf("no dependency files found")})
 			slog.Debug("No dependency files found",
 				"owner", repo.Config.Owner,
 				"repo", repo.Config.Repository)
@@ -197,15 +745,20 @@ func (g *Generator) analyzeRepository(ctx context.Context, repo config.RepoWithP
 		}
 	}
 
+	_, report.ConstraintOnly = analyzer.(*dependencies.ManifestAnalyzer)
+
 	slog.Debug("Found dependency files",
 		"owner", repo.Config.Owner,
 		"repo", repo.Config.Repository,
 		"count", len(candidates))
 
 	// Analyze dependencies
-	results, err := analyzer.AnalyzeDependencies(ctx, repo.Config.Owner, repo.Config.Repository, repo.Config.Ref, candidates, depConfig)
+	results, err := analyzer.AnalyzeDependencies(ctx, repo.Config.Owner, repo.Config.Repository, ref, candidates, depConfig)
+	if err == nil && len(results) == 0 && len(fileErrs) > 0 {
+		err = fileErrs[0]
+	}
 	if err != nil {
-		report.Error = fmt.Errorf("failed to analyze dependencies: %w", err)
+		report.Error = NewErrorDetail(classifyAnonymous(ctx, repoClient, repo, classifyError(fmt.Errorf("failed to analyze dependencies: %w", err))))
 		slog.Debug("Failed to analyze dependencies",
 			"owner", repo.Config.Owner,
 			"repo", repo.Config.Repository,
@@ -213,21 +766,103 @@ func (g *Generator) analyzeRepository(ctx context.Context, repo config.RepoWithP
 		return report
 	}
 
-	// Extract versions for requested packages
-	for _, deps := range results {
-		for _, dep := range deps {
+	// Extract versions for requested packages, matching normalized names so
+	// lock file spelling differences don't hide a tracked package, and former
+	// names of aliased packages
+	eco := dependencies.EcosystemForAnalyzer(repo.Config.Analyzer)
+	if g.graphs {
+		report.Graph = dependencies.BuildGraph(eco, results)
+	}
+	report.Violations = g.checkPolicies(repo, report.Key(), results)
+	tracked := make(map[string]string, len(repo.Config.Packages))
+	for _, pkg := range repo.Config.Packages {
+		tracked[dependencies.NormalizeName(eco, pkg)] = pkg
+	}
+	for _, pkg := range repo.Config.Packages {
+		for _, alias := range g.aliases.formerNames(eco, pkg) {
+			if _, ok := tracked[dependencies.NormalizeName(eco, alias)]; !ok {
+				tracked[dependencies.NormalizeName(eco, alias)] = pkg
+			}
+		}
+	}
+	fileVersions := make(map[string]map[string]string)
+	runtime := make(map[string]bool) // Tracked packages locked other than for development
+	for _, path := range slices.Sorted(maps.Keys(results)) {
+		for _, dep := range results[path] {
 			// Check if this is a package we're tracking
-			for _, pkg := range repo.Config.Packages {
-				if dep.Name == pkg {
-					report.Dependencies[pkg] = dep.Version
-					slog.Debug("Found tracked package",
-						"package", pkg,
-						"version", dep.Version,
-						"repo", repo.Config.Repository)
-					break
+			pkg, ok := tracked[dependencies.NormalizeName(eco, dep.Name)]
+			if !ok || (g.excludeDev && dep.Type == "dev") {
+				continue
+			}
+			if dep.Type != "dev" {
+				runtime[pkg] = true
+			}
+			if fileVersions[pkg] == nil {
+				fileVersions[pkg] = make(map[string]string)
+			}
+			if _, seen := fileVersions[pkg][path]; !seen {
+				fileVersions[pkg][path] = intern(dep.Version)
+			}
+			if _, seen := report.Dependencies[pkg]; seen {
+				continue
+			}
+			report.Dependencies[pkg] = intern(dep.Version)
+			if report.Provenance == nil {
+				report.Provenance = make(map[string]Provenance)
+			}
+			report.Provenance[pkg] = Provenance{File: intern(path), Type: intern(dep.Type), Source: intern(dep.Source)}
+			if dependencies.NormalizeName(eco, dep.Name) != dependencies.NormalizeName(eco, pkg) {
+				if report.LockedNames == nil {
+					report.LockedNames = make(map[string]string)
+				}
+				report.LockedNames[pkg] = dep.Name
+			}
+			if dep.Registry != "" {
+				if report.Registries == nil {
+					report.Registries = make(map[string]string)
+				}
+				report.Registries[pkg] = intern(dep.Registry)
+			}
+			if g.hashes {
+				if report.Hashes == nil {
+					report.Hashes = make(map[string][]string)
+				}
+				report.Hashes[pkg] = append([]string{}, dep.Hashes...)
+			}
+			if dep.Constraint != "" {
+				if report.Constraints == nil {
+					report.Constraints = make(map[string]string)
 				}
+				report.Constraints[pkg] = intern(dep.Constraint)
 			}
+			slog.Debug("Found tracked package",
+				"package", pkg,
+				"name", dep.Name,
+				"version", dep.Version,
+				"file", path,
+				"repo", repo.Config.Repository)
+		}
+	}
+	for pkg := range report.Dependencies {
+		if !runtime[pkg] {
+			if report.DevOnly == nil {
+				report.DevOnly = make(map[string]bool)
+			}
+			report.DevOnly[pkg] = true
+		}
+	}
+	for pkg, files := range fileVersions {
+		if len(files) < 2 {
+			continue
+		}
+		if report.FileVersions == nil {
+			report.FileVersions = make(map[string]map[string]string)
 		}
+		report.FileVersions[pkg] = files
+	}
+
+	if repo.Config.UpdatePRs {
+		collectUpdatePullRequests(ctx, repoClient, repo, &report)
 	}
 
 	slog.Debug("Repository analysis complete",
@@ -238,31 +873,129 @@ func (g *Generator) analyzeRepository(ctx context.Context, repo config.RepoWithP
 	return report
 }
 
+// collectUpdatePullRequests annotates the report with open dependency-update
+// PRs/MRs for tracked packages. Failures are logged and otherwise ignored so
+// enrichment never fails an otherwise successful analysis.
+func collectUpdatePullRequests(ctx context.Context, client repository.Client, repo config.RepoWithProvider, report *RepositoryReport) {
+	lister, ok := client.(repository.UpdatePullRequestLister)
+	if !ok {
+		slog.Debug("Provider does not support update PR listing", "provider", repo.Provider)
+		return
+	}
+
+	prs, err := lister.ListUpdatePullRequests(ctx, repo.Config.Owner, repo.Config.Repository)
+	if err != nil {
+		slog.Debug("Failed to list update pull requests",
+			"owner", repo.Config.Owner,
+			"repo", repo.Config.Repository,
+			"error", err)
+		return
+	}
+
+	eco := dependencies.EcosystemForAnalyzer(repo.Config.Analyzer)
+	for _, pr := range prs {
+		for _, pkg := range repo.Config.Packages {
+			if !strings.EqualFold(dependencies.NormalizeName(eco, pr.Package), dependencies.NormalizeName(eco, pkg)) {
+				continue
+			}
+			if report.UpdatePullRequests == nil {
+				report.UpdatePullRequests = make(map[string]repository.UpdatePullRequest)
+			}
+			// Keep the oldest open PR per package; that is the one most likely stuck.
+			if existing, found := report.UpdatePullRequests[pkg]; !found || pr.CreatedAt.Before(existing.CreatedAt) {
+				report.UpdatePullRequests[pkg] = pr
+			}
+			break
+		}
+	}
+}
+
 // GetPackageVersions returns version information grouped by package
 func (r *Report) GetPackageVersions() []PackageVersions {
+	m := r.Matrix()
 	result := make([]PackageVersions, len(r.Packages))
-
 	for i, pkg := range r.Packages {
-		pv := PackageVersions{
-			PackageName: pkg,
-			Versions:    make(map[string][]string),
-		}
+		result[i] = m.PackageVersions(pkg)
+	}
+	return result
+}
 
-		for _, repoReport := range r.Repositories {
-			repoID := fmt.Sprintf("%s/%s", repoReport.Owner, repoReport.Repository)
+// PackageEcosystem returns the ecosystem of pkg, used to pick version
+// ordering rules: its PackageEcosystems entry or, without one, the ecosystem
+// of the first repository reporting a version of it (or of the first
+// repository, when none does)
+func (r *Report) PackageEcosystem(pkg string) dependencies.Ecosystem {
+	if eco, ok := r.PackageEcosystems[pkg]; ok {
+		return eco
+	}
+	for i := range r.Repositories {
+		if _, ok := r.Repositories[i].Dependencies[pkg]; ok {
+			return r.Repositories[i].GetEcosystem()
+		}
+	}
+	if len(r.Repositories) > 0 {
+		return r.Repositories[0].GetEcosystem()
+	}
+	return dependencies.EcosystemUnknown
+}
 
-			if version, found := repoReport.Dependencies[pkg]; found {
-				pv.Versions[version] = append(pv.Versions[version], repoID)
-			} else {
-				// Package not found in this repository
-				pv.Versions[""] = append(pv.Versions[""], repoID)
-			}
+// IsOutdated reports whether version sorts below the highest version of pkg
+// found across the report's successfully analyzed repositories, leaving out
+// versions suppressed by ignore rules
+func (r *Report) IsOutdated(pkg, version string) bool {
+	if version == "" {
+		return false
+	}
+	eco := r.PackageEcosystem(pkg)
+	versions := make([]string, 0, len(r.Repositories))
+	for _, rr := range r.Repositories {
+		if rr.Error != nil || rr.Suppressed(pkg) {
+			continue
+		}
+		if v := rr.Dependencies[pkg]; v != "" {
+			versions = append(versions, v)
 		}
+	}
+	_, highest := versioning.MinMax(eco, versions)
+	return highest != "" && versioning.Compare(eco, version, highest) < 0
+}
 
-		result[i] = pv
+// GetEcosystem returns Ecosystem or, when unset (e.g. a report decoded from
+// older JSON), the ecosystem of Analyzer
+func (r *RepositoryReport) GetEcosystem() dependencies.Ecosystem {
+	if r.Ecosystem != "" {
+		return r.Ecosystem
 	}
+	return dependencies.EcosystemForAnalyzer(r.Analyzer)
+}
 
-	return result
+// Inconsistent reports whether the dependency files of the repository lock
+// pkg at different versions
+func (r *RepositoryReport) Inconsistent(pkg string) bool {
+	eco := r.GetEcosystem()
+	var first string
+	for i, path := range slices.Sorted(maps.Keys(r.FileVersions[pkg])) {
+		v := r.FileVersions[pkg][path]
+		if i == 0 {
+			first = v
+		} else if versioning.Compare(eco, v, first) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// InconsistentPackages returns the sorted tracked packages locked at
+// different versions by different dependency files (see Inconsistent)
+func (r *RepositoryReport) InconsistentPackages() []string {
+	var pkgs []string
+	for pkg := range r.FileVersions {
+		if r.Inconsistent(pkg) {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	slices.Sort(pkgs)
+	return pkgs
 }
 
 // GetRepoIdentifier returns a human-readable identifier for a repository report
@@ -270,6 +1003,35 @@ func (r *RepositoryReport) GetRepoIdentifier() string {
 	return fmt.Sprintf("%s/%s", r.Owner, r.Repository)
 }
 
+// Key returns the fully qualified identifier provider:owner/repo@ref, unique
+// within a report even when the same repository is tracked at several refs
+func (r *RepositoryReport) Key() string {
+	return fmt.Sprintf("%s:%s/%s@%s", r.Provider, r.Owner, r.Repository, r.Ref)
+}
+
+// ResolvePackage maps a package name to the spelling used in this report (an
+// entry of Packages), comparing ecosystem-normalized names so a user-entered
+// "pyyaml" finds the "PyYAML" column. Unmatched names are returned unchanged.
+func (r *Report) ResolvePackage(name string) string {
+	ecosystems := make(map[dependencies.Ecosystem]bool)
+	for _, rr := range r.Repositories {
+		ecosystems[rr.GetEcosystem()] = true
+	}
+	for _, pkg := range r.Packages {
+		if pkg == name {
+			return pkg
+		}
+	}
+	for _, pkg := range r.Packages {
+		for eco := range ecosystems {
+			if dependencies.NormalizeName(eco, pkg) == dependencies.NormalizeName(eco, name) {
+				return pkg
+			}
+		}
+	}
+	return name
+}
+
 // HasErrors returns true if any repository analysis encountered an error
 func (r *Report) HasErrors() bool {
 	for _, repo := range r.Repositories {
@@ -280,13 +1042,44 @@ func (r *Report) HasErrors() bool {
 	return false
 }
 
-// GetErrors returns all errors encountered during analysis
+// GetErrors returns all errors encountered during analysis, keyed by
+// RepoLabel
 func (r *Report) GetErrors() map[string]error {
 	errors := make(map[string]error)
-	for _, repo := range r.Repositories {
-		if repo.Error != nil {
-			errors[repo.GetRepoIdentifier()] = repo.Error
+	for i := range r.Repositories {
+		if repo := &r.Repositories[i]; repo.Error != nil {
+			errors[r.RepoLabel(repo)] = repo.Error
 		}
 	}
 	return errors
 }
+
+// Err summarizes repository failures as an error classified for exit status.
+// Returns nil when every repository succeeded, exitcode.Partial when some
+// succeeded, and otherwise exitcode.ConfigError or exitcode.ProviderError if
+// any failure carries that class (config first), falling back to exitcode.Failure.
+func (r *Report) Err() error {
+	failed := 0
+	code := exitcode.Failure
+	for _, repo := range r.Repositories {
+		if repo.Error == nil {
+			continue
+		}
+		failed++
+		switch exitcode.FromError(repo.Error) {
+		case exitcode.ConfigError:
+			code = exitcode.ConfigError
+		case exitcode.ProviderError:
+			if code != exitcode.ConfigError {
+				code = exitcode.ProviderError
+			}
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	if failed < len(r.Repositories) {
+		code = exitcode.Partial
+	}
+	return exitcode.Errorf(code, "one or more repositories failed (%d of %d)", failed, len(r.Repositories))
+}
@@ -4,9 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/exitcode"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
 )
 
 func TestNewGenerator(t *testing.T) {
@@ -18,6 +32,49 @@ func TestNewGenerator(t *testing.T) {
 	if gen.depFactory == nil {
 		t.Error("Generator should have a dependency factory")
 	}
+	if gen.retryPolicy == nil || gen.retryPolicy.MaxAttempts != repository.DefaultRetryPolicy().MaxAttempts {
+		t.Error("Generator should default to the repository retry policy")
+	}
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	def := repository.DefaultRetryPolicy()
+
+	if got := RetryPolicyFromConfig(nil); got.MaxAttempts != def.MaxAttempts || got.MaxBackoff != def.MaxBackoff {
+		t.Errorf("nil config should yield defaults, got %+v", got)
+	}
+
+	got := RetryPolicyFromConfig(&config.RetryConfig{MaxAttempts: 1, InitialBackoff: time.Second})
+	if got.MaxAttempts != 1 {
+		t.Errorf("expected MaxAttempts 1, got %d", got.MaxAttempts)
+	}
+	if got.InitialBackoff != time.Second {
+		t.Errorf("expected InitialBackoff 1s, got %v", got.InitialBackoff)
+	}
+	if got.MaxBackoff != def.MaxBackoff || len(got.RetryableStatusCodes) != len(def.RetryableStatusCodes) {
+		t.Errorf("unset fields should keep defaults, got %+v", got)
+	}
+}
+
+func TestWithRetryLogging_ForwardsScopedEvents(t *testing.T) {
+	var got []repository.RetryEvent
+	ctx := repository.WithRetryObserver(context.Background(), func(ev repository.RetryEvent) {
+		got = append(got, ev)
+	})
+
+	repo := config.RepoWithProvider{
+		Provider: "github",
+		Config:   config.RepoConfig{Owner: "o", Repository: "r", Ref: "main"},
+	}
+	obs := repository.RetryObserverFromContext(withRetryLogging(ctx, repo))
+	obs(repository.RetryEvent{Attempt: 1, StatusCode: 502})
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 forwarded event, got %d", len(got))
+	}
+	if got[0].Scope != "github:o/r@main" {
+		t.Errorf("expected scope github:o/r@main, got %q", got[0].Scope)
+	}
 }
 
 func TestGenerate_EmptyRepos(t *testing.T) {
@@ -200,6 +257,9 @@ func TestGenerate_InvalidAnalyzer(t *testing.T) {
 	if report.Repositories[0].Error == nil {
 		t.Error("Expected error for invalid analyzer")
 	}
+	if code := exitcode.FromError(report.Repositories[0].Error); code != exitcode.ConfigError {
+		t.Errorf("Expected invalid analyzer to be a config error, got %v", code)
+	}
 }
 
 func TestGenerate_NoDependencyFilesFound(t *testing.T) {
@@ -234,6 +294,224 @@ func TestGenerate_NoDependencyFilesFound(t *testing.T) {
 	}
 }
 
+func TestGenerate_ManifestOnlyIsConstraintOnly(t *testing.T) {
+	gen := NewGenerator()
+	// The repository commits a pyproject.toml but no lock file
+	store := memory.NewStore()
+	store.AddRepository("o", "r", memory.Files{"pyproject.toml": "[project]\ndependencies = [\"Django>=4.2,<5\"]\n"})
+	gen.newClient = func(string, repository.Config) (repository.Client, error) { return memory.NewClient(store), nil }
+	rpt, err := gen.Generate(context.Background(), []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Analyzer: "poetry", Packages: []string{"django", "requests"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := rpt.Repositories[0]
+	if rr.Error != nil || !rr.ConstraintOnly {
+		t.Fatalf("expected a constraint-only result, got %+v", rr)
+	}
+	if v, ok := rr.Dependencies["django"]; !ok || v != "" || rr.Constraints["django"] != ">=4.2,<5" {
+		t.Errorf("django = %q (constraint %q)", v, rr.Constraints["django"])
+	}
+	if _, ok := rr.Dependencies["requests"]; ok {
+		t.Error("undeclared package should not be reported")
+	}
+}
+
+func TestGenerate_UseArchives(t *testing.T) {
+	gen := NewGenerator()
+	gen.SetUseArchives(true)
+	store := memory.NewStore()
+	store.AddRepository("o", "r", memory.Files{
+		"poetry.lock":                "[[package]]\nname = \"django\"\nversion = \"4.2.0\"\n",
+		"tests/fixtures/poetry.lock": "[[package]]\nname = \"django\"\nversion = \"1.0.0\"\n",
+	})
+	gen.newClient = func(provider string, _ repository.Config) (repository.Client, error) {
+		if provider == "fast" {
+			return &stubClient{}, nil // Cannot download archives
+		}
+		return memory.NewClient(store), nil
+	}
+	rpt, err := gen.Generate(context.Background(), []config.RepoWithProvider{
+		{Provider: "memory", Config: config.RepoConfig{Owner: "o", Repository: "r", Analyzer: "poetry", Packages: []string{"django"}, ExcludePaths: []string{"tests"}}},
+		{Provider: "fast", Config: config.RepoConfig{Owner: "o", Repository: "fast", Analyzer: "poetry", Packages: []string{"django"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil || rr.Dependencies["django"] != "4.2.0" || len(rr.FileVersions) > 0 {
+			t.Errorf("%s: django = %q, file versions %v, error %v", rr.Repository, rr.Dependencies["django"], rr.FileVersions, rr.Error)
+		}
+	}
+}
+
+func TestGenerate_ContentCache(t *testing.T) {
+	cache, err := repository.NewContentCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := memory.NewStore()
+	store.AddRepository("o", "r", memory.Files{"poetry.lock": "[[package]]\nname = \"django\"\nversion = \"4.2.0\"\n"})
+	repos := []config.RepoWithProvider{
+		{Provider: "memory", Config: config.RepoConfig{Owner: "o", Repository: "r", Analyzer: "poetry", Packages: []string{"django"}}},
+	}
+	for run := range 2 {
+		gen := NewGenerator()
+		gen.SetContentCache(cache)
+		gen.newClient = func(string, repository.Config) (repository.Client, error) {
+			return memory.NewClient(store), nil
+		}
+		rpt, err := gen.Generate(context.Background(), repos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rr := rpt.Repositories[0]; rr.Error != nil || rr.Dependencies["django"] != "4.2.0" {
+			t.Errorf("run %d: django = %q, error %v", run, rr.Dependencies["django"], rr.Error)
+		}
+	}
+	if stats, err := cache.Stats(); err != nil || stats.Misses == 0 || stats.Hits != stats.Misses {
+		t.Errorf("Stats() = %+v, %v; want the second run served entirely from the cache", stats, err)
+	}
+}
+
+// monorepoClient is a stubClient whose repository has a poetry.lock per
+// service, locking django at different versions
+type monorepoClient struct{ stubClient }
+
+func (c *monorepoClient) ListFilesUnder(context.Context, string, string, string, string) ([]repository.FileInfo, error) {
+	return []repository.FileInfo{
+		{Path: "services/web/poetry.lock", Type: "file"},
+		{Path: "services/api/poetry.lock", Type: "file"},
+	}, nil
+}
+
+func (c *monorepoClient) GetFileContent(_ context.Context, _, _, _, path string) (string, error) {
+	django := "4.2.0"
+	if strings.Contains(path, "web") {
+		django = "5.0.1"
+	}
+	return "[[package]]\nname = \"django\"\nversion = \"" + django + "\"\n\n[[package]]\nname = \"requests\"\nversion = \"2.32.3\"\n", nil
+}
+
+func TestGenerate_FileVersions(t *testing.T) {
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) { return &monorepoClient{}, nil }
+	rpt, err := gen.Generate(context.Background(), []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Analyzer: "poetry", Packages: []string{"django", "requests"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := rpt.Repositories[0]
+	if rr.Error != nil {
+		t.Fatalf("unexpected error: %v", rr.Error)
+	}
+	// The first file by path wins, whatever order the analyzer returned
+	if got := rr.Dependencies["django"]; got != "4.2.0" {
+		t.Errorf("django = %q, want the services/api version 4.2.0", got)
+	}
+	want := map[string]string{"services/api/poetry.lock": "4.2.0", "services/web/poetry.lock": "5.0.1"}
+	if got := rr.FileVersions["django"]; !maps.Equal(got, want) {
+		t.Errorf("FileVersions[django] = %v, want %v", got, want)
+	}
+	if got := rr.InconsistentPackages(); !slices.Equal(got, []string{"django"}) {
+		t.Errorf("InconsistentPackages() = %v, want [django]", got)
+	}
+	if rr.Inconsistent("requests") {
+		t.Error("requests is locked at the same version by both files")
+	}
+}
+
+type devClient struct{ stubClient }
+
+func (c *devClient) GetFileContent(context.Context, string, string, string, string) (string, error) {
+	return "[[package]]\nname = \"django\"\nversion = \"4.2.0\"\n\n[[package]]\nname = \"pytest\"\nversion = \"8.0.0\"\ncategory = \"dev\"\n", nil
+}
+
+func TestGenerate_DevDependencies(t *testing.T) {
+	repos := []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Analyzer: "poetry", Packages: []string{"django", "pytest"}}},
+	}
+	for _, exclude := range []bool{false, true} {
+		gen := NewGenerator()
+		gen.newClient = func(string, repository.Config) (repository.Client, error) { return &devClient{}, nil }
+		gen.SetExcludeDev(exclude)
+		rpt, err := gen.Generate(context.Background(), repos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := rpt.Repositories[0]
+		if rr.Dependencies["django"] != "4.2.0" || rr.DevOnly["django"] {
+			t.Errorf("exclude=%v: expected runtime django 4.2.0, got %q (dev only %v)", exclude, rr.Dependencies["django"], rr.DevOnly["django"])
+		}
+		if exclude && rr.Dependencies["pytest"] != "" {
+			t.Errorf("expected dev-only pytest left out, got %q", rr.Dependencies["pytest"])
+		}
+		if !exclude && (rr.Dependencies["pytest"] != "8.0.0" || !rr.DevOnly["pytest"]) {
+			t.Errorf("expected pytest 8.0.0 marked dev only, got %q (dev only %v)", rr.Dependencies["pytest"], rr.DevOnly["pytest"])
+		}
+	}
+	if (&Generator{excludeDev: true}).analysisFingerprint(repos[0]) == (&Generator{}).analysisFingerprint(repos[0]) {
+		t.Error("excluding dev dependencies should change the fingerprint")
+	}
+}
+
+func TestGenerate_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) { return &monorepoClient{}, nil }
+	if _, err := gen.Generate(context.Background(), []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	files := 0
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+		if s.Name() == "dependencies.analyzeFile" {
+			files++
+		}
+	}
+	root, repo := spans["report.Generate"], spans["report.analyzeRepository"]
+	if root == nil || repo == nil {
+		t.Fatalf("missing report spans, got %v", slices.Collect(maps.Keys(spans)))
+	}
+	if repo.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Error("repository span is not a child of the run span")
+	}
+	if files != 2 {
+		t.Errorf("got %d file spans, want one per lock file (2)", files)
+	}
+	attrs := map[string]string{}
+	for _, kv := range repo.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["devdashboard.repository"] != "github:o/r@main" || attrs["devdashboard.outcome"] != "ok" {
+		t.Errorf("repository span attributes = %v", attrs)
+	}
+}
+
+func TestInconsistent_ComparesVersions(t *testing.T) {
+	rr := RepositoryReport{
+		Analyzer: "poetry",
+		FileVersions: map[string]map[string]string{
+			"django": {"a/poetry.lock": "5.0", "b/poetry.lock": "5.0.0"},
+		},
+	}
+	if rr.Inconsistent("django") {
+		t.Error("equal PEP 440 versions should not be inconsistent")
+	}
+	if rr.Inconsistent("unknown") {
+		t.Error("a package without file versions should not be inconsistent")
+	}
+}
+
 func TestGenerate_ExplicitPathsWithContent(t *testing.T) {
 	gen := NewGenerator()
 	ctx := context.Background()
@@ -464,7 +742,7 @@ func TestHasErrors(t *testing.T) {
 			report: &Report{
 				Repositories: []RepositoryReport{
 					{Owner: "owner1", Repository: "repo1", Error: nil},
-					{Owner: "owner2", Repository: "repo2", Error: errors.New("test error")},
+					{Owner: "owner2", Repository: "repo2", Error: NewErrorDetail(errors.New("test error"))},
 				},
 			},
 			expected: true,
@@ -473,8 +751,8 @@ func TestHasErrors(t *testing.T) {
 			name: "all errors",
 			report: &Report{
 				Repositories: []RepositoryReport{
-					{Owner: "owner1", Repository: "repo1", Error: errors.New("error1")},
-					{Owner: "owner2", Repository: "repo2", Error: errors.New("error2")},
+					{Owner: "owner1", Repository: "repo1", Error: NewErrorDetail(errors.New("error1"))},
+					{Owner: "owner2", Repository: "repo2", Error: NewErrorDetail(errors.New("error2"))},
 				},
 			},
 			expected: true,
@@ -498,6 +776,32 @@ func TestHasErrors(t *testing.T) {
 	}
 }
 
+func TestReportErr_Classification(t *testing.T) {
+	cfgErr := exitcode.New(exitcode.ConfigError, errors.New("bad analyzer"))
+	provErr := exitcode.New(exitcode.ProviderError, errors.New("401"))
+	plain := errors.New("no dependency files found")
+
+	tests := []struct {
+		name  string
+		repos []RepositoryReport
+		want  exitcode.Code
+	}{
+		{"all ok", []RepositoryReport{{}, {}}, exitcode.OK},
+		{"partial", []RepositoryReport{{}, {Error: NewErrorDetail(provErr)}}, exitcode.Partial},
+		{"all provider", []RepositoryReport{{Error: NewErrorDetail(provErr)}, {Error: NewErrorDetail(plain)}}, exitcode.ProviderError},
+		{"config wins", []RepositoryReport{{Error: NewErrorDetail(provErr)}, {Error: NewErrorDetail(cfgErr)}}, exitcode.ConfigError},
+		{"unclassified", []RepositoryReport{{Error: NewErrorDetail(plain)}}, exitcode.Failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpt := &Report{Repositories: tt.repos}
+			if got := exitcode.FromError(rpt.Err()); got != tt.want {
+				t.Errorf("exit code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetErrors(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -519,7 +823,7 @@ func TestGetErrors(t *testing.T) {
 			report: &Report{
 				Repositories: []RepositoryReport{
 					{Owner: "owner1", Repository: "repo1", Error: nil},
-					{Owner: "owner2", Repository: "repo2", Error: errors.New("test error")},
+					{Owner: "owner2", Repository: "repo2", Error: NewErrorDetail(errors.New("test error"))},
 				},
 			},
 			expectedCount: 1,
@@ -528,9 +832,9 @@ func TestGetErrors(t *testing.T) {
 			name: "multiple errors",
 			report: &Report{
 				Repositories: []RepositoryReport{
-					{Owner: "owner1", Repository: "repo1", Error: errors.New("error1")},
-					{Owner: "owner2", Repository: "repo2", Error: errors.New("error2")},
-					{Owner: "owner3", Repository: "repo3", Error: errors.New("error3")},
+					{Owner: "owner1", Repository: "repo1", Error: NewErrorDetail(errors.New("error1"))},
+					{Owner: "owner2", Repository: "repo2", Error: NewErrorDetail(errors.New("error2"))},
+					{Owner: "owner3", Repository: "repo3", Error: NewErrorDetail(errors.New("error3"))},
 				},
 			},
 			expectedCount: 3,
@@ -564,7 +868,7 @@ func TestGetErrors_VerifyErrorContent(t *testing.T) {
 			{
 				Owner:      "test-owner",
 				Repository: "test-repo",
-				Error:      expectedError,
+				Error:      NewErrorDetail(expectedError),
 			},
 		},
 	}
@@ -577,7 +881,7 @@ func TestGetErrors_VerifyErrorContent(t *testing.T) {
 	key := "test-owner/test-repo"
 	if err, found := errors[key]; !found {
 		t.Errorf("Expected error for key %s", key)
-	} else if err != expectedError {
+	} else if detail, ok := err.(*ErrorDetail); !ok || detail.Unwrap() != expectedError {
 		t.Errorf("Expected error %v, got %v", expectedError, err)
 	}
 }
@@ -786,3 +1090,412 @@ func TestGenerate_MixedPackages(t *testing.T) {
 		}
 	}
 }
+
+// prListerClient is a minimal repository.Client that also implements
+// repository.UpdatePullRequestLister.
+type prListerClient struct {
+	prs []repository.UpdatePullRequest
+	err error
+}
+
+func (c *prListerClient) ListFiles(_ context.Context, _, _, _, _ string) ([]repository.FileInfo, error) {
+	return nil, nil
+}
+
+func (c *prListerClient) GetRepositoryInfo(_ context.Context, _, _ string) (*repository.Info, error) {
+	return &repository.Info{}, nil
+}
+
+func (c *prListerClient) ListFilesRecursive(_ context.Context, _, _, _ string) ([]repository.FileInfo, error) {
+	return nil, nil
+}
+
+func (c *prListerClient) ListFilesUnder(_ context.Context, _, _, _, _ string) ([]repository.FileInfo, error) {
+	return nil, nil
+}
+
+func (c *prListerClient) GetFileContent(_ context.Context, _, _, _, _ string) (string, error) {
+	return "", nil
+}
+
+func (c *prListerClient) ListUpdatePullRequests(_ context.Context, _, _ string) ([]repository.UpdatePullRequest, error) {
+	return c.prs, c.err
+}
+
+func TestCollectUpdatePullRequests(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(48 * time.Hour)
+	client := &prListerClient{prs: []repository.UpdatePullRequest{
+		{Number: 2, Package: "Django", CreatedAt: newer},
+		{Number: 1, Package: "django", CreatedAt: older},
+		{Number: 3, Package: "untracked", CreatedAt: older},
+	}}
+	repo := config.RepoWithProvider{
+		Provider: "github",
+		Config: config.RepoConfig{
+			Owner:      "o",
+			Repository: "r",
+			Packages:   []string{"django", "requests"},
+			UpdatePRs:  true,
+		},
+	}
+
+	rr := RepositoryReport{}
+	collectUpdatePullRequests(context.Background(), client, repo, &rr)
+
+	if len(rr.UpdatePullRequests) != 1 {
+		t.Fatalf("expected 1 annotated package, got %d", len(rr.UpdatePullRequests))
+	}
+	if pr := rr.UpdatePullRequests["django"]; pr.Number != 1 {
+		t.Errorf("expected oldest PR #1 for django, got #%d", pr.Number)
+	}
+}
+
+func TestCollectUpdatePullRequests_ErrorIgnored(t *testing.T) {
+	client := &prListerClient{err: errors.New("boom")}
+	repo := config.RepoWithProvider{Config: config.RepoConfig{Packages: []string{"django"}}}
+
+	rr := RepositoryReport{}
+	collectUpdatePullRequests(context.Background(), client, repo, &rr)
+	if rr.UpdatePullRequests != nil || rr.Error != nil {
+		t.Errorf("expected listing failure to be ignored, got %+v", rr)
+	}
+}
+
+// stubClient serves a single poetry.lock, or blocks until ctx is done when
+// slow is set. late delays the lock file's content without watching ctx.
+type stubClient struct {
+	slow bool
+	late time.Duration
+}
+
+func (c *stubClient) wait(ctx context.Context) error {
+	if !c.slow {
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *stubClient) GetRepositoryInfo(ctx context.Context, _, _ string) (*repository.Info, error) {
+	return &repository.Info{}, c.wait(ctx)
+}
+
+func (c *stubClient) ListFiles(ctx context.Context, _, _, _, _ string) ([]repository.FileInfo, error) {
+	return nil, c.wait(ctx)
+}
+
+func (c *stubClient) ListFilesRecursive(ctx context.Context, _, _, _ string) ([]repository.FileInfo, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return []repository.FileInfo{{Path: "poetry.lock", Type: "file"}}, nil
+}
+
+func (c *stubClient) ListFilesUnder(ctx context.Context, owner, repo, ref, _ string) ([]repository.FileInfo, error) {
+	return c.ListFilesRecursive(ctx, owner, repo, ref)
+}
+
+func (c *stubClient) GetFileContent(ctx context.Context, _, _, _, _ string) (string, error) {
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+	time.Sleep(c.late)
+	return "[[package]]\nname = \"django\"\nversion = \"4.2.0\"\n", nil
+}
+
+func stubRepos() []config.RepoWithProvider {
+	return []config.RepoWithProvider{
+		{Provider: "fast", Config: config.RepoConfig{Owner: "o", Repository: "fast", Analyzer: "poetry", Packages: []string{"django"}}},
+		{Provider: "slow", Config: config.RepoConfig{Owner: "o", Repository: "slow", Analyzer: "poetry", Packages: []string{"django"}}},
+	}
+}
+
+// stubGenerator returns a generator whose "slow" provider never responds.
+func stubGenerator() *Generator {
+	gen := NewGenerator()
+	gen.newClient = func(provider string, _ repository.Config) (repository.Client, error) {
+		return &stubClient{slow: provider == "slow"}, nil
+	}
+	return gen
+}
+
+func assertPartialResults(t *testing.T, rpt *Report) {
+	t.Helper()
+	fast, slow := rpt.Repositories[0], rpt.Repositories[1]
+	if fast.Error != nil || fast.Dependencies["django"] != "4.2.0" {
+		t.Errorf("expected completed repository to keep results, got %+v", fast)
+	}
+	if slow.ErrorCategory() != ErrorCategoryTimeout {
+		t.Errorf("expected slow repository to be marked timed out, got %v", slow.Error)
+	}
+}
+
+func TestGenerate_DeadlineReturnsPartialResults(t *testing.T) {
+	gen := stubGenerator()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	rpt, err := gen.Generate(ctx, stubRepos())
+	if err != nil {
+		t.Fatalf("expected partial report on deadline, got error: %v", err)
+	}
+	assertPartialResults(t, rpt)
+}
+
+func TestGenerate_RepositoryTimeout(t *testing.T) {
+	gen := stubGenerator()
+	gen.SetRepositoryTimeout(50 * time.Millisecond)
+
+	rpt, err := gen.Generate(context.Background(), stubRepos())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	assertPartialResults(t, rpt)
+}
+
+func TestGenerate_RepositoryTimeoutAfterCompletion(t *testing.T) {
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) {
+		return &stubClient{late: 60 * time.Millisecond}, nil
+	}
+	gen.SetRepositoryTimeout(20 * time.Millisecond)
+
+	rpt, err := gen.Generate(context.Background(), stubRepos()[:1])
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if rr := rpt.Repositories[0]; rr.Error != nil || rr.Dependencies["django"] != "4.2.0" {
+		t.Errorf("repository finishing past its deadline = %+v, want its results kept", rr)
+	}
+}
+
+func TestGenerate_Observer(t *testing.T) {
+	gen := stubGenerator()
+	var mu sync.Mutex
+	var events []RepositoryEvent
+	gen.SetObserver(func(ev RepositoryEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+	})
+	gen.SetRepositoryTimeout(50 * time.Millisecond)
+
+	if _, err := gen.Generate(context.Background(), stubRepos()); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want a start and a finish per repository: %+v", len(events), events)
+	}
+	finished := map[string]*RepositoryReport{}
+	for _, ev := range events {
+		if ev.Done {
+			finished[ev.Key] = ev.Report
+		}
+	}
+	if rr := finished["fast:o/fast@"]; rr == nil || rr.Error != nil {
+		t.Errorf("fast repository finish = %+v, want success", rr)
+	}
+	if rr := finished["slow:o/slow@"]; rr == nil || rr.ErrorCategory() != ErrorCategoryTimeout {
+		t.Errorf("slow repository finish = %+v, want its timeout", rr)
+	}
+}
+
+func TestGenerate_Concurrency(t *testing.T) {
+	gen := stubGenerator()
+	gen.SetConcurrency(1)
+	var mu sync.Mutex
+	var active, maxActive int
+	gen.SetObserver(func(ev RepositoryEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ev.Done {
+			active--
+			return
+		}
+		active++
+		maxActive = max(maxActive, active)
+	})
+	repos := []config.RepoWithProvider{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		repos = append(repos, config.RepoWithProvider{Provider: "fast",
+			Config: config.RepoConfig{Owner: "o", Repository: name, Analyzer: "poetry", Packages: []string{"django"}}})
+	}
+
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if maxActive != 1 {
+		t.Errorf("up to %d repositories analyzed at once, want 1", maxActive)
+	}
+	for _, rr := range rpt.Repositories {
+		if rr.Error != nil {
+			t.Errorf("%s failed: %v", rr.Key(), rr.Error)
+		}
+	}
+}
+
+func TestGenerate_PerRepositoryEndpoint(t *testing.T) {
+	gen := NewGenerator()
+	var mu sync.Mutex
+	got := map[string]repository.Config{}
+	gen.newClient = func(_ string, cfg repository.Config) (repository.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		got[cfg.BaseURL] = cfg
+		return &stubClient{}, nil
+	}
+	repos := []config.RepoWithProvider{
+		{Provider: "gitlab", Config: config.RepoConfig{Owner: "o", Repository: "internal", Analyzer: "poetry",
+			BaseURL: "https://gitlab.example.com", Token: "internal-token"}},
+		{Provider: "gitlab", Config: config.RepoConfig{Owner: "o", Repository: "public", Analyzer: "poetry", Token: "public-token"}},
+	}
+
+	if _, err := gen.Generate(context.Background(), repos); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if cfg := got["https://gitlab.example.com"]; cfg.Token != "internal-token" {
+		t.Errorf("self-hosted client config = %+v, want the repository's token", cfg)
+	}
+	if cfg, ok := got[""]; !ok || cfg.Token != "public-token" {
+		t.Errorf("gitlab.com client config = %+v, want the repository's token", cfg)
+	}
+}
+
+func TestCanonicalizePackages(t *testing.T) {
+	repos := []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Repository: "a", Analyzer: "poetry", Packages: []string{"PyYAML", "requests"}}},
+		{Provider: "github", Config: config.RepoConfig{Repository: "b", Analyzer: "uvlock", Packages: []string{"pyyaml", "Requests"}}},
+	}
+
+	out, packages, ecosystems := canonicalizePackages(repos, nil)
+
+	if fmt.Sprint(packages) != "[PyYAML requests]" {
+		t.Errorf("expected first spellings to be kept, got %v", packages)
+	}
+	if ecosystems["PyYAML"] != dependencies.EcosystemPython || len(ecosystems) != 2 {
+		t.Errorf("expected both packages in the python ecosystem, got %v", ecosystems)
+	}
+	if fmt.Sprint(out[1].Config.Packages) != "[PyYAML requests]" {
+		t.Errorf("expected repository packages rewritten to display names, got %v", out[1].Config.Packages)
+	}
+	if repos[1].Config.Packages[0] != "pyyaml" {
+		t.Error("caller's repository configuration should not be modified")
+	}
+}
+
+func TestGenerate_MatchesNormalizedPackageNames(t *testing.T) {
+	gen := stubGenerator()
+	repos := []config.RepoWithProvider{
+		{Provider: "fast", Config: config.RepoConfig{Owner: "o", Repository: "fast", Analyzer: "poetry", Packages: []string{"Django"}}},
+	}
+
+	rpt, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rpt.Repositories[0].Dependencies["Django"]; got != "4.2.0" {
+		t.Errorf("expected lock file 'django' to match tracked 'Django', got %q", got)
+	}
+	if got := rpt.Repositories[0].Registries["Django"]; got != dependencies.PyPIRegistry {
+		t.Errorf("expected poetry.lock PyPI package attributed to %s, got %q", dependencies.PyPIRegistry, got)
+	}
+	if got := rpt.Repositories[0].Provenance["Django"]; got != (Provenance{File: "poetry.lock", Type: "runtime", Source: "pypi"}) {
+		t.Errorf("expected provenance of the poetry.lock entry, got %+v", got)
+	}
+}
+
+func TestResolvePackage(t *testing.T) {
+	rpt := &Report{
+		Packages:     []string{"PyYAML", "requests"},
+		Repositories: []RepositoryReport{{Analyzer: "poetry"}},
+	}
+	tests := map[string]string{
+		"PyYAML":   "PyYAML",
+		"pyyaml":   "PyYAML",
+		"Requests": "requests",
+		"django":   "django",
+	}
+	for in, want := range tests {
+		if got := rpt.ResolvePackage(in); got != want {
+			t.Errorf("ResolvePackage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGetPackageVersions_SortedAndDrift(t *testing.T) {
+	rpt := &Report{
+		Packages: []string{"django", "requests"},
+		Repositories: []RepositoryReport{
+			{Owner: "o", Repository: "a", Analyzer: "poetry", Dependencies: map[string]string{"django": "4.10.0", "requests": "2.31"}},
+			{Owner: "o", Repository: "b", Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2.0", "requests": "2.31.0"}},
+			{Owner: "o", Repository: "c", Analyzer: "poetry", Dependencies: map[string]string{"django": "4.9rc1"}},
+		},
+	}
+
+	versions := rpt.GetPackageVersions()
+	django, requests := versions[0], versions[1]
+
+	if fmt.Sprint(django.Sorted) != "[4.2.0 4.9rc1 4.10.0]" {
+		t.Errorf("expected PEP 440 ordering, got %v", django.Sorted)
+	}
+	if django.Min != "4.2.0" || django.Max != "4.10.0" || !django.HasDrift() {
+		t.Errorf("unexpected django bounds: min=%q max=%q drift=%v", django.Min, django.Max, django.HasDrift())
+	}
+	if requests.HasDrift() {
+		t.Errorf("equivalent versions should not be drift: %v", requests.Sorted)
+	}
+
+	if !rpt.IsOutdated("django", "4.2.0") || rpt.IsOutdated("django", "4.10.0") {
+		t.Error("IsOutdated should compare against the newest version in use")
+	}
+	if rpt.IsOutdated("requests", "2.31") || rpt.IsOutdated("django", "") {
+		t.Error("equivalent or missing versions should not be outdated")
+	}
+}
+
+func TestRegenerate_MergesOnlySelected(t *testing.T) {
+	gen := NewGenerator()
+	calls := 0
+	var mu sync.Mutex
+	gen.newClient = func(string, repository.Config) (repository.Client, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return &stubClient{}, nil
+	}
+	repos := []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "a", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}}},
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "b", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}}},
+	}
+	base := &Report{
+		Repositories: []RepositoryReport{
+			{Provider: "github", Owner: "o", Repository: "a", Ref: "main", Dependencies: map[string]string{"django": "3.0.0"}},
+			{Provider: "github", Owner: "o", Repository: "b", Ref: "main", Dependencies: map[string]string{"django": "3.0.0"}},
+		},
+		Packages: []string{"django"},
+	}
+
+	rpt, err := gen.Regenerate(context.Background(), base, repos, func(r config.RepoWithProvider) bool {
+		return r.Config.Repository == "b"
+	})
+	if err != nil {
+		t.Fatalf("Regenerate failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected only the selected repository to be analyzed, got %d clients", calls)
+	}
+	if len(rpt.Repositories) != 2 || rpt.Repositories[0].Repository != "a" || rpt.Repositories[1].Repository != "b" {
+		t.Fatalf("unexpected repositories: %+v", rpt.Repositories)
+	}
+	if got := rpt.Repositories[0].Dependencies["django"]; got != "3.0.0" {
+		t.Errorf("unselected repository should keep base results, got %q", got)
+	}
+	if got := rpt.Repositories[1].Dependencies["django"]; got != "4.2.0" {
+		t.Errorf("selected repository should be re-analyzed, got %q", got)
+	}
+	if base.Repositories[1].Dependencies["django"] != "3.0.0" {
+		t.Error("base report must not be modified")
+	}
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// JSONSchemaID identifies the JSON Schema of JSONDocument
+const JSONSchemaID = "https://github.com/greg-hellings/devdashboard/schemas/report-v2.json"
+
+// schemaEnums lists the values of string types with a closed set
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeFor[ErrorCategory](): {
+		string(ErrorCategoryNone), string(ErrorCategoryAuth), string(ErrorCategoryNotFound), string(ErrorCategoryPrivate),
+		string(ErrorCategoryParse), string(ErrorCategoryRateLimit), string(ErrorCategoryTimeout),
+		string(ErrorCategoryBudget), string(ErrorCategoryConfig), string(ErrorCategoryUnknown),
+	},
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of JSONDocument, derived
+// from its Go types so it cannot drift from what WriteJSON writes
+func JSONSchema() ([]byte, error) {
+	g := &schemaGen{defs: make(map[string]any), names: make(map[reflect.Type]string)}
+	root := g.object(reflect.TypeFor[JSONDocument]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = JSONSchemaID
+	root["title"] = "DevDashboard dependency report"
+	root["properties"].(map[string]any)["schemaVersion"] = map[string]any{"type": "integer", "minimum": 1, "maximum": JSONSchemaVersion}
+	root["$defs"] = g.defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaGen collects the definitions of the struct types it meets
+type schemaGen struct {
+	defs  map[string]any
+	names map[reflect.Type]string
+}
+
+// schema returns the schema of values of type t
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	if values, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return map[string]any{"anyOf": []any{g.schema(t.Elem()), map[string]any{"type": "null"}}}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": []string{"array", "null"}, "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return map[string]any{"$ref": "#/$defs/" + g.define(t)}
+	}
+	return map[string]any{} // Interfaces hold any value
+}
+
+// define adds the definition of struct type t under its name (unless taken
+// by another type) and returns the name used
+func (g *schemaGen) define(t reflect.Type) string {
+	if defined, ok := g.names[t]; ok {
+		return defined
+	}
+	name := t.Name()
+	if _, taken := g.defs[name]; taken {
+		name = t.String()
+	}
+	g.names[t] = name // Before recursing, for recursive types
+	g.defs[name] = g.object(t)
+	return name
+}
+
+// object returns the schema of struct type t, flattening embedded structs
+// like encoding/json
+func (g *schemaGen) object(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	g.fields(t, props, &required, make(map[string]bool))
+	obj := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+// fields adds the JSON fields of t not shadowed by shallower ones in seen
+func (g *schemaGen) fields(t reflect.Type, props map[string]any, required *[]string, seen map[string]bool) {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, f.Type)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		props[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+	for _, e := range embedded {
+		g.fields(e, props, required, seen)
+	}
+}
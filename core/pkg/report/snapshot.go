@@ -0,0 +1,333 @@
+package report
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// SnapshotVersion is bumped when the snapshot layout changes; snapshots of
+// another version are ignored.
+const SnapshotVersion = 1
+
+// Snapshot records the commit each repository was analyzed at, with the
+// results found there, so the next run can skip repositories whose ref still
+// resolves to the same commit (see Generator.SetPrevious).
+type Snapshot struct {
+	Version      int                      `json:"version"`
+	GeneratedAt  time.Time                `json:"generatedAt"`
+	Repositories map[string]SnapshotEntry `json:"repositories"` // By RepositoryReport.Key
+
+	// Failed maps the key of each repository that failed to analyze to its
+	// error (a bare message in snapshots of earlier releases). Failures are
+	// never reused; they let the next run tell new failures from known ones
+	// (see Report).
+	Failed map[string]*ErrorDetail `json:"failed,omitempty"`
+}
+
+// SnapshotEntry holds one repository's analysis results before report hooks
+// changed them
+type SnapshotEntry struct {
+	CommitSHA    string            `json:"commitSha"`
+	Analyzer     string            `json:"analyzer,omitempty"`
+	Fingerprint  string            `json:"fingerprint"` // Analysis settings (see analysisFingerprint)
+	Dependencies map[string]string `json:"dependencies"`
+	Constraints  map[string]string `json:"constraints,omitempty"`
+	// FileVersions mirrors RepositoryReport.FileVersions
+	FileVersions map[string]map[string]string `json:"fileVersions,omitempty"`
+	// ConstraintOnly mirrors RepositoryReport.ConstraintOnly
+	ConstraintOnly bool `json:"constraintOnly,omitempty"`
+	// LockedNames mirrors RepositoryReport.LockedNames
+	LockedNames map[string]string `json:"lockedNames,omitempty"`
+	// DevOnly mirrors RepositoryReport.DevOnly
+	DevOnly map[string]bool `json:"devOnly,omitempty"`
+	// Provenance mirrors RepositoryReport.Provenance
+	Provenance map[string]Provenance `json:"provenance,omitempty"`
+	// Registries mirrors RepositoryReport.Registries
+	Registries map[string]string `json:"registries,omitempty"`
+	// Hashes mirrors RepositoryReport.Hashes
+	Hashes map[string][]string `json:"hashes,omitempty"`
+
+	// Graph is recorded when the run included dependency graphs
+	Graph *dependencies.Graph `json:"graph,omitempty"`
+
+	// Violations of the policies the run checked
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Snapshot returns the commit-keyed results of the successfully analyzed
+// repositories, or nil when the report was not produced by a Generator.
+func (r *Report) Snapshot() *Snapshot {
+	return r.snapshot
+}
+
+// newSnapshot captures successful results that carry a commit SHA, and the
+// failures. Maps are copied because hooks may later suppress packages in
+// place.
+func newSnapshot(reports []RepositoryReport) *Snapshot {
+	s := &Snapshot{Version: SnapshotVersion, GeneratedAt: time.Now().UTC(), Repositories: map[string]SnapshotEntry{}}
+	for _, rr := range reports {
+		if rr.Error != nil {
+			if s.Failed == nil {
+				s.Failed = make(map[string]*ErrorDetail)
+			}
+			s.Failed[rr.Key()] = rr.Error
+			continue
+		}
+		if rr.CommitSHA == "" {
+			continue
+		}
+		s.Repositories[rr.Key()] = SnapshotEntry{
+			CommitSHA:      rr.CommitSHA,
+			Analyzer:       rr.Analyzer,
+			Fingerprint:    rr.fingerprint,
+			Dependencies:   maps.Clone(rr.Dependencies),
+			Constraints:    maps.Clone(rr.Constraints),
+			FileVersions:   maps.Clone(rr.FileVersions),
+			ConstraintOnly: rr.ConstraintOnly,
+			LockedNames:    maps.Clone(rr.LockedNames),
+			DevOnly:        maps.Clone(rr.DevOnly),
+			Provenance:     maps.Clone(rr.Provenance),
+			Registries:     maps.Clone(rr.Registries),
+			Hashes:         maps.Clone(rr.Hashes),
+			Graph:          rr.Graph,
+			Violations:     slices.Clone(rr.Violations),
+		}
+	}
+	return s
+}
+
+// Report rebuilds a report from the snapshot, for comparing a new run with
+// the previous one (see NewProblems and NewPackages). Repositories carry
+// their key fields, analyzer and tracked package versions, and failed ones
+// the recorded error; annotations, pull requests and the
+// effects of hooks are not restored.
+func (s *Snapshot) Report() *Report {
+	if s == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(s.Repositories)+len(s.Failed))
+	for key := range s.Repositories {
+		keys = append(keys, key)
+	}
+	for key := range s.Failed {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	r := &Report{}
+	packages := make(map[string]bool)
+	for _, key := range slices.Compact(keys) {
+		rr := RepositoryReport{}
+		rr.Provider, rr.Owner, rr.Repository, rr.Ref = SplitKey(key)
+		if detail, failed := s.Failed[key]; failed {
+			rr.Error = detail
+		} else {
+			entry := s.Repositories[key]
+			rr.Analyzer = entry.Analyzer
+			rr.Ecosystem = dependencies.EcosystemForAnalyzer(entry.Analyzer)
+			rr.CommitSHA = entry.CommitSHA
+			rr.Dependencies = maps.Clone(entry.Dependencies)
+			rr.Constraints = maps.Clone(entry.Constraints)
+			rr.FileVersions = maps.Clone(entry.FileVersions)
+			rr.ConstraintOnly = entry.ConstraintOnly
+			rr.LockedNames = maps.Clone(entry.LockedNames)
+			rr.DevOnly = maps.Clone(entry.DevOnly)
+			rr.Provenance = maps.Clone(entry.Provenance)
+			rr.Registries = maps.Clone(entry.Registries)
+			rr.Hashes = maps.Clone(entry.Hashes)
+			rr.Violations = slices.Clone(entry.Violations)
+			for pkg := range entry.Dependencies {
+				packages[pkg] = true
+			}
+		}
+		r.Repositories = append(r.Repositories, rr)
+	}
+	r.Packages = slices.Sorted(maps.Keys(packages))
+	return r
+}
+
+// SplitKey parses a RepositoryReport.Key (provider:owner/repo@ref). Owners
+// may contain slashes (GitLab subgroups), so the repository is the last
+// path segment.
+func SplitKey(key string) (provider, owner, repo, ref string) {
+	provider, rest, _ := strings.Cut(key, ":")
+	path, ref, _ := strings.Cut(rest, "@")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return provider, path[:i], path[i+1:], ref
+	}
+	return provider, "", path, ref
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot. A missing file, or
+// one from another SnapshotVersion, returns nil without error.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	// #nosec G304 path chosen by the user
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse snapshot %s: %w", path, err)
+	}
+	if s.Version != SnapshotVersion {
+		slog.Info("Ignoring snapshot from another version", "path", path, "version", s.Version)
+		return nil, nil
+	}
+	// Decoding allocates every name and version per repository; share them
+	for key, entry := range s.Repositories {
+		entry.Dependencies = internMap(entry.Dependencies)
+		for pkg, files := range entry.FileVersions {
+			entry.FileVersions[pkg] = internMap(files)
+		}
+		s.Repositories[key] = entry
+	}
+	return &s, nil
+}
+
+// internMap returns m with interned keys and values
+func internMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	interned := make(map[string]string, len(m))
+	for k, v := range m {
+		interned[intern(k)] = intern(v)
+	}
+	return interned
+}
+
+// SaveSnapshot writes s to path, creating parent directories
+func SaveSnapshot(path string, s *Snapshot) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// SetPrevious enables incremental analysis: repositories whose ref resolves
+// to the commit recorded in prev, with unchanged analysis settings, reuse the
+// recorded results instead of fetching and parsing files again. Pass nil to
+// analyze everything.
+func (g *Generator) SetPrevious(prev *Snapshot) {
+	g.previous = prev
+}
+
+// analysisFingerprint summarizes the settings that influence a repository's
+// results, so changing them (e.g. tracking another package) invalidates the
+// snapshot entry even when the commit did not move.
+func (g *Generator) analysisFingerprint(repo config.RepoWithProvider) string {
+	eco := dependencies.EcosystemForAnalyzer(repo.Config.Analyzer)
+	pkgs := make([]string, 0, len(repo.Config.Packages))
+	for _, pkg := range repo.Config.Packages {
+		if former := g.aliases.formerNames(eco, pkg); len(former) > 0 {
+			pkg += "=" + strings.Join(former, "|")
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	slices.Sort(pkgs)
+	var applied []Policy
+	for _, p := range g.policies {
+		if p.appliesTo(repo) {
+			applied = append(applied, p)
+		}
+	}
+	fields := []string{
+		repo.Config.Analyzer,
+		strings.Join(repo.Config.Paths, ","),
+		strings.Join(pkgs, ","),
+		fmt.Sprint(repo.Config.Constraints),
+		fmt.Sprint(g.graphs),
+		fmt.Sprintf("%+v", applied),
+	}
+	// Appended only when set, so existing snapshots stay valid
+	if g.hashes {
+		fields = append(fields, "hashes")
+	}
+	if g.excludeDev {
+		fields = append(fields, "excludeDev")
+	}
+	if len(repo.Config.ExcludePaths) > 0 {
+		fields = append(fields, "exclude="+strings.Join(repo.Config.ExcludePaths, ","))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// resolveCommit records the commit the repository's ref points to and reports
+// whether the previous snapshot already holds results for it (copied into
+// report). Providers without commit lookup, or lookup failures, fall back to
+// a full analysis.
+func (g *Generator) resolveCommit(ctx context.Context, client repository.Client, repo config.RepoWithProvider, report *RepositoryReport) bool {
+	report.fingerprint = g.analysisFingerprint(repo)
+	resolver, ok := client.(repository.CommitResolver)
+	if !ok {
+		return false
+	}
+	sha, err := resolver.ResolveCommit(ctx, repo.Config.Owner, repo.Config.Repository, repo.Config.Ref)
+	if err != nil {
+		slog.Debug("Failed to resolve commit; analyzing in full",
+			"owner", repo.Config.Owner,
+			"repo", repo.Config.Repository,
+			"ref", repo.Config.Ref,
+			"error", err)
+		return false
+	}
+	report.CommitSHA = sha
+
+	if g.previous == nil {
+		return false
+	}
+	prev, ok := g.previous.Repositories[report.Key()]
+	if !ok || prev.CommitSHA != sha || prev.Fingerprint != report.fingerprint {
+		return false
+	}
+	report.Dependencies = maps.Clone(prev.Dependencies)
+	if report.Dependencies == nil {
+		report.Dependencies = make(map[string]string)
+	}
+	report.Constraints = maps.Clone(prev.Constraints)
+	report.FileVersions = maps.Clone(prev.FileVersions)
+	report.ConstraintOnly = prev.ConstraintOnly
+	report.LockedNames = maps.Clone(prev.LockedNames)
+	report.DevOnly = maps.Clone(prev.DevOnly)
+	report.Provenance = maps.Clone(prev.Provenance)
+	report.Registries = maps.Clone(prev.Registries)
+	report.Hashes = maps.Clone(prev.Hashes)
+	report.Graph = prev.Graph
+	report.Violations = slices.Clone(prev.Violations)
+	report.Cached = true
+	slog.Debug("Commit unchanged; reusing previous results",
+		"owner", repo.Config.Owner,
+		"repo", repo.Config.Repository,
+		"sha", sha)
+	return true
+}
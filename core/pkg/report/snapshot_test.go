@@ -0,0 +1,186 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// shaClient is a stubClient that resolves every ref to sha and counts file reads
+type shaClient struct {
+	stubClient
+	sha   string
+	reads *atomic.Int32
+}
+
+func (c *shaClient) ResolveCommit(context.Context, string, string, string) (string, error) {
+	return c.sha, nil
+}
+
+func (c *shaClient) GetFileContent(ctx context.Context, owner, repo, ref, path string) (string, error) {
+	c.reads.Add(1)
+	return c.stubClient.GetFileContent(ctx, owner, repo, ref, path)
+}
+
+func TestGenerate_IncrementalBySHA(t *testing.T) {
+	var reads atomic.Int32
+	sha := "aaa"
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) {
+		return &shaClient{sha: sha, reads: &reads}, nil
+	}
+	repos := []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}}},
+	}
+
+	first, err := gen.Generate(context.Background(), repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Repositories[0].CommitSHA != "aaa" || first.Repositories[0].Cached {
+		t.Fatalf("first run: %+v", first.Repositories[0])
+	}
+	path := filepath.Join(t.TempDir(), "snap.json")
+	if err := SaveSnapshot(path, first.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := LoadSnapshot(path)
+	if err != nil || prev == nil {
+		t.Fatalf("LoadSnapshot = %v, %v", prev, err)
+	}
+
+	tests := []struct {
+		name       string
+		sha        string
+		packages   []string
+		wantCached bool
+	}{
+		{"same commit", "aaa", []string{"django"}, true},
+		{"new commit", "bbb", []string{"django"}, false},
+		{"settings changed", "aaa", []string{"django", "requests"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sha = tt.sha
+			reads.Store(0)
+			gen.SetPrevious(prev)
+			r := repos[0]
+			r.Config.Packages = tt.packages
+			rpt, err := gen.Generate(context.Background(), []config.RepoWithProvider{r})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := rpt.Repositories[0]
+			if rr.Cached != tt.wantCached || (reads.Load() == 0) != tt.wantCached {
+				t.Errorf("cached = %v with %d file reads, want cached %v", rr.Cached, reads.Load(), tt.wantCached)
+			}
+			if rr.Dependencies["django"] != "4.2.0" || rr.CommitSHA != tt.sha {
+				t.Errorf("unexpected result %+v", rr)
+			}
+		})
+	}
+
+	if s, err := LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); s != nil || err != nil {
+		t.Errorf("missing snapshot = %v, %v", s, err)
+	}
+}
+
+// refClient is a shaClient recording the ref of every file read
+type refClient struct {
+	shaClient
+	mu   sync.Mutex
+	refs []string
+}
+
+func (c *refClient) GetFileContent(ctx context.Context, owner, repo, ref, path string) (string, error) {
+	c.mu.Lock()
+	c.refs = append(c.refs, ref)
+	c.mu.Unlock()
+	return c.shaClient.GetFileContent(ctx, owner, repo, ref, path)
+}
+
+func TestGenerate_ReadsResolvedCommit(t *testing.T) {
+	var reads atomic.Int32
+	client := &refClient{shaClient: shaClient{sha: "0123abcd", reads: &reads}}
+	gen := NewGenerator()
+	gen.newClient = func(string, repository.Config) (repository.Client, error) { return client, nil }
+	rpt, err := gen.Generate(context.Background(), []config.RepoWithProvider{
+		{Provider: "github", Config: config.RepoConfig{Owner: "o", Repository: "r", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr := rpt.Repositories[0]; rr.Ref != "main" || rr.CommitSHA != "0123abcd" || rr.Dependencies["django"] != "4.2.0" {
+		t.Errorf("unexpected result %+v", rr)
+	}
+	if len(client.refs) == 0 || slices.ContainsFunc(client.refs, func(ref string) bool { return ref != "0123abcd" }) {
+		t.Errorf("files read at %v, want the resolved commit", client.refs)
+	}
+}
+
+func TestSnapshotReport(t *testing.T) {
+	rpt := &Report{Repositories: []RepositoryReport{
+		{Provider: "gitlab", Owner: "grp/sub", Repository: "svc", Ref: "main", Analyzer: "poetry", CommitSHA: "abc",
+			Dependencies: map[string]string{"django": "4.2", "requests": ""}},
+		{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Error: NewErrorDetail(errors.New("boom"))},
+	}}
+	snap := newSnapshot(rpt.Repositories)
+	if snap.Failed["github:org/api@main"].Error() != "boom" {
+		t.Fatalf("Failed = %v", snap.Failed)
+	}
+
+	back := snap.Report()
+	if len(back.Repositories) != 2 || fmt.Sprint(back.Packages) != "[django requests]" {
+		t.Fatalf("Report() = %+v", back)
+	}
+	failed, ok := back.Repositories[0], back.Repositories[1]
+	if failed.Key() != "github:org/api@main" || failed.Error == nil || failed.Error.Error() != "boom" {
+		t.Errorf("failed repository = %+v", failed)
+	}
+	if ok.Key() != "gitlab:grp/sub/svc@main" || ok.Owner != "grp/sub" || ok.Analyzer != "poetry" || ok.Dependencies["django"] != "4.2" {
+		t.Errorf("analyzed repository = %+v", ok)
+	}
+	if !NewProblems(back, rpt).Empty() {
+		t.Error("a report compared with its own snapshot should have no new problems")
+	}
+	if (*Snapshot)(nil).Report() != nil {
+		t.Error("nil snapshot should give a nil report")
+	}
+}
+
+// TestSnapshotFailedPersistence verifies failures keep their category through
+// SaveSnapshot and LoadSnapshot, and that bare messages of earlier releases
+// still load.
+func TestSnapshotFailedPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	snap := newSnapshot([]RepositoryReport{
+		{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Error: NewErrorDetail(&NotFoundError{Err: errors.New("gone")})},
+	})
+	if err := SaveSnapshot(path, snap); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed := loaded.Report().Repositories[0]; failed.Error.Error() != "gone" || failed.ErrorCategory() != ErrorCategoryNotFound {
+		t.Errorf("loaded failure = %+v", failed.Error)
+	}
+
+	legacy := `{"version": 1, "repositories": {}, "failed": {"github:org/api@main": "boom"}}`
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err = LoadSnapshot(path); err != nil || loaded.Failed["github:org/api@main"].Error() != "boom" {
+		t.Errorf("legacy snapshot = %+v (err %v)", loaded, err)
+	}
+}
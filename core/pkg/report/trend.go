@@ -0,0 +1,179 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// DefaultSnapshotHistory is the number of snapshots ArchiveSnapshot keeps
+// when not told otherwise
+const DefaultSnapshotHistory = 500
+
+// snapshotArchiveLayout names archived snapshots; it sorts chronologically
+const snapshotArchiveLayout = "20060102T150405.000000000Z"
+
+// HistoryDir returns the directory archiving the snapshots saved to
+// snapshotPath (see ArchiveSnapshot): snapshotPath without its extension,
+// with ".history" appended
+func HistoryDir(snapshotPath string) string {
+	return strings.TrimSuffix(snapshotPath, filepath.Ext(snapshotPath)) + ".history"
+}
+
+// ArchiveSnapshot adds s to the snapshot history in dir, named by its
+// GeneratedAt, and removes the oldest snapshots beyond keep (keep <= 0 uses
+// DefaultSnapshotHistory)
+func ArchiveSnapshot(dir string, s *Snapshot, keep int) error {
+	if s == nil {
+		return nil
+	}
+	if keep <= 0 {
+		keep = DefaultSnapshotHistory
+	}
+	name := s.GeneratedAt.UTC().Format(snapshotArchiveLayout) + ".json"
+	if err := SaveSnapshot(filepath.Join(dir, name), s); err != nil {
+		return err
+	}
+	files, err := historyFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files[:max(0, len(files)-keep)] {
+		if err := os.Remove(f); err != nil {
+			return fmt.Errorf("prune snapshot history: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadSnapshotHistory reads the snapshots archived in dir, oldest first.
+// A missing directory is an empty history; unreadable snapshots are skipped
+// with a warning.
+func LoadSnapshotHistory(dir string) ([]*Snapshot, error) {
+	files, err := historyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*Snapshot, 0, len(files))
+	for _, f := range files {
+		s, err := LoadSnapshot(f)
+		if err != nil {
+			slog.Warn("Skipping unreadable snapshot", "path", f, "error", err)
+			continue
+		}
+		if s != nil {
+			history = append(history, s)
+		}
+	}
+	slices.SortStableFunc(history, func(a, b *Snapshot) int {
+		return a.GeneratedAt.Compare(b.GeneratedAt)
+	})
+	return history, nil
+}
+
+// historyFiles lists the snapshot files of dir sorted by name (oldest first)
+func historyFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot history: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// TrendPoint is the version distribution of a package in one snapshot
+type TrendPoint struct {
+	Time time.Time `json:"time"`
+	// Repositories counts the snapshot's repositories locking the package
+	Repositories int `json:"repositories"`
+	// Versions maps each locked version to the repositories locking it
+	Versions map[string]int `json:"versions"`
+}
+
+// PackageTrend is how the versions of a package evolved across a snapshot
+// history
+type PackageTrend struct {
+	Package   string                 `json:"package"`
+	Ecosystem dependencies.Ecosystem `json:"ecosystem,omitempty"`
+	// Versions lists every version seen, lowest first
+	Versions []string     `json:"versions"`
+	Points   []TrendPoint `json:"points"` // One per snapshot, oldest first
+}
+
+// Trend computes the version distribution of pkg in each snapshot of
+// history, matching ecosystem-normalized names. Repositories reporting the
+// package without a version (e.g. constraint-only ones) are not counted.
+func Trend(history []*Snapshot, pkg string) PackageTrend {
+	t := PackageTrend{Package: pkg, Points: make([]TrendPoint, 0, len(history))}
+	seen := make(map[string]bool)
+	for _, s := range history {
+		p := TrendPoint{Time: s.GeneratedAt, Versions: make(map[string]int)}
+		for _, key := range slices.Sorted(maps.Keys(s.Repositories)) {
+			entry := s.Repositories[key]
+			eco := dependencies.EcosystemForAnalyzer(entry.Analyzer)
+			for name, version := range entry.Dependencies {
+				if version == "" || dependencies.NormalizeName(eco, name) != dependencies.NormalizeName(eco, pkg) {
+					continue
+				}
+				if t.Ecosystem == "" {
+					t.Ecosystem = eco
+				}
+				p.Repositories++
+				p.Versions[version]++
+				seen[version] = true
+				break
+			}
+		}
+		t.Points = append(t.Points, p)
+	}
+	t.Versions = slices.Collect(maps.Keys(seen))
+	versioning.Sort(t.Ecosystem, t.Versions)
+	return t
+}
+
+// Counts returns, per point, the repositories locking version
+func (t PackageTrend) Counts(version string) []int {
+	counts := make([]int, len(t.Points))
+	for i, p := range t.Points {
+		counts[i] = p.Versions[version]
+	}
+	return counts
+}
+
+// Adoption returns, per point, the share (0 to 1) of the repositories
+// locking the package at a version in rng; points without any repository
+// are 0
+func (t PackageTrend) Adoption(rng *versioning.Range) []float64 {
+	shares := make([]float64, len(t.Points))
+	for i, p := range t.Points {
+		if p.Repositories == 0 {
+			continue
+		}
+		matching := 0
+		for version, n := range p.Versions {
+			if rng.Contains(version) {
+				matching += n
+			}
+		}
+		shares[i] = float64(matching) / float64(p.Repositories)
+	}
+	return shares
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// trendSnapshot is a snapshot taken at day whose repositories lock django at
+// the given versions
+func trendSnapshot(day int, versions ...string) *Snapshot {
+	s := &Snapshot{Version: SnapshotVersion, GeneratedAt: time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC), Repositories: map[string]SnapshotEntry{}}
+	for i, v := range versions {
+		s.Repositories[string(rune('a'+i))] = SnapshotEntry{CommitSHA: "sha", Analyzer: "poetry", Dependencies: map[string]string{"Django": v}}
+	}
+	return s
+}
+
+func TestArchiveSnapshot_PrunesOldest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snap.history")
+	for day := 3; day >= 1; day-- { // Archive out of order: names sort by time
+		if err := ArchiveSnapshot(dir, trendSnapshot(day, "4.2.0"), 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ReadDir = %v, %v; want 2 snapshots", entries, err)
+	}
+
+	history, err := LoadSnapshotHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var days []int
+	for _, s := range history {
+		days = append(days, s.GeneratedAt.Day())
+	}
+	if !slices.Equal(days, []int{2, 3}) {
+		t.Errorf("history days = %v, want the two newest oldest first", days)
+	}
+}
+
+func TestLoadSnapshotHistory_Missing(t *testing.T) {
+	history, err := LoadSnapshotHistory(filepath.Join(t.TempDir(), "none.history"))
+	if err != nil || len(history) != 0 {
+		t.Errorf("LoadSnapshotHistory = %v, %v; want empty", history, err)
+	}
+}
+
+func TestHistoryDir(t *testing.T) {
+	if got := HistoryDir("/cache/snapshots/abc.json"); got != "/cache/snapshots/abc.history" {
+		t.Errorf("HistoryDir = %q", got)
+	}
+}
+
+func TestTrend(t *testing.T) {
+	history := []*Snapshot{
+		trendSnapshot(1, "3.2.25", "3.2.25", "4.2.0"),
+		trendSnapshot(2, "3.2.25", "4.2.0", "4.2.1"),
+		trendSnapshot(3, "4.2.1", "4.2.1", "5.0.0", ""),
+	}
+	tr := Trend(history, "django")
+	if !slices.Equal(tr.Versions, []string{"3.2.25", "4.2.0", "4.2.1", "5.0.0"}) {
+		t.Errorf("Versions = %v", tr.Versions)
+	}
+	if tr.Points[2].Repositories != 3 {
+		t.Errorf("unversioned repository should not be counted: %+v", tr.Points[2])
+	}
+	if got := tr.Counts("3.2.25"); !slices.Equal(got, []int{2, 1, 0}) {
+		t.Errorf("Counts(3.2.25) = %v", got)
+	}
+
+	rng, err := versioning.ParseRange(tr.Ecosystem, ">=4.2,<4.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{1.0 / 3, 2.0 / 3, 2.0 / 3}
+	if got := tr.Adoption(rng); !slices.Equal(got, want) {
+		t.Errorf("Adoption = %v, want %v", got, want)
+	}
+
+	if missing := Trend(history, "flask"); len(missing.Versions) != 0 || len(missing.Points) != 3 {
+		t.Errorf("untracked package trend = %+v", missing)
+	}
+}
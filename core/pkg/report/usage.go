@@ -0,0 +1,84 @@
+package report
+
+import (
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// Relations of a package to a repository that uses it
+const (
+	RelationDirect     = "direct"
+	RelationTransitive = "transitive"
+	// RelationUnknown means the repository records neither manifest
+	// declarations nor dependency edges for the package
+	RelationUnknown = "unknown"
+)
+
+// Usage is one repository whose lock files contain a package, as found from
+// the repository's dependency graph (see Generator.SetIncludeGraph)
+type Usage struct {
+	Repository string   `json:"repository"` // RepositoryReport.Key
+	Package    string   `json:"package"`    // Normalized package name
+	Version    string   `json:"version"`
+	Files      []string `json:"files"`
+	Relation   string   `json:"relation"`
+	// RequiredBy lists the packages requiring this one directly
+	RequiredBy []string `json:"requiredBy,omitempty"`
+}
+
+// FindUsages answers "who uses pkg?" across the report's repositories, in
+// report order. versionRange, when not empty, keeps only usages whose locked
+// version satisfies it (see versioning.ParseRange). Failed repositories and
+// those without a graph are skipped.
+func (r *Report) FindUsages(pkg, versionRange string) ([]Usage, error) {
+	ranges := make(map[dependencies.Ecosystem]*versioning.Range)
+	var usages []Usage
+	for _, rr := range r.Repositories {
+		if rr.Error != nil || rr.Graph == nil {
+			continue
+		}
+		eco := rr.GetEcosystem()
+		name := dependencies.NormalizeName(eco, pkg)
+		node, ok := rr.Graph.Node(name)
+		if !ok {
+			continue
+		}
+		if versionRange != "" {
+			rng, ok := ranges[eco]
+			if !ok {
+				var err error
+				if rng, err = versioning.ParseRange(eco, versionRange); err != nil {
+					return nil, err
+				}
+				ranges[eco] = rng
+			}
+			if !rng.Contains(node.Version) {
+				continue
+			}
+		}
+		usages = append(usages, Usage{
+			Repository: rr.Key(),
+			Package:    node.Name,
+			Version:    node.Version,
+			Files:      node.Files,
+			Relation:   relation(rr.Graph, node),
+			RequiredBy: rr.Graph.Dependents(node.Name, false),
+		})
+	}
+	return usages, nil
+}
+
+// relation classifies node as direct or transitive. A package nothing
+// requires is only known to be transitive when the graph records some direct
+// dependencies, since otherwise nothing says what the project declared.
+func relation(g *dependencies.Graph, node dependencies.GraphNode) string {
+	if node.Direct {
+		return RelationDirect
+	}
+	for _, n := range g.Nodes {
+		if n.Direct {
+			return RelationTransitive
+		}
+	}
+	return RelationUnknown
+}
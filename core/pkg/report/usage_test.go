@@ -0,0 +1,53 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+func TestFindUsages(t *testing.T) {
+	uv := dependencies.BuildGraph(dependencies.EcosystemPython, map[string][]dependencies.Dependency{
+		"uv.lock": {
+			{Name: "requests", Version: "2.31.0", Direct: true, Requires: []string{"urllib3"}},
+			{Name: "urllib3", Version: "2.0.7"},
+		},
+	})
+	pipfile := dependencies.BuildGraph(dependencies.EcosystemPython, map[string][]dependencies.Dependency{
+		"svc/Pipfile.lock": {{Name: "Urllib3", Version: "1.26.18"}},
+	})
+	rpt := &Report{Repositories: []RepositoryReport{
+		{Provider: "github", Owner: "o", Repository: "a", Ref: "main", Analyzer: "uvlock", Graph: uv},
+		{Provider: "github", Owner: "o", Repository: "b", Ref: "main", Analyzer: "pipfile", Graph: pipfile},
+		{Provider: "github", Owner: "o", Repository: "c", Ref: "main", Analyzer: "uvlock"},
+	}}
+
+	usages, err := rpt.FindUsages("URLLib3", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 2 {
+		t.Fatalf("expected 2 usages, got %+v", usages)
+	}
+	if u := usages[0]; u.Repository != "github:o/a@main" || u.Relation != RelationTransitive || !slices.Equal(u.RequiredBy, []string{"requests"}) || !slices.Equal(u.Files, []string{"uv.lock"}) {
+		t.Errorf("unexpected uv usage: %+v", u)
+	}
+	if u := usages[1]; u.Relation != RelationUnknown || u.Version != "1.26.18" || !slices.Equal(u.Files, []string{"svc/Pipfile.lock"}) {
+		t.Errorf("unexpected Pipfile usage: %+v", u)
+	}
+
+	usages, err = rpt.FindUsages("urllib3", ">=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 1 || usages[0].Version != "2.0.7" {
+		t.Errorf("range filter: %+v", usages)
+	}
+	if usages, _ := rpt.FindUsages("requests", ""); len(usages) != 1 || usages[0].Relation != RelationDirect {
+		t.Errorf("requests: %+v", usages)
+	}
+	if _, err := rpt.FindUsages("urllib3", ">=bogus"); err == nil {
+		t.Error("expected an invalid range error")
+	}
+}
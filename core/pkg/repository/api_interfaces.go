@@ -25,6 +25,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/google/go-github/v57/github"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
@@ -40,6 +43,9 @@ type GitHubRepositoriesService interface {
 	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
 	// GetContents retrieves either a file OR a directory listing depending on path.
 	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+	// DownloadContents streams a file's raw content, including files over the
+	// 1 MB inline limit of GetContents.
+	DownloadContents(ctx context.Context, owner, repo, filepath string, opts *github.RepositoryContentGetOptions) (io.ReadCloser, *github.Response, error)
 }
 
 // GitHubGitService abstracts git tree traversal used for recursive file listing.
@@ -48,6 +54,49 @@ type GitHubGitService interface {
 	GetTree(ctx context.Context, owner, repo, sha string, recursive bool) (*github.Tree, *github.Response, error)
 }
 
+// GitHubPullRequestsService abstracts pull request listing used for update PR
+// enrichment and creation used for dependency bumps.
+type GitHubPullRequestsService interface {
+	// List returns pull requests for a repository filtered by opts.
+	List(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	// Create opens a pull request.
+	Create(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+// GitHubCommitsService abstracts resolving a ref to its commit SHA.
+type GitHubCommitsService interface {
+	// GetCommitSHA1 returns the SHA of the commit ref points to.
+	GetCommitSHA1(ctx context.Context, owner, repo, ref, lastSHA string) (string, *github.Response, error)
+}
+
+// GitHubDiscoveryService abstracts the account and repository listing calls
+// used by repository discovery (see RepositoryDiscoverer).
+type GitHubDiscoveryService interface {
+	// GetUser fetches a user; an empty login returns the authenticated user.
+	GetUser(ctx context.Context, login string) (*github.User, *github.Response, error)
+	// ListByAuthenticatedUser lists repositories the token can access.
+	ListByAuthenticatedUser(ctx context.Context, opts *github.RepositoryListByAuthenticatedUserOptions) ([]*github.Repository, *github.Response, error)
+	// ListByOrg lists an organization's repositories.
+	ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error)
+	// ListByUser lists a user's public repositories.
+	ListByUser(ctx context.Context, user string, opts *github.RepositoryListByUserOptions) ([]*github.Repository, *github.Response, error)
+}
+
+// GitHubRefsService abstracts branch and tag listing (see RefLister).
+type GitHubRefsService interface {
+	// ListBranches lists a repository's branches.
+	ListBranches(ctx context.Context, owner, repo string, opts *github.BranchListOptions) ([]*github.Branch, *github.Response, error)
+	// ListTags lists a repository's tags.
+	ListTags(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.RepositoryTag, *github.Response, error)
+}
+
+// GitHubArchivesService abstracts repository archive downloads (see
+// ArchiveDownloader).
+type GitHubArchivesService interface {
+	// DownloadTarball streams the gzipped tarball of ref.
+	DownloadTarball(ctx context.Context, owner, repo, ref string) (io.ReadCloser, *github.Response, error)
+}
+
 // githubRepositoriesWrapper is the production wrapper implementing GitHubRepositoriesService.
 type githubRepositoriesWrapper struct {
 	client *github.Client
@@ -61,6 +110,10 @@ func (w *githubRepositoriesWrapper) GetContents(ctx context.Context, owner, repo
 	return w.client.Repositories.GetContents(ctx, owner, repo, path, opts)
 }
 
+func (w *githubRepositoriesWrapper) DownloadContents(ctx context.Context, owner, repo, filepath string, opts *github.RepositoryContentGetOptions) (io.ReadCloser, *github.Response, error) {
+	return w.client.Repositories.DownloadContents(ctx, owner, repo, filepath, opts)
+}
+
 // githubGitWrapper is the production wrapper implementing GitHubGitService.
 type githubGitWrapper struct {
 	client *github.Client
@@ -70,10 +123,98 @@ func (w *githubGitWrapper) GetTree(ctx context.Context, owner, repo, sha string,
 	return w.client.Git.GetTree(ctx, owner, repo, sha, recursive)
 }
 
+// githubPullRequestsWrapper is the production wrapper implementing GitHubPullRequestsService.
+type githubPullRequestsWrapper struct {
+	client *github.Client
+}
+
+func (w *githubPullRequestsWrapper) List(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	return w.client.PullRequests.List(ctx, owner, repo, opts)
+}
+
+func (w *githubPullRequestsWrapper) Create(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	return w.client.PullRequests.Create(ctx, owner, repo, pull)
+}
+
+// githubCommitsWrapper is the production wrapper implementing GitHubCommitsService.
+type githubCommitsWrapper struct {
+	client *github.Client
+}
+
+func (w *githubCommitsWrapper) GetCommitSHA1(ctx context.Context, owner, repo, ref, lastSHA string) (string, *github.Response, error) {
+	return w.client.Repositories.GetCommitSHA1(ctx, owner, repo, ref, lastSHA)
+}
+
+// githubDiscoveryWrapper is the production wrapper implementing GitHubDiscoveryService.
+type githubDiscoveryWrapper struct {
+	client *github.Client
+}
+
+func (w *githubDiscoveryWrapper) GetUser(ctx context.Context, login string) (*github.User, *github.Response, error) {
+	return w.client.Users.Get(ctx, login)
+}
+
+func (w *githubDiscoveryWrapper) ListByAuthenticatedUser(ctx context.Context, opts *github.RepositoryListByAuthenticatedUserOptions) ([]*github.Repository, *github.Response, error) {
+	return w.client.Repositories.ListByAuthenticatedUser(ctx, opts)
+}
+
+func (w *githubDiscoveryWrapper) ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+	return w.client.Repositories.ListByOrg(ctx, org, opts)
+}
+
+func (w *githubDiscoveryWrapper) ListByUser(ctx context.Context, user string, opts *github.RepositoryListByUserOptions) ([]*github.Repository, *github.Response, error) {
+	return w.client.Repositories.ListByUser(ctx, user, opts)
+}
+
+// githubRefsWrapper is the production wrapper implementing GitHubRefsService.
+type githubRefsWrapper struct {
+	client *github.Client
+}
+
+func (w *githubRefsWrapper) ListBranches(ctx context.Context, owner, repo string, opts *github.BranchListOptions) ([]*github.Branch, *github.Response, error) {
+	return w.client.Repositories.ListBranches(ctx, owner, repo, opts)
+}
+
+func (w *githubRefsWrapper) ListTags(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.RepositoryTag, *github.Response, error) {
+	return w.client.Repositories.ListTags(ctx, owner, repo, opts)
+}
+
+// githubArchivesWrapper is the production wrapper implementing GitHubArchivesService.
+type githubArchivesWrapper struct {
+	client *github.Client
+}
+
+// DownloadTarball follows the archive link GitHub redirects to, through the
+// same HTTP client as API requests
+func (w *githubArchivesWrapper) DownloadTarball(ctx context.Context, owner, repo, ref string) (io.ReadCloser, *github.Response, error) {
+	link, resp, err := w.client.Repositories.GetArchiveLink(ctx, owner, repo, github.Tarball, &github.RepositoryContentGetOptions{Ref: ref}, 1)
+	if err != nil {
+		return nil, resp, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	res, err := w.client.Client().Do(req)
+	if err != nil {
+		return nil, resp, err
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, resp, fmt.Errorf("unexpected status code: %s", res.Status)
+	}
+	return res.Body, resp, nil
+}
+
 // GitHubAPI groups the narrowed GitHub service interfaces.
 type GitHubAPI struct {
 	Repositories GitHubRepositoriesService
 	Git          GitHubGitService
+	PullRequests GitHubPullRequestsService
+	Commits      GitHubCommitsService
+	Discovery    GitHubDiscoveryService
+	Refs         GitHubRefsService
+	Archives     GitHubArchivesService
 }
 
 // wrapGitHubClient constructs GitHubAPI from a *github.Client.
@@ -81,6 +222,11 @@ func wrapGitHubClient(c *github.Client) GitHubAPI {
 	return GitHubAPI{
 		Repositories: &githubRepositoriesWrapper{client: c},
 		Git:          &githubGitWrapper{client: c},
+		PullRequests: &githubPullRequestsWrapper{client: c},
+		Commits:      &githubCommitsWrapper{client: c},
+		Discovery:    &githubDiscoveryWrapper{client: c},
+		Refs:         &githubRefsWrapper{client: c},
+		Archives:     &githubArchivesWrapper{client: c},
 	}
 }
 
@@ -100,7 +246,41 @@ type GitLabRepositoriesService interface {
 
 // GitLabRepositoryFilesService abstracts file content retrieval.
 type GitLabRepositoryFilesService interface {
-	GetFile(projectID string, filePath string, opts *gitlab.GetFileOptions, options ...gitlab.RequestOptionFunc) (*gitlab.File, *gitlab.Response, error)
+	// StreamRawFile copies a file's raw content to w as it is received.
+	StreamRawFile(projectID string, filePath string, opts *gitlab.GetRawFileOptions, w io.Writer, options ...gitlab.RequestOptionFunc) (*gitlab.Response, error)
+}
+
+// GitLabMergeRequestsService abstracts merge request listing used for update PR
+// enrichment and creation used for dependency bumps.
+type GitLabMergeRequestsService interface {
+	ListProjectMergeRequests(pid any, opts *gitlab.ListProjectMergeRequestsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.BasicMergeRequest, *gitlab.Response, error)
+	CreateMergeRequest(pid any, opt *gitlab.CreateMergeRequestOptions, options ...gitlab.RequestOptionFunc) (*gitlab.MergeRequest, *gitlab.Response, error)
+}
+
+// GitLabCommitsService abstracts resolving a ref to its commit.
+type GitLabCommitsService interface {
+	GetCommit(pid any, sha string, opt *gitlab.GetCommitOptions, options ...gitlab.RequestOptionFunc) (*gitlab.Commit, *gitlab.Response, error)
+}
+
+// GitLabDiscoveryService abstracts the account and project listing calls used
+// by repository discovery (see RepositoryDiscoverer).
+type GitLabDiscoveryService interface {
+	CurrentUser(options ...gitlab.RequestOptionFunc) (*gitlab.User, *gitlab.Response, error)
+	ListProjects(opt *gitlab.ListProjectsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Project, *gitlab.Response, error)
+	ListGroupProjects(gid any, opt *gitlab.ListGroupProjectsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Project, *gitlab.Response, error)
+	ListUserProjects(uid any, opt *gitlab.ListProjectsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Project, *gitlab.Response, error)
+}
+
+// GitLabRefsService abstracts branch and tag listing (see RefLister).
+type GitLabRefsService interface {
+	ListBranches(pid any, opts *gitlab.ListBranchesOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Branch, *gitlab.Response, error)
+	ListTags(pid any, opt *gitlab.ListTagsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Tag, *gitlab.Response, error)
+}
+
+// GitLabArchivesService abstracts repository archive downloads (see
+// ArchiveDownloader).
+type GitLabArchivesService interface {
+	StreamArchive(pid any, w io.Writer, opt *gitlab.ArchiveOptions, options ...gitlab.RequestOptionFunc) (*gitlab.Response, error)
 }
 
 // gitlabProjectsWrapper is the production wrapper for project metadata.
@@ -126,8 +306,71 @@ type gitlabRepositoryFilesWrapper struct {
 	client *gitlab.Client
 }
 
-func (w *gitlabRepositoryFilesWrapper) GetFile(projectID string, filePath string, opts *gitlab.GetFileOptions, options ...gitlab.RequestOptionFunc) (*gitlab.File, *gitlab.Response, error) {
-	return w.client.RepositoryFiles.GetFile(projectID, filePath, opts, options...)
+// StreamRawFile issues the same request as RepositoryFiles.GetRawFile, which
+// buffers the whole body, but hands the body to w instead.
+func (w *gitlabRepositoryFilesWrapper) StreamRawFile(projectID string, filePath string, opts *gitlab.GetRawFileOptions, dst io.Writer, options ...gitlab.RequestOptionFunc) (*gitlab.Response, error) {
+	u := fmt.Sprintf("projects/%s/repository/files/%s/raw", gitlab.PathEscape(projectID), gitlab.PathEscape(filePath))
+	req, err := w.client.NewRequest(http.MethodGet, u, opts, options)
+	if err != nil {
+		return nil, err
+	}
+	return w.client.Do(req, dst)
+}
+
+// gitlabMergeRequestsWrapper is the production wrapper for merge request listing.
+type gitlabMergeRequestsWrapper struct {
+	client *gitlab.Client
+}
+
+func (w *gitlabMergeRequestsWrapper) ListProjectMergeRequests(pid any, opts *gitlab.ListProjectMergeRequestsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.BasicMergeRequest, *gitlab.Response, error) {
+	return w.client.MergeRequests.ListProjectMergeRequests(pid, opts, options...)
+}
+
+func (w *gitlabMergeRequestsWrapper) CreateMergeRequest(pid any, opt *gitlab.CreateMergeRequestOptions, options ...gitlab.RequestOptionFunc) (*gitlab.MergeRequest, *gitlab.Response, error) {
+	return w.client.MergeRequests.CreateMergeRequest(pid, opt, options...)
+}
+
+// gitlabCommitsWrapper is the production wrapper for commit lookups.
+type gitlabCommitsWrapper struct {
+	client *gitlab.Client
+}
+
+func (w *gitlabCommitsWrapper) GetCommit(pid any, sha string, opt *gitlab.GetCommitOptions, options ...gitlab.RequestOptionFunc) (*gitlab.Commit, *gitlab.Response, error) {
+	return w.client.Commits.GetCommit(pid, sha, opt, options...)
+}
+
+// gitlabDiscoveryWrapper is the production wrapper for repository discovery.
+type gitlabDiscoveryWrapper struct {
+	client *gitlab.Client
+}
+
+func (w *gitlabDiscoveryWrapper) CurrentUser(options ...gitlab.RequestOptionFunc) (*gitlab.User, *gitlab.Response, error) {
+	return w.client.Users.CurrentUser(options...)
+}
+
+func (w *gitlabDiscoveryWrapper) ListProjects(opt *gitlab.ListProjectsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Project, *gitlab.Response, error) {
+	return w.client.Projects.ListProjects(opt, options...)
+}
+
+func (w *gitlabDiscoveryWrapper) ListGroupProjects(gid any, opt *gitlab.ListGroupProjectsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Project, *gitlab.Response, error) {
+	return w.client.Groups.ListGroupProjects(gid, opt, options...)
+}
+
+func (w *gitlabDiscoveryWrapper) ListUserProjects(uid any, opt *gitlab.ListProjectsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Project, *gitlab.Response, error) {
+	return w.client.Projects.ListUserProjects(uid, opt, options...)
+}
+
+// gitlabRefsWrapper is the production wrapper for branch and tag listing.
+type gitlabRefsWrapper struct {
+	client *gitlab.Client
+}
+
+func (w *gitlabRefsWrapper) ListBranches(pid any, opts *gitlab.ListBranchesOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Branch, *gitlab.Response, error) {
+	return w.client.Branches.ListBranches(pid, opts, options...)
+}
+
+func (w *gitlabRefsWrapper) ListTags(pid any, opt *gitlab.ListTagsOptions, options ...gitlab.RequestOptionFunc) ([]*gitlab.Tag, *gitlab.Response, error) {
+	return w.client.Tags.ListTags(pid, opt, options...)
 }
 
 // GitLabAPI groups the narrowed GitLab service interfaces.
@@ -135,6 +378,11 @@ type GitLabAPI struct {
 	Projects        GitLabProjectsService
 	Repositories    GitLabRepositoriesService
 	RepositoryFiles GitLabRepositoryFilesService
+	MergeRequests   GitLabMergeRequestsService
+	Commits         GitLabCommitsService
+	Discovery       GitLabDiscoveryService
+	Refs            GitLabRefsService
+	Archives        GitLabArchivesService
 }
 
 // wrapGitLabClient constructs GitLabAPI from a *gitlab.Client.
@@ -143,6 +391,11 @@ func wrapGitLabClient(c *gitlab.Client) GitLabAPI {
 		Projects:        &gitlabProjectsWrapper{client: c},
 		Repositories:    &gitlabRepositoriesWrapper{client: c},
 		RepositoryFiles: &gitlabRepositoryFilesWrapper{client: c},
+		MergeRequests:   &gitlabMergeRequestsWrapper{client: c},
+		Commits:         &gitlabCommitsWrapper{client: c},
+		Discovery:       &gitlabDiscoveryWrapper{client: c},
+		Refs:            &gitlabRefsWrapper{client: c},
+		Archives:        c.Repositories,
 	}
 }
 
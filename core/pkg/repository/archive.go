@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// ArchiveDownloader is an optional capability implemented by clients able to
+// download a repository's whole tree at a ref as one gzipped tarball: a
+// single request instead of one per listed directory and fetched file.
+// Callers should type-assert a Client against this interface before use.
+type ArchiveDownloader interface {
+	// DownloadArchive streams the .tar.gz archive of ref; an empty ref
+	// downloads the default branch. Callers must close the reader.
+	DownloadArchive(ctx context.Context, owner, repo, ref string) (io.ReadCloser, error)
+}
+
+// DownloadArchive streams the tarball of ref from GitHub
+func (g *GitHubClient) DownloadArchive(ctx context.Context, owner, repo, ref string) (io.ReadCloser, error) {
+	if g.api.Archives == nil {
+		return nil, fmt.Errorf("archive download not available")
+	}
+	rc, _, err := g.api.Archives.DownloadTarball(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download archive from GitHub: %w", err)
+	}
+	return rc, nil
+}
+
+// DownloadArchive streams the tarball of ref from GitLab. Request errors
+// surface from Read.
+func (g *GitLabClient) DownloadArchive(ctx context.Context, owner, repo, ref string) (io.ReadCloser, error) {
+	if g.api.Archives == nil {
+		return nil, fmt.Errorf("archive download not available")
+	}
+	opts := &gitlab.ArchiveOptions{Format: gitlab.Ptr("tar.gz")}
+	if ref != "" {
+		opts.SHA = gitlab.Ptr(ref)
+	}
+	projectID := gitlabProjectID(owner, repo)
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := g.api.Archives.StreamArchive(projectID, pw, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			err = fmt.Errorf("failed to download archive from GitLab: %w", err)
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// ArchiveClient serves one repository at one ref from its downloaded
+// archive. Listings come from the archive, and the content of the files its
+// keep function accepted is held in memory; everything else (other
+// repositories or refs, files whose content was not kept, repository
+// metadata) goes to the wrapped Client.
+type ArchiveClient struct {
+	Client
+	owner, repo, ref string
+	files            []FileInfo // Sorted by path, directories included
+	content          map[string]string
+}
+
+// NewArchiveClient downloads the archive of owner/repo at ref through client,
+// which must implement ArchiveDownloader, keeping the content of the files
+// keep accepts (nil keeps none). maxFileSize bounds a kept file as
+// Config.MaxFileSize does; larger files are left to client.
+func NewArchiveClient(ctx context.Context, client Client, owner, repo, ref string, keep func(path string) bool, maxFileSize int64) (*ArchiveClient, error) {
+	downloader, ok := client.(ArchiveDownloader)
+	if !ok {
+		return nil, fmt.Errorf("provider cannot download archives")
+	}
+	rc, err := downloader.DownloadArchive(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			slog.Warn("Failed to close archive", "error", closeErr)
+		}
+	}()
+
+	a := &ArchiveClient{Client: client, owner: owner, repo: repo, ref: ref, content: make(map[string]string)}
+	if err := a.extract(rc, keep, Config{MaxFileSize: maxFileSize}.fileSizeLimit()); err != nil {
+		return nil, fmt.Errorf("failed to read archive of %s/%s: %w", owner, repo, err)
+	}
+	return a, nil
+}
+
+// extract records the archive's entries, without the top-level directory
+// providers wrap them in (e.g. "owner-repo-sha/")
+func (a *ArchiveClient) extract(r io.Reader, keep func(string) bool, limit int64) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	dirs := make(map[string]bool)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		_, p, found := strings.Cut(strings.TrimPrefix(hdr.Name, "./"), "/")
+		p = strings.TrimSuffix(p, "/")
+		if !found || p == "" {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			dirs[p] = true
+		case tar.TypeReg:
+			for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+				dirs[dir] = true
+			}
+			a.files = append(a.files, FileInfo{Path: p, Name: path.Base(p), Type: "file", Size: hdr.Size})
+			if keep == nil || !keep(p) || (limit > 0 && hdr.Size > limit) {
+				continue
+			}
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			a.content[p] = string(content)
+		}
+	}
+	for dir := range dirs {
+		a.files = append(a.files, FileInfo{Path: dir, Name: path.Base(dir), Type: "dir"})
+	}
+	slices.SortFunc(a.files, func(x, y FileInfo) int { return strings.Compare(x.Path, y.Path) })
+	return nil
+}
+
+// serves reports whether a request is for the archived repository and ref
+func (a *ArchiveClient) serves(owner, repo, ref string) bool {
+	return owner == a.owner && repo == a.repo && ref == a.ref
+}
+
+// ListFiles lists the direct children of dir from the archive
+func (a *ArchiveClient) ListFiles(ctx context.Context, owner, repo, ref, dir string) ([]FileInfo, error) {
+	if !a.serves(owner, repo, ref) {
+		return a.Client.ListFiles(ctx, owner, repo, ref, dir)
+	}
+	dir = strings.Trim(dir, "/")
+	files := make([]FileInfo, 0)
+	for _, f := range a.files {
+		if parent := path.Dir(f.Path); parent == dir || (parent == "." && dir == "") {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// ListFilesRecursive lists every file of the archive
+func (a *ArchiveClient) ListFilesRecursive(ctx context.Context, owner, repo, ref string) ([]FileInfo, error) {
+	if !a.serves(owner, repo, ref) {
+		return a.Client.ListFilesRecursive(ctx, owner, repo, ref)
+	}
+	return a.ListFilesUnder(ctx, owner, repo, ref, "")
+}
+
+// ListFilesUnder lists the archive's files below prefix
+func (a *ArchiveClient) ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]FileInfo, error) {
+	if !a.serves(owner, repo, ref) {
+		return a.Client.ListFilesUnder(ctx, owner, repo, ref, prefix)
+	}
+	prefix = strings.Trim(prefix, "/")
+	files := make([]FileInfo, 0)
+	for _, f := range a.files {
+		if f.Type == "file" && (prefix == "" || strings.HasPrefix(f.Path, prefix+"/")) {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// GetFileContent returns a kept file's content from memory, and asks the
+// wrapped client for any other
+func (a *ArchiveClient) GetFileContent(ctx context.Context, owner, repo, ref, filePath string) (string, error) {
+	if a.serves(owner, repo, ref) {
+		if content, ok := a.content[strings.TrimPrefix(filePath, "/")]; ok {
+			return content, nil
+		}
+	}
+	return a.Client.GetFileContent(ctx, owner, repo, ref, filePath)
+}
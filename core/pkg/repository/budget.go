@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+)
+
+// ErrBudgetExhausted matches (errors.Is) every *BudgetExhaustedError
+var ErrBudgetExhausted = errors.New("API request budget exhausted")
+
+// BudgetExhaustedError is returned for requests refused because a Budget ran
+// out. It is never retried.
+type BudgetExhaustedError struct {
+	Scope string // What the budget covers (e.g. "github" or "github:org/repo")
+	Limit int    // Configured number of requests
+}
+
+func (e *BudgetExhaustedError) Error() string {
+	return fmt.Sprintf("API request budget exhausted for %s (limit %d requests)", e.Scope, e.Limit)
+}
+
+// Is lets errors.Is(err, ErrBudgetExhausted) match
+func (e *BudgetExhaustedError) Is(target error) bool {
+	return target == ErrBudgetExhausted
+}
+
+// Budget caps the number of API requests (including retried attempts) issued
+// through the clients it is attached to (see Config.Budgets). It is safe for
+// concurrent use. A nil *Budget is unlimited.
+type Budget struct {
+	scope string
+	limit int64
+	used  atomic.Int64
+}
+
+// NewBudget returns a budget of limit requests, or nil (unlimited) when limit
+// is not positive.
+func NewBudget(scope string, limit int) *Budget {
+	if limit <= 0 {
+		return nil
+	}
+	return &Budget{scope: scope, limit: int64(limit)}
+}
+
+// Take consumes one request, returning *BudgetExhaustedError when none is left
+func (b *Budget) Take() error {
+	if b == nil {
+		return nil
+	}
+	if b.used.Add(1) > b.limit {
+		b.used.Add(-1)
+		return &BudgetExhaustedError{Scope: b.scope, Limit: int(b.limit)}
+	}
+	return nil
+}
+
+// Used returns the number of requests consumed so far
+func (b *Budget) Used() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.used.Load())
+}
+
+// Limit returns the configured number of requests (0 when unlimited)
+func (b *Budget) Limit() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.limit)
+}
+
+// budgetTransport charges every request to all of its budgets before sending it
+type budgetTransport struct {
+	base    http.RoundTripper
+	budgets []*Budget
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for i, b := range t.budgets {
+		if err := b.Take(); err != nil {
+			// The request is not sent; refund the budgets already charged
+			for _, taken := range t.budgets[:i] {
+				taken.used.Add(-1)
+			}
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// apiHTTPClient returns an *http.Client applying cfg's proxy, TLS settings,
+// request timeout, tracer, fixtures, retry policy and request budgets, or nil
+// when none is configured (callers then keep their default client). Budgets
+// sit below retries so every attempt is charged; the tracer and telemetry sit
+// below both so they see exactly the requests sent, and the request timeout
+// below them so each attempt gets its own deadline. Fixtures sit at the
+// bottom, in place of the network when replaying.
+func apiHTTPClient(cfg Config) (*http.Client, error) {
+	base, err := httpTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport := base
+	if cfg.Fixtures != nil {
+		transport = &fixtureTransport{base: transport, fixtures: cfg.Fixtures}
+	}
+	if cfg.RequestTimeout > 0 {
+		transport = &timeoutTransport{base: transport, timeout: cfg.RequestTimeout}
+	}
+	if cfg.Tracer != nil {
+		transport = &traceTransport{base: transport, tracer: cfg.Tracer}
+	}
+	if telemetry.Enabled() {
+		transport = &telemetryTransport{base: transport}
+	}
+	var budgets []*Budget
+	for _, b := range cfg.Budgets {
+		if b != nil {
+			budgets = append(budgets, b)
+		}
+	}
+	if len(budgets) > 0 {
+		transport = &budgetTransport{base: transport, budgets: budgets}
+	}
+	if cfg.Retry != nil && cfg.Retry.MaxAttempts > 1 {
+		transport = newRetryTransport(transport, *cfg.Retry)
+	}
+	if transport == http.DefaultTransport {
+		return nil, nil
+	}
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBudget(t *testing.T) {
+	if NewBudget("github", 0) != nil {
+		t.Error("non-positive limit should be unlimited (nil)")
+	}
+	var unlimited *Budget
+	if err := unlimited.Take(); err != nil || unlimited.Used() != 0 {
+		t.Errorf("nil budget: %v, used %d", err, unlimited.Used())
+	}
+
+	b := NewBudget("github", 2)
+	for i := 0; i < 2; i++ {
+		if err := b.Take(); err != nil {
+			t.Fatalf("take %d: %v", i, err)
+		}
+	}
+	err := b.Take()
+	var exhausted *BudgetExhaustedError
+	if !errors.Is(err, ErrBudgetExhausted) || !errors.As(err, &exhausted) || exhausted.Scope != "github" || exhausted.Limit != 2 {
+		t.Errorf("expected exhausted error, got %v", err)
+	}
+	if b.Used() != 2 {
+		t.Errorf("refused requests must not count, used %d", b.Used())
+	}
+}
+
+func TestAPIHTTPClient_ChargesEveryAttempt(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	provider, repo := NewBudget("github", 10), NewBudget("github:o/r@main", 3)
+	client, err := apiHTTPClient(Config{Retry: ptr(fastRetryPolicy(2)), Budgets: []*Budget{provider, nil, repo}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two requests of two attempts each: the fourth attempt exceeds the
+	// repository budget and is never sent
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	_ = resp.Body.Close()
+	_, err = client.Get(srv.URL)
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected budget error, got %v", err)
+	}
+	if calls.Load() != 3 || provider.Used() != 3 || repo.Used() != 3 {
+		t.Errorf("calls %d, provider used %d, repo used %d; want 3 each", calls.Load(), provider.Used(), repo.Used())
+	}
+
+	if client, _ := apiHTTPClient(Config{}); client != nil {
+		t.Error("expected nil client without retries or budgets")
+	}
+}
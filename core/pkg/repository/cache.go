@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultContentCacheSize bounds a ContentCache's disk usage when NewContentCache
+// is given no limit
+const DefaultContentCacheSize int64 = 256 << 20
+
+// contentCacheFormat versions the entry layout; entries of another version
+// are never read
+const contentCacheFormat = "v1"
+
+// DefaultContentCacheDir returns the content cache directory under the user
+// cache directory
+func DefaultContentCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "devdashboard", "content"), nil
+}
+
+// ContentCache keeps the file listings and file contents clients read at a
+// commit SHA on disk, so repeated runs against unchanged commits do not
+// fetch them again. Entries are keyed by provider scope, repository, commit
+// SHA, path and (when a listing reported it) the file's blob SHA, and carry
+// a SHA-256 digest of their data checked on every read; entries that fail
+// the check are discarded. Once the cache outgrows its size limit the least
+// recently used entries are evicted. It is safe for concurrent use.
+type ContentCache struct {
+	dir     string
+	maxSize int64
+
+	mu     sync.Mutex
+	size   int64 // Bytes on disk, as of the last scan plus later writes
+	hits   int
+	misses int
+}
+
+// ContentCacheStats describes a ContentCache's disk usage and, for this
+// process, its effectiveness
+type ContentCacheStats struct {
+	Dir     string `json:"dir"`
+	Entries int    `json:"entries"`
+	Size    int64  `json:"size"`              // Bytes
+	MaxSize int64  `json:"maxSize,omitempty"` // Bytes; zero when unlimited
+	Hits    int    `json:"hits,omitempty"`
+	Misses  int    `json:"misses,omitempty"`
+}
+
+// NewContentCache opens (creating it if needed) the cache in dir. maxSize
+// bounds its disk usage in bytes: zero uses DefaultContentCacheSize and a
+// negative value disables eviction.
+func NewContentCache(dir string, maxSize int64) (*ContentCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create content cache: %w", err)
+	}
+	switch {
+	case maxSize == 0:
+		maxSize = DefaultContentCacheSize
+	case maxSize < 0:
+		maxSize = 0
+	}
+	c := &ContentCache{dir: dir, maxSize: maxSize}
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		c.size += e.size
+	}
+	return c, nil
+}
+
+// cacheEntry is an entry file found by a scan
+type cacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entries lists the cache's entry files
+func (c *ContentCache) entries() ([]cacheEntry, error) {
+	var entries []cacheEntry
+	err := filepath.WalkDir(c.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Removed concurrently
+		}
+		entries = append(entries, cacheEntry{path: p, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan content cache: %w", err)
+	}
+	return entries, nil
+}
+
+// entryPath returns the file holding the entry for key
+func (c *ContentCache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(contentCacheFormat + "\x00" + key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+// get returns the data stored for key, if any and intact
+func (c *ContentCache) get(key string) ([]byte, bool) {
+	p := c.entryPath(key)
+	raw, err := os.ReadFile(p) // #nosec G304 -- path derived from a digest under the cache directory
+	if err == nil {
+		header, data, found := bytes.Cut(raw, []byte("\n"))
+		sum := sha256.Sum256(data)
+		if found && string(header) == "sha256:"+hex.EncodeToString(sum[:]) {
+			now := time.Now()
+			_ = os.Chtimes(p, now, now) // Recently used
+			c.mu.Lock()
+			c.hits++
+			c.mu.Unlock()
+			return data, true
+		}
+		slog.Warn("Discarding corrupt content cache entry", "path", p)
+		c.remove(p)
+	}
+	c.mu.Lock()
+	c.misses++
+	c.mu.Unlock()
+	return nil, false
+}
+
+// put stores data for key, evicting old entries if the cache grows too big.
+// Failures are logged: the cache only saves requests.
+func (c *ContentCache) put(key string, data []byte) {
+	p := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		slog.Warn("Failed to write content cache entry", "error", err)
+		return
+	}
+	sum := sha256.Sum256(data)
+	entry := append([]byte("sha256:"+hex.EncodeToString(sum[:])+"\n"), data...)
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".entry-*")
+	if err != nil {
+		slog.Warn("Failed to write content cache entry", "error", err)
+		return
+	}
+	_, err = tmp.Write(entry)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), p)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		slog.Warn("Failed to write content cache entry", "error", err)
+		return
+	}
+
+	c.mu.Lock()
+	c.size += int64(len(entry))
+	over := c.maxSize > 0 && c.size > c.maxSize
+	c.mu.Unlock()
+	if over {
+		c.evict()
+	}
+}
+
+// remove deletes an entry file
+func (c *ContentCache) remove(p string) {
+	info, err := os.Stat(p)
+	if err != nil || os.Remove(p) != nil {
+		return
+	}
+	c.mu.Lock()
+	c.size -= info.Size()
+	c.mu.Unlock()
+}
+
+// evict removes the least recently used entries until the cache fits in
+// three quarters of its limit, so a full cache is not rescanned on every
+// write
+func (c *ContentCache) evict() {
+	entries, err := c.entries()
+	if err != nil {
+		slog.Warn("Failed to evict content cache entries", "error", err)
+		return
+	}
+	slices.SortFunc(entries, func(a, b cacheEntry) int { return a.modTime.Compare(b.modTime) })
+	var size int64
+	for _, e := range entries {
+		size += e.size
+	}
+	target := c.maxSize / 4 * 3
+	for _, e := range entries {
+		if size <= target {
+			break
+		}
+		if os.Remove(e.path) == nil {
+			size -= e.size
+		}
+	}
+	c.mu.Lock()
+	c.size = size
+	c.mu.Unlock()
+}
+
+// Stats scans the cache's entries
+func (c *ContentCache) Stats() (ContentCacheStats, error) {
+	entries, err := c.entries()
+	if err != nil {
+		return ContentCacheStats{}, err
+	}
+	stats := ContentCacheStats{Dir: c.dir, Entries: len(entries), MaxSize: c.maxSize}
+	for _, e := range entries {
+		stats.Size += e.size
+	}
+	c.mu.Lock()
+	stats.Hits, stats.Misses = c.hits, c.misses
+	c.mu.Unlock()
+	return stats, nil
+}
+
+// Clear removes every entry
+func (c *ContentCache) Clear() error {
+	dirs, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to clear content cache: %w", err)
+	}
+	for _, d := range dirs {
+		if err := os.RemoveAll(filepath.Join(c.dir, d.Name())); err != nil {
+			return fmt.Errorf("failed to clear content cache: %w", err)
+		}
+	}
+	c.mu.Lock()
+	c.size = 0
+	c.mu.Unlock()
+	return nil
+}
+
+// Client wraps client so its listings and file contents at full commit
+// SHAs are served from the cache when present and stored in it otherwise.
+// scope identifies the provider instance (e.g. its name and base URL), so
+// repositories of the same name on different instances never share entries.
+func (c *ContentCache) Client(scope string, client Client) *CachedClient {
+	return &CachedClient{Client: client, cache: c, scope: scope, blobs: make(map[string]string)}
+}
+
+// CachedClient is a Client reading through a ContentCache (see
+// ContentCache.Client). Requests at branch or tag names, whose content can
+// change, go straight to the wrapped Client.
+type CachedClient struct {
+	Client
+	cache *ContentCache
+	scope string
+
+	mu    sync.Mutex
+	blobs map[string]string // Blob SHAs listings reported, by cacheKey of the file
+}
+
+// cacheable reports whether ref names an immutable commit
+func cacheable(ref string) bool {
+	return len(ref) == 40 && IsCommitSHA(ref)
+}
+
+// cacheKey joins a request's identifying parts
+func (c *CachedClient) cacheKey(kind, owner, repo, ref, p string) string {
+	return strings.Join([]string{kind, c.scope, strings.ToLower(owner + "/" + repo), strings.ToLower(ref), strings.Trim(p, "/")}, "\x00")
+}
+
+// listing serves a listing from the cache or from list, remembering the
+// blob SHAs of its files
+func (c *CachedClient) listing(kind, owner, repo, ref, prefix string, list func() ([]FileInfo, error)) ([]FileInfo, error) {
+	if !cacheable(ref) {
+		return list()
+	}
+	key := c.cacheKey(kind, owner, repo, ref, prefix)
+	var files []FileInfo
+	if data, ok := c.cache.get(key); !ok || json.Unmarshal(data, &files) != nil {
+		var err error
+		if files, err = list(); err != nil {
+			return nil, err
+		}
+		if data, err := json.Marshal(files); err == nil {
+			c.cache.put(key, data)
+		}
+	}
+	c.mu.Lock()
+	for _, f := range files {
+		if f.SHA != "" {
+			c.blobs[c.cacheKey("file", owner, repo, ref, f.Path)] = f.SHA
+		}
+	}
+	c.mu.Unlock()
+	return files, nil
+}
+
+// ListFilesRecursive serves the listing of a commit from the cache when present
+func (c *CachedClient) ListFilesRecursive(ctx context.Context, owner, repo, ref string) ([]FileInfo, error) {
+	return c.listing("tree", owner, repo, ref, "", func() ([]FileInfo, error) {
+		return c.Client.ListFilesRecursive(ctx, owner, repo, ref)
+	})
+}
+
+// ListFilesUnder serves the listing of a commit from the cache when present
+func (c *CachedClient) ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]FileInfo, error) {
+	return c.listing("under", owner, repo, ref, prefix, func() ([]FileInfo, error) {
+		return c.Client.ListFilesUnder(ctx, owner, repo, ref, prefix)
+	})
+}
+
+// GetFileContent serves a file of a commit from the cache when present
+func (c *CachedClient) GetFileContent(ctx context.Context, owner, repo, ref, filePath string) (string, error) {
+	if !cacheable(ref) {
+		return c.Client.GetFileContent(ctx, owner, repo, ref, filePath)
+	}
+	key := c.cacheKey("file", owner, repo, ref, filePath)
+	c.mu.Lock()
+	if blob := c.blobs[key]; blob != "" {
+		key += "\x00" + blob
+	}
+	c.mu.Unlock()
+	if data, ok := c.cache.get(key); ok {
+		return string(data), nil
+	}
+	content, err := c.Client.GetFileContent(ctx, owner, repo, ref, filePath)
+	if err != nil {
+		return "", err
+	}
+	c.cache.put(key, []byte(content))
+	return content, nil
+}
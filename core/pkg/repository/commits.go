@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// CommitResolver is an optional capability implemented by clients able to
+// resolve a ref to the commit SHA it currently points to. Callers should
+// type-assert a Client against this interface before use.
+type CommitResolver interface {
+	// ResolveCommit returns the commit SHA of ref. An empty ref resolves the
+	// repository's default branch.
+	ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error)
+}
+
+// ResolveCommit returns the commit SHA ref points to on GitHub
+func (g *GitHubClient) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	if g.api.Commits == nil {
+		return "", fmt.Errorf("commit lookup not available")
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	sha, resp, err := g.api.Commits.GetCommitSHA1(ctx, owner, repo, ref, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ref %q on GitHub: %w", ref, err)
+	}
+	if resp != nil && resp.Body != nil {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn("Failed to close response body", "error", closeErr)
+		}
+	}
+	return sha, nil
+}
+
+// ResolveCommit returns the commit SHA ref points to on GitLab
+func (g *GitLabClient) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	if g.api.Commits == nil {
+		return "", fmt.Errorf("commit lookup not available")
+	}
+	if ref == "" {
+		info, err := g.GetRepositoryInfo(ctx, owner, repo)
+		if err != nil {
+			return "", err
+		}
+		ref = info.DefaultBranch
+	}
+	projectID := gitlabProjectID(owner, repo)
+	commit, resp, err := g.api.Commits.GetCommit(projectID, ref, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ref %q on GitLab: %w", ref, err)
+	}
+	if resp != nil && resp.Body != nil {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn("Failed to close response body", "error", closeErr)
+		}
+	}
+	return commit.ID, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+type mockGitHubCommits struct {
+	gotRef string
+	err    error
+}
+
+func (m *mockGitHubCommits) GetCommitSHA1(_ context.Context, _, _, ref, _ string) (string, *github.Response, error) {
+	m.gotRef = ref
+	return "abc123", &github.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, m.err
+}
+
+type mockGitLabCommits struct {
+	gotRef string
+}
+
+func (m *mockGitLabCommits) GetCommit(_ any, sha string, _ *gitlab.GetCommitOptions, _ ...gitlab.RequestOptionFunc) (*gitlab.Commit, *gitlab.Response, error) {
+	m.gotRef = sha
+	return &gitlab.Commit{ID: "def456"}, &gitlab.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
+}
+
+func TestGitHubResolveCommit(t *testing.T) {
+	commits := &mockGitHubCommits{}
+	client := &GitHubClient{api: GitHubAPI{Commits: commits}}
+	sha, err := client.ResolveCommit(context.Background(), "o", "r", "")
+	if err != nil || sha != "abc123" || commits.gotRef != "HEAD" {
+		t.Errorf("ResolveCommit = %q, %v (ref %q)", sha, err, commits.gotRef)
+	}
+
+	commits.err = errors.New("boom")
+	if _, err := client.ResolveCommit(context.Background(), "o", "r", "main"); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := (&GitHubClient{}).ResolveCommit(context.Background(), "o", "r", "main"); err == nil {
+		t.Error("expected error without commits service")
+	}
+}
+
+func TestGitLabResolveCommit(t *testing.T) {
+	commits := &mockGitLabCommits{}
+	client := &GitLabClient{api: GitLabAPI{
+		Commits:  commits,
+		Projects: &mockGitLabProjects{project: &gitlab.Project{DefaultBranch: "trunk"}},
+	}}
+	sha, err := client.ResolveCommit(context.Background(), "g", "p", "")
+	if err != nil || sha != "def456" || commits.gotRef != "trunk" {
+		t.Errorf("ResolveCommit = %q, %v (ref %q)", sha, err, commits.gotRef)
+	}
+}
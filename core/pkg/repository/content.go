@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+)
+
+// DefaultMaxFileSize bounds the content a client reads for a single file when
+// Config.MaxFileSize is zero. Lock files of large monorepos reach a few tens
+// of megabytes; anything beyond this is more likely a vendored artifact.
+const DefaultMaxFileSize int64 = 64 << 20
+
+// ErrFileTooLarge is matched (errors.Is) by FileTooLargeError
+var ErrFileTooLarge = errors.New("file exceeds size limit")
+
+// FileTooLargeError reports a file whose content exceeds the client's size
+// limit. Size is zero when the limit was hit while streaming a file of
+// unknown size.
+type FileTooLargeError struct {
+	Path  string
+	Size  int64
+	Limit int64
+}
+
+func (e *FileTooLargeError) Error() string {
+	if e.Size > 0 {
+		return fmt.Sprintf("file %s is %d bytes, over the %d byte limit (raise maxFileSize to analyze it)", e.Path, e.Size, e.Limit)
+	}
+	return fmt.Sprintf("file %s exceeds the %d byte limit (raise maxFileSize to analyze it)", e.Path, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrFileTooLarge) match
+func (e *FileTooLargeError) Is(target error) bool {
+	return target == ErrFileTooLarge
+}
+
+// ContentStreamer is implemented by clients that can stream file content
+// instead of returning it as one string. Callers must close the reader; a
+// FileTooLargeError may surface from Read once the size limit is crossed.
+type ContentStreamer interface {
+	OpenFileContent(ctx context.Context, owner, repo, ref, path string) (io.ReadCloser, error)
+}
+
+// OpenFile streams a file through client when it implements ContentStreamer
+// and otherwise wraps GetFileContent.
+func OpenFile(ctx context.Context, client Client, owner, repo, ref, path string) (rc io.ReadCloser, err error) {
+	ctx, span := telemetry.Start(ctx, "repository.OpenFile",
+		attribute.String("devdashboard.repository", owner+"/"+repo),
+		attribute.String("devdashboard.ref", ref),
+		attribute.String("devdashboard.file", path))
+	defer func() { telemetry.End(span, err) }()
+
+	if streamer, ok := client.(ContentStreamer); ok {
+		return streamer.OpenFileContent(ctx, owner, repo, ref, path)
+	}
+	content, err := client.GetFileContent(ctx, owner, repo, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+// fileSizeLimit returns the effective content limit; zero means unlimited
+func (c Config) fileSizeLimit() int64 {
+	switch {
+	case c.MaxFileSize == 0:
+		return DefaultMaxFileSize
+	case c.MaxFileSize < 0:
+		return 0
+	default:
+		return c.MaxFileSize
+	}
+}
+
+// checkFileSize rejects a file whose reported size exceeds limit
+func checkFileSize(path string, size, limit int64) error {
+	if limit > 0 && size > limit {
+		return &FileTooLargeError{Path: path, Size: size, Limit: limit}
+	}
+	return nil
+}
+
+// limitedContent fails reads with FileTooLargeError once more than limit
+// bytes have been read
+type limitedContent struct {
+	rc    io.ReadCloser
+	path  string
+	limit int64
+	n     int64
+}
+
+func limitContent(rc io.ReadCloser, path string, limit int64) io.ReadCloser {
+	if limit <= 0 {
+		return rc
+	}
+	return &limitedContent{rc: rc, path: path, limit: limit}
+}
+
+func (l *limitedContent) Read(p []byte) (int, error) {
+	n, err := l.rc.Read(p)
+	l.n += int64(n)
+	if l.n > l.limit {
+		return 0, &FileTooLargeError{Path: l.path, Limit: l.limit}
+	}
+	return n, err
+}
+
+func (l *limitedContent) Close() error {
+	return l.rc.Close()
+}
+
+// limitedWriter is the io.Writer counterpart of limitedContent, for APIs that
+// copy a response body into a writer. exceeded, if set, is called when the
+// limit is crossed.
+type limitedWriter struct {
+	w        io.Writer
+	path     string
+	limit    int64
+	n        int64
+	exceeded func()
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	l.n += int64(len(p))
+	if l.limit > 0 && l.n > l.limit {
+		if l.exceeded != nil {
+			l.exceeded()
+		}
+		return 0, &FileTooLargeError{Path: l.path, Limit: l.limit}
+	}
+	return l.w.Write(p)
+}
+
+// readContent reads rc to the end and closes it
+func readContent(rc io.ReadCloser) (string, error) {
+	defer func() { _ = rc.Close() }()
+	var b strings.Builder
+	if _, err := io.Copy(&b, rc); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
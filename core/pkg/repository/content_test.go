@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestGitHubOpenFileContent(t *testing.T) {
+	large := strings.Repeat("x", 2<<20)
+	repos := &mockGitHubRepos{
+		fileContents: map[string]*github.RepositoryContent{
+			"uv.lock":   {Path: github.String("uv.lock"), Size: github.Int(len(large)), Encoding: github.String("none")},
+			"huge.lock": {Path: github.String("huge.lock"), Size: github.Int(100 << 20), Encoding: github.String("none")},
+		},
+		downloads: map[string]string{"uv.lock": large},
+	}
+	client := &GitHubClient{api: GitHubAPI{Repositories: repos}}
+
+	// Over 1 MB: not inlined, so the content is downloaded
+	content, err := client.GetFileContent(context.Background(), "o", "r", "main", "uv.lock")
+	if err != nil || len(content) != len(large) {
+		t.Fatalf("GetFileContent: %d bytes, err %v", len(content), err)
+	}
+
+	// Over the limit: rejected from the metadata, before downloading
+	_, err = client.OpenFileContent(context.Background(), "o", "r", "main", "huge.lock")
+	var tooLarge *FileTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Size != 100<<20 || tooLarge.Limit != DefaultMaxFileSize {
+		t.Errorf("err = %v, want FileTooLargeError with size and default limit", err)
+	}
+
+	// A negative limit disables the check; a small one cuts the download
+	client.config.MaxFileSize = -1
+	if _, err := client.OpenFileContent(context.Background(), "o", "r", "main", "huge.lock"); err == nil || errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("unlimited: err = %v, want download error only", err)
+	}
+}
+
+func TestLimitContent(t *testing.T) {
+	rc := limitContent(io.NopCloser(strings.NewReader("0123456789")), "f", 4)
+	_, err := io.ReadAll(rc)
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("err = %v, want ErrFileTooLarge", err)
+	}
+
+	rc = limitContent(io.NopCloser(strings.NewReader("0123")), "f", 4)
+	if b, err := io.ReadAll(rc); err != nil || string(b) != "0123" {
+		t.Errorf("at limit: %q, %v", b, err)
+	}
+}
+
+func TestGitLabOpenFileContent_Limit(t *testing.T) {
+	files := &mockGitLabFiles{files: map[string]*gitlab.File{
+		"package-lock.json": {Content: base64.StdEncoding.EncodeToString([]byte(strings.Repeat("{}", 64)))},
+	}}
+	client := &GitLabClient{api: GitLabAPI{RepositoryFiles: files}, config: Config{MaxFileSize: 16}}
+
+	_, err := client.GetFileContent(context.Background(), "g", "r", "main", "package-lock.json")
+	var tooLarge *FileTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Path != "package-lock.json" {
+		t.Errorf("err = %v, want FileTooLargeError", err)
+	}
+
+	_, err = client.GetFileContent(context.Background(), "g", "r", "main", "missing")
+	if err == nil || errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("missing file: err = %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// discoveryPageSize is the page size of repository listings
+const discoveryPageSize = 100
+
+// RepositorySummary is a repository found by a RepositoryDiscoverer
+type RepositorySummary struct {
+	Owner         string // Owner, organization or (GitLab) full group path
+	Name          string // Repository name (GitLab: project path)
+	Description   string
+	DefaultBranch string
+	Private       bool
+	Archived      bool
+}
+
+// RepositoryDiscoverer is an optional capability implemented by clients able
+// to identify the account a token belongs to and list repositories, e.g. to
+// validate a token and pick repositories when setting up. Callers should
+// type-assert a Client against this interface before use.
+type RepositoryDiscoverer interface {
+	// CurrentUser returns the login of the token's account; it fails
+	// without a valid token
+	CurrentUser(ctx context.Context) (string, error)
+
+	// ListRepositories returns up to limit repositories (0 = all) of owner,
+	// an organization, group or user. An empty owner lists the repositories
+	// the token can access.
+	ListRepositories(ctx context.Context, owner string, limit int) ([]RepositorySummary, error)
+}
+
+// CurrentUser returns the login of the authenticated GitHub user
+func (g *GitHubClient) CurrentUser(ctx context.Context) (string, error) {
+	if g.api.Discovery == nil {
+		return "", fmt.Errorf("repository discovery not available")
+	}
+	user, _, err := g.api.Discovery.GetUser(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get authenticated user from GitHub: %w", err)
+	}
+	return user.GetLogin(), nil
+}
+
+// ListRepositories lists the repositories of a GitHub organization or user,
+// or those the token can access when owner is empty
+func (g *GitHubClient) ListRepositories(ctx context.Context, owner string, limit int) ([]RepositorySummary, error) {
+	if g.api.Discovery == nil {
+		return nil, fmt.Errorf("repository discovery not available")
+	}
+	var out []RepositorySummary
+	byUser := false
+	for page := 1; page != 0; {
+		list := github.ListOptions{Page: page, PerPage: discoveryPageSize}
+		var (
+			repos []*github.Repository
+			resp  *github.Response
+			err   error
+		)
+		switch {
+		case owner == "":
+			repos, resp, err = g.api.Discovery.ListByAuthenticatedUser(ctx, &github.RepositoryListByAuthenticatedUserOptions{ListOptions: list})
+		case byUser:
+			repos, resp, err = g.api.Discovery.ListByUser(ctx, owner, &github.RepositoryListByUserOptions{ListOptions: list})
+		default:
+			repos, resp, err = g.api.Discovery.ListByOrg(ctx, owner, &github.RepositoryListByOrgOptions{ListOptions: list})
+			if err != nil && StatusCode(err) == http.StatusNotFound {
+				// Not an organization; list the user's repositories instead
+				byUser = true
+				continue
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories from GitHub: %w", err)
+		}
+		for _, r := range repos {
+			out = append(out, RepositorySummary{
+				Owner:         r.GetOwner().GetLogin(),
+				Name:          r.GetName(),
+				Description:   r.GetDescription(),
+				DefaultBranch: r.GetDefaultBranch(),
+				Private:       r.GetPrivate(),
+				Archived:      r.GetArchived(),
+			})
+		}
+		if limit > 0 && len(out) >= limit {
+			return out[:limit], nil
+		}
+		page = resp.NextPage
+	}
+	return out, nil
+}
+
+// CurrentUser returns the username of the authenticated GitLab user
+func (g *GitLabClient) CurrentUser(ctx context.Context) (string, error) {
+	if g.api.Discovery == nil {
+		return "", fmt.Errorf("repository discovery not available")
+	}
+	user, _, err := g.api.Discovery.CurrentUser(gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to get authenticated user from GitLab: %w", err)
+	}
+	return user.Username, nil
+}
+
+// ListRepositories lists the projects of a GitLab group (subgroups included)
+// or user, or those the token is a member of when owner is empty
+func (g *GitLabClient) ListRepositories(ctx context.Context, owner string, limit int) ([]RepositorySummary, error) {
+	if g.api.Discovery == nil {
+		return nil, fmt.Errorf("repository discovery not available")
+	}
+	var out []RepositorySummary
+	byUser := false
+	for page := 1; page != 0; {
+		list := gitlab.ListOptions{Page: page, PerPage: discoveryPageSize}
+		var (
+			projects []*gitlab.Project
+			resp     *gitlab.Response
+			err      error
+		)
+		switch {
+		case owner == "":
+			projects, resp, err = g.api.Discovery.ListProjects(&gitlab.ListProjectsOptions{ListOptions: list, Membership: gitlab.Ptr(true)}, gitlab.WithContext(ctx))
+		case byUser:
+			projects, resp, err = g.api.Discovery.ListUserProjects(owner, &gitlab.ListProjectsOptions{ListOptions: list}, gitlab.WithContext(ctx))
+		default:
+			projects, resp, err = g.api.Discovery.ListGroupProjects(owner, &gitlab.ListGroupProjectsOptions{ListOptions: list, IncludeSubGroups: gitlab.Ptr(true)}, gitlab.WithContext(ctx))
+			if err != nil && StatusCode(err) == http.StatusNotFound {
+				// Not a group; list the user's projects instead
+				byUser = true
+				continue
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects from GitLab: %w", err)
+		}
+		for _, p := range projects {
+			s := RepositorySummary{
+				Name:          p.Path,
+				Description:   p.Description,
+				DefaultBranch: p.DefaultBranch,
+				Private:       p.Visibility == gitlab.PrivateVisibility,
+				Archived:      p.Archived,
+			}
+			if p.Namespace != nil {
+				s.Owner = p.Namespace.FullPath
+			}
+			out = append(out, s)
+		}
+		if limit > 0 && len(out) >= limit {
+			return out[:limit], nil
+		}
+		page = int(resp.NextPage)
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitHubDiscovery(t *testing.T) {
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/user":
+			_, _ = w.Write([]byte(`{"login":"alice"}`))
+		case "/api/v3/orgs/acme/repos":
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[{"name":"web","owner":{"login":"acme"},"archived":true}]`))
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/orgs/acme/repos?page=2>; rel="next"`, srvURL))
+			_, _ = w.Write([]byte(`[{"name":"api","owner":{"login":"acme"},"default_branch":"main","private":true}]`))
+		case "/api/v3/orgs/alice/repos":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case "/api/v3/users/alice/repos":
+			_, _ = w.Write([]byte(`[{"name":"dotfiles","owner":{"login":"alice"}}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	client, err := NewGitHubClient(Config{BaseURL: srv.URL + "/", Token: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if login, err := client.CurrentUser(ctx); err != nil || login != "alice" {
+		t.Errorf("CurrentUser = %q, %v; want alice", login, err)
+	}
+
+	repos, err := client.ListRepositories(ctx, "acme", 0)
+	if err != nil {
+		t.Fatalf("ListRepositories(acme): %v", err)
+	}
+	want := []RepositorySummary{
+		{Owner: "acme", Name: "api", DefaultBranch: "main", Private: true},
+		{Owner: "acme", Name: "web", Archived: true},
+	}
+	if fmt.Sprint(repos) != fmt.Sprint(want) {
+		t.Errorf("organization repositories = %+v, want %+v", repos, want)
+	}
+	if repos, err := client.ListRepositories(ctx, "acme", 1); err != nil || len(repos) != 1 {
+		t.Errorf("limited listing = %+v, %v; want one repository", repos, err)
+	}
+
+	repos, err = client.ListRepositories(ctx, "alice", 0)
+	if err != nil || len(repos) != 1 || repos[0].Name != "dotfiles" {
+		t.Errorf("user repositories = %+v, %v; want dotfiles", repos, err)
+	}
+}
+
+func TestGitLabDiscovery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/user":
+			_, _ = w.Write([]byte(`{"username":"bob"}`))
+		case "/api/v4/projects":
+			if r.URL.Query().Get("membership") != "true" {
+				t.Errorf("projects listed without membership filter: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[{"path":"tools","namespace":{"full_path":"bob"}}]`))
+		case "/api/v4/groups/platform%2Finfra/projects":
+			if r.URL.Query().Get("include_subgroups") != "true" {
+				t.Errorf("group projects listed without subgroups: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[{"path":"deploy","namespace":{"full_path":"platform/infra/ci"},"visibility":"private","default_branch":"main"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewGitLabClient(Config{BaseURL: srv.URL, Token: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if login, err := client.CurrentUser(ctx); err != nil || login != "bob" {
+		t.Errorf("CurrentUser = %q, %v; want bob", login, err)
+	}
+
+	repos, err := client.ListRepositories(ctx, "platform/infra", 0)
+	if err != nil {
+		t.Fatalf("ListRepositories(group): %v", err)
+	}
+	if len(repos) != 1 || repos[0] != (RepositorySummary{Owner: "platform/infra/ci", Name: "deploy", DefaultBranch: "main", Private: true}) {
+		t.Errorf("group projects = %+v", repos)
+	}
+
+	repos, err = client.ListRepositories(ctx, "", 0)
+	if err != nil || len(repos) != 1 || repos[0].Owner != "bob" || repos[0].Name != "tools" {
+		t.Errorf("member projects = %+v, %v", repos, err)
+	}
+
+	if _, err := client.ListRepositories(ctx, "nobody", 0); err == nil {
+		t.Error("expected an error for an unknown group and user")
+	}
+}
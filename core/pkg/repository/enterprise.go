@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultEnterpriseAPIPrefix is the path of the REST API below a GitHub
+// Enterprise Server's base URL, used when Config.APIPrefix is empty
+const DefaultEnterpriseAPIPrefix = "/api/v3"
+
+// githubWebURL is the web address of public GitHub
+const githubWebURL = "https://github.com"
+
+// apiVersionHeader selects the REST API version of a GitHub request. Servers
+// predating API versioning reject versions they do not know.
+const apiVersionHeader = "X-GitHub-Api-Version"
+
+// enterpriseVersionHeader names the release of the GitHub Enterprise Server
+// answering a request
+const enterpriseVersionHeader = "X-GitHub-Enterprise-Version"
+
+// githubEnterprise reports whether c points the GitHub client at a GitHub
+// Enterprise Server: Enterprise is set, or BaseURL names a host other than
+// github.com
+func (c Config) githubEnterprise() bool {
+	if c.Enterprise {
+		return true
+	}
+	if c.BaseURL == "" {
+		return false
+	}
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host != "github.com" && host != "api.github.com"
+}
+
+// enterpriseURLs returns the REST API URL of the GitHub Enterprise Server at
+// c.BaseURL, ending in the API prefix (c.APIPrefix, DefaultEnterpriseAPIPrefix
+// by default; a BaseURL already ending in it is kept), and the web URL
+// repository pages live under
+func (c Config) enterpriseURLs() (api, web *url.URL, err error) {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	prefix := "/" + strings.Trim(c.APIPrefix, "/")
+	if prefix == "/" {
+		prefix = DefaultEnterpriseAPIPrefix
+	}
+	root := strings.TrimSuffix(base.Path, "/")
+	root = strings.TrimSuffix(root, prefix)
+
+	api, web = new(url.URL), new(url.URL)
+	*api, *web = *base, *base
+	api.Path = root + prefix + "/"
+	api.RawPath = ""
+	web.Path = root
+	web.RawPath = ""
+	return api, web, nil
+}
+
+// apiVersionTransport negotiates the REST API version with a GitHub
+// Enterprise Server: requests carry the client library's
+// X-GitHub-Api-Version until the server rejects it as unknown, after which
+// the request is retried, and later ones are sent, without it, getting the
+// server's default version. It logs the server release once.
+type apiVersionTransport struct {
+	base        http.RoundTripper
+	unversioned atomic.Bool
+	logVersion  sync.Once
+}
+
+func (t *apiVersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.unversioned.Load() && req.Header.Get(apiVersionHeader) != "" {
+		req = withoutAPIVersion(req)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if version := resp.Header.Get(enterpriseVersionHeader); version != "" {
+		t.logVersion.Do(func() {
+			slog.Debug("Connected to GitHub Enterprise Server", "host", req.URL.Host, "version", version)
+		})
+	}
+	if resp.StatusCode != http.StatusBadRequest || req.Header.Get(apiVersionHeader) == "" {
+		return resp, nil
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil || !rejectsAPIVersion(body) || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	slog.Info("GitHub Enterprise Server does not support the requested API version; using its default",
+		"host", req.URL.Host,
+		"version", req.Header.Get(apiVersionHeader))
+	t.unversioned.Store(true)
+	retry := withoutAPIVersion(req)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(retry)
+}
+
+// withoutAPIVersion returns a copy of req without the API version header
+func withoutAPIVersion(req *http.Request) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Del(apiVersionHeader)
+	return clone
+}
+
+// rejectsAPIVersion reports whether a 400 response body rejects the
+// requested API version
+func rejectsAPIVersion(body []byte) bool {
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, strings.ToLower(apiVersionHeader)) || strings.Contains(msg, "api version")
+}
+
+// recursiveTreeUnsupported reports whether a recursive git tree request
+// failed in a way older or restricted GitHub Enterprise Servers answer
+// endpoints they do not offer with
+func recursiveTreeUnsupported(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
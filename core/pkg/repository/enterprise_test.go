@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConfigEnterpriseURLs(t *testing.T) {
+	for _, tt := range []struct {
+		baseURL, prefix string
+		api, web        string
+	}{
+		{"https://ghe.example.com", "", "https://ghe.example.com/api/v3/", "https://ghe.example.com"},
+		{"https://ghe.example.com/api/v3/", "", "https://ghe.example.com/api/v3/", "https://ghe.example.com"},
+		{"https://example.com/github", "rest/", "https://example.com/github/rest/", "https://example.com/github"},
+	} {
+		api, web, err := Config{BaseURL: tt.baseURL, APIPrefix: tt.prefix}.enterpriseURLs()
+		if err != nil || api.String() != tt.api || web.String() != tt.web {
+			t.Errorf("enterpriseURLs(%s, %q) = %v, %v, %v; want %s, %s", tt.baseURL, tt.prefix, api, web, err, tt.api, tt.web)
+		}
+	}
+
+	for baseURL, want := range map[string]bool{"": false, "https://api.github.com/": false, "https://ghe.example.com": true} {
+		if got := (Config{BaseURL: baseURL}).githubEnterprise(); got != want {
+			t.Errorf("githubEnterprise(%q) = %v, want %v", baseURL, got, want)
+		}
+	}
+}
+
+func TestGitHubEnterpriseNegotiation(t *testing.T) {
+	var versioned, recursive atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-GitHub-Api-Version") != "" {
+			versioned.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"Unsupported 'X-GitHub-Api-Version' header"}`))
+			return
+		}
+		if r.URL.Query().Get("recursive") != "" {
+			recursive.Add(1)
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-GitHub-Enterprise-Version", "3.4.0")
+		switch r.URL.Path {
+		case "/github/rest/repos/acme/api/git/trees/main":
+			_, _ = w.Write([]byte(`{"sha":"main","tree":[{"path":"poetry.lock","type":"blob","sha":"a"},{"path":"svc","type":"tree","sha":"s"}]}`))
+		case "/github/rest/repos/acme/api/git/trees/s":
+			_, _ = w.Write([]byte(`{"sha":"s","tree":[{"path":"uv.lock","type":"blob","sha":"b"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewGitHubClient(Config{BaseURL: srv.URL + "/github", APIPrefix: "/rest", Enterprise: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := client.ListFilesRecursive(context.Background(), "acme", "api", "main")
+	if err != nil {
+		t.Fatalf("ListFilesRecursive() error = %v", err)
+	}
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	if !slices.Equal(paths, []string{"poetry.lock", "svc/uv.lock"}) {
+		t.Errorf("paths = %v, want the tree listed level by level", paths)
+	}
+	if want := srv.URL + "/github/acme/api/blob/main/poetry.lock"; files[0].URL != want {
+		t.Errorf("URL = %q, want %q", files[0].URL, want)
+	}
+	if versioned.Load() != 1 {
+		t.Errorf("%d versioned requests, want the version dropped after the first rejection", versioned.Load())
+	}
+	if recursive.Load() != 1 {
+		t.Errorf("%d recursive tree requests, want recursion given up after the first refusal", recursive.Load())
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v57/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// StatusError is a provider API failure with an HTTP status, for providers
+// without an SDK error type of their own (those added with Register, the
+// in-memory provider). IsProviderError and StatusCode recognize it, so
+// reports classify it like GitHub and GitLab failures.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// IsProviderError reports whether err originates from a provider API call:
+// HTTP error responses (including authentication failures), rate limiting,
+// or network-level failures reaching the provider.
+func IsProviderError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var (
+		ghErr   *github.ErrorResponse
+		ghRate  *github.RateLimitError
+		ghAbuse *github.AbuseRateLimitError
+		glErr   *gitlab.ErrorResponse
+		urlErr  *url.Error
+		stErr   *StatusError
+	)
+	return errors.As(err, &ghErr) ||
+		errors.As(err, &stErr) ||
+		errors.As(err, &ghRate) ||
+		errors.As(err, &ghAbuse) ||
+		errors.As(err, &glErr) ||
+		errors.As(err, &urlErr)
+}
+
+// StatusCode returns the HTTP status code of the provider response that caused
+// err, or 0 if err does not carry one.
+func StatusCode(err error) int {
+	var ghErr *github.ErrorResponse
+	if errors.As(err, &ghErr) && ghErr.Response != nil {
+		return ghErr.Response.StatusCode
+	}
+	var ghRate *github.RateLimitError
+	if errors.As(err, &ghRate) && ghRate.Response != nil {
+		return ghRate.Response.StatusCode
+	}
+	var ghAbuse *github.AbuseRateLimitError
+	if errors.As(err, &ghAbuse) && ghAbuse.Response != nil {
+		return ghAbuse.Response.StatusCode
+	}
+	var glErr *gitlab.ErrorResponse
+	if errors.As(err, &glErr) && glErr.Response != nil {
+		return glErr.Response.StatusCode
+	}
+	var stErr *StatusError
+	if errors.As(err, &stErr) {
+		return stErr.StatusCode
+	}
+	return 0
+}
+
+// IsRateLimitError reports whether err indicates provider rate limiting
+// (GitHub primary/secondary limits or HTTP 429 from any provider).
+func IsRateLimitError(err error) bool {
+	var (
+		ghRate  *github.RateLimitError
+		ghAbuse *github.AbuseRateLimitError
+	)
+	if errors.As(err, &ghRate) || errors.As(err, &ghAbuse) {
+		return true
+	}
+	return StatusCode(err) == http.StatusTooManyRequests
+}
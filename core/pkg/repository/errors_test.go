@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestIsProviderError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("parse failure"), false},
+		{"github response", &github.ErrorResponse{Response: &http.Response{StatusCode: 401}}, true},
+		{"github rate limit", fmt.Errorf("list: %w", &github.RateLimitError{}), true},
+		{"gitlab response", fmt.Errorf("get: %w", &gitlab.ErrorResponse{}), true},
+		{"network", &url.Error{Op: "Get", URL: "https://example.com", Err: errors.New("refused")}, true},
+		{"status", fmt.Errorf("get: %w", &StatusError{StatusCode: 404, Message: "no such repository"}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsProviderError(tt.err); got != tt.want {
+				t.Errorf("IsProviderError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusCodeAndRateLimit(t *testing.T) {
+	gh404 := &github.ErrorResponse{Response: &http.Response{StatusCode: 404}}
+	gl429 := fmt.Errorf("wrapped: %w", &gitlab.ErrorResponse{Response: &http.Response{StatusCode: 429}})
+
+	if got := StatusCode(gh404); got != 404 {
+		t.Errorf("StatusCode(github 404) = %d", got)
+	}
+	if got := StatusCode(gl429); got != 429 {
+		t.Errorf("StatusCode(gitlab 429) = %d", got)
+	}
+	if got := StatusCode(&StatusError{StatusCode: 403}); got != 403 {
+		t.Errorf("StatusCode(StatusError 403) = %d", got)
+	}
+	if got := StatusCode(errors.New("plain")); got != 0 {
+		t.Errorf("StatusCode(plain) = %d, want 0", got)
+	}
+
+	if IsRateLimitError(gh404) {
+		t.Error("404 should not be a rate limit error")
+	}
+	if !IsRateLimitError(gl429) {
+		t.Error("429 should be a rate limit error")
+	}
+	if !IsRateLimitError(&github.RateLimitError{}) {
+		t.Error("github.RateLimitError should be a rate limit error")
+	}
+}
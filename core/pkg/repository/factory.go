@@ -1,10 +1,5 @@
 package repository
 
-import (
-	"fmt"
-	"strings"
-)
-
 // ProviderType represents the type of repository provider
 type ProviderType string
 
@@ -32,20 +27,11 @@ func NewFactory(config Config) *Factory {
 // The provider parameter is case-insensitive and supports the following values:
 //   - "github" or "GitHub" - Creates a GitHub client
 //   - "gitlab" or "GitLab" - Creates a GitLab client
+//   - any name added with Register
 //
 // Returns an error if the provider name is not recognized or client creation fails
 func (f *Factory) CreateClient(provider string) (Client, error) {
-	// Normalize provider name to lowercase for comparison
-	normalizedProvider := strings.ToLower(strings.TrimSpace(provider))
-
-	switch ProviderType(normalizedProvider) {
-	case ProviderGitHub:
-		return NewGitHubClient(f.config)
-	case ProviderGitLab:
-		return NewGitLabClient(f.config)
-	default:
-		return nil, fmt.Errorf("unsupported provider: %s (supported: github, gitlab)", provider)
-	}
+	return create(provider, f.config)
 }
 
 // NewClient is a convenience function that creates a repository client
@@ -56,10 +42,8 @@ func NewClient(provider string, config Config) (Client, error) {
 	return factory.CreateClient(provider)
 }
 
-// SupportedProviders returns a list of all supported provider types
+// SupportedProviders returns a list of all supported provider types: the
+// built-in ones followed by those added with Register
 func SupportedProviders() []string {
-	return []string{
-		string(ProviderGitHub),
-		string(ProviderGitLab),
-	}
+	return registeredProviders()
 }
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+)
+
+// FixtureMode selects whether Fixtures record or replay provider responses
+type FixtureMode int
+
+const (
+	// FixtureRecord sends requests as usual and saves every response
+	FixtureRecord FixtureMode = iota + 1
+	// FixtureReplay answers requests from saved responses without network
+	// access; a request that was not recorded fails with
+	// *FixtureMissingError
+	FixtureReplay
+)
+
+// credentialParams are query parameters left out of recorded URLs
+var credentialParams = []string{"access_token", "private_token", "job_token", "token"}
+
+// Fixtures records every provider API response of a run to a directory and
+// replays them later, offline, for deterministic tests of the generator and
+// analyzers and for bug reproduction bundles. Each request is stored in its
+// own JSON file named by a hash of its method, URL and body; request headers
+// (and so tokens) and credential query parameters are never stored. Set it on
+// Config.Fixtures; one Fixtures may be shared by many clients.
+type Fixtures struct {
+	Dir  string
+	Mode FixtureMode
+
+	mu       sync.Mutex
+	recorded int
+}
+
+// RecordFixtures creates dir if needed and returns Fixtures recording into it.
+// Responses already in dir are overwritten when their request is sent again.
+func RecordFixtures(dir string) (*Fixtures, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	return &Fixtures{Dir: dir, Mode: FixtureRecord}, nil
+}
+
+// ReplayFixtures returns Fixtures replaying the responses recorded in dir
+func ReplayFixtures(dir string) (*Fixtures, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %s is not a directory", dir)
+	}
+	return &Fixtures{Dir: dir, Mode: FixtureReplay}, nil
+}
+
+// Recorded returns the number of responses saved so far
+func (f *Fixtures) Recorded() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.recorded
+}
+
+// FixtureMissingError reports a replayed request with no recorded response
+type FixtureMissingError struct {
+	Method string
+	URL    string
+	Dir    string
+}
+
+func (e *FixtureMissingError) Error() string {
+	return fmt.Sprintf("no recorded response for %s %s in %s", e.Method, e.URL, e.Dir)
+}
+
+// fixture is the file format of one recorded exchange
+type fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"requestBody,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+	BodyBase64  bool        `json:"bodyBase64,omitempty"` // Body is base64 (not UTF-8 text)
+}
+
+// fixtureURL returns u without credentials or fragment and with its query
+// parameters sorted, so equivalent requests share a fixture
+func fixtureURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	c.Fragment = ""
+	q := c.Query()
+	for _, p := range credentialParams {
+		q.Del(p)
+	}
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+// path returns the file holding the response to a request
+func (f *Fixtures) path(method, u string, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n", method, u)
+	_, _ = h.Write(body)
+	return filepath.Join(f.Dir, hex.EncodeToString(h.Sum(nil))[:16]+".json")
+}
+
+// save writes one exchange
+func (f *Fixtures) save(path string, fx fixture) error {
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to record fixture: %w", err)
+	}
+	f.recorded++
+	return nil
+}
+
+// load reads the response to req from path
+func (f *Fixtures) load(req *http.Request, path, u string) (*http.Response, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &FixtureMissingError{Method: req.Method, URL: u, Dir: f.Dir}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var fx fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	body := []byte(fx.Body)
+	if fx.BodyBase64 {
+		if body, err = base64.StdEncoding.DecodeString(fx.Body); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+	}
+	header := fx.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fx.Status, http.StatusText(fx.Status)),
+		StatusCode:    fx.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// fixtureTransport records the responses base returns, or replays them
+// without calling base
+type fixtureTransport struct {
+	base     http.RoundTripper
+	fixtures *Fixtures
+}
+
+// RoundTrip implements http.RoundTripper. Recorded responses are read whole,
+// so the file size limit applies only after recording.
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	u := fixtureURL(req.URL)
+	path := t.fixtures.path(req.Method, u, reqBody)
+	if t.fixtures.Mode == FixtureReplay {
+		return t.fixtures.load(req, path, u)
+	}
+
+	// Transport errors are not recorded; replaying them reports the request
+	// as missing instead
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fx := fixture{
+		Method:      req.Method,
+		URL:         u,
+		RequestBody: string(reqBody),
+		Status:      resp.StatusCode,
+		Header:      redactHeaders(resp.Header),
+		Body:        string(body),
+	}
+	if !utf8.Valid(body) {
+		fx.Body, fx.BodyBase64 = base64.StdEncoding.EncodeToString(body), true
+	}
+	if err := t.fixtures.save(path, fx); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
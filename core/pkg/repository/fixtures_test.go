@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFixtures_RecordThenReplay(t *testing.T) {
+	var srvURL string
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		switch r.URL.Path {
+		case "/api/v3/repos/org/api/branches":
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[{"name":"release/1.x","commit":{"sha":"bbb"}}]`))
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/org/api/branches?page=2>; rel="next"`, srvURL))
+			_, _ = w.Write([]byte(`[{"name":"main","commit":{"sha":"aaa"}}]`))
+		case "/api/v3/repos/org/api/tags":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	srvURL = srv.URL
+	dir := t.TempDir()
+
+	recorder, err := RecordFixtures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewGitHubClient(Config{BaseURL: srv.URL + "/", Token: "ghp_secret", Fixtures: recorder})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := client.ListRefs(context.Background(), "org", "api")
+	if err != nil {
+		t.Fatalf("ListRefs while recording: %v", err)
+	}
+	if recorder.Recorded() != requests || requests != 3 {
+		t.Errorf("recorded %d of %d requests, want 3", recorder.Recorded(), requests)
+	}
+	srv.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "ghp_secret") || strings.Contains(string(data), "session=abc") {
+			t.Errorf("%s stores a credential:\n%s", filepath.Base(f), data)
+		}
+	}
+
+	replayer, err := ReplayFixtures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = NewGitHubClient(Config{BaseURL: srv.URL + "/", Fixtures: replayer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := client.ListRefs(context.Background(), "org", "api")
+	if err != nil {
+		t.Fatalf("ListRefs while replaying: %v", err)
+	}
+	if !slices.Equal(replayed, recorded) {
+		t.Errorf("replayed refs = %+v, want the recorded %+v", replayed, recorded)
+	}
+	if requests != 3 {
+		t.Errorf("replay reached the server (%d requests)", requests)
+	}
+
+	_, err = client.GetRepositoryInfo(context.Background(), "org", "other")
+	var missing *FixtureMissingError
+	if !errors.As(err, &missing) || !strings.HasSuffix(missing.URL, "/api/v3/repos/org/other") {
+		t.Errorf("unrecorded request error = %v, want a *FixtureMissingError naming it", err)
+	}
+}
+
+func TestFixtureURL_DropsCredentials(t *testing.T) {
+	u, err := url.Parse("https://user:pw@gitlab.example.com/api/v4/projects?private_token=x&page=2&access_token=y&per_page=100#frag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fixtureURL(u)
+	want := "https://gitlab.example.com/api/v4/projects?page=2&per_page=100"
+	if got != want {
+		t.Errorf("fixtureURL = %q, want %q", got, want)
+	}
+}
+
+func TestReplayFixtures_RequiresDirectory(t *testing.T) {
+	if _, err := ReplayFixtures(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ReplayFixtures accepted a missing directory")
+	}
+}
@@ -4,9 +4,15 @@
 package repository
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"sync/atomic"
 
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
@@ -16,6 +22,11 @@ import (
 type GitHubClient struct {
 	api    GitHubAPI
 	config Config
+	webURL string // Web address repository pages live under
+
+	// flatTrees is set once a GitHub Enterprise Server refused a recursive
+	// git tree listing; trees are then listed one level at a time
+	flatTrees atomic.Bool
 }
 
 // NewGitHubClient creates a new GitHub client with the provided configuration
@@ -26,29 +37,55 @@ func NewGitHubClient(config Config) (*GitHubClient, error) {
 
 	ctx := context.Background()
 
+	// Retry transient failures and enforce request budgets below the
+	// authentication layer if configured
+	httpClient, err := apiHTTPClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GitHub HTTP client: %w", err)
+	}
+	enterprise := config.githubEnterprise()
+	if enterprise {
+		transport := http.DefaultTransport
+		if httpClient != nil {
+			transport = httpClient.Transport
+		}
+		httpClient = &http.Client{Transport: &apiVersionTransport{base: transport}}
+	}
+
 	// Configure authentication if token is provided
 	if config.Token != "" {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: config.Token},
 		)
+		if httpClient != nil {
+			ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+		}
 		tc := oauth2.NewClient(ctx, ts)
 		client = github.NewClient(tc)
 	} else {
-		client = github.NewClient(nil)
+		client = github.NewClient(httpClient)
 	}
 
 	// Set custom base URL for GitHub Enterprise if provided
+	webURL := githubWebURL
 	if config.BaseURL != "" {
-		var err error
 		client, err = client.WithEnterpriseURLs(config.BaseURL, config.BaseURL)
 		if err != nil {
 			return nil, fmt.Errorf("failed to set GitHub Enterprise URL: %w", err)
 		}
 	}
+	if enterprise && config.BaseURL != "" {
+		api, web, err := config.enterpriseURLs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to set GitHub Enterprise URL: %w", err)
+		}
+		client.BaseURL, webURL = api, web.String()
+	}
 
 	return &GitHubClient{
 		api:    wrapGitHubClient(client),
 		config: config,
+		webURL: webURL,
 	}, nil
 }
 
@@ -112,6 +149,7 @@ func (g *GitHubClient) GetRepositoryInfo(ctx context.Context, owner, repo string
 		Description:   ghRepo.GetDescription(),
 		DefaultBranch: ghRepo.GetDefaultBranch(),
 		URL:           ghRepo.GetHTMLURL(),
+		CloneURL:      ghRepo.GetCloneURL(),
 	}
 
 	return repoInfo, nil
@@ -120,49 +158,90 @@ func (g *GitHubClient) GetRepositoryInfo(ctx context.Context, owner, repo string
 // ListFilesRecursive retrieves all files recursively in a repository
 // This traverses the entire repository tree and returns only files (not directories)
 func (g *GitHubClient) ListFilesRecursive(ctx context.Context, owner, repo, ref string) ([]FileInfo, error) {
-	// Use default branch if ref is not specified
-	refToUse := ref
-	if refToUse == "" {
-		repoInfo, err := g.GetRepositoryInfo(ctx, owner, repo)
+	refToUse, err := g.refOrDefault(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the Git tree recursively: one call for all but the largest
+	// repositories, instead of one contents call per directory
+	entries, err := g.treeEntries(ctx, owner, repo, refToUse, "")
+	if err != nil {
+		return nil, err
+	}
+	return g.treeFiles(owner, repo, refToUse, entries), nil
+}
+
+// ListFilesUnder retrieves all files below the prefix directory. The
+// directory's tree is looked up in its parent's listing and only that tree is
+// expanded, so deep monorepos are not traversed in full.
+func (g *GitHubClient) ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]FileInfo, error) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return g.ListFilesRecursive(ctx, owner, repo, ref)
+	}
+	refToUse, err := g.refOrDefault(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := path.Dir(prefix)
+	if parent == "." {
+		parent = ""
+	}
+	siblings, err := g.ListFiles(ctx, owner, repo, refToUse, parent)
+	if err != nil {
+		if StatusCode(err) == http.StatusNotFound {
+			return []FileInfo{}, nil
+		}
+		return nil, err
+	}
+	for _, entry := range siblings {
+		if entry.Path != prefix || entry.Type != "dir" {
+			continue
+		}
+		entries, err := g.treeEntries(ctx, owner, repo, entry.SHA, prefix+"/")
 		if err != nil {
-			return nil, fmt.Errorf("failed to get default branch: %w", err)
+			return nil, err
 		}
-		refToUse = repoInfo.DefaultBranch
+		return g.treeFiles(owner, repo, refToUse, entries), nil
 	}
+	return []FileInfo{}, nil
+}
 
-	// Get the Git tree recursively
-	// This is more efficient than manually traversing directory by directory
-	tree, resp, err := g.api.Git.GetTree(ctx, owner, repo, refToUse, true)
+// refOrDefault returns ref, or the repository's default branch when ref is empty
+func (g *GitHubClient) refOrDefault(ctx context.Context, owner, repo, ref string) (string, error) {
+	if ref != "" {
+		return ref, nil
+	}
+	repoInfo, err := g.GetRepositoryInfo(ctx, owner, repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get repository tree from GitHub: %w", err)
+		return "", fmt.Errorf("failed to get default branch: %w", err)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			slog.Warn("Failed to close response body", "error", closeErr)
-		}
-	}()
+	return repoInfo.DefaultBranch, nil
+}
 
-	// Filter out directories and convert to FileInfo
+// treeFiles converts the blob entries of a git tree to FileInfo, skipping
+// trees (directories) and other types
+func (g *GitHubClient) treeFiles(owner, repo, ref string, entries []*github.TreeEntry) []FileInfo {
 	files := make([]FileInfo, 0)
-	for _, entry := range tree.Entries {
-		// Only include files (blobs), skip trees (directories) and other types
-		if entry.GetType() == "blob" {
-			fileInfo := FileInfo{
-				Path: entry.GetPath(),
-				Name: extractFileName(entry.GetPath()),
-				Type: "file",
-				Size: int64(entry.GetSize()),
-				SHA:  entry.GetSHA(),
-				Mode: entry.GetMode(),
-				// Note: URL is not directly available in tree entries
-				// Would need additional API call per file to get HTML URL
-				URL: fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", owner, repo, refToUse, entry.GetPath()),
-			}
-			files = append(files, fileInfo)
+	for _, entry := range entries {
+		if entry.GetType() != "blob" {
+			continue
 		}
+		files = append(files, FileInfo{
+			Path: entry.GetPath(),
+			Name: extractFileName(entry.GetPath()),
+			Type: "file",
+			Size: int64(entry.GetSize()),
+			SHA:  entry.GetSHA(),
+			Mode: entry.GetMode(),
+			// Note: URL is not directly available in tree entries
+			// Would need additional API call per file to get HTML URL
+			URL: fmt.Sprintf("%s/%s/%s/blob/%s/%s", cmp.Or(g.webURL, githubWebURL), owner, repo, ref, entry.GetPath()),
+		})
 	}
-
-	return files, nil
+	return files
 }
 
 // extractFileName extracts the filename from a full path
@@ -176,35 +255,213 @@ func extractFileName(path string) string {
 	return path
 }
 
-// GetFileContent retrieves the content of a specific file from a GitHub repository
+// treeEntries lists every entry below the tree-ish sha, with paths prefixed
+// by prefix. GitHub truncates recursive listings of very large trees; those
+// are split into one recursive listing per subdirectory instead. On a GitHub
+// Enterprise Server refusing recursive listings every level is listed
+// separately.
+func (g *GitHubClient) treeEntries(ctx context.Context, owner, repo, sha, prefix string) ([]*github.TreeEntry, error) {
+	var recursiveErr error
+	if !g.flatTrees.Load() {
+		tree, err := g.getTree(ctx, owner, repo, sha, true)
+		switch {
+		case err == nil && !tree.GetTruncated():
+			return prefixEntries(tree.Entries, prefix), nil
+		case err == nil:
+			slog.Debug("Git tree truncated; listing subdirectories separately",
+				"owner", owner,
+				"repo", repo,
+				"path", prefix)
+		case g.config.githubEnterprise() && recursiveTreeUnsupported(err):
+			recursiveErr = err
+		default:
+			return nil, err
+		}
+	}
+
+	tree, err := g.getTree(ctx, owner, repo, sha, false)
+	if err != nil {
+		return nil, err
+	}
+	if recursiveErr != nil {
+		slog.Info("GitHub Enterprise Server refused a recursive tree listing; listing trees level by level",
+			"owner", owner,
+			"repo", repo,
+			"error", recursiveErr)
+		g.flatTrees.Store(true)
+	}
+	entries := make([]*github.TreeEntry, 0, len(tree.Entries))
+	for _, entry := range prefixEntries(tree.Entries, prefix) {
+		entries = append(entries, entry)
+		if entry.GetType() != "tree" {
+			continue
+		}
+		sub, err := g.treeEntries(ctx, owner, repo, entry.GetSHA(), entry.GetPath()+"/")
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, sub...)
+	}
+	return entries, nil
+}
+
+func (g *GitHubClient) getTree(ctx context.Context, owner, repo, sha string, recursive bool) (*github.Tree, error) {
+	tree, resp, err := g.api.Git.GetTree(ctx, owner, repo, sha, recursive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository tree from GitHub: %w", err)
+	}
+	if resp != nil && resp.Body != nil {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn("Failed to close response body", "error", closeErr)
+		}
+	}
+	return tree, nil
+}
+
+// prefixEntries returns copies of entries with prefix prepended to each path
+func prefixEntries(entries []*github.TreeEntry, prefix string) []*github.TreeEntry {
+	if prefix == "" {
+		return entries
+	}
+	out := make([]*github.TreeEntry, len(entries))
+	for i, entry := range entries {
+		e := *entry
+		e.Path = github.String(prefix + entry.GetPath())
+		out[i] = &e
+	}
+	return out
+}
+
+// GetFileContent retrieves the content of a specific file from GitHub
 func (g *GitHubClient) GetFileContent(ctx context.Context, owner, repo, ref, path string) (string, error) {
+	rc, err := g.OpenFileContent(ctx, owner, repo, ref, path)
+	if err != nil {
+		return "", err
+	}
+	return readContent(rc)
+}
+
+// OpenFileContent streams a file from GitHub. Files up to 1 MB arrive inline
+// with their metadata; larger ones are downloaded from their raw URL. Files
+// over the size limit are rejected before the download starts.
+func (g *GitHubClient) OpenFileContent(ctx context.Context, owner, repo, ref, path string) (io.ReadCloser, error) {
 	// Use default branch if ref is not specified
 	opts := &github.RepositoryContentGetOptions{}
 	if ref != "" {
 		opts.Ref = ref
 	}
 
-	// Get file content from GitHub API
+	// Get file metadata (and content, when small enough) from GitHub API
 	fileContent, _, resp, err := g.api.Repositories.GetContents(ctx, owner, repo, path, opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to get file content from GitHub: %w", err)
+		return nil, fmt.Errorf("failed to get file content from GitHub: %w", err)
+	}
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		slog.Warn("Failed to close response body", "error", closeErr)
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			slog.Warn("Failed to close response body", "error", closeErr)
-		}
-	}()
 
 	// Check if we got a file (not a directory)
 	if fileContent == nil {
-		return "", fmt.Errorf("path is not a file: %s", path)
+		return nil, fmt.Errorf("path is not a file: %s", path)
+	}
+	limit := g.config.fileSizeLimit()
+	if err := checkFileSize(path, int64(fileContent.GetSize()), limit); err != nil {
+		return nil, err
+	}
+
+	// Content over 1 MB is not inlined ("none" encoding)
+	if fileContent.GetEncoding() == "none" {
+		body, _, err := g.api.Repositories.DownloadContents(ctx, owner, repo, path, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download file content from GitHub: %w", err)
+		}
+		return limitContent(body, path, limit), nil
 	}
 
 	// Get the content - GitHub API returns base64 encoded content
 	content, err := fileContent.GetContent()
 	if err != nil {
-		return "", fmt.Errorf("failed to decode file content: %w", err)
+		return nil, fmt.Errorf("failed to decode file content: %w", err)
 	}
+	return limitContent(io.NopCloser(strings.NewReader(content)), path, limit), nil
+}
+
+// ListUpdatePullRequests returns open pull requests authored by dependency-update
+// bots (Dependabot, Renovate). Results are paginated transparently.
+func (g *GitHubClient) ListUpdatePullRequests(ctx context.Context, owner, repo string) ([]UpdatePullRequest, error) {
+	if g.api.PullRequests == nil {
+		return nil, fmt.Errorf("pull request listing not available")
+	}
+
+	opts := &github.PullRequestListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var out []UpdatePullRequest
+	for {
+		prs, resp, err := g.api.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests from GitHub: %w", err)
+		}
+		if resp != nil && resp.Body != nil {
+			if closeErr := resp.Body.Close(); closeErr != nil {
+				slog.Warn("Failed to close response body", "error", closeErr)
+			}
+		}
+
+		for _, pr := range prs {
+			author := pr.GetUser().GetLogin()
+			branch := pr.GetHead().GetRef()
+			if !isUpdateBotRequest(author, branch) {
+				continue
+			}
+			out = append(out, UpdatePullRequest{
+				Number:    pr.GetNumber(),
+				Title:     pr.GetTitle(),
+				URL:       pr.GetHTMLURL(),
+				Author:    author,
+				Branch:    branch,
+				Package:   parseUpdatePackage(pr.GetTitle(), branch),
+				CreatedAt: pr.GetCreatedAt().Time,
+			})
+		}
 
-	return content, nil
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return out, nil
+}
+
+// CreatePullRequest opens a pull request from pr.Head into pr.Base
+func (g *GitHubClient) CreatePullRequest(ctx context.Context, owner, repo string, pr NewPullRequest) (UpdatePullRequest, error) {
+	if g.api.PullRequests == nil {
+		return UpdatePullRequest{}, fmt.Errorf("pull request creation not available")
+	}
+	created, resp, err := g.api.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.String(pr.Title),
+		Body:  github.String(pr.Body),
+		Head:  github.String(pr.Head),
+		Base:  github.String(pr.Base),
+	})
+	if err != nil {
+		return UpdatePullRequest{}, fmt.Errorf("failed to create pull request on GitHub: %w", err)
+	}
+	if resp != nil && resp.Body != nil {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn("Failed to close response body", "error", closeErr)
+		}
+	}
+	return UpdatePullRequest{
+		Number:    created.GetNumber(),
+		Title:     created.GetTitle(),
+		URL:       created.GetHTMLURL(),
+		Author:    created.GetUser().GetLogin(),
+		Branch:    pr.Head,
+		Package:   parseUpdatePackage(created.GetTitle(), pr.Head),
+		CreatedAt: created.GetCreatedAt().Time,
+	}, nil
 }
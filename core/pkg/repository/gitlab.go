@@ -5,10 +5,13 @@ package repository
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"path/filepath"
+	"strings"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
@@ -34,6 +37,20 @@ func NewGitLabClient(config Config) (*GitLabClient, error) {
 		opts = append(opts, gitlab.WithBaseURL(config.BaseURL))
 	}
 
+	// Replace the library's built-in retries with the configured policy; with
+	// only budgets configured the library keeps retrying, and every attempt
+	// is charged to the budgets
+	httpClient, err := apiHTTPClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GitLab HTTP client: %w", err)
+	}
+	if httpClient != nil {
+		opts = append(opts, gitlab.WithHTTPClient(httpClient))
+		if retryHTTPClient(config.Retry) != nil {
+			opts = append(opts, gitlab.WithoutRetries())
+		}
+	}
+
 	// Create client with authentication if token is provided
 	if config.Token != "" {
 		client, err = gitlab.NewClient(config.Token, opts...)
@@ -55,7 +72,7 @@ func NewGitLabClient(config Config) (*GitLabClient, error) {
 // This returns the contents of a single directory level
 func (g *GitLabClient) ListFiles(ctx context.Context, owner, repo, ref, path string) ([]FileInfo, error) {
 	// GitLab uses project ID or "namespace/project" format
-	projectID := fmt.Sprintf("%s/%s", owner, repo)
+	projectID := gitlabProjectID(owner, repo)
 
 	// Configure options for listing tree
 	opts := &gitlab.ListTreeOptions{
@@ -117,7 +134,7 @@ func (g *GitLabClient) ListFiles(ctx context.Context, owner, repo, ref, path str
 
 // GetRepositoryInfo retrieves metadata about a GitLab repository
 func (g *GitLabClient) GetRepositoryInfo(ctx context.Context, owner, repo string) (*Info, error) {
-	projectID := fmt.Sprintf("%s/%s", owner, repo)
+	projectID := gitlabProjectID(owner, repo)
 
 	project, resp, err := g.api.Projects.GetProject(projectID, nil, gitlab.WithContext(ctx))
 	if err != nil {
@@ -136,6 +153,7 @@ func (g *GitLabClient) GetRepositoryInfo(ctx context.Context, owner, repo string
 		Description:   project.Description,
 		DefaultBranch: project.DefaultBranch,
 		URL:           project.WebURL,
+		CloneURL:      project.HTTPURLToRepo,
 	}
 
 	return repoInfo, nil
@@ -144,7 +162,19 @@ func (g *GitLabClient) GetRepositoryInfo(ctx context.Context, owner, repo string
 // ListFilesRecursive retrieves all files recursively in a repository
 // This traverses the entire repository tree and returns only files (not directories)
 func (g *GitLabClient) ListFilesRecursive(ctx context.Context, owner, repo, ref string) ([]FileInfo, error) {
-	projectID := fmt.Sprintf("%s/%s", owner, repo)
+	return g.listTree(ctx, owner, repo, ref, "")
+}
+
+// ListFilesUnder retrieves all files below the prefix directory using the
+// tree API's path filter
+func (g *GitLabClient) ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]FileInfo, error) {
+	return g.listTree(ctx, owner, repo, ref, strings.Trim(prefix, "/"))
+}
+
+// listTree lists the files below dir (the whole repository when empty); a
+// missing dir yields no files
+func (g *GitLabClient) listTree(ctx context.Context, owner, repo, ref, dir string) ([]FileInfo, error) {
+	projectID := gitlabProjectID(owner, repo)
 
 	// Use default branch if ref is not specified
 	refToUse := ref
@@ -164,6 +194,9 @@ func (g *GitLabClient) ListFilesRecursive(ctx context.Context, owner, repo, ref
 			PerPage: 100,
 		},
 	}
+	if dir != "" {
+		opts.Path = gitlab.Ptr(dir)
+	}
 
 	allFiles := make([]FileInfo, 0)
 	page := 1
@@ -173,6 +206,9 @@ func (g *GitLabClient) ListFilesRecursive(ctx context.Context, owner, repo, ref
 		opts.Page = page
 
 		trees, resp, err := g.api.Repositories.ListTree(projectID, opts, gitlab.WithContext(ctx))
+		if err != nil && dir != "" && StatusCode(err) == http.StatusNotFound {
+			return []FileInfo{}, nil
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to get repository tree from GitLab: %w", err)
 		}
@@ -215,49 +251,166 @@ func (g *GitLabClient) getProjectURL(owner, repo string) string {
 	if baseURL == "" {
 		baseURL = "https://gitlab.com"
 	}
-	return fmt.Sprintf("%s/%s/%s", baseURL, owner, repo)
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(baseURL, "/"), gitlabProjectID(owner, repo))
+}
+
+// gitlabProjectID returns the full path GitLab identifies a project by. The
+// owner may be a nested namespace (group/subgroup/team); the client library
+// URL-encodes the whole path, slashes included, as one path segment of API
+// requests (projects/group%2Fsubgroup%2Fteam%2Fapi).
+func gitlabProjectID(owner, repo string) string {
+	return strings.Trim(owner, "/") + "/" + strings.Trim(repo, "/")
 }
 
 // GetFileContent retrieves the content of a specific file from a GitLab repository
 func (g *GitLabClient) GetFileContent(ctx context.Context, owner, repo, ref, path string) (string, error) {
-	projectID := fmt.Sprintf("%s/%s", owner, repo)
+	rc, err := g.OpenFileContent(ctx, owner, repo, ref, path)
+	if err != nil {
+		return "", err
+	}
+	return readContent(rc)
+}
+
+// OpenFileContent streams a file's raw content from GitLab as it downloads.
+// Request failures, including the size limit, surface from Read.
+func (g *GitLabClient) OpenFileContent(ctx context.Context, owner, repo, ref, path string) (io.ReadCloser, error) {
+	projectID := gitlabProjectID(owner, repo)
 
 	// Use default branch if ref is not specified
 	refToUse := ref
 	if refToUse == "" {
 		repoInfo, err := g.GetRepositoryInfo(ctx, owner, repo)
 		if err != nil {
-			return "", fmt.Errorf("failed to get default branch: %w", err)
+			return nil, fmt.Errorf("failed to get default branch: %w", err)
 		}
 		refToUse = repoInfo.DefaultBranch
 	}
 
-	// Get file content from GitLab API
-	opts := &gitlab.GetFileOptions{
+	opts := &gitlab.GetRawFileOptions{
 		Ref: gitlab.Ptr(refToUse),
 	}
+	// Cancelling stops the download when the reader is closed early or the
+	// limit is crossed; otherwise the client drains the rest of the body
+	streamCtx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	go func() {
+		dst := &limitedWriter{w: pw, path: path, limit: g.config.fileSizeLimit(), exceeded: cancel}
+		_, err := g.api.RepositoryFiles.StreamRawFile(projectID, path, opts, dst, gitlab.WithContext(streamCtx))
+		var tooLarge *FileTooLargeError
+		switch {
+		case errors.As(err, &tooLarge):
+			err = tooLarge
+		case err != nil:
+			err = fmt.Errorf("failed to get file content from GitLab: %w", err)
+		}
+		pw.CloseWithError(err)
+	}()
+	return &pipeContent{PipeReader: pr, cancel: cancel}, nil
+}
 
-	file, resp, err := g.api.RepositoryFiles.GetFile(projectID, path, opts, gitlab.WithContext(ctx))
-	if err != nil {
-		return "", fmt.Errorf("failed to get file content from GitLab: %w", err)
+// pipeContent is a streamed response body whose Close also aborts the request
+type pipeContent struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (p *pipeContent) Close() error {
+	p.cancel()
+	return p.PipeReader.Close()
+}
+
+// ListUpdatePullRequests returns open merge requests authored by dependency-update
+// bots (Dependabot, Renovate). Results are paginated transparently.
+func (g *GitLabClient) ListUpdatePullRequests(ctx context.Context, owner, repo string) ([]UpdatePullRequest, error) {
+	if g.api.MergeRequests == nil {
+		return nil, fmt.Errorf("merge request listing not available")
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			slog.Warn("Failed to close response body", "error", closeErr)
+	projectID := gitlabProjectID(owner, repo)
+
+	opts := &gitlab.ListProjectMergeRequestsOptions{
+		State: gitlab.Ptr("opened"),
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+			Page:    1,
+		},
+	}
+
+	var out []UpdatePullRequest
+	for {
+		mrs, resp, err := g.api.MergeRequests.ListProjectMergeRequests(projectID, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge requests from GitLab: %w", err)
+		}
+		if resp != nil && resp.Body != nil {
+			if closeErr := resp.Body.Close(); closeErr != nil {
+				slog.Warn("Failed to close response body", "error", closeErr)
+			}
+		}
+
+		for _, mr := range mrs {
+			author := ""
+			if mr.Author != nil {
+				author = mr.Author.Username
+			}
+			if !isUpdateBotRequest(author, mr.SourceBranch) {
+				continue
+			}
+			pr := UpdatePullRequest{
+				Number:  mr.IID,
+				Title:   mr.Title,
+				URL:     mr.WebURL,
+				Author:  author,
+				Branch:  mr.SourceBranch,
+				Package: parseUpdatePackage(mr.Title, mr.SourceBranch),
+			}
+			if mr.CreatedAt != nil {
+				pr.CreatedAt = *mr.CreatedAt
+			}
+			out = append(out, pr)
 		}
-	}()
 
-	// GitLab returns base64 encoded content in the Content field
-	// We need to decode it manually
-	if file.Content == "" {
-		return "", fmt.Errorf("file content is empty: %s", path)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
-	// Decode base64 content
-	decodedContent, err := base64.StdEncoding.DecodeString(file.Content)
+	return out, nil
+}
+
+// CreatePullRequest opens a merge request from pr.Head into pr.Base
+func (g *GitLabClient) CreatePullRequest(ctx context.Context, owner, repo string, pr NewPullRequest) (UpdatePullRequest, error) {
+	if g.api.MergeRequests == nil {
+		return UpdatePullRequest{}, fmt.Errorf("merge request creation not available")
+	}
+	projectID := gitlabProjectID(owner, repo)
+
+	mr, resp, err := g.api.MergeRequests.CreateMergeRequest(projectID, &gitlab.CreateMergeRequestOptions{
+		Title:        gitlab.Ptr(pr.Title),
+		Description:  gitlab.Ptr(pr.Body),
+		SourceBranch: gitlab.Ptr(pr.Head),
+		TargetBranch: gitlab.Ptr(pr.Base),
+	}, gitlab.WithContext(ctx))
 	if err != nil {
-		return "", fmt.Errorf("failed to decode base64 content: %w", err)
+		return UpdatePullRequest{}, fmt.Errorf("failed to create merge request on GitLab: %w", err)
 	}
-
-	return string(decodedContent), nil
+	if resp != nil && resp.Body != nil {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			slog.Warn("Failed to close response body", "error", closeErr)
+		}
+	}
+	out := UpdatePullRequest{
+		Number:  mr.IID,
+		Title:   mr.Title,
+		URL:     mr.WebURL,
+		Branch:  pr.Head,
+		Package: parseUpdatePackage(mr.Title, pr.Head),
+	}
+	if mr.Author != nil {
+		out.Author = mr.Author.Username
+	}
+	if mr.CreatedAt != nil {
+		out.CreatedAt = *mr.CreatedAt
+	}
+	return out, nil
 }
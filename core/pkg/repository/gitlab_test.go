@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
 	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -222,3 +225,34 @@ func TestNewGitHubClientWithoutToken(t *testing.T) {
 		t.Fatal("NewGitHubClient returned nil client")
 	}
 }
+
+func TestGitLabNestedNamespace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/grp%2Fsub%2Fteam%2Fweb%2Eapp":
+			_, _ = w.Write([]byte(`{"name":"web.app","path_with_namespace":"grp/sub/team/web.app","default_branch":"main"}`))
+		case "/api/v4/projects/grp%2Fsub%2Fteam%2Fweb%2Eapp/repository/tree":
+			_, _ = w.Write([]byte(`[{"name":"poetry.lock","path":"poetry.lock","type":"blob"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewGitLabClient(Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := client.GetRepositoryInfo(context.Background(), "grp/sub/team/", "web.app")
+	if err != nil || info.DefaultBranch != "main" {
+		t.Fatalf("GetRepositoryInfo = %+v, %v", info, err)
+	}
+	files, err := client.ListFiles(context.Background(), "grp/sub/team", "web.app", "main", "")
+	if err != nil || len(files) != 1 {
+		t.Fatalf("ListFiles = %+v, %v", files, err)
+	}
+	if want := srv.URL + "/grp/sub/team/web.app/-/blob/main/poetry.lock"; files[0].URL != want {
+		t.Errorf("file URL = %q, want %q", files[0].URL, want)
+	}
+}
@@ -0,0 +1,38 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+// A report over in-memory repositories, as a third-party test would build one
+func Example() {
+	memory.Default.AddRepository("acme", "api", memory.Files{
+		"poetry.lock": "[[package]]\nname = \"django\"\nversion = \"4.2.0\"\n",
+	})
+	memory.Default.AddRepository("acme", "web", memory.Files{
+		"poetry.lock": "[[package]]\nname = \"django\"\nversion = \"5.0.1\"\n",
+	})
+	defer memory.Default.Reset()
+
+	var repos []config.RepoWithProvider
+	for _, name := range []string{"api", "web"} {
+		repos = append(repos, config.RepoWithProvider{Provider: memory.Provider, Config: config.RepoConfig{
+			Owner: "acme", Repository: name, Analyzer: "poetry", Packages: []string{"django"},
+		}})
+	}
+	rpt, err := report.NewGenerator().Generate(context.Background(), repos)
+	if err != nil {
+		panic(err)
+	}
+	for _, rr := range rpt.Repositories {
+		fmt.Println(rr.Owner+"/"+rr.Repository, rr.Dependencies["django"])
+	}
+	// Output:
+	// acme/api 4.2.0
+	// acme/web 5.0.1
+}
@@ -0,0 +1,438 @@
+// Package memory provides a repository provider serving in-memory trees of
+// files, for examples, demos and tests that must not reach GitHub or GitLab.
+// Importing it registers the provider "memory", whose clients serve Default:
+//
+//	memory.Default.AddRepository("acme", "api", memory.Files{
+//		"poetry.lock": lock,
+//	})
+//	client, _ := repository.NewClient("memory", repository.Config{})
+//
+// Clients created with NewClient serve any other Store. They implement the
+// optional repository.CommitResolver, RefLister, RepositoryDiscoverer and
+// ArchiveDownloader capabilities; missing repositories, refs and files fail with a 404
+// *repository.StatusError like a real provider's.
+package memory
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1" // #nosec G505 -- commit IDs, not security
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// Provider is the name the memory provider is registered under
+const Provider = "memory"
+
+// DefaultBranch is the default branch of repositories added to a Store
+const DefaultBranch = "main"
+
+// Default is the store served by clients of the registered "memory" provider
+var Default = NewStore()
+
+func init() {
+	repository.Register(Provider, func(repository.Config) (repository.Client, error) {
+		return NewClient(Default), nil
+	})
+}
+
+// Files maps slash-separated paths to file contents
+type Files map[string]string
+
+// memRepo is one repository of a Store
+type memRepo struct {
+	description string
+	branches    map[string]Files
+	tags        map[string]Files
+	err         error
+}
+
+// Store holds repositories by owner and name. It is safe for concurrent use;
+// changes are visible to existing clients immediately.
+type Store struct {
+	mu    sync.RWMutex
+	repos map[string]*memRepo
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{repos: make(map[string]*memRepo)}
+}
+
+func repoKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
+
+// repo returns owner/name, creating it when create is set; the caller holds
+// the lock
+func (s *Store) repo(owner, name string, create bool) *memRepo {
+	r := s.repos[repoKey(owner, name)]
+	if r == nil && create {
+		r = &memRepo{branches: map[string]Files{DefaultBranch: {}}, tags: map[string]Files{}}
+		s.repos[repoKey(owner, name)] = r
+	}
+	return r
+}
+
+// AddRepository adds (or replaces) owner/name with files on its default
+// branch
+func (s *Store) AddRepository(owner, name string, files Files) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.repos, repoKey(owner, name))
+	s.repo(owner, name, true).branches[DefaultBranch] = cloneFiles(files)
+}
+
+// SetDescription sets the description GetRepositoryInfo reports
+func (s *Store) SetDescription(owner, name, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, name, true).description = description
+}
+
+// SetBranch sets the files of a branch, creating the repository if needed
+func (s *Store) SetBranch(owner, name, branch string, files Files) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, name, true).branches[branch] = cloneFiles(files)
+}
+
+// SetTag sets the files of a tag, creating the repository if needed
+func (s *Store) SetTag(owner, name, tag string, files Files) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, name, true).tags[tag] = cloneFiles(files)
+}
+
+// SetFile sets one file of a branch or tag (the default branch when ref is
+// empty), creating the repository and branch if needed
+func (s *Store) SetFile(owner, name, ref, filePath, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.repo(owner, name, true)
+	if ref == "" {
+		ref = DefaultBranch
+	}
+	// Copy on write: clients read trees without holding the lock
+	refs := r.branches
+	if _, isTag := r.tags[ref]; isTag {
+		refs = r.tags
+	}
+	files := cloneFiles(refs[ref])
+	files[strings.TrimPrefix(filePath, "/")] = content
+	refs[ref] = files
+}
+
+// SetError makes every request for owner/name fail with err (nil clears
+// it), e.g. a *repository.StatusError to demonstrate failures
+func (s *Store) SetError(owner, name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, name, true).err = err
+}
+
+// Remove deletes owner/name
+func (s *Store) Remove(owner, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.repos, repoKey(owner, name))
+}
+
+// Reset deletes every repository
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos = make(map[string]*memRepo)
+}
+
+func cloneFiles(files Files) Files {
+	out := make(Files, len(files))
+	for p, content := range files {
+		out[strings.TrimPrefix(p, "/")] = content
+	}
+	return out
+}
+
+// notFound returns the error a provider reports for a missing resource
+func notFound(format string, args ...any) error {
+	return &repository.StatusError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// commitSHA derives a stable commit ID from a tree's contents, so it changes
+// exactly when the files do
+func commitSHA(files Files) string {
+	h := sha1.New() // #nosec G401 -- commit IDs, not security
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%s", p, len(files[p]), files[p])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// tree returns the files of ref in owner/name: a branch, a tag or the commit
+// ID of either; an empty ref is the default branch
+func (s *Store) tree(owner, name, ref string) (Files, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r := s.repo(owner, name, false)
+	if r == nil {
+		return nil, notFound("repository %s/%s not found", owner, name)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if ref == "" {
+		ref = DefaultBranch
+	}
+	if files, ok := r.branches[ref]; ok {
+		return files, nil
+	}
+	if files, ok := r.tags[ref]; ok {
+		return files, nil
+	}
+	if repository.IsCommitSHA(ref) {
+		for _, refs := range []map[string]Files{r.branches, r.tags} {
+			for _, files := range refs {
+				if strings.HasPrefix(commitSHA(files), strings.ToLower(ref)) {
+					return files, nil
+				}
+			}
+		}
+	}
+	return nil, notFound("ref %s not found in %s/%s", ref, owner, name)
+}
+
+// Client serves a Store through the repository.Client interface
+type Client struct {
+	store *Store
+}
+
+// NewClient creates a client serving store
+func NewClient(store *Store) *Client {
+	return &Client{store: store}
+}
+
+// GetRepositoryInfo implements repository.Client
+func (c *Client) GetRepositoryInfo(ctx context.Context, owner, repo string) (*repository.Info, error) {
+	if _, err := c.tree(ctx, owner, repo, ""); err != nil {
+		return nil, err
+	}
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	var description string
+	if r := c.store.repo(owner, repo, false); r != nil {
+		description = r.description
+	}
+	return &repository.Info{
+		ID:            repoKey(owner, repo),
+		Name:          repo,
+		FullName:      owner + "/" + repo,
+		Description:   description,
+		DefaultBranch: DefaultBranch,
+		URL:           "memory://" + owner + "/" + repo,
+		CloneURL:      "memory://" + owner + "/" + repo + ".git",
+	}, nil
+}
+
+// tree checks ctx and returns a ref's files
+func (c *Client) tree(ctx context.Context, owner, repo, ref string) (Files, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.store.tree(owner, repo, ref)
+}
+
+// fileInfo describes a file of a tree
+func fileInfo(owner, repo, filePath, content string) repository.FileInfo {
+	return repository.FileInfo{
+		Path: filePath,
+		Name: path.Base(filePath),
+		Type: "file",
+		Size: int64(len(content)),
+		Mode: "100644",
+		SHA:  commitSHA(Files{filePath: content}),
+		URL:  "memory://" + owner + "/" + repo + "/" + filePath,
+	}
+}
+
+// ListFiles implements repository.Client: the files and directories directly
+// in dir
+func (c *Client) ListFiles(ctx context.Context, owner, repo, ref, dir string) ([]repository.FileInfo, error) {
+	files, err := c.tree(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.Trim(dir, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	seen := map[string]bool{}
+	var out []repository.FileInfo
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		rest, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+		if sub, _, isDir := strings.Cut(rest, "/"); isDir {
+			if !seen[sub] {
+				seen[sub] = true
+				out = append(out, repository.FileInfo{Path: prefix + sub, Name: sub, Type: "dir", Mode: "040000"})
+			}
+			continue
+		}
+		out = append(out, fileInfo(owner, repo, p, files[p]))
+	}
+	if out == nil && prefix != "" {
+		return nil, notFound("path %s not found in %s/%s", dir, owner, repo)
+	}
+	return out, nil
+}
+
+// ListFilesRecursive implements repository.Client
+func (c *Client) ListFilesRecursive(ctx context.Context, owner, repo, ref string) ([]repository.FileInfo, error) {
+	return c.ListFilesUnder(ctx, owner, repo, ref, "")
+}
+
+// ListFilesUnder implements repository.Client
+func (c *Client) ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]repository.FileInfo, error) {
+	files, err := c.tree(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	prefix = strings.Trim(prefix, "/")
+	var out []repository.FileInfo
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		if prefix == "" || strings.HasPrefix(p, prefix+"/") {
+			out = append(out, fileInfo(owner, repo, p, files[p]))
+		}
+	}
+	return out, nil
+}
+
+// GetFileContent implements repository.Client
+func (c *Client) GetFileContent(ctx context.Context, owner, repo, ref, filePath string) (string, error) {
+	files, err := c.tree(ctx, owner, repo, ref)
+	if err != nil {
+		return "", err
+	}
+	content, ok := files[strings.TrimPrefix(filePath, "/")]
+	if !ok {
+		return "", notFound("file %s not found in %s/%s", filePath, owner, repo)
+	}
+	return content, nil
+}
+
+// ResolveCommit implements repository.CommitResolver
+func (c *Client) ResolveCommit(ctx context.Context, owner, repo, ref string) (string, error) {
+	files, err := c.tree(ctx, owner, repo, ref)
+	if err != nil {
+		return "", err
+	}
+	return commitSHA(files), nil
+}
+
+// ListRefs implements repository.RefLister: branches, then tags, each sorted
+// by name with the default branch first
+func (c *Client) ListRefs(ctx context.Context, owner, repo string) ([]repository.Ref, error) {
+	if _, err := c.tree(ctx, owner, repo, ""); err != nil {
+		return nil, err
+	}
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	r := c.store.repo(owner, repo, false)
+	if r == nil {
+		return nil, notFound("repository %s/%s not found", owner, repo)
+	}
+	var refs []repository.Ref
+	for _, name := range sortedRefs(r.branches) {
+		refs = append(refs, repository.Ref{Name: name, Kind: repository.RefKindBranch, CommitSHA: commitSHA(r.branches[name])})
+	}
+	for _, name := range sortedRefs(r.tags) {
+		refs = append(refs, repository.Ref{Name: name, Kind: repository.RefKindTag, CommitSHA: commitSHA(r.tags[name])})
+	}
+	return refs, nil
+}
+
+// DownloadArchive implements repository.ArchiveDownloader with a tarball
+// whose files sit in a "repo-<commit>/" directory, as on real providers
+func (c *Client) DownloadArchive(ctx context.Context, owner, repo, ref string) (io.ReadCloser, error) {
+	files, err := c.tree(ctx, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	root := repo + "-" + commitSHA(files) + "/"
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: root, Mode: 0o755}); err != nil {
+		return nil, err
+	}
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		hdr := &tar.Header{Typeflag: tar.TypeReg, Name: root + p, Mode: 0o644, Size: int64(len(files[p]))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(tw, files[p]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&buf), nil
+}
+
+// CurrentUser implements repository.RepositoryDiscoverer; every token
+// belongs to the user "memory"
+func (c *Client) CurrentUser(ctx context.Context) (string, error) {
+	return Provider, ctx.Err()
+}
+
+// ListRepositories implements repository.RepositoryDiscoverer, listing
+// owner's repositories (every repository when owner is empty) by name
+func (c *Client) ListRepositories(ctx context.Context, owner string, limit int) ([]repository.RepositorySummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	var out []repository.RepositorySummary
+	for _, key := range slices.Sorted(maps.Keys(c.store.repos)) {
+		repoOwner, name, _ := strings.Cut(key, "/")
+		if owner != "" && !strings.EqualFold(owner, repoOwner) {
+			continue
+		}
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, repository.RepositorySummary{
+			Owner:         repoOwner,
+			Name:          name,
+			Description:   c.store.repos[key].description,
+			DefaultBranch: DefaultBranch,
+		})
+	}
+	return out, nil
+}
+
+// sortedRefs returns the names of refs, DefaultBranch first
+func sortedRefs(refs map[string]Files) []string {
+	names := slices.Sorted(maps.Keys(refs))
+	if i := slices.Index(names, DefaultBranch); i > 0 {
+		names = append(append([]string{DefaultBranch}, names[:i]...), names[i+1:]...)
+	}
+	return names
+}
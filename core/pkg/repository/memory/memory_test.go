@@ -0,0 +1,308 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+func paths(files []repository.FileInfo) []string {
+	var out []string
+	for _, f := range files {
+		out = append(out, f.Path)
+	}
+	return out
+}
+
+func TestClient_FilesAndContent(t *testing.T) {
+	store := memory.NewStore()
+	store.AddRepository("acme", "api", memory.Files{
+		"poetry.lock":              "lock",
+		"services/web/poetry.lock": "web lock",
+		"/README.md":               "readme",
+	})
+	client := memory.NewClient(store)
+	ctx := context.Background()
+
+	files, err := client.ListFilesRecursive(ctx, "acme", "api", "")
+	if err != nil || !slices.Equal(paths(files), []string{"README.md", "poetry.lock", "services/web/poetry.lock"}) {
+		t.Errorf("ListFilesRecursive = %v, %v", paths(files), err)
+	}
+	files, err = client.ListFilesUnder(ctx, "acme", "api", "main", "services")
+	if err != nil || !slices.Equal(paths(files), []string{"services/web/poetry.lock"}) {
+		t.Errorf("ListFilesUnder(services) = %v, %v", paths(files), err)
+	}
+	files, err = client.ListFiles(ctx, "acme", "api", "", "")
+	if err != nil || !slices.Equal(paths(files), []string{"README.md", "poetry.lock", "services"}) || files[2].Type != "dir" {
+		t.Errorf("ListFiles(root) = %+v, %v", files, err)
+	}
+	if content, err := client.GetFileContent(ctx, "acme", "api", "", "services/web/poetry.lock"); err != nil || content != "web lock" {
+		t.Errorf("GetFileContent = %q, %v", content, err)
+	}
+	if info, err := client.GetRepositoryInfo(ctx, "Acme", "API"); err != nil || info.DefaultBranch != memory.DefaultBranch {
+		t.Errorf("GetRepositoryInfo = %+v, %v", info, err)
+	}
+
+	for name, err := range map[string]error{
+		"missing file":       func() error { _, err := client.GetFileContent(ctx, "acme", "api", "", "nope"); return err }(),
+		"missing ref":        func() error { _, err := client.ListFilesRecursive(ctx, "acme", "api", "dev"); return err }(),
+		"missing repository": func() error { _, err := client.GetRepositoryInfo(ctx, "acme", "nope"); return err }(),
+	} {
+		if repository.StatusCode(err) != http.StatusNotFound || !repository.IsProviderError(err) {
+			t.Errorf("%s: err = %v, want a provider 404", name, err)
+		}
+	}
+}
+
+// fetchCounter counts file content and listing requests reaching the
+// wrapped client
+type fetchCounter struct {
+	*memory.Client
+	fetches int
+	lists   int
+}
+
+func (c *fetchCounter) ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]repository.FileInfo, error) {
+	c.lists++
+	return c.Client.ListFilesUnder(ctx, owner, repo, ref, prefix)
+}
+
+func (c *fetchCounter) GetFileContent(ctx context.Context, owner, repo, ref, filePath string) (string, error) {
+	c.fetches++
+	return c.Client.GetFileContent(ctx, owner, repo, ref, filePath)
+}
+
+func TestArchiveClient(t *testing.T) {
+	store := memory.NewStore()
+	store.AddRepository("acme", "api", memory.Files{
+		"poetry.lock":              "lock",
+		"services/web/poetry.lock": "web lock",
+		"services/web/app.py":      "print()",
+		"README.md":                "readme",
+	})
+	inner := &fetchCounter{Client: memory.NewClient(store)}
+	ctx := context.Background()
+	keep := func(p string) bool { return strings.HasSuffix(p, ".lock") }
+
+	client, err := repository.NewArchiveClient(ctx, inner, "acme", "api", "main", keep, 0)
+	if err != nil {
+		t.Fatalf("NewArchiveClient: %v", err)
+	}
+	files, err := client.ListFilesUnder(ctx, "acme", "api", "main", "services")
+	if err != nil || !slices.Equal(paths(files), []string{"services/web/app.py", "services/web/poetry.lock"}) {
+		t.Errorf("ListFilesUnder(services) = %v, %v", paths(files), err)
+	}
+	files, err = client.ListFiles(ctx, "acme", "api", "main", "")
+	if err != nil || !slices.Equal(paths(files), []string{"README.md", "poetry.lock", "services"}) || files[2].Type != "dir" {
+		t.Errorf("ListFiles(root) = %+v, %v", files, err)
+	}
+	if content, err := client.GetFileContent(ctx, "acme", "api", "main", "services/web/poetry.lock"); err != nil || content != "web lock" {
+		t.Errorf("GetFileContent(kept) = %q, %v", content, err)
+	}
+	if inner.fetches != 0 {
+		t.Errorf("kept file fetched from the provider %d times", inner.fetches)
+	}
+	if content, err := client.GetFileContent(ctx, "acme", "api", "main", "README.md"); err != nil || content != "readme" || inner.fetches != 1 {
+		t.Errorf("GetFileContent(not kept) = %q, %v after %d fetches; want it from the provider", content, err, inner.fetches)
+	}
+
+	if _, err := repository.NewArchiveClient(ctx, inner, "acme", "nope", "main", keep, 0); repository.StatusCode(err) != http.StatusNotFound {
+		t.Errorf("missing repository: err = %v, want a provider 404", err)
+	}
+}
+
+func TestClient_RefsAndCommits(t *testing.T) {
+	store := memory.NewStore()
+	store.AddRepository("acme", "api", memory.Files{"uv.lock": "v1"})
+	store.SetBranch("acme", "api", "develop", memory.Files{"uv.lock": "v2"})
+	store.SetTag("acme", "api", "v1.0.0", memory.Files{"uv.lock": "v1"})
+	client := memory.NewClient(store)
+	ctx := context.Background()
+
+	refs, err := client.ListRefs(ctx, "acme", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, r := range refs {
+		names = append(names, r.Kind+":"+r.Name)
+	}
+	if want := []string{"branch:main", "branch:develop", "tag:v1.0.0"}; !slices.Equal(names, want) {
+		t.Errorf("ListRefs = %v, want %v", names, want)
+	}
+
+	main, _ := client.ResolveCommit(ctx, "acme", "api", "")
+	tag, _ := client.ResolveCommit(ctx, "acme", "api", "v1.0.0")
+	develop, _ := client.ResolveCommit(ctx, "acme", "api", "develop")
+	if main != tag || main == develop || !repository.IsCommitSHA(main) {
+		t.Errorf("commits main=%s tag=%s develop=%s: want equal trees to share a SHA", main, tag, develop)
+	}
+	if content, err := client.GetFileContent(ctx, "acme", "api", develop[:12], "uv.lock"); err != nil || content != "v2" {
+		t.Errorf("GetFileContent at a commit SHA = %q, %v", content, err)
+	}
+
+	store.SetFile("acme", "api", "", "uv.lock", "v3")
+	if changed, _ := client.ResolveCommit(ctx, "acme", "api", ""); changed == main {
+		t.Error("commit SHA did not change with the files")
+	}
+	if err := repository.CheckRef(ctx, client, "acme", "api", "develop"); err != nil {
+		t.Errorf("CheckRef(develop) = %v", err)
+	}
+}
+
+func TestStore_SetErrorAndDiscovery(t *testing.T) {
+	store := memory.NewStore()
+	store.AddRepository("acme", "api", nil)
+	store.AddRepository("acme", "web", nil)
+	store.AddRepository("other", "tool", nil)
+	client := memory.NewClient(store)
+	ctx := context.Background()
+
+	boom := &repository.StatusError{StatusCode: http.StatusUnauthorized, Message: "bad credentials"}
+	store.SetError("acme", "web", boom)
+	if _, err := client.ListFilesRecursive(ctx, "acme", "web", ""); !errors.Is(err, boom) {
+		t.Errorf("err = %v, want the injected error", err)
+	}
+	store.SetError("acme", "web", nil)
+	if _, err := client.ListFilesRecursive(ctx, "acme", "web", ""); err != nil {
+		t.Errorf("err = %v after clearing it", err)
+	}
+
+	var d repository.RepositoryDiscoverer = client
+	repos, err := d.ListRepositories(ctx, "acme", 0)
+	if err != nil || len(repos) != 2 || repos[0].Name != "api" || repos[1].Name != "web" {
+		t.Errorf("ListRepositories(acme) = %+v, %v", repos, err)
+	}
+	if repos, _ := d.ListRepositories(ctx, "", 1); len(repos) != 1 {
+		t.Errorf("ListRepositories limit 1 returned %d", len(repos))
+	}
+}
+
+func TestRegisteredProvider(t *testing.T) {
+	if !slices.Contains(repository.SupportedProviders(), memory.Provider) {
+		t.Fatalf("SupportedProviders() = %v, want it to include %s", repository.SupportedProviders(), memory.Provider)
+	}
+	memory.Default.AddRepository("demo", "registered", memory.Files{"pdm.lock": "x"})
+	t.Cleanup(func() { memory.Default.Remove("demo", "registered") })
+
+	client, err := repository.NewClient("Memory", repository.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, err := client.GetFileContent(context.Background(), "demo", "registered", "", "pdm.lock"); err != nil || content != "x" {
+		t.Errorf("GetFileContent through the registry = %q, %v", content, err)
+	}
+}
+
+func TestContentCache(t *testing.T) {
+	store := memory.NewStore()
+	store.AddRepository("acme", "api", memory.Files{"services/web/poetry.lock": "web lock", "poetry.lock": "lock"})
+	inner := &fetchCounter{Client: memory.NewClient(store)}
+	ctx := context.Background()
+	sha, err := inner.ResolveCommit(ctx, "acme", "api", "main")
+	if err != nil {
+		t.Fatalf("ResolveCommit: %v", err)
+	}
+	dir := t.TempDir()
+
+	run := func(ref string) {
+		t.Helper()
+		cache, err := repository.NewContentCache(dir, 0)
+		if err != nil {
+			t.Fatalf("NewContentCache: %v", err)
+		}
+		client := cache.Client("memory", inner)
+		files, err := client.ListFilesUnder(ctx, "acme", "api", ref, "services")
+		if err != nil || !slices.Equal(paths(files), []string{"services/web/poetry.lock"}) {
+			t.Fatalf("ListFilesUnder = %v, %v", paths(files), err)
+		}
+		if content, err := client.GetFileContent(ctx, "acme", "api", ref, "services/web/poetry.lock"); err != nil || content != "web lock" {
+			t.Fatalf("GetFileContent = %q, %v", content, err)
+		}
+	}
+
+	run(sha)
+	if inner.lists != 1 || inner.fetches != 1 {
+		t.Fatalf("first run: %d listings, %d fetches; want 1 of each", inner.lists, inner.fetches)
+	}
+	run(sha)
+	if inner.lists != 1 || inner.fetches != 1 {
+		t.Errorf("repeat run reached the provider: %d listings, %d fetches", inner.lists, inner.fetches)
+	}
+	run("main")
+	if inner.lists != 2 || inner.fetches != 2 {
+		t.Errorf("branch ref served from the cache: %d listings, %d fetches", inner.lists, inner.fetches)
+	}
+
+	// Corrupt every entry: they are discarded and fetched again
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		return os.WriteFile(p, []byte("sha256:0\ntampered"), 0o600)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(sha)
+	if inner.lists != 3 || inner.fetches != 3 {
+		t.Errorf("corrupt entries served: %d listings, %d fetches", inner.lists, inner.fetches)
+	}
+
+	cache, err := repository.NewContentCache(dir, 0)
+	if err != nil {
+		t.Fatalf("NewContentCache: %v", err)
+	}
+	if stats, err := cache.Stats(); err != nil || stats.Entries != 2 || stats.Size == 0 {
+		t.Errorf("Stats() = %+v, %v; want the listing and the file", stats, err)
+	}
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if stats, err := cache.Stats(); err != nil || stats.Entries != 0 || stats.Size != 0 {
+		t.Errorf("Stats() after Clear = %+v, %v", stats, err)
+	}
+}
+
+func TestContentCache_Eviction(t *testing.T) {
+	store := memory.NewStore()
+	files := memory.Files{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		files[name+"/poetry.lock"] = strings.Repeat(name, 100)
+	}
+	store.AddRepository("acme", "api", files)
+	inner := &fetchCounter{Client: memory.NewClient(store)}
+	ctx := context.Background()
+	sha, err := inner.ResolveCommit(ctx, "acme", "api", "main")
+	if err != nil {
+		t.Fatalf("ResolveCommit: %v", err)
+	}
+
+	cache, err := repository.NewContentCache(t.TempDir(), 400)
+	if err != nil {
+		t.Fatalf("NewContentCache: %v", err)
+	}
+	client := cache.Client("memory", inner)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if _, err := client.GetFileContent(ctx, "acme", "api", sha, name+"/poetry.lock"); err != nil {
+			t.Fatalf("GetFileContent(%s): %v", name, err)
+		}
+	}
+	stats, err := cache.Stats()
+	if err != nil || stats.Size > 400 || stats.Entries == 0 {
+		t.Errorf("Stats() = %+v, %v; want entries within the 400 byte limit", stats, err)
+	}
+	fetches := inner.fetches
+	if _, err := client.GetFileContent(ctx, "acme", "api", sha, "d/poetry.lock"); err != nil || inner.fetches != fetches {
+		t.Errorf("most recent entry evicted (err %v)", err)
+	}
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// Ref kinds reported in Ref.Kind
+const (
+	RefKindBranch = "branch"
+	RefKindTag    = "tag"
+)
+
+// maxListedRefs caps the branches and (separately) the tags ListRefs returns;
+// repositories tagging every build can have tens of thousands
+const maxListedRefs = 1000
+
+// maxRefSuggestions caps RefNotFoundError.Suggestions
+const maxRefSuggestions = 3
+
+// Ref is a branch or tag of a repository
+type Ref struct {
+	Name      string
+	Kind      string // RefKindBranch or RefKindTag
+	CommitSHA string // Commit the ref points to, when the provider reports it
+}
+
+// RefLister is an optional capability implemented by clients able to list a
+// repository's branches and tags, e.g. to offer refs in a picker or validate
+// configured ones. Callers should type-assert a Client against this
+// interface before use.
+type RefLister interface {
+	// ListRefs returns the repository's branches followed by its tags, at
+	// most maxListedRefs of each
+	ListRefs(ctx context.Context, owner, repo string) ([]Ref, error)
+}
+
+// ErrRefCheckUnsupported is returned by CheckRef for clients that can
+// neither list refs nor resolve commits
+var ErrRefCheckUnsupported = errors.New("provider cannot list refs")
+
+// RefNotFoundError reports a ref that is neither a branch, a tag nor a
+// resolvable commit of the repository
+type RefNotFoundError struct {
+	Repository  string   // owner/repo
+	Ref         string   // Ref as configured
+	Suggestions []string // Similarly named refs, closest first
+}
+
+func (e *RefNotFoundError) Error() string {
+	msg := fmt.Sprintf("ref %q not found in %s", e.Ref, e.Repository)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+// IsCommitSHA reports whether ref looks like a full or abbreviated commit SHA
+func IsCommitSHA(ref string) bool {
+	if len(ref) < 7 || len(ref) > 40 {
+		return false
+	}
+	for _, c := range ref {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckRef verifies that ref names a branch or tag of owner/repo or, when it
+// looks like a commit SHA, a commit. An empty ref (the default branch) is
+// always valid. A missing ref yields a *RefNotFoundError suggesting similarly
+// named refs; clients without RefLister or CommitResolver yield
+// ErrRefCheckUnsupported.
+func CheckRef(ctx context.Context, client Client, owner, repo, ref string) error {
+	if ref == "" {
+		return nil
+	}
+	lister, canList := client.(RefLister)
+	resolver, canResolve := client.(CommitResolver)
+	if !canList && !canResolve {
+		return ErrRefCheckUnsupported
+	}
+	var refs []Ref
+	if canList {
+		var err error
+		if refs, err = lister.ListRefs(ctx, owner, repo); err != nil {
+			return err
+		}
+		if slices.ContainsFunc(refs, func(r Ref) bool { return r.Name == ref }) {
+			return nil
+		}
+	}
+	if canResolve && (IsCommitSHA(ref) || !canList) {
+		_, err := resolver.ResolveCommit(ctx, owner, repo, ref)
+		if err == nil {
+			return nil
+		}
+		if code := StatusCode(err); code != http.StatusNotFound && code != http.StatusUnprocessableEntity {
+			return err
+		}
+	}
+	return &RefNotFoundError{Repository: owner + "/" + repo, Ref: ref, Suggestions: suggestRefs(refs, ref)}
+}
+
+// suggestRefs returns the names in refs within a small edit distance of ref
+// (or matching it case-insensitively), closest first
+func suggestRefs(refs []Ref, ref string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	limit := max(2, len(ref)/3)
+	var candidates []candidate
+	for _, r := range refs {
+		d := editDistance(strings.ToLower(r.Name), strings.ToLower(ref))
+		if d <= limit && !slices.ContainsFunc(candidates, func(c candidate) bool { return c.name == r.Name }) {
+			candidates = append(candidates, candidate{r.Name, d})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return a.distance - b.distance })
+	var out []string
+	for _, c := range candidates[:min(len(candidates), maxRefSuggestions)] {
+		out = append(out, c.name)
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// ListRefs lists the branches and tags of a GitHub repository
+func (g *GitHubClient) ListRefs(ctx context.Context, owner, repo string) ([]Ref, error) {
+	if g.api.Refs == nil {
+		return nil, fmt.Errorf("ref listing not available")
+	}
+	var refs []Ref
+	for page := 1; page != 0 && len(refs) < maxListedRefs; {
+		branches, resp, err := g.api.Refs.ListBranches(ctx, owner, repo, &github.BranchListOptions{
+			ListOptions: github.ListOptions{Page: page, PerPage: discoveryPageSize},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches from GitHub: %w", err)
+		}
+		for _, b := range branches {
+			refs = append(refs, Ref{Name: b.GetName(), Kind: RefKindBranch, CommitSHA: b.GetCommit().GetSHA()})
+		}
+		page = resp.NextPage
+	}
+	refs = refs[:min(len(refs), maxListedRefs)]
+
+	branchCount := len(refs)
+	for page := 1; page != 0 && len(refs)-branchCount < maxListedRefs; {
+		tags, resp, err := g.api.Refs.ListTags(ctx, owner, repo, &github.ListOptions{Page: page, PerPage: discoveryPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags from GitHub: %w", err)
+		}
+		for _, t := range tags {
+			refs = append(refs, Ref{Name: t.GetName(), Kind: RefKindTag, CommitSHA: t.GetCommit().GetSHA()})
+		}
+		page = resp.NextPage
+	}
+	return refs[:min(len(refs), branchCount+maxListedRefs)], nil
+}
+
+// ListRefs lists the branches and tags of a GitLab project
+func (g *GitLabClient) ListRefs(ctx context.Context, owner, repo string) ([]Ref, error) {
+	if g.api.Refs == nil {
+		return nil, fmt.Errorf("ref listing not available")
+	}
+	projectID := gitlabProjectID(owner, repo)
+	var refs []Ref
+	for page := 1; page != 0 && len(refs) < maxListedRefs; {
+		branches, resp, err := g.api.Refs.ListBranches(projectID, &gitlab.ListBranchesOptions{
+			ListOptions: gitlab.ListOptions{Page: page, PerPage: discoveryPageSize},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches from GitLab: %w", err)
+		}
+		for _, b := range branches {
+			r := Ref{Name: b.Name, Kind: RefKindBranch}
+			if b.Commit != nil {
+				r.CommitSHA = b.Commit.ID
+			}
+			refs = append(refs, r)
+		}
+		page = int(resp.NextPage)
+	}
+	refs = refs[:min(len(refs), maxListedRefs)]
+
+	branchCount := len(refs)
+	for page := 1; page != 0 && len(refs)-branchCount < maxListedRefs; {
+		tags, resp, err := g.api.Refs.ListTags(projectID, &gitlab.ListTagsOptions{
+			ListOptions: gitlab.ListOptions{Page: page, PerPage: discoveryPageSize},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags from GitLab: %w", err)
+		}
+		for _, t := range tags {
+			r := Ref{Name: t.Name, Kind: RefKindTag}
+			if t.Commit != nil {
+				r.CommitSHA = t.Commit.ID
+			}
+			refs = append(refs, r)
+		}
+		page = int(resp.NextPage)
+	}
+	return refs[:min(len(refs), branchCount+maxListedRefs)], nil
+}
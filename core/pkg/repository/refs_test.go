@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGitHubListRefsAndCheckRef(t *testing.T) {
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/org/api/branches":
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[{"name":"release/1.x","commit":{"sha":"bbb"}}]`))
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/org/api/branches?page=2>; rel="next"`, srvURL))
+			_, _ = w.Write([]byte(`[{"name":"main","commit":{"sha":"aaa"}}]`))
+		case "/api/v3/repos/org/api/tags":
+			_, _ = w.Write([]byte(`[{"name":"v1.0.0","commit":{"sha":"ccc"}}]`))
+		case "/api/v3/repos/org/api/commits/0123456789abcdef":
+			_, _ = w.Write([]byte(`0123456789abcdef0123456789abcdef01234567`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	client, err := NewGitHubClient(Config{BaseURL: srv.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	refs, err := client.ListRefs(ctx, "org", "api")
+	if err != nil {
+		t.Fatalf("ListRefs: %v", err)
+	}
+	want := []Ref{
+		{Name: "main", Kind: RefKindBranch, CommitSHA: "aaa"},
+		{Name: "release/1.x", Kind: RefKindBranch, CommitSHA: "bbb"},
+		{Name: "v1.0.0", Kind: RefKindTag, CommitSHA: "ccc"},
+	}
+	if !slices.Equal(refs, want) {
+		t.Errorf("refs = %+v, want %+v", refs, want)
+	}
+
+	for _, ref := range []string{"", "main", "v1.0.0", "0123456789abcdef"} {
+		if err := CheckRef(ctx, client, "org", "api", ref); err != nil {
+			t.Errorf("CheckRef(%q): %v", ref, err)
+		}
+	}
+	err = CheckRef(ctx, client, "org", "api", "mian")
+	var notFound *RefNotFoundError
+	if !errors.As(err, &notFound) || !slices.Equal(notFound.Suggestions, []string{"main"}) {
+		t.Errorf("CheckRef(mian) = %v, want a not-found error suggesting main", err)
+	}
+	if err := CheckRef(ctx, client, "org", "api", "deadbeef"); !errors.As(err, &notFound) {
+		t.Errorf("CheckRef(unknown SHA) = %v, want a not-found error", err)
+	}
+}
+
+func TestGitLabListRefs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/grp%2Fsub%2Fweb/repository/branches":
+			_, _ = w.Write([]byte(`[{"name":"main","commit":{"id":"aaa"}}]`))
+		case "/api/v4/projects/grp%2Fsub%2Fweb/repository/tags":
+			_, _ = w.Write([]byte(`[{"name":"v2.0.0","commit":{"id":"ddd"}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewGitLabClient(Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs, err := client.ListRefs(context.Background(), "grp/sub", "web")
+	want := []Ref{{Name: "main", Kind: RefKindBranch, CommitSHA: "aaa"}, {Name: "v2.0.0", Kind: RefKindTag, CommitSHA: "ddd"}}
+	if err != nil || !slices.Equal(refs, want) {
+		t.Errorf("ListRefs = %+v, %v; want %+v", refs, err, want)
+	}
+}
+
+func TestIsCommitSHA(t *testing.T) {
+	for ref, want := range map[string]bool{"abc1234": true, "0123456789abcdef0123456789abcdef01234567": true, "main": false, "abc123": false, "v1.0.0": false} {
+		if got := IsCommitSHA(ref); got != want {
+			t.Errorf("IsCommitSHA(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
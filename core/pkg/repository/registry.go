@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// registration is one registered provider
+type registration struct {
+	factory func(Config) (Client, error)
+	builtin bool // Shipped with devdashboard
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*registration{
+		string(ProviderGitHub): {factory: func(c Config) (Client, error) { return NewGitHubClient(c) }, builtin: true},
+		string(ProviderGitLab): {factory: func(c Config) (Client, error) { return NewGitLabClient(c) }, builtin: true},
+	}
+)
+
+// Register makes a provider available to the Factory (and so as a key of the
+// configuration's "providers" section) under name, which is matched
+// case-insensitively. Programs embedding devdashboard call it from an init
+// function to plug in their own git hosting. Register panics if name is
+// empty, factory is nil or the name is already registered.
+func Register(name string, factory func(Config) (Client, error)) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		panic("repository: Register with empty provider name")
+	}
+	if factory == nil {
+		panic("repository: Register provider " + key + " with nil factory")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[key]; dup {
+		panic("repository: Register called twice for provider " + key)
+	}
+	registry[key] = &registration{factory: factory}
+}
+
+// create builds a client for a registered provider
+func create(provider string, config Config) (Client, error) {
+	registryMu.RLock()
+	r, ok := registry[strings.ToLower(strings.TrimSpace(provider))]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s (supported: %s)", provider, strings.Join(SupportedProviders(), ", "))
+	}
+	return r.factory(config)
+}
+
+// registeredProviders returns the built-in providers in their usual order
+// followed by the registered ones, sorted
+func registeredProviders() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := []string{string(ProviderGitHub), string(ProviderGitLab)}
+	var extra []string
+	for name, r := range registry {
+		if !r.builtin {
+			extra = append(extra, name)
+		}
+	}
+	slices.Sort(extra)
+	return append(names, extra...)
+}
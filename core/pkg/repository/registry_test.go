@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	var got Config
+	Register("gitea", func(c Config) (Client, error) {
+		got = c
+		return NewGitHubClient(c)
+	})
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "gitea")
+		registryMu.Unlock()
+	})
+
+	if _, err := NewFactory(Config{Token: "t", BaseURL: "https://git.example.com/api/v3/"}).CreateClient("Gitea"); err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	if got.Token != "t" {
+		t.Errorf("factory received %+v, want the factory's config", got)
+	}
+	if want := []string{"github", "gitlab", "gitea"}; !slices.Equal(SupportedProviders(), want) {
+		t.Errorf("SupportedProviders() = %v, want %v", SupportedProviders(), want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a built-in provider to panic")
+		}
+	}()
+	Register("GitHub", func(c Config) (Client, error) { return nil, nil })
+}
@@ -6,6 +6,7 @@ package repository
 
 import (
 	"context"
+	"time"
 )
 
 // FileInfo represents metadata about a file in a repository
@@ -27,6 +28,7 @@ type Info struct {
 	Description   string // Repository description
 	DefaultBranch string // Default branch name
 	URL           string // Web URL to the repository
+	CloneURL      string // HTTPS URL for git clone/push
 }
 
 // Client defines the interface for interacting with git repository providers
@@ -66,6 +68,19 @@ type Client interface {
 	//   - Error if the operation fails
 	ListFilesRecursive(ctx context.Context, owner, repo, ref string) ([]FileInfo, error)
 
+	// ListFilesUnder retrieves all files recursively below a directory, without
+	// traversing the rest of the repository
+	// Parameters:
+	//   - ctx: Context for cancellation and timeouts
+	//   - owner: Repository owner (username or organization)
+	//   - repo: Repository name
+	//   - ref: Git reference (branch name, tag, or commit SHA). Empty string uses default branch
+	//   - prefix: Directory within the repository. Empty string lists the whole repository
+	// Returns:
+	//   - Slice of FileInfo objects for all files below prefix; empty if prefix does not exist
+	//   - Error if the operation fails
+	ListFilesUnder(ctx context.Context, owner, repo, ref, prefix string) ([]FileInfo, error)
+
 	// GetFileContent retrieves the content of a specific file from the repository
 	// Parameters:
 	//   - ctx: Context for cancellation and timeouts
@@ -90,4 +105,51 @@ type Config struct {
 	// For GitHub Enterprise or GitLab self-hosted instances
 	// Leave empty for public GitHub (github.com) or GitLab (gitlab.com)
 	BaseURL string
+
+	// Enterprise marks BaseURL as a GitHub Enterprise Server. A BaseURL
+	// naming a host other than github.com is detected as one without it.
+	Enterprise bool
+
+	// APIPrefix is the path of the REST API below BaseURL on GitHub
+	// Enterprise Server; empty uses DefaultEnterpriseAPIPrefix
+	APIPrefix string
+
+	// Retry configures retries of transient API failures (5xx, 429, timeouts).
+	// Nil keeps each provider library's default behavior.
+	Retry *RetryPolicy
+
+	// Budgets cap the API requests this client may issue; every request is
+	// charged to each of them (e.g. a per-provider and a per-repository
+	// budget). Nil entries are unlimited.
+	Budgets []*Budget
+
+	// MaxFileSize bounds the content read for a single file, in bytes. Zero
+	// uses DefaultMaxFileSize; a negative value disables the limit.
+	MaxFileSize int64
+
+	// Proxy is the URL of an HTTP(S) proxy for API requests. Empty uses the
+	// HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment variables.
+	Proxy string
+
+	// CAFile is a PEM bundle of certificate authorities trusted in addition
+	// to the system ones (e.g. for an instance with an internal CA)
+	CAFile string
+
+	// InsecureSkipVerify disables TLS certificate verification. Only for
+	// test instances: it exposes the token to any interceptor.
+	InsecureSkipVerify bool
+
+	// Tracer, when set, records every API request the client sends (see
+	// HTTPTracer)
+	Tracer *HTTPTracer
+
+	// Fixtures, when set, records every API response to a directory or
+	// replays recorded ones offline (see Fixtures)
+	Fixtures *Fixtures
+
+	// RequestTimeout bounds each API request attempt, response body
+	// included. An attempt that exceeds it fails with a *RequestTimeoutError
+	// and is retried under Retry. Zero leaves requests bounded only by
+	// their context.
+	RequestTimeout time.Duration
 }
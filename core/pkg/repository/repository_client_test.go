@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -20,6 +21,15 @@ type mockGitHubRepos struct {
 	repo         *github.Repository
 	dirContents  map[string][]*github.RepositoryContent
 	fileContents map[string]*github.RepositoryContent
+	downloads    map[string]string // Raw content served by DownloadContents
+}
+
+func (m *mockGitHubRepos) DownloadContents(_ context.Context, _, _, filepath string, _ *github.RepositoryContentGetOptions) (io.ReadCloser, *github.Response, error) {
+	content, ok := m.downloads[filepath]
+	if !ok {
+		return nil, nil, fmt.Errorf("no download for %s", filepath)
+	}
+	return io.NopCloser(strings.NewReader(content)), &github.Response{}, nil
 }
 
 func (m *mockGitHubRepos) Get(_ context.Context, _, _ string) (*github.Repository, *github.Response, error) {
@@ -38,11 +48,22 @@ func (m *mockGitHubRepos) GetContents(_ context.Context, _, _, path string, _ *g
 }
 
 type mockGitHubGit struct {
-	tree *github.Tree
+	tree  *github.Tree
+	trees map[string]*github.Tree // By "sha" (recursive) or "sha:flat"; overrides tree
+	calls int
 }
 
-func (m *mockGitHubGit) GetTree(_ context.Context, _ string, _ string, _ string, _ bool) (*github.Tree, *github.Response, error) {
-	return m.tree, &github.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
+func (m *mockGitHubGit) GetTree(_ context.Context, _ string, _ string, sha string, recursive bool) (*github.Tree, *github.Response, error) {
+	m.calls++
+	tree := m.tree
+	if m.trees != nil {
+		key := sha
+		if !recursive {
+			key += ":flat"
+		}
+		tree = m.trees[key]
+	}
+	return tree, &github.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
 }
 
 ///////////////////////////////
@@ -60,9 +81,14 @@ func (m *mockGitLabProjects) GetProject(_ string, _ *gitlab.GetProjectOptions, _
 type mockGitLabRepos struct {
 	pages    map[int][]*gitlab.TreeNode
 	nextPage map[int]int
+	path     string // Path option of the last ListTree call
 }
 
 func (m *mockGitLabRepos) ListTree(_ string, opts *gitlab.ListTreeOptions, _ ...gitlab.RequestOptionFunc) ([]*gitlab.TreeNode, *gitlab.Response, error) {
+	m.path = ""
+	if opts.Path != nil {
+		m.path = *opts.Path
+	}
 	page := opts.Page
 	nodes := m.pages[page]
 	resp := &gitlab.Response{
@@ -76,9 +102,17 @@ type mockGitLabFiles struct {
 	files map[string]*gitlab.File
 }
 
-func (m *mockGitLabFiles) GetFile(_ string, filePath string, _ *gitlab.GetFileOptions, _ ...gitlab.RequestOptionFunc) (*gitlab.File, *gitlab.Response, error) {
-	f := m.files[filePath]
-	return f, &gitlab.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
+func (m *mockGitLabFiles) StreamRawFile(_ string, filePath string, _ *gitlab.GetRawFileOptions, w io.Writer, _ ...gitlab.RequestOptionFunc) (*gitlab.Response, error) {
+	f, ok := m.files[filePath]
+	if !ok {
+		return nil, fmt.Errorf("no file %s", filePath)
+	}
+	content, err := base64.StdEncoding.DecodeString(f.Content)
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(content)
+	return &gitlab.Response{}, err
 }
 
 ///////////////////////////////
@@ -137,6 +171,68 @@ func TestGitHubListFilesRecursive_DefaultBranchFallback(t *testing.T) {
 	}
 }
 
+func TestGitHubListFilesRecursive_TruncatedTree(t *testing.T) {
+	entry := func(typ, path, sha string) *github.TreeEntry {
+		return &github.TreeEntry{Type: github.String(typ), Path: github.String(path), SHA: github.String(sha)}
+	}
+	git := &mockGitHubGit{trees: map[string]*github.Tree{
+		"main":      {Truncated: github.Bool(true), Entries: []*github.TreeEntry{entry("blob", "go.mod", "m")}},
+		"main:flat": {Entries: []*github.TreeEntry{entry("blob", "go.mod", "m"), entry("tree", "svc", "s")}},
+		"s":         {Entries: []*github.TreeEntry{entry("blob", "pyproject.toml", "p"), entry("tree", "lib", "l"), entry("blob", "lib/uv.lock", "u")}},
+	}}
+	client := &GitHubClient{api: GitHubAPI{Git: git}}
+
+	files, err := client.ListFilesRecursive(context.Background(), "owner", "repo", "main")
+	if err != nil {
+		t.Fatalf("ListFilesRecursive error: %v", err)
+	}
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	want := "go.mod,svc/pyproject.toml,svc/lib/uv.lock"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("paths = %s, want %s", got, want)
+	}
+	if git.calls != 3 {
+		t.Errorf("GetTree calls = %d, want 3", git.calls)
+	}
+}
+
+func TestGitHubListFilesUnder(t *testing.T) {
+	repos := &mockGitHubRepos{dirContents: map[string][]*github.RepositoryContent{
+		"services": {
+			{Type: github.String("dir"), Path: github.String("services/api"), SHA: github.String("api")},
+			{Type: github.String("dir"), Path: github.String("services/web"), SHA: github.String("web")},
+		},
+	}}
+	git := &mockGitHubGit{trees: map[string]*github.Tree{
+		"api": {Entries: []*github.TreeEntry{
+			{Type: github.String("blob"), Path: github.String("poetry.lock"), SHA: github.String("p")},
+		}},
+	}}
+	client := &GitHubClient{api: GitHubAPI{Repositories: repos, Git: git}}
+
+	files, err := client.ListFilesUnder(context.Background(), "owner", "repo", "main", "services/api/")
+	if err != nil {
+		t.Fatalf("ListFilesUnder error: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "services/api/poetry.lock" {
+		t.Fatalf("files = %+v, want services/api/poetry.lock", files)
+	}
+	if !strings.Contains(files[0].URL, "/blob/main/services/api/poetry.lock") {
+		t.Errorf("unexpected URL %s", files[0].URL)
+	}
+	if git.calls != 1 {
+		t.Errorf("GetTree calls = %d, want 1", git.calls)
+	}
+
+	files, err = client.ListFilesUnder(context.Background(), "owner", "repo", "main", "services/missing")
+	if err != nil || len(files) != 0 {
+		t.Errorf("missing directory: files = %+v, err = %v", files, err)
+	}
+}
+
 func TestGitHubListFiles_DirectoryListing(t *testing.T) {
 	dirContents := map[string][]*github.RepositoryContent{
 		"": {
@@ -220,6 +316,24 @@ func TestGitHubGetFileContent_Base64(t *testing.T) {
 // GitLab Client Tests
 ///////////////////////////////
 
+func TestGitLabListFilesUnder_PathOption(t *testing.T) {
+	repos := &mockGitLabRepos{pages: map[int][]*gitlab.TreeNode{
+		1: {{Type: "blob", Path: "services/api/uv.lock", Name: "uv.lock", ID: "sha1"}},
+	}}
+	client := &GitLabClient{api: GitLabAPI{Repositories: repos}}
+
+	files, err := client.ListFilesUnder(context.Background(), "group", "repo", "main", "/services/api/")
+	if err != nil {
+		t.Fatalf("ListFilesUnder error: %v", err)
+	}
+	if repos.path != "services/api" {
+		t.Errorf("Path option = %q, want services/api", repos.path)
+	}
+	if len(files) != 1 || files[0].Path != "services/api/uv.lock" {
+		t.Errorf("unexpected files %+v", files)
+	}
+}
+
 func TestGitLabListFilesRecursive_Pagination(t *testing.T) {
 	project := &gitlab.Project{
 		ID:                500,
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how repository clients retry transient provider
+// failures (5xx responses, rate limiting, network timeouts).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the
+	// first one. Values <= 1 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Subsequent delays
+	// double until MaxBackoff is reached.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts (including Retry-After hints).
+	MaxBackoff time.Duration
+
+	// RetryableStatusCodes lists HTTP status codes considered transient.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured:
+// 3 attempts, 500ms initial backoff capped at 10s, retrying 429 and 5xx
+// gateway/availability errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       500 * time.Millisecond,
+		MaxBackoff:           10 * time.Second,
+		RetryableStatusCodes: []int{429, 500, 502, 503, 504},
+	}
+}
+
+// Backoff returns the delay to wait after the given (1-based) failed attempt.
+// A Retry-After header on resp takes precedence when it asks for longer.
+func (p RetryPolicy) Backoff(attempt int, resp *http.Response) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			if hinted := time.Duration(secs) * time.Second; hinted > delay {
+				delay = hinted
+			}
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// ShouldRetry reports whether a request outcome is transient under this policy.
+func (p RetryPolicy) ShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	if resp == nil {
+		return false
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryEvent describes a single retry decision made by a repository client.
+type RetryEvent struct {
+	Scope       string        // Caller-supplied label (e.g. repository ID); see WithRetryObserver
+	Method      string        // HTTP method of the retried request
+	URL         string        // Request URL (query string stripped)
+	Attempt     int           // The attempt that failed (1-based)
+	MaxAttempts int           // Configured attempt budget
+	StatusCode  int           // HTTP status of the failed attempt (0 on transport error)
+	Err         error         // Transport error of the failed attempt (nil on HTTP status failure)
+	Delay       time.Duration // Backoff before the next attempt
+}
+
+// RetryObserver receives retry events; it must be safe for concurrent use.
+type RetryObserver func(RetryEvent)
+
+type retryObserverKey struct{}
+
+// WithRetryObserver returns a context that delivers retry events for requests
+// issued with it to obs. It replaces any observer already present in ctx;
+// callers that want to keep an outer observer should forward to it from obs
+// (see RetryObserverFromContext).
+func WithRetryObserver(ctx context.Context, obs RetryObserver) context.Context {
+	return context.WithValue(ctx, retryObserverKey{}, obs)
+}
+
+// RetryObserverFromContext returns the observer registered on ctx, or nil.
+func RetryObserverFromContext(ctx context.Context) RetryObserver {
+	obs, _ := ctx.Value(retryObserverKey{}).(RetryObserver)
+	return obs
+}
+
+// retryTransport is an http.RoundTripper applying a RetryPolicy.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+// newRetryTransport wraps base (http.DefaultTransport if nil) with policy.
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{base: base, policy: policy}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxAttempts := t.policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	ctx := req.Context()
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if hasBody && req.GetBody == nil {
+		// Body cannot be replayed, so the request is attempted exactly once.
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			r = req.Clone(ctx)
+			if hasBody {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= maxAttempts || !t.policy.ShouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.policy.Backoff(attempt, resp)
+		if obs := RetryObserverFromContext(ctx); obs != nil {
+			ev := RetryEvent{
+				Method:      req.Method,
+				URL:         redactURL(req),
+				Attempt:     attempt,
+				MaxAttempts: maxAttempts,
+				Err:         err,
+				Delay:       delay,
+			}
+			if resp != nil {
+				ev.StatusCode = resp.StatusCode
+			}
+			obs(ev)
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryHTTPClient returns an *http.Client applying policy, or nil when the
+// policy is absent or disables retries (callers then keep their default client).
+func retryHTTPClient(policy *RetryPolicy) *http.Client {
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return nil
+	}
+	return &http.Client{Transport: newRetryTransport(nil, *policy)}
+}
+
+// redactURL returns the request URL without query parameters (which may carry tokens).
+func redactURL(req *http.Request) string {
+	if req.URL == nil {
+		return ""
+	}
+	u := *req.URL
+	u.RawQuery = ""
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy(attempts int) RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.MaxAttempts = attempts
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.Backoff(tt.attempt, nil); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"30"}}}
+	if got := p.Backoff(1, resp); got != time.Second {
+		t.Errorf("Retry-After should be capped at MaxBackoff, got %v", got)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	p := DefaultRetryPolicy()
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{"503", &http.Response{StatusCode: 503}, nil, true},
+		{"429", &http.Response{StatusCode: 429}, nil, true},
+		{"404", &http.Response{StatusCode: 404}, nil, false},
+		{"200", &http.Response{StatusCode: 200}, nil, false},
+		{"canceled", nil, context.Canceled, false},
+		{"deadline", nil, context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		if got := p.ShouldRetry(tt.resp, tt.err); got != tt.want {
+			t.Errorf("%s: ShouldRetry = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryTransport_RecoversFromTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var events []RetryEvent
+	ctx := WithRetryObserver(context.Background(), func(ev RetryEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+	})
+
+	client := retryHTTPClient(&RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           5 * time.Millisecond,
+		RetryableStatusCodes: []int{502},
+	})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/x?private_token=secret", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 after retries, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 retry events, got %d", len(events))
+	}
+	if events[0].StatusCode != 502 || events[0].Attempt != 1 || events[1].Attempt != 2 {
+		t.Errorf("unexpected events: %+v", events)
+	}
+	if events[0].URL != srv.URL+"/x" {
+		t.Errorf("expected query string to be stripped, got %q", events[0].URL)
+	}
+}
+
+func TestRetryTransport_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := retryHTTPClient(ptr(fastRetryPolicy(2)))
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected final 503 to be returned, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := retryHTTPClient(ptr(fastRetryPolicy(5)))
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected a single attempt for 404, got %d", calls.Load())
+	}
+}
+
+func TestRetryHTTPClient_Disabled(t *testing.T) {
+	if retryHTTPClient(nil) != nil {
+		t.Error("expected nil client for nil policy")
+	}
+	if retryHTTPClient(ptr(fastRetryPolicy(1))) != nil {
+		t.Error("expected nil client when MaxAttempts <= 1")
+	}
+}
+
+func TestNewClientsWithRetryPolicy(t *testing.T) {
+	cfg := Config{Token: "t", Retry: ptr(fastRetryPolicy(3))}
+	if _, err := NewGitHubClient(cfg); err != nil {
+		t.Errorf("NewGitHubClient with retry policy: %v", err)
+	}
+	if _, err := NewGitLabClient(cfg); err != nil {
+		t.Errorf("NewGitLabClient with retry policy: %v", err)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/telemetry"
+)
+
+// telemetryTransport records a client span and a duration metric for every
+// request it carries (see telemetry.Setup)
+type telemetryTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *telemetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := telemetry.Tracer().Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", redactURL(req)),
+			attribute.String("server.address", req.URL.Hostname()),
+		))
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+	}
+	telemetry.RecordProviderRequest(ctx, time.Since(start), req.URL.Hostname(), req.Method, status)
+	telemetry.End(span, err)
+	return resp, err
+}
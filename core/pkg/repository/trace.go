@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redactedHeaders are replaced by "[redacted]" in traces
+var redactedHeaders = []string{"Authorization", "Private-Token", "Job-Token", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// rateLimitHeaders report the requests left in the rate limit window
+// (GitHub, then GitLab)
+var rateLimitHeaders = []string{"X-Ratelimit-Remaining", "Ratelimit-Remaining"}
+
+// HTTPTraceEntry is one provider API request seen by an HTTPTracer. URLs
+// carry no query string and credentials headers are redacted; bodies are
+// never recorded.
+type HTTPTraceEntry struct {
+	Started            time.Time
+	Method             string
+	URL                string
+	Status             int // 0 when no response was received
+	Duration           time.Duration
+	RateLimitRemaining string // Empty when the provider did not report it
+	RequestHeaders     http.Header
+	ResponseHeaders    http.Header
+	Error              string
+}
+
+// HTTPTracer records provider API requests for debugging: each is logged to
+// Logger (when set) and kept for WriteHAR. Set it on Config.Tracer; one
+// tracer may be shared by many clients.
+type HTTPTracer struct {
+	// Logger receives one Info record per request; nil disables logging
+	Logger *slog.Logger
+	// MaxEntries bounds the entries kept, dropping the oldest (0 = no limit)
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries []HTTPTraceEntry
+}
+
+// NewHTTPTracer creates a tracer logging to logger (nil records only)
+func NewHTTPTracer(logger *slog.Logger) *HTTPTracer {
+	return &HTTPTracer{Logger: logger}
+}
+
+// Entries returns the recorded requests, oldest first
+func (t *HTTPTracer) Entries() []HTTPTraceEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]HTTPTraceEntry(nil), t.entries...)
+}
+
+// record logs and stores one request
+func (t *HTTPTracer) record(e HTTPTraceEntry) {
+	if t.Logger != nil {
+		attrs := []any{"source", "http", "method", e.Method, "url", e.URL, "status", e.Status,
+			"duration", e.Duration.Round(time.Millisecond)}
+		if e.RateLimitRemaining != "" {
+			attrs = append(attrs, "rateLimitRemaining", e.RateLimitRemaining)
+		}
+		if e.Error != "" {
+			attrs = append(attrs, "error", e.Error)
+		}
+		t.Logger.Info("HTTP request", attrs...)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, e)
+	if t.MaxEntries > 0 && len(t.entries) > t.MaxEntries {
+		t.entries = append(t.entries[:0], t.entries[len(t.entries)-t.MaxEntries:]...)
+	}
+}
+
+// traceTransport reports every request it carries to a tracer
+type traceTransport struct {
+	base   http.RoundTripper
+	tracer *HTTPTracer
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e := HTTPTraceEntry{
+		Started:        time.Now(),
+		Method:         req.Method,
+		URL:            redactURL(req),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+	resp, err := t.base.RoundTrip(req)
+	e.Duration = time.Since(e.Started)
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if resp != nil {
+		e.Status = resp.StatusCode
+		e.ResponseHeaders = redactHeaders(resp.Header)
+		for _, h := range rateLimitHeaders {
+			if v := resp.Header.Get(h); v != "" {
+				e.RateLimitRemaining = v
+				break
+			}
+		}
+	}
+	t.tracer.record(e)
+	return resp, err
+}
+
+// redactHeaders returns a copy of h with credential headers redacted
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range redactedHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, "[redacted]")
+		}
+	}
+	return out
+}
+
+// HAR-like document written by WriteHAR (a subset of HTTP Archive 1.2)
+type (
+	harFile struct {
+		Log harLog `json:"log"`
+	}
+	harLog struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	}
+	harCreator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	harEntry struct {
+		StartedDateTime    time.Time   `json:"startedDateTime"`
+		Time               float64     `json:"time"` // Milliseconds
+		Request            harRequest  `json:"request"`
+		Response           harResponse `json:"response"`
+		RateLimitRemaining string      `json:"_rateLimitRemaining,omitempty"`
+		Error              string      `json:"_error,omitempty"`
+	}
+	harRequest struct {
+		Method  string      `json:"method"`
+		URL     string      `json:"url"`
+		Headers []harHeader `json:"headers"`
+	}
+	harResponse struct {
+		Status     int         `json:"status"`
+		StatusText string      `json:"statusText"`
+		Headers    []harHeader `json:"headers"`
+	}
+	harHeader struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+)
+
+// WriteHAR writes the recorded requests as an HTTP Archive (HAR 1.2) style
+// JSON document, without bodies, for attaching to bug reports. creator names
+// the program version recorded in the file.
+func (t *HTTPTracer) WriteHAR(w io.Writer, creator string) error {
+	doc := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "devdashboard", Version: creator},
+		Entries: []harEntry{},
+	}}
+	for _, e := range t.Entries() {
+		doc.Log.Entries = append(doc.Log.Entries, harEntry{
+			StartedDateTime:    e.Started,
+			Time:               float64(e.Duration.Microseconds()) / 1000,
+			Request:            harRequest{Method: e.Method, URL: e.URL, Headers: harHeaders(e.RequestHeaders)},
+			Response:           harResponse{Status: e.Status, StatusText: http.StatusText(e.Status), Headers: harHeaders(e.ResponseHeaders)},
+			RateLimitRemaining: e.RateLimitRemaining,
+			Error:              e.Error,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// harHeaders flattens h into name/value pairs sorted by name
+func harHeaders(h http.Header) []harHeader {
+	out := []harHeader{}
+	for name, values := range h {
+		for _, v := range values {
+			out = append(out, harHeader{Name: name, Value: v})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b harHeader) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPTracer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("Set-Cookie", "session=abc")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"secret":"body"}`))
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	tracer := NewHTTPTracer(slog.New(slog.NewTextHandler(&logs, nil)))
+	client, err := apiHTTPClient(Config{Tracer: tracer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/repos/o/r?access_token=secret", "/missing"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	entries := tracer.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	first := entries[0]
+	if first.URL != srv.URL+"/repos/o/r" || first.Status != http.StatusOK || first.RateLimitRemaining != "4999" {
+		t.Errorf("first entry = %+v", first)
+	}
+	if entries[1].Status != http.StatusNotFound {
+		t.Errorf("second entry status = %d, want 404", entries[1].Status)
+	}
+	if !strings.Contains(logs.String(), "rateLimitRemaining=4999") || !strings.Contains(logs.String(), "status=404") {
+		t.Errorf("log output missing request details:\n%s", logs.String())
+	}
+
+	var har bytes.Buffer
+	if err := tracer.WriteHAR(&har, "1.2.3"); err != nil {
+		t.Fatalf("WriteHAR: %v", err)
+	}
+	out := har.String() + logs.String()
+	for _, leaked := range []string{"secret", "session=abc", "body"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("trace output contains %q:\n%s", leaked, out)
+		}
+	}
+	var doc struct {
+		Log struct {
+			Creator struct{ Version string }
+			Entries []struct {
+				Request  struct{ Method, URL string }
+				Response struct{ Status int }
+			}
+		}
+	}
+	if err := json.Unmarshal(har.Bytes(), &doc); err != nil {
+		t.Fatalf("HAR is not valid JSON: %v", err)
+	}
+	if doc.Log.Creator.Version != "1.2.3" || len(doc.Log.Entries) != 2 || doc.Log.Entries[1].Response.Status != http.StatusNotFound {
+		t.Errorf("HAR document = %+v", doc)
+	}
+}
+
+func TestHTTPTracer_MaxEntries(t *testing.T) {
+	tracer := &HTTPTracer{MaxEntries: 2}
+	for _, u := range []string{"a", "b", "c"} {
+		tracer.record(HTTPTraceEntry{URL: u})
+	}
+	if entries := tracer.Entries(); len(entries) != 2 || entries[0].URL != "b" {
+		t.Errorf("entries = %+v, want the two newest", entries)
+	}
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// httpTransport returns the transport carrying cfg's API requests. Without a
+// proxy, CA file or InsecureSkipVerify it is http.DefaultTransport, which
+// honors HTTPS_PROXY/NO_PROXY; otherwise a copy of it with those settings.
+func httpTransport(cfg Config) (http.RoundTripper, error) {
+	if cfg.Proxy == "" && cfg.CAFile == "" && !cfg.InsecureSkipVerify {
+		return http.DefaultTransport, nil
+	}
+
+	var transport *http.Transport
+	if dt, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = dt.Clone()
+	} else {
+		transport = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+
+	if cfg.Proxy != "" {
+		proxy, err := url.Parse(cfg.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if cfg.CAFile != "" || cfg.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// #nosec G402 -- explicit opt-in for test instances, documented as unsafe
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		}
+		if cfg.CAFile != "" {
+			pool, err := caPool(cfg.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
+
+// caPool returns the system certificate pool extended with the PEM
+// certificates in path
+func caPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in CA file %s", path)
+	}
+	return pool, nil
+}
+
+// RequestTimeoutError reports an API request attempt that exceeded
+// Config.RequestTimeout. It is a net.Error whose Timeout is true, so retry
+// policies treat it like a network timeout, and deliberately does not wrap
+// context.DeadlineExceeded, which would stop retries.
+type RequestTimeoutError struct {
+	Method string
+	URL    string        // Query string stripped
+	Limit  time.Duration // The Config.RequestTimeout exceeded
+}
+
+func (e *RequestTimeoutError) Error() string {
+	return fmt.Sprintf("%s %s exceeded %s request timeout", e.Method, e.URL, e.Limit)
+}
+
+// Timeout implements net.Error.
+func (e *RequestTimeoutError) Timeout() bool { return true }
+
+// Temporary implements net.Error.
+func (e *RequestTimeoutError) Temporary() bool { return true }
+
+// timeoutTransport bounds each request, from sending it to closing its
+// response body, by timeout
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, t.timeoutError(ctx, req, err)
+	}
+	resp.Body = &timeoutBody{ReadCloser: resp.Body, transport: t, req: req, ctx: ctx, cancel: cancel}
+	return resp, nil
+}
+
+// timeoutError replaces err with a *RequestTimeoutError when the request's
+// own timeout, rather than the caller's context, expired
+func (t *timeoutTransport) timeoutError(ctx context.Context, req *http.Request, err error) error {
+	if req.Context().Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &RequestTimeoutError{Method: req.Method, URL: redactURL(req), Limit: t.timeout}
+	}
+	return err
+}
+
+// timeoutBody releases the request's timeout context when the body is closed
+type timeoutBody struct {
+	io.ReadCloser
+	transport *timeoutTransport
+	req       *http.Request
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = b.transport.timeoutError(b.ctx, b.req, err)
+	}
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
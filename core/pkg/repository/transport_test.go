@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAPIHTTPClient_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	badCA := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(badCA, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantErr   string // apiHTTPClient error
+		wantReach bool
+	}{
+		{name: "custom CA", cfg: Config{CAFile: caFile}, wantReach: true},
+		{name: "skip verify", cfg: Config{InsecureSkipVerify: true}, wantReach: true},
+		{name: "untrusted", cfg: Config{Retry: ptr(fastRetryPolicy(2))}},
+		{name: "missing CA file", cfg: Config{CAFile: filepath.Join(t.TempDir(), "missing.pem")}, wantErr: "failed to read CA file"},
+		{name: "CA file without certificates", cfg: Config{CAFile: badCA}, wantErr: "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := apiHTTPClient(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("apiHTTPClient: %v", err)
+			}
+			resp, err := client.Get(srv.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if reached := err == nil; reached != tt.wantReach {
+				t.Errorf("request error = %v, want reachable %v", err, tt.wantReach)
+			}
+		})
+	}
+}
+
+func TestAPIHTTPClient_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	client, err := apiHTTPClient(Config{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("apiHTTPClient: %v", err)
+	}
+	resp, err := client.Get("http://gitlab.example.invalid/api/v4/projects")
+	if err != nil {
+		t.Fatalf("request through proxy: %v", err)
+	}
+	_ = resp.Body.Close()
+	if proxied != "http://gitlab.example.invalid/api/v4/projects" {
+		t.Errorf("proxy received %q, want the API URL", proxied)
+	}
+
+	if _, err := apiHTTPClient(Config{Proxy: "://bad"}); err == nil {
+		t.Error("expected an error for an invalid proxy URL")
+	}
+}
+
+func TestAPIHTTPClient_RequestTimeout(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client, err := apiHTTPClient(Config{RequestTimeout: 50 * time.Millisecond, Retry: ptr(fastRetryPolicy(2))})
+	if err != nil {
+		t.Fatalf("apiHTTPClient: %v", err)
+	}
+
+	resp, err := client.Get(srv.URL + "/fast")
+	if err != nil {
+		t.Fatalf("fast request: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil || string(body) != "ok" {
+		t.Fatalf("fast response = %q, %v", body, err)
+	}
+
+	hits.Store(0)
+	_, err = client.Get(srv.URL + "/slow?token=secret")
+	var timeoutErr *RequestTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("error = %v, want a *RequestTimeoutError", err)
+	}
+	if want := "GET " + srv.URL + "/slow exceeded 50ms request timeout"; timeoutErr.Error() != want {
+		t.Errorf("error message = %q, want %q", timeoutErr.Error(), want)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("slow request sent %d times, want 2 (timeouts are retried)", n)
+	}
+}
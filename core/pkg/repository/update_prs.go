@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// UpdatePullRequest describes an open pull request (GitHub) or merge request
+// (GitLab) opened by a dependency-update bot such as Dependabot or Renovate.
+type UpdatePullRequest struct {
+	Number    int       // PR number (GitHub) or MR IID (GitLab)
+	Title     string    // PR/MR title
+	URL       string    // Web URL to the PR/MR
+	Author    string    // Author login (e.g. "dependabot[bot]")
+	Branch    string    // Source branch name
+	Package   string    // Package name parsed from the title/branch ("" if unknown)
+	CreatedAt time.Time // When the PR/MR was opened
+}
+
+// OpenDays returns the number of whole days the request has been open as of now.
+func (p UpdatePullRequest) OpenDays(now time.Time) int {
+	if p.CreatedAt.IsZero() || now.Before(p.CreatedAt) {
+		return 0
+	}
+	return int(now.Sub(p.CreatedAt).Hours() / 24)
+}
+
+// UpdatePullRequestLister is an optional capability implemented by clients
+// able to enumerate open dependency-update pull requests. Callers should
+// type-assert a Client against this interface before use.
+type UpdatePullRequestLister interface {
+	// ListUpdatePullRequests returns open PRs/MRs authored by known
+	// dependency-update bots for the given repository.
+	ListUpdatePullRequests(ctx context.Context, owner, repo string) ([]UpdatePullRequest, error)
+}
+
+// NewPullRequest describes a pull request (GitHub) or merge request (GitLab)
+// to open from Head into Base.
+type NewPullRequest struct {
+	Title string
+	Body  string
+	Head  string // Source branch
+	Base  string // Target branch
+}
+
+// PullRequestCreator is an optional capability implemented by clients able
+// to open pull requests. Callers should type-assert a Client against this
+// interface before use.
+type PullRequestCreator interface {
+	// CreatePullRequest opens pr in the given repository and returns it.
+	CreatePullRequest(ctx context.Context, owner, repo string, pr NewPullRequest) (UpdatePullRequest, error)
+}
+
+// updateBotMarkers are substrings identifying dependency-update bots in author
+// logins or branch prefixes. "devdashboard" matches the branches of the bump
+// command.
+var updateBotMarkers = []string{"dependabot", "renovate", "devdashboard"}
+
+// isUpdateBotRequest reports whether the author or source branch indicates a
+// dependency-update bot.
+func isUpdateBotRequest(author, branch string) bool {
+	a := strings.ToLower(author)
+	b := strings.ToLower(branch)
+	for _, m := range updateBotMarkers {
+		if strings.Contains(a, m) || strings.HasPrefix(b, m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// "Bump django from 3.2 to 4.2", "build(deps): bump requests from 2.0 to 2.31 in /app"
+	dependabotTitleRe = regexp.MustCompile(`(?i)\bbump\s+(\S+)\s+from\s`)
+	// "Update dependency django to v4.2", "chore(deps): update module github.com/x/y to v1.2"
+	renovateTitleRe = regexp.MustCompile(`(?i)\bupdate\s+(?:dependency|module)\s+(\S+)\s+to\s`)
+	// trailing "-4.2.1" / "-4.x" version suffix on bot branches
+	branchVersionSuffixRe = regexp.MustCompile(`-v?\d[\w.]*$`)
+)
+
+// parseUpdatePackage extracts the package name targeted by a bot PR/MR from its
+// title, falling back to the branch name. Returns "" when no name can be found.
+func parseUpdatePackage(title, branch string) string {
+	if m := dependabotTitleRe.FindStringSubmatch(title); m != nil {
+		return strings.Trim(m[1], "`\"'")
+	}
+	if m := renovateTitleRe.FindStringSubmatch(title); m != nil {
+		return strings.Trim(m[1], "`\"'")
+	}
+
+	// Branch fallback: dependabot/<ecosystem>/<pkg>-<version>, renovate/<pkg>-<version>
+	lower := strings.ToLower(branch)
+	var rest string
+	switch {
+	case strings.HasPrefix(lower, "dependabot/"):
+		parts := strings.SplitN(branch, "/", 3)
+		if len(parts) < 3 {
+			return ""
+		}
+		rest = parts[2]
+	case strings.HasPrefix(lower, "renovate/"):
+		rest = branch[len("renovate/"):]
+	default:
+		return ""
+	}
+	return branchVersionSuffixRe.ReplaceAllString(rest, "")
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+type mockGitHubPulls struct {
+	prs     []*github.PullRequest
+	created *github.NewPullRequest
+}
+
+func (m *mockGitHubPulls) List(_ context.Context, _, _ string, _ *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	return m.prs, &github.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
+}
+
+func (m *mockGitHubPulls) Create(_ context.Context, _, _ string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	m.created = pull
+	return &github.PullRequest{
+		Number:  github.Int(12),
+		Title:   pull.Title,
+		HTMLURL: github.String("https://github.com/o/r/pull/12"),
+		User:    &github.User{Login: github.String("ci-bot")},
+	}, &github.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
+}
+
+type mockGitLabMRs struct {
+	mrs     []*gitlab.BasicMergeRequest
+	created *gitlab.CreateMergeRequestOptions
+}
+
+func (m *mockGitLabMRs) ListProjectMergeRequests(_ any, _ *gitlab.ListProjectMergeRequestsOptions, _ ...gitlab.RequestOptionFunc) ([]*gitlab.BasicMergeRequest, *gitlab.Response, error) {
+	return m.mrs, &gitlab.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
+}
+
+func (m *mockGitLabMRs) CreateMergeRequest(_ any, opt *gitlab.CreateMergeRequestOptions, _ ...gitlab.RequestOptionFunc) (*gitlab.MergeRequest, *gitlab.Response, error) {
+	m.created = opt
+	mr := &gitlab.MergeRequest{}
+	mr.IID = 5
+	mr.Title = *opt.Title
+	mr.WebURL = "https://gitlab.com/g/p/-/merge_requests/5"
+	return mr, &gitlab.Response{Response: &http.Response{Body: io.NopCloser(strings.NewReader(""))}}, nil
+}
+
+func TestParseUpdatePackage(t *testing.T) {
+	tests := []struct {
+		title  string
+		branch string
+		want   string
+	}{
+		{"Bump django from 3.2.1 to 4.2.0", "", "django"},
+		{"build(deps): bump requests from 2.25.0 to 2.31.0 in /app", "", "requests"},
+		{"Update dependency urllib3 to v2.0.7", "", "urllib3"},
+		{"chore(deps): update module github.com/spf13/cobra to v1.8.0", "", "github.com/spf13/cobra"},
+		{"Some manual change", "dependabot/pip/pyyaml-6.0.1", "pyyaml"},
+		{"Lock file maintenance", "renovate/flask-3.x", "flask"},
+		{"Update all non-major dependencies", "renovate/all-minor-patch", "all-minor-patch"},
+		{"Random PR", "feature/foo", ""},
+	}
+	for _, tt := range tests {
+		if got := parseUpdatePackage(tt.title, tt.branch); got != tt.want {
+			t.Errorf("parseUpdatePackage(%q, %q) = %q, want %q", tt.title, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestIsUpdateBotRequest(t *testing.T) {
+	if !isUpdateBotRequest("dependabot[bot]", "") {
+		t.Error("expected dependabot author to match")
+	}
+	if !isUpdateBotRequest("someone", "renovate/django-4.x") {
+		t.Error("expected renovate branch to match")
+	}
+	if !isUpdateBotRequest("alice", "devdashboard/bump-django-4.2.11") {
+		t.Error("expected devdashboard bump branch to match")
+	}
+	if isUpdateBotRequest("alice", "feature/renovate-ui") {
+		t.Error("did not expect human PR to match")
+	}
+}
+
+func TestUpdatePullRequestOpenDays(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	pr := UpdatePullRequest{CreatedAt: now.Add(-72*time.Hour - time.Minute)}
+	if got := pr.OpenDays(now); got != 3 {
+		t.Errorf("OpenDays = %d, want 3", got)
+	}
+	if got := (UpdatePullRequest{}).OpenDays(now); got != 0 {
+		t.Errorf("OpenDays for zero CreatedAt = %d, want 0", got)
+	}
+}
+
+func TestGitHubListUpdatePullRequests_FiltersBots(t *testing.T) {
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	prs := []*github.PullRequest{
+		{
+			Number:    github.Int(7),
+			Title:     github.String("Bump django from 3.2 to 4.2"),
+			HTMLURL:   github.String("https://github.com/o/r/pull/7"),
+			User:      &github.User{Login: github.String("dependabot[bot]")},
+			Head:      &github.PullRequestBranch{Ref: github.String("dependabot/pip/django-4.2")},
+			CreatedAt: &github.Timestamp{Time: created},
+		},
+		{
+			Number: github.Int(8),
+			Title:  github.String("Add feature"),
+			User:   &github.User{Login: github.String("alice")},
+			Head:   &github.PullRequestBranch{Ref: github.String("feature/x")},
+		},
+	}
+
+	client := &GitHubClient{api: GitHubAPI{PullRequests: &mockGitHubPulls{prs: prs}}}
+	got, err := client.ListUpdatePullRequests(context.Background(), "o", "r")
+	if err != nil {
+		t.Fatalf("ListUpdatePullRequests error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 bot PR, got %d", len(got))
+	}
+	if got[0].Package != "django" || got[0].Number != 7 || !got[0].CreatedAt.Equal(created) {
+		t.Errorf("unexpected PR: %+v", got[0])
+	}
+}
+
+func TestGitLabListUpdatePullRequests_FiltersBots(t *testing.T) {
+	created := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	mrs := []*gitlab.BasicMergeRequest{
+		{
+			IID:          3,
+			Title:        "Update dependency requests to v2.31.0",
+			WebURL:       "https://gitlab.com/g/p/-/merge_requests/3",
+			SourceBranch: "renovate/requests-2.x",
+			Author:       &gitlab.BasicUser{Username: "renovate-bot"},
+			CreatedAt:    &created,
+		},
+		{
+			IID:          4,
+			Title:        "Refactor",
+			SourceBranch: "refactor",
+			Author:       &gitlab.BasicUser{Username: "bob"},
+		},
+	}
+
+	client := &GitLabClient{api: GitLabAPI{MergeRequests: &mockGitLabMRs{mrs: mrs}}}
+	got, err := client.ListUpdatePullRequests(context.Background(), "g", "p")
+	if err != nil {
+		t.Fatalf("ListUpdatePullRequests error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 bot MR, got %d", len(got))
+	}
+	if got[0].Package != "requests" || got[0].Number != 3 {
+		t.Errorf("unexpected MR: %+v", got[0])
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	pr := NewPullRequest{Title: "Bump django from 3.2 to 4.2.11", Body: "body", Head: "devdashboard/bump-django-4.2.11", Base: "main"}
+
+	pulls := &mockGitHubPulls{}
+	gh := &GitHubClient{api: GitHubAPI{PullRequests: pulls}}
+	got, err := gh.CreatePullRequest(context.Background(), "o", "r", pr)
+	if err != nil {
+		t.Fatalf("GitHub CreatePullRequest error: %v", err)
+	}
+	if got.Number != 12 || got.Package != "django" || got.Branch != pr.Head {
+		t.Errorf("unexpected GitHub PR: %+v", got)
+	}
+	if pulls.created.GetHead() != pr.Head || pulls.created.GetBase() != "main" {
+		t.Errorf("unexpected GitHub request: %+v", pulls.created)
+	}
+
+	mrs := &mockGitLabMRs{}
+	gl := &GitLabClient{api: GitLabAPI{MergeRequests: mrs}}
+	got, err = gl.CreatePullRequest(context.Background(), "g", "p", pr)
+	if err != nil {
+		t.Fatalf("GitLab CreatePullRequest error: %v", err)
+	}
+	if got.Number != 5 || got.Package != "django" {
+		t.Errorf("unexpected GitLab MR: %+v", got)
+	}
+	if *mrs.created.SourceBranch != pr.Head || *mrs.created.TargetBranch != "main" {
+		t.Errorf("unexpected GitLab request: %+v", mrs.created)
+	}
+}
@@ -4,10 +4,10 @@ package services
 
 // Dependency service scaffold providing an abstraction for running
 // dependency reports asynchronously with progress streaming.
-// This initial version wraps the existing report.Generator and
-// simulates per-repository progress events. In a future phase,
-// deeper integration can emit granular phases (download, analyze,
-// aggregate, etc.).
+// It wraps report.Generator and streams each repository's start and
+// result as the generator reports them (see report.Generator.SetObserver).
+// In a future phase, deeper integration can emit granular phases
+// (download, analyze, aggregate, etc.).
 //
 // Module path note: internal imports use the renamed core module
 // path: github.com/greg-hellings/devdashboard/core/...
@@ -21,10 +21,8 @@ package services
 //    rpt, err := handle.Result()
 //
 // Future Enhancements:
-//  - Real-time per-repository analysis progress from report.Generator internals
 //  - Cancellation propagation for individual repository tasks
 //  - Metrics / durations per repository
-//  - Retry support for transient failures
 //  - Progress phases for discovery vs. analysis vs. aggregation
 //
 // TODO: Integrate granular stage-level progress (e.g., fetch metadata,
@@ -44,6 +42,7 @@ import (
 
 	"github.com/greg-hellings/devdashboard/core/pkg/config"
 	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
 )
 
 // ProgressPhase represents lifecycle phases for repository analysis.
@@ -61,25 +60,76 @@ const (
 	PhaseError ProgressPhase = "error"
 	// PhaseAggregate represents synthetic aggregate events (overall report assembly).
 	PhaseAggregate ProgressPhase = "aggregate" // overall report assembly
+	// PhaseRetry indicates a transient provider error is being retried (repo still running).
+	PhaseRetry ProgressPhase = "retry"
 )
 
 // ReportProgress conveys status updates for a single repository (or aggregate).
 type ReportProgress struct {
 	RepoID    string        // Provider:Owner/Repo@Ref (empty for aggregate events)
 	Phase     ProgressPhase // Current phase
-	Error     error         // Non-nil if PhaseError
+	Error     error         // Non-nil if PhaseError; failure being retried if PhaseRetry
+	Attempt   int           // Failed attempt number (PhaseRetry only)
+	Anonymous bool          // The repository is read without a token (PhaseRunning, PhaseComplete, PhaseError)
 	Timestamp time.Time     // Event emission time
 }
 
 // ReportOptions defines tunable behavior for a report run.
 type ReportOptions struct {
-	// Concurrency hint for future expansion (currently ignored, generator manages itself).
+	// Concurrency limits how many repositories are analyzed at once (see
+	// report.Generator.SetConcurrency). Zero analyzes all in parallel.
 	Concurrency int
 
 	// EmitAggregateEvents controls whether aggregate start/finish progress events are sent.
 	EmitAggregateEvents bool
 
-	// Reserved for future caching / retry strategy, etc.
+	// Previous enables incremental analysis: repositories whose commit is
+	// unchanged since this snapshot reuse its results (see
+	// report.Generator.SetPrevious). Nil analyzes everything.
+	Previous *report.Snapshot
+
+	// IncludeGraph populates each repository's dependency graph (see
+	// report.Generator.SetIncludeGraph)
+	IncludeGraph bool
+
+	// UseArchives reads each repository from one archive download where the
+	// provider supports it (see report.Generator.SetUseArchives)
+	UseArchives bool
+
+	// ContentCache serves files of unchanged commits from disk (see
+	// report.Generator.SetContentCache). Nil fetches everything.
+	ContentCache *repository.ContentCache
+
+	// ExcludeDev leaves development dependencies out of the report (see
+	// report.Generator.SetExcludeDev)
+	ExcludeDev bool
+
+	// IncludeHashes populates each repository's artifact digests (see
+	// report.Generator.SetIncludeHashes)
+	IncludeHashes bool
+
+	// Policies are checked against each analyzed repository (see
+	// report.Generator.SetPolicies). Empty keeps the generator's own.
+	Policies []report.Policy
+
+	// IgnoreRules annotate or suppress accepted findings (see
+	// report.Generator.SetIgnoreRules). Empty keeps the generator's own.
+	IgnoreRules []report.IgnoreRule
+
+	// HTTPTracer records the run's provider API requests (see
+	// report.Generator.SetHTTPTracer). Nil disables tracing.
+	HTTPTracer *repository.HTTPTracer
+
+	// Fixtures record or replay the run's provider API responses (see
+	// report.Generator.SetFixtures). Nil uses the network as usual.
+	Fixtures *repository.Fixtures
+
+	// RepositoryTimeout and RequestTimeout bound each repository's analysis
+	// and each provider API request (see report.Generator.SetRepositoryTimeout
+	// and SetRequestTimeout). Zero keeps the generator's own, e.g. from the
+	// configuration's timeouts (see report.NewGeneratorFromConfig).
+	RepositoryTimeout time.Duration
+	RequestTimeout    time.Duration
 }
 
 // ResultHandle provides access to the final report.
@@ -116,6 +166,9 @@ type DependencyService interface {
 // dependencyService is the default implementation.
 type dependencyService struct {
 	generator *report.Generator
+	// defaults holds the generator's own settings, used for options left
+	// zero so one run's options do not carry over to the next
+	defaults ReportOptions
 }
 
 // NewDependencyService constructs a DependencyService.
@@ -124,19 +177,41 @@ func NewDependencyService(gen *report.Generator) DependencyService {
 	if gen == nil {
 		gen = report.NewGenerator()
 	}
-	return &dependencyService{generator: gen}
+	return &dependencyService{
+		generator: gen,
+		defaults: ReportOptions{
+			Policies:          gen.Policies(),
+			IgnoreRules:       gen.IgnoreRules(),
+			RepositoryTimeout: gen.RepositoryTimeout(),
+			RequestTimeout:    gen.RequestTimeout(),
+		},
+	}
+}
+
+// withDefaults fills the options opts leaves zero from the generator's own
+// settings
+func (s *dependencyService) withDefaults(opts ReportOptions) ReportOptions {
+	if len(opts.Policies) == 0 {
+		opts.Policies = s.defaults.Policies
+	}
+	if len(opts.IgnoreRules) == 0 {
+		opts.IgnoreRules = s.defaults.IgnoreRules
+	}
+	if opts.RepositoryTimeout == 0 {
+		opts.RepositoryTimeout = s.defaults.RepositoryTimeout
+	}
+	if opts.RequestTimeout == 0 {
+		opts.RequestTimeout = s.defaults.RequestTimeout
+	}
+	return opts
 }
 
 // RunReport launches the report generation asynchronously.
 // Progress emission strategy:
 //  1. Emit PhaseQueued for each repo.
-//  2. Emit PhaseRunning for each repo just before starting the global generator (simulated).
-//  3. Call generator.Generate once (current implementation is aggregate).
-//  4. For each successful repo in final report emit PhaseComplete.
-//     For each failed repo emit PhaseError.
-//  5. Optionally emit aggregate start/finish events if opts.EmitAggregateEvents.
-//
-// NOTE: This is a coarse-grained simulation until deeper hooks are available.
+//  2. Call generator.Generate once; as each repo starts emit PhaseRunning,
+//     and as it finishes emit PhaseComplete, or PhaseError if it failed.
+//  3. Optionally emit aggregate start/finish events if opts.EmitAggregateEvents.
 func (s *dependencyService) RunReport(
 	ctx context.Context,
 	repos []config.RepoWithProvider,
@@ -145,6 +220,7 @@ func (s *dependencyService) RunReport(
 	if len(repos) == 0 {
 		return nil, nil, errors.New("no repositories provided")
 	}
+	opts = s.withDefaults(opts)
 
 	progressCh := make(chan ReportProgress, len(repos)*4) // buffer heuristic
 
@@ -186,20 +262,36 @@ func (s *dependencyService) RunReport(
 			}
 		}
 
-		// Emit running (simulated)
-		for _, id := range repoIDs {
+		// Perform actual generation (single aggregate call), streaming each
+		// repository's start and result and retries of transient provider
+		// errors as they happen.
+		genCtx := repository.WithRetryObserver(ctx, func(ev repository.RetryEvent) {
 			select {
 			case <-ctx.Done():
-				handle.mu.Lock()
-				handle.err = ctx.Err()
-				handle.mu.Unlock()
-				return
-			case progressCh <- ReportProgress{RepoID: id, Phase: PhaseRunning, Timestamp: time.Now()}:
+			case progressCh <- retryProgress(ev):
 			}
-		}
-
-		// Perform actual generation (single aggregate call)
-		rpt, genErr := s.generator.Generate(ctx, repos)
+		})
+		s.generator.SetObserver(func(ev report.RepositoryEvent) {
+			select {
+			case <-ctx.Done():
+			case progressCh <- repositoryProgress(ev):
+			}
+		})
+		defer s.generator.SetObserver(nil)
+		s.generator.SetPrevious(opts.Previous)
+		s.generator.SetIncludeGraph(opts.IncludeGraph)
+		s.generator.SetIncludeHashes(opts.IncludeHashes)
+		s.generator.SetExcludeDev(opts.ExcludeDev)
+		s.generator.SetUseArchives(opts.UseArchives)
+		s.generator.SetContentCache(opts.ContentCache)
+		s.generator.SetPolicies(opts.Policies)
+		s.generator.SetIgnoreRules(opts.IgnoreRules)
+		s.generator.SetHTTPTracer(opts.HTTPTracer)
+		s.generator.SetFixtures(opts.Fixtures)
+		s.generator.SetConcurrency(opts.Concurrency)
+		s.generator.SetRepositoryTimeout(opts.RepositoryTimeout)
+		s.generator.SetRequestTimeout(opts.RequestTimeout)
+		rpt, genErr := s.generator.Generate(genCtx, repos)
 
 		handle.mu.Lock()
 		handle.report = rpt
@@ -228,35 +320,43 @@ func (s *dependencyService) RunReport(
 			return
 		}
 
-		// Emit completion/error per repository from final report.
-		if rpt != nil {
-			now := time.Now()
-			for _, rr := range rpt.Repositories {
-				id := fmt.Sprintf("%s:%s/%s@%s", rr.Provider, rr.Owner, rr.Repository, rr.Ref)
-				if rr.Error != nil {
-					progressCh <- ReportProgress{
-						RepoID:    id,
-						Phase:     PhaseError,
-						Error:     rr.Error,
-						Timestamp: now,
-					}
-				} else {
-					progressCh <- ReportProgress{
-						RepoID:    id,
-						Phase:     PhaseComplete,
-						Timestamp: now,
-					}
-				}
-			}
-			if opts.EmitAggregateEvents {
-				progressCh <- ReportProgress{
-					RepoID:    "",
-					Phase:     PhaseComplete,
-					Timestamp: time.Now(),
-				}
+		if rpt != nil && opts.EmitAggregateEvents {
+			progressCh <- ReportProgress{
+				RepoID:    "",
+				Phase:     PhaseComplete,
+				Timestamp: time.Now(),
 			}
 		}
 	}()
 
 	return progressCh, handle, nil
 }
+
+// repositoryProgress converts a generator repository event into a
+// PhaseRunning, PhaseComplete or PhaseError progress event.
+func repositoryProgress(ev report.RepositoryEvent) ReportProgress {
+	p := ReportProgress{RepoID: ev.Key, Phase: PhaseRunning, Anonymous: ev.Anonymous, Timestamp: time.Now()}
+	if ev.Done {
+		p.Phase = PhaseComplete
+		if ev.Report.Error != nil {
+			p.Phase = PhaseError
+			p.Error = ev.Report.Error
+		}
+	}
+	return p
+}
+
+// retryProgress converts a repository retry event into a PhaseRetry progress event.
+func retryProgress(ev repository.RetryEvent) ReportProgress {
+	cause := ev.Err
+	if cause == nil {
+		cause = fmt.Errorf("HTTP %d", ev.StatusCode)
+	}
+	return ReportProgress{
+		RepoID:    ev.Scope,
+		Phase:     PhaseRetry,
+		Error:     fmt.Errorf("%s %s attempt %d/%d failed, retrying in %s: %w", ev.Method, ev.URL, ev.Attempt, ev.MaxAttempts, ev.Delay, cause),
+		Attempt:   ev.Attempt,
+		Timestamp: time.Now(),
+	}
+}
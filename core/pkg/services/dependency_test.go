@@ -3,11 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/config"
 	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
 )
 
 func TestNewDependencyService(t *testing.T) {
@@ -79,6 +82,69 @@ func TestDependencyService_RunReport_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestDependencyService_RunReport_StreamsRepositoryProgress(t *testing.T) {
+	svc := NewDependencyService(nil)
+	repos := []config.RepoWithProvider{
+		{Provider: "invalid-provider", Config: config.RepoConfig{Owner: "o", Repository: "r", Ref: "main", Analyzer: "poetry"}},
+	}
+	ch, handle, err := svc.RunReport(context.Background(), repos, ReportOptions{})
+	if err != nil {
+		t.Fatalf("RunReport: %v", err)
+	}
+	var phases []ProgressPhase
+	for p := range ch {
+		if p.RepoID != "invalid-provider:o/r@main" {
+			t.Errorf("unexpected RepoID %q", p.RepoID)
+		}
+		phases = append(phases, p.Phase)
+	}
+	if got := fmt.Sprint(phases); got != "[queued running error]" {
+		t.Errorf("phases = %s, want [queued running error]", got)
+	}
+	if _, err := handle.Result(); err != nil {
+		t.Errorf("Result: %v", err)
+	}
+}
+
+func TestDependencyService_RunReport_KeepsGeneratorSettings(t *testing.T) {
+	gen, err := report.NewGeneratorFromConfig(&config.Config{
+		Timeouts: &config.TimeoutsConfig{Repository: time.Minute, Request: 10 * time.Second},
+		Policies: []config.PolicyConfig{{Name: "pin", Package: "django", Version: ">=4.2"}},
+		Ignores:  []config.IgnoreConfig{{Package: "django", Reason: "accepted"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewDependencyService(gen)
+	repos := []config.RepoWithProvider{
+		{Provider: "invalid-provider", Config: config.RepoConfig{Owner: "o", Repository: "r", Ref: "main", Analyzer: "poetry"}},
+	}
+	for range 2 {
+		_, handle, err := svc.RunReport(context.Background(), repos, ReportOptions{})
+		if err != nil {
+			t.Fatalf("RunReport: %v", err)
+		}
+		if _, err := handle.Result(); err != nil {
+			t.Fatalf("Result: %v", err)
+		}
+		if gen.RepositoryTimeout() != time.Minute || gen.RequestTimeout() != 10*time.Second {
+			t.Errorf("timeouts = %s/%s, want the configured 1m0s/10s", gen.RepositoryTimeout(), gen.RequestTimeout())
+		}
+		if len(gen.Policies()) != 1 || len(gen.IgnoreRules()) != 1 {
+			t.Errorf("policies %v, ignore rules %v, want the configured ones", gen.Policies(), gen.IgnoreRules())
+		}
+	}
+
+	_, handle, err := svc.RunReport(context.Background(), repos, ReportOptions{RepositoryTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("RunReport: %v", err)
+	}
+	_, _ = handle.Result()
+	if gen.RepositoryTimeout() != time.Second || gen.RequestTimeout() != 10*time.Second {
+		t.Errorf("timeouts = %s/%s, want the option over the configured request timeout", gen.RepositoryTimeout(), gen.RequestTimeout())
+	}
+}
+
 func TestResultHandle_Done(t *testing.T) {
 	repos := []config.RepoWithProvider{
 		{
@@ -117,6 +183,7 @@ func TestProgressPhaseConstants(t *testing.T) {
 		PhaseComplete,
 		PhaseError,
 		PhaseAggregate,
+		PhaseRetry,
 	}
 
 	for _, phase := range phases {
@@ -211,3 +278,28 @@ func TestResultHandle_MultipleCalls(t *testing.T) {
 		t.Error("expected same error from multiple calls")
 	}
 }
+
+func TestRetryProgress(t *testing.T) {
+	p := retryProgress(repository.RetryEvent{
+		Scope:       "github:owner/repo@main",
+		Method:      "GET",
+		URL:         "https://api.github.com/repos/owner/repo",
+		Attempt:     2,
+		MaxAttempts: 3,
+		StatusCode:  503,
+		Delay:       time.Second,
+	})
+
+	if p.Phase != PhaseRetry {
+		t.Errorf("expected PhaseRetry, got %s", p.Phase)
+	}
+	if p.RepoID != "github:owner/repo@main" {
+		t.Errorf("unexpected RepoID: %s", p.RepoID)
+	}
+	if p.Attempt != 2 {
+		t.Errorf("expected attempt 2, got %d", p.Attempt)
+	}
+	if p.Error == nil || !strings.Contains(p.Error.Error(), "HTTP 503") {
+		t.Errorf("expected error mentioning HTTP 503, got %v", p.Error)
+	}
+}
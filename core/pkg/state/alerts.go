@@ -0,0 +1,216 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// Alert rule kinds (AlertRule.Kind)
+const (
+	// AlertNewError fires for each repository that fails analysis after
+	// succeeding in the previous report
+	AlertNewError = "new-error"
+	// AlertVersionChange fires for each repository whose locked version of
+	// AlertRule.Package changed
+	AlertVersionChange = "version-change"
+	// AlertDrift fires when a package (AlertRule.Package, or any tracked
+	// package when empty) comes to be locked at more than AlertRule.Threshold
+	// distinct versions
+	AlertDrift = "drift"
+)
+
+// AlertKinds lists the supported AlertRule kinds
+var AlertKinds = []string{AlertNewError, AlertVersionChange, AlertDrift}
+
+// MaxAlerts caps Alerts; the oldest entries are dropped first.
+const MaxAlerts = 200
+
+// AlertRule is a condition checked after each background refresh (see
+// EvaluateAlertRules)
+type AlertRule struct {
+	Kind string `yaml:"kind"`
+	// Package is the package a version-change rule watches, or a drift rule
+	// is limited to
+	Package string `yaml:"package,omitempty"`
+	// Threshold is the number of distinct versions a drift rule tolerates;
+	// 0 fires on any drift
+	Threshold int `yaml:"threshold,omitempty"`
+	// Disabled rules are kept but not evaluated
+	Disabled bool `yaml:"disabled,omitempty"`
+}
+
+// Validate checks the rule's kind and the fields it requires
+func (r AlertRule) Validate() error {
+	switch r.Kind {
+	case AlertNewError:
+	case AlertVersionChange:
+		if r.Package == "" {
+			return errors.New("a version-change rule needs a package")
+		}
+	case AlertDrift:
+		if r.Threshold < 0 {
+			return errors.New("drift threshold must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown alert rule kind %q", r.Kind)
+	}
+	return nil
+}
+
+// String describes the rule, e.g. "django drift exceeds 2 versions"
+func (r AlertRule) String() string {
+	switch r.Kind {
+	case AlertNewError:
+		return "New repository error"
+	case AlertVersionChange:
+		return r.Package + " changed version"
+	case AlertDrift:
+		subject := "Any package"
+		if r.Package != "" {
+			subject = r.Package
+		}
+		return fmt.Sprintf("%s drift exceeds %d version(s)", subject, r.driftLimit())
+	}
+	return r.Kind
+}
+
+// driftLimit is the number of distinct versions a drift rule tolerates
+func (r AlertRule) driftLimit() int {
+	return max(r.Threshold, 1)
+}
+
+// Alert is a fired AlertRule, shown in the GUI's Alerts view until
+// acknowledged
+type Alert struct {
+	// Key identifies the rule and subject (repository, package) so a repeat
+	// can be matched against earlier alerts
+	Key     string    `yaml:"key"`
+	Rule    string    `yaml:"rule"` // AlertRule.String of the rule that fired
+	Message string    `yaml:"message"`
+	Time    time.Time `yaml:"time"`
+	RunID   string    `yaml:"runId,omitempty"` // Report run that fired it
+
+	Acknowledged bool `yaml:"acknowledged,omitempty"`
+	// SnoozedUntil silences repeats of the alert (same Key) until then
+	SnoozedUntil time.Time `yaml:"snoozedUntil,omitempty"`
+}
+
+// EvaluateAlertRules returns the alerts next fires compared with prev, the
+// previous report: rules describe changes, so a nil prev fires nothing.
+// Disabled and invalid rules are skipped. Alerts carry Key, Rule and
+// Message; RecordAlerts timestamps them.
+func EvaluateAlertRules(rules []AlertRule, prev, next *report.Report) []Alert {
+	if prev == nil || next == nil {
+		return nil
+	}
+	before := make(map[string]*report.RepositoryReport, len(prev.Repositories))
+	for i := range prev.Repositories {
+		before[prev.Repositories[i].Key()] = &prev.Repositories[i]
+	}
+	var prevMatrix, nextMatrix *report.VersionMatrix
+
+	var alerts []Alert
+	for _, rule := range rules {
+		if rule.Disabled || rule.Validate() != nil {
+			continue
+		}
+		fire := func(subject, message string) {
+			alerts = append(alerts, Alert{Key: rule.Kind + ":" + rule.Package + ":" + subject, Rule: rule.String(), Message: message})
+		}
+		switch rule.Kind {
+		case AlertNewError:
+			for i := range next.Repositories {
+				rr := &next.Repositories[i]
+				if old, ok := before[rr.Key()]; ok && old.Error == nil && rr.Error != nil {
+					fire(rr.Key(), fmt.Sprintf("%s failed: %s", rr.Key(), rr.Error.Message))
+				}
+			}
+		case AlertVersionChange:
+			pkg := next.ResolvePackage(rule.Package)
+			for i := range next.Repositories {
+				rr := &next.Repositories[i]
+				old, ok := before[rr.Key()]
+				if !ok || old.Error != nil || rr.Error != nil {
+					continue
+				}
+				from, to := old.Dependencies[prev.ResolvePackage(rule.Package)], rr.Dependencies[pkg]
+				if from != "" && to != "" && from != to {
+					fire(rr.Key(), fmt.Sprintf("%s: %s changed from %s to %s", rr.Key(), pkg, from, to))
+				}
+			}
+		case AlertDrift:
+			if nextMatrix == nil {
+				prevMatrix, nextMatrix = prev.Matrix(), next.Matrix()
+			}
+			packages := next.Packages
+			if rule.Package != "" {
+				packages = []string{next.ResolvePackage(rule.Package)}
+			}
+			for _, pkg := range packages {
+				versions := nextMatrix.PackageVersions(pkg).Sorted
+				if len(versions) <= rule.driftLimit() || len(prevMatrix.PackageVersions(prev.ResolvePackage(pkg)).Sorted) > rule.driftLimit() {
+					continue
+				}
+				fire(pkg, fmt.Sprintf("%s is locked at %d versions: %v", pkg, len(versions), versions))
+			}
+		}
+	}
+	return alerts
+}
+
+// RecordAlerts appends fired alerts to Alerts, timestamped now and tagged
+// with runID, keeping at most MaxAlerts. Alerts whose Key is snoozed past
+// now, or already open (not acknowledged), are not recorded again. It
+// returns the recorded alerts, for notifications.
+func (s *GUIState) RecordAlerts(runID string, now time.Time, fired []Alert) []Alert {
+	var recorded []Alert
+	for _, a := range fired {
+		if slices.ContainsFunc(s.Alerts, func(old Alert) bool {
+			return old.Key == a.Key && (!old.Acknowledged || old.SnoozedUntil.After(now))
+		}) {
+			continue
+		}
+		a.Time, a.RunID = now, runID
+		s.Alerts = append(s.Alerts, a)
+		recorded = append(recorded, a)
+	}
+	if over := len(s.Alerts) - MaxAlerts; over > 0 {
+		s.Alerts = append([]Alert{}, s.Alerts[over:]...)
+	}
+	return recorded
+}
+
+// AcknowledgeAlert marks the alerts with key as seen
+func (s *GUIState) AcknowledgeAlert(key string) {
+	for i := range s.Alerts {
+		if s.Alerts[i].Key == key {
+			s.Alerts[i].Acknowledged = true
+		}
+	}
+}
+
+// SnoozeAlert acknowledges the alerts with key and silences repeats until
+// until
+func (s *GUIState) SnoozeAlert(key string, until time.Time) {
+	for i := range s.Alerts {
+		if s.Alerts[i].Key == key {
+			s.Alerts[i].Acknowledged = true
+			s.Alerts[i].SnoozedUntil = until
+		}
+	}
+}
+
+// OpenAlerts returns the number of alerts not yet acknowledged
+func (s *GUIState) OpenAlerts() int {
+	open := 0
+	for _, a := range s.Alerts {
+		if !a.Acknowledged {
+			open++
+		}
+	}
+	return open
+}
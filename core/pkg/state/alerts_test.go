@@ -0,0 +1,80 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func alertReport(web error, djangoAPI, djangoWeb string) *report.Report {
+	rpt := &report.Report{Packages: []string{"django"}, Repositories: []report.RepositoryReport{
+		{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry", Dependencies: map[string]string{"django": djangoAPI}},
+		{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Analyzer: "poetry", Dependencies: map[string]string{"django": djangoWeb}},
+	}}
+	if web != nil {
+		rpt.Repositories[1].Error = report.NewErrorDetail(web)
+	}
+	return rpt
+}
+
+func TestEvaluateAlertRules(t *testing.T) {
+	rules := []AlertRule{
+		{Kind: AlertNewError},
+		{Kind: AlertVersionChange, Package: "Django"},
+		{Kind: AlertDrift, Threshold: 1},
+		{Kind: AlertDrift, Package: "django", Threshold: 2},
+		{Kind: AlertNewError, Disabled: true},
+		{Kind: AlertVersionChange},
+	}
+	prev := alertReport(nil, "4.2.0", "4.2.0")
+
+	fired := EvaluateAlertRules(rules, prev, alertReport(nil, "5.0.0", "4.2.0"))
+	var rulesFired []string
+	for _, a := range fired {
+		rulesFired = append(rulesFired, a.Rule)
+	}
+	if len(fired) != 2 || fired[0].Rule != "Django changed version" || fired[1].Rule != "Any package drift exceeds 1 version(s)" {
+		t.Fatalf("fired %v, want the version change and the drift above one version", rulesFired)
+	}
+	if fired[0].Message != "github:org/api@main: django changed from 4.2.0 to 5.0.0" {
+		t.Errorf("version change message = %q", fired[0].Message)
+	}
+
+	fired = EvaluateAlertRules(rules, prev, alertReport(errors.New("boom"), "4.2.0", ""))
+	if len(fired) != 1 || fired[0].Key != "new-error::github:org/web@main" {
+		t.Errorf("fired %+v, want the new error of org/web", fired)
+	}
+	if got := EvaluateAlertRules(rules, nil, prev); got != nil {
+		t.Errorf("first report fired %+v, want nothing", got)
+	}
+}
+
+func TestRecordAlerts(t *testing.T) {
+	st := NewDefaultGUIState()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	alert := Alert{Key: "drift::django", Rule: "Any package drift exceeds 1 version(s)", Message: "django drifted"}
+
+	if got := st.RecordAlerts("run1", now, []Alert{alert}); len(got) != 1 || got[0].RunID != "run1" || !got[0].Time.Equal(now) {
+		t.Fatalf("recorded %+v", got)
+	}
+	if got := st.RecordAlerts("run2", now, []Alert{alert}); len(got) != 0 || st.OpenAlerts() != 1 {
+		t.Errorf("open alert recorded again: %+v", got)
+	}
+
+	st.SnoozeAlert(alert.Key, now.Add(time.Hour))
+	if st.OpenAlerts() != 0 {
+		t.Errorf("snoozed alert still open")
+	}
+	if got := st.RecordAlerts("run3", now.Add(time.Minute), []Alert{alert}); len(got) != 0 {
+		t.Errorf("snoozed alert recorded: %+v", got)
+	}
+	if got := st.RecordAlerts("run4", now.Add(2*time.Hour), []Alert{alert}); len(got) != 1 {
+		t.Errorf("alert not recorded after its snooze expired")
+	}
+	st.AcknowledgeAlert(alert.Key)
+	if st.OpenAlerts() != 0 || len(st.Alerts) != 2 {
+		t.Errorf("alerts = %+v, want both acknowledged", st.Alerts)
+	}
+}
@@ -217,9 +217,11 @@ func (StubCredentialStore) ListProviders() ([]string, error) { return []string{}
 
 // ResolveProviderToken returns the credential for the given provider.
 // Lookup order:
-//  1. Environment variable DEV_DASHBOARD_<PROVIDER>_TOKEN
+//  1. Environment variable DEV_DASHBOARD_<PROVIDER>_TOKEN, preceded by
+//     DEV_DASHBOARD_<PROFILE>_<PROVIDER>_TOKEN for a non-default profile
 //  2. GUIState.Credentials snapshot (prototype / YAML storage)
-//  3. CredentialStore (if provided)
+//  3. CredentialStore (if provided), trying "<profile>/<provider>" before
+//     "<provider>" for a non-default profile
 //
 // It returns an empty string if none is found. Always redact tokens before logging.
 func ResolveProviderToken(provider string, st *GUIState, cs CredentialStore) (string, error) {
@@ -227,9 +229,19 @@ func ResolveProviderToken(provider string, st *GUIState, cs CredentialStore) (st
 		return "", errors.New("provider cannot be empty")
 	}
 
-	envName := fmt.Sprintf("DEV_DASHBOARD_%s_TOKEN", strings.ToUpper(provider))
-	if v := strings.TrimSpace(os.Getenv(envName)); v != "" {
-		return v, nil
+	profile := ""
+	if st != nil && st.Profile != "" && st.Profile != DefaultProfile {
+		profile = st.Profile
+	}
+
+	envNames := []string{fmt.Sprintf("DEV_DASHBOARD_%s_TOKEN", envSegment(provider))}
+	if profile != "" {
+		envNames = append([]string{fmt.Sprintf("DEV_DASHBOARD_%s_%s_TOKEN", envSegment(profile), envSegment(provider))}, envNames...)
+	}
+	for _, envName := range envNames {
+		if v := strings.TrimSpace(os.Getenv(envName)); v != "" {
+			return v, nil
+		}
 	}
 
 	// YAML / state snapshot (prototype only)
@@ -248,16 +260,37 @@ func ResolveProviderToken(provider string, st *GUIState, cs CredentialStore) (st
 
 	// Credential store (could be secure / keyring-backed)
 	if cs != nil {
-		if tok, err := cs.GetToken(provider); err == nil && strings.TrimSpace(tok) != "" {
-			return tok, nil
-		} else if err != nil && !errors.Is(err, ErrCredentialNotFound) {
-			return "", fmt.Errorf("credential store failure: %w", err)
+		keys := []string{provider}
+		if profile != "" {
+			keys = append([]string{profile + "/" + provider}, keys...)
+		}
+		for _, key := range keys {
+			if tok, err := cs.GetToken(key); err == nil && strings.TrimSpace(tok) != "" {
+				return tok, nil
+			} else if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+				return "", fmt.Errorf("credential store failure: %w", err)
+			}
 		}
 	}
 
 	return "", nil
 }
 
+// envSegment upper-cases s and replaces characters not allowed in
+// environment variable names with "_"
+func envSegment(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
 // RedactToken safely redacts a token for logging purposes.
 func RedactToken(tok string) string {
 	if tok == "" {
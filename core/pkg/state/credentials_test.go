@@ -417,6 +417,25 @@ func TestResolveProviderToken(t *testing.T) {
 		}
 	})
 
+	t.Run("profile-scoped env and store", func(t *testing.T) {
+		t.Setenv("DEV_DASHBOARD_GITHUB_TOKEN", "ghp_env")
+		store := NewInMemoryCredentialStore()
+		_ = store.SetToken("gitlab", "glpat_store")
+		_ = store.SetToken("team-b/gitlab", "glpat_team")
+		state := &GUIState{Profile: "team-b"}
+
+		if token, _ := ResolveProviderToken("gitlab", state, store); token != "glpat_team" {
+			t.Errorf("expected profile store token, got %s", token)
+		}
+		if token, _ := ResolveProviderToken("github", state, store); token != "ghp_env" {
+			t.Errorf("expected fallback to unscoped env token, got %s", token)
+		}
+		t.Setenv("DEV_DASHBOARD_TEAM_B_GITHUB_TOKEN", "ghp_team")
+		if token, _ := ResolveProviderToken("github", state, store); token != "ghp_team" {
+			t.Errorf("expected profile env token, got %s", token)
+		}
+	})
+
 	t.Run("priority order: env > state > store", func(t *testing.T) {
 		_ = os.Setenv("DEV_DASHBOARD_GITHUB_TOKEN", "ghp_env")
 		defer func() { _ = os.Unsetenv("DEV_DASHBOARD_GITHUB_TOKEN") }()
@@ -0,0 +1,47 @@
+package state
+
+import (
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/demo"
+)
+
+// DemoProfile is the profile name of demo mode states (see NewDemoGUIState)
+const DemoProfile = "demo"
+
+// NewDemoGUIState returns a state exploring a generated demo dataset: its
+// repositories on the in-memory provider, every package tracked, the
+// dataset's package groups, a sample policy, and report history entries
+// matching ds.History(now). Auto-refresh stays off. The caller populates
+// the memory provider (ds.Populate) and should never save the state.
+func NewDemoGUIState(ds *demo.Dataset, now time.Time) *GUIState {
+	st := NewDefaultGUIState()
+	st.Profile = DemoProfile
+	st.GUI.AutoRefresh.Enabled = false
+	for _, r := range ds.Repositories() {
+		st.RepositoriesCache = append(st.RepositoriesCache, RepoCacheEntry{
+			Provider:   r.Provider,
+			Owner:      r.Config.Owner,
+			Repository: r.Config.Repository,
+			Ref:        r.Config.Ref,
+			Packages:   r.Config.Packages,
+			Analyzer:   r.Config.Analyzer,
+			Tags:       r.Config.Tags,
+		})
+	}
+	st.TrackedPackages = demo.Packages()
+	st.PackageGroups = demo.PackageGroups()
+	st.Policies = []config.PolicyConfig{
+		{Name: "supported-django", Package: "django", Version: ">=4.2", Severity: "error"},
+		{Name: "pydantic-v2", Package: "pydantic", Version: ">=2", Tags: []string{"prod"}, Severity: "warning"},
+	}
+	for _, s := range ds.History(now) {
+		st.ReportHistory = append(st.ReportHistory, ReportHistoryEntry{
+			GeneratedAt:  s.GeneratedAt,
+			RepoCount:    len(s.Repositories) + len(s.Failed),
+			PackageCount: len(st.TrackedPackages),
+		})
+	}
+	return st
+}
@@ -0,0 +1,37 @@
+package state
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/demo"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
+)
+
+func TestNewDemoGUIState(t *testing.T) {
+	ds := demo.Generate(demo.Options{Repositories: 10, Weeks: 3})
+	st := NewDemoGUIState(ds, time.Now())
+	if st.Profile != DemoProfile || st.NeedsOnboarding() || st.GUI.AutoRefresh.Enabled {
+		t.Errorf("demo state: profile %q, needs onboarding %v, auto-refresh %v", st.Profile, st.NeedsOnboarding(), st.GUI.AutoRefresh.Enabled)
+	}
+	if len(st.RepositoriesCache) != 10 || len(st.ReportHistory) != 3 {
+		t.Errorf("demo state has %d repositories and %d history entries, want 10 and 3", len(st.RepositoriesCache), len(st.ReportHistory))
+	}
+	for _, rc := range st.RepositoriesCache {
+		if rc.Provider != memory.Provider || rc.Token != "" || len(rc.Packages) == 0 {
+			t.Errorf("demo repository %+v: want the memory provider, no token and tracked packages", rc)
+		}
+	}
+	if _, err := report.PoliciesFromConfig(st.Policies); err != nil {
+		t.Errorf("demo policies are invalid: %v", err)
+	}
+	for _, name := range st.PackageGroupNames() {
+		for _, pkg := range st.PackageGroups[name] {
+			if !slices.Contains(st.TrackedPackages, pkg) {
+				t.Errorf("package group %s lists untracked package %s", name, pkg)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package state
+
+import (
+	"encoding/csv"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// Delimiters for Format and FormatRecords
+const (
+	CSVDelimiter = ','
+	TSVDelimiter = '\t'
+)
+
+// Header returns the column titles of the view's records: "Tag" when rows
+// are grouped by tag, "Repository", then each package
+func (v DependencyTableView) Header() []string {
+	header := make([]string, 0, len(v.Packages)+2)
+	if v.Groups != nil {
+		header = append(header, "Tag")
+	}
+	header = append(header, "Repository")
+	return append(header, v.Packages...)
+}
+
+// Record returns the values of row i of the view (an index into v.Rows), in
+// Header order: the repository's owner/repo@ref and each package's locked
+// version, empty when not locked, or "ERROR" when the analysis failed.
+func (v DependencyTableView) Record(rpt *report.Report, i int) []string {
+	return v.appendRecord(make([]string, 0, len(v.Packages)+2), rpt, i)
+}
+
+// appendRecord appends the values of row i to record
+func (v DependencyTableView) appendRecord(record []string, rpt *report.Report, i int) []string {
+	row := v.Rows[i]
+	rr := &rpt.Repositories[row]
+	if v.Groups != nil {
+		record = append(record, v.Groups[i])
+	}
+	record = append(record, repositoryLabel(rr))
+	for _, pkg := range v.Packages {
+		var version string
+		if v.matrix != nil {
+			version = v.matrix.Version(row, pkg)
+		} else {
+			version = rr.Dependencies[pkg]
+		}
+		if version == "" && rr.Error != nil {
+			version = "ERROR"
+		}
+		record = append(record, version)
+	}
+	return record
+}
+
+// Records returns the header and every row of the view, across all pages
+func (v DependencyTableView) Records(rpt *report.Report) [][]string {
+	records := make([][]string, 0, len(v.Rows)+1)
+	records = append(records, v.Header())
+	for i := range v.Rows {
+		records = append(records, v.Record(rpt, i))
+	}
+	return records
+}
+
+// Format renders the header and every row of the view as FormatRecords
+// does, one row at a time rather than building every record first, so
+// copying a view of a large report holds only the text
+func (v DependencyTableView) Format(rpt *report.Report, delimiter rune) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Comma = delimiter
+	// Writing to a strings.Builder cannot fail
+	_ = w.Write(v.Header())
+	record := make([]string, 0, len(v.Packages)+2)
+	for i := range v.Rows {
+		_ = w.Write(v.appendRecord(record[:0], rpt, i))
+	}
+	w.Flush()
+	return b.String()
+}
+
+// FormatRecords renders records as delimited text for the clipboard, one line
+// per record, quoting fields as CSV does (e.g. a field holding the delimiter)
+func FormatRecords(records [][]string, delimiter rune) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Comma = delimiter
+	// Writing to a strings.Builder cannot fail
+	_ = w.WriteAll(records)
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestDependencyTableViewRecords(t *testing.T) {
+	rpt := &report.Report{
+		Packages: []string{"requests", "urllib3"},
+		Repositories: []report.RepositoryReport{
+			{Owner: "org", Repository: "api", Ref: "main", Dependencies: map[string]string{"requests": "2.31.0"}},
+			{Owner: "org", Repository: "cli", Ref: "dev", Error: report.NewErrorDetail(errors.New("not found"))},
+		},
+	}
+	st := NewDefaultGUIState()
+	view := st.FilterDependencyTable(rpt)
+
+	tsv := FormatRecords(view.Records(rpt), TSVDelimiter)
+	want := "Repository\trequests\turllib3\norg/api@main\t2.31.0\t\norg/cli@dev\tERROR\tERROR\n"
+	if tsv != want {
+		t.Errorf("TSV = %q, want %q", tsv, want)
+	}
+	if got := view.Format(rpt, TSVDelimiter); got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+	if got := FormatRecords([][]string{view.Record(rpt, 0)}, CSVDelimiter); got != "org/api@main,2.31.0,\n" {
+		t.Errorf("CSV row = %q", got)
+	}
+	if got := FormatRecords([][]string{{"a,b", `say "hi"`}}, CSVDelimiter); got != "\"a,b\",\"say \"\"hi\"\"\"\n" {
+		t.Errorf("expected CSV quoting, got %q", got)
+	}
+
+	st.GUI.DependencyGroupByTag = true
+	grouped := st.FilterDependencyTable(rpt)
+	if got := FormatRecords(grouped.Records(rpt)[:1], CSVDelimiter); got != "Tag,Repository,requests,urllib3\n" {
+		t.Errorf("grouped header = %q", got)
+	}
+	if got, want := grouped.Format(rpt, CSVDelimiter), FormatRecords(grouped.Records(rpt), CSVDelimiter); got != want {
+		t.Errorf("grouped Format = %q, want %q", got, want)
+	}
+}
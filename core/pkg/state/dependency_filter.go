@@ -0,0 +1,263 @@
+package state
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// DependencyFilter narrows the GUI dependencies table. Text queries are
+// case-insensitive substring matches; empty fields match everything.
+type DependencyFilter struct {
+	Package        string `yaml:"package,omitempty"`        // Matched against package (column) names
+	Repository     string `yaml:"repository,omitempty"`     // Matched against owner/repo@ref
+	Tag            string `yaml:"tag,omitempty"`            // Keep repositories carrying this tag (case-insensitive)
+	Ecosystem      string `yaml:"ecosystem,omitempty"`      // Keep repositories and packages of this ecosystem (see report.EcosystemLabel)
+	OnlyMismatched bool   `yaml:"onlyMismatched,omitempty"` // Keep packages with version drift
+	OnlyErrors     bool   `yaml:"onlyErrors,omitempty"`     // Keep repositories that failed analysis
+}
+
+// IsZero reports whether the filter shows everything.
+func (f DependencyFilter) IsZero() bool {
+	return f == DependencyFilter{}
+}
+
+// SortByRepository is the DependencySort column naming the repository column
+// rather than a package (package names never contain ":")
+const SortByRepository = ":repository"
+
+// DependencySort orders the rows of the GUI dependencies table.
+type DependencySort struct {
+	Column     string `yaml:"column,omitempty"` // Package name or SortByRepository; empty keeps report order
+	Descending bool   `yaml:"descending,omitempty"`
+}
+
+// Toggle cycles sorting on column: ascending, then descending, then off.
+// Selecting a different column starts again at ascending.
+func (s *DependencySort) Toggle(column string) {
+	switch {
+	case s.Column != column:
+		*s = DependencySort{Column: column}
+	case !s.Descending:
+		s.Descending = true
+	default:
+		*s = DependencySort{}
+	}
+}
+
+// DependencyTableView is the filtered projection of a report shown in the
+// dependencies table.
+type DependencyTableView struct {
+	Rows     []int    // Indexes into Report.Repositories, in display order
+	Packages []string // Column package names, spelled as in the report
+
+	// Groups holds the tag each row is listed under when grouping by tag
+	// (parallel to Rows, "" for untagged repositories); nil when not grouped.
+	// A repository with several tags is listed once per tag.
+	Groups []string
+
+	// The report's version matrix, so table cells can flag outdated
+	// versions without rescanning the report
+	matrix *report.VersionMatrix
+	shown  map[string]bool // Packages, for lookups
+}
+
+// DefaultDependencyPageSize is the number of repositories per dependencies
+// table page when GUI.DependencyPageSize is not set
+const DefaultDependencyPageSize = 100
+
+// IsOutdated reports whether version sorts below the highest version of pkg
+// among successfully analyzed repositories. It matches Report.IsOutdated for
+// packages in the view and is false for any other package.
+func (v DependencyTableView) IsOutdated(pkg, version string) bool {
+	if !v.shown[pkg] {
+		return false
+	}
+	return v.matrix.IsOutdated(pkg, version)
+}
+
+// Behind returns how many newer versions of pkg than version were found (see
+// report.VersionMatrix.Behind), the distance the dependencies heatmap colors
+// cells by; -1 for packages outside the view
+func (v DependencyTableView) Behind(pkg, version string) int {
+	if !v.shown[pkg] {
+		return -1
+	}
+	return v.matrix.Behind(pkg, version)
+}
+
+// Page returns the rows of one page of size repositories along with the page
+// actually shown (page clamped to the valid range) and the page count, which
+// is at least 1. A size <= 0 uses DefaultDependencyPageSize.
+func (v DependencyTableView) Page(page, size int) (rows []int, shown, pages int) {
+	start, end, shown, pages := v.pageBounds(page, size)
+	return v.Rows[start:end], shown, pages
+}
+
+// PageGroups returns the Groups entries matching the rows returned by Page,
+// or nil when the view is not grouped.
+func (v DependencyTableView) PageGroups(page, size int) []string {
+	if v.Groups == nil {
+		return nil
+	}
+	start, end, _, _ := v.pageBounds(page, size)
+	return v.Groups[start:end]
+}
+
+// PageOffset returns the index into Rows of the first row returned by Page
+func (v DependencyTableView) PageOffset(page, size int) int {
+	start, _, _, _ := v.pageBounds(page, size)
+	return start
+}
+
+func (v DependencyTableView) pageBounds(page, size int) (start, end, shown, pages int) {
+	if size <= 0 {
+		size = DefaultDependencyPageSize
+	}
+	pages = (len(v.Rows) + size - 1) / size
+	if pages == 0 {
+		pages = 1
+	}
+	shown = min(max(page, 0), pages-1)
+	start = shown * size
+	end = min(start+size, len(v.Rows))
+	return start, end, shown, pages
+}
+
+// FilterDependencyTable applies GUI.DependencyFilter to rpt, starting from
+// VisiblePackages (or every report package when none are tracked), then
+// orders rows by GUI.DependencySort and, with GUI.DependencyGroupByTag, groups
+// them by tag. A nil report yields an empty view.
+func (s *GUIState) FilterDependencyTable(rpt *report.Report) DependencyTableView {
+	var view DependencyTableView
+	if rpt == nil {
+		return view
+	}
+	f := s.GUI.DependencyFilter
+
+	packages := rpt.Packages
+	if visible := s.VisiblePackages(); len(visible) > 0 {
+		packages = make([]string, len(visible))
+		for i, name := range visible {
+			packages[i] = rpt.ResolvePackage(name)
+		}
+	}
+
+	view.matrix = rpt.Matrix()
+	view.shown = make(map[string]bool)
+	pkgQuery := strings.ToLower(strings.TrimSpace(f.Package))
+	for _, pkg := range packages {
+		if pkgQuery != "" && !strings.Contains(strings.ToLower(pkg), pkgQuery) {
+			continue
+		}
+		if f.Ecosystem != "" && report.EcosystemLabel(view.matrix.Ecosystem(pkg)) != f.Ecosystem {
+			continue
+		}
+		if f.OnlyMismatched && !view.matrix.HasDrift(pkg) {
+			continue
+		}
+		view.Packages = append(view.Packages, pkg)
+		view.shown[pkg] = true
+	}
+
+	repoQuery := strings.ToLower(strings.TrimSpace(f.Repository))
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		if f.OnlyErrors && rr.Error == nil {
+			continue
+		}
+		if f.Ecosystem != "" && report.EcosystemLabel(rr.GetEcosystem()) != f.Ecosystem {
+			continue
+		}
+		if repoQuery != "" && !strings.Contains(strings.ToLower(repositoryLabel(rr)), repoQuery) {
+			continue
+		}
+		if tag := strings.TrimSpace(f.Tag); tag != "" && !config.HasAnyTag(rr.Tags, []string{tag}) {
+			continue
+		}
+		view.Rows = append(view.Rows, i)
+	}
+	sortDependencyRows(rpt, view.matrix, view.Rows, s.GUI.DependencySort)
+	if s.GUI.DependencyGroupByTag {
+		view.Rows, view.Groups = groupRowsByTag(rpt, view.Rows, f.Tag)
+	}
+	return view
+}
+
+// groupRowsByTag lists rows under each of their tags, groups ordered by tag
+// name with untagged repositories last, keeping row order within a group.
+// When only is set, other tags do not form groups.
+func groupRowsByTag(rpt *report.Report, rows []int, only string) ([]int, []string) {
+	var lists [][]string
+	for _, i := range rows {
+		lists = append(lists, rpt.Repositories[i].Tags)
+	}
+	tags := config.MergeTags(lists...)
+	if only = strings.TrimSpace(only); only != "" {
+		tags = slices.DeleteFunc(tags, func(tag string) bool { return !strings.EqualFold(tag, only) })
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return strings.ToLower(tags[i]) < strings.ToLower(tags[j]) })
+
+	grouped := make([]int, 0, len(rows))
+	groups := make([]string, 0, len(rows))
+	for _, tag := range tags {
+		for _, i := range rows {
+			if config.HasAnyTag(rpt.Repositories[i].Tags, []string{tag}) {
+				grouped = append(grouped, i)
+				groups = append(groups, tag)
+			}
+		}
+	}
+	for _, i := range rows {
+		if len(config.MergeTags(rpt.Repositories[i].Tags)) == 0 {
+			grouped = append(grouped, i)
+			groups = append(groups, "")
+		}
+	}
+	return grouped, groups
+}
+
+// sortDependencyRows orders repository indexes by the sort column. Packages
+// compare by version using the package's ecosystem rules; repositories
+// without a version (missing or failed) always sort last. Ties keep report
+// order.
+func sortDependencyRows(rpt *report.Report, m *report.VersionMatrix, rows []int, by DependencySort) {
+	if by.Column == "" {
+		return
+	}
+	if by.Column == SortByRepository {
+		sort.SliceStable(rows, func(i, j int) bool {
+			a, b := &rpt.Repositories[rows[i]], &rpt.Repositories[rows[j]]
+			c := strings.Compare(strings.ToLower(repositoryLabel(a)), strings.ToLower(repositoryLabel(b)))
+			if by.Descending {
+				return c > 0
+			}
+			return c < 0
+		})
+		return
+	}
+
+	pkg := rpt.ResolvePackage(by.Column)
+	eco := m.Ecosystem(pkg)
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := m.Version(rows[i], pkg), m.Version(rows[j], pkg)
+		switch {
+		case a == "" || b == "":
+			return a != "" && b == ""
+		case by.Descending:
+			return versioning.Compare(eco, a, b) > 0
+		default:
+			return versioning.Compare(eco, a, b) < 0
+		}
+	})
+}
+
+// repositoryLabel is the owner/repo@ref text shown in the repository column
+func repositoryLabel(rr *report.RepositoryReport) string {
+	return fmt.Sprintf("%s/%s@%s", rr.Owner, rr.Repository, rr.Ref)
+}
@@ -0,0 +1,277 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestFilterDependencyTable(t *testing.T) {
+	rpt := &report.Report{
+		Packages: []string{"PyYAML", "requests", "urllib3"},
+		Repositories: []report.RepositoryReport{
+			{Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"PyYAML": "6.0", "requests": "2.31.0", "urllib3": "2.0.0"}},
+			{Owner: "org", Repository: "web", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"PyYAML": "6.0", "requests": "2.28.0", "urllib3": "2.0.0"}},
+			{Owner: "other", Repository: "cli", Ref: "dev", Analyzer: "poetry",
+				Error: report.NewErrorDetail(errors.New("no dependency files found"))},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		tracked  []string
+		filter   DependencyFilter
+		rows     string
+		packages string
+	}{
+		{"no filter", nil, DependencyFilter{}, "[0 1 2]", "[PyYAML requests urllib3]"},
+		{"tracked spelling resolved", []string{"pyyaml"}, DependencyFilter{}, "[0 1 2]", "[PyYAML]"},
+		{"package substring", nil, DependencyFilter{Package: "URL"}, "[0 1 2]", "[urllib3]"},
+		{"repository substring", nil, DependencyFilter{Repository: "ORG/"}, "[0 1]", "[PyYAML requests urllib3]"},
+		{"repository ref", nil, DependencyFilter{Repository: "@dev"}, "[2]", "[PyYAML requests urllib3]"},
+		{"only mismatched", nil, DependencyFilter{OnlyMismatched: true}, "[0 1 2]", "[requests]"},
+		{"only errors", nil, DependencyFilter{OnlyErrors: true}, "[2]", "[PyYAML requests urllib3]"},
+		{"combined", nil, DependencyFilter{Package: "yaml", OnlyMismatched: true}, "[0 1 2]", "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewDefaultGUIState()
+			st.TrackedPackages = tt.tracked
+			st.GUI.DependencyFilter = tt.filter
+			view := st.FilterDependencyTable(rpt)
+			if got := fmt.Sprint(view.Rows); got != tt.rows {
+				t.Errorf("rows = %s, want %s", got, tt.rows)
+			}
+			if got := fmt.Sprint(view.Packages); got != tt.packages {
+				t.Errorf("packages = %s, want %s", got, tt.packages)
+			}
+		})
+	}
+
+	if view := NewDefaultGUIState().FilterDependencyTable(nil); len(view.Rows) != 0 || len(view.Packages) != 0 {
+		t.Errorf("expected empty view for nil report, got %+v", view)
+	}
+}
+
+func TestDependencyFilterIsZero(t *testing.T) {
+	if !(DependencyFilter{}).IsZero() {
+		t.Error("empty filter should be zero")
+	}
+	if (DependencyFilter{OnlyErrors: true}).IsZero() {
+		t.Error("filter with OnlyErrors should not be zero")
+	}
+}
+
+func TestFilterDependencyTableSort(t *testing.T) {
+	rpt := &report.Report{
+		Packages: []string{"requests"},
+		Repositories: []report.RepositoryReport{
+			{Owner: "org", Repository: "b", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"requests": "2.9.0"}},
+			{Owner: "org", Repository: "a", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{}},
+			{Owner: "org", Repository: "c", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"requests": "2.31.0"}},
+			{Owner: "org", Repository: "d", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"requests": "2.31.0rc1"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		sort DependencySort
+		rows string
+	}{
+		{"report order", DependencySort{}, "[0 1 2 3]"},
+		{"version ascending", DependencySort{Column: "Requests"}, "[0 3 2 1]"},
+		{"version descending", DependencySort{Column: "requests", Descending: true}, "[2 3 0 1]"},
+		{"repository ascending", DependencySort{Column: SortByRepository}, "[1 0 2 3]"},
+		{"repository descending", DependencySort{Column: SortByRepository, Descending: true}, "[3 2 0 1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewDefaultGUIState()
+			st.GUI.DependencySort = tt.sort
+			if got := fmt.Sprint(st.FilterDependencyTable(rpt).Rows); got != tt.rows {
+				t.Errorf("rows = %s, want %s", got, tt.rows)
+			}
+		})
+	}
+}
+
+func TestFilterDependencyTableTags(t *testing.T) {
+	rpt := &report.Report{
+		Packages: []string{"requests"},
+		Repositories: []report.RepositoryReport{
+			{Owner: "org", Repository: "a", Tags: []string{"team-search"}},
+			{Owner: "org", Repository: "b"},
+			{Owner: "org", Repository: "c", Tags: []string{"Team-Payments", "deprecated"}},
+			{Owner: "org", Repository: "d", Tags: []string{"team-payments"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		tag     string
+		group   bool
+		rows    string
+		groups  string
+		grouped bool
+	}{
+		{"no tag", "", false, "[0 1 2 3]", "", false},
+		{"tag filter", "TEAM-PAYMENTS", false, "[2 3]", "", false},
+		{"grouped", "", true, "[2 2 3 0 1]", "[deprecated Team-Payments Team-Payments team-search ]", true},
+		{"grouped with tag filter", "team-payments", true, "[2 3]", "[Team-Payments Team-Payments]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewDefaultGUIState()
+			st.GUI.DependencyFilter = DependencyFilter{Tag: tt.tag}
+			st.GUI.DependencyGroupByTag = tt.group
+			view := st.FilterDependencyTable(rpt)
+			if got := fmt.Sprint(view.Rows); got != tt.rows {
+				t.Errorf("rows = %s, want %s", got, tt.rows)
+			}
+			if (view.Groups != nil) != tt.grouped {
+				t.Fatalf("grouped = %v, want %v", view.Groups != nil, tt.grouped)
+			}
+			if tt.grouped && fmt.Sprint(view.Groups) != tt.groups {
+				t.Errorf("groups = %q, want %q", fmt.Sprint(view.Groups), tt.groups)
+			}
+		})
+	}
+}
+
+func TestDependencySortToggle(t *testing.T) {
+	var s DependencySort
+	s.Toggle("requests")
+	if s != (DependencySort{Column: "requests"}) {
+		t.Errorf("first toggle should sort ascending, got %+v", s)
+	}
+	s.Toggle("requests")
+	if s != (DependencySort{Column: "requests", Descending: true}) {
+		t.Errorf("second toggle should sort descending, got %+v", s)
+	}
+	s.Toggle("requests")
+	if s != (DependencySort{}) {
+		t.Errorf("third toggle should clear sorting, got %+v", s)
+	}
+	s.Toggle("requests")
+	s.Toggle(SortByRepository)
+	if s != (DependencySort{Column: SortByRepository}) {
+		t.Errorf("switching column should restart ascending, got %+v", s)
+	}
+}
+
+func TestDependencyTableViewIsOutdated(t *testing.T) {
+	rpt := &report.Report{
+		Packages: []string{"requests", "django"},
+		Repositories: []report.RepositoryReport{
+			{Analyzer: "poetry", Dependencies: map[string]string{"requests": "2.9.0", "django": "4.2"}},
+			{Analyzer: "poetry", Dependencies: map[string]string{"requests": "2.31.0"}},
+			{Analyzer: "poetry", Dependencies: map[string]string{"requests": "3.0.0"}, Error: report.NewErrorDetail(errors.New("partial"))},
+		},
+	}
+	st := NewDefaultGUIState()
+	st.TrackedPackages = []string{"requests"}
+	view := st.FilterDependencyTable(rpt)
+
+	for _, tt := range []struct {
+		pkg, version string
+		want         bool
+	}{
+		{"requests", "2.9.0", true},
+		{"requests", "2.31.0", false},
+		{"requests", "", false},
+		{"django", "1.0", false}, // not in the view
+	} {
+		if got := view.IsOutdated(tt.pkg, tt.version); got != tt.want {
+			t.Errorf("IsOutdated(%q, %q) = %v, want %v", tt.pkg, tt.version, got, tt.want)
+		}
+		if tt.pkg == "requests" && rpt.IsOutdated(tt.pkg, tt.version) != tt.want {
+			t.Errorf("view disagrees with Report.IsOutdated for %q", tt.version)
+		}
+	}
+	if view.Behind("requests", "2.9.0") != 1 || view.Behind("requests", "2.31.0") != 0 || view.Behind("django", "4.2") != -1 {
+		t.Errorf("Behind = %d, %d, %d; want 1, 0 and -1 outside the view",
+			view.Behind("requests", "2.9.0"), view.Behind("requests", "2.31.0"), view.Behind("django", "4.2"))
+	}
+}
+
+func TestDependencyTableViewPage(t *testing.T) {
+	view := DependencyTableView{Rows: []int{0, 1, 2, 3, 4}}
+
+	tests := []struct {
+		page, size   int
+		rows         string
+		shown, pages int
+	}{
+		{0, 2, "[0 1]", 0, 3},
+		{2, 2, "[4]", 2, 3},
+		{9, 2, "[4]", 2, 3},
+		{-1, 2, "[0 1]", 0, 3},
+		{0, 0, "[0 1 2 3 4]", 0, 1},
+	}
+	for _, tt := range tests {
+		rows, shown, pages := view.Page(tt.page, tt.size)
+		if fmt.Sprint(rows) != tt.rows || shown != tt.shown || pages != tt.pages {
+			t.Errorf("Page(%d, %d) = %v, %d, %d; want %s, %d, %d",
+				tt.page, tt.size, rows, shown, pages, tt.rows, tt.shown, tt.pages)
+		}
+	}
+
+	grouped := DependencyTableView{Rows: []int{0, 1, 2}, Groups: []string{"a", "a", "b"}}
+	if got := fmt.Sprint(grouped.PageGroups(1, 2)); got != "[b]" {
+		t.Errorf("PageGroups(1, 2) = %s, want [b]", got)
+	}
+	if view.PageGroups(0, 2) != nil {
+		t.Error("ungrouped view should have nil page groups")
+	}
+	if got := view.PageOffset(9, 2); got != 4 {
+		t.Errorf("PageOffset(9, 2) = %d, want 4", got)
+	}
+
+	rows, shown, pages := DependencyTableView{}.Page(3, 10)
+	if len(rows) != 0 || shown != 0 || pages != 1 {
+		t.Errorf("empty view should have one empty page, got %v, %d, %d", rows, shown, pages)
+	}
+}
+
+func TestFilterDependencyTableEcosystem(t *testing.T) {
+	rpt := &report.Report{
+		Packages: []string{"django", "github.com/psf/black"},
+		Repositories: []report.RepositoryReport{
+			{Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry",
+				Dependencies: map[string]string{"django": "4.2.11"}},
+			{Owner: "org", Repository: "api", Ref: "main", Analyzer: "pre-commit", Ecosystem: "pre-commit",
+				Dependencies: map[string]string{"github.com/psf/black": "24.2.0"}},
+			{Owner: "org", Repository: "tool", Ref: "main", Analyzer: "cargo"},
+		},
+	}
+
+	tests := []struct {
+		ecosystem string
+		rows      string
+		packages  string
+	}{
+		{"", "[0 1 2]", "[django github.com/psf/black]"},
+		{"python", "[0]", "[django]"},
+		{"pre-commit", "[1]", "[github.com/psf/black]"},
+		{"other", "[2]", "[]"},
+	}
+	for _, tt := range tests {
+		st := NewDefaultGUIState()
+		st.GUI.DependencyFilter = DependencyFilter{Ecosystem: tt.ecosystem}
+		view := st.FilterDependencyTable(rpt)
+		if got := fmt.Sprint(view.Rows); got != tt.rows {
+			t.Errorf("%q: rows = %s, want %s", tt.ecosystem, got, tt.rows)
+		}
+		if got := fmt.Sprint(view.Packages); got != tt.packages {
+			t.Errorf("%q: packages = %s, want %s", tt.ecosystem, got, tt.packages)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package state
+
+import (
+	"sort"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// MaxErrorLogEntries caps ErrorLog; the oldest entries are dropped first.
+const MaxErrorLogEntries = 500
+
+// AppendError records an entry in ErrorLog, keeping at most
+// MaxErrorLogEntries.
+func (s *GUIState) AppendError(e ErrorLogEntry) {
+	s.ErrorLog = append(s.ErrorLog, e)
+	if over := len(s.ErrorLog) - MaxErrorLogEntries; over > 0 {
+		s.ErrorLog = append([]ErrorLogEntry{}, s.ErrorLog[over:]...)
+	}
+}
+
+// RecordReportErrors appends an entry for every repository that failed
+// analysis in rpt, tagged with runID.
+func (s *GUIState) RecordReportErrors(runID string, rpt *report.Report) {
+	if rpt == nil {
+		return
+	}
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		if rr.Error == nil {
+			continue
+		}
+		s.AppendError(ErrorLogEntry{
+			Time:       time.Now().UTC(),
+			Source:     "report:" + string(rr.ErrorCategory()),
+			Severity:   "error",
+			Message:    "Analysis failed for " + rr.GetRepoIdentifier() + "@" + rr.Ref,
+			Details:    rr.Error.Error(),
+			RunID:      runID,
+			Repository: rr.Key(),
+		})
+	}
+}
+
+// ErrorRun groups the ErrorLog entries recorded by one report run.
+type ErrorRun struct {
+	RunID   string // Empty for entries recorded outside a run
+	Entries []ErrorLogEntry
+}
+
+// ErrorRuns groups ErrorLog by RunID, most recent run first. Entries keep
+// their log order within a run.
+func (s *GUIState) ErrorRuns() []ErrorRun {
+	index := make(map[string]int)
+	var runs []ErrorRun
+	last := make(map[string]int)
+	for i, e := range s.ErrorLog {
+		n, ok := index[e.RunID]
+		if !ok {
+			n = len(runs)
+			index[e.RunID] = n
+			runs = append(runs, ErrorRun{RunID: e.RunID})
+		}
+		runs[n].Entries = append(runs[n].Entries, e)
+		last[e.RunID] = i
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return last[runs[i].RunID] > last[runs[j].RunID]
+	})
+	return runs
+}
+
+// ClearResolvedErrors removes entries from runs before runID that no longer
+// apply given rpt, the report produced by runID: repository entries whose
+// repository analyzed successfully, and run-level entries whose source and
+// message did not recur in runID. It returns the number of entries removed.
+func (s *GUIState) ClearResolvedErrors(runID string, rpt *report.Report) int {
+	if rpt == nil {
+		return 0
+	}
+	succeeded := make(map[string]bool, len(rpt.Repositories))
+	for i := range rpt.Repositories {
+		if rpt.Repositories[i].Error == nil {
+			succeeded[rpt.Repositories[i].Key()] = true
+		}
+	}
+	recurring := make(map[[2]string]bool)
+	for _, e := range s.ErrorLog {
+		if e.RunID == runID {
+			recurring[[2]string{e.Source, e.Message}] = true
+		}
+	}
+
+	kept := s.ErrorLog[:0]
+	for _, e := range s.ErrorLog {
+		resolved := false
+		if e.RunID != runID {
+			if e.Repository != "" {
+				resolved = succeeded[e.Repository]
+			} else {
+				resolved = !recurring[[2]string{e.Source, e.Message}]
+			}
+		}
+		if !resolved {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(s.ErrorLog) - len(kept)
+	s.ErrorLog = kept
+	return removed
+}
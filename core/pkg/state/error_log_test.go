@@ -0,0 +1,79 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestAppendErrorCapsLog(t *testing.T) {
+	st := NewDefaultGUIState()
+	for i := 0; i < MaxErrorLogEntries+5; i++ {
+		st.AppendError(ErrorLogEntry{Message: fmt.Sprint(i)})
+	}
+	if len(st.ErrorLog) != MaxErrorLogEntries {
+		t.Fatalf("expected %d entries, got %d", MaxErrorLogEntries, len(st.ErrorLog))
+	}
+	if st.ErrorLog[0].Message != "5" {
+		t.Errorf("expected oldest entries dropped first, first is %q", st.ErrorLog[0].Message)
+	}
+}
+
+func TestRecordReportErrorsAndRuns(t *testing.T) {
+	st := NewDefaultGUIState()
+	st.AppendError(ErrorLogEntry{RunID: "run1", Source: "token-resolve", Message: "no token"})
+	st.RecordReportErrors("run1", &report.Report{Repositories: []report.RepositoryReport{
+		{Provider: "github", Owner: "org", Repository: "api", Ref: "main", Error: report.NewErrorDetail(errors.New("boom"))},
+		{Provider: "github", Owner: "org", Repository: "web", Ref: "main"},
+	}})
+	st.AppendError(ErrorLogEntry{RunID: "run2", Source: "retry", Message: "retrying"})
+
+	if len(st.ErrorLog) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(st.ErrorLog))
+	}
+	if e := st.ErrorLog[1]; e.Repository != "github:org/api@main" || e.Details != "boom" || e.RunID != "run1" {
+		t.Errorf("unexpected report error entry %+v", e)
+	}
+
+	runs := st.ErrorRuns()
+	if len(runs) != 2 || runs[0].RunID != "run2" || runs[1].RunID != "run1" {
+		t.Fatalf("expected runs newest first, got %+v", runs)
+	}
+	if len(runs[1].Entries) != 2 {
+		t.Errorf("expected 2 entries in run1, got %d", len(runs[1].Entries))
+	}
+}
+
+func TestClearResolvedErrors(t *testing.T) {
+	st := NewDefaultGUIState()
+	st.ErrorLog = []ErrorLogEntry{
+		{RunID: "run1", Source: "report:network", Message: "api failed", Repository: "github:org/api@main"},
+		{RunID: "run1", Source: "report:auth", Message: "web failed", Repository: "github:org/web@main"},
+		{RunID: "run1", Source: "token-resolve", Message: "no gitlab token"},
+		{RunID: "run1", Source: "token-resolve", Message: "no github token"},
+		{RunID: "run2", Source: "token-resolve", Message: "no gitlab token"},
+		{RunID: "run2", Source: "report:auth", Message: "web failed", Repository: "github:org/web@main"},
+	}
+	rpt := &report.Report{Repositories: []report.RepositoryReport{
+		{Provider: "github", Owner: "org", Repository: "api", Ref: "main"},
+		{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Error: report.NewErrorDetail(errors.New("denied"))},
+	}}
+
+	if n := st.ClearResolvedErrors("run2", rpt); n != 2 {
+		t.Errorf("expected 2 resolved entries removed, got %d", n)
+	}
+	var remaining []string
+	for _, e := range st.ErrorLog {
+		remaining = append(remaining, e.RunID+":"+e.Message)
+	}
+	want := "[run1:web failed run1:no gitlab token run2:no gitlab token run2:web failed]"
+	if fmt.Sprint(remaining) != want {
+		t.Errorf("remaining = %v, want %s", remaining, want)
+	}
+
+	if n := st.ClearResolvedErrors("run3", nil); n != 0 {
+		t.Errorf("nil report should clear nothing, got %d", n)
+	}
+}
@@ -48,7 +48,7 @@ package state
 //
 // Usage Example (GUI):
 //   st, _ := state.LoadGUIState("")
-//   st.GUI.Theme = "dark"
+//   st.GUI.Theme = ThemeCfg{Variant: "dark"}
 //   _ = state.SaveGUIState(st, "")
 //
 // Usage Example (Export CLI-compatible YAML):
@@ -59,12 +59,14 @@ package state
 // with other future state domains (e.g., pipeline, metrics).
 //
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -82,8 +84,13 @@ type GUIState struct {
 	Providers         map[string]ProviderConfigWrapper `yaml:"providers"`
 	RepositoriesCache []RepoCacheEntry                 `yaml:"repositoriesCache"`
 	TrackedPackages   []string                         `yaml:"trackedPackages"`
+	PackageGroups     map[string][]string              `yaml:"packageGroups,omitempty"` // named watchlists, same shape as CLI config
+	Policies          []config.PolicyConfig            `yaml:"policies,omitempty"`      // version pinning rules, same shape as CLI config
+	Ignores           []config.IgnoreConfig            `yaml:"ignores,omitempty"`       // accepted drift/violations, same shape as CLI config
 	Credentials       *CredentialSnapshot              `yaml:"credentials,omitempty"`
 	ErrorLog          []ErrorLogEntry                  `yaml:"errorLog,omitempty"`
+	AlertRules        []AlertRule                      `yaml:"alertRules,omitempty"` // checked after background refreshes
+	Alerts            []Alert                          `yaml:"alerts,omitempty"`
 	ReportHistory     []ReportHistoryEntry             `yaml:"reportHistory,omitempty"`
 	Extensions        map[string]map[string]any        `yaml:"extensions,omitempty"` // reserved for future pluggable modules
 	Meta              map[string]string                `yaml:"meta,omitempty"`       // arbitrary small string map
@@ -92,12 +99,51 @@ type GUIState struct {
 // GUISection contains desktop/UI specific preferences and metadata.
 type GUISection struct {
 	LastWindow   WindowGeometry  `yaml:"lastWindow"`
-	Theme        string          `yaml:"theme"` // light | dark
+	Theme        ThemeCfg        `yaml:"theme"`
 	RecentConfig []string        `yaml:"recentConfigFiles"`
 	Concurrency  ConcurrencyCfg  `yaml:"concurrency"`
 	AutoRefresh  AutoRefreshCfg  `yaml:"autoRefresh"`
+	Tray         TrayCfg         `yaml:"tray"`
+	Sync         SyncCfg         `yaml:"sync,omitempty"`
 	Logging      LoggingCfg      `yaml:"logging"`
 	LastReport   *LastReportMeta `yaml:"lastReport,omitempty"`
+	// DetachedViews records, by view name (e.g. "Logs"), the views moved
+	// into a window of their own and that window's geometry
+	DetachedViews map[string]*DetachedView `yaml:"detachedViews,omitempty"`
+	// ActivePackageGroup selects a PackageGroups entry as the table filter
+	// preset; empty uses TrackedPackages
+	ActivePackageGroup string `yaml:"activePackageGroup,omitempty"`
+	// DependencyFilter is the dependencies table search/filter toolbar state
+	DependencyFilter DependencyFilter `yaml:"dependencyFilter,omitempty"`
+	// DependencySort is the dependencies table row order (header click)
+	DependencySort DependencySort `yaml:"dependencySort,omitempty"`
+	// DependencyGroupByTag lists dependencies table rows under their
+	// repository tags
+	DependencyGroupByTag bool `yaml:"dependencyGroupByTag,omitempty"`
+	// DependencyHeatmap renders the dependencies table as a heatmap colored
+	// by how many versions each cell is behind the newest one found
+	DependencyHeatmap bool `yaml:"dependencyHeatmap,omitempty"`
+	// DependencyPageSize is the repositories shown per dependencies table
+	// page; 0 uses DefaultDependencyPageSize
+	DependencyPageSize int `yaml:"dependencyPageSize,omitempty"`
+	// ExcludeDevDependencies leaves packages locked only as development
+	// dependencies out of GUI reports
+	ExcludeDevDependencies bool `yaml:"excludeDevDependencies,omitempty"`
+	// Timeouts bound report runs started from the GUI; a zero Run uses
+	// DefaultReportTimeout
+	Timeouts config.TimeoutsConfig `yaml:"timeouts,omitempty"`
+}
+
+// DefaultReportTimeout bounds a GUI report run when GUI.Timeouts.Run is not
+// set
+const DefaultReportTimeout = 5 * time.Minute
+
+// ReportTimeout returns the deadline for a whole report run
+func (g GUISection) ReportTimeout() time.Duration {
+	if g.Timeouts.Run > 0 {
+		return g.Timeouts.Run
+	}
+	return DefaultReportTimeout
 }
 
 // WindowGeometry tracks last window geometry.
@@ -107,6 +153,33 @@ type WindowGeometry struct {
 	Maximized bool `yaml:"maximized"`
 }
 
+// DetachedView is a view shown in its own window, e.g. on a second monitor
+type DetachedView struct {
+	// Open reopens the view in its own window on launch; false keeps the
+	// geometry for the next time it is detached
+	Open   bool           `yaml:"open"`
+	Window WindowGeometry `yaml:"window"`
+}
+
+// Detached returns the record of the named view, creating it when missing
+func (g *GUISection) Detached(view string) *DetachedView {
+	if g.DetachedViews == nil {
+		g.DetachedViews = make(map[string]*DetachedView)
+	}
+	d := g.DetachedViews[view]
+	if d == nil {
+		d = &DetachedView{}
+		g.DetachedViews[view] = d
+	}
+	return d
+}
+
+// IsDetached reports whether the named view was last shown in its own window
+func (g GUISection) IsDetached(view string) bool {
+	d := g.DetachedViews[view]
+	return d != nil && d.Open
+}
+
 // ConcurrencyCfg tuning for async tasks.
 type ConcurrencyCfg struct {
 	MaxWorkers int `yaml:"maxWorkers"`
@@ -118,17 +191,30 @@ type AutoRefreshCfg struct {
 	IntervalSeconds int  `yaml:"intervalSeconds"`
 }
 
+// TrayCfg controls the system tray icon. When enabled, closing the window
+// hides it to the tray and reports keep running in the background.
+type TrayCfg struct {
+	Enabled bool `yaml:"enabled"`
+	// Notify sends a desktop notification when a report finds new version
+	// drift or newly failing repositories
+	Notify bool `yaml:"notify"`
+}
+
 // LoggingCfg controls in-memory logging capture.
 type LoggingCfg struct {
 	RingBufferSize int    `yaml:"ringBufferSize"`
 	Level          string `yaml:"level"` // info | debug | warn | error
+	// TraceHTTP logs every provider API request of report runs (see
+	// repository.HTTPTracer)
+	TraceHTTP bool `yaml:"traceHTTP,omitempty"`
 }
 
 // LastReportMeta summarises the most recent dependency report.
 type LastReportMeta struct {
-	GeneratedAt  time.Time `yaml:"generatedAt"`
-	RepoCount    int       `yaml:"repoCount"`
-	PackageCount int       `yaml:"packageCount"`
+	GeneratedAt  time.Time     `yaml:"generatedAt"`
+	RepoCount    int           `yaml:"repoCount"`
+	PackageCount int           `yaml:"packageCount"`
+	Duration     time.Duration `yaml:"duration,omitempty"` // Wall time of the run
 }
 
 // ProviderConfigWrapper mirrors CLI provider structure.
@@ -139,14 +225,29 @@ type ProviderConfigWrapper struct {
 
 // RepoCacheEntry is a denormalized cache row for fast GUI listing.
 type RepoCacheEntry struct {
-	Provider   string   `yaml:"provider"`
-	Token      string   `yaml:"token"`
-	Owner      string   `yaml:"owner"`
-	Repository string   `yaml:"repository"`
-	Ref        string   `yaml:"ref"`
-	Paths      []string `yaml:"paths"`
-	Packages   []string `yaml:"packages"`
-	Analyzer   string   `yaml:"analyzer"`
+	Provider    string   `yaml:"provider"`
+	Token       string   `yaml:"token"`
+	BaseURL     string   `yaml:"baseURL,omitempty"`
+	Enterprise  bool     `yaml:"enterprise,omitempty"`
+	APIPrefix   string   `yaml:"apiPrefix,omitempty"`
+	Owner       string   `yaml:"owner"`
+	Repository  string   `yaml:"repository"`
+	Ref         string   `yaml:"ref"`
+	Paths       []string `yaml:"paths"`
+	Packages    []string `yaml:"packages"`
+	Analyzer    string   `yaml:"analyzer"`
+	UpdatePRs   bool     `yaml:"updatePRs,omitempty"`
+	Constraints bool     `yaml:"constraints,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	// Network settings, inherited from the provider default when unset
+	Proxy              string `yaml:"proxy,omitempty"`
+	CAFile             string `yaml:"caFile,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty"`
+	// ExcludePaths, inherited from the provider default when unset
+	ExcludePaths []string `yaml:"excludePaths,omitempty"`
+	// Canonical identifies the project the repository mirrors (see
+	// config.RepoConfig.Canonical)
+	Canonical string `yaml:"canonical,omitempty"`
 }
 
 // CredentialSnapshot is prototype-only. Replace with keyring / secure store.
@@ -157,11 +258,13 @@ type CredentialSnapshot struct {
 
 // ErrorLogEntry allows structured recent error display.
 type ErrorLogEntry struct {
-	Time     time.Time `yaml:"time"`
-	Source   string    `yaml:"source"`
-	Severity string    `yaml:"severity"`
-	Message  string    `yaml:"message"`
-	Details  string    `yaml:"details,omitempty"`
+	Time       time.Time `yaml:"time"`
+	Source     string    `yaml:"source"`
+	Severity   string    `yaml:"severity"`
+	Message    string    `yaml:"message"`
+	Details    string    `yaml:"details,omitempty"`
+	RunID      string    `yaml:"runId,omitempty"`      // Report run that recorded the entry
+	Repository string    `yaml:"repository,omitempty"` // Repository key (provider:owner/repo@ref), if repository specific
 }
 
 // ReportHistoryEntry supports future diff & history features.
@@ -172,7 +275,8 @@ type ReportHistoryEntry struct {
 	SummaryPath  string    `yaml:"summaryPath,omitempty"`
 }
 
-// GUIStateStore defines pluggable storage behaviour (filesystem, memory, remote).
+// GUIStateStore defines pluggable storage behaviour (filesystem, memory,
+// remote). Remote implementations are RemoteGUIStateStore.
 type GUIStateStore interface {
 	Load(path string) (*GUIState, error)
 	Save(st *GUIState, path string) error
@@ -193,17 +297,19 @@ func (FilesystemGUIStateStore) Save(st *GUIState, path string) error {
 
 // NewDefaultGUIState creates a new initialized GUIState with sane defaults.
 func NewDefaultGUIState() *GUIState {
+	defaults := DefaultSettings()
 	return &GUIState{
 		StateVersion: 1,
 		SavedAt:      time.Now().UTC(),
 		Profile:      "default",
 		GUI: GUISection{
 			LastWindow:   WindowGeometry{Width: 1100, Height: 700, Maximized: false},
-			Theme:        "light",
+			Theme:        ThemeCfg{Variant: ThemeLight},
 			RecentConfig: []string{},
-			Concurrency:  ConcurrencyCfg{MaxWorkers: runtime.NumCPU()},
-			AutoRefresh:  AutoRefreshCfg{Enabled: false, IntervalSeconds: 900},
-			Logging:      LoggingCfg{RingBufferSize: 5000, Level: "info"},
+			Concurrency:  defaults.Concurrency,
+			AutoRefresh:  defaults.AutoRefresh,
+			Tray:         TrayCfg{Enabled: false, Notify: true},
+			Logging:      defaults.Logging,
 		},
 		Providers: map[string]ProviderConfigWrapper{
 			"github": {
@@ -248,7 +354,8 @@ func LoadGUIState(path string) (*GUIState, error) {
 	return &st, nil
 }
 
-// SaveGUIState persists the state atomically to disk.
+// SaveGUIState persists the state atomically to disk, holding the state
+// file lock (see SaveGUIStateIfUnmodified for the non-clobbering variant).
 func SaveGUIState(st *GUIState, path string) error {
 	if st == nil {
 		return errors.New("state: nil GUIState")
@@ -256,11 +363,21 @@ func SaveGUIState(st *GUIState, path string) error {
 	if path == "" {
 		path = DefaultGUIStatePath()
 	}
+	return withStateLock(path, func() error { return writeGUIState(st, path) })
+}
+
+// writeGUIState stamps SavedAt and replaces path atomically. The caller holds
+// the lock. SavedAt is restored when the write fails so it keeps matching the
+// file on disk.
+func writeGUIState(st *GUIState, path string) (err error) {
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o750); err != nil {
-		return fmt.Errorf("state: mkdir failed: %w", err)
-	}
+	previous := st.SavedAt
 	st.SavedAt = time.Now().UTC()
+	defer func() {
+		if err != nil {
+			st.SavedAt = previous
+		}
+	}()
 
 	out, err := yaml.Marshal(st)
 	if err != nil {
@@ -321,11 +438,9 @@ func normalizeGUIState(st *GUIState) {
 		st.GUI.Concurrency.MaxWorkers = runtime.NumCPU()
 	}
 	if st.GUI.Logging.RingBufferSize <= 0 {
-		st.GUI.Logging.RingBufferSize = 5000
-	}
-	if st.GUI.Theme == "" {
-		st.GUI.Theme = "light"
+		st.GUI.Logging.RingBufferSize = defaultRingBufferSize
 	}
+	st.GUI.Theme = st.GUI.Theme.Normalized()
 	if st.Providers == nil {
 		st.Providers = map[string]ProviderConfigWrapper{}
 	}
@@ -415,31 +530,76 @@ func (s *GUIState) MergeCLIConfig(path string) error {
 		}
 		s.Providers[pname] = wrapper
 	}
+	for name, pkgs := range cfg.PackageGroups {
+		if _, exists := s.PackageGroups[name]; exists {
+			continue
+		}
+		s.SetPackageGroup(name, pkgs)
+	}
+	for _, policy := range cfg.Policies {
+		if !slices.ContainsFunc(s.Policies, func(p config.PolicyConfig) bool { return p.Name == policy.Name }) {
+			s.Policies = append(s.Policies, policy)
+		}
+	}
+	for _, ignore := range cfg.Ignores {
+		if !slices.Contains(s.Ignores, ignore) {
+			s.Ignores = append(s.Ignores, ignore)
+		}
+	}
+	if cfg.Timeouts != nil && s.GUI.Timeouts == (config.TimeoutsConfig{}) {
+		s.GUI.Timeouts = *cfg.Timeouts
+	}
 	s.RebuildRepositoriesCache()
 	s.AppendRecentConfig(path, 10)
 	return nil
 }
 
 // RebuildRepositoriesCache regenerates the flattened repository cache.
+// Repositories without their own token, base URL, proxy, CA file or exclude
+// paths inherit the provider default's.
 func (s *GUIState) RebuildRepositoriesCache() {
 	cache := make([]RepoCacheEntry, 0, 64)
 	for pname, wrapper := range s.Providers {
 		for _, r := range wrapper.Repositories {
+			excludes := r.ExcludePaths
+			if len(excludes) == 0 {
+				excludes = wrapper.Default.ExcludePaths
+			}
 			cache = append(cache, RepoCacheEntry{
-				Provider:   pname,
-				Token:      r.Token,
-				Owner:      r.Owner,
-				Repository: r.Repository,
-				Ref:        r.Ref,
-				Paths:      r.Paths,
-				Packages:   r.Packages,
-				Analyzer:   r.Analyzer,
+				Provider:           pname,
+				Token:              cmp.Or(r.Token, wrapper.Default.Token),
+				BaseURL:            cmp.Or(r.BaseURL, wrapper.Default.BaseURL),
+				Enterprise:         r.Enterprise || wrapper.Default.Enterprise,
+				APIPrefix:          cmp.Or(r.APIPrefix, wrapper.Default.APIPrefix),
+				Owner:              r.Owner,
+				Repository:         r.Repository,
+				Ref:                r.Ref,
+				Paths:              r.Paths,
+				Packages:           r.Packages,
+				Analyzer:           r.Analyzer,
+				UpdatePRs:          r.UpdatePRs,
+				Constraints:        r.Constraints,
+				Tags:               r.Tags,
+				Proxy:              cmp.Or(r.Proxy, wrapper.Default.Proxy),
+				CAFile:             cmp.Or(r.CAFile, wrapper.Default.CAFile),
+				InsecureSkipVerify: r.InsecureSkipVerify || wrapper.Default.InsecureSkipVerify,
+				ExcludePaths:       excludes,
+				Canonical:          r.Canonical,
 			})
 		}
 	}
 	s.RepositoriesCache = cache
 }
 
+// RepositoryTags returns the distinct tags of the cached repositories, sorted
+func (s *GUIState) RepositoryTags() []string {
+	repos := make([]config.RepoWithProvider, 0, len(s.RepositoriesCache))
+	for _, rc := range s.RepositoriesCache {
+		repos = append(repos, config.RepoWithProvider{Provider: rc.Provider, Config: config.RepoConfig{Tags: rc.Tags}})
+	}
+	return config.RepoTags(repos)
+}
+
 func repoCacheKey(provider, owner, repo, ref string) string {
 	return fmt.Sprintf("%s:%s/%s@%s", provider, owner, repo, ref)
 }
@@ -463,6 +623,8 @@ func (s *GUIState) TrimForCLIExport() *GUIState {
 	cp.TrackedPackages = nil
 	cp.Credentials = nil
 	cp.ErrorLog = nil
+	cp.AlertRules = nil
+	cp.Alerts = nil
 	cp.ReportHistory = nil
 	cp.Extensions = nil
 	cp.Meta = nil
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,8 +23,8 @@ func TestNewDefaultGUIState(t *testing.T) {
 	if state.Providers == nil {
 		t.Error("expected Providers map to be initialized")
 	}
-	if state.GUI.Theme != "light" {
-		t.Errorf("expected default theme 'light', got %s", state.GUI.Theme)
+	if state.GUI.Theme.Variant != "light" {
+		t.Errorf("expected default theme 'light', got %s", state.GUI.Theme.Variant)
 	}
 }
 
@@ -65,8 +66,10 @@ func TestSaveGUIState_LoadGUIState(t *testing.T) {
 	// Create and save a state
 	state := NewDefaultGUIState()
 	state.Profile = "integration-test"
-	state.GUI.Theme = "dark"
+	state.GUI.Theme = ThemeCfg{Variant: "dark"}
 	state.TrackedPackages = []string{"pkg1", "pkg2", "pkg3"}
+	state.GUI.Detached("Logs").Open = true
+	state.GUI.Detached("Logs").Window = WindowGeometry{Width: 800, Height: 600}
 
 	err := SaveGUIState(state, statePath)
 	if err != nil {
@@ -82,12 +85,18 @@ func TestSaveGUIState_LoadGUIState(t *testing.T) {
 	if loaded.Profile != "integration-test" {
 		t.Errorf("expected profile 'integration-test', got %s", loaded.Profile)
 	}
-	if loaded.GUI.Theme != "dark" {
-		t.Errorf("expected theme 'dark', got %s", loaded.GUI.Theme)
+	if loaded.GUI.Theme.Variant != "dark" {
+		t.Errorf("expected theme 'dark', got %s", loaded.GUI.Theme.Variant)
 	}
 	if len(loaded.TrackedPackages) != 3 {
 		t.Errorf("expected 3 tracked packages, got %d", len(loaded.TrackedPackages))
 	}
+	if !loaded.GUI.IsDetached("Logs") || loaded.GUI.Detached("Logs").Window.Width != 800 {
+		t.Errorf("expected the detached Logs window to round-trip, got %+v", loaded.GUI.DetachedViews["Logs"])
+	}
+	if loaded.GUI.IsDetached("Dependencies") {
+		t.Error("expected Dependencies to stay attached")
+	}
 }
 
 func TestLoadGUIState_NonExistentFile(t *testing.T) {
@@ -104,7 +113,7 @@ func TestNormalizeGUIState(t *testing.T) {
 	if state.Providers == nil {
 		t.Error("expected Providers to be initialized")
 	}
-	if state.GUI.Theme == "" {
+	if state.GUI.Theme.Variant == "" {
 		t.Error("expected Theme to be set to default")
 	}
 	if state.GUI.Concurrency.MaxWorkers == 0 {
@@ -224,7 +233,14 @@ func TestMergeCLIConfig(t *testing.T) {
 	defer func() { _ = os.Remove(tmpfile.Name()) }()
 
 	// Write a simple config
-	configContent := `providers:
+	configContent := `policies:
+  - name: django-lts
+    package: django
+    version: ">=4.2"
+timeouts:
+  run: 10m
+  request: 15s
+providers:
   github:
     default:
       owner: testowner
@@ -261,6 +277,19 @@ func TestMergeCLIConfig(t *testing.T) {
 	if len(state.GUI.RecentConfig) == 0 {
 		t.Error("expected recent config to be updated")
 	}
+
+	if len(state.Policies) != 1 || state.Policies[0].Name != "django-lts" {
+		t.Errorf("expected policies to be merged, got %+v", state.Policies)
+	}
+	if err := state.MergeCLIConfig(tmpfile.Name()); err != nil || len(state.Policies) != 1 {
+		t.Errorf("merging again should not duplicate policies: %v, %+v", err, state.Policies)
+	}
+	if state.GUI.ReportTimeout() != 10*time.Minute || state.GUI.Timeouts.Request != 15*time.Second {
+		t.Errorf("expected timeouts to be merged, got %+v", state.GUI.Timeouts)
+	}
+	if NewDefaultGUIState().GUI.ReportTimeout() != DefaultReportTimeout {
+		t.Error("expected the default report timeout when none is set")
+	}
 }
 
 func TestRebuildRepositoriesCache(t *testing.T) {
@@ -277,16 +306,30 @@ func TestRebuildRepositoriesCache(t *testing.T) {
 					Repository: "repo1",
 					Ref:        "main",
 					Analyzer:   "go",
+					Tags:       []string{"team-search"},
 				},
 			},
 		},
 		"gitlab": {
+			Default: config.RepoDefaults{
+				Token:   "internal-token",
+				BaseURL: "https://gitlab.example.com",
+				CAFile:  "/etc/ssl/internal-ca.pem",
+			},
 			Repositories: []config.RepoConfig{
 				{
 					Owner:      "owner2",
 					Repository: "repo2",
 					Ref:        "develop",
 					Analyzer:   "python",
+					Tags:       []string{"deprecated", "team-search"},
+				},
+				{
+					Owner:      "owner3",
+					Repository: "repo3",
+					Analyzer:   "python",
+					BaseURL:    "https://gitlab.com",
+					Token:      "public-token",
 				},
 			},
 		},
@@ -294,8 +337,22 @@ func TestRebuildRepositoriesCache(t *testing.T) {
 
 	state.RebuildRepositoriesCache()
 
-	if len(state.RepositoriesCache) != 2 {
-		t.Errorf("expected 2 cached repositories, got %d", len(state.RepositoriesCache))
+	if len(state.RepositoriesCache) != 3 {
+		t.Errorf("expected 3 cached repositories, got %d", len(state.RepositoriesCache))
+	}
+
+	// Per-repository endpoints override the provider default
+	for _, entry := range state.RepositoriesCache {
+		switch entry.Repository {
+		case "repo2":
+			if entry.BaseURL != "https://gitlab.example.com" || entry.Token != "internal-token" || entry.CAFile != "/etc/ssl/internal-ca.pem" {
+				t.Errorf("repo2 should inherit the provider endpoint, got %+v", entry)
+			}
+		case "repo3":
+			if entry.BaseURL != "https://gitlab.com" || entry.Token != "public-token" {
+				t.Errorf("repo3 should keep its own endpoint, got %+v", entry)
+			}
+		}
 	}
 
 	// Verify entries have correct data
@@ -310,6 +367,10 @@ func TestRebuildRepositoriesCache(t *testing.T) {
 			t.Error("expected repository to be set")
 		}
 	}
+
+	if got := fmt.Sprint(state.RepositoryTags()); got != "[deprecated team-search]" {
+		t.Errorf("RepositoryTags = %s, want [deprecated team-search]", got)
+	}
 }
 
 func TestRepoCacheKey(t *testing.T) {
@@ -367,7 +428,7 @@ func TestGUIState_WriteTo(t *testing.T) {
 func TestTrimForCLIExport(t *testing.T) {
 	state := NewDefaultGUIState()
 	state.Profile = "test-profile"
-	state.GUI.Theme = "dark"
+	state.GUI.Theme = ThemeCfg{Variant: "dark"}
 	state.GUI.RecentConfig = []string{"/path/to/config.yaml"}
 	state.Credentials = &CredentialSnapshot{
 		GitHubToken: "ghp_secret",
@@ -378,7 +439,7 @@ func TestTrimForCLIExport(t *testing.T) {
 
 	trimmed := state.TrimForCLIExport()
 
-	if trimmed.GUI.Theme != "" {
+	if trimmed.GUI.Theme.Variant != "" {
 		t.Error("expected GUI section to be cleared")
 	}
 	if len(trimmed.GUI.RecentConfig) != 0 {
@@ -555,3 +616,26 @@ func TestErrorLogEntry_Structure(t *testing.T) {
 		t.Error("expected time to match")
 	}
 }
+
+func TestMergeCLIConfigIgnores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `providers: {}
+ignores:
+  - repository: myorg/legacy-app
+    package: django
+    reason: pinned until Q3
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	state := NewDefaultGUIState()
+	for range 2 {
+		if err := state.MergeCLIConfig(path); err != nil {
+			t.Fatalf("MergeCLIConfig failed: %v", err)
+		}
+	}
+	if len(state.Ignores) != 1 || state.Ignores[0].Reason != "pinned until Q3" {
+		t.Errorf("expected one merged ignore, got %+v", state.Ignores)
+	}
+}
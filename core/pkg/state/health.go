@@ -0,0 +1,75 @@
+package state
+
+import (
+	"sort"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// RepositoryHealth combines what the GUI knows about one configured
+// repository: its results in the current report and its logged errors.
+// Provider metadata (repository.Info) is fetched separately.
+type RepositoryHealth struct {
+	Key string // provider:owner/repo@ref, as in ErrorLogEntry.Repository
+
+	// Analyzed is true when the current report includes the repository; the
+	// fields below it are only set then
+	Analyzed      bool
+	CommitSHA     string
+	Cached        bool
+	ErrorCategory report.ErrorCategory // Empty when the analysis succeeded
+	Error         string
+	Found         int      // Tracked packages with a resolved version
+	Missing       []string // Tracked packages not found, sorted
+	Locked        int      // Packages in the dependency graph; 0 without graphs
+
+	// LastError is the most recent ErrorLog entry for the repository, from
+	// any run; nil when none is logged
+	LastError *ErrorLogEntry
+}
+
+// RepositoryKey returns the key identifying entry in reports and ErrorLog
+func (e RepoCacheEntry) RepositoryKey() string {
+	return repoCacheKey(e.Provider, e.Owner, e.Repository, e.Ref)
+}
+
+// RepositoryHealth summarizes entry from rpt (which may be nil) and ErrorLog
+func (s *GUIState) RepositoryHealth(entry RepoCacheEntry, rpt *report.Report) RepositoryHealth {
+	h := RepositoryHealth{Key: entry.RepositoryKey()}
+	for i := len(s.ErrorLog) - 1; i >= 0; i-- {
+		if s.ErrorLog[i].Repository == h.Key {
+			e := s.ErrorLog[i]
+			h.LastError = &e
+			break
+		}
+	}
+	if rpt == nil {
+		return h
+	}
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		if rr.Key() != h.Key {
+			continue
+		}
+		h.Analyzed = true
+		h.CommitSHA = rr.CommitSHA
+		h.Cached = rr.Cached
+		if rr.Error != nil {
+			h.ErrorCategory = rr.ErrorCategory()
+			h.Error = rr.Error.Error()
+		}
+		for name, version := range rr.Dependencies {
+			if version == "" {
+				h.Missing = append(h.Missing, name)
+			} else {
+				h.Found++
+			}
+		}
+		sort.Strings(h.Missing)
+		if rr.Graph != nil {
+			h.Locked = len(rr.Graph.Nodes)
+		}
+		break
+	}
+	return h
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestRepositoryHealth(t *testing.T) {
+	st := NewDefaultGUIState()
+	api := RepoCacheEntry{Provider: "github", Owner: "org", Repository: "api", Ref: "main"}
+	web := RepoCacheEntry{Provider: "github", Owner: "org", Repository: "web", Ref: "main"}
+	st.ErrorLog = []ErrorLogEntry{
+		{RunID: "run1", Message: "old failure", Repository: "github:org/web@main"},
+		{RunID: "run2", Message: "new failure", Repository: "github:org/web@main"},
+		{RunID: "run2", Message: "unrelated", Repository: "github:org/other@main"},
+	}
+
+	if h := st.RepositoryHealth(api, nil); h.Analyzed || h.LastError != nil || h.Key != "github:org/api@main" {
+		t.Errorf("health without report = %+v", h)
+	}
+
+	rpt := &report.Report{Repositories: []report.RepositoryReport{
+		{Provider: "github", Owner: "org", Repository: "api", Ref: "main", CommitSHA: "abc123",
+			Dependencies: map[string]string{"requests": "2.31.0", "django": "", "flask": ""},
+			Graph:        &dependencies.Graph{Nodes: []dependencies.GraphNode{{Name: "requests"}, {Name: "urllib3"}}}},
+		{Provider: "github", Owner: "org", Repository: "web", Ref: "main", Error: report.NewErrorDetail(errors.New("401 Bad credentials"))},
+	}}
+	h := st.RepositoryHealth(api, rpt)
+	if !h.Analyzed || h.CommitSHA != "abc123" || h.Found != 1 || h.Locked != 2 || h.Error != "" {
+		t.Errorf("api health = %+v", h)
+	}
+	if len(h.Missing) != 2 || h.Missing[0] != "django" {
+		t.Errorf("missing = %v, want sorted [django flask]", h.Missing)
+	}
+
+	h = st.RepositoryHealth(web, rpt)
+	if h.ErrorCategory != report.CategorizeError(errors.New("401 Bad credentials")) || h.Error == "" {
+		t.Errorf("web error = %q (%s)", h.Error, h.ErrorCategory)
+	}
+	if h.LastError == nil || h.LastError.Message != "new failure" {
+		t.Errorf("last error = %+v", h.LastError)
+	}
+}
@@ -0,0 +1,256 @@
+package state
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// Repository import formats accepted by ParseRepositoryImport
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+// DefaultImportAnalyzer is used for imported rows when neither the row nor
+// its provider defaults name an analyzer
+const DefaultImportAnalyzer = "poetry"
+
+// ImportFormatForPath picks the import format from a file extension
+func ImportFormatForPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ImportFormatCSV, nil
+	case ".json":
+		return ImportFormatJSON, nil
+	default:
+		return "", fmt.Errorf("import: unsupported file type %q (expected .csv or .json)", filepath.Ext(path))
+	}
+}
+
+// importRecord is one repository row of an import file. List fields are
+// semicolon-separated in CSV.
+type importRecord struct {
+	Provider   string   `json:"provider"`
+	Owner      string   `json:"owner"`
+	Repository string   `json:"repository"`
+	Repo       string   `json:"repo"` // Alias for Repository
+	Ref        string   `json:"ref"`
+	Analyzer   string   `json:"analyzer"`
+	Paths      []string `json:"paths"`
+	Packages   []string `json:"packages"`
+	Tags       []string `json:"tags"`
+}
+
+// ParseRepositoryImport reads repository rows from a CSV file with a header
+// row (provider, owner, repository or repo, ref, and optionally analyzer,
+// paths, packages, tags) or a JSON array of objects with the same keys.
+// Rows are returned as read; PlanRepositoryImport validates them.
+func ParseRepositoryImport(r io.Reader, format string) ([]RepoCacheEntry, error) {
+	var records []importRecord
+	switch format {
+	case ImportFormatCSV:
+		var err error
+		if records, err = parseImportCSV(r); err != nil {
+			return nil, err
+		}
+	case ImportFormatJSON:
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, fmt.Errorf("import: invalid JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("import: unsupported format %q", format)
+	}
+
+	rows := make([]RepoCacheEntry, 0, len(records))
+	for _, rec := range records {
+		repo := rec.Repository
+		if repo == "" {
+			repo = rec.Repo
+		}
+		rows = append(rows, RepoCacheEntry{
+			Provider:   strings.ToLower(strings.TrimSpace(rec.Provider)),
+			Owner:      strings.TrimSpace(rec.Owner),
+			Repository: strings.TrimSpace(repo),
+			Ref:        strings.TrimSpace(rec.Ref),
+			Analyzer:   strings.ToLower(strings.TrimSpace(rec.Analyzer)),
+			Paths:      rec.Paths,
+			Packages:   rec.Packages,
+			Tags:       config.MergeTags(rec.Tags),
+		})
+	}
+	return rows, nil
+}
+
+func parseImportCSV(r io.Reader) ([]importRecord, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("import: CSV file is empty")
+		}
+		return nil, fmt.Errorf("import: invalid CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"provider", "owner"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("import: CSV header missing %q column", required)
+		}
+	}
+	_, hasRepository := columns["repository"]
+	_, hasRepo := columns["repo"]
+	if !hasRepository && !hasRepo {
+		return nil, errors.New(`import: CSV header missing "repository" column`)
+	}
+
+	var records []importRecord
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("import: invalid CSV: %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		list := func(name string) []string {
+			var out []string
+			for _, item := range strings.Split(field(name), ";") {
+				if item = strings.TrimSpace(item); item != "" {
+					out = append(out, item)
+				}
+			}
+			return out
+		}
+		records = append(records, importRecord{
+			Provider:   field("provider"),
+			Owner:      field("owner"),
+			Repository: field("repository"),
+			Repo:       field("repo"),
+			Ref:        field("ref"),
+			Analyzer:   field("analyzer"),
+			Paths:      list("paths"),
+			Packages:   list("packages"),
+			Tags:       list("tags"),
+		})
+	}
+}
+
+// ImportProblem explains why an import row was rejected. Row is the 1-based
+// position among the data rows.
+type ImportProblem struct {
+	Row     int
+	Message string
+}
+
+// RepositoryImportPlan is the preview of an import: rows that would be added,
+// rows skipped as duplicates (already configured or repeated in the file)
+// and rows rejected as invalid.
+type RepositoryImportPlan struct {
+	Add        []RepoCacheEntry
+	Duplicates []RepoCacheEntry
+	Invalid    []ImportProblem
+}
+
+// PlanRepositoryImport validates rows and sorts them into a plan without
+// modifying the state. Missing owner, ref and analyzer values are taken from
+// the provider defaults; a row still lacking an analyzer uses
+// DefaultImportAnalyzer.
+func (s *GUIState) PlanRepositoryImport(rows []RepoCacheEntry) RepositoryImportPlan {
+	var plan RepositoryImportPlan
+	seen := make(map[string]bool, len(s.RepositoriesCache)+len(rows))
+	for _, r := range s.RepositoriesCache {
+		seen[repoCacheKey(r.Provider, r.Owner, r.Repository, r.Ref)] = true
+	}
+
+	for i, row := range rows {
+		reject := func(format string, args ...any) {
+			plan.Invalid = append(plan.Invalid, ImportProblem{Row: i + 1, Message: fmt.Sprintf(format, args...)})
+		}
+		if !slices.Contains(repository.SupportedProviders(), row.Provider) {
+			reject("unsupported provider %q", row.Provider)
+			continue
+		}
+		defaults := s.Providers[row.Provider].Default
+		if row.Owner == "" {
+			row.Owner = defaults.Owner
+		}
+		if row.Ref == "" {
+			row.Ref = defaults.Ref
+		}
+		if row.Analyzer == "" {
+			row.Analyzer = defaults.Analyzer
+		}
+		if row.Analyzer == "" {
+			row.Analyzer = DefaultImportAnalyzer
+		}
+		switch {
+		case row.Owner == "":
+			reject("missing owner")
+			continue
+		case row.Repository == "":
+			reject("missing repository")
+			continue
+		case !slices.Contains(dependencies.SupportedAnalyzers(), row.Analyzer):
+			reject("unsupported analyzer %q", row.Analyzer)
+			continue
+		}
+		if err := config.ValidateNamespace(row.Provider, row.Owner, row.Repository); err != nil {
+			reject("%v", err)
+			continue
+		}
+
+		key := repoCacheKey(row.Provider, row.Owner, row.Repository, row.Ref)
+		if seen[key] {
+			plan.Duplicates = append(plan.Duplicates, row)
+			continue
+		}
+		seen[key] = true
+		plan.Add = append(plan.Add, row)
+	}
+	return plan
+}
+
+// ApplyRepositoryImport appends the plan's new repositories to their
+// providers and rebuilds the cache. It returns the number added.
+func (s *GUIState) ApplyRepositoryImport(plan RepositoryImportPlan) int {
+	if len(plan.Add) == 0 {
+		return 0
+	}
+	if s.Providers == nil {
+		s.Providers = map[string]ProviderConfigWrapper{}
+	}
+	for _, row := range plan.Add {
+		wrapper := s.Providers[row.Provider]
+		wrapper.Repositories = append(wrapper.Repositories, config.RepoConfig{
+			Owner:      row.Owner,
+			Repository: row.Repository,
+			Ref:        row.Ref,
+			Paths:      row.Paths,
+			Packages:   row.Packages,
+			Analyzer:   row.Analyzer,
+			Tags:       row.Tags,
+		})
+		s.Providers[row.Provider] = wrapper
+	}
+	s.RebuildRepositoriesCache()
+	return len(plan.Add)
+}
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+func TestImportFormatForPath(t *testing.T) {
+	for path, want := range map[string]string{"repos.CSV": ImportFormatCSV, "/tmp/inventory.json": ImportFormatJSON} {
+		if got, err := ImportFormatForPath(path); err != nil || got != want {
+			t.Errorf("ImportFormatForPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := ImportFormatForPath("repos.yaml"); err == nil {
+		t.Error("expected error for unsupported extension")
+	}
+}
+
+func TestParseRepositoryImport(t *testing.T) {
+	csvInput := `Provider, Owner, Repo, Ref, Tags
+GitHub, org, api, main, team-payments;deprecated
+gitlab, grp, web, ,
+`
+	jsonInput := `[
+  {"provider": "GitHub", "owner": "org", "repository": "api", "ref": "main", "tags": ["team-payments", "deprecated"]},
+  {"provider": "gitlab", "owner": "grp", "repo": "web"}
+]`
+
+	for format, input := range map[string]string{ImportFormatCSV: csvInput, ImportFormatJSON: jsonInput} {
+		t.Run(format, func(t *testing.T) {
+			rows, err := ParseRepositoryImport(strings.NewReader(input), format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(rows) != 2 {
+				t.Fatalf("expected 2 rows, got %d", len(rows))
+			}
+			got := fmt.Sprintf("%s:%s/%s@%s %v", rows[0].Provider, rows[0].Owner, rows[0].Repository, rows[0].Ref, rows[0].Tags)
+			if got != "github:org/api@main [team-payments deprecated]" {
+				t.Errorf("row 1 = %s", got)
+			}
+			if rows[1].Repository != "web" || rows[1].Ref != "" {
+				t.Errorf("row 2 = %+v", rows[1])
+			}
+		})
+	}
+
+	for name, tt := range map[string]struct{ input, format string }{
+		"missing column": {"provider,owner\ngithub,org\n", ImportFormatCSV},
+		"empty csv":      {"", ImportFormatCSV},
+		"bad json":       {"{", ImportFormatJSON},
+		"bad format":     {"", "xml"},
+	} {
+		if _, err := ParseRepositoryImport(strings.NewReader(tt.input), tt.format); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestPlanAndApplyRepositoryImport(t *testing.T) {
+	st := NewDefaultGUIState()
+	st.Providers = map[string]ProviderConfigWrapper{
+		"github": {
+			Default:      config.RepoDefaults{Owner: "org", Ref: "main", Analyzer: "uvlock"},
+			Repositories: []config.RepoConfig{{Owner: "org", Repository: "api", Ref: "main", Analyzer: "poetry"}},
+		},
+	}
+	st.RebuildRepositoriesCache()
+
+	plan := st.PlanRepositoryImport([]RepoCacheEntry{
+		{Provider: "github", Repository: "api"},                      // duplicate after defaults
+		{Provider: "github", Repository: "web", Tags: []string{"x"}}, // added with defaults
+		{Provider: "github", Repository: "web"},                      // repeated in file
+		{Provider: "gitlab", Owner: "grp/sub", Repository: "cli"},    // added with fallback analyzer
+		{Provider: "bitbucket", Owner: "o", Repository: "r"},
+		{Provider: "gitlab", Repository: "nameless"},
+		{Provider: "gitlab", Owner: "grp", Repository: "r", Analyzer: "maven"},
+		{Provider: "github", Owner: "org/team", Repository: "r"},
+	})
+
+	if len(plan.Add) != 2 || len(plan.Duplicates) != 2 || len(plan.Invalid) != 4 {
+		t.Fatalf("plan = %d add, %d duplicates, %d invalid; want 2, 2, 4", len(plan.Add), len(plan.Duplicates), len(plan.Invalid))
+	}
+	if a := plan.Add[0]; a.Owner != "org" || a.Ref != "main" || a.Analyzer != "uvlock" {
+		t.Errorf("provider defaults not applied: %+v", a)
+	}
+	if plan.Add[1].Analyzer != DefaultImportAnalyzer {
+		t.Errorf("expected fallback analyzer, got %q", plan.Add[1].Analyzer)
+	}
+	if got := fmt.Sprint(plan.Invalid); got != `[{5 unsupported provider "bitbucket"} {6 missing owner} {7 unsupported analyzer "maven"} {8 owner "org/team": github does not support nested namespaces}]` {
+		t.Errorf("invalid = %s", got)
+	}
+	if len(st.RepositoriesCache) != 1 {
+		t.Fatal("planning should not modify the state")
+	}
+
+	if n := st.ApplyRepositoryImport(plan); n != 2 {
+		t.Errorf("applied %d, want 2", n)
+	}
+	if len(st.RepositoriesCache) != 3 || len(st.Providers["gitlab"].Repositories) != 1 {
+		t.Errorf("expected 3 cached repositories and a new gitlab provider, got %d", len(st.RepositoriesCache))
+	}
+	if again := st.PlanRepositoryImport(plan.Add); len(again.Add) != 0 {
+		t.Errorf("re-importing should only find duplicates, got %+v", again.Add)
+	}
+}
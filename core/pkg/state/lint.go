@@ -0,0 +1,81 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lint rule identifiers reported in RepoLintWarning.Rule.
+const (
+	LintMissingAnalyzer = "missing-analyzer"
+	LintEmptyRef        = "empty-ref"
+	LintNoPackages      = "no-packages"
+	LintDuplicate       = "duplicate"
+	LintMissingToken    = "missing-token"
+)
+
+// RepoLintWarning describes a configuration problem detected on a cached
+// repository entry before a report is run.
+type RepoLintWarning struct {
+	Rule    string // Stable rule identifier (Lint* constants)
+	Message string // Human-readable explanation
+}
+
+// LintOptions supplies facts the lint rules cannot derive from GUIState alone.
+// Nil callbacks are treated as returning false.
+type LintOptions struct {
+	// HasProviderToken reports whether a provider-level token resolves
+	// (environment, state credentials or credential store).
+	HasProviderToken func(provider string) bool
+
+	// AuthFailed reports whether the most recent report saw an
+	// authentication failure for the entry.
+	AuthFailed func(entry RepoCacheEntry) bool
+}
+
+// LintRepositories validates RepositoriesCache and returns warnings keyed by
+// cache index. Entries without problems are absent from the map.
+func (s *GUIState) LintRepositories(opts LintOptions) map[int][]RepoLintWarning {
+	out := make(map[int][]RepoLintWarning)
+
+	seen := make(map[string]int, len(s.RepositoriesCache))
+	ownersWithTokens := make(map[string]bool)
+	for _, r := range s.RepositoriesCache {
+		key := repoCacheKey(r.Provider, r.Owner, r.Repository, r.Ref)
+		seen[key]++
+		if r.Token != "" {
+			ownersWithTokens[r.Provider+":"+strings.ToLower(r.Owner)] = true
+		}
+	}
+
+	for i, r := range s.RepositoriesCache {
+		var warnings []RepoLintWarning
+		add := func(rule, format string, args ...any) {
+			warnings = append(warnings, RepoLintWarning{Rule: rule, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if strings.TrimSpace(r.Analyzer) == "" {
+			add(LintMissingAnalyzer, "No analyzer configured; the report will fail for this repository")
+		}
+		if strings.TrimSpace(r.Ref) == "" {
+			add(LintEmptyRef, "Ref is empty; the provider's default branch will be used")
+		}
+		if len(r.Packages) == 0 && len(s.TrackedPackages) > 0 {
+			add(LintNoPackages, "Packages list is empty while %d tracked packages are set", len(s.TrackedPackages))
+		}
+		if seen[repoCacheKey(r.Provider, r.Owner, r.Repository, r.Ref)] > 1 {
+			add(LintDuplicate, "Duplicate entry for %s/%s@%s", r.Owner, r.Repository, r.Ref)
+		}
+		if r.Token == "" && (opts.HasProviderToken == nil || !opts.HasProviderToken(r.Provider)) {
+			authFailed := opts.AuthFailed != nil && opts.AuthFailed(r)
+			if authFailed || ownersWithTokens[r.Provider+":"+strings.ToLower(r.Owner)] {
+				add(LintMissingToken, "No %s token available but %s appears to be private", r.Provider, r.Owner)
+			}
+		}
+
+		if len(warnings) > 0 {
+			out[i] = warnings
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"testing"
+)
+
+func lintRules(warnings []RepoLintWarning) map[string]bool {
+	rules := make(map[string]bool, len(warnings))
+	for _, w := range warnings {
+		rules[w.Rule] = true
+	}
+	return rules
+}
+
+func TestLintRepositories(t *testing.T) {
+	st := NewDefaultGUIState()
+	st.TrackedPackages = []string{"django"}
+	st.RepositoriesCache = []RepoCacheEntry{
+		{Provider: "github", Owner: "acme", Repository: "ok", Ref: "main", Analyzer: "poetry", Packages: []string{"django"}},
+		{Provider: "github", Owner: "acme", Repository: "bare", Ref: "", Analyzer: ""},
+		{Provider: "github", Owner: "acme", Repository: "dup", Ref: "main", Analyzer: "poetry", Packages: []string{"x"}},
+		{Provider: "github", Owner: "acme", Repository: "dup", Ref: "main", Analyzer: "poetry", Packages: []string{"x"}},
+	}
+
+	got := st.LintRepositories(LintOptions{})
+
+	if _, ok := got[0]; ok {
+		t.Errorf("expected no warnings for well-formed entry, got %+v", got[0])
+	}
+	bare := lintRules(got[1])
+	for _, rule := range []string{LintMissingAnalyzer, LintEmptyRef, LintNoPackages} {
+		if !bare[rule] {
+			t.Errorf("expected rule %s on bare entry, got %+v", rule, got[1])
+		}
+	}
+	if !lintRules(got[2])[LintDuplicate] || !lintRules(got[3])[LintDuplicate] {
+		t.Errorf("expected duplicate warnings on both entries, got %+v / %+v", got[2], got[3])
+	}
+	if bare[LintMissingToken] {
+		t.Error("did not expect missing-token without evidence of a private owner")
+	}
+}
+
+func TestLintRepositories_MissingToken(t *testing.T) {
+	st := NewDefaultGUIState()
+	st.RepositoriesCache = []RepoCacheEntry{
+		{Provider: "github", Owner: "Private", Repository: "a", Ref: "main", Analyzer: "poetry", Token: "secret"},
+		{Provider: "github", Owner: "private", Repository: "b", Ref: "main", Analyzer: "poetry"},
+		{Provider: "gitlab", Owner: "grp", Repository: "c", Ref: "main", Analyzer: "poetry"},
+	}
+
+	got := st.LintRepositories(LintOptions{
+		AuthFailed: func(e RepoCacheEntry) bool { return e.Repository == "c" },
+	})
+	if !lintRules(got[1])[LintMissingToken] {
+		t.Errorf("expected missing-token when sibling repo of owner has a token, got %+v", got[1])
+	}
+	if !lintRules(got[2])[LintMissingToken] {
+		t.Errorf("expected missing-token after auth failure, got %+v", got[2])
+	}
+
+	withProviderTokens := st.LintRepositories(LintOptions{
+		HasProviderToken: func(string) bool { return true },
+		AuthFailed:       func(RepoCacheEntry) bool { return true },
+	})
+	if lintRules(withProviderTokens[1])[LintMissingToken] || lintRules(withProviderTokens[2])[LintMissingToken] {
+		t.Error("provider-level token should suppress missing-token warnings")
+	}
+}
@@ -0,0 +1,117 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrStateLocked is returned when another process holds the state file lock
+// for longer than the lock wait.
+var ErrStateLocked = errors.New("state: state file is locked by another process")
+
+// ErrStateModified is returned by the IfUnmodified savers when the file on
+// disk was written by another process (a second GUI instance or a scheduled
+// CLI run) after the in-memory state was loaded or last saved.
+var ErrStateModified = errors.New("state: state file was modified by another process")
+
+// Lock tuning. A lock file is only held while a save runs, so one older than
+// stateLockStale was left behind by a crashed process and is reclaimed.
+var (
+	stateLockWait  = 5 * time.Second
+	stateLockStale = 30 * time.Second
+	stateLockPoll  = 50 * time.Millisecond
+)
+
+// lockStateFile takes an exclusive lock on path by creating path+".lock".
+// The lock is advisory and portable (no flock), so it only protects writers
+// that go through this package.
+func lockStateFile(path string) (unlock func(), err error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(stateLockWait)
+	for {
+		// #nosec G304 lock path derives from the state path chosen by the caller
+		f, err := os.OpenFile(filepath.Clean(lockPath), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("state: lock failed: %w", err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > stateLockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s", ErrStateLocked, lockPath)
+		}
+		time.Sleep(stateLockPoll)
+	}
+}
+
+// ReadSavedAt returns the savedAt stamp of the state file at path (default
+// path when empty) without loading the rest of it. A missing file returns
+// the zero time.
+func ReadSavedAt(path string) (time.Time, error) {
+	if path == "" {
+		path = DefaultGUIStatePath()
+	}
+	// #nosec G304 path chosen by the caller, read only
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("state: read failed: %w", err)
+	}
+	var stamp struct {
+		SavedAt time.Time `yaml:"savedAt"`
+	}
+	if err := yaml.Unmarshal(data, &stamp); err != nil {
+		return time.Time{}, fmt.Errorf("state: parse failed: %w", err)
+	}
+	return stamp.SavedAt, nil
+}
+
+// SaveGUIStateIfUnmodified saves like SaveGUIState, but first compares the
+// savedAt stamp on disk with st.SavedAt (the stamp st was loaded or last
+// saved with) and returns ErrStateModified instead of overwriting another
+// process's write. The check and the write happen under the same lock.
+func SaveGUIStateIfUnmodified(st *GUIState, path string) error {
+	if st == nil {
+		return errors.New("state: nil GUIState")
+	}
+	if path == "" {
+		path = DefaultGUIStatePath()
+	}
+	return withStateLock(path, func() error {
+		onDisk, err := ReadSavedAt(path)
+		if err != nil {
+			return err
+		}
+		if !onDisk.IsZero() && !onDisk.Equal(st.SavedAt) {
+			return fmt.Errorf("%w (on disk %s, loaded %s)", ErrStateModified,
+				onDisk.Format(time.RFC3339), st.SavedAt.Format(time.RFC3339))
+		}
+		return writeGUIState(st, path)
+	})
+}
+
+// withStateLock creates the state directory and runs fn holding the lock
+func withStateLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("state: mkdir failed: %w", err)
+	}
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
@@ -0,0 +1,84 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockStateFile(t *testing.T) {
+	defer func(wait, stale time.Duration) { stateLockWait, stateLockStale = wait, stale }(stateLockWait, stateLockStale)
+	stateLockWait = 100 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "gui_state.yaml")
+
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if _, err := lockStateFile(path); !errors.Is(err, ErrStateLocked) {
+		t.Fatalf("second lock should fail with ErrStateLocked, got %v", err)
+	}
+	unlock()
+	unlock, err = lockStateFile(path)
+	if err != nil {
+		t.Fatalf("lock after unlock: %v", err)
+	}
+	defer unlock()
+
+	// A lock left behind by a crashed process is reclaimed once stale
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+	stateLockStale = 30 * time.Second
+	reclaimed, err := lockStateFile(path)
+	if err != nil {
+		t.Fatalf("stale lock not reclaimed: %v", err)
+	}
+	reclaimed()
+}
+
+func TestSaveGUIStateIfUnmodified(t *testing.T) {
+	useTempConfigDir(t)
+	path := DefaultGUIStatePath()
+
+	mine := NewDefaultGUIState()
+	if err := SaveGUIStateIfUnmodified(mine, path); err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+	if err := SaveGUIStateIfUnmodified(mine, path); err != nil {
+		t.Fatalf("save over own write: %v", err)
+	}
+
+	// Another instance loads, edits and saves
+	other, err := LoadGUIState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.TrackedPackages = []string{"requests"}
+	time.Sleep(time.Millisecond)
+	if err := SaveGUIState(other, path); err != nil {
+		t.Fatal(err)
+	}
+
+	stamp := mine.SavedAt
+	if err := SaveGUIStateIfUnmodified(mine, path); !errors.Is(err, ErrStateModified) {
+		t.Fatalf("expected ErrStateModified, got %v", err)
+	}
+	if !mine.SavedAt.Equal(stamp) {
+		t.Error("a refused save must not change SavedAt")
+	}
+	onDisk, err := ReadSavedAt(path)
+	if err != nil || !onDisk.Equal(other.SavedAt) {
+		t.Errorf("ReadSavedAt = %v, %v; want %v", onDisk, err, other.SavedAt)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file should be removed after saving, stat err %v", err)
+	}
+
+	if missing, err := ReadSavedAt(filepath.Join(t.TempDir(), "none.yaml")); err != nil || !missing.IsZero() {
+		t.Errorf("missing file: %v, %v", missing, err)
+	}
+}
@@ -0,0 +1,143 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+)
+
+// onboardingMetaKey records in GUIState.Meta that the first-run wizard was
+// completed or dismissed
+const onboardingMetaKey = "onboarding"
+
+// Onboarding values for Meta[onboardingMetaKey]
+const (
+	OnboardingDone    = "done"
+	OnboardingSkipped = "skipped"
+)
+
+// Onboarding holds the answers of the GUI's first-run wizard
+type Onboarding struct {
+	Provider string // "github" or "gitlab"
+	BaseURL  string // Optional API base URL (GitHub Enterprise, self-hosted GitLab)
+	Token    string
+	Owner    string // Default owner for repositories given without one
+	// Repositories as "owner/name" or "name"; GitLab owners may be nested
+	// group paths ("group/subgroup/name")
+	Repositories []string
+	Analyzer     string   // Empty uses DefaultImportAnalyzer
+	Packages     []string // Tracked packages, also set on each repository
+}
+
+// NeedsOnboarding reports whether the first-run wizard should be offered: no
+// repositories are configured and the wizard was neither completed nor
+// dismissed
+func (s *GUIState) NeedsOnboarding() bool {
+	if s.Meta[onboardingMetaKey] != "" {
+		return false
+	}
+	for _, wrapper := range s.Providers {
+		if len(wrapper.Repositories) > 0 {
+			return false
+		}
+	}
+	return len(s.RepositoriesCache) == 0
+}
+
+// SkipOnboarding records that the user dismissed the first-run wizard
+func (s *GUIState) SkipOnboarding() {
+	if s.Meta == nil {
+		s.Meta = map[string]string{}
+	}
+	s.Meta[onboardingMetaKey] = OnboardingSkipped
+}
+
+// ApplyOnboarding writes the wizard's answers into the state: the token into
+// the credentials snapshot, the base URL and owner into the provider
+// defaults, the repositories (validated like an import) into the provider and
+// the packages into TrackedPackages. It returns the import plan so callers
+// can report duplicates and rejected rows, and fails without changing the
+// state when no repository can be added.
+func (s *GUIState) ApplyOnboarding(o Onboarding) (RepositoryImportPlan, error) {
+	provider := strings.ToLower(strings.TrimSpace(o.Provider))
+	if !slices.Contains(repository.SupportedProviders(), provider) {
+		return RepositoryImportPlan{}, fmt.Errorf("state: onboarding: unsupported provider %q", o.Provider)
+	}
+	owner := strings.TrimSpace(o.Owner)
+	packages := canonicalPackageList(o.Packages)
+
+	rows := make([]RepoCacheEntry, 0, len(o.Repositories))
+	for _, name := range o.Repositories {
+		name = strings.Trim(strings.TrimSpace(name), "/")
+		if name == "" {
+			continue
+		}
+		row := RepoCacheEntry{
+			Provider:   provider,
+			Owner:      owner,
+			Repository: name,
+			Analyzer:   strings.ToLower(strings.TrimSpace(o.Analyzer)),
+			Packages:   packages,
+		}
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			row.Owner, row.Repository = name[:i], name[i+1:]
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return RepositoryImportPlan{}, errors.New("state: onboarding: no repositories selected")
+	}
+
+	plan := s.PlanRepositoryImport(rows)
+	if len(plan.Add) == 0 {
+		return plan, fmt.Errorf("state: onboarding: none of the %d repositories can be added (%d duplicate, %d invalid)",
+			len(rows), len(plan.Duplicates), len(plan.Invalid))
+	}
+
+	if s.Providers == nil {
+		s.Providers = map[string]ProviderConfigWrapper{}
+	}
+	wrapper := s.Providers[provider]
+	if baseURL := strings.TrimSpace(o.BaseURL); baseURL != "" {
+		wrapper.Default.BaseURL = baseURL
+	}
+	if wrapper.Default.Owner == "" {
+		wrapper.Default.Owner = owner
+	}
+	s.Providers[provider] = wrapper
+
+	if token := strings.TrimSpace(o.Token); token != "" {
+		if s.Credentials == nil {
+			s.Credentials = &CredentialSnapshot{}
+		}
+		if provider == "gitlab" {
+			s.Credentials.GitLabToken = token
+		} else {
+			s.Credentials.GitHubToken = token
+		}
+	}
+
+	s.ApplyRepositoryImport(plan)
+	if len(packages) > 0 {
+		s.TrackedPackages = packages
+	}
+	if s.Meta == nil {
+		s.Meta = map[string]string{}
+	}
+	s.Meta[onboardingMetaKey] = OnboardingDone
+	return plan, nil
+}
+
+// canonicalPackageList trims and de-duplicates package names, keeping order
+func canonicalPackageList(names []string) []string {
+	var out []string
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" && !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package state
+
+import (
+	"testing"
+)
+
+func TestApplyOnboarding(t *testing.T) {
+	st := NewDefaultGUIState()
+	if !st.NeedsOnboarding() {
+		t.Fatal("new state should need onboarding")
+	}
+
+	if _, err := st.ApplyOnboarding(Onboarding{Provider: "bitbucket", Repositories: []string{"a/b"}}); err == nil {
+		t.Error("expected unsupported provider to be rejected")
+	}
+	if _, err := st.ApplyOnboarding(Onboarding{Provider: "github", Repositories: []string{"api"}}); err == nil {
+		t.Error("expected a repository without owner to be rejected")
+	}
+	if !st.NeedsOnboarding() || st.Credentials != nil {
+		t.Fatal("failed onboarding changed the state")
+	}
+
+	plan, err := st.ApplyOnboarding(Onboarding{
+		Provider:     "GitLab",
+		BaseURL:      "https://gitlab.example.com",
+		Token:        " glpat-x ",
+		Owner:        "platform",
+		Repositories: []string{"api", "platform/infra/deploy", "api", " "},
+		Packages:     []string{"requests", " requests", "django"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyOnboarding: %v", err)
+	}
+	if len(plan.Add) != 2 || len(plan.Duplicates) != 1 {
+		t.Errorf("plan = %+v, want 2 added and 1 duplicate", plan)
+	}
+	if st.NeedsOnboarding() || st.Meta[onboardingMetaKey] != OnboardingDone {
+		t.Error("onboarding not recorded as done")
+	}
+	if st.Credentials == nil || st.Credentials.GitLabToken != "glpat-x" {
+		t.Errorf("credentials = %+v", st.Credentials)
+	}
+	gl := st.Providers["gitlab"]
+	if gl.Default.BaseURL != "https://gitlab.example.com" || gl.Default.Owner != "platform" {
+		t.Errorf("provider defaults = %+v", gl.Default)
+	}
+	if len(st.RepositoriesCache) != 2 {
+		t.Fatalf("cache = %+v", st.RepositoriesCache)
+	}
+	deploy := st.RepositoriesCache[1]
+	if deploy.Owner != "platform/infra" || deploy.Repository != "deploy" || deploy.Analyzer != DefaultImportAnalyzer ||
+		deploy.BaseURL != "https://gitlab.example.com" || len(deploy.Packages) != 2 {
+		t.Errorf("nested group repository = %+v", deploy)
+	}
+	if len(st.TrackedPackages) != 2 || st.TrackedPackages[0] != "requests" {
+		t.Errorf("tracked packages = %v", st.TrackedPackages)
+	}
+}
+
+func TestSkipOnboarding(t *testing.T) {
+	st := NewDefaultGUIState()
+	st.Meta = nil
+	st.SkipOnboarding()
+	if st.NeedsOnboarding() {
+		t.Error("dismissed onboarding offered again")
+	}
+}
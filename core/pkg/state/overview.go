@@ -0,0 +1,114 @@
+package state
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// DefaultOverviewTop is the number of most divergent packages an Overview
+// lists when not told otherwise
+const DefaultOverviewTop = 5
+
+// Overview holds the aggregate figures of the GUI's Overview view, computed
+// from the current report and the archived snapshot history
+type Overview struct {
+	Repositories int // Repositories in the report
+	Failed       int // Repositories that failed analysis
+	Packages     int // Tracked packages
+	Drifting     int // Tracked packages locked at more than one version
+
+	// Divergent lists the packages locked at the most distinct versions,
+	// most first (ties by name), leaving out packages without drift
+	Divergent []DivergentPackage
+
+	// Largest is the repository furthest behind the newest version of a
+	// package; its Behind is 0 when nothing drifts
+	Largest LargestDrift
+
+	// History has a point per archived snapshot, oldest first
+	History []OverviewPoint
+}
+
+// DivergentPackage is a package locked at several versions
+type DivergentPackage struct {
+	Package  string
+	Versions int    // Distinct versions locked
+	Min, Max string // Lowest and highest version
+}
+
+// LargestDrift is the repository lagging the most versions behind the
+// newest version of a package found in the report
+type LargestDrift struct {
+	Repository string // Report.RepoLabel
+	Package    string
+	Version    string // Version the repository locks
+	Latest     string // Newest version found
+	Behind     int    // Distinct newer versions found (see VersionMatrix.Behind)
+}
+
+// OverviewPoint summarizes one archived snapshot
+type OverviewPoint struct {
+	Time         time.Time
+	Repositories int
+	Failed       int
+	Drifting     int
+}
+
+// NewOverview aggregates rpt (nil for no report yet) and history (oldest
+// first, see report.LoadSnapshotHistory), listing the top most divergent
+// packages (DefaultOverviewTop when top <= 0)
+func NewOverview(rpt *report.Report, history []*report.Snapshot, top int) Overview {
+	if top <= 0 {
+		top = DefaultOverviewTop
+	}
+	var o Overview
+	for _, snap := range history {
+		r := snap.Report()
+		m := r.Matrix()
+		point := OverviewPoint{Time: snap.GeneratedAt, Repositories: len(r.Repositories), Failed: len(snap.Failed)}
+		for _, pkg := range r.Packages {
+			if m.HasDrift(pkg) {
+				point.Drifting++
+			}
+		}
+		o.History = append(o.History, point)
+	}
+	if rpt == nil {
+		return o
+	}
+
+	o.Repositories, o.Packages = len(rpt.Repositories), len(rpt.Packages)
+	for i := range rpt.Repositories {
+		if rpt.Repositories[i].Error != nil {
+			o.Failed++
+		}
+	}
+	m := rpt.Matrix()
+	for _, pkg := range rpt.Packages {
+		if !m.HasDrift(pkg) {
+			continue
+		}
+		o.Drifting++
+		pv := m.PackageVersions(pkg)
+		o.Divergent = append(o.Divergent, DivergentPackage{Package: pkg, Versions: len(pv.Sorted), Min: pv.Min, Max: pv.Max})
+		for i := range rpt.Repositories {
+			if rpt.Repositories[i].Error != nil {
+				continue
+			}
+			version := m.Version(i, pkg)
+			if behind := m.Behind(pkg, version); behind > o.Largest.Behind {
+				o.Largest = LargestDrift{Repository: m.Label(i), Package: pkg, Version: version, Latest: pv.Max, Behind: behind}
+			}
+		}
+	}
+	slices.SortStableFunc(o.Divergent, func(a, b DivergentPackage) int {
+		return cmp.Or(b.Versions-a.Versions, cmp.Compare(a.Package, b.Package))
+	})
+	if len(o.Divergent) > top {
+		o.Divergent = o.Divergent[:top]
+	}
+	return o
+}
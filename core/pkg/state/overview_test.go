@@ -0,0 +1,55 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestNewOverview(t *testing.T) {
+	repo := func(name string, deps map[string]string) report.RepositoryReport {
+		return report.RepositoryReport{Provider: "github", Owner: "org", Repository: name, Ref: "main", Analyzer: "poetry", Dependencies: deps}
+	}
+	rpt := &report.Report{
+		Packages: []string{"django", "requests", "urllib3"},
+		Repositories: []report.RepositoryReport{
+			repo("api", map[string]string{"django": "5.0.1", "requests": "2.31.0", "urllib3": "2.0.0"}),
+			repo("web", map[string]string{"django": "4.2.0", "requests": "2.31.0", "urllib3": "1.26.0"}),
+			repo("legacy", map[string]string{"django": "3.2.0", "requests": "2.31.0"}),
+			repo("broken", nil),
+		},
+	}
+	rpt.Repositories[3].Error = report.NewErrorDetail(errors.New("boom"))
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	history := []*report.Snapshot{
+		{GeneratedAt: day, Repositories: map[string]report.SnapshotEntry{
+			"github:org/api@main": {Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2.0"}},
+			"github:org/web@main": {Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2.0"}},
+		}},
+		{GeneratedAt: day.AddDate(0, 0, 1), Repositories: map[string]report.SnapshotEntry{
+			"github:org/api@main": {Analyzer: "poetry", Dependencies: map[string]string{"django": "5.0.1"}},
+			"github:org/web@main": {Analyzer: "poetry", Dependencies: map[string]string{"django": "4.2.0"}},
+		}, Failed: map[string]*report.ErrorDetail{"github:org/broken@main": report.NewErrorDetail(errors.New("boom"))}},
+	}
+
+	o := NewOverview(rpt, history, 1)
+	if o.Repositories != 4 || o.Failed != 1 || o.Packages != 3 || o.Drifting != 2 {
+		t.Errorf("counts = %d repositories, %d failed, %d packages, %d drifting; want 4, 1, 3, 2", o.Repositories, o.Failed, o.Packages, o.Drifting)
+	}
+	if len(o.Divergent) != 1 || o.Divergent[0] != (DivergentPackage{Package: "django", Versions: 3, Min: "3.2.0", Max: "5.0.1"}) {
+		t.Errorf("Divergent = %+v, want django with 3 versions", o.Divergent)
+	}
+	if want := (LargestDrift{Repository: "org/legacy", Package: "django", Version: "3.2.0", Latest: "5.0.1", Behind: 2}); o.Largest != want {
+		t.Errorf("Largest = %+v, want %+v", o.Largest, want)
+	}
+	if len(o.History) != 2 || o.History[0].Drifting != 0 || o.History[1] != (OverviewPoint{Time: day.AddDate(0, 0, 1), Repositories: 3, Failed: 1, Drifting: 1}) {
+		t.Errorf("History = %+v", o.History)
+	}
+
+	if empty := NewOverview(nil, nil, 0); empty.Repositories != 0 || empty.History != nil {
+		t.Errorf("NewOverview(nil) = %+v, want zero figures", empty)
+	}
+}
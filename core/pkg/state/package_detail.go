@@ -0,0 +1,96 @@
+package state
+
+import (
+	"slices"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
+)
+
+// PackageDetail is the GUI's package detail page: every version of a tracked
+// package in use and the repositories locking each, from the package's
+// report.PackageVersions
+type PackageDetail struct {
+	Package string
+	Latest  string // Newest version found (PackageVersions.Max)
+
+	// Versions lists the versions in use, newest first
+	Versions []PackageVersionUse
+
+	// Missing lists the repositories not locking the package and Failed
+	// those whose analysis failed (Report.RepoLabel)
+	Missing []string
+	Failed  []string
+}
+
+// PackageVersionUse is one version of a package and the repositories locking
+// it, in report order
+type PackageVersionUse struct {
+	Version string
+	// Behind is the number of newer versions found (see
+	// VersionMatrix.Behind); -1 for versions left out of drift, e.g. only
+	// locked by suppressed repositories
+	Behind int
+	Users  []PackageUser
+}
+
+// PackageUser is a repository locking a package
+type PackageUser struct {
+	Repository string // Report.RepoLabel
+	Key        string // RepositoryReport.Key
+	// Relation is report.RelationDirect or RelationTransitive, from the
+	// repository's dependency graph; empty without one
+	Relation   string
+	Dev        bool   // Locked only as a development dependency
+	Suppressed bool   // Matched by an ignore rule
+	Registry   string // Package index it was resolved from, when recorded
+}
+
+// NewPackageDetail collects the detail page of pkg from rpt
+func NewPackageDetail(rpt *report.Report, pkg string) PackageDetail {
+	m := rpt.Matrix()
+	pv := m.PackageVersions(pkg)
+	d := PackageDetail{Package: pkg, Latest: pv.Max}
+
+	relations := make(map[string]string)
+	if usages, err := rpt.FindUsages(pkg, ""); err == nil {
+		for _, u := range usages {
+			if u.Relation != report.RelationUnknown {
+				relations[u.Repository] = u.Relation
+			}
+		}
+	}
+
+	byVersion := make(map[string]int)
+	for i := range rpt.Repositories {
+		rr := &rpt.Repositories[i]
+		version := m.Version(i, pkg)
+		switch {
+		case rr.Error != nil:
+			d.Failed = append(d.Failed, m.Label(i))
+			continue
+		case version == "":
+			d.Missing = append(d.Missing, m.Label(i))
+			continue
+		}
+		pos, ok := byVersion[version]
+		if !ok {
+			pos = len(d.Versions)
+			byVersion[version] = pos
+			d.Versions = append(d.Versions, PackageVersionUse{Version: version, Behind: m.Behind(pkg, version)})
+		}
+		d.Versions[pos].Users = append(d.Versions[pos].Users, PackageUser{
+			Repository: m.Label(i),
+			Key:        rr.Key(),
+			Relation:   relations[rr.Key()],
+			Dev:        rr.DevOnly[pkg],
+			Suppressed: rr.Suppressed(pkg),
+			Registry:   rr.Registries[pkg],
+		})
+	}
+	eco := m.Ecosystem(pkg)
+	slices.SortStableFunc(d.Versions, func(a, b PackageVersionUse) int {
+		return versioning.Compare(eco, b.Version, a.Version)
+	})
+	return d
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+func TestNewPackageDetail(t *testing.T) {
+	repo := func(name, version string) report.RepositoryReport {
+		return report.RepositoryReport{Provider: "github", Owner: "org", Repository: name, Ref: "main", Analyzer: "poetry",
+			Dependencies: map[string]string{"requests": version}}
+	}
+	rpt := &report.Report{Packages: []string{"requests"}, Repositories: []report.RepositoryReport{
+		repo("api", "2.25.0"),
+		repo("web", "2.31.0"),
+		repo("cli", "2.25.0"),
+		repo("docs", ""),
+		repo("broken", ""),
+	}}
+	rpt.Repositories[0].Graph = &dependencies.Graph{Nodes: []dependencies.GraphNode{
+		{Name: "flask", Version: "3.0.0", Direct: true},
+		{Name: "requests", Version: "2.25.0"},
+	}}
+	rpt.Repositories[1].Graph = &dependencies.Graph{Nodes: []dependencies.GraphNode{{Name: "requests", Version: "2.31.0", Direct: true}}}
+	rpt.Repositories[2].DevOnly = map[string]bool{"requests": true}
+	rpt.Repositories[2].Registries = map[string]string{"requests": "https://pypi.example.com/simple"}
+	delete(rpt.Repositories[3].Dependencies, "requests")
+	rpt.Repositories[4].Error = report.NewErrorDetail(errors.New("boom"))
+
+	d := NewPackageDetail(rpt, "requests")
+	if d.Latest != "2.31.0" || len(d.Versions) != 2 {
+		t.Fatalf("detail = %+v, want 2 versions up to 2.31.0", d)
+	}
+	newest, old := d.Versions[0], d.Versions[1]
+	if newest.Version != "2.31.0" || newest.Behind != 0 || len(newest.Users) != 1 || newest.Users[0].Relation != report.RelationDirect {
+		t.Errorf("newest = %+v, want org/web locking 2.31.0 directly", newest)
+	}
+	if old.Version != "2.25.0" || old.Behind != 1 || len(old.Users) != 2 {
+		t.Fatalf("oldest = %+v, want org/api and org/cli one version behind", old)
+	}
+	want := PackageUser{Repository: "org/cli", Key: "github:org/cli@main", Dev: true, Registry: "https://pypi.example.com/simple"}
+	if old.Users[0].Relation != report.RelationTransitive || old.Users[1] != want {
+		t.Errorf("users = %+v, want org/api transitive and %+v", old.Users, want)
+	}
+	if !slices.Equal(d.Missing, []string{"org/docs"}) || !slices.Equal(d.Failed, []string{"org/broken"}) {
+		t.Errorf("missing %v, failed %v", d.Missing, d.Failed)
+	}
+}
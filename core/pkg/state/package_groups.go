@@ -0,0 +1,42 @@
+package state
+
+import "sort"
+
+// PackageGroupNames returns the defined package group names, sorted.
+func (s *GUIState) PackageGroupNames() []string {
+	names := make([]string, 0, len(s.PackageGroups))
+	for name := range s.PackageGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetPackageGroup creates or replaces a named package group.
+func (s *GUIState) SetPackageGroup(name string, packages []string) {
+	if s.PackageGroups == nil {
+		s.PackageGroups = map[string][]string{}
+	}
+	s.PackageGroups[name] = append([]string{}, packages...)
+}
+
+// DeletePackageGroup removes a package group, clearing it as the active
+// preset if selected.
+func (s *GUIState) DeletePackageGroup(name string) {
+	delete(s.PackageGroups, name)
+	if s.GUI.ActivePackageGroup == name {
+		s.GUI.ActivePackageGroup = ""
+	}
+}
+
+// VisiblePackages returns the packages the dependencies table should show:
+// the active package group if one is selected and still exists, otherwise
+// TrackedPackages. An empty result means "all packages in the report".
+func (s *GUIState) VisiblePackages() []string {
+	if name := s.GUI.ActivePackageGroup; name != "" {
+		if pkgs, ok := s.PackageGroups[name]; ok {
+			return pkgs
+		}
+	}
+	return s.TrackedPackages
+}
@@ -0,0 +1,67 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVisiblePackages(t *testing.T) {
+	st := NewDefaultGUIState()
+	st.TrackedPackages = []string{"requests"}
+	st.SetPackageGroup("web", []string{"django", "flask"})
+	st.SetPackageGroup("crypto", []string{"cryptography"})
+
+	if fmt.Sprint(st.PackageGroupNames()) != "[crypto web]" {
+		t.Errorf("unexpected group names %v", st.PackageGroupNames())
+	}
+	if fmt.Sprint(st.VisiblePackages()) != "[requests]" {
+		t.Errorf("expected tracked packages without active group, got %v", st.VisiblePackages())
+	}
+
+	st.GUI.ActivePackageGroup = "web"
+	if fmt.Sprint(st.VisiblePackages()) != "[django flask]" {
+		t.Errorf("expected active group packages, got %v", st.VisiblePackages())
+	}
+
+	st.DeletePackageGroup("web")
+	if st.GUI.ActivePackageGroup != "" {
+		t.Error("deleting the active group should clear the selection")
+	}
+	if fmt.Sprint(st.VisiblePackages()) != "[requests]" {
+		t.Errorf("expected fallback to tracked packages, got %v", st.VisiblePackages())
+	}
+}
+
+func TestMergeCLIConfigPackageGroups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "repos.yaml")
+	content := `
+packageGroups:
+  web: ["django"]
+  crypto: ["cryptography"]
+providers:
+  github:
+    default:
+      analyzer: poetry
+    repositories:
+      - owner: o
+        repository: r
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	st := NewDefaultGUIState()
+	st.SetPackageGroup("web", []string{"flask"})
+	if err := st.MergeCLIConfig(path); err != nil {
+		t.Fatalf("MergeCLIConfig: %v", err)
+	}
+	if fmt.Sprint(st.PackageGroups["web"]) != "[flask]" {
+		t.Errorf("existing group should be kept, got %v", st.PackageGroups["web"])
+	}
+	if fmt.Sprint(st.PackageGroups["crypto"]) != "[cryptography]" {
+		t.Errorf("new group should be imported, got %v", st.PackageGroups["crypto"])
+	}
+}
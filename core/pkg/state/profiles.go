@@ -0,0 +1,177 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/report"
+)
+
+// DefaultProfile is the profile stored at DefaultGUIStatePath
+const DefaultProfile = "default"
+
+// profileNamePattern restricts profile names to safe file names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ValidateProfileName rejects names that are empty, too long or not usable
+// as a file name (letters, digits, ".", "_" and "-", starting alphanumeric).
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("state: invalid profile name %q (use letters, digits, '.', '_' or '-')", name)
+	}
+	return nil
+}
+
+// ProfileStatePath returns the state file of a profile. The default profile
+// keeps using DefaultGUIStatePath; others live in a profiles directory next
+// to it.
+func ProfileStatePath(name string) (string, error) {
+	if name == "" || name == DefaultProfile {
+		return DefaultGUIStatePath(), nil
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(profilesDir(), name+".yaml"), nil
+}
+
+func profilesDir() string {
+	return filepath.Join(filepath.Dir(DefaultGUIStatePath()), "profiles")
+}
+
+// ListProfiles returns DefaultProfile followed by the other saved profiles,
+// sorted by name.
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(profilesDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("state: list profiles: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".yaml")
+		if !ok || e.IsDir() || name == DefaultProfile || ValidateProfileName(name) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// LoadProfile loads a profile's state (defaults when it was never saved).
+// The returned state's Profile is always name.
+func LoadProfile(name string) (*GUIState, error) {
+	path, err := ProfileStatePath(name)
+	if err != nil {
+		return nil, err
+	}
+	st, err := LoadGUIState(path)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	st.Profile = name
+	return st, nil
+}
+
+// SaveProfile writes st to the state file of st.Profile.
+func SaveProfile(st *GUIState) error {
+	if st == nil {
+		return errors.New("state: nil GUIState")
+	}
+	path, err := ProfileStatePath(st.Profile)
+	if err != nil {
+		return err
+	}
+	return SaveGUIState(st, path)
+}
+
+// SaveProfileIfUnmodified writes st to the state file of st.Profile unless
+// another process wrote it since st was loaded (see SaveGUIStateIfUnmodified).
+func SaveProfileIfUnmodified(st *GUIState) error {
+	if st == nil {
+		return errors.New("state: nil GUIState")
+	}
+	path, err := ProfileStatePath(st.Profile)
+	if err != nil {
+		return err
+	}
+	return SaveGUIStateIfUnmodified(st, path)
+}
+
+// ProfileSavedAt returns the savedAt stamp of a profile's state file (zero
+// when it was never saved).
+func ProfileSavedAt(name string) (time.Time, error) {
+	path, err := ProfileStatePath(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ReadSavedAt(path)
+}
+
+// CreateProfile saves a new, empty profile. Display preferences (window,
+// theme, logging, concurrency, tray) are copied from from when set so the
+// application looks the same after switching; repositories, packages and
+// credentials start empty.
+func CreateProfile(name string, from *GUIState) (*GUIState, error) {
+	if name == DefaultProfile {
+		return nil, fmt.Errorf("state: profile %q already exists", name)
+	}
+	path, err := ProfileStatePath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("state: profile %q already exists", name)
+	}
+	st := NewDefaultGUIState()
+	st.Profile = name
+	if from != nil {
+		st.GUI.LastWindow = from.GUI.LastWindow
+		st.GUI.Theme = from.GUI.Theme
+		st.GUI.Logging = from.GUI.Logging
+		st.GUI.Concurrency = from.GUI.Concurrency
+		st.GUI.Tray = from.GUI.Tray
+	}
+	if err := SaveProfile(st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// DeleteProfile removes a saved profile. The default profile cannot be
+// deleted; deleting a profile that was never saved is not an error.
+func DeleteProfile(name string) error {
+	if name == "" || name == DefaultProfile {
+		return errors.New("state: the default profile cannot be deleted")
+	}
+	path, err := ProfileStatePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("state: delete profile: %w", err)
+	}
+	if err := os.RemoveAll(report.HistoryDir(path)); err != nil {
+		return fmt.Errorf("state: delete profile history: %w", err)
+	}
+	return nil
+}
+
+// ProfileHistoryDir returns the directory archiving the report snapshots of
+// a profile, beside its state file (see report.ArchiveSnapshot).
+func ProfileHistoryDir(name string) (string, error) {
+	path, err := ProfileStatePath(name)
+	if err != nil {
+		return "", err
+	}
+	return report.HistoryDir(path), nil
+}
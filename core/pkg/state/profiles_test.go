@@ -0,0 +1,105 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useTempConfigDir points the user config directory at a temporary directory
+func useTempConfigDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+}
+
+func TestProfileStatePath(t *testing.T) {
+	useTempConfigDir(t)
+	if p, err := ProfileStatePath(DefaultProfile); err != nil || p != DefaultGUIStatePath() {
+		t.Errorf("default profile path = %q, %v", p, err)
+	}
+	p, err := ProfileStatePath("work")
+	if err != nil || filepath.Base(p) != "work.yaml" || filepath.Base(filepath.Dir(p)) != "profiles" {
+		t.Errorf("work profile path = %q, %v", p, err)
+	}
+	for _, bad := range []string{"../evil", "a/b", ".hidden", "with space"} {
+		if _, err := ProfileStatePath(bad); err == nil {
+			t.Errorf("expected error for profile name %q", bad)
+		}
+	}
+}
+
+func TestProfileLifecycle(t *testing.T) {
+	useTempConfigDir(t)
+
+	base := NewDefaultGUIState()
+	base.GUI.Theme = ThemeCfg{Variant: ThemeDark}
+	base.TrackedPackages = []string{"requests"}
+
+	work, err := CreateProfile("work", base)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if work.Profile != "work" || work.GUI.Theme.Variant != ThemeDark || len(work.TrackedPackages) != 0 {
+		t.Errorf("new profile should copy display preferences only: %+v", work)
+	}
+	if _, err := CreateProfile("work", base); err == nil {
+		t.Error("creating an existing profile should fail")
+	}
+	if _, err := CreateProfile(DefaultProfile, base); err == nil {
+		t.Error("creating the default profile should fail")
+	}
+	if _, err := CreateProfile("oss", nil); err != nil {
+		t.Fatalf("create oss: %v", err)
+	}
+
+	work.TrackedPackages = []string{"django"}
+	if err := SaveProfile(work); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := LoadProfile("work")
+	if err != nil || fmt.Sprint(loaded.TrackedPackages) != "[django]" || loaded.Profile != "work" {
+		t.Fatalf("load = %+v, %v", loaded, err)
+	}
+
+	names, err := ListProfiles()
+	if err != nil || fmt.Sprint(names) != "[default oss work]" {
+		t.Errorf("ListProfiles = %v, %v", names, err)
+	}
+
+	if err := DeleteProfile(DefaultProfile); err == nil {
+		t.Error("deleting the default profile should fail")
+	}
+	if err := DeleteProfile("work"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if names, _ := ListProfiles(); fmt.Sprint(names) != "[default oss]" {
+		t.Errorf("after delete, profiles = %v", names)
+	}
+	if err := DeleteProfile("work"); err != nil {
+		t.Errorf("deleting a missing profile should succeed, got %v", err)
+	}
+}
+
+func TestProfileHistoryDir(t *testing.T) {
+	useTempConfigDir(t)
+	dir, err := ProfileHistoryDir("work")
+	if err != nil || filepath.Base(dir) != "work.history" {
+		t.Fatalf("ProfileHistoryDir = %q, %v", dir, err)
+	}
+	if err := SaveProfile(&GUIState{Profile: "work"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("history should be deleted with the profile, stat error = %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrRemoteStateNotFound is returned by RemoteGUIStateStore.Fetch when no
+// state has been stored under the key yet.
+var ErrRemoteStateNotFound = errors.New("state: remote state not found")
+
+// ErrStateConflict is returned when a conditional write finds the remote
+// state changed since the version the caller last saw.
+var ErrStateConflict = errors.New("state: remote state changed since last sync")
+
+// RemoteGUIStateStore is a GUIStateStore shared between machines. Versions are
+// opaque strings (HTTP ETags) used for optimistic concurrency.
+type RemoteGUIStateStore interface {
+	GUIStateStore
+	// Fetch returns the state stored under key and its version, or
+	// ErrRemoteStateNotFound.
+	Fetch(ctx context.Context, key string) (*GUIState, string, error)
+	// Put stores st under key if the remote still holds version (empty means
+	// the key must not exist yet) and returns the new version. A mismatch is
+	// ErrStateConflict.
+	Put(ctx context.Context, key string, st *GUIState, version string) (string, error)
+}
+
+// remoteStore implements RemoteGUIStateStore over HTTP GET/PUT with
+// If-Match / If-None-Match preconditions. Backends differ only in how
+// object URLs are built and requests are authenticated.
+type remoteStore struct {
+	client    *http.Client
+	objectURL func(key string) (string, error)
+	sign      func(req *http.Request, payload []byte)
+
+	// versions remembers the last version seen per key so Load followed by
+	// Save performs a conditional write
+	mu       sync.Mutex
+	versions map[string]string
+}
+
+// Load implements GUIStateStore.Load, fetching the state stored under path.
+func (s *remoteStore) Load(path string) (*GUIState, error) {
+	st, _, err := s.Fetch(context.Background(), path)
+	return st, err
+}
+
+// Save implements GUIStateStore.Save. The write fails with ErrStateConflict
+// if the remote changed since the last Load or Save of path.
+func (s *remoteStore) Save(st *GUIState, path string) error {
+	s.mu.Lock()
+	version := s.versions[path]
+	s.mu.Unlock()
+	_, err := s.Put(context.Background(), path, st, version)
+	return err
+}
+
+// Fetch implements RemoteGUIStateStore.Fetch.
+func (s *remoteStore) Fetch(ctx context.Context, key string) (*GUIState, string, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, "", ErrRemoteStateNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("state: remote fetch failed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteStateSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("state: remote read failed: %w", err)
+	}
+	if len(data) > maxRemoteStateSize {
+		return nil, "", fmt.Errorf("state: remote state exceeds %d bytes", maxRemoteStateSize)
+	}
+	st := NewDefaultGUIState()
+	if err := yaml.Unmarshal(data, st); err != nil {
+		return nil, "", fmt.Errorf("state: remote unmarshal failed: %w", err)
+	}
+	normalizeGUIState(st)
+	version := resp.Header.Get("ETag")
+	s.remember(key, version)
+	return st, version, nil
+}
+
+// Put implements RemoteGUIStateStore.Put.
+func (s *remoteStore) Put(ctx context.Context, key string, st *GUIState, version string) (string, error) {
+	if st == nil {
+		return "", errors.New("state: nil GUIState")
+	}
+	st.SavedAt = time.Now().UTC()
+	payload, err := yaml.Marshal(st)
+	if err != nil {
+		return "", fmt.Errorf("state: marshal failed: %w", err)
+	}
+	header := http.Header{"Content-Type": {"application/yaml"}}
+	if version == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", version)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, payload, header)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict:
+		return "", ErrStateConflict
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return "", fmt.Errorf("state: remote save failed: %s", resp.Status)
+	}
+	newVersion := resp.Header.Get("ETag")
+	s.remember(key, newVersion)
+	return newVersion, nil
+}
+
+// maxRemoteStateSize bounds the size of a fetched state document
+const maxRemoteStateSize = 16 << 20
+
+func (s *remoteStore) remember(key, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.versions == nil {
+		s.versions = make(map[string]string)
+	}
+	s.versions[key] = version
+}
+
+func (s *remoteStore) do(ctx context.Context, method, key string, payload []byte, header http.Header) (*http.Response, error) {
+	target, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("state: remote request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	s.sign(req, payload)
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("state: remote %s failed: %w", strings.ToLower(method), err)
+	}
+	return resp, nil
+}
+
+// HTTPStoreConfig configures NewHTTPGUIStateStore.
+type HTTPStoreConfig struct {
+	BaseURL string       // Keys are appended as path segments
+	Token   string       // Sent as "Authorization: Bearer <token>" when set
+	Client  *http.Client // Defaults to http.DefaultClient
+}
+
+// NewHTTPGUIStateStore returns a store that GETs and PUTs YAML documents at
+// BaseURL/<key>. The server must return ETags and honor If-Match and
+// If-None-Match on PUT (answering 412 on mismatch) for conflict detection.
+func NewHTTPGUIStateStore(cfg HTTPStoreConfig) (RemoteGUIStateStore, error) {
+	base, err := url.Parse(strings.TrimSpace(cfg.BaseURL))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("state: invalid HTTP store URL %q", cfg.BaseURL)
+	}
+	return &remoteStore{
+		client:    cfg.Client,
+		objectURL: func(key string) (string, error) { return joinObjectURL(base, "", key) },
+		sign: func(req *http.Request, _ []byte) {
+			if cfg.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+cfg.Token)
+			}
+		},
+	}, nil
+}
+
+// S3StoreConfig configures NewS3GUIStateStore for AWS S3 or any
+// S3-compatible service (MinIO, Ceph, R2) addressed path-style.
+type S3StoreConfig struct {
+	Endpoint        string // e.g. "https://s3.us-east-1.amazonaws.com" or a MinIO URL
+	Region          string // Signing region (default "us-east-1")
+	Bucket          string
+	Prefix          string // Optional key prefix, e.g. "devdashboard/"
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string       // Optional, for temporary credentials
+	Client          *http.Client // Defaults to http.DefaultClient
+}
+
+// NewS3GUIStateStore returns a store keeping state documents as objects in
+// an S3 bucket, authenticated with AWS Signature Version 4. Conditional
+// writes rely on the service supporting If-Match / If-None-Match on PUT.
+func NewS3GUIStateStore(cfg S3StoreConfig) (RemoteGUIStateStore, error) {
+	endpoint, err := url.Parse(strings.TrimSpace(cfg.Endpoint))
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("state: invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, errors.New("state: S3 store requires a bucket")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("state: S3 store requires access key credentials")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &remoteStore{
+		client: cfg.Client,
+		objectURL: func(key string) (string, error) {
+			return joinObjectURL(endpoint, cfg.Bucket, cfg.Prefix+key)
+		},
+		sign: func(req *http.Request, payload []byte) {
+			signS3Request(req, payload, cfg, time.Now().UTC())
+		},
+	}, nil
+}
+
+// joinObjectURL appends bucket (if any) and key to base, escaping each path
+// segment. Keys must be non-empty and free of "." / ".." segments.
+func joinObjectURL(base *url.URL, bucket, key string) (string, error) {
+	key = strings.Trim(key, "/")
+	if key == "" {
+		return "", errors.New("state: remote key is empty")
+	}
+	segments := strings.Split(key, "/")
+	if bucket != "" {
+		segments = append([]string{bucket}, segments...)
+	}
+	escaped := make([]string, len(segments))
+	for i, seg := range segments {
+		if seg == "." || seg == ".." || seg == "" {
+			return "", fmt.Errorf("state: invalid remote key %q", key)
+		}
+		escaped[i] = url.PathEscape(seg)
+	}
+	u := *base
+	u.RawPath = ""
+	u.Path = strings.TrimSuffix(base.Path, "/") + "/" + strings.Join(segments, "/")
+	u.RawPath = strings.TrimSuffix(base.EscapedPath(), "/") + "/" + strings.Join(escaped, "/")
+	return u.String(), nil
+}
+
+// signS3Request adds AWS Signature Version 4 headers for the S3 service.
+// Only host and x-amz-* headers are signed so precondition headers may be
+// set independently.
+func signS3Request(req *http.Request, payload []byte, cfg S3StoreConfig, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{"host": req.URL.Host, "x-amz-content-sha256": payloadHash, "x-amz-date": amzDate}
+	if cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cfg.SessionToken)
+		signed = append(signed, "x-amz-security-token")
+		values["x-amz-security-token"] = cfg.SessionToken
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(values[name]) + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
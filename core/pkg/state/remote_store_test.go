@@ -0,0 +1,173 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeObjectServer is an in-memory object store honoring If-Match and
+// If-None-Match on PUT, as the HTTP and S3 backends expect.
+type fakeObjectServer struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	etags   map[string]string
+	puts    int
+	auth    []string
+}
+
+func newFakeObjectServer(t *testing.T) (*fakeObjectServer, *httptest.Server) {
+	f := &fakeObjectServer{objects: map[string][]byte{}, etags: map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeObjectServer) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	path := r.URL.EscapedPath()
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := f.objects[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", f.etags[path])
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		current, exists := f.etags[path]
+		if (r.Header.Get("If-None-Match") == "*" && exists) ||
+			(r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != current) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.puts++
+		f.objects[path] = data
+		f.etags[path] = fmt.Sprintf(`"v%d"`, f.puts)
+		w.Header().Set("ETag", f.etags[path])
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestRemoteStores(t *testing.T) {
+	fake, srv := newFakeObjectServer(t)
+	httpStore, err := NewHTTPGUIStateStore(HTTPStoreConfig{BaseURL: srv.URL + "/state/", Token: "secret"})
+	if err != nil {
+		t.Fatalf("http store: %v", err)
+	}
+	s3Store, err := NewS3GUIStateStore(S3StoreConfig{Endpoint: srv.URL, Bucket: "team", Prefix: "dd/", AccessKeyID: "AKID", SecretAccessKey: "shh"})
+	if err != nil {
+		t.Fatalf("s3 store: %v", err)
+	}
+
+	for name, store := range map[string]RemoteGUIStateStore{"http": httpStore, "s3": s3Store} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if _, _, err := store.Fetch(ctx, "shared.yaml"); !errors.Is(err, ErrRemoteStateNotFound) {
+				t.Fatalf("expected not found, got %v", err)
+			}
+
+			st := NewDefaultGUIState()
+			st.TrackedPackages = []string{"requests"}
+			v1, err := store.Put(ctx, "shared.yaml", st, "")
+			if err != nil || v1 == "" {
+				t.Fatalf("create: version %q, err %v", v1, err)
+			}
+			if _, err := store.Put(ctx, "shared.yaml", st, ""); !errors.Is(err, ErrStateConflict) {
+				t.Errorf("create over existing should conflict, got %v", err)
+			}
+
+			got, version, err := store.Fetch(ctx, "shared.yaml")
+			if err != nil || version != v1 || fmt.Sprint(got.TrackedPackages) != "[requests]" {
+				t.Fatalf("fetch = %v, %q, %v", got.TrackedPackages, version, err)
+			}
+
+			if _, err := store.Put(ctx, "shared.yaml", st, v1); err != nil {
+				t.Fatalf("conditional update: %v", err)
+			}
+			if _, err := store.Put(ctx, "shared.yaml", st, v1); !errors.Is(err, ErrStateConflict) {
+				t.Errorf("stale update should conflict, got %v", err)
+			}
+
+			// Load/Save track versions per key
+			loaded, err := store.Load("shared.yaml")
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if err := store.Save(loaded, "shared.yaml"); err != nil {
+				t.Errorf("save after load: %v", err)
+			}
+			if _, _, err := store.Fetch(ctx, "../escape"); err == nil {
+				t.Error("expected error for key with .. segment")
+			}
+		})
+	}
+
+	if _, ok := fake.objects["/state/shared.yaml"]; !ok {
+		t.Errorf("http object not stored at base URL path: %v", fake.objects)
+	}
+	if _, ok := fake.objects["/team/dd/shared.yaml"]; !ok {
+		t.Errorf("s3 object not stored path-style under the bucket: %v", fake.objects)
+	}
+	var bearer, sigv4 bool
+	for _, a := range fake.auth {
+		bearer = bearer || a == "Bearer secret"
+		sigv4 = sigv4 || strings.HasPrefix(a, "AWS4-HMAC-SHA256 Credential=AKID/")
+	}
+	if !bearer || !sigv4 {
+		t.Errorf("expected bearer and SigV4 authorization headers, got %v", fake.auth)
+	}
+}
+
+func TestRemoteStoreConfigValidation(t *testing.T) {
+	if _, err := NewHTTPGUIStateStore(HTTPStoreConfig{BaseURL: "ftp://example.com"}); err == nil {
+		t.Error("expected error for non-HTTP URL")
+	}
+	if _, err := NewS3GUIStateStore(S3StoreConfig{Endpoint: "https://s3.example.com", AccessKeyID: "a", SecretAccessKey: "b"}); err == nil {
+		t.Error("expected error for missing bucket")
+	}
+	if _, err := NewS3GUIStateStore(S3StoreConfig{Endpoint: "https://s3.example.com", Bucket: "b"}); err == nil {
+		t.Error("expected error for missing credentials")
+	}
+}
+
+func TestSignS3Request(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPut, "https://s3.example.com/bucket/key.yaml", nil)
+	cfg := S3StoreConfig{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "tok"}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	signS3Request(req, []byte("body"), cfg, now)
+
+	auth := req.Header.Get("Authorization")
+	for _, want := range []string{
+		"Credential=AKID/20250102/eu-west-1/s3/aws4_request",
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token",
+	} {
+		if !strings.Contains(auth, want) {
+			t.Errorf("authorization %q missing %q", auth, want)
+		}
+	}
+	if req.Header.Get("X-Amz-Date") != "20250102T030405Z" || req.Header.Get("X-Amz-Security-Token") != "tok" {
+		t.Errorf("unexpected signing headers: %v", req.Header)
+	}
+
+	// Signing is deterministic and depends on the payload
+	again, _ := http.NewRequest(http.MethodPut, "https://s3.example.com/bucket/key.yaml", nil)
+	signS3Request(again, []byte("body"), cfg, now)
+	other, _ := http.NewRequest(http.MethodPut, "https://s3.example.com/bucket/key.yaml", nil)
+	signS3Request(other, []byte("other"), cfg, now)
+	if again.Header.Get("Authorization") != auth || other.Header.Get("Authorization") == auth {
+		t.Error("signature should be deterministic and payload-dependent")
+	}
+}
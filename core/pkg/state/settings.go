@@ -0,0 +1,91 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+// Settings limits
+const (
+	MaxWorkersLimit       = 256       // Upper bound for Concurrency.MaxWorkers
+	MinAutoRefreshSeconds = 60        // Shortest auto-refresh interval
+	MinRingBufferSize     = 100       // Smallest in-memory log buffer
+	MaxRingBufferSize     = 1_000_000 // Largest in-memory log buffer
+
+	defaultRingBufferSize     = 5000
+	defaultAutoRefreshSeconds = 900 // 15 minutes
+)
+
+// LogLevels are the accepted Logging.Level values, most verbose first
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Settings are the GUI preferences edited in the desktop Settings view: the
+// tunable parts of GUISection that are not window or view state
+type Settings struct {
+	Concurrency ConcurrencyCfg
+	AutoRefresh AutoRefreshCfg
+	Logging     LoggingCfg
+	Timeouts    config.TimeoutsConfig
+}
+
+// DefaultSettings returns the settings of a new state
+func DefaultSettings() Settings {
+	return Settings{
+		Concurrency: ConcurrencyCfg{MaxWorkers: runtime.NumCPU()},
+		AutoRefresh: AutoRefreshCfg{Enabled: false, IntervalSeconds: defaultAutoRefreshSeconds},
+		Logging:     LoggingCfg{RingBufferSize: defaultRingBufferSize, Level: "info"},
+	}
+}
+
+// Settings returns the section's current settings
+func (g GUISection) Settings() Settings {
+	return Settings{
+		Concurrency: g.Concurrency,
+		AutoRefresh: g.AutoRefresh,
+		Logging:     g.Logging,
+		Timeouts:    g.Timeouts,
+	}
+}
+
+// ApplySettings validates s and stores it in the section, leaving the
+// section unchanged when s is invalid
+func (g *GUISection) ApplySettings(s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	s.Logging.Level = strings.ToLower(s.Logging.Level)
+	g.Concurrency = s.Concurrency
+	g.AutoRefresh = s.AutoRefresh
+	g.Logging = s.Logging
+	g.Timeouts = s.Timeouts
+	return nil
+}
+
+// Validate reports every out-of-range setting
+func (s Settings) Validate() error {
+	var errs []error
+	if w := s.Concurrency.MaxWorkers; w < 1 || w > MaxWorkersLimit {
+		errs = append(errs, fmt.Errorf("max workers must be between 1 and %d, got %d", MaxWorkersLimit, w))
+	}
+	if s.AutoRefresh.Enabled && s.AutoRefresh.IntervalSeconds < MinAutoRefreshSeconds {
+		errs = append(errs, fmt.Errorf("auto-refresh interval must be at least %d seconds, got %d", MinAutoRefreshSeconds, s.AutoRefresh.IntervalSeconds))
+	}
+	if n := s.Logging.RingBufferSize; n < MinRingBufferSize || n > MaxRingBufferSize {
+		errs = append(errs, fmt.Errorf("log buffer size must be between %d and %d, got %d", MinRingBufferSize, MaxRingBufferSize, n))
+	}
+	if !slices.Contains(LogLevels, strings.ToLower(s.Logging.Level)) {
+		errs = append(errs, fmt.Errorf("log level must be one of %s, got %q", strings.Join(LogLevels, ", "), s.Logging.Level))
+	}
+	if s.Timeouts.Run < 0 || s.Timeouts.Repository < 0 || s.Timeouts.Request < 0 {
+		errs = append(errs, errors.New("timeouts must not be negative"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("state: invalid settings: %w", errors.Join(errs...))
+	}
+	return nil
+}
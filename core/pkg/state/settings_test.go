@@ -0,0 +1,47 @@
+package state
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplySettings(t *testing.T) {
+	st := NewDefaultGUIState()
+	if got := st.GUI.Settings(); got != DefaultSettings() {
+		t.Fatalf("new state settings = %+v, want the defaults", got)
+	}
+
+	s := DefaultSettings()
+	s.Concurrency.MaxWorkers = 4
+	s.AutoRefresh = AutoRefreshCfg{Enabled: true, IntervalSeconds: 300}
+	s.Logging.Level = "DEBUG"
+	s.Timeouts.Request = 10 * time.Second
+	if err := st.GUI.ApplySettings(s); err != nil {
+		t.Fatalf("ApplySettings: %v", err)
+	}
+	if st.GUI.Concurrency.MaxWorkers != 4 || !st.GUI.AutoRefresh.Enabled || st.GUI.Logging.Level != "debug" || st.GUI.Timeouts.Request != 10*time.Second {
+		t.Errorf("settings not applied: %+v", st.GUI.Settings())
+	}
+
+	bad := s
+	bad.Concurrency.MaxWorkers = 0
+	bad.AutoRefresh.IntervalSeconds = 5
+	bad.Logging.Level = "verbose"
+	err := st.GUI.ApplySettings(bad)
+	if err == nil {
+		t.Fatal("expected invalid settings to be rejected")
+	}
+	for _, want := range []string{"max workers", "auto-refresh interval", "log level"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	if st.GUI.Concurrency.MaxWorkers != 4 {
+		t.Error("invalid settings partially applied")
+	}
+
+	if err := st.GUI.ApplySettings(DefaultSettings()); err != nil || st.GUI.Settings() != DefaultSettings() {
+		t.Errorf("reset to defaults = %+v, %v", st.GUI.Settings(), err)
+	}
+}
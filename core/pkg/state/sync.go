@@ -0,0 +1,262 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Remote sync backends selectable in SyncCfg.Backend
+const (
+	SyncBackendHTTP = "http"
+	SyncBackendS3   = "s3"
+)
+
+// DefaultSyncKey is the remote document key used when SyncCfg.Key is empty
+const DefaultSyncKey = "shared_state.yaml"
+
+// SyncCfg shares the curated repository list, tracked packages and package
+// groups with other machines through a remote store. Credentials are never
+// stored here: the HTTP token resolves like a provider token named "sync"
+// (DEV_DASHBOARD_SYNC_TOKEN or the credential store) and S3 keys come from
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+type SyncCfg struct {
+	Backend string `yaml:"backend,omitempty"` // SyncBackendHTTP or SyncBackendS3; empty disables sync
+	URL     string `yaml:"url,omitempty"`     // HTTP base URL or S3 endpoint
+	Bucket  string `yaml:"bucket,omitempty"`  // S3 only
+	Region  string `yaml:"region,omitempty"`  // S3 only (default us-east-1)
+	Key     string `yaml:"key,omitempty"`     // Remote document key (default DefaultSyncKey)
+
+	// Bookkeeping from the last successful sync, used to tell local edits
+	// from remote ones
+	LastVersion  string    `yaml:"lastVersion,omitempty"`
+	LastHash     string    `yaml:"lastHash,omitempty"`
+	LastSyncedAt time.Time `yaml:"lastSyncedAt,omitempty"`
+}
+
+// Enabled reports whether a sync backend is configured
+func (c SyncCfg) Enabled() bool {
+	return c.Backend != ""
+}
+
+// RemoteKey returns Key or DefaultSyncKey
+func (c SyncCfg) RemoteKey() string {
+	if k := strings.TrimSpace(c.Key); k != "" {
+		return k
+	}
+	return DefaultSyncKey
+}
+
+// NewSyncStore builds the remote store described by s.GUI.Sync, resolving
+// credentials as documented on SyncCfg.
+func (s *GUIState) NewSyncStore(cs CredentialStore) (RemoteGUIStateStore, error) {
+	cfg := s.GUI.Sync
+	switch cfg.Backend {
+	case SyncBackendHTTP:
+		token, err := ResolveProviderToken("sync", s, cs)
+		if err != nil {
+			return nil, err
+		}
+		return NewHTTPGUIStateStore(HTTPStoreConfig{BaseURL: cfg.URL, Token: token})
+	case SyncBackendS3:
+		return NewS3GUIStateStore(S3StoreConfig{
+			Endpoint:        cfg.URL,
+			Region:          cfg.Region,
+			Bucket:          cfg.Bucket,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		})
+	case "":
+		return nil, errors.New("state: sync is not configured")
+	default:
+		return nil, fmt.Errorf("state: unknown sync backend %q (supported: %s, %s)", cfg.Backend, SyncBackendHTTP, SyncBackendS3)
+	}
+}
+
+// SharedCopy returns a new state holding only the sections shared through
+// sync: providers (with every token removed), tracked packages and package
+// groups.
+func (s *GUIState) SharedCopy() *GUIState {
+	cp := NewDefaultGUIState()
+	cp.Providers = make(map[string]ProviderConfigWrapper, len(s.Providers))
+	for name, prov := range s.Providers {
+		repos := make([]config.RepoConfig, len(prov.Repositories))
+		copy(repos, prov.Repositories)
+		for i := range repos {
+			repos[i].Token = ""
+		}
+		prov.Default.Token = ""
+		prov.Repositories = repos
+		cp.Providers[name] = prov
+	}
+	cp.TrackedPackages = append([]string{}, s.TrackedPackages...)
+	cp.PackageGroups = make(map[string][]string, len(s.PackageGroups))
+	for name, pkgs := range s.PackageGroups {
+		cp.PackageGroups[name] = append([]string{}, pkgs...)
+	}
+	cp.GUI = GUISection{}
+	cp.RepositoriesCache = nil
+	return cp
+}
+
+// ApplyShared replaces the shared sections with those of remote. Tokens
+// already configured locally for the same repository are kept.
+func (s *GUIState) ApplyShared(remote *GUIState) {
+	tokens := make(map[string]string)
+	defaultTokens := make(map[string]string)
+	for name, prov := range s.Providers {
+		defaultTokens[name] = prov.Default.Token
+		for _, r := range prov.Repositories {
+			tokens[repoCacheKey(name, r.Owner, r.Repository, r.Ref)] = r.Token
+		}
+	}
+
+	shared := remote.SharedCopy()
+	for name, prov := range shared.Providers {
+		prov.Default.Token = defaultTokens[name]
+		for i := range prov.Repositories {
+			r := &prov.Repositories[i]
+			r.Token = tokens[repoCacheKey(name, r.Owner, r.Repository, r.Ref)]
+		}
+		shared.Providers[name] = prov
+	}
+	s.Providers = shared.Providers
+	s.TrackedPackages = shared.TrackedPackages
+	s.PackageGroups = shared.PackageGroups
+	s.RebuildRepositoriesCache()
+}
+
+// sharedEmpty reports whether no repositories, tracked packages or package
+// groups are configured
+func (s *GUIState) sharedEmpty() bool {
+	for _, prov := range s.Providers {
+		if len(prov.Repositories) > 0 {
+			return false
+		}
+	}
+	return len(s.TrackedPackages) == 0 && len(s.PackageGroups) == 0
+}
+
+// sharedHash fingerprints the shared sections to detect local edits
+func (s *GUIState) sharedHash() (string, error) {
+	out, err := yaml.Marshal(struct {
+		Providers       map[string]ProviderConfigWrapper `yaml:"providers"`
+		TrackedPackages []string                         `yaml:"trackedPackages"`
+		PackageGroups   map[string][]string              `yaml:"packageGroups"`
+	}{s.SharedCopy().Providers, s.TrackedPackages, s.PackageGroups})
+	if err != nil {
+		return "", fmt.Errorf("state: hash shared state: %w", err)
+	}
+	return sha256Hex(out), nil
+}
+
+// SyncResult describes what SyncShared did
+type SyncResult string
+
+// SyncShared outcomes
+const (
+	SyncUnchanged SyncResult = "unchanged" // Local and remote already agree
+	SyncPushed    SyncResult = "pushed"    // Local edits were uploaded
+	SyncPulled    SyncResult = "pulled"    // Remote edits were applied locally
+)
+
+// SyncConflictError reports that both the local and remote shared state
+// changed since the last sync. Resolve with SyncShared using SyncPreferLocal
+// or SyncPreferRemote.
+type SyncConflictError struct {
+	Remote *GUIState // The remote state at the time of the conflict
+}
+
+func (e *SyncConflictError) Error() string {
+	return "state: shared state changed both locally and remotely since the last sync"
+}
+
+// Unwrap lets errors.Is(err, ErrStateConflict) match sync conflicts
+func (e *SyncConflictError) Unwrap() error {
+	return ErrStateConflict
+}
+
+// SyncStrategy chooses how SyncShared treats concurrent changes
+type SyncStrategy int
+
+// Sync strategies
+const (
+	SyncDetectConflicts SyncStrategy = iota // Return *SyncConflictError when both sides changed
+	SyncPreferLocal                         // Overwrite the remote with local state
+	SyncPreferRemote                        // Replace local shared sections with the remote
+)
+
+// SyncShared reconciles the shared sections with the remote document named
+// by GUI.Sync and records the new version in GUI.Sync. Changes on only one
+// side are pushed or pulled; changes on both sides are a conflict unless
+// strategy picks a winner. A remote write racing another machine also
+// surfaces as a conflict.
+func (s *GUIState) SyncShared(ctx context.Context, store RemoteGUIStateStore, strategy SyncStrategy) (SyncResult, error) {
+	key := s.GUI.Sync.RemoteKey()
+	localHash, err := s.sharedHash()
+	if err != nil {
+		return "", err
+	}
+
+	remote, version, err := store.Fetch(ctx, key)
+	switch {
+	case errors.Is(err, ErrRemoteStateNotFound):
+		return s.pushShared(ctx, store, key, "", localHash)
+	case err != nil:
+		return "", err
+	}
+
+	last := s.GUI.Sync
+	remoteChanged := version != last.LastVersion
+	localChanged := localHash != last.LastHash
+	if last.LastVersion == "" && s.sharedEmpty() {
+		localChanged = false // a fresh install adopts the shared state
+	}
+	switch {
+	case strategy == SyncPreferLocal && (localChanged || remoteChanged):
+		return s.pushShared(ctx, store, key, version, localHash)
+	case strategy == SyncPreferRemote || (remoteChanged && !localChanged):
+		s.ApplyShared(remote)
+		if localHash, err = s.sharedHash(); err != nil {
+			return "", err
+		}
+		s.recordSync(version, localHash)
+		return SyncPulled, nil
+	case remoteChanged:
+		return "", &SyncConflictError{Remote: remote}
+	case localChanged:
+		return s.pushShared(ctx, store, key, version, localHash)
+	default:
+		s.recordSync(version, localHash)
+		return SyncUnchanged, nil
+	}
+}
+
+func (s *GUIState) pushShared(ctx context.Context, store RemoteGUIStateStore, key, version, hash string) (SyncResult, error) {
+	newVersion, err := store.Put(ctx, key, s.SharedCopy(), version)
+	if errors.Is(err, ErrStateConflict) {
+		remote, _, fetchErr := store.Fetch(ctx, key)
+		if fetchErr != nil {
+			return "", err
+		}
+		return "", &SyncConflictError{Remote: remote}
+	}
+	if err != nil {
+		return "", err
+	}
+	s.recordSync(newVersion, hash)
+	return SyncPushed, nil
+}
+
+func (s *GUIState) recordSync(version, hash string) {
+	s.GUI.Sync.LastVersion = version
+	s.GUI.Sync.LastHash = hash
+	s.GUI.Sync.LastSyncedAt = time.Now().UTC()
+}
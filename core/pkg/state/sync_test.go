@@ -0,0 +1,112 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+func newSyncTestState(tracked ...string) *GUIState {
+	st := NewDefaultGUIState()
+	st.Providers = map[string]ProviderConfigWrapper{
+		"github": {
+			Default:      config.RepoDefaults{Token: "default-secret", Analyzer: "poetry"},
+			Repositories: []config.RepoConfig{{Owner: "org", Repository: "api", Ref: "main", Token: "repo-secret"}},
+		},
+	}
+	st.TrackedPackages = tracked
+	st.RebuildRepositoriesCache()
+	return st
+}
+
+func TestSharedCopyStripsTokens(t *testing.T) {
+	st := newSyncTestState("requests")
+	shared := st.SharedCopy()
+	if tok := shared.Providers["github"].Default.Token + shared.Providers["github"].Repositories[0].Token; tok != "" {
+		t.Errorf("shared copy leaked tokens: %q", tok)
+	}
+	if st.Providers["github"].Repositories[0].Token != "repo-secret" {
+		t.Error("SharedCopy must not modify the original")
+	}
+}
+
+func TestSyncShared(t *testing.T) {
+	_, srv := newFakeObjectServer(t)
+	ctx := context.Background()
+	newStore := func() RemoteGUIStateStore {
+		store, err := NewHTTPGUIStateStore(HTTPStoreConfig{BaseURL: srv.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	}
+	a, b := newSyncTestState("requests"), NewDefaultGUIState()
+	storeA, storeB := newStore(), newStore()
+
+	sync := func(st *GUIState, store RemoteGUIStateStore, strategy SyncStrategy, want SyncResult) {
+		t.Helper()
+		got, err := st.SyncShared(ctx, store, strategy)
+		if err != nil || got != want {
+			t.Fatalf("SyncShared = %q, %v; want %q", got, err, want)
+		}
+	}
+
+	sync(a, storeA, SyncDetectConflicts, SyncPushed)    // first sync creates the document
+	sync(b, storeB, SyncDetectConflicts, SyncPulled)    // other machine picks it up
+	sync(b, storeB, SyncDetectConflicts, SyncUnchanged) // nothing new
+	if fmt.Sprint(b.TrackedPackages) != "[requests]" || len(b.RepositoriesCache) != 1 {
+		t.Fatalf("pull did not apply shared state: %v, %d repos", b.TrackedPackages, len(b.RepositoriesCache))
+	}
+	if b.RepositoriesCache[0].Token != "" {
+		t.Error("tokens must not travel through sync")
+	}
+
+	b.TrackedPackages = append(b.TrackedPackages, "django")
+	sync(b, storeB, SyncDetectConflicts, SyncPushed)
+	sync(a, storeA, SyncDetectConflicts, SyncPulled)
+	if fmt.Sprint(a.TrackedPackages) != "[requests django]" {
+		t.Errorf("a did not receive b's edit: %v", a.TrackedPackages)
+	}
+	if a.Providers["github"].Repositories[0].Token != "repo-secret" || a.Providers["github"].Default.Token != "default-secret" {
+		t.Error("pull should keep local tokens")
+	}
+
+	// Concurrent edits conflict until a side is chosen
+	a.TrackedPackages = []string{"numpy"}
+	b.TrackedPackages = []string{"flask"}
+	sync(b, storeB, SyncDetectConflicts, SyncPushed)
+	_, err := a.SyncShared(ctx, storeA, SyncDetectConflicts)
+	var conflict *SyncConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrStateConflict) {
+		t.Fatalf("expected sync conflict, got %v", err)
+	}
+	if fmt.Sprint(conflict.Remote.TrackedPackages) != "[flask]" {
+		t.Errorf("conflict should carry the remote state, got %v", conflict.Remote.TrackedPackages)
+	}
+	sync(a, storeA, SyncPreferLocal, SyncPushed)
+	sync(b, storeB, SyncDetectConflicts, SyncPulled)
+	if fmt.Sprint(b.TrackedPackages) != "[numpy]" {
+		t.Errorf("prefer-local should win, b has %v", b.TrackedPackages)
+	}
+}
+
+func TestNewSyncStore(t *testing.T) {
+	st := NewDefaultGUIState()
+	if _, err := st.NewSyncStore(nil); err == nil {
+		t.Error("expected error when sync is not configured")
+	}
+	st.GUI.Sync = SyncCfg{Backend: "ftp"}
+	if _, err := st.NewSyncStore(nil); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+	st.GUI.Sync = SyncCfg{Backend: SyncBackendHTTP, URL: "https://state.example.com/dd"}
+	if _, err := st.NewSyncStore(nil); err != nil {
+		t.Errorf("http store: %v", err)
+	}
+	if key := st.GUI.Sync.RemoteKey(); key != DefaultSyncKey {
+		t.Errorf("RemoteKey = %q, want default", key)
+	}
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Theme variants accepted in ThemeCfg.Variant
+const (
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+	ThemeSystem = "system" // follow the operating system setting
+)
+
+// AccentPresets lists the supported accent colors, in display order. An
+// empty accent uses the toolkit's default primary color.
+var AccentPresets = []string{"blue", "green", "orange", "purple", "red"}
+
+// ThemeCfg is the GUI appearance preference.
+type ThemeCfg struct {
+	Variant string `yaml:"variant"`          // light | dark | system
+	Accent  string `yaml:"accent,omitempty"` // One of AccentPresets; empty for default
+}
+
+// UnmarshalYAML accepts both the structured form and the legacy plain string
+// (theme: "dark") written by earlier versions.
+func (t *ThemeCfg) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = ThemeCfg{Variant: node.Value}
+		return nil
+	}
+	type plain ThemeCfg
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return fmt.Errorf("invalid theme: %w", err)
+	}
+	*t = ThemeCfg(p)
+	return nil
+}
+
+// Normalized returns the preference with a known variant (light when
+// unrecognized) and accent (cleared when unrecognized), lowercased.
+func (t ThemeCfg) Normalized() ThemeCfg {
+	out := ThemeCfg{
+		Variant: strings.ToLower(strings.TrimSpace(t.Variant)),
+		Accent:  strings.ToLower(strings.TrimSpace(t.Accent)),
+	}
+	switch out.Variant {
+	case ThemeLight, ThemeDark, ThemeSystem:
+	default:
+		out.Variant = ThemeLight
+	}
+	known := false
+	for _, a := range AccentPresets {
+		if out.Accent == a {
+			known = true
+			break
+		}
+	}
+	if !known {
+		out.Accent = ""
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package state
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestThemeCfgUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want ThemeCfg
+	}{
+		{"legacy string", `theme: dark`, ThemeCfg{Variant: "dark"}},
+		{"structured", "theme:\n  variant: system\n  accent: green\n", ThemeCfg{Variant: "system", Accent: "green"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out struct {
+				Theme ThemeCfg `yaml:"theme"`
+			}
+			if err := yaml.Unmarshal([]byte(tt.in), &out); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if out.Theme != tt.want {
+				t.Errorf("got %+v, want %+v", out.Theme, tt.want)
+			}
+		})
+	}
+}
+
+func TestThemeCfgNormalized(t *testing.T) {
+	tests := []struct {
+		in, want ThemeCfg
+	}{
+		{ThemeCfg{}, ThemeCfg{Variant: ThemeLight}},
+		{ThemeCfg{Variant: " Dark ", Accent: "Purple"}, ThemeCfg{Variant: ThemeDark, Accent: "purple"}},
+		{ThemeCfg{Variant: "neon", Accent: "pink"}, ThemeCfg{Variant: ThemeLight}},
+		{ThemeCfg{Variant: "system"}, ThemeCfg{Variant: ThemeSystem}},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Normalized(); got != tt.want {
+			t.Errorf("Normalized(%+v) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package telemetry instruments report runs with OpenTelemetry: spans for
+// each run, repository, analyzed file and provider API request, and metrics
+// of their durations. Until Setup installs OTLP exporters, the global no-op
+// providers make instrumentation free.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of devdashboard's spans and metrics
+const ScopeName = "github.com/greg-hellings/devdashboard/core"
+
+// DefaultMetricsInterval is how often metrics are exported by default
+const DefaultMetricsInterval = 30 * time.Second
+
+// EndpointEnv is the standard OpenTelemetry variable naming the OTLP
+// endpoint; setting it enables export without a telemetry configuration
+const EndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+// Config selects where telemetry is exported
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector base URL (e.g.
+	// "http://otel-collector:4318"); empty uses the OTEL_EXPORTER_OTLP_*
+	// environment variables
+	Endpoint string
+	// Headers are sent with every export request (e.g. an API key)
+	Headers map[string]string
+	// ServiceName identifies the process (default "devdashboard")
+	ServiceName string
+	// ServiceVersion is recorded as service.version when set
+	ServiceVersion string
+	// MetricsInterval is the metric export period (default
+	// DefaultMetricsInterval)
+	MetricsInterval time.Duration
+}
+
+// enabled is set once Setup installed exporters
+var enabled atomic.Bool
+
+// Enabled reports whether Setup installed exporters, so callers can skip
+// instrumentation work that the no-op providers would not make free (e.g.
+// wrapping HTTP transports)
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Setup installs global tracer and meter providers exporting over OTLP/HTTP
+// and returns a function flushing and stopping them, to call before exit.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "devdashboard"
+	}
+	if cfg.MetricsInterval <= 0 {
+		cfg.MetricsInterval = DefaultMetricsInterval
+	}
+	attrs := []attribute.KeyValue{attribute.String("service.name", cfg.ServiceName)}
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, attribute.String("service.version", cfg.ServiceVersion))
+	}
+	res, err := resource.New(ctx, resource.WithFromEnv(), resource.WithTelemetrySDK(), resource.WithAttributes(attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("telemetry resource: %w", err)
+	}
+
+	traceOpts := []otlptracehttp.Option{}
+	metricOpts := []otlpmetrichttp.Option{}
+	if endpoint := strings.TrimSuffix(cfg.Endpoint, "/"); endpoint != "" {
+		traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(endpoint+"/v1/metrics"))
+	}
+	if len(cfg.Headers) > 0 {
+		traceOpts = append(traceOpts, otlptracehttp.WithHeaders(cfg.Headers))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithHeaders(cfg.Headers))
+	}
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry trace exporter: %w", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		_ = traceExporter.Shutdown(ctx)
+		return nil, fmt.Errorf("telemetry metric exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(traceExporter), sdktrace.WithResource(res))
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(cfg.MetricsInterval))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled.Store(true)
+
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}, nil
+}
+
+// EnvConfigured reports whether the environment names an OTLP endpoint
+func EnvConfigured() bool {
+	return os.Getenv(EndpointEnv) != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Tracer returns devdashboard's tracer from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
+
+// Start starts a span named name as a child of ctx's span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err (if any) on span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Metric instruments, created on first use from the global meter provider
+// (which forwards to the one Setup installs later)
+var instruments struct {
+	once       sync.Once
+	report     metric.Float64Histogram
+	repository metric.Float64Histogram
+	file       metric.Float64Histogram
+	request    metric.Float64Histogram
+}
+
+func initInstruments() {
+	instruments.once.Do(func() {
+		m := otel.Meter(ScopeName)
+		// Errors only come from invalid names, which these are not
+		instruments.report, _ = m.Float64Histogram("devdashboard.report.duration",
+			metric.WithUnit("s"), metric.WithDescription("Duration of report runs"))
+		instruments.repository, _ = m.Float64Histogram("devdashboard.repository.duration",
+			metric.WithUnit("s"), metric.WithDescription("Duration of analyzing one repository, by outcome"))
+		instruments.file, _ = m.Float64Histogram("devdashboard.file.duration",
+			metric.WithUnit("s"), metric.WithDescription("Duration of fetching and parsing one dependency file, by outcome"))
+		instruments.request, _ = m.Float64Histogram("devdashboard.provider.request.duration",
+			metric.WithUnit("s"), metric.WithDescription("Duration of provider API requests, by status"))
+	})
+}
+
+// RecordReport records a report run
+func RecordReport(ctx context.Context, d time.Duration) {
+	initInstruments()
+	instruments.report.Record(ctx, d.Seconds())
+}
+
+// RecordRepository records a repository's analysis; outcome is "ok",
+// "cached" or an error category
+func RecordRepository(ctx context.Context, d time.Duration, provider, analyzer, outcome string) {
+	initInstruments()
+	instruments.repository.Record(ctx, d.Seconds(), metric.WithAttributes(
+		attribute.String("devdashboard.provider", provider),
+		attribute.String("devdashboard.analyzer", analyzer),
+		attribute.String("devdashboard.outcome", outcome),
+	))
+}
+
+// RecordFile records a dependency file's analysis; outcome is "ok" or
+// "error"
+func RecordFile(ctx context.Context, d time.Duration, analyzer, outcome string) {
+	initInstruments()
+	instruments.file.Record(ctx, d.Seconds(), metric.WithAttributes(
+		attribute.String("devdashboard.analyzer", analyzer),
+		attribute.String("devdashboard.outcome", outcome),
+	))
+}
+
+// RecordProviderRequest records a provider API request; status is 0 when no
+// response was received
+func RecordProviderRequest(ctx context.Context, d time.Duration, host, method string, status int) {
+	initInstruments()
+	instruments.request.Record(ctx, d.Seconds(), metric.WithAttributes(
+		attribute.String("server.address", host),
+		attribute.String("http.request.method", method),
+		attribute.Int("http.response.status_code", status),
+	))
+}
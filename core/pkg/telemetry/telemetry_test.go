@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetup_ExportsOverOTLP(t *testing.T) {
+	var mu sync.Mutex
+	paths := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path] = r.Header.Get("X-Api-Key")
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tracenoop.NewTracerProvider())
+		otel.SetMeterProvider(metricnoop.NewMeterProvider())
+	})
+
+	shutdown, err := Setup(context.Background(), Config{
+		Endpoint: srv.URL + "/",
+		Headers:  map[string]string{"X-Api-Key": "secret"},
+	})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if !Enabled() {
+		t.Error("Enabled() = false after Setup")
+	}
+
+	ctx, span := Start(context.Background(), "report.Generate")
+	RecordReport(ctx, time.Second)
+	End(span, errors.New("boom"))
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if Enabled() {
+		t.Error("Enabled() = true after shutdown")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, path := range []string{"/v1/traces", "/v1/metrics"} {
+		key, ok := paths[path]
+		if !ok {
+			t.Errorf("nothing exported to %s, got %v", path, paths)
+		} else if key != "secret" {
+			t.Errorf("%s export header = %q, want the configured one", path, key)
+		}
+	}
+}
+
+func TestEnvConfigured(t *testing.T) {
+	t.Setenv(EndpointEnv, "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	if EnvConfigured() {
+		t.Error("EnvConfigured() = true without an endpoint")
+	}
+	t.Setenv(EndpointEnv, "http://collector:4318")
+	if !EnvConfigured() {
+		t.Error("EnvConfigured() = false with " + EndpointEnv)
+	}
+}
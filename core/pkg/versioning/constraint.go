@@ -0,0 +1,151 @@
+package versioning
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+// Range is a parsed version range such as ">=2.0,<3" or "~=1.4.2". Clauses are
+// separated by commas and must all match. Supported operators are ==, !=,
+// >=, <=, >, < and ~= (compatible release); a bare version means ==, and
+// ==/!= accept a trailing ".*" wildcard ("==2.*").
+type Range struct {
+	raw     string
+	scheme  Scheme
+	clauses []rangeClause
+}
+
+// rangeClause is a single comparison; wildcard and compatible-release
+// clauses are expanded into a lower and an exclusive upper bound
+type rangeClause struct {
+	op      string
+	version Version
+	upper   Version // set for "==X.*", "!=X.*" and "~="
+}
+
+var rangeOperators = []string{"===", "==", "!=", ">=", "<=", "~=", ">", "<"}
+
+// ParseRange parses spec using the version scheme of eco
+func ParseRange(eco dependencies.Ecosystem, spec string) (*Range, error) {
+	r := &Range{raw: spec, scheme: SchemeFor(eco)}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		clause, err := r.parseClause(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version range %q: %w", spec, err)
+		}
+		r.clauses = append(r.clauses, clause)
+	}
+	if len(r.clauses) == 0 {
+		return nil, fmt.Errorf("invalid version range %q: no clauses", spec)
+	}
+	return r, nil
+}
+
+func (r *Range) parseClause(s string) (rangeClause, error) {
+	op := "=="
+	for _, candidate := range rangeOperators {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			s = strings.TrimSpace(s[len(candidate):])
+			break
+		}
+	}
+	if op == "===" {
+		op = "=="
+	}
+
+	wildcard := strings.HasSuffix(s, ".*")
+	if wildcard {
+		if op != "==" && op != "!=" {
+			return rangeClause{}, fmt.Errorf("wildcard only allowed with == and !=: %s", s)
+		}
+		s = strings.TrimSuffix(s, ".*")
+	}
+	v, err := r.scheme.Parse(s)
+	if err != nil {
+		return rangeClause{}, err
+	}
+	clause := rangeClause{op: op, version: v}
+
+	var prefix string
+	switch {
+	case wildcard:
+		prefix = s
+	case op == "~=":
+		// ~=1.4.2 means >=1.4.2 and ==1.4.*
+		i := strings.LastIndex(s, ".")
+		if i < 0 {
+			return rangeClause{}, fmt.Errorf("~= needs at least two release segments: %s", s)
+		}
+		prefix = s[:i]
+	default:
+		return clause, nil
+	}
+	upper, err := r.nextRelease(prefix)
+	if err != nil {
+		return rangeClause{}, err
+	}
+	clause.upper = upper
+	return clause, nil
+}
+
+// nextRelease increments the last segment of a release prefix ("1.4" to "1.5")
+func (r *Range) nextRelease(prefix string) (Version, error) {
+	segments := strings.Split(prefix, ".")
+	last, err := strconv.Atoi(segments[len(segments)-1])
+	if err != nil {
+		return nil, fmt.Errorf("prefix %s must end in a number", prefix)
+	}
+	segments[len(segments)-1] = strconv.Itoa(last + 1)
+	return r.scheme.Parse(strings.Join(segments, "."))
+}
+
+// String returns the range as written
+func (r *Range) String() string { return r.raw }
+
+// Contains reports whether version satisfies every clause. Unparsable
+// versions never match.
+func (r *Range) Contains(version string) bool {
+	v, err := r.scheme.Parse(version)
+	if err != nil {
+		return false
+	}
+	for _, c := range r.clauses {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c rangeClause) matches(v Version) bool {
+	cmp := v.Compare(c.version)
+	if c.upper != nil {
+		within := cmp >= 0 && v.Compare(c.upper) < 0
+		if c.op == "!=" {
+			return !within
+		}
+		return within
+	}
+	switch c.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default: // "<"
+		return cmp < 0
+	}
+}
@@ -0,0 +1,50 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		spec    string
+		version string
+		want    bool
+	}{
+		{">=2.0,<3", "2.31.0", true},
+		{">=2.0,<3", "3.0", false},
+		{">=2.0, <3", "1.9", false},
+		{"2.31.0", "2.31", true},
+		{"==2.*", "2.0.1", true},
+		{"==2.*", "3.0", false},
+		{"!=2.*", "3.0", true},
+		{"~=1.4.2", "1.4.9", true},
+		{"~=1.4.2", "1.4.1", false},
+		{"~=1.4.2", "1.5.0", false},
+		{"~=1.4", "1.9", true},
+		{"!=1.0", "1.0.0", false},
+		{">1.0", "1.0.post1", true},
+		{"<=1.0", "garbage", false},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(dependencies.EcosystemPython, tt.spec)
+		if err != nil {
+			t.Fatalf("ParseRange(%q): %v", tt.spec, err)
+		}
+		if got := r.Contains(tt.version); got != tt.want {
+			t.Errorf("ParseRange(%q).Contains(%q) = %v, want %v", tt.spec, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, spec := range []string{"", ",", ">=garbage", "~=1", ">=1.*"} {
+		if _, err := ParseRange(dependencies.EcosystemPython, spec); err == nil {
+			t.Errorf("ParseRange(%q) should fail", spec)
+		}
+	}
+	if r, err := ParseRange(dependencies.EcosystemUnknown, "^1.0"); err == nil {
+		t.Errorf("semver ranges do not support ^, got %v", r)
+	}
+}
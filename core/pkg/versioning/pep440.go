@@ -0,0 +1,169 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PEP440 parses Python package versions as specified by PEP 440, including
+// epochs, pre/post/dev releases and local version labels
+type PEP440 struct{}
+
+// Name returns "pep440"
+func (PEP440) Name() string { return "pep440" }
+
+// pep440Re is the permissive pattern from PEP 440 Appendix B
+var pep440Re = regexp.MustCompile(`(?i)^\s*v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?P<pre>[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?))?` +
+	`(?P<dev>[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?\s*$`)
+
+// pep440Pre ranks normalized pre-release labels
+var pep440Pre = map[string]int{
+	"a": 0, "alpha": 0,
+	"b": 1, "beta": 1,
+	"c": 2, "rc": 2, "pre": 2, "preview": 2,
+}
+
+// pep440Version is a parsed PEP 440 version
+type pep440Version struct {
+	raw     string
+	epoch   int
+	release []int
+	pre     []int // {label rank, number}; nil when not a pre-release
+	post    int   // -1 when not a post-release
+	dev     int   // -1 when not a dev release
+	local   []string
+}
+
+// Parse parses a PEP 440 version such as "1!2.0.0rc1.post2.dev3+local.7"
+func (PEP440) Parse(s string) (Version, error) {
+	m := pep440Re.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid PEP 440 version %q", s)
+	}
+	group := func(name string) string { return m[pep440Re.SubexpIndex(name)] }
+	num := func(s string) int {
+		n, _ := strconv.Atoi(s) // digits only; empty means implicit 0
+		return n
+	}
+
+	v := &pep440Version{raw: s, epoch: num(group("epoch")), post: -1, dev: -1}
+	for _, part := range strings.Split(group("release"), ".") {
+		v.release = append(v.release, num(part))
+	}
+	// Trailing zeros don't affect ordering: 1.0 == 1.0.0
+	for len(v.release) > 1 && v.release[len(v.release)-1] == 0 {
+		v.release = v.release[:len(v.release)-1]
+	}
+	if l := group("pre_l"); l != "" {
+		v.pre = []int{pep440Pre[strings.ToLower(l)], num(group("pre_n"))}
+	}
+	if group("post") != "" {
+		v.post = num(group("post_n1") + group("post_n2"))
+	}
+	if group("dev") != "" {
+		v.dev = num(group("dev_n"))
+	}
+	if local := group("local"); local != "" {
+		v.local = strings.FieldsFunc(strings.ToLower(local), func(r rune) bool {
+			return r == '.' || r == '-' || r == '_'
+		})
+	}
+	return v, nil
+}
+
+// String returns the version as originally written
+func (v *pep440Version) String() string { return v.raw }
+
+// Compare orders versions following PEP 440: epoch, release, pre-release,
+// post-release, dev-release, then local label
+func (v *pep440Version) Compare(other Version) int {
+	o, ok := other.(*pep440Version)
+	if !ok {
+		return strings.Compare(v.String(), other.String())
+	}
+	if c := compareInts(v.epoch, o.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(v.release) || i < len(o.release); i++ {
+		var a, b int
+		if i < len(v.release) {
+			a = v.release[i]
+		}
+		if i < len(o.release) {
+			b = o.release[i]
+		}
+		if c := compareInts(a, b); c != 0 {
+			return c
+		}
+	}
+	if c := compareInts(v.preKey(), o.preKey()); c != 0 {
+		return c
+	}
+	if v.pre != nil && o.pre != nil {
+		if c := compareInts(v.pre[0], o.pre[0]); c != 0 {
+			return c
+		}
+		if c := compareInts(v.pre[1], o.pre[1]); c != 0 {
+			return c
+		}
+	}
+	if c := compareInts(v.post, o.post); c != 0 {
+		return c
+	}
+	if c := compareInts(v.devKey(), o.devKey()); c != 0 {
+		return c
+	}
+	return compareLocal(v.local, o.local)
+}
+
+// preKey places dev-only releases (1.0.dev1) before pre-releases (1.0a1),
+// which sort before final releases (1.0)
+func (v *pep440Version) preKey() int {
+	switch {
+	case v.pre == nil && v.post < 0 && v.dev >= 0:
+		return 0
+	case v.pre != nil:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// devKey places dev releases before the same version without a dev segment
+func (v *pep440Version) devKey() int {
+	if v.dev < 0 {
+		return int(^uint(0) >> 1)
+	}
+	return v.dev
+}
+
+// compareLocal orders local labels: none sorts first, numeric segments
+// compare numerically and sort after alphanumeric ones
+func compareLocal(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+		switch {
+		case errA == nil && errB == nil:
+			if c := compareInts(na, nb); c != 0 {
+				return c
+			}
+		case errA == nil:
+			return 1
+		case errB == nil:
+			return -1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(a), len(b))
+}
@@ -0,0 +1,68 @@
+package versioning
+
+import "testing"
+
+func TestPEP440Ordering(t *testing.T) {
+	// Each version must sort strictly after the previous one
+	ordered := []string{
+		"0.9",
+		"1.0.dev1",
+		"1.0a1.dev1",
+		"1.0a1",
+		"1.0a2",
+		"1.0b1",
+		"1.0rc1",
+		"1.0",
+		"1.0+local.1",
+		"1.0+local.2",
+		"1.0.post1.dev1",
+		"1.0.post1",
+		"1.1",
+		"1.10",
+		"1!0.1",
+	}
+	scheme := PEP440{}
+	for i := 1; i < len(ordered); i++ {
+		a, err := scheme.Parse(ordered[i-1])
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", ordered[i-1], err)
+		}
+		b, err := scheme.Parse(ordered[i])
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", ordered[i], err)
+		}
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestPEP440Equivalence(t *testing.T) {
+	tests := [][2]string{
+		{"1.0", "1.0.0"},
+		{"v2.1", "2.1"},
+		{"1.0-alpha1", "1.0a1"},
+		{"1.0c1", "1.0rc1"},
+		{"1.0-1", "1.0.post1"},
+		{"1.0.RC1", "1.0rc1"},
+	}
+	scheme := PEP440{}
+	for _, tt := range tests {
+		a, errA := scheme.Parse(tt[0])
+		b, errB := scheme.Parse(tt[1])
+		if errA != nil || errB != nil {
+			t.Fatalf("parse errors: %v, %v", errA, errB)
+		}
+		if a.Compare(b) != 0 {
+			t.Errorf("expected %s == %s", tt[0], tt[1])
+		}
+	}
+}
+
+func TestPEP440Invalid(t *testing.T) {
+	for _, s := range []string{"", "latest", "1.0-foo", "git+https://example.com"} {
+		if _, err := (PEP440{}).Parse(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SemVer parses Semantic Versioning 2.0.0 versions. It is lenient about a
+// leading "v" and missing minor/patch components ("v1.2" is 1.2.0); build
+// metadata is ignored for ordering.
+type SemVer struct{}
+
+// Name returns "semver"
+func (SemVer) Name() string { return "semver" }
+
+var semverRe = regexp.MustCompile(`^\s*[vV]?(\d+)(?:\.(\d+))?(?:\.(\d+))?` +
+	`(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?` +
+	`(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?\s*$`)
+
+// semverVersion is a parsed semantic version
+type semverVersion struct {
+	raw        string
+	core       [3]int
+	prerelease []string
+}
+
+// Parse parses a semantic version such as "1.4.0-rc.1+build.5"
+func (SemVer) Parse(s string) (Version, error) {
+	m := semverRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid semantic version %q", s)
+	}
+	v := &semverVersion{raw: s}
+	for i := 0; i < 3; i++ {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid semantic version %q: %w", s, err)
+		}
+		v.core[i] = n
+	}
+	if m[4] != "" {
+		v.prerelease = strings.Split(m[4], ".")
+	}
+	return v, nil
+}
+
+// String returns the version as originally written
+func (v *semverVersion) String() string { return v.raw }
+
+// Compare orders versions by SemVer precedence: major.minor.patch, then a
+// release sorts after any of its pre-releases
+func (v *semverVersion) Compare(other Version) int {
+	o, ok := other.(*semverVersion)
+	if !ok {
+		return strings.Compare(v.String(), other.String())
+	}
+	for i := range v.core {
+		if c := compareInts(v.core[i], o.core[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		a, b := v.prerelease[i], o.prerelease[i]
+		na, errA := strconv.Atoi(a)
+		nb, errB := strconv.Atoi(b)
+		switch {
+		case errA == nil && errB == nil:
+			if c := compareInts(na, nb); c != 0 {
+				return c
+			}
+		case errA == nil:
+			return -1 // numeric identifiers have lower precedence
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(a, b); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(v.prerelease), len(o.prerelease))
+}
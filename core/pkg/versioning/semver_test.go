@@ -0,0 +1,53 @@
+package versioning
+
+import "testing"
+
+func TestSemVerOrdering(t *testing.T) {
+	// Precedence example from the SemVer 2.0.0 specification, plus leniency cases
+	ordered := []string{
+		"0.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"v1.2",
+		"1.10.0",
+	}
+	scheme := SemVer{}
+	for i := 1; i < len(ordered); i++ {
+		a, err := scheme.Parse(ordered[i-1])
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", ordered[i-1], err)
+		}
+		b, err := scheme.Parse(ordered[i])
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", ordered[i], err)
+		}
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestSemVerBuildMetadataIgnored(t *testing.T) {
+	a, _ := SemVer{}.Parse("1.0.0+build.1")
+	b, _ := SemVer{}.Parse("1.0.0+build.2")
+	if a.Compare(b) != 0 {
+		t.Error("build metadata should not affect precedence")
+	}
+	if a.String() != "1.0.0+build.1" {
+		t.Errorf("String() should return the original text, got %q", a.String())
+	}
+}
+
+func TestSemVerInvalid(t *testing.T) {
+	for _, s := range []string{"", "1.0.0.0", "one", "1.0.0-"} {
+		if _, err := (SemVer{}).Parse(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
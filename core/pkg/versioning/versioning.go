@@ -0,0 +1,104 @@
+// Package versioning parses and orders package version strings using the
+// rules of each ecosystem (PEP 440 for Python, Semantic Versioning
+// otherwise). It backs version sorting, per-package min/max and drift
+// detection in reports.
+package versioning
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+// Version is a parsed version that can be ordered against other versions
+// produced by the same Scheme
+type Version interface {
+	// String returns the version as originally written
+	String() string
+
+	// Compare returns -1, 0 or +1 when the version sorts before, equal to or
+	// after other. other must come from the same Scheme.
+	Compare(other Version) int
+}
+
+// Scheme parses version strings for one ecosystem
+type Scheme interface {
+	// Name identifies the scheme (e.g. "pep440", "semver")
+	Name() string
+
+	// Parse parses s, returning an error if it is not a valid version
+	Parse(s string) (Version, error)
+}
+
+// SchemeFor returns the version scheme used by an ecosystem. Ecosystems
+// without specific rules use Semantic Versioning.
+func SchemeFor(eco dependencies.Ecosystem) Scheme {
+	switch eco {
+	case dependencies.EcosystemPython:
+		return PEP440{}
+	default:
+		return SemVer{}
+	}
+}
+
+// Compare orders two version strings within an ecosystem. Valid versions sort
+// before unparsable ones; two unparsable versions compare as strings.
+func Compare(eco dependencies.Ecosystem, a, b string) int {
+	scheme := SchemeFor(eco)
+	va, errA := scheme.Parse(a)
+	vb, errB := scheme.Parse(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.Compare(vb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Sort orders versions ascending in place using Compare
+func Sort(eco dependencies.Ecosystem, versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(eco, versions[i], versions[j]) < 0
+	})
+}
+
+// MinMax returns the lowest and highest valid versions. Unparsable and empty
+// entries are ignored unless no valid version exists, in which case both
+// results are empty.
+func MinMax(eco dependencies.Ecosystem, versions []string) (lowest, highest string) {
+	scheme := SchemeFor(eco)
+	var minV, maxV Version
+	for _, s := range versions {
+		v, err := scheme.Parse(s)
+		if err != nil {
+			continue
+		}
+		if minV == nil || v.Compare(minV) < 0 {
+			minV = v
+		}
+		if maxV == nil || v.Compare(maxV) > 0 {
+			maxV = v
+		}
+	}
+	if minV == nil {
+		return "", ""
+	}
+	return minV.String(), maxV.String()
+}
+
+// compareInts returns -1, 0 or +1 ordering a against b
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
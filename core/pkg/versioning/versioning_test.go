@@ -0,0 +1,56 @@
+package versioning
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
+)
+
+func TestSchemeFor(t *testing.T) {
+	if SchemeFor(dependencies.EcosystemPython).Name() != "pep440" {
+		t.Error("python should use PEP 440")
+	}
+	if SchemeFor(dependencies.EcosystemUnknown).Name() != "semver" {
+		t.Error("unknown ecosystems should fall back to semver")
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"1.10.0", "unknown", "1.2.0", "1.2.0rc1", "", "1.9"}
+	Sort(dependencies.EcosystemPython, versions)
+	want := "[1.2.0rc1 1.2.0 1.9 1.10.0  unknown]"
+	if got := fmt.Sprint(versions); got != want {
+		t.Errorf("Sort() = %s, want %s", got, want)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	lowest, highest := MinMax(dependencies.EcosystemPython, []string{"2.0", "", "1.10", "garbage", "1.9"})
+	if lowest != "1.9" || highest != "2.0" {
+		t.Errorf("MinMax() = (%q, %q), want (1.9, 2.0)", lowest, highest)
+	}
+	lowest, highest = MinMax(dependencies.EcosystemPython, []string{"garbage"})
+	if lowest != "" || highest != "" {
+		t.Errorf("expected empty results without valid versions, got (%q, %q)", lowest, highest)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2", "1.10", -1},
+		{"1.10", "1.2", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.0", "bogus", -1},
+		{"bogus", "1.0", 1},
+		{"abc", "abd", -1},
+	}
+	for _, tt := range tests {
+		if got := Compare(dependencies.EcosystemPython, tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package webhook parses GitHub and GitLab push webhooks so a long-running
+// server can re-analyze just the repository that changed.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+// maxPayloadBytes bounds the request body read from a webhook
+const maxPayloadBytes = 5 << 20
+
+var (
+	// ErrUnauthorized is returned when a webhook's signature or token does
+	// not match the configured secret
+	ErrUnauthorized = errors.New("webhook: invalid signature or token")
+
+	// ErrIgnoredEvent is returned for deliveries that are not branch pushes
+	// (pings, tag pushes, other event types)
+	ErrIgnoredEvent = errors.New("webhook: event ignored")
+)
+
+// PushEvent identifies the branch a push webhook reported
+type PushEvent struct {
+	Provider      string // "github" or "gitlab"
+	FullName      string // owner/repo (GitLab: full namespace path)
+	Branch        string // Pushed branch, without refs/heads/
+	DefaultBranch string // Repository default branch, when the payload includes it
+}
+
+// Matches reports whether repo is the pushed repository at the pushed
+// branch. Repositories without a ref follow the default branch.
+func (e PushEvent) Matches(repo config.RepoWithProvider) bool {
+	if !strings.EqualFold(repo.Provider, e.Provider) {
+		return false
+	}
+	if !strings.EqualFold(repo.Config.Owner+"/"+repo.Config.Repository, e.FullName) {
+		return false
+	}
+	if repo.Config.Ref == "" {
+		return e.DefaultBranch != "" && e.Branch == e.DefaultBranch
+	}
+	return repo.Config.Ref == e.Branch
+}
+
+// ParseGitHubPush validates and decodes a GitHub push delivery. When secret is
+// set the X-Hub-Signature-256 HMAC must match.
+func ParseGitHubPush(r *http.Request, secret string) (*PushEvent, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(want), []byte(r.Header.Get("X-Hub-Signature-256"))) {
+			return nil, ErrUnauthorized
+		}
+	}
+	if event := r.Header.Get("X-GitHub-Event"); event != "push" {
+		return nil, fmt.Errorf("%w: github %q", ErrIgnoredEvent, event)
+	}
+
+	var payload struct {
+		Ref        string `json:"ref"`
+		Repository struct {
+			FullName      string `json:"full_name"`
+			DefaultBranch string `json:"default_branch"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("webhook: decode github payload: %w", err)
+	}
+	return newPushEvent("github", payload.Repository.FullName, payload.Ref, payload.Repository.DefaultBranch)
+}
+
+// ParseGitLabPush validates and decodes a GitLab push hook. When secret is
+// set the X-Gitlab-Token header must equal it.
+func ParseGitLabPush(r *http.Request, secret string) (*PushEvent, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if secret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(r.Header.Get("X-Gitlab-Token"))) != 1 {
+		return nil, ErrUnauthorized
+	}
+	if event := r.Header.Get("X-Gitlab-Event"); event != "Push Hook" {
+		return nil, fmt.Errorf("%w: gitlab %q", ErrIgnoredEvent, event)
+	}
+
+	var payload struct {
+		Ref     string `json:"ref"`
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+			DefaultBranch     string `json:"default_branch"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("webhook: decode gitlab payload: %w", err)
+	}
+	return newPushEvent("gitlab", payload.Project.PathWithNamespace, payload.Ref, payload.Project.DefaultBranch)
+}
+
+func newPushEvent(provider, fullName, ref, defaultBranch string) (*PushEvent, error) {
+	branch, ok := strings.CutPrefix(ref, "refs/heads/")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s ref %q is not a branch", ErrIgnoredEvent, provider, ref)
+	}
+	if fullName == "" {
+		return nil, fmt.Errorf("webhook: %s payload has no repository name", provider)
+	}
+	return &PushEvent{Provider: provider, FullName: fullName, Branch: branch, DefaultBranch: defaultBranch}, nil
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: read body: %w", err)
+	}
+	return body, nil
+}
+
+// Secrets holds the shared secrets verified per provider; an empty secret
+// disables verification for that provider.
+type Secrets struct {
+	GitHub string
+	GitLab string
+}
+
+// NewHandler serves POST /webhooks/github and /webhooks/gitlab under mux,
+// calling onPush for every valid branch push. Deliveries are acknowledged with
+// 202 before onPush's work completes, so onPush should not block for long.
+func NewHandler(mux *http.ServeMux, secrets Secrets, onPush func(PushEvent)) {
+	handle := func(parse func(*http.Request, string) (*PushEvent, error), secret string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			ev, err := parse(r, secret)
+			switch {
+			case errors.Is(err, ErrUnauthorized):
+				slog.Warn("Rejected webhook", "path", r.URL.Path, "error", err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			case errors.Is(err, ErrIgnoredEvent):
+				slog.Debug("Ignored webhook", "path", r.URL.Path, "error", err)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			slog.Info("Push webhook received", "provider", ev.Provider, "repository", ev.FullName, "branch", ev.Branch)
+			onPush(*ev)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}
+	mux.HandleFunc("/webhooks/github", handle(ParseGitHubPush, secrets.GitHub))
+	mux.HandleFunc("/webhooks/gitlab", handle(ParseGitLabPush, secrets.GitLab))
+}
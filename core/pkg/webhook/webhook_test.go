@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/greg-hellings/devdashboard/core/pkg/config"
+)
+
+func githubRequest(event, body, secret string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+	r.Header.Set("X-GitHub-Event", event)
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return r
+}
+
+const githubPush = `{"ref":"refs/heads/main","repository":{"full_name":"Org/API","default_branch":"main"}}`
+
+func TestParseGitHubPush(t *testing.T) {
+	ev, err := ParseGitHubPush(githubRequest("push", githubPush, "s3cret"), "s3cret")
+	if err != nil {
+		t.Fatalf("ParseGitHubPush: %v", err)
+	}
+	if ev.Provider != "github" || ev.FullName != "Org/API" || ev.Branch != "main" || ev.DefaultBranch != "main" {
+		t.Errorf("unexpected event %+v", ev)
+	}
+
+	tests := []struct {
+		name    string
+		req     *http.Request
+		wantErr error
+	}{
+		{"bad signature", githubRequest("push", githubPush, "other"), ErrUnauthorized},
+		{"ping", githubRequest("ping", `{}`, "s3cret"), ErrIgnoredEvent},
+		{"tag push", githubRequest("push", `{"ref":"refs/tags/v1","repository":{"full_name":"o/r"}}`, "s3cret"), ErrIgnoredEvent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseGitHubPush(tt.req, "s3cret"); err == nil || !strings.Contains(err.Error(), tt.wantErr.Error()) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseGitLabPush(t *testing.T) {
+	body := `{"ref":"refs/heads/develop","project":{"path_with_namespace":"group/sub/app","default_branch":"main"}}`
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader(body))
+	r.Header.Set("X-Gitlab-Event", "Push Hook")
+	r.Header.Set("X-Gitlab-Token", "tok")
+	ev, err := ParseGitLabPush(r, "tok")
+	if err != nil {
+		t.Fatalf("ParseGitLabPush: %v", err)
+	}
+	if ev.FullName != "group/sub/app" || ev.Branch != "develop" {
+		t.Errorf("unexpected event %+v", ev)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader(body))
+	r.Header.Set("X-Gitlab-Event", "Push Hook")
+	if _, err := ParseGitLabPush(r, "tok"); err != ErrUnauthorized {
+		t.Errorf("missing token: err = %v", err)
+	}
+}
+
+func TestPushEventMatches(t *testing.T) {
+	ev := PushEvent{Provider: "github", FullName: "org/api", Branch: "main", DefaultBranch: "main"}
+	repo := func(owner, name, ref string) config.RepoWithProvider {
+		return config.RepoWithProvider{Provider: "github", Config: config.RepoConfig{Owner: owner, Repository: name, Ref: ref}}
+	}
+	tests := []struct {
+		name string
+		repo config.RepoWithProvider
+		want bool
+	}{
+		{"same branch", repo("Org", "API", "main"), true},
+		{"other branch", repo("org", "api", "release"), false},
+		{"default branch", repo("org", "api", ""), true},
+		{"other repository", repo("org", "web", "main"), false},
+		{"other provider", config.RepoWithProvider{Provider: "gitlab", Config: config.RepoConfig{Owner: "org", Repository: "api", Ref: "main"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ev.Matches(tt.repo); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var got []PushEvent
+	mux := http.NewServeMux()
+	NewHandler(mux, Secrets{GitHub: "s3cret"}, func(ev PushEvent) { got = append(got, ev) })
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"push", githubRequest("push", githubPush, "s3cret"), http.StatusAccepted},
+		{"ping", githubRequest("ping", `{}`, "s3cret"), http.StatusNoContent},
+		{"unsigned", githubRequest("push", githubPush, ""), http.StatusUnauthorized},
+		{"get", httptest.NewRequest(http.MethodGet, "/webhooks/github", nil), http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+	if len(got) != 1 || got[0].FullName != "Org/API" {
+		t.Errorf("onPush calls = %+v", got)
+	}
+}
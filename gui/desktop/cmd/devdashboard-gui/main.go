@@ -8,29 +8,91 @@ package main
 //   - Asynchronous dependency report using DependencyService (progress streamed)
 //   - Auto-refresh capability honoring state.GUI.AutoRefresh settings
 //   - Repository Add dialog (basic form to append repositories)
+//   - Ref pickers in the add/edit repository dialogs listing the provider's
+//     branches and tags, filtered as you type and flagging unknown refs
+//   - Ref comparison dialog highlighting packages whose versions differ
+//     between refs of a repository tracked at several refs
+//   - Shared state sync (gui.sync) of repositories, tracked packages and
+//     package groups through an HTTP or S3-compatible store, with conflict
+//     detection
+//   - Bulk repository import from CSV/JSON inventories with a preview of
+//     additions, duplicates and invalid rows before applying
 //   - Tracked Packages management (modal editor)
 //   - JSON report export (similar shape to CLI JSON output)
-//   - Ring-buffer log capture with level filtering
-//   - Sidebar navigation (Providers, Repositories, Dependencies, Packages, Logs)
-//   - Row detail modal for full dependency list per repository
+//   - Ring-buffer log capture with level/source filtering, follow mode and
+//     text or JSON Lines export
+//   - Sidebar navigation (Overview, Providers, Repositories, Dependencies, Packages, Graph, Policies, Errors, Alerts, Logs, Settings)
+//   - Overview view: cards for repositories analyzed, errors, packages
+//     tracked, the largest drift and the last run's duration, the most
+//     divergent packages, and drift and failures across archived snapshots
+//   - Row detail modal for full dependency list per repository, with the
+//     per-file versions of packages its dependency files disagree on
+//   - Repository health page (from the Repositories list and the row detail
+//     modal): provider metadata, analyzed commit, last error and package counts
+//   - Live config lint warnings (hover tooltips) on Repositories view rows
+//   - Dependencies table search/filter toolbar, including an ecosystem
+//     select (persisted in gui.dependencyFilter)
+//   - Click-to-sort dependency columns (version-aware, persisted in
+//     gui.dependencySort) with pinned header row and repository column
+//   - Dependencies heatmap mode (gui.dependencyHeatmap) coloring each cell
+//     by how many versions it is behind the newest one found
+//   - Package detail dialog (right-click a package column header): the
+//     versions in use, the repositories locking each and whether directly
+//     or for development, and the package's versions across snapshots
+//   - Errors view grouping structured errors and per-repository report
+//     failures by run, with copy-to-clipboard and "clear resolved"
+//   - Window size/full-screen state saved on resize (gui.lastWindow)
+//   - Optional system tray mode (gui.tray): close hides to tray, background
+//     refresh continues, notifications on new drift/errors
+//   - Alerts view: alert rules (new error, package version change, drift
+//     above N versions) checked after each background refresh, firing
+//     desktop notifications, with acknowledge and snooze
+//   - Paginated dependencies table (gui.dependencyPageSize repositories per
+//     page) backed by a precomputed filtered view
+//   - Repository tags (edited in the repository dialogs) with tag filtering
+//     and grouping of the dependencies table (gui.dependencyGroupByTag)
+//   - Graph view answering "what depends on X?" from the lock file
+//     dependency graphs (uv.lock, poetry.lock) of the latest report
+//   - Policies view listing violations of the version pinning and source
+//     rules in the state's policies section (loaded from CLI configs);
+//     findings matched by the state's ignores are greyed out with their
+//     reason, here and in the dependencies table
+//   - Settings view editing concurrency, auto-refresh, logging and timeouts
+//     with validation, applied without a restart, and reset to defaults
+//   - History view charting a package's version adoption across the
+//     snapshots archived beside the profile's state after each report
+//   - First-run setup wizard (also in the sidebar): provider, token
+//     validation, repository discovery or entry, tracked packages and the
+//     first report, written into the state on Finish
 //
 // State Persistence:
-//   Uses statepkg.LoadGUIState("") and statepkg.SaveGUIState(st, "").
-//   Default path resolution is handled by DefaultStatePath().
-//   State mutations trigger a debounced save.
+//   Uses statepkg.LoadProfile and statepkg.SaveProfile. The "default"
+//   profile lives at DefaultGUIStatePath(); others (created, switched and
+//   deleted from the sidebar, or opened with --profile <name>) live in a
+//   profiles directory beside it. State mutations trigger a debounced save
+//   that holds the state file lock and refuses to overwrite a file another
+//   instance (or a scheduled CLI run) wrote since we loaded it; such writes
+//   are also detected by polling and prompt to reload or keep our state.
+//   Each Runtime owns its debouncer; pending saves are flushed when the
+//   window closes and failed background saves are shown as a toast.
 //
 // Tracked Packages:
 //   If state.TrackedPackages is empty, the Dependencies table falls back to
 //   displaying all packages discovered in the current report. Managing tracked
-//   packages allows users to focus comparisons.
+//   packages allows users to focus comparisons. Named package groups
+//   (state.PackageGroups, also importable from CLI YAML) act as filter presets
+//   that replace the tracked list while selected.
 //
 // Auto-Refresh:
 //   If enabled in YAML state (gui.autoRefresh.enabled), a background goroutine
 //   triggers a report refresh at gui.autoRefresh.intervalSeconds. Safeguards
-//   prevent overlapping runs.
+//   prevent overlapping runs. Background refreshes (auto-refresh and the
+//   tray's Refresh Now) evaluate the state's alertRules against the previous
+//   report.
 //
 // DependencyService Progress:
-//   Progress events are displayed in a minimal list below the status line.
+//   Progress events update a completed/total count in the status line,
+//   throttled so large reports don't flood the UI thread.
 //   Future phases can enhance with per-repository progress bars.
 //
 // NOTE: Credentials remain ephemeral prototypes and are not stored securely.
@@ -52,32 +114,48 @@ package main
 //   - Repository edit/remove dialogs
 //   - JSON export refinement (error section toggles)
 //   - Detailed progress with granular phases
-//   - History/diff of previous reports
-//   - Package search/filter toolbar
-//   - Error pane separate from Logs
+//   - Diff of previous reports
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"image/color"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
 	fapp "fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/greg-hellings/devdashboard/core/pkg/config"
+	"github.com/greg-hellings/devdashboard/core/pkg/demo"
+	"github.com/greg-hellings/devdashboard/core/pkg/dependencies"
 	"github.com/greg-hellings/devdashboard/core/pkg/report"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository"
+	"github.com/greg-hellings/devdashboard/core/pkg/repository/memory"
 	"github.com/greg-hellings/devdashboard/core/pkg/services"
 	statepkg "github.com/greg-hellings/devdashboard/core/pkg/state"
+	"github.com/greg-hellings/devdashboard/core/pkg/versioning"
 )
 
 // version override via -ldflags "-X main.version=..."
@@ -86,10 +164,9 @@ var version = "devdesktop-phase2"
 // ----- Runtime Layer (Non-persisted fields) -----
 //
 // Theme handling:
-// We persist the desired variant (light|dark) in rt.state.GUI.Theme and
-// store the preference using the app's Preferences under key "themeVariant".
-// Fyne will apply the variant automatically; we no longer use deprecated
-// theme.LightTheme()/theme.DarkTheme() helpers nor a custom theme wrapper.
+// rt.state.GUI.Theme holds the variant (light|dark|system) and an optional
+// accent preset. applyTheme installs an appTheme wrapping the default theme,
+// so changes take effect immediately without a restart.
 
 // Runtime encapsulates the live (non-persisted) GUI execution state,
 // including the current dependency report, progress events, credential
@@ -115,8 +192,43 @@ type Runtime struct {
 	// Credential store (env/YAML/keyring resolution)
 	credentialStore statepkg.CredentialStore
 
+	// Config lint warnings keyed by RepositoriesCache index (see refreshRepoLint)
+	repoLint map[int][]statepkg.RepoLintWarning
+
+	// Filtered rows/columns of the dependencies table (see refreshDependencyView)
+	depView statepkg.DependencyTableView
+	// Current dependencies table page (0-based; clamped by DependencyTableView.Page)
+	depPage int
+
+	// Run ID (start time) of the last completed report, used to clear resolved errors
+	lastRunID string
+
+	// Provider API requests of reports run with HTTP tracing on (see
+	// buildLogsView); nil until the first traced run
+	httpTracer *repository.HTTPTracer
+
 	// Auto-refresh control
 	autoRefreshStopChan chan struct{}
+	// The next report run is a background refresh, evaluating alert rules
+	// (see runBackgroundRefresh)
+	backgroundRun bool
+
+	// Debounced state writes (see saveState and Flush)
+	saver stateSaver
+
+	// Windows of the views detached from the main window (see
+	// hostDetachableView); only touched from UI callbacks
+	detachedWindows map[viewID]fyne.Window
+
+	// Snapshot history directory of demo mode (see startDemo); while set the
+	// state is never saved
+	demoHistoryDir string
+
+	// Called (from any goroutine) when the state file was written by another
+	// process; see watchStateFile and saveState
+	onExternalChange func()
+	// Called (from any goroutine) when a debounced save fails
+	onSaveError func(error)
 }
 
 // NewRuntime constructs a Runtime wrapper around a loaded GUIState,
@@ -149,16 +261,16 @@ type LogEntry struct {
 // bounded ring buffer for GUI inspection while delegating to an underlying
 // handler (next). It is safe for concurrent use.
 type RingLogHandler struct {
-	next     slog.Handler
-	capacity int
+	next  slog.Handler
+	level *slog.LevelVar
 
-	mu    sync.RWMutex
-	logs  []LogEntry
-	level slog.Level
+	mu       sync.RWMutex
+	logs     []LogEntry
+	capacity int
 }
 
-// NewRingLogHandler constructs a RingLogHandler that records up to 'capacity' log entries at or above the provided 'level' while forwarding all records to the wrapped 'next' handler. A non-positive capacity falls back to 5000.
-func NewRingLogHandler(next slog.Handler, capacity int, level slog.Level) *RingLogHandler {
+// NewRingLogHandler constructs a RingLogHandler that records up to 'capacity' log entries at or above the provided 'level' while forwarding all records to the wrapped 'next' handler. A non-positive capacity falls back to 5000. The level is shared with handlers derived by WithAttrs/WithGroup and may be changed later (see SetLevel).
+func NewRingLogHandler(next slog.Handler, capacity int, level *slog.LevelVar) *RingLogHandler {
 	if capacity <= 0 {
 		capacity = 5000
 	}
@@ -172,14 +284,14 @@ func NewRingLogHandler(next slog.Handler, capacity int, level slog.Level) *RingL
 
 // Enabled reports whether a log of the given level should be processed (captured + forwarded). Only levels >= handler level are retained.
 func (h *RingLogHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
-	return lvl >= h.level && h.next.Enabled(ctx, lvl)
+	return lvl >= h.level.Level() && h.next.Enabled(ctx, lvl)
 }
 
 // Handle records the log entry in the ring buffer if its level meets the threshold, while always delegating to the wrapped handler.
 func (h *RingLogHandler) Handle(ctx context.Context, rec slog.Record) error {
 	_ = h.next.Handle(ctx, rec)
 
-	if rec.Level < h.level {
+	if rec.Level < h.level.Level() {
 		return nil
 	}
 
@@ -206,12 +318,37 @@ func (h *RingLogHandler) Handle(ctx context.Context, rec slog.Record) error {
 
 // WithAttrs returns a new RingLogHandler wrapping the underlying handler augmented with the provided attributes; captured entries remain separate.
 func (h *RingLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return NewRingLogHandler(h.next.WithAttrs(attrs), h.capacity, h.level)
+	return NewRingLogHandler(h.next.WithAttrs(attrs), h.Capacity(), h.level)
 }
 
 // WithGroup returns a new RingLogHandler scoping subsequent attributes under the provided group name; ring buffer semantics are unchanged.
 func (h *RingLogHandler) WithGroup(name string) slog.Handler {
-	return NewRingLogHandler(h.next.WithGroup(name), h.capacity, h.level)
+	return NewRingLogHandler(h.next.WithGroup(name), h.Capacity(), h.level)
+}
+
+// SetLevel changes the minimum level captured (and, when the wrapped handler shares the same LevelVar, forwarded).
+func (h *RingLogHandler) SetLevel(lvl slog.Level) {
+	h.level.Set(lvl)
+}
+
+// Capacity returns the maximum number of retained entries.
+func (h *RingLogHandler) Capacity() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.capacity
+}
+
+// SetCapacity changes the maximum number of retained entries, dropping the oldest ones beyond it. A non-positive capacity is ignored.
+func (h *RingLogHandler) SetCapacity(capacity int) {
+	if capacity <= 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.capacity = capacity
+	if len(h.logs) > capacity {
+		h.logs = append(h.logs[:0], h.logs[len(h.logs)-capacity:]...)
+	}
 }
 
 // Entries returns a snapshot copy of all retained log entries in FIFO order.
@@ -223,45 +360,51 @@ func (h *RingLogHandler) Entries() []LogEntry {
 	return cp
 }
 
+// parseLogLevel maps a GUI logging level name to a slog level (info when unknown)
+func parseLogLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
 // ----- Main -----
 
 func main() {
+	profileName := flag.String("profile", statepkg.DefaultProfile, "State profile to open (see the sidebar profile selector)")
+	demoMode := flag.Bool("demo", false, "Start in demo mode with generated sample data (nothing is saved)")
+	flag.Parse()
+
 	app := fapp.NewWithID("devdashboard.desktop")
-	state, err := statepkg.LoadGUIState("")
+	state, err := statepkg.LoadProfile(*profileName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load GUI state: %v\n", err)
 		state = statepkg.NewDefaultGUIState()
 	}
 	runtime := NewRuntime(state)
+	refreshRepoLint(runtime)
 
-	// Initialize theme preference based on persisted state (light|dark).
-	// Store it so Fyne applies the preferred variant.
-	switch strings.ToLower(state.GUI.Theme) {
-	case "dark":
-		app.Preferences().SetString("themeVariant", "dark")
-	default:
-		app.Preferences().SetString("themeVariant", "light")
-		runtime.state.GUI.Theme = "light"
-	}
+	applyTheme(app, state.GUI.Theme)
 
-	// Logging level mapping
-	logLevel := slog.LevelInfo
-	switch strings.ToLower(state.GUI.Logging.Level) {
-	case "debug":
-		logLevel = slog.LevelDebug
-	case "warn":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	}
+	// Shared by the stdout and ring handlers so the Settings view can change it
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(parseLogLevel(state.GUI.Logging.Level))
 
 	baseHandler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})
 	logHandler := NewRingLogHandler(baseHandler, state.GUI.Logging.RingBufferSize, logLevel)
 	slog.SetDefault(slog.New(logHandler))
-	slog.Info("GUI starting", "version", version, "statePath", statepkg.DefaultGUIStatePath())
+	statePath, _ := statepkg.ProfileStatePath(state.Profile)
+	slog.Info("GUI starting", "version", version, "profile", state.Profile, "statePath", statePath)
 
-	w := app.NewWindow("DevDashboard")
-	w.Resize(fyne.NewSize(float32(state.GUI.LastWindow.Width), float32(state.GUI.LastWindow.Height)))
+	w := app.NewWindow(windowTitle(state.Profile))
+	if geo := state.GUI.LastWindow; geo.Width > 0 && geo.Height > 0 {
+		w.Resize(fyne.NewSize(float32(geo.Width), float32(geo.Height)))
+	}
 	if state.GUI.LastWindow.Maximized {
 		w.SetFullScreen(true)
 	}
@@ -293,25 +436,333 @@ func main() {
 		}
 	}
 
-	root := buildUI(app, w, runtime, logHandler, enqueueUI)
-	w.SetContent(root)
+	quit := func() {
+		stopAutoRefresh(runtime)
+		runtime.endDemo()
+		uiOnce.Do(func() { close(uiQueue) })
+		app.Quit()
+	}
+	// shutdown flushes any pending save before quitting; when that fails the
+	// user can stay (and retry or fix the problem) or quit without saving
+	shutdown := func() {
+		slog.Info("Window closing - saving state")
+		if err := runtime.Flush(); err != nil {
+			w.Show()
+			dialog.ShowConfirm("Save Failed",
+				fmt.Sprintf("Your latest changes could not be saved:\n%v\n\nQuit without saving?", err),
+				func(ok bool) {
+					if ok {
+						quit()
+					}
+				}, w)
+			return
+		}
+		quit()
+	}
+	runtime.onSaveError = func(err error) {
+		enqueueUI(func() { showToast(w, "Saving state failed: "+err.Error()) })
+	}
+	var trayOnce sync.Once
+	enableTray := func() {
+		trayOnce.Do(func() { setupTray(app, w, runtime, enqueueUI, shutdown) })
+	}
+
+	// switchProfile saves the current profile and rebuilds the window around
+	// the selected one
+	var switchProfile func(name string) error
+	var activateProfile func(next *statepkg.GUIState)
+	var startOnboarding, startDemo func()
+	switchProfile = func(name string) error {
+		runtime.mu.RLock()
+		running := runtime.reportRunning
+		current := runtime.state.Profile
+		runtime.mu.RUnlock()
+		if name == current {
+			return nil
+		}
+		if running {
+			return errors.New("a report is running; switch profiles after it finishes")
+		}
+		next, err := statepkg.LoadProfile(name)
+		if err != nil {
+			return err
+		}
+		if err := runtime.Flush(); err != nil {
+			return fmt.Errorf("saving profile %q: %w", current, err)
+		}
+		runtime.endDemo()
+		activateProfile(next)
+		slog.Info("Switched profile", "from", current, "to", next.Profile)
+		return nil
+	}
+
+	// activateProfile swaps in a loaded state and rebuilds the window around it
+	activateProfile = func(next *statepkg.GUIState) {
+		stopAutoRefresh(runtime)
+
+		runtime.mu.Lock()
+		runtime.state = next
+		runtime.currentReport = nil
+		runtime.depView = statepkg.DependencyTableView{}
+		runtime.depPage = 0
+		runtime.lastRunID = ""
+		runtime.mu.Unlock()
+		refreshRepoLint(runtime)
+
+		applyTheme(app, next.GUI.Theme)
+		w.SetTitle(windowTitle(next.Profile))
+		w.SetContent(container.New(newGeometryLayout(runtime, w),
+			buildUI(app, w, runtime, logHandler, enqueueUI, enableTray, switchProfile, startOnboarding, startDemo)))
+		startAutoRefresh(runtime, enqueueUI)
+		if next.GUI.Tray.Enabled {
+			enableTray()
+		}
+	}
+
+	// Another instance (or a scheduled CLI run) wrote our state file: offer to
+	// reload it or to keep the in-memory state and overwrite the file
+	var changePromptOpen atomic.Bool
+	runtime.onExternalChange = func() {
+		if !changePromptOpen.CompareAndSwap(false, true) {
+			return
+		}
+		enqueueUI(func() {
+			runtime.mu.RLock()
+			profile := runtime.state.Profile
+			runtime.mu.RUnlock()
+			msg := widget.NewLabel(fmt.Sprintf("The state file of profile %q was changed by another DevDashboard instance.\nReload it (discarding unsaved changes here) or keep this window's state and overwrite it?", profile))
+			msg.Wrapping = fyne.TextWrapWord
+			d := dialog.NewCustomConfirm("State Changed on Disk", "Reload", "Keep Mine", msg, func(reload bool) {
+				defer changePromptOpen.Store(false)
+				if !reload {
+					if err := runtime.ForceSave(); err != nil {
+						dialog.ShowError(err, w)
+					}
+					return
+				}
+				runtime.DiscardPendingSave()
+				next, err := statepkg.LoadProfile(profile)
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				activateProfile(next)
+				slog.Info("Reloaded state changed on disk", "profile", profile)
+			}, w)
+			d.Resize(fyne.NewSize(480, 200))
+			d.Show()
+		})
+	}
+	go watchStateFile(runtime, 5*time.Second, runtime.onExternalChange)
+
+	rebuild := func() {
+		w.SetContent(container.New(newGeometryLayout(runtime, w),
+			buildUI(app, w, runtime, logHandler, enqueueUI, enableTray, switchProfile, startOnboarding, startDemo)))
+	}
+
+	// startOnboarding runs the setup wizard; finishing it rebuilds the window
+	// around the new repositories and, if asked, runs the first report and
+	// rebuilds again to show it
+	startOnboarding = func() {
+		showOnboardingWizard(runtime, w, enqueueUI, func(runReport bool) {
+			refreshRepoLint(runtime)
+			rebuild()
+			if runReport {
+				runReportAsync(runtime, enqueueUI, nil, nil, nil, rebuild)
+			}
+		})
+	}
+
+	// startDemo saves the open profile and swaps in a generated demo dataset:
+	// repositories served by the in-memory provider and weeks of snapshot
+	// history in a temporary directory. Nothing is saved until another
+	// profile is picked. It runs the first report and rebuilds to show it.
+	startDemo = func() {
+		runtime.mu.RLock()
+		running := runtime.reportRunning
+		runtime.mu.RUnlock()
+		if running {
+			dialog.ShowError(errors.New("a report is running; start demo mode after it finishes"), w)
+			return
+		}
+		if err := runtime.Flush(); err != nil {
+			dialog.ShowError(fmt.Errorf("saving the open profile: %w", err), w)
+			return
+		}
+		dir, err := os.MkdirTemp("", "devdashboard-demo-")
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		runtime.endDemo()
+		now := time.Now()
+		ds := demo.Generate(demo.Options{})
+		ds.Populate(memory.Default)
+		for _, snap := range ds.History(now) {
+			if err := report.ArchiveSnapshot(dir, snap, 0); err != nil {
+				slog.Warn("Failed to archive demo snapshot", "path", dir, "error", err)
+			}
+		}
+		runtime.mu.Lock()
+		runtime.demoHistoryDir = dir
+		runtime.mu.Unlock()
+		activateProfile(statepkg.NewDemoGUIState(ds, now))
+		slog.Info("Started demo mode", "repositories", len(ds.Repositories()), "history", dir)
+		runReportAsync(runtime, enqueueUI, nil, nil, nil, rebuild)
+	}
+
+	quitItem := fyne.NewMenuItem("Quit", shutdown)
+	quitItem.IsQuit = true
+	w.SetMainMenu(fyne.NewMainMenu(fyne.NewMenu("File",
+		fyne.NewMenuItem("Open Report...", func() {
+			openReportFile(runtime, w, rebuild)
+		}),
+		fyne.NewMenuItem("Export JSON...", func() {
+			exportJSONReport(runtime, w)
+		}),
+		fyne.NewMenuItemSeparator(),
+		quitItem,
+	)))
+
+	root := buildUI(app, w, runtime, logHandler, enqueueUI, enableTray, switchProfile, startOnboarding, startDemo)
+	w.SetContent(container.New(newGeometryLayout(runtime, w), root))
 
 	// Start auto-refresh if enabled (pass dispatcher)
 	startAutoRefresh(runtime, enqueueUI)
 
+	if state.GUI.Tray.Enabled {
+		enableTray()
+	}
+
+	// New users start with an empty dashboard; offer the setup wizard
+	// unless demo mode was asked for
+	if *demoMode {
+		startDemo()
+	} else if state.NeedsOnboarding() {
+		startOnboarding()
+	}
+
 	w.SetCloseIntercept(func() {
-		slog.Info("Window closing - saving state")
-		saveState(runtime)
-		if runtime.autoRefreshStopChan != nil {
-			close(runtime.autoRefreshStopChan)
+		runtime.mu.RLock()
+		toTray := runtime.state.GUI.Tray.Enabled
+		runtime.mu.RUnlock()
+		if toTray {
+			// Keep running (and auto-refreshing) in the tray
+			saveState(runtime)
+			w.Hide()
+			return
 		}
-		uiOnce.Do(func() { close(uiQueue) })
-		app.Quit()
+		shutdown()
 	})
 
 	w.ShowAndRun()
 }
 
+// windowTitle names the open profile unless it is the default one
+func windowTitle(profile string) string {
+	if profile == "" || profile == statepkg.DefaultProfile {
+		return "DevDashboard"
+	}
+	return "DevDashboard — " + profile
+}
+
+// ----- Theme -----
+
+// defaultAccentOption is the accent select entry for the toolkit default color
+const defaultAccentOption = "default accent"
+
+// accentColors maps statepkg.AccentPresets to primary colors
+var accentColors = map[string]color.Color{
+	"blue":   color.NRGBA{R: 0x29, G: 0x6f, B: 0xf6, A: 0xff},
+	"green":  color.NRGBA{R: 0x2e, G: 0x9e, B: 0x4f, A: 0xff},
+	"orange": color.NRGBA{R: 0xf2, G: 0x8c, B: 0x28, A: 0xff},
+	"purple": color.NRGBA{R: 0x8e, G: 0x4e, B: 0xd6, A: 0xff},
+	"red":    color.NRGBA{R: 0xd9, G: 0x3f, B: 0x3f, A: 0xff},
+}
+
+// appTheme wraps the default Fyne theme, forcing the configured variant
+// (unless following the system) and substituting the accent color.
+type appTheme struct {
+	cfg statepkg.ThemeCfg
+}
+
+var _ fyne.Theme = appTheme{}
+
+// Color returns the default theme color for the forced variant, with the
+// primary color replaced by the accent preset
+func (t appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.cfg.Variant {
+	case statepkg.ThemeDark:
+		variant = theme.VariantDark
+	case statepkg.ThemeLight:
+		variant = theme.VariantLight
+	}
+	if name == theme.ColorNamePrimary {
+		if c, ok := accentColors[t.cfg.Accent]; ok {
+			return c
+		}
+	}
+	return theme.DefaultTheme().Color(name, variant)
+}
+
+// Font delegates to the default theme
+func (appTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(style)
+}
+
+// Icon delegates to the default theme
+func (appTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(name)
+}
+
+// Size delegates to the default theme
+func (appTheme) Size(name fyne.ThemeSizeName) float32 {
+	return theme.DefaultTheme().Size(name)
+}
+
+// applyTheme installs the configured theme on the running app
+func applyTheme(app fyne.App, cfg statepkg.ThemeCfg) {
+	app.Settings().SetTheme(appTheme{cfg: cfg.Normalized()})
+}
+
+// ----- System Tray -----
+
+// setupTray installs the system tray menu (desktop drivers only). The menu
+// reopens the window, triggers a background refresh, or quits via shutdown.
+func setupTray(app fyne.App, w fyne.Window, rt *Runtime, enqueueUI func(func()), shutdown func()) {
+	desk, ok := app.(desktop.App)
+	if !ok {
+		slog.Warn("System tray not supported by this driver")
+		return
+	}
+	quit := fyne.NewMenuItem("Quit", shutdown)
+	quit.IsQuit = true
+	desk.SetSystemTrayMenu(fyne.NewMenu("DevDashboard",
+		fyne.NewMenuItem("Open DevDashboard", func() {
+			w.Show()
+			w.RequestFocus()
+		}),
+		fyne.NewMenuItem("Refresh Now", func() {
+			runBackgroundRefresh(rt, enqueueUI)
+		}),
+		fyne.NewMenuItemSeparator(),
+		quit,
+	))
+	slog.Info("System tray enabled")
+}
+
+// problemsNotification summarizes new drift and failures found by a report
+func problemsNotification(p report.Problems) *fyne.Notification {
+	var parts []string
+	if len(p.Drift) > 0 {
+		parts = append(parts, fmt.Sprintf("version drift in %s", strings.Join(p.Drift, ", ")))
+	}
+	if len(p.Errors) > 0 {
+		parts = append(parts, fmt.Sprintf("%d newly failing repositories", len(p.Errors)))
+	}
+	return &fyne.Notification{Title: "Dependency changes detected", Content: "New " + strings.Join(parts, "; ")}
+}
+
 // ----- Auto-Refresh -----
 
 func startAutoRefresh(rt *Runtime, enqueueUI func(func())) {
@@ -339,7 +790,7 @@ func startAutoRefresh(rt *Runtime, enqueueUI func(func())) {
 					slog.Info("Auto-refresh triggering report")
 					fyne.CurrentApp().SendNotification(&fyne.Notification{Title: "Auto-refresh", Content: "Refreshing dependencies"})
 					enqueueUI(func() {
-						runReportAsync(rt, enqueueUI, nil, nil, nil) // status label, table, and container updated in view if present
+						runBackgroundRefresh(rt, enqueueUI)
 					})
 				} else {
 					slog.Debug("Skipping auto-refresh; report already running")
@@ -352,54 +803,109 @@ func startAutoRefresh(rt *Runtime, enqueueUI func(func())) {
 	}()
 }
 
+// runBackgroundRefresh runs a report without a view attached, evaluating
+// the alert rules once it completes
+func runBackgroundRefresh(rt *Runtime, enqueueUI func(func())) {
+	rt.mu.Lock()
+	rt.backgroundRun = true
+	rt.mu.Unlock()
+	runReportAsync(rt, enqueueUI, nil, nil, nil, nil)
+}
+
+// alertsNotification summarizes newly recorded alerts
+func alertsNotification(alerts []statepkg.Alert) *fyne.Notification {
+	if len(alerts) == 1 {
+		return &fyne.Notification{Title: "Alert: " + alerts[0].Rule, Content: alerts[0].Message}
+	}
+	rules := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		if !slices.Contains(rules, a.Rule) {
+			rules = append(rules, a.Rule)
+		}
+	}
+	return &fyne.Notification{Title: fmt.Sprintf("%d new alerts", len(alerts)), Content: strings.Join(rules, "; ")}
+}
+
+// stopAutoRefresh ends the auto-refresh goroutine, if running
+func stopAutoRefresh(rt *Runtime) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.autoRefreshStopChan != nil {
+		close(rt.autoRefreshStopChan)
+		rt.autoRefreshStopChan = nil
+	}
+}
+
 // ----- UI Composition -----
 
 type viewID string
 
 const (
+	viewOverview     viewID = "Overview"
 	viewProviders    viewID = "Providers"
 	viewRepositories viewID = "Repositories"
 	viewDependencies viewID = "Dependencies"
 	viewPackages     viewID = "Packages"
+	viewGraph        viewID = "Graph"
+	viewPolicies     viewID = "Policies"
+	viewErrors       viewID = "Errors"
+	viewAlerts       viewID = "Alerts"
 	viewLogs         viewID = "Logs"
 	viewHistory      viewID = "History"
+	viewSettings     viewID = "Settings"
 )
 
-func buildUI(app fyne.App, w fyne.Window, rt *Runtime, logHandler *RingLogHandler, enqueueUI func(func())) fyne.CanvasObject {
+func buildUI(app fyne.App, w fyne.Window, rt *Runtime, logHandler *RingLogHandler, enqueueUI func(func()), enableTray func(), switchProfile func(string) error, startOnboarding, startDemo func()) fyne.CanvasObject {
 	dyn := container.NewStack()
 
 	// Pre-build views
+	overviewView := buildOverviewView(rt, enqueueUI)
 	providersView := buildProvidersView(rt, app, w)
-	reposView := buildRepositoriesView(rt, app, w)
+	reposView := buildRepositoriesView(rt, app, w, enqueueUI)
 	depsView := buildDependenciesView(rt, w, enqueueUI)
 	packagesView := buildPackagesView(rt, app, w)
-	logsView := buildLogsView(rt, app, w, logHandler)
+	graphView := buildGraphView(rt)
+	policiesView := buildPoliciesView(rt)
+	errorsView := buildErrorsView(rt, app, w)
+	alertsView := buildAlertsView(rt, w)
+	logsView := buildLogsView(rt, app, w, logHandler, enqueueUI)
 
-	historyView := buildHistoryView(rt)
+	historyView := buildHistoryView(rt, enqueueUI)
+	settingsView := buildSettingsView(rt, w, logHandler, enqueueUI)
 
 	views := map[viewID]fyne.CanvasObject{
+		viewOverview:     overviewView,
 		viewProviders:    providersView,
 		viewRepositories: reposView,
 		viewDependencies: depsView,
 		viewPackages:     packagesView,
+		viewGraph:        graphView,
+		viewPolicies:     policiesView,
+		viewErrors:       errorsView,
+		viewAlerts:       alertsView,
 		viewLogs:         logsView,
 		viewHistory:      historyView,
+		viewSettings:     settingsView,
+	}
+	detachers := make(map[viewID]func())
+	for _, id := range detachableViews {
+		views[id], detachers[id] = hostDetachableView(app, rt, id, views[id])
 	}
 
 	// Track current view for highlighting
 	currentView := viewDependencies
 
-	sidebar := buildSidebar(app, dyn, views, rt, &currentView)
+	sidebar := buildSidebar(app, w, dyn, views, detachers, rt, &currentView, enableTray, switchProfile, startOnboarding, startDemo)
 
 	// Initial view
-	dyn.Objects = []fyne.CanvasObject{depsView}
+	dyn.Objects = []fyne.CanvasObject{views[viewDependencies]}
 
 	split := container.NewHSplit(sidebar, dyn)
 	split.SetOffset(0.20)
 	return split
 }
 
-func buildSidebar(app fyne.App, dyn *fyne.Container, views map[viewID]fyne.CanvasObject, rt *Runtime, currentView *viewID) fyne.CanvasObject {
+func buildSidebar(app fyne.App, w fyne.Window, dyn *fyne.Container, views map[viewID]fyne.CanvasObject, detachers map[viewID]func(), rt *Runtime, currentView *viewID, enableTray func(), switchProfile func(string) error, startOnboarding, startDemo func()) fyne.CanvasObject {
 	title := widget.NewLabel(fmt.Sprintf("DevDashboard %s", version))
 	title.Alignment = fyne.TextAlignCenter
 	title.TextStyle = fyne.TextStyle{Bold: true}
@@ -435,34 +941,278 @@ func buildSidebar(app fyne.App, dyn *fyne.Container, views map[viewID]fyne.Canva
 		buttons[id] = btn
 		return btn
 	}
+	// detachableViewBtn adds a button moving the view into its own window
+	detachableViewBtn := func(id viewID) fyne.CanvasObject {
+		return container.NewBorder(nil, nil, nil,
+			widget.NewButtonWithIcon("", theme.ViewRestoreIcon(), detachers[id]),
+			switchViewBtn(id))
+	}
 
-	themeToggle := widget.NewButton("Toggle Theme", func() {
-		// Toggle persisted variant and update app preference.
-		if strings.ToLower(rt.state.GUI.Theme) == "dark" {
-			rt.state.GUI.Theme = "light"
-			app.Preferences().SetString("themeVariant", "light")
-		} else {
-			rt.state.GUI.Theme = "dark"
-			app.Preferences().SetString("themeVariant", "dark")
+	// Theme variant and accent apply immediately and persist in gui.theme
+	setTheme := func(apply func(*statepkg.ThemeCfg)) {
+		rt.mu.Lock()
+		apply(&rt.state.GUI.Theme)
+		cfg := rt.state.GUI.Theme
+		rt.mu.Unlock()
+		applyTheme(app, cfg)
+		saveState(rt)
+	}
+	variantSelect := widget.NewSelect([]string{statepkg.ThemeLight, statepkg.ThemeDark, statepkg.ThemeSystem}, func(v string) {
+		setTheme(func(t *statepkg.ThemeCfg) { t.Variant = v })
+	})
+	variantSelect.Selected = rt.state.GUI.Theme.Variant
+	accentSelect := widget.NewSelect(append([]string{defaultAccentOption}, statepkg.AccentPresets...), func(a string) {
+		if a == defaultAccentOption {
+			a = ""
 		}
+		setTheme(func(t *statepkg.ThemeCfg) { t.Accent = a })
+	})
+	accentSelect.Selected = defaultAccentOption
+	if rt.state.GUI.Theme.Accent != "" {
+		accentSelect.Selected = rt.state.GUI.Theme.Accent
+	}
+	themeControls := container.NewVBox(
+		widget.NewLabel("Theme"),
+		variantSelect,
+		accentSelect,
+	)
+
+	// Tray mode; the icon appears when first enabled and stays until restart
+	// because Fyne cannot remove a tray menu once set.
+	trayToggle := widget.NewCheck("Minimize to tray", func(on bool) {
+		rt.mu.Lock()
+		rt.state.GUI.Tray.Enabled = on
+		rt.mu.Unlock()
 		saveState(rt)
+		if on {
+			enableTray()
+		}
+	})
+	trayToggle.Checked = rt.state.GUI.Tray.Enabled
+	if _, ok := app.(desktop.App); !ok {
+		trayToggle.Disable()
+	}
+
+	profileControls := buildProfileControls(rt, w, switchProfile)
+	setupBtn := widget.NewButtonWithIcon("Setup Wizard...", theme.HelpIcon(), startOnboarding)
+	demoBtn := widget.NewButtonWithIcon("Demo Mode", theme.MediaPlayIcon(), func() {
+		dialog.ShowConfirm("Demo Mode",
+			"Explore DevDashboard with generated sample repositories and report history?\nNothing is saved in demo mode; pick a profile to leave it.",
+			func(ok bool) {
+				if ok {
+					startDemo()
+				}
+			}, w)
 	})
+	rt.mu.RLock()
+	if rt.demoHistoryDir != "" {
+		demoBtn.Disable()
+	}
+	rt.mu.RUnlock()
 
 	return container.NewVBox(
 		title,
+		profileControls,
 		widget.NewSeparator(),
+		switchViewBtn(viewOverview),
 		switchViewBtn(viewProviders),
 		switchViewBtn(viewRepositories),
-		switchViewBtn(viewDependencies),
+		detachableViewBtn(viewDependencies),
 		switchViewBtn(viewPackages),
-		switchViewBtn(viewLogs),
+		switchViewBtn(viewGraph),
+		switchViewBtn(viewPolicies),
+		switchViewBtn(viewErrors),
+		switchViewBtn(viewAlerts),
+		detachableViewBtn(viewLogs),
+		switchViewBtn(viewSettings),
+		setupBtn,
+		demoBtn,
 		widget.NewSeparator(),
-		themeToggle,
+		themeControls,
+		trayToggle,
 		layout.NewSpacer(),
 		widget.NewLabel("© DevDashboard"),
 	)
 }
 
+// detachableViews can be moved into a window of their own (see
+// hostDetachableView), e.g. to watch the logs and the dependencies table on
+// two monitors
+var detachableViews = []viewID{viewDependencies, viewLogs}
+
+// hostDetachableView returns the main window slot showing content and a
+// function detaching it: content moves into a window of its own and the slot
+// shows a placeholder until that window is closed. Whether the view is
+// detached, and its window's geometry, persist in the state's
+// GUI.DetachedViews, so a view detached when the GUI closed reopens in its
+// own window. Rebuilding the UI moves the fresh content into the window
+// already open.
+func hostDetachableView(app fyne.App, rt *Runtime, id viewID, content fyne.CanvasObject) (fyne.CanvasObject, func()) {
+	slot := container.NewStack(content)
+	var win fyne.Window
+
+	attach := func() {
+		if win != nil {
+			delete(rt.detachedWindows, id)
+			win.Close()
+			win = nil
+		}
+		rt.mu.Lock()
+		rt.state.GUI.Detached(string(id)).Open = false
+		rt.mu.Unlock()
+		saveState(rt)
+		slot.Objects = []fyne.CanvasObject{content}
+		slot.Refresh()
+	}
+	placeholder := container.NewCenter(container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("%s is open in its own window.", id)),
+		container.NewHBox(
+			widget.NewButtonWithIcon("Show Window", theme.ViewFullScreenIcon(), func() {
+				if win != nil {
+					win.Show()
+					win.RequestFocus()
+				}
+			}),
+			widget.NewButtonWithIcon("Attach Here", theme.ViewRestoreIcon(), attach),
+		),
+	))
+	show := func() {
+		if win = rt.detachedWindows[id]; win == nil {
+			rt.mu.RLock()
+			title := windowTitle(rt.state.Profile) + " — " + string(id)
+			var geo statepkg.WindowGeometry
+			if d := rt.state.GUI.DetachedViews[string(id)]; d != nil {
+				geo = d.Window
+			}
+			rt.mu.RUnlock()
+			win = app.NewWindow(title)
+			if geo.Width > 0 && geo.Height > 0 {
+				win.Resize(fyne.NewSize(float32(geo.Width), float32(geo.Height)))
+			}
+			win.SetFullScreen(geo.Maximized)
+			if rt.detachedWindows == nil {
+				rt.detachedWindows = make(map[viewID]fyne.Window)
+			}
+			rt.detachedWindows[id] = win
+		}
+		win.SetContent(container.New(newDetachedGeometryLayout(rt, win, id), content))
+		win.SetCloseIntercept(attach)
+		slot.Objects = []fyne.CanvasObject{placeholder}
+		slot.Refresh()
+		win.Show()
+	}
+	detach := func() {
+		slog.Info("Detach view", "view", id)
+		rt.mu.Lock()
+		rt.state.GUI.Detached(string(id)).Open = true
+		rt.mu.Unlock()
+		saveState(rt)
+		show()
+	}
+
+	rt.mu.RLock()
+	open := rt.state.GUI.IsDetached(string(id))
+	rt.mu.RUnlock()
+	if open {
+		show()
+	} else if old := rt.detachedWindows[id]; old != nil {
+		// Another profile was activated with the view attached
+		delete(rt.detachedWindows, id)
+		old.Close()
+	}
+	return slot, detach
+}
+
+// parseTimeout parses a timeout entry; empty means no timeout
+func parseTimeout(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, errors.New("expected a duration such as 30s or 5m")
+	}
+	return d, nil
+}
+
+// buildProfileControls creates the sidebar profile selector with new and
+// delete actions. Each profile has its own state file (providers, tracked
+// packages, credentials snapshot and preferences).
+func buildProfileControls(rt *Runtime, w fyne.Window, switchProfile func(string) error) fyne.CanvasObject {
+	rt.mu.RLock()
+	current := rt.state.Profile
+	rt.mu.RUnlock()
+
+	names, err := statepkg.ListProfiles()
+	if err != nil {
+		slog.Warn("Listing profiles failed", "error", err)
+		names = []string{current}
+	}
+	if !slices.Contains(names, current) {
+		names = append(names, current)
+	}
+
+	profileSelect := widget.NewSelect(names, nil)
+	profileSelect.Selected = current
+	profileSelect.OnChanged = func(name string) {
+		if err := switchProfile(name); err != nil {
+			dialog.ShowError(err, w)
+			profileSelect.SetSelected(current)
+		}
+	}
+
+	newBtn := widget.NewButton("New...", func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("e.g. work, oss")
+		nameEntry.Validator = statepkg.ValidateProfileName
+		dialog.ShowForm("New Profile", "Create", "Cancel",
+			[]*widget.FormItem{{Text: "Name", Widget: nameEntry, HintText: "Starts empty; display preferences are copied"}},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				rt.mu.RLock()
+				_, err := statepkg.CreateProfile(nameEntry.Text, rt.state)
+				rt.mu.RUnlock()
+				if err == nil {
+					err = switchProfile(nameEntry.Text)
+				}
+				if err != nil {
+					dialog.ShowError(err, w)
+				}
+			}, w)
+	})
+
+	deleteBtn := widget.NewButton("Delete", func() {
+		dialog.ShowConfirm("Delete Profile",
+			fmt.Sprintf("Delete profile %q and its repositories, packages and credentials snapshot?", current),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := switchProfile(statepkg.DefaultProfile); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if err := statepkg.DeleteProfile(current); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				slog.Info("Profile deleted", "profile", current)
+			}, w)
+	})
+	if current == statepkg.DefaultProfile {
+		deleteBtn.Disable()
+	}
+
+	return container.NewVBox(
+		widget.NewLabel("Profile"),
+		profileSelect,
+		container.NewGridWithColumns(2, newBtn, deleteBtn),
+	)
+}
+
 // ----- Providers View -----
 
 func buildProvidersView(rt *Runtime, _ fyne.App, _ fyne.Window) fyne.CanvasObject {
@@ -490,6 +1240,50 @@ func buildProvidersView(rt *Runtime, _ fyne.App, _ fyne.Window) fyne.CanvasObjec
 		status.SetText("Status: Validation not implemented")
 	})
 
+	// Network settings are provider defaults; repositories may override them
+	// in the YAML configuration
+	type networkEntries struct {
+		proxy, caFile *widget.Entry
+		skipVerify    *widget.Check
+	}
+	network := map[string]networkEntries{}
+	networkForm := widget.NewForm()
+	rt.mu.RLock()
+	for _, p := range []string{"github", "gitlab"} {
+		defaults := rt.state.Providers[p].Default
+		e := networkEntries{proxy: widget.NewEntry(), caFile: widget.NewEntry(),
+			skipVerify: widget.NewCheck("Skip TLS verification (test instances only)", nil)}
+		e.proxy.SetPlaceHolder("http://proxy.example.com:3128 (default: HTTPS_PROXY)")
+		e.proxy.SetText(defaults.Proxy)
+		e.caFile.SetPlaceHolder("/path/to/ca-bundle.pem (optional)")
+		e.caFile.SetText(defaults.CAFile)
+		e.skipVerify.SetChecked(defaults.InsecureSkipVerify)
+		network[p] = e
+		name := providerDisplayName(p)
+		networkForm.Append(name+" Proxy", e.proxy)
+		networkForm.Append(name+" CA File", e.caFile)
+		networkForm.Append(name+" TLS", e.skipVerify)
+	}
+	rt.mu.RUnlock()
+
+	saveNetworkBtn := widget.NewButton("Save Network Settings", func() {
+		rt.mu.Lock()
+		if rt.state.Providers == nil {
+			rt.state.Providers = map[string]statepkg.ProviderConfigWrapper{}
+		}
+		for p, e := range network {
+			wrapper := rt.state.Providers[p]
+			wrapper.Default.Proxy = strings.TrimSpace(e.proxy.Text)
+			wrapper.Default.CAFile = strings.TrimSpace(e.caFile.Text)
+			wrapper.Default.InsecureSkipVerify = e.skipVerify.Checked
+			rt.state.Providers[p] = wrapper
+		}
+		rt.state.RebuildRepositoriesCache()
+		rt.mu.Unlock()
+		saveState(rt)
+		status.SetText("Status: Network settings saved")
+	})
+
 	return container.NewVBox(
 		widget.NewLabelWithStyle("Provider Management", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		widget.NewSeparator(),
@@ -498,14 +1292,29 @@ func buildProvidersView(rt *Runtime, _ fyne.App, _ fyne.Window) fyne.CanvasObjec
 			&widget.FormItem{Text: "GitLab Token", Widget: gitlabToken},
 		),
 		container.NewHBox(saveBtn, validateBtn),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Network", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		networkForm,
+		saveNetworkBtn,
 		status,
 		layout.NewSpacer(),
 	)
 }
 
+// providerDisplayName returns the product name of a built-in provider
+func providerDisplayName(provider string) string {
+	switch provider {
+	case "github":
+		return "GitHub"
+	case "gitlab":
+		return "GitLab"
+	}
+	return provider
+}
+
 // ----- Repositories View -----
 
-func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasObject {
+func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window, enqueueUI func(func())) fyne.CanvasObject {
 	repoList := widget.NewList(
 		func() int {
 			rt.mu.RLock()
@@ -513,18 +1322,26 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 			return len(rt.state.RepositoriesCache)
 		},
 		func() fyne.CanvasObject {
-			return widget.NewLabel("")
+			return container.NewHBox(newLintBadge(), widget.NewLabel(""))
 		},
 		func(i widget.ListItemID, o fyne.CanvasObject) {
 			rt.mu.RLock()
 			defer rt.mu.RUnlock()
+			row := o.(*fyne.Container)
+			badge := row.Objects[0].(*lintBadge)
+			lbl := row.Objects[1].(*widget.Label)
 			if i >= len(rt.state.RepositoriesCache) {
-				o.(*widget.Label).SetText("")
+				badge.SetWarnings(nil)
+				lbl.SetText("")
 				return
 			}
 			r := rt.state.RepositoriesCache[i]
-			o.(*widget.Label).SetText(fmt.Sprintf("%s: %s/%s@%s (%s)",
-				r.Provider, r.Owner, r.Repository, r.Ref, r.Analyzer))
+			badge.SetWarnings(rt.repoLint[i])
+			text := fmt.Sprintf("%s: %s/%s@%s (%s)", r.Provider, r.Owner, r.Repository, r.Ref, r.Analyzer)
+			if len(r.Tags) > 0 {
+				text += " [" + strings.Join(r.Tags, ", ") + "]"
+			}
+			lbl.SetText(text)
 		},
 	)
 	// Double-click to edit repository directly
@@ -542,25 +1359,52 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 		providerEntry.SetSelected(selected.Provider)
 
 		ownerEntry := widget.NewEntry()
+		ownerEntry.SetPlaceHolder("Owner, or GitLab group path (group/subgroup)")
 		ownerEntry.SetText(selected.Owner)
+		setOwnerValidator(ownerEntry, providerEntry)
 
 		repoEntry := widget.NewEntry()
 		repoEntry.SetText(selected.Repository)
 
-		refEntry := widget.NewEntry()
-		refEntry.SetText(selected.Ref)
-
-		analyzerEntry := widget.NewSelect([]string{"poetry", "pipfile", "uvlock"}, nil)
+		analyzerEntry := widget.NewSelect(dependencies.SupportedAnalyzers(), nil)
 		analyzerEntry.SetSelected(selected.Analyzer)
 
 		pathsEntry := widget.NewMultiLineEntry()
 		pathsEntry.SetText(strings.Join(selected.Paths, "\n"))
 		pathsEntry.SetMinRowsVisible(5)
 
+		excludeEntry := widget.NewMultiLineEntry()
+		excludeEntry.SetPlaceHolder("e.g. tests/fixtures or examples/*")
+		excludeEntry.SetText(strings.Join(selected.ExcludePaths, "\n"))
+		excludeEntry.SetMinRowsVisible(3)
+
 		packagesEntry := widget.NewMultiLineEntry()
 		packagesEntry.SetText(strings.Join(selected.Packages, "\n"))
 		packagesEntry.SetMinRowsVisible(5)
 
+		updatePRsCheck := widget.NewCheck("Annotate open Dependabot/Renovate PRs", nil)
+		updatePRsCheck.SetChecked(selected.UpdatePRs)
+
+		constraintsCheck := widget.NewCheck("Read manifests for declared constraints", nil)
+		constraintsCheck.SetChecked(selected.Constraints)
+
+		tagsEntry := widget.NewEntry()
+		tagsEntry.SetPlaceHolder("e.g. team-payments, deprecated")
+		tagsEntry.SetText(strings.Join(selected.Tags, ", "))
+
+		baseURLEntry := widget.NewEntry()
+		baseURLEntry.SetPlaceHolder("Provider default (e.g. https://gitlab.example.com)")
+		baseURLEntry.SetText(selected.BaseURL)
+
+		tokenEntry := widget.NewPasswordEntry()
+		tokenEntry.SetPlaceHolder("Provider default")
+		tokenEntry.SetText(selected.Token)
+
+		refEntry, refPicker := newRefPicker(rt, w, enqueueUI, func() statepkg.RepoCacheEntry {
+			return refTarget(rt, providerEntry.Selected, ownerEntry.Text, repoEntry.Text, baseURLEntry.Text, tokenEntry.Text)
+		})
+		refEntry.SetText(selected.Ref)
+
 		removeBtn := widget.NewButton("Remove Repository", func() {
 			dialog.ShowConfirm("Remove Repository",
 				fmt.Sprintf("Remove %s/%s@%s?", selected.Owner, selected.Repository, selected.Ref),
@@ -598,10 +1442,16 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 				{Text: "Provider", Widget: providerEntry},
 				{Text: "Owner", Widget: ownerEntry},
 				{Text: "Repository", Widget: repoEntry},
-				{Text: "Ref", Widget: refEntry},
+				{Text: "Ref", Widget: refPicker},
 				{Text: "Analyzer", Widget: analyzerEntry},
 				{Text: "Paths (one per line)", Widget: pathsEntry},
+				{Text: "Exclude Paths (globs, one per line)", Widget: excludeEntry},
 				{Text: "Packages (one per line)", Widget: packagesEntry},
+				{Text: "Update PRs", Widget: updatePRsCheck},
+				{Text: "Constraints", Widget: constraintsCheck},
+				{Text: "Tags (comma-separated)", Widget: tagsEntry},
+				{Text: "Base URL", Widget: baseURLEntry},
+				{Text: "Token", Widget: tokenEntry},
 			},
 			OnSubmit: func() {
 				newProvider := providerEntry.Selected
@@ -613,12 +1463,24 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 					dialog.ShowError(fmt.Errorf("required fields missing"), w)
 					return
 				}
+				if err := config.ValidateNamespace(newProvider, newOwner, newRepo); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
 				newPaths := filterNonEmptyLines(pathsEntry.Text)
+				newExcludes := filterNonEmptyLines(excludeEntry.Text)
+				if err := dependencies.ValidateExcludePaths(newExcludes); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
 				newPackages := filterNonEmptyLines(packagesEntry.Text)
 
 				// Apply changes
 				rt.mu.Lock()
-				// Remove old entry from its provider slice
+				// Remove old entry from its provider slice, keeping its
+				// settings the dialog does not show (proxy, CA file,
+				// enterprise API prefix, canonical ID)
+				var original config.RepoConfig
 				for pi, wrapper := range rt.state.Providers {
 					updated := wrapper.Repositories[:0]
 					for _, r := range wrapper.Repositories {
@@ -626,6 +1488,7 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 							r.Owner == selected.Owner &&
 							r.Repository == selected.Repository &&
 							r.Ref == selected.Ref {
+							original = r
 							continue // drop old
 						}
 						updated = append(updated, r)
@@ -636,13 +1499,24 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 				// Add updated entry to new provider
 				wrapper := rt.state.Providers[newProvider]
 				wrapper.Repositories = append(wrapper.Repositories, config.RepoConfig{
-					Token:      selected.Token, // preserve token if any
-					Owner:      newOwner,
-					Repository: newRepo,
-					Ref:        newRef,
-					Paths:      newPaths,
-					Packages:   newPackages,
-					Analyzer:   newAnalyzer,
+					Token:              repoOverride(tokenEntry.Text, wrapper.Default.Token),
+					BaseURL:            repoOverride(strings.TrimSpace(baseURLEntry.Text), wrapper.Default.BaseURL),
+					Enterprise:         original.Enterprise,
+					APIPrefix:          original.APIPrefix,
+					Owner:              newOwner,
+					Repository:         newRepo,
+					Ref:                newRef,
+					Paths:              newPaths,
+					Packages:           newPackages,
+					Analyzer:           newAnalyzer,
+					UpdatePRs:          updatePRsCheck.Checked,
+					Constraints:        constraintsCheck.Checked,
+					Tags:               config.MergeTags(strings.Split(tagsEntry.Text, ",")),
+					Proxy:              original.Proxy,
+					CAFile:             original.CAFile,
+					InsecureSkipVerify: original.InsecureSkipVerify,
+					ExcludePaths:       excludeOverride(newExcludes, wrapper.Default.ExcludePaths),
+					Canonical:          original.Canonical,
 				})
 				rt.state.Providers[newProvider] = wrapper
 				rt.state.RebuildRepositoriesCache()
@@ -655,10 +1529,14 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 			SubmitText: "Save",
 		}
 
+		healthBtn := widget.NewButton("Repository Health...", func() {
+			showRepositoryHealthDialog(rt, w, enqueueUI, selected)
+		})
+
 		formContainer := container.NewVBox(
 			form,
 			widget.NewSeparator(),
-			removeBtn,
+			container.NewGridWithColumns(2, healthBtn, removeBtn),
 		)
 
 		// Create a larger dialog for better editing experience
@@ -702,14 +1580,50 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 	})
 
 	addRepoBtn := widget.NewButton("Add Repository...", func() {
-		showAddRepositoryDialog(rt, w, repoList, status)
+		showAddRepositoryDialog(rt, w, enqueueUI, repoList, status)
+	})
+
+	importBtn := widget.NewButton("Import CSV/JSON...", func() {
+		fd := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if rc == nil {
+				return
+			}
+			defer func() { _ = rc.Close() }()
+			format, err := statepkg.ImportFormatForPath(rc.URI().Path())
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			rows, err := statepkg.ParseRepositoryImport(rc, format)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			rt.mu.RLock()
+			plan := rt.state.PlanRepositoryImport(rows)
+			rt.mu.RUnlock()
+			showImportPreviewDialog(rt, w, plan, repoList, status)
+		}, w)
+		fd.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json"}))
+		fd.Show()
+	})
+
+	syncBtn := widget.NewButton("Sync...", func() {
+		showSyncDialog(rt, w, enqueueUI, func(msg string) {
+			repoList.Refresh()
+			status.SetText(msg)
+		})
 	})
 
 	return container.NewBorder(
 		container.NewVBox(
 			widget.NewLabelWithStyle("Repository Management", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 			widget.NewSeparator(),
-			container.NewHBox(addRepoBtn, loadConfigBtn),
+			container.NewHBox(addRepoBtn, loadConfigBtn, importBtn, syncBtn),
 			status,
 		),
 		nil, nil, nil,
@@ -717,37 +1631,75 @@ func buildRepositoriesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasOb
 	)
 }
 
-func showAddRepositoryDialog(rt *Runtime, w fyne.Window, list *widget.List, status *widget.Label) {
+// setOwnerValidator validates ownerEntry as the owner of a repository of the
+// provider selected in providerEntry (see config.ValidateNamespace): a single
+// name, or a group path such as group/subgroup/team on GitLab. Changing the
+// provider revalidates it.
+func setOwnerValidator(ownerEntry *widget.Entry, providerEntry *widget.Select) {
+	ownerEntry.Validator = func(text string) error {
+		if text = strings.TrimSpace(text); text == "" {
+			return nil // Required fields are checked on submit
+		}
+		return config.ValidateNamespace(providerEntry.Selected, text, "")
+	}
+	providerEntry.OnChanged = func(string) { _ = ownerEntry.Validate() }
+}
+
+func showAddRepositoryDialog(rt *Runtime, w fyne.Window, enqueueUI func(func()), list *widget.List, status *widget.Label) {
 	providerEntry := widget.NewSelect([]string{"github", "gitlab"}, func(string) {})
 	providerEntry.SetSelected("github")
 
 	ownerEntry := widget.NewEntry()
-	ownerEntry.SetPlaceHolder("Owner / namespace")
+	ownerEntry.SetPlaceHolder("Owner, or GitLab group path (group/subgroup)")
+	setOwnerValidator(ownerEntry, providerEntry)
 
 	repoEntry := widget.NewEntry()
 	repoEntry.SetPlaceHolder("Repository name")
 
-	refEntry := widget.NewEntry()
-	refEntry.SetText("main")
-
-	analyzerEntry := widget.NewSelect([]string{"poetry", "pipfile", "uvlock"}, func(string) {})
+	analyzerEntry := widget.NewSelect(dependencies.SupportedAnalyzers(), func(string) {})
 	analyzerEntry.SetSelected("poetry")
 
 	pathsEntry := widget.NewMultiLineEntry()
 	pathsEntry.SetPlaceHolder("Paths (one per line, optional)")
 
+	excludeEntry := widget.NewMultiLineEntry()
+	excludeEntry.SetPlaceHolder("Exclude paths (globs, e.g. tests/fixtures, optional)")
+
 	packagesEntry := widget.NewMultiLineEntry()
 	packagesEntry.SetPlaceHolder("Packages (one per line)")
 
+	updatePRsCheck := widget.NewCheck("Annotate open Dependabot/Renovate PRs", nil)
+	constraintsCheck := widget.NewCheck("Read manifests for declared constraints", nil)
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder("Tags (comma-separated, optional)")
+
+	baseURLEntry := widget.NewEntry()
+	baseURLEntry.SetPlaceHolder("Base URL (optional, e.g. https://gitlab.example.com)")
+
+	tokenEntry := widget.NewPasswordEntry()
+	tokenEntry.SetPlaceHolder("Token (optional, overrides the provider token)")
+
+	refEntry, refPicker := newRefPicker(rt, w, enqueueUI, func() statepkg.RepoCacheEntry {
+		return refTarget(rt, providerEntry.Selected, ownerEntry.Text, repoEntry.Text, baseURLEntry.Text, tokenEntry.Text)
+	})
+	refEntry.SetText("main")
+
 	form := &widget.Form{
 		Items: []*widget.FormItem{
 			{Text: "Provider", Widget: providerEntry},
 			{Text: "Owner", Widget: ownerEntry},
 			{Text: "Repository", Widget: repoEntry},
-			{Text: "Ref", Widget: refEntry},
+			{Text: "Ref", Widget: refPicker},
 			{Text: "Analyzer", Widget: analyzerEntry},
 			{Text: "Paths", Widget: pathsEntry},
+			{Text: "Exclude Paths", Widget: excludeEntry},
 			{Text: "Packages", Widget: packagesEntry},
+			{Text: "Update PRs", Widget: updatePRsCheck},
+			{Text: "Constraints", Widget: constraintsCheck},
+			{Text: "Tags", Widget: tagsEntry},
+			{Text: "Base URL", Widget: baseURLEntry},
+			{Text: "Token", Widget: tokenEntry},
 		},
 		OnSubmit: func() {
 			provider := providerEntry.Selected
@@ -760,8 +1712,17 @@ func showAddRepositoryDialog(rt *Runtime, w fyne.Window, list *widget.List, stat
 				dialog.ShowError(fmt.Errorf("required fields missing"), w)
 				return
 			}
+			if err := config.ValidateNamespace(provider, owner, repo); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
 
 			paths := filterNonEmptyLines(pathsEntry.Text)
+			excludes := filterNonEmptyLines(excludeEntry.Text)
+			if err := dependencies.ValidateExcludePaths(excludes); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
 			packages := filterNonEmptyLines(packagesEntry.Text)
 
 			rt.mu.Lock()
@@ -770,12 +1731,18 @@ func showAddRepositoryDialog(rt *Runtime, w fyne.Window, list *widget.List, stat
 				wrapper.Default.Analyzer = "poetry"
 			}
 			wrapper.Repositories = append(wrapper.Repositories, config.RepoConfig{
-				Owner:      owner,
-				Repository: repo,
-				Ref:        ref,
-				Paths:      paths,
-				Packages:   packages,
-				Analyzer:   analyzer,
+				Token:        repoOverride(tokenEntry.Text, wrapper.Default.Token),
+				BaseURL:      repoOverride(strings.TrimSpace(baseURLEntry.Text), wrapper.Default.BaseURL),
+				Owner:        owner,
+				Repository:   repo,
+				Ref:          ref,
+				Paths:        paths,
+				Packages:     packages,
+				Analyzer:     analyzer,
+				UpdatePRs:    updatePRsCheck.Checked,
+				Constraints:  constraintsCheck.Checked,
+				Tags:         config.MergeTags(strings.Split(tagsEntry.Text, ",")),
+				ExcludePaths: excludeOverride(excludes, wrapper.Default.ExcludePaths),
 			})
 			rt.state.Providers[provider] = wrapper
 			rt.state.RebuildRepositoriesCache()
@@ -792,6 +1759,164 @@ func showAddRepositoryDialog(rt *Runtime, w fyne.Window, list *widget.List, stat
 	dialog.ShowCustom("Add Repository", "Close", container.NewVScroll(form), w)
 }
 
+// repoOverride returns a repository's token or base URL as entered, or empty
+// when it matches the provider default so the repository keeps inheriting it
+func repoOverride(value, def string) string {
+	if value == def {
+		return ""
+	}
+	return value
+}
+
+// excludeOverride is repoOverride for a repository's exclude paths
+func excludeOverride(patterns, def []string) []string {
+	if slices.Equal(patterns, def) {
+		return nil
+	}
+	return patterns
+}
+
+// showImportPreviewDialog lists what a bulk repository import would add, skip
+// as duplicates and reject, and applies it on confirmation.
+func showImportPreviewDialog(rt *Runtime, w fyne.Window, plan statepkg.RepositoryImportPlan, list *widget.List, status *widget.Label) {
+	var lines []string
+	for _, r := range plan.Add {
+		lines = append(lines, fmt.Sprintf("+ %s: %s/%s@%s (%s)", r.Provider, r.Owner, r.Repository, r.Ref, r.Analyzer))
+	}
+	for _, r := range plan.Duplicates {
+		lines = append(lines, fmt.Sprintf("= %s: %s/%s@%s (already configured)", r.Provider, r.Owner, r.Repository, r.Ref))
+	}
+	for _, p := range plan.Invalid {
+		lines = append(lines, fmt.Sprintf("! row %d: %s", p.Row, p.Message))
+	}
+	rows := widget.NewList(
+		func() int { return len(lines) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(lines[i]) },
+	)
+	summary := widget.NewLabel(fmt.Sprintf("%d to add, %d duplicates skipped, %d invalid rows skipped",
+		len(plan.Add), len(plan.Duplicates), len(plan.Invalid)))
+	content := container.NewBorder(summary, nil, nil, nil, rows)
+
+	preview := dialog.NewCustomConfirm("Import Repositories", "Import", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		rt.mu.Lock()
+		added := rt.state.ApplyRepositoryImport(plan)
+		total := len(rt.state.RepositoriesCache)
+		rt.mu.Unlock()
+		saveState(rt)
+		list.Refresh()
+		status.SetText(fmt.Sprintf("Imported %d repositories (%d total)", added, total))
+		slog.Info("Repositories imported", "added", added, "duplicates", len(plan.Duplicates), "invalid", len(plan.Invalid))
+	}, w)
+	preview.Resize(fyne.NewSize(600, 450))
+	preview.Show()
+}
+
+// showSyncDialog edits gui.sync and shares the repository list, tracked
+// packages and package groups through the configured remote store. done runs
+// on the UI thread with a status message after each sync.
+func showSyncDialog(rt *Runtime, w fyne.Window, enqueueUI func(func()), done func(string)) {
+	rt.mu.RLock()
+	cfg := rt.state.GUI.Sync
+	rt.mu.RUnlock()
+
+	backendSelect := widget.NewSelect([]string{statepkg.SyncBackendHTTP, statepkg.SyncBackendS3}, nil)
+	backendSelect.SetSelected(cfg.Backend)
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://state.example.com/devdashboard or S3 endpoint")
+	urlEntry.SetText(cfg.URL)
+	bucketEntry := widget.NewEntry()
+	bucketEntry.SetPlaceHolder("S3 only")
+	bucketEntry.SetText(cfg.Bucket)
+	regionEntry := widget.NewEntry()
+	regionEntry.SetPlaceHolder("us-east-1")
+	regionEntry.SetText(cfg.Region)
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder(statepkg.DefaultSyncKey)
+	keyEntry.SetText(cfg.Key)
+
+	lastSynced := "Never synced"
+	if !cfg.LastSyncedAt.IsZero() {
+		lastSynced = "Last synced " + cfg.LastSyncedAt.Local().Format(time.RFC1123)
+	}
+	help := widget.NewLabel("Shares repositories, tracked packages and package groups; tokens stay local.\n" +
+		"HTTP token: DEV_DASHBOARD_SYNC_TOKEN. S3 keys: AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY.\n" + lastSynced)
+	help.Wrapping = fyne.TextWrapWord
+
+	var runSync func(strategy statepkg.SyncStrategy)
+	runSync = func(strategy statepkg.SyncStrategy) {
+		rt.mu.Lock()
+		rt.state.GUI.Sync.Backend = backendSelect.Selected
+		rt.state.GUI.Sync.URL = strings.TrimSpace(urlEntry.Text)
+		rt.state.GUI.Sync.Bucket = strings.TrimSpace(bucketEntry.Text)
+		rt.state.GUI.Sync.Region = strings.TrimSpace(regionEntry.Text)
+		rt.state.GUI.Sync.Key = strings.TrimSpace(keyEntry.Text)
+		store, err := rt.state.NewSyncStore(rt.credentialStore)
+		// Sync a detached copy so network I/O never holds the state lock
+		work := rt.state.SharedCopy()
+		work.GUI.Sync = rt.state.GUI.Sync
+		rt.mu.Unlock()
+		saveState(rt)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			result, err := work.SyncShared(ctx, store, strategy)
+			enqueueUI(func() {
+				var conflict *statepkg.SyncConflictError
+				switch {
+				case errors.As(err, &conflict):
+					dialog.ShowCustomConfirm("Sync Conflict", "Keep Local", "Use Remote",
+						widget.NewLabel("The shared state changed both here and remotely since the last sync."),
+						func(keepLocal bool) {
+							if keepLocal {
+								runSync(statepkg.SyncPreferLocal)
+							} else {
+								runSync(statepkg.SyncPreferRemote)
+							}
+						}, w)
+					return
+				case err != nil:
+					slog.Error("State sync failed", "source", "sync", "error", err)
+					dialog.ShowError(err, w)
+					return
+				}
+				rt.mu.Lock()
+				if result == statepkg.SyncPulled {
+					rt.state.ApplyShared(work)
+				}
+				rt.state.GUI.Sync = work.GUI.Sync
+				rt.mu.Unlock()
+				saveState(rt)
+				slog.Info("State synced", "source", "sync", "result", result)
+				done(fmt.Sprintf("Sync: %s", result))
+			})
+		}()
+	}
+
+	form := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: "Backend", Widget: backendSelect},
+			{Text: "URL / Endpoint", Widget: urlEntry},
+			{Text: "Bucket", Widget: bucketEntry},
+			{Text: "Region", Widget: regionEntry},
+			{Text: "Key", Widget: keyEntry},
+		},
+		OnSubmit:   func() { runSync(statepkg.SyncDetectConflicts) },
+		SubmitText: "Save & Sync Now",
+	}
+	d := dialog.NewCustom("Shared State Sync", "Close", container.NewVBox(help, form), w)
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
+}
+
 func filterNonEmptyLines(s string) []string {
 	var out []string
 	for _, line := range strings.Split(s, "\n") {
@@ -810,14 +1935,15 @@ func buildPackagesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasObject
 		func() int {
 			rt.mu.RLock()
 			defer rt.mu.RUnlock()
-			return len(rt.state.TrackedPackages)
+			return len(rt.state.VisiblePackages())
 		},
 		func() fyne.CanvasObject { return widget.NewLabel("") },
 		func(i widget.ListItemID, o fyne.CanvasObject) {
 			rt.mu.RLock()
 			defer rt.mu.RUnlock()
-			if i < len(rt.state.TrackedPackages) {
-				o.(*widget.Label).SetText(rt.state.TrackedPackages[i])
+			visible := rt.state.VisiblePackages()
+			if i < len(visible) {
+				o.(*widget.Label).SetText(visible[i])
 			} else {
 				o.(*widget.Label).SetText("")
 			}
@@ -826,24 +1952,95 @@ func buildPackagesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasObject
 
 	status := widget.NewLabel("No tracked packages defined (uses all).")
 
-	editBtn := widget.NewButton("Edit Tracked Packages...", func() {
+	// Package group presets: "Tracked packages" edits the flat list, any other
+	// entry filters the dependencies table to that named group.
+	groupOptions := func() []string {
+		rt.mu.RLock()
+		defer rt.mu.RUnlock()
+		return append([]string{trackedPackagesOption}, rt.state.PackageGroupNames()...)
+	}
+	groupSelect := widget.NewSelect(groupOptions(), func(choice string) {
+		rt.mu.Lock()
+		if choice == trackedPackagesOption {
+			rt.state.GUI.ActivePackageGroup = ""
+		} else {
+			rt.state.GUI.ActivePackageGroup = choice
+		}
+		rt.mu.Unlock()
+		saveState(rt)
+		list.Refresh()
+		status.SetText(packagesStatusText(rt))
+	})
+	rt.mu.RLock()
+	active := rt.state.GUI.ActivePackageGroup
+	rt.mu.RUnlock()
+	if active == "" {
+		active = trackedPackagesOption
+	}
+	groupSelect.Selected = active // set directly: SetSelected would fire OnChanged and save
+
+	editBtn := widget.NewButton("Edit Packages...", func() {
 		editTrackedPackagesDialog(rt, w, list, status)
 	})
 
+	saveGroupBtn := widget.NewButton("Save as Group...", func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("e.g. crypto-critical")
+		dialog.ShowForm("Save Package Group", "Save", "Cancel",
+			[]*widget.FormItem{{Text: "Group name", Widget: nameEntry}},
+			func(ok bool) {
+				name := strings.TrimSpace(nameEntry.Text)
+				if !ok || name == "" || name == trackedPackagesOption {
+					return
+				}
+				rt.mu.Lock()
+				rt.state.SetPackageGroup(name, rt.state.VisiblePackages())
+				rt.mu.Unlock()
+				saveState(rt)
+				groupSelect.Options = groupOptions()
+				groupSelect.SetSelected(name)
+			}, w)
+	})
+
+	deleteGroupBtn := widget.NewButton("Delete Group", func() {
+		name := groupSelect.Selected
+		if name == "" || name == trackedPackagesOption {
+			return
+		}
+		dialog.ShowConfirm("Delete Package Group", fmt.Sprintf("Delete group %q?", name), func(ok bool) {
+			if !ok {
+				return
+			}
+			rt.mu.Lock()
+			rt.state.DeletePackageGroup(name)
+			rt.mu.Unlock()
+			saveState(rt)
+			groupSelect.Options = groupOptions()
+			groupSelect.SetSelected(trackedPackagesOption)
+		}, w)
+	})
+
 	resetBtn := widget.NewButton("Clear", func() {
 		rt.mu.Lock()
-		rt.state.TrackedPackages = []string{}
+		if name := rt.state.GUI.ActivePackageGroup; name != "" {
+			rt.state.SetPackageGroup(name, nil)
+		} else {
+			rt.state.TrackedPackages = []string{}
+		}
 		rt.mu.Unlock()
 		saveState(rt)
 		list.Refresh()
 		status.SetText("Cleared; table will show all discovered packages.")
 	})
 
+	status.SetText(packagesStatusText(rt))
+
 	return container.NewBorder(
 		container.NewVBox(
 			widget.NewLabelWithStyle("Tracked Packages", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 			widget.NewSeparator(),
-			container.NewHBox(editBtn, resetBtn),
+			container.NewBorder(nil, nil, widget.NewLabel("Filter preset:"), nil, groupSelect),
+			container.NewHBox(editBtn, resetBtn, saveGroupBtn, deleteGroupBtn),
 			status,
 		),
 		nil, nil, nil,
@@ -851,31 +2048,59 @@ func buildPackagesView(rt *Runtime, _ fyne.App, w fyne.Window) fyne.CanvasObject
 	)
 }
 
+// trackedPackagesOption is the package group selector entry for the flat
+// TrackedPackages list (no group active).
+const trackedPackagesOption = "Tracked packages"
+
+// packagesStatusText summarizes which packages the dependencies table shows.
+func packagesStatusText(rt *Runtime) string {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	visible := rt.state.VisiblePackages()
+	name := rt.state.GUI.ActivePackageGroup
+	switch {
+	case len(visible) == 0 && name != "":
+		return fmt.Sprintf("Group %q is empty (uses all).", name)
+	case len(visible) == 0:
+		return "No tracked packages defined (uses all)."
+	case name != "":
+		return fmt.Sprintf("Group %q: %d packages.", name, len(visible))
+	default:
+		return fmt.Sprintf("%d tracked packages.", len(visible))
+	}
+}
+
 func editTrackedPackagesDialog(rt *Runtime, w fyne.Window, list *widget.List, status *widget.Label) {
 	rt.mu.RLock()
-	current := append([]string{}, rt.state.TrackedPackages...)
+	current := append([]string{}, rt.state.VisiblePackages()...)
+	group := rt.state.GUI.ActivePackageGroup
 	rt.mu.RUnlock()
 
 	entry := widget.NewMultiLineEntry()
 	entry.SetText(strings.Join(current, "\n"))
 	entry.SetPlaceHolder("One package per line")
 
+	title := "Edit Tracked Packages"
+	if group != "" {
+		title = fmt.Sprintf("Edit Package Group %q", group)
+	}
+
 	saveBtn := widget.NewButton("Save", func() {
 		newPkgs := filterNonEmptyLines(entry.Text)
 		rt.mu.Lock()
-		rt.state.TrackedPackages = newPkgs
+		if group != "" {
+			rt.state.SetPackageGroup(group, newPkgs)
+		} else {
+			rt.state.TrackedPackages = newPkgs
+		}
 		rt.mu.Unlock()
 		saveState(rt)
 		list.Refresh()
-		if len(newPkgs) == 0 {
-			status.SetText("No tracked packages defined (uses all).")
-		} else {
-			status.SetText(fmt.Sprintf("%d tracked packages.", len(newPkgs)))
-		}
-		dialog.ShowInformation("Saved", "Tracked packages updated.", w)
+		status.SetText(packagesStatusText(rt))
+		dialog.ShowInformation("Saved", "Packages updated.", w)
 	})
 
-	dialog.ShowCustom("Edit Tracked Packages", "Close",
+	dialog.ShowCustom(title, "Close",
 		container.NewBorder(nil, container.NewHBox(saveBtn), nil, nil,
 			widget.NewLabel("Enter packages to display in the main report table (one per line)."),
 			entry,
@@ -895,8 +2120,16 @@ func calculateRepoColumnWidth(rpt *report.Report) float32 {
 	maxLen := len("Repository") // Start with header text
 	longestText := "Repository"
 
-	for _, repo := range rpt.Repositories {
-		repoText := fmt.Sprintf("%s/%s@%s", repo.Owner, repo.Repository, repo.Ref)
+	for i := range rpt.Repositories {
+		repo := &rpt.Repositories[i]
+		// Grouped rows are prefixed with a tag; size for the longest one
+		group := ""
+		for _, tag := range repo.Tags {
+			if len(tag) > len(group) {
+				group = tag
+			}
+		}
+		repoText := repositoryCellText(repo, group, group != "")
 		if len(repoText) > maxLen {
 			maxLen = len(repoText)
 			longestText = repoText
@@ -921,6 +2154,42 @@ func calculateRepoColumnWidth(rpt *report.Report) float32 {
 	return width
 }
 
+// repositoryCellText renders the repository column of the results table. When
+// rows are grouped by tag the group is shown first, with untagged
+// repositories marked as such.
+func repositoryCellText(repo *report.RepositoryReport, group string, grouped bool) string {
+	text := fmt.Sprintf("%s/%s@%s", repo.Owner, repo.Repository, repo.Ref)
+	switch {
+	case !grouped:
+		return text
+	case group == "":
+		return "[untagged] " + text
+	default:
+		return "[" + group + "] " + text
+	}
+}
+
+// versionCellText renders a resolved version for the results table, followed by
+// the declared manifest constraint when one was collected and a "dev" marker
+// when the package is only locked for development. Constraint-only
+// repositories (no lock file) show just the constraint in parentheses.
+func versionCellText(repo *report.RepositoryReport, packageName string) string {
+	version := repo.Dependencies[packageName]
+	if constraint := repo.Constraints[packageName]; version == "" && repo.ConstraintOnly && constraint != "" {
+		return "(" + constraint + ")"
+	}
+	if version == "" {
+		return ""
+	}
+	if constraint := repo.Constraints[packageName]; constraint != "" {
+		version = fmt.Sprintf("%s (%s)", version, constraint)
+	}
+	if repo.DevOnly[packageName] {
+		version += " [dev]"
+	}
+	return version
+}
+
 // calculatePackageColumnWidth calculates the optimal width for a package column
 // based on the longest version string or package name (header)
 func calculatePackageColumnWidth(rpt *report.Report, packageName string) float32 {
@@ -932,8 +2201,8 @@ func calculatePackageColumnWidth(rpt *report.Report, packageName string) float32
 	longestText := packageName
 
 	// Check all version strings for this package
-	for _, repo := range rpt.Repositories {
-		version := repo.Dependencies[packageName]
+	for i := range rpt.Repositories {
+		version := versionCellText(&rpt.Repositories[i], packageName)
 		if version != "" && len(version) > len(longestText) {
 			longestText = version
 		}
@@ -965,6 +2234,7 @@ func calculatePackageColumnWidth(rpt *report.Report, packageName string) float32
 func buildDependenciesView(rt *Runtime, w fyne.Window, enqueueUI func(func())) fyne.CanvasObject {
 	var table *widget.Table // declare early so we can reference it
 	var _ = table           // avoid unused variable error until table is assigned
+	var pager *dependencyPager
 
 	// Create a container that will hold either the table or the spinner
 	contentContainer := container.NewStack()
@@ -984,11 +2254,74 @@ func buildDependenciesView(rt *Runtime, w fyne.Window, enqueueUI func(func())) f
 		// Show spinner when starting refresh
 		contentContainer.Objects = []fyne.CanvasObject{spinnerContainer}
 		contentContainer.Refresh()
-		runReportAsync(rt, enqueueUI, status, table, contentContainer)
+		runReportAsync(rt, enqueueUI, status, table, contentContainer, pager.refresh)
 	})
 	exportBtn := widget.NewButton("Export JSON", func() {
 		exportJSONReport(rt, w)
 	})
+	compareBtn := widget.NewButton("Compare Refs...", func() {
+		showRefComparisonDialog(rt, w)
+	})
+	// Dev dependencies are dropped while analyzing, so a change applies from
+	// the next report
+	rt.mu.RLock()
+	excludeDev := rt.state.GUI.ExcludeDevDependencies
+	rt.mu.RUnlock()
+	devCheck := widget.NewCheck("Exclude dev dependencies", func(b bool) {
+		rt.mu.Lock()
+		rt.state.GUI.ExcludeDevDependencies = b
+		rt.mu.Unlock()
+		saveState(rt)
+		status.SetText("Refresh the report to apply the dev dependency setting.")
+	})
+	devCheck.Checked = excludeDev
+
+	// copyMenu offers copying the right-clicked cell, its row, or every
+	// filtered row (all pages) to the clipboard, and on package headers the
+	// package's detail dialog
+	copyMenu := func(c *tableCell, pos fyne.Position) {
+		rt.mu.RLock()
+		rpt, view := rt.currentReport, rt.depView
+		offset := view.PageOffset(rt.depPage, rt.state.GUI.DependencyPageSize)
+		rt.mu.RUnlock()
+		cnv := fyne.CurrentApp().Driver().CanvasForObject(c)
+		if rpt == nil || cnv == nil {
+			return
+		}
+		// The cell may render another position once scrolled
+		text := c.Text
+		clip := fyne.CurrentApp().Clipboard()
+		copyText := func(text, what string) {
+			clip.SetContent(text)
+			status.SetText("Copied " + what + " to clipboard.")
+		}
+		row := view.Header()
+		if c.cell.Row > 0 && offset+c.cell.Row-1 < len(view.Rows) {
+			row = view.Record(rpt, offset+c.cell.Row-1)
+		}
+		menu := fyne.NewMenu("",
+			fyne.NewMenuItem("Copy Cell", func() { copyText(text, "cell") }),
+			fyne.NewMenuItem("Copy Row", func() {
+				copyText(statepkg.FormatRecords([][]string{row}, statepkg.TSVDelimiter), "row")
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Copy View as TSV", func() {
+				copyText(view.Format(rpt, statepkg.TSVDelimiter), fmt.Sprintf("%d rows as TSV", len(view.Rows)))
+			}),
+			fyne.NewMenuItem("Copy View as CSV", func() {
+				copyText(view.Format(rpt, statepkg.CSVDelimiter), fmt.Sprintf("%d rows as CSV", len(view.Rows)))
+			}),
+		)
+		if c.cell.Row == 0 && c.cell.Col > 0 && c.cell.Col-1 < len(view.Packages) {
+			// Header clicks sort, so the details live in the menu
+			pkg := view.Packages[c.cell.Col-1]
+			menu.Items = append([]*fyne.MenuItem{
+				fyne.NewMenuItem("Package Details...", func() { showPackageDetailDialog(rt, rpt, pkg, w, enqueueUI) }),
+				fyne.NewMenuItemSeparator(),
+			}, menu.Items...)
+		}
+		widget.ShowPopUpMenuAtPosition(menu, cnv, pos)
+	}
 
 	table = widget.NewTable(
 		func() (int, int) {
@@ -997,22 +2330,18 @@ func buildDependenciesView(rt *Runtime, w fyne.Window, enqueueUI func(func())) f
 			if rt.currentReport == nil {
 				return 1, 1
 			}
-			// header + repositories
-			rows := len(rt.currentReport.Repositories) + 1
-			tracked := rt.state.TrackedPackages
-			var cols int
-			if len(tracked) == 0 {
-				cols = len(rt.currentReport.Packages) + 1
-			} else {
-				cols = len(tracked) + 1
-			}
-			return rows, cols
+			// header + current page of filtered repositories
+			return len(dependencyPageRows(rt)) + 1, len(rt.depView.Packages) + 1
 		},
-		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func() fyne.CanvasObject { return newTableCell(copyMenu) },
 		func(cell widget.TableCellID, o fyne.CanvasObject) {
 			rt.mu.RLock()
 			defer rt.mu.RUnlock()
-			lbl := o.(*widget.Label)
+			lbl := o.(*tableCell)
+			lbl.cell = cell
+			lbl.heat = nil
+			lbl.Importance = widget.MediumImportance
+			lbl.TextStyle = fyne.TextStyle{}
 			if rt.currentReport == nil {
 				if cell.Row == 0 && cell.Col == 0 {
 					lbl.SetText("No data")
@@ -1022,50 +2351,87 @@ func buildDependenciesView(rt *Runtime, w fyne.Window, enqueueUI func(func())) f
 				return
 			}
 			rpt := rt.currentReport
-			tracked := rt.state.TrackedPackages
-			var packages []string
-			if len(tracked) == 0 {
-				packages = rpt.Packages
-			} else {
-				packages = tracked
-			}
+			packages := rt.depView.Packages
 
 			if cell.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				sortBy := rt.state.GUI.DependencySort
 				if cell.Col == 0 {
-					lbl.SetText("Repository")
+					lbl.SetText(sortHeaderText("Repository", statepkg.SortByRepository, sortBy))
+				} else if cell.Col-1 < len(packages) {
+					lbl.SetText(sortHeaderText(packages[cell.Col-1], packages[cell.Col-1], sortBy))
 				} else {
-					lbl.SetText(packages[cell.Col-1])
+					lbl.SetText("")
 				}
-				lbl.TextStyle = fyne.TextStyle{Bold: true}
 				return
 			}
 
-			repoIdx := cell.Row - 1
-			if repoIdx >= len(rpt.Repositories) {
+			rows := dependencyPageRows(rt)
+			if cell.Row-1 >= len(rows) || cell.Col-1 >= len(packages) {
 				lbl.SetText("")
 				return
 			}
-			repoReport := rpt.Repositories[repoIdx]
+			repoReport := &rpt.Repositories[rows[cell.Row-1]]
 			if cell.Col == 0 {
-				lbl.SetText(fmt.Sprintf("%s/%s@%s", repoReport.Owner, repoReport.Repository, repoReport.Ref))
+				group := ""
+				if groups := rt.depView.PageGroups(rt.depPage, rt.state.GUI.DependencyPageSize); groups != nil {
+					group = groups[cell.Row-1]
+				}
+				lbl.SetText(repositoryCellText(repoReport, group, rt.depView.Groups != nil))
 				return
 			}
 			pkgName := packages[cell.Col-1]
 			version := repoReport.Dependencies[pkgName]
 			if version == "" {
 				if repoReport.Error != nil {
-					lbl.SetText("ERR")
+					category := repoReport.ErrorCategory()
+					lbl.Importance = errorCategoryImportance(category)
+					lbl.SetText("ERR:" + string(category))
 				} else {
 					lbl.SetText("—")
 				}
 				return
 			}
-			lbl.SetText(version)
+			if rt.state.GUI.DependencyHeatmap && repoReport.Error == nil && !repoReport.Suppressed(pkgName) {
+				// The version stays readable from the copy menu and the
+				// provenance dialog; the cell shows only the distance
+				behind := rt.depView.Behind(pkgName, version)
+				lbl.heat = heatColor(behind)
+				text := ""
+				if behind > 0 {
+					text = strconv.Itoa(behind)
+				}
+				lbl.SetText(text)
+				return
+			}
+			if repoReport.Suppressed(pkgName) {
+				lbl.Importance = widget.LowImportance
+			} else if repoReport.Error == nil && rt.depView.IsOutdated(pkgName, version) {
+				lbl.Importance = widget.WarningImportance
+			}
+			lbl.SetText(versionCellText(repoReport, pkgName))
 		},
 	)
 
+	// Keep the header row and repository column visible while scrolling
+	table.StickyRowCount = 1
+	table.StickyColumnCount = 1
+
 	table.OnSelected = func(id widget.TableCellID) {
 		if id.Row == 0 {
+			// Header click cycles sorting on that column
+			rt.mu.Lock()
+			column := statepkg.SortByRepository
+			if id.Col > 0 && id.Col-1 < len(rt.depView.Packages) {
+				column = rt.depView.Packages[id.Col-1]
+			}
+			if rt.currentReport != nil {
+				rt.state.GUI.DependencySort.Toggle(column)
+			}
+			rt.mu.Unlock()
+			saveState(rt)
+			table.UnselectAll()
+			table.Refresh()
 			return
 		}
 		rt.mu.RLock()
@@ -1073,33 +2439,21 @@ func buildDependenciesView(rt *Runtime, w fyne.Window, enqueueUI func(func())) f
 		if rt.currentReport == nil {
 			return
 		}
-		repoIdx := id.Row - 1
-		if repoIdx >= len(rt.currentReport.Repositories) {
+		rows := dependencyPageRows(rt)
+		if id.Row-1 >= len(rows) {
 			return
 		}
-		showRepoDetailsModal(rt.currentReport.Repositories[repoIdx], w)
+		repoReport := rt.currentReport.Repositories[rows[id.Row-1]]
+		if id.Col > 0 && id.Col-1 < len(rt.depView.Packages) && repoReport.Error == nil {
+			showCellProvenance(rt, repoReport, rt.depView.Packages[id.Col-1], w, enqueueUI)
+			return
+		}
+		showRepoDetailsModal(rt, repoReport, w, enqueueUI)
 	}
 
 	// Set initial column widths
 	rt.mu.RLock()
-	if rt.currentReport != nil {
-		// Calculate and set repository column width based on content
-		repoColWidth := calculateRepoColumnWidth(rt.currentReport)
-		table.SetColumnWidth(0, repoColWidth)
-
-		// Set package column widths dynamically based on content
-		tracked := rt.state.TrackedPackages
-		var packages []string
-		if len(tracked) == 0 {
-			packages = rt.currentReport.Packages
-		} else {
-			packages = tracked
-		}
-		for i, pkgName := range packages {
-			colWidth := calculatePackageColumnWidth(rt.currentReport, pkgName)
-			table.SetColumnWidth(i+1, colWidth)
-		}
-	} else {
+	if rt.currentReport == nil {
 		// No report yet, use default widths
 		table.SetColumnWidth(0, 300)
 		for i := 1; i < 20; i++ {
@@ -1107,6 +2461,32 @@ func buildDependenciesView(rt *Runtime, w fyne.Window, enqueueUI func(func())) f
 		}
 	}
 	rt.mu.RUnlock()
+	applyDependencyColumnWidths(rt, table)
+
+	pager = newDependencyPager(rt, table)
+	filterBar := buildDependencyFilterBar(rt, table, pager)
+
+	legend := heatmapLegend()
+	rt.mu.RLock()
+	heatmap := rt.state.GUI.DependencyHeatmap
+	rt.mu.RUnlock()
+	heatmapCheck := widget.NewCheck("Heatmap", func(b bool) {
+		rt.mu.Lock()
+		rt.state.GUI.DependencyHeatmap = b
+		rt.mu.Unlock()
+		saveState(rt)
+		if b {
+			legend.Show()
+		} else {
+			legend.Hide()
+		}
+		applyDependencyColumnWidths(rt, table)
+		table.Refresh()
+	})
+	heatmapCheck.Checked = heatmap
+	if !heatmap {
+		legend.Hide()
+	}
 
 	// Set initial content (table if report exists, empty if not)
 	rt.mu.RLock()
@@ -1119,65 +2499,451 @@ func buildDependenciesView(rt *Runtime, w fyne.Window, enqueueUI func(func())) f
 		container.NewVBox(
 			widget.NewLabelWithStyle("Dependencies Report", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 			widget.NewSeparator(),
-			container.NewHBox(refreshBtn, exportBtn),
+			container.NewHBox(refreshBtn, exportBtn, compareBtn, devCheck, heatmapCheck),
+			filterBar,
+			legend,
 			status,
 		),
-		nil, nil, nil,
+		pager.bar, nil, nil,
 		contentContainer,
 	)
 }
 
-func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Label, table *widget.Table, contentContainer *fyne.Container) {
-	rt.mu.Lock()
-	if rt.reportRunning {
-		rt.mu.Unlock()
-		if statusLabel != nil {
-			statusLabel.SetText("Report already running...")
-		}
-		return
+// showRefComparisonDialog compares the tracked package versions of each
+// repository the current report analyzed at several refs. Packages whose
+// versions differ between the refs are highlighted.
+func showRefComparisonDialog(rt *Runtime, w fyne.Window) {
+	rt.mu.RLock()
+	var comparisons []report.RefComparison
+	if rt.currentReport != nil {
+		comparisons = rt.currentReport.RefComparisons()
 	}
-	rt.reportRunning = true
-	rt.progressEvents = []services.ReportProgress{}
-	rt.progressIndex = map[string]services.ReportProgress{}
-	repos := make([]config.RepoWithProvider, 0, len(rt.state.RepositoriesCache))
-	for _, rc := range rt.state.RepositoriesCache {
-		repos = append(repos, config.RepoWithProvider{
-			Provider: rc.Provider,
-			Config: config.RepoConfig{
-				Token:      rc.Token,
-				Owner:      rc.Owner,
-				Repository: rc.Repository,
-				Ref:        rc.Ref,
-				Paths:      rc.Paths,
-				Packages:   rc.Packages,
-				Analyzer:   rc.Analyzer,
-			},
-		})
+	rt.mu.RUnlock()
+	if len(comparisons) == 0 {
+		dialog.ShowInformation("Compare Refs",
+			"No repository in the current report was analyzed at more than one ref.\n"+
+				"Add a repository again with another ref (e.g. a release branch) and refresh the report.", w)
+		return
 	}
-	rt.mu.Unlock()
 
-	if statusLabel != nil {
-		enqueueUI(func() {
-			statusLabel.SetText("Running report...")
-		})
+	names := make([]string, len(comparisons))
+	for i, c := range comparisons {
+		names[i] = fmt.Sprintf("%s/%s (%s)", c.Owner, c.Repository, strings.Join(c.Refs, " vs "))
 	}
-
-	// Resolve provider tokens before generating the report.
-	for idx := range repos {
-		rp := &repos[idx]
-		// If token already set in config, keep it; otherwise attempt resolution.
-		if rp.Config.Token == "" {
-			tok, terr := statepkg.ResolveProviderToken(rp.Provider, rt.state, rt.credentialStore)
-			if terr != nil {
-				// Record structured error (non-fatal for this repo, token may remain empty)
-				rt.mu.Lock()
-				rt.state.ErrorLog = append(rt.state.ErrorLog, statepkg.ErrorLogEntry{
-					Time:     time.Now().UTC(),
-					Source:   "token-resolve",
-					Severity: "error",
-					Message:  fmt.Sprintf("Failed to resolve token for %s:%s/%s", rp.Provider, rp.Config.Owner, rp.Config.Repository),
-					Details:  terr.Error(),
-				})
+	current := comparisons[0]
+	var rows []report.RefPackage
+	onlyDiffs := widget.NewCheck("Only differences", nil)
+	summary := widget.NewLabel("")
+	table := widget.NewTable(
+		func() (int, int) { return len(rows) + 1, len(current.Refs) + 1 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(cell widget.TableCellID, o fyne.CanvasObject) {
+			lbl := o.(*widget.Label)
+			lbl.Importance = widget.MediumImportance
+			lbl.TextStyle = fyne.TextStyle{}
+			if cell.Row == 0 {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				if cell.Col == 0 {
+					lbl.SetText("Package")
+				} else {
+					lbl.SetText(current.Refs[cell.Col-1])
+				}
+				return
+			}
+			p := rows[cell.Row-1]
+			if p.Differs {
+				lbl.Importance = widget.WarningImportance
+			}
+			if cell.Col == 0 {
+				lbl.SetText(p.Package)
+				return
+			}
+			version := p.Versions[current.Refs[cell.Col-1]]
+			if version == "" {
+				version = "-"
+			}
+			lbl.SetText(version)
+		},
+	)
+	table.SetColumnWidth(0, 220)
+	refresh := func() {
+		rows = current.Packages
+		if onlyDiffs.Checked {
+			rows = current.Differences()
+		}
+		for col := range current.Refs {
+			table.SetColumnWidth(col+1, 140)
+		}
+		summary.SetText(fmt.Sprintf("%d of %d packages differ between %s",
+			len(current.Differences()), len(current.Packages), strings.Join(current.Refs, " and ")))
+		table.Refresh()
+	}
+	onlyDiffs.OnChanged = func(bool) { refresh() }
+
+	repoSelect := widget.NewSelect(names, func(name string) {
+		if i := slices.Index(names, name); i >= 0 {
+			current = comparisons[i]
+			refresh()
+		}
+	})
+	repoSelect.SetSelectedIndex(0)
+	refresh()
+
+	top := container.NewVBox(container.NewBorder(nil, nil, nil, onlyDiffs, repoSelect), summary)
+	d := dialog.NewCustom("Compare Refs", "Close", container.NewBorder(top, nil, nil, nil, table), w)
+	d.Resize(fyne.NewSize(720, 480))
+	d.Show()
+}
+
+// dependencyPageRows returns the report indexes of the repositories on the
+// current dependencies table page. The caller must hold rt.mu.
+func dependencyPageRows(rt *Runtime) []int {
+	rows, _, _ := rt.depView.Page(rt.depPage, rt.state.GUI.DependencyPageSize)
+	return rows
+}
+
+// dependencyPager is the page navigation bar below the dependencies table
+type dependencyPager struct {
+	rt      *Runtime
+	table   *widget.Table
+	bar     *fyne.Container
+	label   *widget.Label
+	prevBtn *widget.Button
+	nextBtn *widget.Button
+}
+
+func newDependencyPager(rt *Runtime, table *widget.Table) *dependencyPager {
+	p := &dependencyPager{rt: rt, table: table, label: widget.NewLabel("")}
+	p.prevBtn = widget.NewButton("◀ Prev", func() { p.turn(-1) })
+	p.nextBtn = widget.NewButton("Next ▶", func() { p.turn(1) })
+	p.bar = container.NewHBox(p.prevBtn, p.label, p.nextBtn)
+	p.refresh()
+	return p
+}
+
+// turn moves delta pages and redraws the table
+func (p *dependencyPager) turn(delta int) {
+	p.rt.mu.Lock()
+	_, shown, _ := p.rt.depView.Page(p.rt.depPage, p.rt.state.GUI.DependencyPageSize)
+	p.rt.depPage = shown + delta
+	p.rt.mu.Unlock()
+	p.table.ScrollToTop()
+	p.table.Refresh()
+	p.refresh()
+}
+
+// refresh updates the page label and button states from the current view
+func (p *dependencyPager) refresh() {
+	p.rt.mu.Lock()
+	rows, shown, pages := p.rt.depView.Page(p.rt.depPage, p.rt.state.GUI.DependencyPageSize)
+	p.rt.depPage = shown
+	total := len(p.rt.depView.Rows)
+	p.rt.mu.Unlock()
+
+	p.label.SetText(fmt.Sprintf("Page %d of %d (%d of %d repositories)", shown+1, pages, len(rows), total))
+	if shown > 0 {
+		p.prevBtn.Enable()
+	} else {
+		p.prevBtn.Disable()
+	}
+	if shown < pages-1 {
+		p.nextBtn.Enable()
+	} else {
+		p.nextBtn.Disable()
+	}
+}
+
+// sortHeaderText renders a dependencies table header, marking the column the
+// rows are currently sorted by.
+func sortHeaderText(title, column string, sortBy statepkg.DependencySort) string {
+	switch {
+	case sortBy.Column != column:
+		return title
+	case sortBy.Descending:
+		return title + " ▼"
+	default:
+		return title + " ▲"
+	}
+}
+
+// buildDependencyFilterBar creates the search/filter toolbar for the
+// dependencies table. Changes are stored in state.GUI.DependencyFilter and
+// applied immediately.
+func buildDependencyFilterBar(rt *Runtime, table *widget.Table, pager *dependencyPager) fyne.CanvasObject {
+	rt.mu.RLock()
+	f := rt.state.GUI.DependencyFilter
+	rt.mu.RUnlock()
+
+	update := func(apply func(*statepkg.DependencyFilter)) {
+		rt.mu.Lock()
+		apply(&rt.state.GUI.DependencyFilter)
+		rt.depPage = 0
+		rt.mu.Unlock()
+		saveState(rt)
+		applyDependencyColumnWidths(rt, table)
+		table.Refresh()
+		pager.refresh()
+	}
+
+	pkgEntry := widget.NewEntry()
+	pkgEntry.SetPlaceHolder("Filter packages")
+	pkgEntry.SetText(f.Package)
+	pkgEntry.OnChanged = func(s string) {
+		update(func(f *statepkg.DependencyFilter) { f.Package = s })
+	}
+
+	repoEntry := widget.NewEntry()
+	repoEntry.SetPlaceHolder("Filter repositories")
+	repoEntry.SetText(f.Repository)
+	repoEntry.OnChanged = func(s string) {
+		update(func(f *statepkg.DependencyFilter) { f.Repository = s })
+	}
+
+	mismatchedCheck := widget.NewCheck("Only mismatched versions", func(b bool) {
+		update(func(f *statepkg.DependencyFilter) { f.OnlyMismatched = b })
+	})
+	mismatchedCheck.Checked = f.OnlyMismatched
+
+	errorsCheck := widget.NewCheck("Only errors", func(b bool) {
+		update(func(f *statepkg.DependencyFilter) { f.OnlyErrors = b })
+	})
+	errorsCheck.Checked = f.OnlyErrors
+
+	// Tag suggestions come from the configured repositories; any typed tag
+	// is accepted so reports from older configs can still be filtered
+	rt.mu.RLock()
+	tagEntry := widget.NewSelectEntry(rt.state.RepositoryTags())
+	groupByTag := rt.state.GUI.DependencyGroupByTag
+	rt.mu.RUnlock()
+	tagEntry.SetPlaceHolder("Filter tag")
+	tagEntry.SetText(f.Tag)
+	tagEntry.OnChanged = func(s string) {
+		update(func(f *statepkg.DependencyFilter) { f.Tag = s })
+	}
+
+	// Ecosystems come from the registered analyzers, so the choice exists
+	// before the first report
+	const allEcosystems = "All ecosystems"
+	ecosystems := []string{allEcosystems}
+	for _, a := range dependencies.SupportedAnalyzers() {
+		if label := report.EcosystemLabel(dependencies.EcosystemForAnalyzer(a)); !slices.Contains(ecosystems, label) {
+			ecosystems = append(ecosystems, label)
+		}
+	}
+	slices.Sort(ecosystems[1:])
+	ecosystemSelect := widget.NewSelect(ecosystems, func(s string) {
+		if s == allEcosystems {
+			s = ""
+		}
+		update(func(f *statepkg.DependencyFilter) { f.Ecosystem = s })
+	})
+	ecosystemSelect.Selected = cmp.Or(f.Ecosystem, allEcosystems)
+
+	groupCheck := widget.NewCheck("Group by tag", func(b bool) {
+		rt.mu.Lock()
+		rt.state.GUI.DependencyGroupByTag = b
+		rt.mu.Unlock()
+		update(func(*statepkg.DependencyFilter) {})
+	})
+	groupCheck.Checked = groupByTag
+
+	clearBtn := widget.NewButton("Clear Filters", func() {
+		pkgEntry.SetText("")
+		repoEntry.SetText("")
+		tagEntry.SetText("")
+		ecosystemSelect.SetSelected(allEcosystems)
+		mismatchedCheck.SetChecked(false)
+		errorsCheck.SetChecked(false)
+	})
+
+	return container.NewBorder(nil, nil, nil,
+		container.NewHBox(mismatchedCheck, errorsCheck, groupCheck, clearBtn),
+		container.NewGridWithColumns(4, pkgEntry, repoEntry, tagEntry, ecosystemSelect),
+	)
+}
+
+// refreshDependencyView recomputes the filtered dependencies table rows and
+// columns from the current report, visible packages and filter.
+func refreshDependencyView(rt *Runtime) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.depView = rt.state.FilterDependencyTable(rt.currentReport)
+}
+
+// applyDependencyColumnWidths sizes the dependencies table columns to the
+// content of the current filtered view.
+func applyDependencyColumnWidths(rt *Runtime, table *widget.Table) {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	if rt.currentReport == nil {
+		return
+	}
+	table.SetColumnWidth(0, calculateRepoColumnWidth(rt.currentReport))
+	for i, pkgName := range rt.depView.Packages {
+		width := calculatePackageColumnWidth(rt.currentReport, pkgName)
+		if rt.state.GUI.DependencyHeatmap {
+			// Heatmap cells hold at most a small count; keep many packages
+			// on screen at the cost of truncated headers
+			width = min(width, heatmapColumnWidth)
+		}
+		table.SetColumnWidth(i+1, width)
+	}
+}
+
+// heatmapColumnWidth caps package column widths in heatmap mode
+const heatmapColumnWidth = 72
+
+// heatmapColors color heatmap cells by the number of newer versions found,
+// the last one for anything further behind
+var heatmapColors = []color.Color{
+	color.NRGBA{R: 0x2e, G: 0x9e, B: 0x4f, A: 0xb0}, // newest
+	color.NRGBA{R: 0x9c, G: 0xc4, B: 0x3a, A: 0xb0},
+	color.NRGBA{R: 0xf2, G: 0xc1, B: 0x28, A: 0xb0},
+	color.NRGBA{R: 0xf2, G: 0x8c, B: 0x28, A: 0xb0},
+	color.NRGBA{R: 0xd9, G: 0x3f, B: 0x3f, A: 0xb0},
+}
+
+// heatColor returns the heatmap color of a cell behind versions behind the
+// newest one, or nil when the version could not be placed
+func heatColor(behind int) color.Color {
+	if behind < 0 {
+		return nil
+	}
+	return heatmapColors[min(behind, len(heatmapColors)-1)]
+}
+
+// heatmapLegend explains the heatmap colors
+func heatmapLegend() *fyne.Container {
+	legend := container.NewHBox(widget.NewLabel("Versions behind newest:"))
+	for i, c := range heatmapColors {
+		swatch := canvas.NewRectangle(c)
+		swatch.SetMinSize(fyne.NewSize(16, 16))
+		text := strconv.Itoa(i)
+		if i == len(heatmapColors)-1 {
+			text += "+"
+		}
+		legend.Add(container.NewHBox(container.NewCenter(swatch), widget.NewLabel(text)))
+	}
+	return legend
+}
+
+// runReportAsync generates a dependency report in the background. The optional
+// widgets are updated as the report progresses; onComplete, if set, runs on
+// the UI thread after a successful report has been applied to the table (or
+// as soon as it completes when no table is given).
+func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Label, table *widget.Table, contentContainer *fyne.Container, onComplete func()) {
+	rt.mu.Lock()
+	background := rt.backgroundRun
+	rt.backgroundRun = false
+	if rt.reportRunning {
+		rt.mu.Unlock()
+		if statusLabel != nil {
+			statusLabel.SetText("Report already running...")
+		}
+		return
+	}
+	rt.reportRunning = true
+	started := time.Now()
+	runID := started.UTC().Format(time.RFC3339)
+	rt.progressEvents = []services.ReportProgress{}
+	rt.progressIndex = map[string]services.ReportProgress{}
+	repos := make([]config.RepoWithProvider, 0, len(rt.state.RepositoriesCache))
+	for _, rc := range rt.state.RepositoriesCache {
+		repos = append(repos, config.RepoWithProvider{
+			Provider: rc.Provider,
+			Config: config.RepoConfig{
+				Token:              rc.Token,
+				BaseURL:            rc.BaseURL,
+				Enterprise:         rc.Enterprise,
+				APIPrefix:          rc.APIPrefix,
+				Owner:              rc.Owner,
+				Repository:         rc.Repository,
+				Ref:                rc.Ref,
+				Paths:              rc.Paths,
+				Packages:           rc.Packages,
+				Analyzer:           rc.Analyzer,
+				UpdatePRs:          rc.UpdatePRs,
+				Constraints:        rc.Constraints,
+				Tags:               rc.Tags,
+				Proxy:              rc.Proxy,
+				CAFile:             rc.CAFile,
+				InsecureSkipVerify: rc.InsecureSkipVerify,
+				ExcludePaths:       rc.ExcludePaths,
+				Canonical:          rc.Canonical,
+			},
+		})
+	}
+	policyCfgs := slices.Clone(rt.state.Policies)
+	ignoreCfgs := slices.Clone(rt.state.Ignores)
+	timeouts := rt.state.GUI.Timeouts
+	runTimeout := rt.state.GUI.ReportTimeout()
+	workers := rt.state.GUI.Concurrency.MaxWorkers
+	excludeDev := rt.state.GUI.ExcludeDevDependencies
+	var tracer *repository.HTTPTracer
+	if rt.state.GUI.Logging.TraceHTTP {
+		if rt.httpTracer == nil {
+			rt.httpTracer = &repository.HTTPTracer{Logger: slog.Default(), MaxEntries: httpTraceEntries}
+		}
+		tracer = rt.httpTracer
+	}
+	rt.mu.Unlock()
+
+	// An invalid policy is reported and skipped rather than blocking the run
+	policies, err := report.PoliciesFromConfig(policyCfgs)
+	if err != nil {
+		slog.Error("Ignoring policies", "error", err)
+		rt.mu.Lock()
+		rt.state.AppendError(statepkg.ErrorLogEntry{
+			Time:     time.Now().UTC(),
+			Source:   "policy",
+			Severity: "error",
+			Message:  "Invalid policy configuration; policies were not checked",
+			Details:  err.Error(),
+			RunID:    runID,
+		})
+		rt.mu.Unlock()
+		policies = nil
+	}
+	ignores, err := report.IgnoreRulesFromConfig(ignoreCfgs)
+	if err != nil {
+		slog.Error("Ignoring ignore rules", "error", err)
+		rt.mu.Lock()
+		rt.state.AppendError(statepkg.ErrorLogEntry{
+			Time:     time.Now().UTC(),
+			Source:   "policy",
+			Severity: "error",
+			Message:  "Invalid ignores configuration; no findings were ignored",
+			Details:  err.Error(),
+			RunID:    runID,
+		})
+		rt.mu.Unlock()
+		ignores = nil
+	}
+
+	if statusLabel != nil {
+		enqueueUI(func() {
+			statusLabel.SetText("Running report...")
+		})
+	}
+
+	// Resolve provider tokens before generating the report.
+	for idx := range repos {
+		rp := &repos[idx]
+		// If token already set in config, keep it; otherwise attempt resolution.
+		if rp.Config.Token == "" {
+			tok, terr := statepkg.ResolveProviderToken(rp.Provider, rt.state, rt.credentialStore)
+			if terr != nil {
+				// Record structured error (non-fatal for this repo, token may remain empty)
+				rt.mu.Lock()
+				rt.state.AppendError(statepkg.ErrorLogEntry{
+					Time:       time.Now().UTC(),
+					Source:     "token-resolve",
+					Severity:   "error",
+					Message:    fmt.Sprintf("Failed to resolve token for %s:%s/%s", rp.Provider, rp.Config.Owner, rp.Config.Repository),
+					Details:    terr.Error(),
+					RunID:      runID,
+					Repository: fmt.Sprintf("%s:%s/%s@%s", rp.Provider, rp.Config.Owner, rp.Config.Repository, rp.Config.Ref),
+				})
 				rt.mu.Unlock()
 			} else if tok != "" {
 				rp.Config.Token = tok
@@ -1195,11 +2961,28 @@ func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Lab
 		}
 	}
 
+	// Repositories whose commit did not move since the last report are reused
+	var previous *report.Snapshot
+	rt.mu.RLock()
+	if rt.currentReport != nil {
+		previous = rt.currentReport.Snapshot()
+	}
+	rt.mu.RUnlock()
+
 	slog.Info("Starting dependency report", "repos", len(repos))
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
 
 	progressCh, handle, err := rt.depSvc.RunReport(ctx, repos, services.ReportOptions{
 		EmitAggregateEvents: true,
+		Previous:            previous,
+		IncludeGraph:        true,
+		Policies:            policies,
+		IgnoreRules:         ignores,
+		HTTPTracer:          tracer,
+		RepositoryTimeout:   timeouts.Repository,
+		RequestTimeout:      timeouts.Request,
+		Concurrency:         workers,
+		ExcludeDev:          excludeDev,
 	})
 	if err != nil {
 		cancel()
@@ -1225,17 +3008,38 @@ func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Lab
 		return
 	}
 
-	// Progress collector
+	// Progress collector. Only the status line reflects progress (the table is
+	// replaced by a spinner while running), so update it at most every 200ms
+	// rather than refreshing whole canvases on every event.
 	go func() {
+		var done int
+		var lastUpdate time.Time
 		for p := range progressCh {
 			rt.mu.Lock()
 			rt.progressEvents = append(rt.progressEvents, p)
 			rt.progressIndex[p.RepoID] = p
+			if p.RepoID != "" && (p.Phase == services.PhaseComplete || p.Phase == services.PhaseError) {
+				done++
+			}
+			if p.Phase == services.PhaseRetry {
+				// Record transient provider failures being retried
+				rt.state.AppendError(statepkg.ErrorLogEntry{
+					Time:       p.Timestamp.UTC(),
+					Source:     "retry",
+					Severity:   "warn",
+					Message:    fmt.Sprintf("Retrying request for %s (attempt %d)", p.RepoID, p.Attempt),
+					Details:    p.Error.Error(),
+					RunID:      runID,
+					Repository: p.RepoID,
+				})
+			}
 			rt.mu.Unlock()
-			if drv := fyne.CurrentApp().Driver(); drv != nil {
-				for _, w := range fyne.CurrentApp().Driver().AllWindows() {
-					w.Canvas().Refresh(w.Content())
-				}
+			if statusLabel != nil && time.Since(lastUpdate) >= 200*time.Millisecond {
+				lastUpdate = time.Now()
+				text := fmt.Sprintf("Running report... (%d/%d repositories)", done, len(repos))
+				enqueueUI(func() {
+					statusLabel.SetText(text)
+				})
 			}
 		}
 	}()
@@ -1245,8 +3049,18 @@ func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Lab
 		defer cancel()
 		rpt, rErr := handle.Result()
 		rt.mu.Lock()
+		prevReport := rt.currentReport
 		rt.currentReport = rpt
+		notifyProblems := rt.state.GUI.Tray.Enabled && rt.state.GUI.Tray.Notify
+		historyDir, historyErr := rt.historyDirLocked()
 		rt.reportRunning = false
+		rt.lastRunID = runID
+		rt.state.RecordReportErrors(runID, rpt)
+		var alerts []statepkg.Alert
+		if background && rErr == nil {
+			fired := statepkg.EvaluateAlertRules(rt.state.AlertRules, prevReport, rpt)
+			alerts = rt.state.RecordAlerts(runID, time.Now().UTC(), fired)
+		}
 		if rErr != nil {
 			// Append aggregated error event entry if not already captured
 			rt.progressEvents = append(rt.progressEvents, services.ReportProgress{
@@ -1255,12 +3069,21 @@ func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Lab
 				Error:     rErr,
 				Timestamp: time.Now(),
 			})
+			rt.state.AppendError(statepkg.ErrorLogEntry{
+				Time:     time.Now().UTC(),
+				Source:   "report",
+				Severity: "error",
+				Message:  "Report failed",
+				Details:  rErr.Error(),
+				RunID:    runID,
+			})
 		} else {
 			// Update last report meta
 			rt.state.GUI.LastReport = &statepkg.LastReportMeta{
 				GeneratedAt:  time.Now().UTC(),
 				RepoCount:    len(rpt.Repositories),
 				PackageCount: len(rpt.Packages),
+				Duration:     time.Since(started).Round(time.Millisecond),
 			}
 		}
 		rt.mu.Unlock()
@@ -1282,35 +3105,37 @@ func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Lab
 				})
 			}
 			slog.Info("Report complete", "repos", len(rpt.Repositories), "packages", len(rpt.Packages))
+			// Archive the snapshot for the History view's trends
+			if historyErr == nil {
+				if err := report.ArchiveSnapshot(historyDir, rpt.Snapshot(), 0); err != nil {
+					slog.Warn("Failed to archive snapshot", "path", historyDir, "error", err)
+				}
+			}
+			if notifyProblems && prevReport != nil {
+				if problems := report.NewProblems(prevReport, rpt); !problems.Empty() {
+					fyne.CurrentApp().SendNotification(problemsNotification(problems))
+				}
+			}
+			if len(alerts) > 0 {
+				slog.Info("Alert rules fired", "alerts", len(alerts))
+				fyne.CurrentApp().SendNotification(alertsNotification(alerts))
+			}
 
 			// Update table column widths based on new report data and switch from spinner to table
 			if table != nil && rpt != nil && contentContainer != nil {
 				enqueueUI(func() {
-					// Calculate and set repository column width based on content
-					repoColWidth := calculateRepoColumnWidth(rpt)
-					table.SetColumnWidth(0, repoColWidth)
-
-					// Update package column widths dynamically based on content
-					rt.mu.RLock()
-					tracked := rt.state.TrackedPackages
-					var packages []string
-					if len(tracked) == 0 {
-						packages = rpt.Packages
-					} else {
-						packages = tracked
-					}
-					rt.mu.RUnlock()
-
-					for i, pkgName := range packages {
-						colWidth := calculatePackageColumnWidth(rpt, pkgName)
-						table.SetColumnWidth(i+1, colWidth)
-					}
+					applyDependencyColumnWidths(rt, table)
 					table.Refresh()
 
 					// Switch from spinner to table
 					contentContainer.Objects = []fyne.CanvasObject{table}
 					contentContainer.Refresh()
+					if onComplete != nil {
+						onComplete()
+					}
 				})
+			} else if onComplete != nil {
+				enqueueUI(onComplete)
 			}
 		}
 		// If report failed, hide spinner and show error message
@@ -1345,101 +3170,1917 @@ func runReportAsync(rt *Runtime, enqueueUI func(func()), statusLabel *widget.Lab
 	}()
 }
 
-// ----- Repo Detail Modal -----
+// ----- Onboarding Wizard -----
 
-func showRepoDetailsModal(repo report.RepositoryReport, w fyne.Window) {
-	content := container.NewVBox(
-		widget.NewLabelWithStyle(fmt.Sprintf("Repository: %s/%s@%s",
-			repo.Owner, repo.Repository, repo.Ref),
-			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-		widget.NewSeparator(),
-	)
-	if repo.Error != nil {
-		content.Add(widget.NewLabel(fmt.Sprintf("Error: %v", repo.Error)))
-	}
-	content.Add(widget.NewLabel("Dependencies:"))
-	for pkg, ver := range repo.Dependencies {
-		content.Add(widget.NewLabel(fmt.Sprintf("  %s: %s", pkg, ver)))
+// onboardingDiscoveryLimit caps the repositories listed by the wizard's
+// Discover button
+const onboardingDiscoveryLimit = 500
+
+// showOnboardingWizard guides a new user through the first setup: provider,
+// token (validated against the provider), repositories (discovered or typed),
+// analyzer and tracked packages. Finishing writes the answers into the state
+// (see GUIState.ApplyOnboarding) and calls done with whether the first report
+// should run; dismissing it on the first step records that it was skipped.
+func showOnboardingWizard(rt *Runtime, w fyne.Window, enqueueUI func(func()), done func(runReport bool)) {
+	rt.mu.RLock()
+	var savedTokens statepkg.CredentialSnapshot
+	if rt.state.Credentials != nil {
+		savedTokens = *rt.state.Credentials
 	}
-	dialog.ShowCustom("Repository Details", "Close", container.NewVScroll(content), w)
-}
+	rt.mu.RUnlock()
 
-// ----- Logs View -----
+	// Step 1: provider
+	providerSelect := widget.NewSelect([]string{"github", "gitlab"}, nil)
+	baseURLEntry := widget.NewEntry()
+	baseURLEntry.SetPlaceHolder("Base URL (optional, e.g. https://gitlab.example.com)")
 
-func buildLogsView(rt *Runtime, _ fyne.App, _ fyne.Window, logHandler *RingLogHandler) fyne.CanvasObject {
-	// Filtering controls
-	searchEntry := widget.NewEntry()
-	searchEntry.SetPlaceHolder("Filter text (substring)")
+	// Step 2: token
+	tokenEntry := widget.NewPasswordEntry()
+	tokenEntry.SetPlaceHolder("Personal access token")
+	tokenStatus := widget.NewLabel("")
+	tokenStatus.Wrapping = fyne.TextWrapWord
 
-	// Forward declarations so callbacks can reference logList after assignment.
-	var logList *widget.List
-	errorOnlyToggle := widget.NewCheck("Show only errors", func(bool) {
-		if logList != nil {
-			logList.Refresh()
+	// Step 3: repositories
+	ownerEntry := widget.NewEntry()
+	ownerEntry.SetPlaceHolder("Organization, group or user (empty: all accessible)")
+	discovered := widget.NewCheckGroup(nil, nil)
+	discoverStatus := widget.NewLabel("")
+	manualEntry := widget.NewMultiLineEntry()
+	manualEntry.SetPlaceHolder("Additional repositories, one per line (owner/name)")
+
+	// Step 4: packages
+	analyzerSelect := widget.NewSelect(dependencies.SupportedAnalyzers(), nil)
+	analyzerSelect.SetSelected(statepkg.DefaultImportAnalyzer)
+	packagesEntry := widget.NewMultiLineEntry()
+	packagesEntry.SetPlaceHolder("Packages to track, one per line (empty: all)")
+	runCheck := widget.NewCheck("Run the first report now", nil)
+	runCheck.SetChecked(true)
+
+	providerSelect.OnChanged = func(p string) {
+		token := savedTokens.GitHubToken
+		if p == "gitlab" {
+			token = savedTokens.GitLabToken
 		}
-	})
+		tokenEntry.SetText(token)
+		tokenStatus.SetText("")
+		discovered.Options = nil
+		discovered.Selected = nil
+		discovered.Refresh()
+		discoverStatus.SetText("")
+	}
+	providerSelect.SetSelected("github")
 
-	structuredErrorsToggle := widget.NewCheck("Show structured errors", func(bool) {
-		if logList != nil {
-			logList.Refresh()
+	// discoverer builds a client from the entered provider, base URL and
+	// token, using the provider's saved network settings
+	discoverer := func() (repository.RepositoryDiscoverer, error) {
+		provider := providerSelect.Selected
+		rt.mu.RLock()
+		defaults := rt.state.Providers[provider].Default
+		requestTimeout := rt.state.GUI.Timeouts.Request
+		rt.mu.RUnlock()
+		client, err := repository.NewClient(provider, repository.Config{
+			Token:              strings.TrimSpace(tokenEntry.Text),
+			BaseURL:            strings.TrimSpace(baseURLEntry.Text),
+			Proxy:              defaults.Proxy,
+			CAFile:             defaults.CAFile,
+			InsecureSkipVerify: defaults.InsecureSkipVerify,
+			RequestTimeout:     requestTimeout,
+		})
+		if err != nil {
+			return nil, err
 		}
-	})
-
-	levelSelect := widget.NewSelect([]string{"ALL", "DEBUG", "INFO", "WARN", "ERROR"}, func(string) {
-		if logList != nil {
-			logList.Refresh()
+		d, ok := client.(repository.RepositoryDiscoverer)
+		if !ok {
+			return nil, fmt.Errorf("%s does not support repository discovery", providerDisplayName(provider))
 		}
-	})
-	levelSelect.SetSelected("ALL")
-
-	// List with dynamic filtering (assigned after control declarations)
-	logList = widget.NewList(
-		func() int {
-			entries := filteredLogs(logHandler, searchEntry.Text, levelSelect.Selected, errorOnlyToggle.Checked, structuredErrorsToggle.Checked, rt)
-			return len(entries)
-		},
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			entries := filteredLogs(logHandler, searchEntry.Text, levelSelect.Selected, errorOnlyToggle.Checked, structuredErrorsToggle.Checked, rt)
-			if i < len(entries) {
-				e := entries[i]
-				o.(*widget.Label).SetText(fmt.Sprintf("%s [%s] %s",
-					e.Time.Format(time.RFC3339), e.Level.String(), e.Message))
-			} else {
-				o.(*widget.Label).SetText("")
-			}
-		},
-	)
+		return d, nil
+	}
 
-	refreshBtn := widget.NewButton("Refresh", func() {
-		if logList != nil {
-			logList.Refresh()
-		}
-	})
-	clearBtn := widget.NewButton("Clear", func() {
-		logHandler.mu.Lock()
-		logHandler.logs = []LogEntry{}
-		logHandler.mu.Unlock()
-		if logList != nil {
-			logList.Refresh()
+	validateBtn := widget.NewButtonWithIcon("Validate", theme.ConfirmIcon(), nil)
+	validateBtn.OnTapped = func() {
+		d, err := discoverer()
+		if err != nil {
+			tokenStatus.SetText(err.Error())
+			return
 		}
-	})
+		validateBtn.Disable()
+		tokenStatus.SetText("Checking token...")
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			login, err := d.CurrentUser(ctx)
+			enqueueUI(func() {
+				validateBtn.Enable()
+				if err != nil {
+					tokenStatus.SetText(fmt.Sprintf("Token rejected: %v", err))
+					return
+				}
+				tokenStatus.SetText(fmt.Sprintf("Authenticated as %s", login))
+				if ownerEntry.Text == "" {
+					ownerEntry.SetText(login)
+				}
+				slog.Info("Onboarding token validated", "provider", providerSelect.Selected, "user", login)
+			})
+		}()
+	}
 
-	controls := container.NewVBox(
+	discoverBtn := widget.NewButtonWithIcon("Discover", theme.SearchIcon(), nil)
+	discoverBtn.OnTapped = func() {
+		d, err := discoverer()
+		if err != nil {
+			discoverStatus.SetText(err.Error())
+			return
+		}
+		owner := strings.TrimSpace(ownerEntry.Text)
+		discoverBtn.Disable()
+		discoverStatus.SetText("Listing repositories...")
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			repos, err := d.ListRepositories(ctx, owner, onboardingDiscoveryLimit)
+			enqueueUI(func() {
+				discoverBtn.Enable()
+				if err != nil {
+					discoverStatus.SetText(fmt.Sprintf("Discovery failed: %v", err))
+					return
+				}
+				options := make([]string, 0, len(repos))
+				for _, r := range repos {
+					if !r.Archived {
+						options = append(options, r.Owner+"/"+r.Name)
+					}
+				}
+				discovered.Options = options
+				discovered.Selected = nil
+				discovered.Refresh()
+				text := fmt.Sprintf("Found %d repositories (archived ones hidden)", len(options))
+				if len(repos) == onboardingDiscoveryLimit {
+					text += fmt.Sprintf("; showing the first %d", onboardingDiscoveryLimit)
+				}
+				discoverStatus.SetText(text)
+			})
+		}()
+	}
+	selectAllBtn := widget.NewButton("Select All", func() { discovered.SetSelected(discovered.Options) })
+
+	answers := func() statepkg.Onboarding {
+		return statepkg.Onboarding{
+			Provider:     providerSelect.Selected,
+			BaseURL:      baseURLEntry.Text,
+			Token:        tokenEntry.Text,
+			Owner:        ownerEntry.Text,
+			Repositories: append(slices.Clone(discovered.Selected), filterNonEmptyLines(manualEntry.Text)...),
+			Analyzer:     analyzerSelect.Selected,
+			Packages:     filterNonEmptyLines(packagesEntry.Text),
+		}
+	}
+
+	intro := widget.NewLabel("Choose where your repositories are hosted. Leave the base URL empty for github.com or gitlab.com.")
+	intro.Wrapping = fyne.TextWrapWord
+	tokenHelp := widget.NewLabel("The token needs read access to repository contents. It is stored in the state file (see the Providers view).")
+	tokenHelp.Wrapping = fyne.TextWrapWord
+	steps := []struct {
+		title   string
+		content fyne.CanvasObject
+	}{
+		{"Provider", container.NewVBox(intro, widget.NewForm(
+			widget.NewFormItem("Provider", providerSelect),
+			widget.NewFormItem("Base URL", baseURLEntry)))},
+		{"Token", container.NewVBox(tokenHelp, tokenEntry,
+			container.NewHBox(validateBtn), tokenStatus)},
+		{"Repositories", container.NewBorder(
+			container.NewVBox(
+				container.NewBorder(nil, nil, nil, container.NewHBox(discoverBtn, selectAllBtn), ownerEntry),
+				discoverStatus),
+			manualEntry, nil, nil,
+			container.NewVScroll(discovered))},
+		{"Packages", widget.NewForm(
+			widget.NewFormItem("Analyzer", analyzerSelect),
+			widget.NewFormItem("Packages", packagesEntry),
+			widget.NewFormItem("", runCheck))},
+	}
+
+	step := 0
+	heading := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	body := container.NewStack()
+	backBtn := widget.NewButtonWithIcon("Back", theme.NavigateBackIcon(), nil)
+	nextBtn := widget.NewButtonWithIcon("Next", theme.NavigateNextIcon(), nil)
+	nextBtn.Importance = widget.HighImportance
+	skipBtn := widget.NewButton("Skip Setup", nil)
+
+	d := dialog.NewCustomWithoutButtons("Welcome to DevDashboard",
+		container.NewBorder(heading, nil, nil, nil, body), w)
+	d.SetButtons([]fyne.CanvasObject{skipBtn, backBtn, nextBtn})
+
+	showStep := func() {
+		heading.SetText(fmt.Sprintf("Step %d of %d: %s", step+1, len(steps), steps[step].title))
+		body.Objects = []fyne.CanvasObject{steps[step].content}
+		body.Refresh()
+		if step == 0 {
+			backBtn.Disable()
+		} else {
+			backBtn.Enable()
+		}
+		if step == len(steps)-1 {
+			nextBtn.SetText("Finish")
+			nextBtn.SetIcon(theme.ConfirmIcon())
+		} else {
+			nextBtn.SetText("Next")
+			nextBtn.SetIcon(theme.NavigateNextIcon())
+		}
+	}
+	backBtn.OnTapped = func() {
+		step--
+		showStep()
+	}
+	nextBtn.OnTapped = func() {
+		if step == 2 && len(answers().Repositories) == 0 {
+			dialog.ShowError(errors.New("select or enter at least one repository"), w)
+			return
+		}
+		if step < len(steps)-1 {
+			step++
+			showStep()
+			return
+		}
+		rt.mu.Lock()
+		plan, err := rt.state.ApplyOnboarding(answers())
+		rt.mu.Unlock()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		saveState(rt)
+		slog.Info("Onboarding complete", "provider", providerSelect.Selected,
+			"added", len(plan.Add), "duplicates", len(plan.Duplicates), "invalid", len(plan.Invalid))
+		d.Hide()
+		done(runCheck.Checked)
+	}
+	skipBtn.OnTapped = func() {
+		rt.mu.Lock()
+		rt.state.SkipOnboarding()
+		rt.mu.Unlock()
+		saveState(rt)
+		d.Hide()
+	}
+
+	showStep()
+	d.Resize(fyne.NewSize(640, 480))
+	d.Show()
+}
+
+// ----- Config Lint -----
+
+// refreshRepoLint re-validates the repository cache and stores the warnings on
+// the runtime. Provider tokens are resolved outside the lock; auth failures from
+// the current report mark owners that are likely private.
+func refreshRepoLint(rt *Runtime) {
+	rt.mu.RLock()
+	providers := map[string]bool{}
+	for _, r := range rt.state.RepositoriesCache {
+		providers[r.Provider] = false
+	}
+	authFailed := map[string]bool{}
+	if rt.currentReport != nil {
+		for _, rr := range rt.currentReport.Repositories {
+			if category := rr.ErrorCategory(); category == report.ErrorCategoryAuth || category == report.ErrorCategoryPrivate {
+				authFailed[fmt.Sprintf("%s:%s/%s@%s", rr.Provider, rr.Owner, rr.Repository, rr.Ref)] = true
+			}
+		}
+	}
+	rt.mu.RUnlock()
+
+	for p := range providers {
+		tok, err := statepkg.ResolveProviderToken(p, rt.state, rt.credentialStore)
+		providers[p] = err == nil && tok != ""
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.repoLint = rt.state.LintRepositories(statepkg.LintOptions{
+		HasProviderToken: func(p string) bool { return providers[p] },
+		AuthFailed: func(e statepkg.RepoCacheEntry) bool {
+			return authFailed[fmt.Sprintf("%s:%s/%s@%s", e.Provider, e.Owner, e.Repository, e.Ref)]
+		},
+	})
+}
+
+// tableCell is a table label that opens a context menu when right-clicked;
+// cell is the position it currently renders and heat, when set, its
+// heatmap background
+type tableCell struct {
+	widget.Label
+	cell widget.TableCellID
+	heat color.Color
+	menu func(c *tableCell, pos fyne.Position)
+}
+
+var _ fyne.SecondaryTappable = (*tableCell)(nil)
+
+func newTableCell(menu func(c *tableCell, pos fyne.Position)) *tableCell {
+	c := &tableCell{menu: menu}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// TappedSecondary opens the context menu at the pointer
+func (c *tableCell) TappedSecondary(e *fyne.PointEvent) {
+	c.menu(c, e.AbsolutePosition)
+}
+
+// CreateRenderer draws the label over its heat background
+func (c *tableCell) CreateRenderer() fyne.WidgetRenderer {
+	bg := canvas.NewRectangle(color.Transparent)
+	return &tableCellRenderer{WidgetRenderer: c.Label.CreateRenderer(), cell: c, bg: bg}
+}
+
+// tableCellRenderer wraps the label renderer with a background rectangle
+type tableCellRenderer struct {
+	fyne.WidgetRenderer
+	cell *tableCell
+	bg   *canvas.Rectangle
+}
+
+func (r *tableCellRenderer) Layout(size fyne.Size) {
+	r.bg.Resize(size)
+	r.WidgetRenderer.Layout(size)
+}
+
+func (r *tableCellRenderer) Objects() []fyne.CanvasObject {
+	return append([]fyne.CanvasObject{r.bg}, r.WidgetRenderer.Objects()...)
+}
+
+func (r *tableCellRenderer) Refresh() {
+	r.bg.FillColor = color.Transparent
+	if r.cell.heat != nil {
+		r.bg.FillColor = r.cell.heat
+	}
+	r.bg.Refresh()
+	r.WidgetRenderer.Refresh()
+}
+
+// lintBadge is a warning icon that shows its lint messages in a tooltip-style
+// popup while hovered. It hides itself when there are no warnings.
+type lintBadge struct {
+	widget.Icon
+	tooltip string
+	popup   *widget.PopUp
+}
+
+var _ desktop.Hoverable = (*lintBadge)(nil)
+
+func newLintBadge() *lintBadge {
+	b := &lintBadge{}
+	b.ExtendBaseWidget(b)
+	b.SetResource(theme.WarningIcon())
+	b.Hide()
+	return b
+}
+
+// SetWarnings updates the tooltip text and visibility.
+func (b *lintBadge) SetWarnings(warnings []statepkg.RepoLintWarning) {
+	lines := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		lines = append(lines, "• "+w.Message)
+	}
+	b.tooltip = strings.Join(lines, "\n")
+	if b.tooltip == "" {
+		b.Hide()
+	} else {
+		b.Show()
+	}
+}
+
+// MouseIn shows the tooltip next to the pointer.
+func (b *lintBadge) MouseIn(e *desktop.MouseEvent) {
+	if b.tooltip == "" {
+		return
+	}
+	c := fyne.CurrentApp().Driver().CanvasForObject(b)
+	if c == nil {
+		return
+	}
+	b.popup = widget.NewPopUp(widget.NewLabel(b.tooltip), c)
+	b.popup.ShowAtPosition(e.AbsolutePosition.Add(fyne.NewPos(12, 12)))
+}
+
+// MouseMoved is required by desktop.Hoverable.
+func (b *lintBadge) MouseMoved(*desktop.MouseEvent) {}
+
+// MouseOut hides the tooltip.
+func (b *lintBadge) MouseOut() {
+	if b.popup != nil {
+		b.popup.Hide()
+		b.popup = nil
+	}
+}
+
+// ----- Repo Detail Modal -----
+
+// errorCategoryImportance maps a report error category to a label importance (color).
+func errorCategoryImportance(category report.ErrorCategory) widget.Importance {
+	switch category {
+	case report.ErrorCategoryRateLimit, report.ErrorCategoryTimeout, report.ErrorCategoryBudget:
+		return widget.WarningImportance
+	case report.ErrorCategoryNotFound:
+		return widget.LowImportance
+	case report.ErrorCategoryParse, report.ErrorCategoryConfig:
+		return widget.HighImportance
+	default:
+		return widget.DangerImportance
+	}
+}
+
+func showRepoDetailsModal(rt *Runtime, repo report.RepositoryReport, w fyne.Window, enqueueUI func(func())) {
+	content := container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("Repository: %s/%s@%s",
+			repo.Owner, repo.Repository, repo.Ref),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+	)
+	if len(repo.Tags) > 0 {
+		content.Add(widget.NewLabel("Tags: " + strings.Join(repo.Tags, ", ")))
+	}
+	if repo.Error != nil {
+		category := repo.ErrorCategory()
+		errLabel := widget.NewLabel(fmt.Sprintf("Error [%s]: %v", category, repo.Error))
+		errLabel.Importance = errorCategoryImportance(category)
+		errLabel.Wrapping = fyne.TextWrapWord
+		content.Add(errLabel)
+		if hint := category.Hint(); hint != "" {
+			hintLabel := widget.NewLabel(hint)
+			hintLabel.Wrapping = fyne.TextWrapWord
+			content.Add(hintLabel)
+		}
+	}
+	if repo.Anonymous {
+		content.Add(widget.NewLabel("Accessed anonymously: no token is configured for this repository."))
+	}
+	if repo.ConstraintOnly {
+		content.Add(widget.NewLabel("No lock file: versions are the constraints declared in the manifests."))
+	}
+	for _, m := range repo.Mirrors {
+		line := fmt.Sprintf("Mirror: %s", m.Key())
+		switch {
+		case m.Error != nil:
+			line += " (failed: " + m.Error.Message + ")"
+		case len(m.OutOfSync) > 0:
+			line += " (out of sync: " + strings.Join(m.OutOfSync, ", ") + ")"
+		}
+		mirrorLabel := widget.NewLabel(line)
+		mirrorLabel.Wrapping = fyne.TextWrapWord
+		content.Add(mirrorLabel)
+	}
+	content.Add(widget.NewLabel("Dependencies:"))
+	for pkg, ver := range repo.Dependencies {
+		line := fmt.Sprintf("  %s: %s", pkg, ver)
+		if locked, ok := repo.LockedNames[pkg]; ok {
+			line += " (locked as " + locked + ")"
+		}
+		if registry := repo.Registries[pkg]; registry != "" && registry != dependencies.PyPIRegistry {
+			line += " (from " + registry + ")"
+		}
+		if constraint := repo.Constraints[pkg]; constraint != "" {
+			line += " (declared " + constraint + ")"
+		}
+		content.Add(widget.NewLabel(line))
+	}
+	if len(repo.Ignored) > 0 {
+		content.Add(widget.NewSeparator())
+		content.Add(widget.NewLabel("Ignored findings:"))
+		for _, pkg := range slices.Sorted(maps.Keys(repo.Ignored)) {
+			ig := repo.Ignored[pkg]
+			l := widget.NewLabel(fmt.Sprintf("  %s %s [%s]", pkg, repo.Dependencies[pkg], report.IgnoreNote(&ig)))
+			l.Importance = widget.LowImportance
+			l.Wrapping = fyne.TextWrapWord
+			content.Add(l)
+		}
+	}
+	if inconsistent := repo.InconsistentPackages(); len(inconsistent) > 0 {
+		content.Add(widget.NewSeparator())
+		warn := widget.NewLabel("Internal inconsistency: dependency files lock these packages at different versions")
+		warn.Importance = widget.WarningImportance
+		warn.Wrapping = fyne.TextWrapWord
+		content.Add(warn)
+		for _, pkg := range inconsistent {
+			files := repo.FileVersions[pkg]
+			for _, path := range slices.Sorted(maps.Keys(files)) {
+				content.Add(widget.NewLabel(fmt.Sprintf("  %s: %s in %s", pkg, cmp.Or(files[path], "-"), path)))
+			}
+		}
+	}
+	if len(repo.UpdatePullRequests) > 0 {
+		content.Add(widget.NewSeparator())
+		content.Add(widget.NewLabel("Open update PRs:"))
+		now := time.Now()
+		for pkg, pr := range repo.UpdatePullRequests {
+			content.Add(widget.NewLabel(fmt.Sprintf("  %s: #%d open since %d days (%s)",
+				pkg, pr.Number, pr.OpenDays(now), pr.URL)))
+		}
+	}
+	content.Add(widget.NewSeparator())
+	content.Add(widget.NewButton("Repository Health...", func() {
+		// The caller may hold rt.mu while building this dialog; look the
+		// repository up only when asked
+		entry := statepkg.RepoCacheEntry{Provider: repo.Provider, Owner: repo.Owner, Repository: repo.Repository, Ref: repo.Ref, Analyzer: repo.Analyzer}
+		rt.mu.RLock()
+		for _, rc := range rt.state.RepositoriesCache {
+			if rc.RepositoryKey() == repo.Key() {
+				entry = rc
+				break
+			}
+		}
+		rt.mu.RUnlock()
+		showRepositoryHealthDialog(rt, w, enqueueUI, entry)
+	}))
+	dialog.ShowCustom("Repository Details", "Close", container.NewVScroll(content), w)
+}
+
+// showCellProvenance explains a version cell: the dependency file the
+// version was read from, the dependency's type and source, and the commit the
+// repository was analyzed at
+func showCellProvenance(rt *Runtime, repo report.RepositoryReport, pkg string, w fyne.Window, enqueueUI func(func())) {
+	value := func(text string) *widget.Label {
+		l := widget.NewLabel(cmp.Or(text, "—"))
+		l.Wrapping = fyne.TextWrapWord
+		return l
+	}
+	prov, found := repo.Provenance[pkg]
+	form := widget.NewForm(
+		widget.NewFormItem("Version", value(versionCellText(&repo, pkg))),
+		widget.NewFormItem("File", value(prov.File)),
+		widget.NewFormItem("Type", value(prov.Type)),
+		widget.NewFormItem("Source", value(prov.Source)),
+	)
+	if registry := repo.Registries[pkg]; registry != "" {
+		form.Append("Registry", value(registry))
+	}
+	if locked, ok := repo.LockedNames[pkg]; ok {
+		form.Append("Locked as", value(locked))
+	}
+	commit := repo.CommitSHA
+	if commit != "" && repo.Cached {
+		commit += " (unchanged since the previous run)"
+	}
+	form.Append("Commit", value(commit))
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("%s in %s/%s@%s", pkg, repo.Owner, repo.Repository, repo.Ref),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		form,
+	)
+	switch {
+	case repo.Dependencies[pkg] == "" && !repo.ConstraintOnly:
+		content.Add(widget.NewLabel("Not locked by any dependency file of this repository."))
+	case !found:
+		content.Add(widget.NewLabel("This report does not record where the version was read (reused from an older snapshot)."))
+	}
+	if files := repo.FileVersions[pkg]; len(files) > 1 {
+		content.Add(widget.NewLabel("Locked by several files:"))
+		for _, path := range slices.Sorted(maps.Keys(files)) {
+			content.Add(widget.NewLabel(fmt.Sprintf("  %s in %s", cmp.Or(files[path], "-"), path)))
+		}
+	}
+	var d dialog.Dialog
+	content.Add(widget.NewButton("Repository Details...", func() {
+		d.Hide()
+		showRepoDetailsModal(rt, repo, w, enqueueUI)
+	}))
+	d = dialog.NewCustom("Version Provenance", "Close", content, w)
+	d.Show()
+}
+
+// packageHistoryPoints is the number of most recent snapshots the package
+// detail dialog lists
+const packageHistoryPoints = 20
+
+// showPackageDetailDialog shows every version of pkg in rpt with the
+// repositories locking each (statepkg.NewPackageDetail), and the versions
+// of pkg across the archived snapshots, loaded in the background
+func showPackageDetailDialog(rt *Runtime, rpt *report.Report, pkg string, w fyne.Window, enqueueUI func(func())) {
+	detail := statepkg.NewPackageDetail(rpt, pkg)
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("Package: "+pkg, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel("Newest version found: "+cmp.Or(detail.Latest, "—")),
+		widget.NewSeparator(),
+	)
+	if len(detail.Versions) == 0 {
+		content.Add(widget.NewLabel("No analyzed repository locks this package."))
+	}
+	for _, v := range detail.Versions {
+		heading := widget.NewLabel("")
+		heading.TextStyle = fyne.TextStyle{Bold: true}
+		switch {
+		case v.Behind == 0:
+			heading.SetText(fmt.Sprintf("%s (newest, %d repositories)", v.Version, len(v.Users)))
+		case v.Behind > 0:
+			heading.Importance = widget.WarningImportance
+			heading.SetText(fmt.Sprintf("%s (%d behind, %d repositories)", v.Version, v.Behind, len(v.Users)))
+		default:
+			heading.Importance = widget.LowImportance
+			heading.SetText(fmt.Sprintf("%s (ignored, %d repositories)", v.Version, len(v.Users)))
+		}
+		content.Add(heading)
+		for _, u := range v.Users {
+			var notes []string
+			if u.Relation != "" {
+				notes = append(notes, u.Relation)
+			}
+			if u.Dev {
+				notes = append(notes, "dev")
+			}
+			if u.Suppressed {
+				notes = append(notes, "ignored")
+			}
+			if u.Registry != "" && u.Registry != dependencies.PyPIRegistry {
+				notes = append(notes, "from "+u.Registry)
+			}
+			line := "  " + u.Repository
+			if len(notes) > 0 {
+				line += " (" + strings.Join(notes, ", ") + ")"
+			}
+			content.Add(widget.NewLabel(line))
+		}
+	}
+	if len(detail.Missing) > 0 {
+		missing := widget.NewLabel("Not locked by: " + strings.Join(detail.Missing, ", "))
+		missing.Wrapping = fyne.TextWrapWord
+		content.Add(missing)
+	}
+	if len(detail.Failed) > 0 {
+		failed := widget.NewLabel("Failed analysis: " + strings.Join(detail.Failed, ", "))
+		failed.Importance = widget.DangerImportance
+		failed.Wrapping = fyne.TextWrapWord
+		content.Add(failed)
+	}
+
+	content.Add(widget.NewSeparator())
+	content.Add(widget.NewLabelWithStyle("Version history", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	history := container.NewVBox(widget.NewLabel("Loading snapshot history..."))
+	content.Add(history)
+	rt.mu.RLock()
+	dir, err := rt.historyDirLocked()
+	rt.mu.RUnlock()
+	if err != nil {
+		history.Objects = []fyne.CanvasObject{widget.NewLabel(err.Error())}
+	} else {
+		go func() {
+			lines := packageHistoryLines(dir, pkg)
+			enqueueUI(func() {
+				history.Objects = nil
+				for _, line := range lines {
+					history.Add(widget.NewLabel(line))
+				}
+				history.Refresh()
+			})
+		}()
+	}
+
+	d := dialog.NewCustom("Package Details", "Close", container.NewVScroll(content), w)
+	d.Resize(fyne.NewSize(560, 520))
+	d.Show()
+}
+
+// packageHistoryLines lists, newest first, the versions of pkg and how many
+// repositories locked each in the latest packageHistoryPoints snapshots
+// archived in dir
+func packageHistoryLines(dir, pkg string) []string {
+	history, err := report.LoadSnapshotHistory(dir)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if len(history) == 0 {
+		return []string{"No archived snapshots yet: they are recorded after each successful report."}
+	}
+	t := report.Trend(history[max(0, len(history)-packageHistoryPoints):], pkg)
+	var lines []string
+	for i := len(t.Points) - 1; i >= 0; i-- {
+		p := t.Points[i]
+		var counts []string
+		for j := len(t.Versions) - 1; j >= 0; j-- {
+			if n := p.Versions[t.Versions[j]]; n > 0 {
+				counts = append(counts, fmt.Sprintf("%s ×%d", t.Versions[j], n))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", p.Time.Local().Format("2006-01-02 15:04"), cmp.Or(strings.Join(counts, ", "), "not locked")))
+	}
+	return lines
+}
+
+// showRepositoryHealthDialog shows a repository's provider metadata (fetched
+// in the background with GetRepositoryInfo), the commit it was last analyzed
+// at, its latest error and how many of its tracked packages were found
+func showRepositoryHealthDialog(rt *Runtime, w fyne.Window, enqueueUI func(func()), entry statepkg.RepoCacheEntry) {
+	rt.mu.RLock()
+	health := rt.state.RepositoryHealth(entry, rt.currentReport)
+	rt.mu.RUnlock()
+
+	valueLabel := func(text string) *widget.Label {
+		l := widget.NewLabel(text)
+		l.Wrapping = fyne.TextWrapWord
+		return l
+	}
+	defaultBranch := valueLabel("Loading...")
+	description := valueLabel("Loading...")
+	url := valueLabel("Loading...")
+
+	sha, analysis, counts := "not analyzed", "No report includes this repository yet", "-"
+	if health.Analyzed {
+		sha = cmp.Or(health.CommitSHA, "unknown (provider could not resolve the ref)")
+		analysis = "Succeeded"
+		if health.Cached {
+			analysis += " (reused from the previous report; commit unchanged)"
+		}
+		counts = fmt.Sprintf("%d of %d tracked packages found", health.Found, health.Found+len(health.Missing))
+		if len(health.Missing) > 0 {
+			counts += "; missing: " + strings.Join(health.Missing, ", ")
+		}
+		if health.Locked > 0 {
+			counts += fmt.Sprintf("; %d locked packages", health.Locked)
+		}
+	}
+	analysisLabel := valueLabel(analysis)
+	if health.Error != "" {
+		analysisLabel.SetText(fmt.Sprintf("Failed [%s]: %s", health.ErrorCategory, health.Error))
+		analysisLabel.Importance = errorCategoryImportance(health.ErrorCategory)
+	}
+	lastError := "None logged"
+	if e := health.LastError; e != nil {
+		lastError = fmt.Sprintf("%s: %s", e.Time.Local().Format(time.DateTime), e.Message)
+		if e.Details != "" {
+			lastError += " (" + e.Details + ")"
+		}
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem("Repository", valueLabel(health.Key)),
+		widget.NewFormItem("Description", description),
+		widget.NewFormItem("Default branch", defaultBranch),
+		widget.NewFormItem("URL", url),
+		widget.NewFormItem("Analyzed commit", valueLabel(sha)),
+		widget.NewFormItem("Last analysis", analysisLabel),
+		widget.NewFormItem("Dependencies", valueLabel(counts)),
+		widget.NewFormItem("Last error", valueLabel(lastError)),
+	)
+	d := dialog.NewCustom("Repository Health", "Close", container.NewVScroll(form), w)
+	d.Resize(fyne.NewSize(640, 460))
+	d.Show()
+
+	go func() {
+		info, err := fetchRepositoryInfo(rt, entry)
+		enqueueUI(func() {
+			if err != nil {
+				for _, l := range []*widget.Label{defaultBranch, description, url} {
+					l.SetText("unavailable")
+				}
+				description.SetText(fmt.Sprintf("Could not load repository information: %v", err))
+				description.Importance = widget.WarningImportance
+				description.Refresh()
+				return
+			}
+			defaultBranch.SetText(info.DefaultBranch)
+			description.SetText(cmp.Or(info.Description, "(none)"))
+			url.SetText(info.URL)
+		})
+	}()
+}
+
+// fetchRepositoryInfo reads a repository's provider metadata
+func fetchRepositoryInfo(rt *Runtime, entry statepkg.RepoCacheEntry) (*repository.Info, error) {
+	client, err := newRepositoryClient(rt, entry)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return client.GetRepositoryInfo(ctx, entry.Owner, entry.Repository)
+}
+
+// newRepositoryClient creates a client for entry using its token (or the
+// resolved provider token) and network settings
+func newRepositoryClient(rt *Runtime, entry statepkg.RepoCacheEntry) (repository.Client, error) {
+	rt.mu.RLock()
+	token := entry.Token
+	if token == "" {
+		var err error
+		if token, err = statepkg.ResolveProviderToken(entry.Provider, rt.state, rt.credentialStore); err != nil {
+			slog.Debug("No provider token for repository info", "provider", entry.Provider, "error", err)
+		}
+	}
+	requestTimeout := rt.state.GUI.Timeouts.Request
+	rt.mu.RUnlock()
+
+	return repository.NewClient(entry.Provider, repository.Config{
+		Token:              token,
+		BaseURL:            entry.BaseURL,
+		Enterprise:         entry.Enterprise,
+		APIPrefix:          entry.APIPrefix,
+		Proxy:              entry.Proxy,
+		CAFile:             entry.CAFile,
+		InsecureSkipVerify: entry.InsecureSkipVerify,
+		RequestTimeout:     requestTimeout,
+	})
+}
+
+// maxRefOptions caps the refs shown in a ref picker's dropdown
+const maxRefOptions = 50
+
+// refTarget describes the repository a dialog's fields name, falling back to
+// the provider defaults for the base URL and network settings
+func refTarget(rt *Runtime, provider, owner, repo, baseURL, token string) statepkg.RepoCacheEntry {
+	rt.mu.RLock()
+	defaults := rt.state.Providers[provider].Default
+	rt.mu.RUnlock()
+	return statepkg.RepoCacheEntry{
+		Provider:           provider,
+		Owner:              strings.TrimSpace(owner),
+		Repository:         strings.TrimSpace(repo),
+		BaseURL:            cmp.Or(strings.TrimSpace(baseURL), defaults.BaseURL),
+		Enterprise:         defaults.Enterprise,
+		APIPrefix:          defaults.APIPrefix,
+		Token:              cmp.Or(token, defaults.Token),
+		Proxy:              defaults.Proxy,
+		CAFile:             defaults.CAFile,
+		InsecureSkipVerify: defaults.InsecureSkipVerify,
+	}
+}
+
+// newRefPicker returns a ref entry and the row holding it with a button that
+// loads the branches and tags of the repository target describes. Once
+// loaded, the dropdown lists the refs matching the typed text and the entry
+// flags refs that are neither one of them nor a commit SHA.
+func newRefPicker(rt *Runtime, w fyne.Window, enqueueUI func(func()), target func() statepkg.RepoCacheEntry) (*widget.SelectEntry, fyne.CanvasObject) {
+	var refs []string // nil until loaded
+	entry := widget.NewSelectEntry(nil)
+	entry.SetPlaceHolder("Branch, tag or commit (empty: default branch)")
+	filter := func(text string) {
+		if refs == nil {
+			return
+		}
+		text = strings.ToLower(strings.TrimSpace(text))
+		var options []string
+		for _, r := range refs {
+			if strings.Contains(strings.ToLower(r), text) {
+				options = append(options, r)
+				if len(options) == maxRefOptions {
+					break
+				}
+			}
+		}
+		entry.SetOptions(options)
+	}
+	entry.OnChanged = filter
+	entry.Validator = func(text string) error {
+		text = strings.TrimSpace(text)
+		if refs == nil || text == "" || slices.Contains(refs, text) || repository.IsCommitSHA(text) {
+			return nil
+		}
+		return fmt.Errorf("not a branch or tag of the repository")
+	}
+
+	loadBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), nil)
+	loadBtn.OnTapped = func() {
+		t := target()
+		if t.Owner == "" || t.Repository == "" {
+			dialog.ShowError(errors.New("enter the owner and repository first"), w)
+			return
+		}
+		loadBtn.Disable()
+		go func() {
+			var loaded []repository.Ref
+			client, err := newRepositoryClient(rt, t)
+			if err == nil {
+				lister, ok := client.(repository.RefLister)
+				if !ok {
+					err = fmt.Errorf("%s cannot list refs", providerDisplayName(t.Provider))
+				} else {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+					loaded, err = lister.ListRefs(ctx, t.Owner, t.Repository)
+					cancel()
+				}
+			}
+			enqueueUI(func() {
+				loadBtn.Enable()
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("loading refs of %s/%s: %w", t.Owner, t.Repository, err), w)
+					return
+				}
+				refs = make([]string, 0, len(loaded))
+				for _, r := range loaded {
+					refs = append(refs, r.Name)
+				}
+				filter("")
+				_ = entry.Validate()
+				slog.Info("Loaded refs", "repository", t.Owner+"/"+t.Repository, "refs", len(refs))
+			})
+		}()
+	}
+	return entry, container.NewBorder(nil, nil, nil, loadBtn, entry)
+}
+
+// ----- Errors View -----
+
+// errorRow is one line of the Errors view: a run header or an entry
+type errorRow struct {
+	header string
+	entry  *statepkg.ErrorLogEntry
+}
+
+// errorRows flattens ErrorRuns into list rows, newest run first
+func errorRows(rt *Runtime) []errorRow {
+	rt.mu.RLock()
+	runs := rt.state.ErrorRuns()
+	rt.mu.RUnlock()
+
+	var rows []errorRow
+	for _, run := range runs {
+		label := "Run " + run.RunID
+		if run.RunID == "" {
+			label = "Outside report runs"
+		}
+		rows = append(rows, errorRow{header: fmt.Sprintf("%s (%d)", label, len(run.Entries))})
+		for i := range run.Entries {
+			rows = append(rows, errorRow{entry: &run.Entries[i]})
+		}
+	}
+	return rows
+}
+
+// errorEntryText renders an error log entry for display and the clipboard
+func errorEntryText(e *statepkg.ErrorLogEntry) string {
+	text := fmt.Sprintf("%s [%s] %s: %s", e.Time.Format(time.RFC3339), strings.ToUpper(e.Severity), e.Source, e.Message)
+	if e.Details != "" {
+		text += " (" + e.Details + ")"
+	}
+	return text
+}
+
+// severityIcon picks the icon shown next to an error log entry
+func severityIcon(severity string) fyne.Resource {
+	switch strings.ToLower(severity) {
+	case "error":
+		return theme.ErrorIcon()
+	case "warn", "warning":
+		return theme.WarningIcon()
+	default:
+		return theme.InfoIcon()
+	}
+}
+
+func buildErrorsView(rt *Runtime, app fyne.App, w fyne.Window) fyne.CanvasObject {
+	rows := errorRows(rt)
+	status := widget.NewLabel("")
+
+	var list *widget.List
+	reload := func() {
+		rows = errorRows(rt)
+		list.Refresh()
+	}
+
+	list = widget.NewList(
+		func() int { return len(rows) },
+		func() fyne.CanvasObject {
+			copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), nil)
+			copyBtn.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, widget.NewIcon(theme.InfoIcon()), copyBtn, widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			lbl := c.Objects[0].(*widget.Label)
+			icon := c.Objects[1].(*widget.Icon)
+			copyBtn := c.Objects[2].(*widget.Button)
+			if i >= len(rows) {
+				lbl.SetText("")
+				return
+			}
+			row := rows[i]
+			if row.entry == nil {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.SetText(row.header)
+				icon.Hide()
+				copyBtn.Hide()
+				return
+			}
+			text := errorEntryText(row.entry)
+			lbl.TextStyle = fyne.TextStyle{}
+			lbl.SetText(text)
+			icon.SetResource(severityIcon(row.entry.Severity))
+			icon.Show()
+			copyBtn.OnTapped = func() {
+				app.Clipboard().SetContent(text)
+				status.SetText("Copied entry to clipboard.")
+			}
+			copyBtn.Show()
+		},
+	)
+
+	refreshBtn := widget.NewButton("Refresh", reload)
+	copyAllBtn := widget.NewButton("Copy All", func() {
+		var b strings.Builder
+		for _, row := range rows {
+			if row.entry == nil {
+				b.WriteString(row.header + "\n")
+				continue
+			}
+			b.WriteString("  " + errorEntryText(row.entry) + "\n")
+		}
+		app.Clipboard().SetContent(b.String())
+		status.SetText("Copied all errors to clipboard.")
+	})
+	clearResolvedBtn := widget.NewButton("Clear Resolved", func() {
+		rt.mu.Lock()
+		removed := rt.state.ClearResolvedErrors(rt.lastRunID, rt.currentReport)
+		rt.mu.Unlock()
+		saveState(rt)
+		reload()
+		status.SetText(fmt.Sprintf("Cleared %d resolved error(s).", removed))
+	})
+	clearAllBtn := widget.NewButton("Clear All", func() {
+		dialog.ShowConfirm("Clear Errors", "Remove every recorded error?", func(ok bool) {
+			if !ok {
+				return
+			}
+			rt.mu.Lock()
+			rt.state.ErrorLog = []statepkg.ErrorLogEntry{}
+			rt.mu.Unlock()
+			saveState(rt)
+			reload()
+			status.SetText("Cleared all errors.")
+		}, w)
+	})
+
+	return container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Errors", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewSeparator(),
+			container.NewHBox(refreshBtn, copyAllBtn, clearResolvedBtn, clearAllBtn),
+			status,
+		),
+		nil, nil, nil,
+		list,
+	)
+}
+
+// ----- Overview View -----
+
+// overviewBarWidth is the width of the longest bar of the Overview charts
+const overviewBarWidth = 240
+
+// overviewChartHeight is the height of the tallest bar of the Overview
+// history charts
+const overviewChartHeight = 80
+
+// overviewCard is a card showing one figure in large type with a caption
+func overviewCard(title, value, caption string) fyne.CanvasObject {
+	figure := canvas.NewText(value, theme.Color(theme.ColorNamePrimary))
+	figure.TextSize = theme.TextSize() * 2
+	figure.TextStyle = fyne.TextStyle{Bold: true}
+	note := widget.NewLabel(caption)
+	note.Wrapping = fyne.TextWrapWord
+	return widget.NewCard(title, "", container.NewVBox(figure, note))
+}
+
+// overviewHistoryChart draws one bar per snapshot, scaled to the largest
+// value
+func overviewHistoryChart(values []int, color fyne.ThemeColorName) fyne.CanvasObject {
+	peak := slices.Max(append(slices.Clone(values), 1))
+	bars := container.NewHBox()
+	for _, v := range values {
+		bar := canvas.NewRectangle(theme.Color(color))
+		bar.SetMinSize(fyne.NewSize(10, max(1, float32(v)/float32(peak)*overviewChartHeight)))
+		bars.Add(container.NewVBox(layout.NewSpacer(), bar))
+	}
+	return bars
+}
+
+// buildOverviewView summarizes the current report and the snapshot history:
+// cards with the headline figures, the most divergent packages, and drift
+// and failures across the archived snapshots
+func buildOverviewView(rt *Runtime, enqueueUI func(func())) fyne.CanvasObject {
+	body := container.NewVBox()
+	status := widget.NewLabel("")
+
+	render := func(o statepkg.Overview, last *statepkg.LastReportMeta, historyErr error) {
+		body.Objects = nil
+		if o.Repositories == 0 {
+			status.SetText("No report yet: run one from the Dependencies view.")
+		} else {
+			status.SetText("")
+		}
+
+		largest, largestNote := "None", "No package drifts."
+		if o.Largest.Behind > 0 {
+			largest = fmt.Sprintf("%d behind", o.Largest.Behind)
+			largestNote = fmt.Sprintf("%s locks %s %s; newest is %s", o.Largest.Repository, o.Largest.Package, o.Largest.Version, o.Largest.Latest)
+		}
+		lastRun, lastNote := "-", "No completed run recorded."
+		if last != nil {
+			if last.Duration > 0 {
+				lastRun = last.Duration.Round(100 * time.Millisecond).String()
+			}
+			lastNote = "Finished " + last.GeneratedAt.Local().Format(time.DateTime)
+		}
+		body.Add(container.NewGridWithColumns(3,
+			overviewCard("Repositories analyzed", fmt.Sprintf("%d/%d", o.Repositories-o.Failed, o.Repositories), "Successfully analyzed of those configured"),
+			overviewCard("Errors", strconv.Itoa(o.Failed), "Repositories that failed analysis"),
+			overviewCard("Packages tracked", strconv.Itoa(o.Packages), fmt.Sprintf("%d with version drift", o.Drifting)),
+			overviewCard("Largest drift", largest, largestNote),
+			overviewCard("Last run", lastRun, lastNote),
+		))
+
+		divergent := container.NewVBox()
+		if len(o.Divergent) == 0 {
+			divergent.Add(widget.NewLabel("No package is locked at more than one version."))
+		}
+		for _, d := range o.Divergent {
+			bar := canvas.NewRectangle(theme.Color(theme.ColorNameWarning))
+			bar.SetMinSize(fyne.NewSize(float32(d.Versions)/float32(o.Divergent[0].Versions)*overviewBarWidth, theme.TextSize()))
+			divergent.Add(container.NewBorder(nil, nil,
+				widget.NewLabel(fmt.Sprintf("%s: %d versions (%s to %s)", d.Package, d.Versions, d.Min, d.Max)), nil,
+				container.NewHBox(container.NewCenter(bar))))
+		}
+		body.Add(widget.NewCard(fmt.Sprintf("Top %d most divergent packages", statepkg.DefaultOverviewTop), "", divergent))
+
+		var history fyne.CanvasObject
+		switch {
+		case historyErr != nil:
+			history = widget.NewLabel(historyErr.Error())
+		case len(o.History) == 0:
+			history = widget.NewLabel("No archived snapshots yet: they are recorded after each successful report.")
+		default:
+			drifting, failed := make([]int, len(o.History)), make([]int, len(o.History))
+			for i, p := range o.History {
+				drifting[i], failed[i] = p.Drifting, p.Failed
+			}
+			first, latest := o.History[0], o.History[len(o.History)-1]
+			history = container.NewVBox(
+				widget.NewLabel(fmt.Sprintf("%d snapshots from %s to %s", len(o.History),
+					first.Time.Local().Format("2006-01-02"), latest.Time.Local().Format("2006-01-02"))),
+				container.NewGridWithColumns(2,
+					container.NewVBox(widget.NewLabel(fmt.Sprintf("Packages with drift (now %d)", latest.Drifting)), overviewHistoryChart(drifting, theme.ColorNameWarning)),
+					container.NewVBox(widget.NewLabel(fmt.Sprintf("Failed repositories (now %d)", latest.Failed)), overviewHistoryChart(failed, theme.ColorNameError)),
+				),
+			)
+		}
+		body.Add(widget.NewCard("History", "", history))
+		body.Refresh()
+	}
+
+	reload := func() {
+		rt.mu.RLock()
+		rpt := rt.currentReport
+		var last *statepkg.LastReportMeta
+		if rt.state.GUI.LastReport != nil {
+			meta := *rt.state.GUI.LastReport
+			last = &meta
+		}
+		dir, dirErr := rt.historyDirLocked()
+		rt.mu.RUnlock()
+		status.SetText("Loading snapshot history...")
+		go func() {
+			var history []*report.Snapshot
+			err := dirErr
+			if err == nil {
+				history, err = report.LoadSnapshotHistory(dir)
+			}
+			o := statepkg.NewOverview(rpt, history, statepkg.DefaultOverviewTop)
+			enqueueUI(func() { render(o, last, err) })
+		}()
+	}
+	reload()
+
+	return container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil,
+				widget.NewLabelWithStyle("Overview", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewButton("Refresh", reload)),
+			widget.NewSeparator(),
+			status,
+		),
+		nil, nil, nil,
+		container.NewVScroll(body),
+	)
+}
+
+// ----- Alerts View -----
+
+// alertSnoozes are the durations offered when snoozing an alert
+var alertSnoozes = []struct {
+	label string
+	d     time.Duration
+}{
+	{"1 hour", time.Hour},
+	{"1 day", 24 * time.Hour},
+	{"1 week", 7 * 24 * time.Hour},
+}
+
+// alertText formats an alert for the Alerts view
+func alertText(a statepkg.Alert, now time.Time) string {
+	text := fmt.Sprintf("%s %s: %s", a.Time.Local().Format(time.DateTime), a.Rule, a.Message)
+	if a.SnoozedUntil.After(now) {
+		text += fmt.Sprintf(" (snoozed until %s)", a.SnoozedUntil.Local().Format(time.DateTime))
+	}
+	return text
+}
+
+// buildAlertsView lists the alerts fired by background refreshes, newest
+// first, with acknowledge and snooze per alert; the alert rules are edited
+// from here (see showAlertRulesDialog)
+func buildAlertsView(rt *Runtime, w fyne.Window) fyne.CanvasObject {
+	var alerts []statepkg.Alert
+	status := widget.NewLabel("")
+
+	var list *widget.List
+	reload := func() {
+		rt.mu.RLock()
+		alerts = slices.Clone(rt.state.Alerts)
+		rules := len(rt.state.AlertRules)
+		rt.mu.RUnlock()
+		slices.Reverse(alerts)
+		open := 0
+		for _, a := range alerts {
+			if !a.Acknowledged {
+				open++
+			}
+		}
+		status.SetText(fmt.Sprintf("%d open alert(s), %d rule(s). Rules are checked after each auto-refresh or tray refresh.", open, rules))
+		list.Refresh()
+	}
+	update := func(apply func(*statepkg.GUIState)) {
+		rt.mu.Lock()
+		apply(rt.state)
+		rt.mu.Unlock()
+		saveState(rt)
+		reload()
+	}
+
+	list = widget.NewList(
+		func() int { return len(alerts) },
+		func() fyne.CanvasObject {
+			ackBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), nil)
+			snoozeBtn := widget.NewButtonWithIcon("", theme.HistoryIcon(), nil)
+			lbl := widget.NewLabel("")
+			lbl.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), container.NewHBox(ackBtn, snoozeBtn), lbl)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			lbl := c.Objects[0].(*widget.Label)
+			icon := c.Objects[1].(*widget.Icon)
+			buttons := c.Objects[2].(*fyne.Container)
+			ackBtn, snoozeBtn := buttons.Objects[0].(*widget.Button), buttons.Objects[1].(*widget.Button)
+			if i >= len(alerts) {
+				lbl.SetText("")
+				return
+			}
+			a := alerts[i]
+			if a.Acknowledged {
+				lbl.TextStyle = fyne.TextStyle{}
+				icon.SetResource(theme.InfoIcon())
+				ackBtn.Disable()
+			} else {
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				icon.SetResource(theme.WarningIcon())
+				ackBtn.Enable()
+			}
+			lbl.SetText(alertText(a, time.Now()))
+			ackBtn.OnTapped = func() {
+				update(func(st *statepkg.GUIState) { st.AcknowledgeAlert(a.Key) })
+			}
+			snoozeBtn.OnTapped = func() {
+				options := make([]string, len(alertSnoozes))
+				for i, s := range alertSnoozes {
+					options[i] = s.label
+				}
+				choice := widget.NewRadioGroup(options, nil)
+				choice.SetSelected(options[0])
+				dialog.ShowCustomConfirm("Snooze Alert", "Snooze", "Cancel",
+					container.NewVBox(widget.NewLabel("Acknowledge and silence repeats of:\n"+a.Rule), choice),
+					func(ok bool) {
+						if !ok {
+							return
+						}
+						for _, s := range alertSnoozes {
+							if s.label == choice.Selected {
+								update(func(st *statepkg.GUIState) { st.SnoozeAlert(a.Key, time.Now().UTC().Add(s.d)) })
+							}
+						}
+					}, w)
+			}
+		},
+	)
+
+	ackAllBtn := widget.NewButton("Acknowledge All", func() {
+		update(func(st *statepkg.GUIState) {
+			for _, a := range st.Alerts {
+				st.AcknowledgeAlert(a.Key)
+			}
+		})
+	})
+	clearBtn := widget.NewButton("Clear Acknowledged", func() {
+		now := time.Now()
+		update(func(st *statepkg.GUIState) {
+			// Snoozed alerts are kept so their repeats stay silent
+			st.Alerts = slices.DeleteFunc(st.Alerts, func(a statepkg.Alert) bool {
+				return a.Acknowledged && !a.SnoozedUntil.After(now)
+			})
+		})
+	})
+	rulesBtn := widget.NewButtonWithIcon("Rules...", theme.SettingsIcon(), func() {
+		showAlertRulesDialog(rt, w, reload)
+	})
+	reload()
+
+	return container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Alerts", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewSeparator(),
+			container.NewHBox(widget.NewButton("Refresh", reload), ackAllBtn, clearBtn, rulesBtn),
+			status,
+		),
+		nil, nil, nil,
+		list,
+	)
+}
+
+// showAlertRulesDialog edits the state's alert rules: the current rules
+// with enable and remove controls, and a form adding one. onChange runs
+// after each change is saved.
+func showAlertRulesDialog(rt *Runtime, w fyne.Window, onChange func()) {
+	var rules []statepkg.AlertRule
+	var list *widget.List
+	reload := func() {
+		rt.mu.RLock()
+		rules = slices.Clone(rt.state.AlertRules)
+		rt.mu.RUnlock()
+		list.Refresh()
+	}
+	update := func(apply func(*statepkg.GUIState)) {
+		rt.mu.Lock()
+		apply(rt.state)
+		rt.mu.Unlock()
+		saveState(rt)
+		reload()
+		onChange()
+	}
+
+	list = widget.NewList(
+		func() int { return len(rules) },
+		func() fyne.CanvasObject {
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+			removeBtn.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, widget.NewCheck("", nil), removeBtn, widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			c := o.(*fyne.Container)
+			lbl := c.Objects[0].(*widget.Label)
+			enabled := c.Objects[1].(*widget.Check)
+			removeBtn := c.Objects[2].(*widget.Button)
+			if i >= len(rules) {
+				return
+			}
+			lbl.SetText(rules[i].String())
+			enabled.OnChanged = nil
+			enabled.SetChecked(!rules[i].Disabled)
+			enabled.OnChanged = func(on bool) {
+				update(func(st *statepkg.GUIState) {
+					if i < len(st.AlertRules) {
+						st.AlertRules[i].Disabled = !on
+					}
+				})
+			}
+			removeBtn.OnTapped = func() {
+				update(func(st *statepkg.GUIState) {
+					if i < len(st.AlertRules) {
+						st.AlertRules = slices.Delete(st.AlertRules, i, i+1)
+					}
+				})
+			}
+		},
+	)
+
+	kindSelect := widget.NewSelect(statepkg.AlertKinds, nil)
+	kindSelect.SetSelected(statepkg.AlertNewError)
+	pkgEntry := widget.NewEntry()
+	pkgEntry.SetPlaceHolder("Package (version-change; optional for drift)")
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetPlaceHolder("Distinct versions tolerated (drift)")
+	addBtn := widget.NewButtonWithIcon("Add Rule", theme.ContentAddIcon(), func() {
+		rule := statepkg.AlertRule{Kind: kindSelect.Selected, Package: strings.TrimSpace(pkgEntry.Text)}
+		if text := strings.TrimSpace(thresholdEntry.Text); text != "" {
+			n, err := strconv.Atoi(text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("threshold: %w", err), w)
+				return
+			}
+			rule.Threshold = n
+		}
+		if err := rule.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		update(func(st *statepkg.GUIState) { st.AlertRules = append(st.AlertRules, rule) })
+		pkgEntry.SetText("")
+		thresholdEntry.SetText("")
+	})
+	reload()
+
+	form := container.NewVBox(
+		widget.NewLabel("New rule"),
+		kindSelect,
+		pkgEntry,
+		thresholdEntry,
+		addBtn,
+	)
+	d := dialog.NewCustom("Alert Rules", "Close", container.NewBorder(nil, form, nil, nil, list), w)
+	d.Resize(fyne.NewSize(560, 480))
+	d.Show()
+}
+
+// ----- Graph View -----
+
+// buildGraphView browses the dependency graphs of the latest report: pick a
+// repository and a package to list what it requires and what requires it
+// (directly, or through other packages). Tapping a package navigates to it.
+func buildGraphView(rt *Runtime) fyne.CanvasObject {
+	var (
+		reports    map[string]*report.RepositoryReport
+		requires   []string
+		requiredBy []string
+	)
+	info := widget.NewLabel("")
+	info.Wrapping = fyne.TextWrapWord
+	repoSelect := widget.NewSelect(nil, nil)
+	pkgEntry := widget.NewEntry()
+	pkgEntry.SetPlaceHolder("Package, e.g. urllib3")
+	indirect := widget.NewCheck("Include indirect dependents", nil)
+
+	packageList := func(items *[]string) *widget.List {
+		list := widget.NewList(
+			func() int { return len(*items) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(i widget.ListItemID, o fyne.CanvasObject) {
+				if i < len(*items) {
+					o.(*widget.Label).SetText((*items)[i])
+				}
+			},
+		)
+		list.OnSelected = func(i widget.ListItemID) {
+			list.UnselectAll()
+			if i < len(*items) {
+				pkgEntry.SetText((*items)[i])
+			}
+		}
+		return list
+	}
+	requiresList := packageList(&requires)
+	requiredByList := packageList(&requiredBy)
+
+	show := func() {
+		requires, requiredBy = nil, nil
+		defer requiresList.Refresh()
+		defer requiredByList.Refresh()
+
+		rr, ok := reports[repoSelect.Selected]
+		if !ok {
+			info.SetText("Select a repository. Graphs are available after a report run; Pipfile.lock records packages but no dependency edges.")
+			return
+		}
+		g := rr.Graph
+		name := dependencies.NormalizeName(rr.GetEcosystem(), pkgEntry.Text)
+		if name == "" {
+			info.SetText(fmt.Sprintf("%d packages, %d dependency edges. Enter a package to explore.", len(g.Nodes), len(g.Edges)))
+			return
+		}
+		node, ok := g.Node(name)
+		if !ok {
+			info.SetText(fmt.Sprintf("%s is not locked in %s.", name, repoSelect.Selected))
+			return
+		}
+		requires = g.Requires(name)
+		requiredBy = g.Dependents(name, indirect.Checked)
+		info.SetText(fmt.Sprintf("%s %s requires %d package(s) and is required by %d.", node.Name, node.Version, len(requires), len(requiredBy)))
+	}
+
+	reload := func() {
+		rt.mu.RLock()
+		reports = make(map[string]*report.RepositoryReport)
+		if rt.currentReport != nil {
+			for i := range rt.currentReport.Repositories {
+				rr := &rt.currentReport.Repositories[i]
+				if rr.Graph != nil {
+					reports[rr.Key()] = rr
+				}
+			}
+		}
+		rt.mu.RUnlock()
+		options := make([]string, 0, len(reports))
+		for key := range reports {
+			options = append(options, key)
+		}
+		sort.Strings(options)
+		repoSelect.Options = options
+		if _, ok := reports[repoSelect.Selected]; !ok {
+			repoSelect.ClearSelected()
+			if len(options) > 0 {
+				repoSelect.SetSelected(options[0])
+			}
+		}
+		repoSelect.Refresh()
+		show()
+	}
+
+	repoSelect.OnChanged = func(string) { show() }
+	pkgEntry.OnChanged = func(string) { show() }
+	indirect.OnChanged = func(bool) { show() }
+	reload()
+
+	lists := container.NewGridWithColumns(2,
+		container.NewBorder(widget.NewLabelWithStyle("Requires", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), nil, nil, nil, requiresList),
+		container.NewBorder(widget.NewLabelWithStyle("Required by", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), nil, nil, nil, requiredByList),
+	)
+	return container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Graph", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewSeparator(),
+			container.NewBorder(nil, nil, nil, widget.NewButton("Refresh", reload), repoSelect),
+			container.NewBorder(nil, nil, nil, indirect, pkgEntry),
+			info,
+		),
+		nil, nil, nil,
+		lists,
+	)
+}
+
+// ----- Policies View -----
+
+// buildPoliciesView lists the policy violations of the latest report, errors
+// first. Policies come from the state's policies section (same shape as the
+// CLI config, merged by "Load CLI YAML...").
+func buildPoliciesView(rt *Runtime) fyne.CanvasObject {
+	var violations []report.Violation
+	info := widget.NewLabel("")
+	info.Wrapping = fyne.TextWrapWord
+
+	list := widget.NewList(
+		func() int { return len(violations) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(violations) {
+				return
+			}
+			v := violations[i]
+			label := o.(*widget.Label)
+			text := fmt.Sprintf("[%s] %s  %s: %s (%s)", v.Severity, v.Repository, v.Policy, v.Message, v.File)
+			if note := report.IgnoreNote(v.Ignored); note != "" {
+				text += " [" + note + "]"
+			}
+			label.SetText(text)
+			switch {
+			case v.Suppressed():
+				label.Importance = widget.LowImportance
+			case v.Severity == report.SeverityError:
+				label.Importance = widget.DangerImportance
+			default:
+				label.Importance = widget.MediumImportance
+			}
+			label.Refresh()
+		},
+	)
+
+	reload := func() {
+		rt.mu.RLock()
+		configured := len(rt.state.Policies)
+		violations = nil
+		if rt.currentReport != nil {
+			violations = rt.currentReport.Violations()
+		}
+		rt.mu.RUnlock()
+		// Errors first, suppressed violations last
+		rank := func(v report.Violation) int {
+			switch {
+			case v.Suppressed():
+				return 2
+			case v.Severity == report.SeverityError:
+				return 0
+			default:
+				return 1
+			}
+		}
+		sort.SliceStable(violations, func(i, j int) bool {
+			return rank(violations[i]) < rank(violations[j])
+		})
+		errs, ignored := 0, 0
+		for _, v := range violations {
+			if v.Suppressed() {
+				ignored++
+			} else if v.Severity == report.SeverityError {
+				errs++
+			}
+		}
+		switch {
+		case configured == 0:
+			info.SetText("No policies configured. Add a policies section to a CLI config and load it with \"Load CLI YAML...\" in Repositories.")
+		case len(violations) == 0:
+			info.SetText(fmt.Sprintf("%d policies; no violations in the latest report.", configured))
+		default:
+			info.SetText(fmt.Sprintf("%d policies; %d violations (%d errors, %d ignored) in the latest report.", configured, len(violations), errs, ignored))
+		}
+		list.Refresh()
+	}
+	reload()
+
+	return container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil, widget.NewButton("Refresh", reload),
+				widget.NewLabelWithStyle("Policies", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})),
+			widget.NewSeparator(),
+			info,
+		),
+		nil, nil, nil,
+		list,
+	)
+}
+
+// ----- Logs View -----
+
+// httpTraceEntries bounds the provider API requests kept for "Export HTTP
+// Trace..."
+const httpTraceEntries = 5000
+
+func buildLogsView(rt *Runtime, _ fyne.App, w fyne.Window, logHandler *RingLogHandler, enqueueUI func(func())) fyne.CanvasObject {
+	// Filtering controls
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Filter text (substring)")
+
+	// Forward declarations so callbacks can reference logList after assignment.
+	var logList *widget.List
+	errorOnlyToggle := widget.NewCheck("Show only errors", func(bool) {
+		if logList != nil {
+			logList.Refresh()
+		}
+	})
+
+	levelSelect := widget.NewSelect([]string{"ALL", "DEBUG", "INFO", "WARN", "ERROR"}, func(string) {
+		if logList != nil {
+			logList.Refresh()
+		}
+	})
+	levelSelect.SetSelected("ALL")
+
+	// Source options are rebuilt from the buffer on refresh (see logSource)
+	sourceSelect := widget.NewSelect([]string{"ALL"}, func(string) {
+		if logList != nil {
+			logList.Refresh()
+		}
+	})
+	sourceSelect.SetSelected("ALL")
+	refreshSources := func() {
+		sourceSelect.Options = append([]string{"ALL"}, logSources(logHandler.Entries())...)
+		sourceSelect.Refresh()
+	}
+	refreshSources()
+
+	filtered := func() []LogEntry {
+		return filteredLogs(logHandler, searchEntry.Text, levelSelect.Selected, sourceSelect.Selected, errorOnlyToggle.Checked)
+	}
+
+	// List with dynamic filtering (assigned after control declarations)
+	logList = widget.NewList(
+		func() int {
+			return len(filtered())
+		},
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			entries := filtered()
+			if i < len(entries) {
+				e := entries[i]
+				o.(*widget.Label).SetText(fmt.Sprintf("%s [%s] %s",
+					e.Time.Format(time.RFC3339), e.Level.String(), e.Message))
+			} else {
+				o.(*widget.Label).SetText("")
+			}
+		},
+	)
+
+	// Follow mode refreshes the list every second and keeps it scrolled to the
+	// newest entry until unchecked.
+	var followStop chan struct{}
+	followToggle := widget.NewCheck("Follow", func(on bool) {
+		if followStop != nil {
+			close(followStop)
+			followStop = nil
+		}
+		if !on {
+			return
+		}
+		stop := make(chan struct{})
+		followStop = stop
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					enqueueUI(func() {
+						logList.Refresh()
+						logList.ScrollToBottom()
+					})
+				case <-stop:
+					return
+				}
+			}
+		}()
+	})
+
+	refreshBtn := widget.NewButton("Refresh", func() {
+		refreshSources()
+		if logList != nil {
+			logList.Refresh()
+		}
+	})
+	clearBtn := widget.NewButton("Clear", func() {
+		logHandler.mu.Lock()
+		logHandler.logs = []LogEntry{}
+		logHandler.mu.Unlock()
+		refreshSources()
+		if logList != nil {
+			logList.Refresh()
+		}
+	})
+
+	exportFormat := widget.NewSelect([]string{logFormatText, logFormatJSONL}, nil)
+	exportFormat.SetSelected(logFormatText)
+	exportBtn := widget.NewButton("Export...", func() {
+		exportLogs(logHandler.Entries(), exportFormat.Selected, w)
+	})
+
+	// HTTP tracing logs each provider API request of later reports (source
+	// "http") and keeps them for a HAR-like export to attach to bug reports
+	rt.mu.RLock()
+	tracing := rt.state.GUI.Logging.TraceHTTP
+	rt.mu.RUnlock()
+	traceToggle := widget.NewCheck("Trace HTTP requests", func(on bool) {
+		rt.mu.Lock()
+		rt.state.GUI.Logging.TraceHTTP = on
+		rt.mu.Unlock()
+		saveState(rt)
+	})
+	traceToggle.SetChecked(tracing)
+	exportTraceBtn := widget.NewButton("Export HTTP Trace...", func() {
+		rt.mu.RLock()
+		tracer := rt.httpTracer
+		rt.mu.RUnlock()
+		if tracer == nil || len(tracer.Entries()) == 0 {
+			dialog.ShowInformation("Export HTTP Trace", "No requests traced yet. Enable \"Trace HTTP requests\" and run a report.", w)
+			return
+		}
+		exportHTTPTrace(tracer, w)
+	})
+
+	controls := container.NewVBox(
 		widget.NewLabelWithStyle("Logs", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		widget.NewSeparator(),
-		container.NewHBox(searchEntry, levelSelect),
-		container.NewHBox(errorOnlyToggle, structuredErrorsToggle),
-		container.NewHBox(refreshBtn, clearBtn),
+		container.NewBorder(nil, nil, nil, container.NewHBox(levelSelect, sourceSelect), searchEntry),
+		container.NewHBox(errorOnlyToggle, followToggle, widget.NewSeparator(), traceToggle),
+		container.NewHBox(refreshBtn, clearBtn, widget.NewSeparator(), exportFormat, exportBtn, exportTraceBtn),
 	)
 
-	return container.NewBorder(
-		controls,
-		nil, nil, nil,
-		logList,
-	)
+	return container.NewBorder(
+		controls,
+		nil, nil, nil,
+		logList,
+	)
+}
+
+// Log export formats offered by the Logs view
+const (
+	logFormatText  = "Text"
+	logFormatJSONL = "JSON Lines"
+)
+
+// logSourceKeys are the slog attributes identifying where an entry came from,
+// in order of preference
+var logSourceKeys = []string{"source", "provider", "view"}
+
+// logSource returns the source of a log entry: the first of logSourceKeys
+// present in its attributes, or "app" if none is set.
+func logSource(e LogEntry) string {
+	for _, key := range logSourceKeys {
+		for _, a := range e.Attrs {
+			if a.Key == key {
+				if v := a.Value.String(); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return "app"
+}
+
+// logSources returns the distinct sources of entries, sorted
+func logSources(entries []LogEntry) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, e := range entries {
+		if src := logSource(e); !seen[src] {
+			seen[src] = true
+			out = append(out, src)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// logRecord is the JSON Lines shape of an exported log entry
+type logRecord struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// writeLogs encodes entries as plain text (one line per entry with
+// key=value attributes) or as JSON Lines.
+func writeLogs(out io.Writer, entries []LogEntry, format string) error {
+	if format == logFormatJSONL {
+		enc := json.NewEncoder(out)
+		for _, e := range entries {
+			rec := logRecord{Time: e.Time, Level: e.Level.String(), Message: e.Message}
+			if len(e.Attrs) > 0 {
+				rec.Attrs = make(map[string]any, len(e.Attrs))
+				for _, a := range e.Attrs {
+					rec.Attrs[a.Key] = a.Value.Resolve().Any()
+				}
+			}
+			if err := enc.Encode(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, e := range entries {
+		line := fmt.Sprintf("%s [%s] %s", e.Time.Format(time.RFC3339), e.Level.String(), e.Message)
+		for _, a := range e.Attrs {
+			line += fmt.Sprintf(" %s=%v", a.Key, a.Value)
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportLogs saves the captured log buffer to a user-chosen file
+func exportLogs(entries []LogEntry, format string, w fyne.Window) {
+	fs := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if uc == nil {
+			return
+		}
+		defer func() { _ = uc.Close() }()
+
+		if wErr := writeLogs(uc, entries, format); wErr != nil {
+			dialog.ShowError(wErr, w)
+			return
+		}
+		dialog.ShowInformation("Export Logs", fmt.Sprintf("Exported %d log entries.", len(entries)), w)
+	}, w)
+	if format == logFormatJSONL {
+		fs.SetFileName("devdashboard-logs.jsonl")
+	} else {
+		fs.SetFileName("devdashboard-logs.log")
+	}
+	fs.Show()
+}
+
+// exportHTTPTrace saves the traced provider API requests as HAR-like JSON
+func exportHTTPTrace(tracer *repository.HTTPTracer, w fyne.Window) {
+	fs := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if uc == nil {
+			return
+		}
+		defer func() { _ = uc.Close() }()
+
+		if wErr := tracer.WriteHAR(uc, version); wErr != nil {
+			dialog.ShowError(wErr, w)
+			return
+		}
+		dialog.ShowInformation("Export HTTP Trace", fmt.Sprintf("Exported %d requests.", len(tracer.Entries())), w)
+	}, w)
+	fs.SetFileName("devdashboard-http.har")
+	fs.Show()
 }
-func filteredLogs(logHandler *RingLogHandler, search, levelFilter string, errorsOnly bool, structuredErrors bool, rt *Runtime) []LogEntry {
+
+func filteredLogs(logHandler *RingLogHandler, search, levelFilter, sourceFilter string, errorsOnly bool) []LogEntry {
 	logHandler.mu.RLock()
 	defer logHandler.mu.RUnlock()
 	if len(logHandler.logs) == 0 {
@@ -1449,64 +5090,35 @@ func filteredLogs(logHandler *RingLogHandler, search, levelFilter string, errors
 	levelFilter = strings.ToUpper(strings.TrimSpace(levelFilter))
 
 	var out []LogEntry
-	if structuredErrors {
-		// Map structured errors into LogEntry shape
-		rt.mu.RLock()
-		errs := append([]statepkg.ErrorLogEntry{}, rt.state.ErrorLog...)
-		rt.mu.RUnlock()
-		for _, se := range errs {
-			msg := se.Message
-			if se.Details != "" {
-				msg = msg + " (" + se.Details + ")"
-			}
-			out = append(out, LogEntry{
-				Time:    se.Time,
-				Level:   slog.LevelError,
-				Message: fmt.Sprintf("[%s] %s", se.Source, msg),
-				Attrs:   []slog.Attr{slog.String("severity", se.Severity)},
-			})
+	for _, e := range logHandler.logs {
+		// Level filter
+		if levelFilter != "" && levelFilter != "ALL" && strings.ToUpper(e.Level.String()) != levelFilter {
+			continue
 		}
-	} else {
-		for _, e := range logHandler.logs {
-			// Level filter
-			if levelFilter != "" && levelFilter != "ALL" && strings.ToUpper(e.Level.String()) != levelFilter {
-				continue
-			}
-			// Errors-only toggle
-			if errorsOnly && strings.ToUpper(e.Level.String()) != "ERROR" {
+		// Source filter (slog attrs)
+		if sourceFilter != "" && sourceFilter != "ALL" && logSource(e) != sourceFilter {
+			continue
+		}
+		// Errors-only toggle
+		if errorsOnly && strings.ToUpper(e.Level.String()) != "ERROR" {
+			continue
+		}
+		// Substring search (match message or level)
+		if search != "" {
+			if !strings.Contains(strings.ToLower(e.Message), search) &&
+				!strings.Contains(strings.ToLower(e.Level.String()), search) {
 				continue
 			}
-			// Substring search (match message or level)
-			if search != "" {
-				if !strings.Contains(strings.ToLower(e.Message), search) &&
-					!strings.Contains(strings.ToLower(e.Level.String()), search) {
-					continue
-				}
-			}
-			out = append(out, e)
 		}
+		out = append(out, e)
 	}
 	return out
 }
 
 // ----- JSON Export -----
 
-type jsonOutput struct {
-	Version      string                    `json:"cliVersion"`
-	GeneratedAt  time.Time                 `json:"generatedAt"`
-	Repositories []report.RepositoryReport `json:"repositories"`
-	Packages     []string                  `json:"packages"`
-	Summary      jsonSummary               `json:"summary"`
-	Errors       map[string]string         `json:"errors,omitempty"`
-}
-
-type jsonSummary struct {
-	RepositoryCount int `json:"repositoryCount"`
-	PackageCount    int `json:"packageCount"`
-	SuccessCount    int `json:"successCount"`
-	ErrorCount      int `json:"errorCount"`
-}
-
+// exportJSONReport saves the current report as the canonical JSON document
+// the CLI's --format json writes (see report.JSONDocument).
 func exportJSONReport(rt *Runtime, w fyne.Window) {
 	rt.mu.RLock()
 	rpt := rt.currentReport
@@ -1527,124 +5139,594 @@ func exportJSONReport(rt *Runtime, w fyne.Window) {
 		}
 		defer func() { _ = uc.Close() }()
 
-		successCount := 0
-		for _, rr := range rpt.Repositories {
-			if rr.Error == nil {
-				successCount++
-			}
-		}
-		errCount := len(rpt.Repositories) - successCount
-		errMap := map[string]string{}
-		for _, rr := range rpt.Repositories {
-			if rr.Error != nil {
-				key := fmt.Sprintf("%s:%s/%s@%s", rr.Provider, rr.Owner, rr.Repository, rr.Ref)
-				errMap[key] = rr.Error.Error()
-			}
+		if wErr := rpt.WriteJSON(uc, report.JSONOptions{Version: version}, true); wErr != nil {
+			dialog.ShowError(wErr, w)
+			return
 		}
+		dialog.ShowInformation("Export JSON", "Report exported successfully.", w)
+	}, w)
+	fs.SetFileName("dependency-report.json")
+	fs.Show()
+}
 
-		payload := jsonOutput{
-			Version:      version,
-			GeneratedAt:  time.Now().UTC(),
-			Repositories: rpt.Repositories,
-			Packages:     rpt.Packages,
-			Summary: jsonSummary{
-				RepositoryCount: len(rpt.Repositories),
-				PackageCount:    len(rpt.Packages),
-				SuccessCount:    successCount,
-				ErrorCount:      errCount,
-			},
+// openReportFile shows a report exported as JSON (by the CLI's --format json
+// or Export JSON, possibly on another machine) in place of the current one,
+// then calls onLoaded to redraw. The next refresh replaces it with a live run.
+func openReportFile(rt *Runtime, w fyne.Window, onLoaded func()) {
+	fd := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
 		}
-		if len(errMap) > 0 {
-			payload.Errors = errMap
+		if rc == nil {
+			return
 		}
-
-		data, mErr := json.MarshalIndent(payload, "", "  ")
-		if mErr != nil {
-			dialog.ShowError(mErr, w)
+		defer func() { _ = rc.Close() }()
+		rpt, info, err := report.ReadJSON(rc)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("opening %s: %w", rc.URI().Name(), err), w)
 			return
 		}
-		if _, wErr := uc.Write(data); wErr != nil {
-			dialog.ShowError(wErr, w)
+
+		rt.mu.Lock()
+		if rt.reportRunning {
+			rt.mu.Unlock()
+			dialog.ShowError(errors.New("a report is running; open the file after it finishes"), w)
 			return
 		}
-		dialog.ShowInformation("Export JSON", "Report exported successfully.", w)
+		rt.currentReport = rpt
+		rt.depPage = 0
+		rt.lastRunID = ""
+		rt.mu.Unlock()
+		refreshDependencyView(rt)
+		slog.Info("Opened report", "path", rc.URI().Path(), "repos", len(rpt.Repositories), "generatedAt", info.GeneratedAt)
+		if onLoaded != nil {
+			onLoaded()
+		}
+		msg := fmt.Sprintf("Opened %s: %d repos, %d packages", rc.URI().Name(), len(rpt.Repositories), len(rpt.Packages))
+		if !info.GeneratedAt.IsZero() {
+			msg += ", generated " + info.GeneratedAt.Local().Format("2006-01-02 15:04")
+		}
+		showToast(w, msg)
 	}, w)
-	fs.SetFileName("dependency-report.json")
-	fs.Show()
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	fd.Show()
 }
 
 // ----- State Saving (Debounced) -----
 
-var saveMu sync.Mutex
+// saveDebounce coalesces bursts of state mutations into one write
+const saveDebounce = 250 * time.Millisecond
 
-var saveTimer *time.Timer
+// stateSaver debounces writes of one Runtime's state. pending stays set until
+// a write succeeds (or is discarded) so Flush never drops a scheduled save.
+type stateSaver struct {
+	mu      sync.Mutex
+	timer   *time.Timer
+	pending bool
+}
 
+// saveState re-validates derived views after a state mutation and schedules
+// a debounced write.
 func saveState(rt *Runtime) {
-	saveMu.Lock()
-	defer saveMu.Unlock()
+	// Every state mutation funnels through here; re-validate before persisting.
+	refreshRepoLint(rt)
+	refreshDependencyView(rt)
+	rt.scheduleSave()
+}
+
+// scheduleSave (re)starts the debounce timer. Failures of the delayed write
+// are reported through onExternalChange or onSaveError.
+func (rt *Runtime) scheduleSave() {
+	rt.saver.mu.Lock()
+	defer rt.saver.mu.Unlock()
+	rt.saver.pending = true
+	if rt.saver.timer != nil {
+		rt.saver.timer.Stop()
+	}
+	rt.saver.timer = time.AfterFunc(saveDebounce, func() {
+		rt.saver.mu.Lock()
+		defer rt.saver.mu.Unlock()
+		if !rt.saver.pending {
+			return // flushed or discarded meanwhile
+		}
+		err := rt.writeStateLocked(false)
+		switch {
+		case errors.Is(err, statepkg.ErrStateModified):
+			if rt.onExternalChange != nil {
+				rt.onExternalChange()
+			}
+		case err != nil:
+			if rt.onSaveError != nil {
+				rt.onSaveError(err)
+			}
+		}
+	})
+}
+
+// Flush cancels the debounce timer and writes a pending save now. It never
+// overwrites a state file another process changed (ErrStateModified).
+func (rt *Runtime) Flush() error {
+	rt.saver.mu.Lock()
+	defer rt.saver.mu.Unlock()
+	if rt.saver.timer != nil {
+		rt.saver.timer.Stop()
+	}
+	if !rt.saver.pending {
+		return nil
+	}
+	return rt.writeStateLocked(false)
+}
+
+// ForceSave writes the state now, overwriting external changes
+func (rt *Runtime) ForceSave() error {
+	rt.saver.mu.Lock()
+	defer rt.saver.mu.Unlock()
+	if rt.saver.timer != nil {
+		rt.saver.timer.Stop()
+	}
+	return rt.writeStateLocked(true)
+}
+
+// DiscardPendingSave cancels a scheduled save without writing
+func (rt *Runtime) DiscardPendingSave() {
+	rt.saver.mu.Lock()
+	defer rt.saver.mu.Unlock()
+	if rt.saver.timer != nil {
+		rt.saver.timer.Stop()
+	}
+	rt.saver.pending = false
+}
+
+// writeStateLocked saves the current state; the caller holds saver.mu
+func (rt *Runtime) writeStateLocked(force bool) error {
+	rt.mu.RLock()
+	st := rt.state
+	inDemo := rt.demoHistoryDir != ""
+	rt.mu.RUnlock()
+	if inDemo {
+		rt.saver.pending = false
+		return nil
+	}
+	save := statepkg.SaveProfileIfUnmodified
+	if force {
+		save = statepkg.SaveProfile
+	}
+	err := save(st)
+	switch {
+	case errors.Is(err, statepkg.ErrStateModified):
+		// Never clobber a write from another instance; the user decides
+		slog.Warn("State file changed on disk; not overwriting", "profile", st.Profile, "error", err)
+	case err != nil:
+		slog.Error("Failed to save state", "profile", st.Profile, "error", err)
+	default:
+		rt.saver.pending = false
+		slog.Debug("State saved", "profile", st.Profile)
+	}
+	return err
+}
 
-	if saveTimer != nil {
-		saveTimer.Stop()
+// historyDirLocked returns the snapshot history directory of the open
+// profile, or of demo mode; the caller holds rt.mu
+func (rt *Runtime) historyDirLocked() (string, error) {
+	if rt.demoHistoryDir != "" {
+		return rt.demoHistoryDir, nil
+	}
+	return statepkg.ProfileHistoryDir(rt.state.Profile)
+}
+
+// endDemo leaves demo mode (if on), dropping its pending save, snapshot
+// history and in-memory repositories; the caller then swaps in a saved
+// profile
+func (rt *Runtime) endDemo() {
+	rt.mu.RLock()
+	dir := rt.demoHistoryDir
+	rt.mu.RUnlock()
+	if dir == "" {
+		return
+	}
+	rt.DiscardPendingSave()
+	rt.mu.Lock()
+	rt.demoHistoryDir = ""
+	rt.mu.Unlock()
+	memory.Default.Reset()
+	if err := os.RemoveAll(dir); err != nil {
+		slog.Warn("Failed to remove demo history", "path", dir, "error", err)
 	}
-	// Debounce writes (250ms)
-	saveTimer = time.AfterFunc(250*time.Millisecond, func() {
-		saveMu.Lock()
-		defer saveMu.Unlock()
+}
+
+// showToast briefly shows msg at the bottom of the window
+func showToast(w fyne.Window, msg string) {
+	label := widget.NewLabel(msg)
+	pop := widget.NewPopUp(label, w.Canvas())
+	size := pop.MinSize()
+	canvasSize := w.Canvas().Size()
+	pop.ShowAtPosition(fyne.NewPos((canvasSize.Width-size.Width)/2, canvasSize.Height-size.Height-theme.Padding()*4))
+	time.AfterFunc(5*time.Second, func() { fyne.Do(pop.Hide) })
+}
+
+// watchStateFile polls the savedAt stamp of the open profile's state file and
+// calls onChange when another process wrote a newer one. Our own saves stamp
+// the in-memory state before writing, so they never look newer.
+func watchStateFile(rt *Runtime, interval time.Duration, onChange func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
 		rt.mu.RLock()
-		st := rt.state
+		profile := rt.state.Profile
+		savedAt := rt.state.SavedAt
+		inDemo := rt.demoHistoryDir != ""
 		rt.mu.RUnlock()
-
-		if err := statepkg.SaveGUIState(st, ""); err != nil {
-			slog.Error("Failed to save state", "error", err)
-		} else {
-			slog.Debug("State saved", "path", statepkg.DefaultGUIStatePath())
+		if inDemo {
+			continue
+		}
+		onDisk, err := statepkg.ProfileSavedAt(profile)
+		if err != nil {
+			slog.Debug("State file check failed", "profile", profile, "error", err)
+			continue
+		}
+		if onDisk.After(savedAt) {
+			onChange()
 		}
+	}
+}
 
-	})
+// ----- Utility for window geometry update -----
 
+// geometryLayout stacks the window content and records the window size in
+// state whenever it changes. Fyne has no window resize event, but the root
+// content is laid out again on every resize. Window position is not exposed
+// by Fyne, so placement (including the monitor) is left to the window manager.
+type geometryLayout struct {
+	rt    *Runtime
+	w     fyne.Window
+	last  fyne.Size
+	timer *time.Timer
+
+	// geometry returns the state entry recording w's size
+	geometry func(*statepkg.GUIState) *statepkg.WindowGeometry
 }
 
-// ----- Utility for window geometry update -----
+func newGeometryLayout(rt *Runtime, w fyne.Window) *geometryLayout {
+	return &geometryLayout{rt: rt, w: w, geometry: func(st *statepkg.GUIState) *statepkg.WindowGeometry {
+		return &st.GUI.LastWindow
+	}}
+}
+
+// newDetachedGeometryLayout records the size of the window view is detached
+// into (see hostDetachableView)
+func newDetachedGeometryLayout(rt *Runtime, w fyne.Window, view viewID) *geometryLayout {
+	return &geometryLayout{rt: rt, w: w, geometry: func(st *statepkg.GUIState) *statepkg.WindowGeometry {
+		return &st.GUI.Detached(string(view)).Window
+	}}
+}
+
+// Layout resizes every object to fill the window and schedules a geometry save
+func (l *geometryLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	layout.NewStackLayout().Layout(objects, size)
+	if size == l.last || size.Width <= 0 || size.Height <= 0 {
+		return
+	}
+	l.last = size
+	updateWindowGeometryOnResize(l)
+}
 
-// (removed unused updateWindowGeometryOnResize)
+// MinSize returns the largest minimum size of the contained objects
+func (l *geometryLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	return layout.NewStackLayout().MinSize(objects)
+}
 
-// (Optional future) hooking updateWindowGeometryOnResize(rt) after UI creation.
-// For now omitted to keep resource usage low.
+// updateWindowGeometryOnResize stores the window size after resizing settles
+// (500ms). While full screen only the flag is updated so leaving full screen
+// restores the previous size on next launch.
+func updateWindowGeometryOnResize(l *geometryLayout) {
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	size := l.last
+	l.timer = time.AfterFunc(500*time.Millisecond, func() {
+		l.rt.mu.Lock()
+		geo := l.geometry(l.rt.state)
+		geo.Maximized = l.w.FullScreen()
+		if !geo.Maximized {
+			geo.Width = int(size.Width)
+			geo.Height = int(size.Height)
+		}
+		l.rt.mu.Unlock()
+		saveState(l.rt)
+	})
+}
 
 // ----- END -----
 
 // (removed unused debugRuntimeSnapshot)
 
 // ----- History View (placeholder) -----
-func buildHistoryView(rt *Runtime) fyne.CanvasObject {
-	rt.mu.RLock()
-	hist := rt.state.ReportHistory
-	rt.mu.RUnlock()
+// buildSettingsView edits the tunable preferences of gui_state.yaml
+// (concurrency, auto-refresh, logging and timeouts). Saving validates every
+// field and applies the changes without a restart: the log level and buffer
+// size at once, the auto-refresh schedule by restarting it, concurrency and
+// timeouts from the next report run.
+func buildSettingsView(rt *Runtime, w fyne.Window, logHandler *RingLogHandler, enqueueUI func(func())) fyne.CanvasObject {
+	intEntry := func() *widget.Entry {
+		e := widget.NewEntry()
+		e.Validator = func(s string) error {
+			_, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return errors.New("expected a whole number")
+			}
+			return nil
+		}
+		return e
+	}
+	durationEntry := func(placeholder string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetPlaceHolder(placeholder)
+		e.Validator = func(s string) error {
+			_, err := parseTimeout(s)
+			return err
+		}
+		return e
+	}
+	workersEntry := intEntry()
+	autoRefreshCheck := widget.NewCheck("Refresh the report periodically", nil)
+	intervalEntry := intEntry()
+	levelSelect := widget.NewSelect(statepkg.LogLevels, nil)
+	bufferEntry := intEntry()
+	runEntry := durationEntry(statepkg.DefaultReportTimeout.String())
+	repoEntry := durationEntry("no limit")
+	requestEntry := durationEntry("no limit")
+
+	durationText := func(d time.Duration) string {
+		if d <= 0 {
+			return ""
+		}
+		return d.String()
+	}
+	show := func(s statepkg.Settings) {
+		workersEntry.SetText(strconv.Itoa(s.Concurrency.MaxWorkers))
+		autoRefreshCheck.SetChecked(s.AutoRefresh.Enabled)
+		intervalEntry.SetText(strconv.Itoa(s.AutoRefresh.IntervalSeconds))
+		levelSelect.SetSelected(strings.ToLower(s.Logging.Level))
+		bufferEntry.SetText(strconv.Itoa(s.Logging.RingBufferSize))
+		runEntry.SetText(durationText(s.Timeouts.Run))
+		repoEntry.SetText(durationText(s.Timeouts.Repository))
+		requestEntry.SetText(durationText(s.Timeouts.Request))
+	}
 
-	if len(hist) == 0 {
-		return container.NewCenter(widget.NewLabel("No report history yet."))
+	// apply stores s and puts it into effect; the HTTP trace toggle lives in
+	// the Logs view and is kept as is
+	apply := func(s statepkg.Settings) error {
+		rt.mu.Lock()
+		s.Logging.TraceHTTP = rt.state.GUI.Logging.TraceHTTP
+		err := rt.state.GUI.ApplySettings(s)
+		rt.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		logHandler.SetLevel(parseLogLevel(s.Logging.Level))
+		logHandler.SetCapacity(s.Logging.RingBufferSize)
+		stopAutoRefresh(rt)
+		startAutoRefresh(rt, enqueueUI)
+		saveState(rt)
+		slog.Info("Settings updated",
+			"maxWorkers", s.Concurrency.MaxWorkers,
+			"autoRefresh", s.AutoRefresh.Enabled,
+			"intervalSeconds", s.AutoRefresh.IntervalSeconds,
+			"logLevel", s.Logging.Level,
+			"ringBufferSize", s.Logging.RingBufferSize,
+			"runTimeout", s.Timeouts.Run,
+			"repoTimeout", s.Timeouts.Repository,
+			"requestTimeout", s.Timeouts.Request)
+		return nil
 	}
 
-	list := widget.NewList(
-		func() int { return len(hist) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			if i >= len(hist) {
-				o.(*widget.Label).SetText("")
-				return
+	saveBtn := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		var s statepkg.Settings
+		var errs []error
+		atoi := func(e *widget.Entry) int {
+			n, err := strconv.Atoi(strings.TrimSpace(e.Text))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%q is not a whole number", e.Text))
 			}
-			entry := hist[i]
-			o.(*widget.Label).SetText(fmt.Sprintf("%s - %d repos / %d packages",
-				entry.GeneratedAt.Format(time.RFC3339),
-				entry.RepoCount,
-				entry.PackageCount,
-			))
-		},
+			return n
+		}
+		duration := func(e *widget.Entry) time.Duration {
+			d, err := parseTimeout(e.Text)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%q: %w", e.Text, err))
+			}
+			return d
+		}
+		s.Concurrency.MaxWorkers = atoi(workersEntry)
+		s.AutoRefresh.Enabled = autoRefreshCheck.Checked
+		s.AutoRefresh.IntervalSeconds = atoi(intervalEntry)
+		s.Logging.Level = levelSelect.Selected
+		s.Logging.RingBufferSize = atoi(bufferEntry)
+		s.Timeouts.Run = duration(runEntry)
+		s.Timeouts.Repository = duration(repoEntry)
+		s.Timeouts.Request = duration(requestEntry)
+		if len(errs) > 0 {
+			dialog.ShowError(errors.Join(errs...), w)
+			return
+		}
+		if err := apply(s); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		showToast(w, "Settings saved")
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	resetBtn := widget.NewButtonWithIcon("Reset to Defaults", theme.HistoryIcon(), func() {
+		dialog.ShowConfirm("Reset Settings",
+			"Restore the default concurrency, auto-refresh, logging and timeout settings?",
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				defaults := statepkg.DefaultSettings()
+				if err := apply(defaults); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				show(defaults)
+				showToast(w, "Settings reset to defaults")
+			}, w)
+	})
+
+	rt.mu.RLock()
+	show(rt.state.GUI.Settings())
+	rt.mu.RUnlock()
+
+	form := widget.NewForm(
+		widget.NewFormItem("Max workers", workersEntry),
+		widget.NewFormItem("Auto-refresh", autoRefreshCheck),
+		widget.NewFormItem("Interval (seconds)", intervalEntry),
+		widget.NewFormItem("Log level", levelSelect),
+		widget.NewFormItem("Log buffer size", bufferEntry),
+		widget.NewFormItem("Report run timeout", runEntry),
+		widget.NewFormItem("Repository timeout", repoEntry),
+		widget.NewFormItem("Request timeout", requestEntry),
+	)
+	form.Items[0].HintText = fmt.Sprintf("Repositories analyzed at once (1-%d)", statepkg.MaxWorkersLimit)
+	form.Items[2].HintText = fmt.Sprintf("At least %d", statepkg.MinAutoRefreshSeconds)
+	form.Items[4].HintText = "Log entries kept for the Logs view"
+	form.Items[5].HintText = "Whole report, e.g. 10m"
+	form.Items[6].HintText = "One repository's analysis, e.g. 45s; empty for no limit"
+	form.Items[7].HintText = "One provider API request, retried when exceeded; empty for no limit"
+
+	note := widget.NewLabel("Concurrency and timeouts apply from the next report run; other settings apply immediately.")
+	note.Wrapping = fyne.TextWrapWord
+
+	return container.NewBorder(
+		widget.NewLabelWithStyle("Settings", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewHBox(saveBtn, resetBtn),
+		nil, nil,
+		container.NewVScroll(container.NewVBox(form, note)),
 	)
+}
+
+func buildHistoryView(rt *Runtime, enqueueUI func(func())) fyne.CanvasObject {
+	rt.mu.RLock()
+	hist := rt.state.ReportHistory
+	rt.mu.RUnlock()
+
+	var runs fyne.CanvasObject = container.NewCenter(widget.NewLabel("No report history yet."))
+	if len(hist) > 0 {
+		runs = widget.NewList(
+			func() int { return len(hist) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(i widget.ListItemID, o fyne.CanvasObject) {
+				if i >= len(hist) {
+					o.(*widget.Label).SetText("")
+					return
+				}
+				entry := hist[i]
+				o.(*widget.Label).SetText(fmt.Sprintf("%s - %d repos / %d packages",
+					entry.GeneratedAt.Format(time.RFC3339),
+					entry.RepoCount,
+					entry.PackageCount,
+				))
+			},
+		)
+	}
 
+	split := container.NewVSplit(buildTrendPanel(rt, enqueueUI), runs)
+	split.SetOffset(0.45)
 	return container.NewBorder(
 		widget.NewLabelWithStyle("History", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		nil, nil, nil,
-		list,
+		split,
 	)
 }
+
+// trendChartHeight is the height of the tallest bar of the trend chart
+const trendChartHeight = 120
+
+// buildTrendPanel charts how a package's versions evolved across the
+// snapshots archived after each report: per snapshot, the share of the
+// repositories locking the package at a version in the entered range (the
+// newest version seen when the range is empty).
+func buildTrendPanel(rt *Runtime, enqueueUI func(func())) fyne.CanvasObject {
+	rt.mu.RLock()
+	tracked := slices.Clone(rt.state.TrackedPackages)
+	rt.mu.RUnlock()
+
+	pkgEntry := widget.NewSelectEntry(tracked)
+	pkgEntry.SetPlaceHolder("Package, e.g. django")
+	rangeEntry := widget.NewEntry()
+	rangeEntry.SetPlaceHolder("Version range, e.g. >=4.2,<4.3 (default: newest version)")
+	caption := widget.NewLabel("Pick a package to chart its versions across previous reports.")
+	caption.Wrapping = fyne.TextWrapWord
+	bars := container.NewHBox()
+
+	show := func() {
+		pkg := strings.TrimSpace(pkgEntry.Text)
+		if pkg == "" {
+			return
+		}
+		spec := strings.TrimSpace(rangeEntry.Text)
+		rt.mu.RLock()
+		dir, err := rt.historyDirLocked()
+		rt.mu.RUnlock()
+		if err != nil {
+			caption.SetText(err.Error())
+			return
+		}
+		caption.SetText("Loading snapshot history...")
+		go func() {
+			text, shares := trendChartData(dir, pkg, spec)
+			enqueueUI(func() {
+				caption.SetText(text)
+				bars.Objects = nil
+				for _, share := range shares {
+					bar := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+					bar.SetMinSize(fyne.NewSize(12, max(1, float32(share)*trendChartHeight)))
+					bars.Add(container.NewVBox(layout.NewSpacer(), bar))
+				}
+				bars.Refresh()
+			})
+		}()
+	}
+	pkgEntry.OnSubmitted = func(string) { show() }
+	rangeEntry.OnSubmitted = func(string) { show() }
+
+	// A transparent strut keeps the chart area at full height while empty
+	strut := canvas.NewRectangle(color.Transparent)
+	strut.SetMinSize(fyne.NewSize(0, trendChartHeight))
+	chart := container.NewHScroll(container.NewHBox(strut, bars))
+
+	form := container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("Show", theme.SearchIcon(), show),
+		container.NewGridWithColumns(2, pkgEntry, rangeEntry))
+	return container.NewBorder(form, caption, nil, nil, chart)
+}
+
+// trendChartData loads the snapshot history in dir and returns the chart
+// caption and per-snapshot adoption shares of pkg (nil when there is
+// nothing to chart)
+func trendChartData(dir, pkg, spec string) (string, []float64) {
+	history, err := report.LoadSnapshotHistory(dir)
+	if err != nil {
+		return err.Error(), nil
+	}
+	if len(history) == 0 {
+		return "No archived snapshots yet: they are recorded after each successful report.", nil
+	}
+	t := report.Trend(history, pkg)
+	if len(t.Versions) == 0 {
+		return fmt.Sprintf("No repositories locked %s in %d snapshots.", pkg, len(history)), nil
+	}
+	if spec == "" {
+		spec = "==" + t.Versions[len(t.Versions)-1]
+	}
+	rng, err := versioning.ParseRange(t.Ecosystem, spec)
+	if err != nil {
+		return err.Error(), nil
+	}
+	shares := t.Adoption(rng)
+	first, last := t.Points[0], t.Points[len(t.Points)-1]
+	return fmt.Sprintf("%s %s: %.0f%% of %d repositories (was %.0f%%) across %d snapshots from %s to %s",
+		pkg, spec, shares[len(shares)-1]*100, last.Repositories, shares[0]*100, len(t.Points),
+		first.Time.Local().Format("2006-01-02"), last.Time.Local().Format("2006-01-02")), shares
+}